@@ -47,6 +47,64 @@ func (m AutoScalingMode) String() string {
 	}
 }
 
+// ScalingReason identifies the threshold that decided a scaling transition.
+type ScalingReason uint8
+
+const (
+	// ScalingReasonNone means no threshold was recorded (e.g. before the first transition)
+	ScalingReasonNone ScalingReason = iota
+	// ScalingReasonWriteRate means writes/sec reached ScaleToMPSCWriteThreshold
+	ScalingReasonWriteRate
+	// ScalingReasonContention means the contention ratio reached ScaleToMPSCContentionRatio
+	ScalingReasonContention
+	// ScalingReasonLatency means the average latency reached ScaleToMPSCLatencyThreshold
+	ScalingReasonLatency
+	// ScalingReasonGoroutines means active writers reached ScaleToMPSCGoroutineCount
+	ScalingReasonGoroutines
+	// ScalingReasonLowLoad means every metric fell below its ScaleToSingle limit
+	ScalingReasonLowLoad
+	// ScalingReasonStrategy means a custom ScalingStrategy chose the mode
+	ScalingReasonStrategy
+)
+
+// String returns the reason as written in transition records.
+func (r ScalingReason) String() string {
+	switch r {
+	case ScalingReasonNone:
+		return "none"
+	case ScalingReasonWriteRate:
+		return "write_rate"
+	case ScalingReasonContention:
+		return "contention"
+	case ScalingReasonLatency:
+		return "latency"
+	case ScalingReasonGoroutines:
+		return "goroutines"
+	case ScalingReasonLowLoad:
+		return "low_load"
+	case ScalingReasonStrategy:
+		return "strategy"
+	default:
+		return "unknown"
+	}
+}
+
+// scalingHistorySize is the number of transitions kept in RecentDecisions.
+const scalingHistorySize = 16
+
+// ScalingDecision records one mode transition and the metrics of the
+// measurement window that triggered it.
+type ScalingDecision struct {
+	Time             time.Time       // When the transition happened
+	From             AutoScalingMode // Mode before the transition
+	To               AutoScalingMode // Mode after the transition
+	Reason           ScalingReason   // Threshold that decided the transition
+	WritesPerSecond  uint64          // Write rate in the window
+	ContentionRatio  uint32          // Contention percentage in the window
+	AvgLatency       time.Duration   // Average write latency in the window
+	ActiveGoroutines uint32          // Concurrent writers at decision time
+}
+
 // AutoScalingMetrics tracks performance metrics for scaling decisions
 type AutoScalingMetrics struct {
 	// Write frequency metrics
@@ -85,6 +143,91 @@ type AutoScalingConfig struct {
 	MeasurementWindow    time.Duration // How often to check metrics (e.g., 100ms)
 	ScalingCooldown      time.Duration // Min time between scale operations (e.g., 1s)
 	StabilityRequirement int           // Consecutive measurements before scaling (e.g., 3)
+
+	// Strategy chooses the preferred mode each window (nil = ThresholdStrategy
+	// over the thresholds above). Cooldown and stability still apply.
+	Strategy ScalingStrategy
+}
+
+// ScalingMetrics is what a ScalingStrategy sees of one measurement window.
+type ScalingMetrics struct {
+	Time             time.Time       // End of the measurement window
+	Mode             AutoScalingMode // Mode in effect during the window
+	WritesPerSecond  uint64          // Write rate in the window
+	ContentionRatio  uint32          // Contention percentage since start (0-100)
+	AvgLatency       time.Duration   // Average write latency in the window
+	ActiveGoroutines uint32          // Concurrent writers at the end of the window
+}
+
+// ScalingStrategy decides which mode an AutoScalingLogger should run in.
+//
+// DecideMode is called once per measurement window from the scaling
+// goroutine. Returning a mode different from metrics.Mode votes for a
+// transition; the logger switches once StabilityRequirement consecutive
+// windows agree and ScalingCooldown has passed since the last transition.
+// Transitions chosen by a custom strategy are recorded with
+// ScalingReasonStrategy.
+//
+// Example (scale up early during business hours):
+//
+//	type businessHours struct{ iris.ThresholdStrategy }
+//
+//	func (s businessHours) DecideMode(m iris.ScalingMetrics) iris.AutoScalingMode {
+//	    if h := m.Time.Hour(); h >= 9 && h < 18 {
+//	        return iris.MPSCMode
+//	    }
+//	    return s.ThresholdStrategy.DecideMode(m)
+//	}
+type ScalingStrategy interface {
+	DecideMode(metrics ScalingMetrics) AutoScalingMode
+}
+
+// ScalingStrategyFunc adapts a function to the ScalingStrategy interface.
+type ScalingStrategyFunc func(metrics ScalingMetrics) AutoScalingMode
+
+// DecideMode calls f(metrics).
+func (f ScalingStrategyFunc) DecideMode(metrics ScalingMetrics) AutoScalingMode {
+	return f(metrics)
+}
+
+// ThresholdStrategy is the default scaling strategy, inspired by Lethe's
+// shouldScaleToMPSC: scale to MPSC when any ScaleToMPSC threshold is
+// reached, back to SingleRing when every metric is within its ScaleToSingle
+// limit, and otherwise keep the current mode. Only the threshold fields of
+// Config are used.
+type ThresholdStrategy struct {
+	Config AutoScalingConfig
+}
+
+// DecideMode implements ScalingStrategy.
+func (s ThresholdStrategy) DecideMode(metrics ScalingMetrics) AutoScalingMode {
+	mode, _ := s.decide(metrics)
+	return mode
+}
+
+// decide is DecideMode with the threshold that decided the mode.
+func (s ThresholdStrategy) decide(metrics ScalingMetrics) (AutoScalingMode, ScalingReason) {
+	// Scale to MPSC conditions
+	switch {
+	case metrics.WritesPerSecond >= s.Config.ScaleToMPSCWriteThreshold:
+		return MPSCMode, ScalingReasonWriteRate
+	case metrics.ContentionRatio >= s.Config.ScaleToMPSCContentionRatio:
+		return MPSCMode, ScalingReasonContention
+	case metrics.AvgLatency >= s.Config.ScaleToMPSCLatencyThreshold:
+		return MPSCMode, ScalingReasonLatency
+	case metrics.ActiveGoroutines >= s.Config.ScaleToMPSCGoroutineCount:
+		return MPSCMode, ScalingReasonGoroutines
+	}
+
+	// Scale to Single conditions
+	if metrics.WritesPerSecond <= s.Config.ScaleToSingleWriteThreshold &&
+		metrics.ContentionRatio <= s.Config.ScaleToSingleContentionRatio &&
+		metrics.AvgLatency <= s.Config.ScaleToSingleLatencyMax {
+		return SingleRingMode, ScalingReasonLowLoad
+	}
+
+	// No clear preference, maintain current mode
+	return metrics.Mode, ScalingReasonNone
 }
 
 // DefaultAutoScalingConfig returns production-ready auto-scaling configuration
@@ -117,6 +260,11 @@ type AutoScalingLogger struct {
 	singleRingLogger *Logger // Single-threaded ultra-fast mode
 	mpscLogger       *Logger // Multi-producer mode
 
+	// root is the logger With or Named derived this one from, which owns
+	// the mode, metrics and scaling loop; nil for a logger from
+	// NewAutoScalingLogger
+	root *AutoScalingLogger
+
 	// Auto-scaling components
 	metrics AutoScalingMetrics
 	config  AutoScalingConfig
@@ -136,6 +284,13 @@ type AutoScalingLogger struct {
 	totalScaleOperations atomic.Uint64 // Total number of scaling operations
 	scaleToMPSCCount     atomic.Uint64 // Scale to MPSC operations
 	scaleToSingleCount   atomic.Uint64 // Scale to Single operations
+
+	// Transition audit trail, guarded by historyMu
+	historyMu  sync.Mutex
+	modeSince  time.Time                           // Start of the current mode
+	timeInMode [2]time.Duration                    // Completed time per mode, indexed by AutoScalingMode
+	history    [scalingHistorySize]ScalingDecision // Ring of recent transitions
+	historyN   uint64                              // Transitions recorded so far
 }
 
 // NewAutoScalingLogger creates an auto-scaling logger
@@ -170,6 +325,7 @@ func NewAutoScalingLogger(cfg Config, scalingConfig AutoScalingConfig, opts ...O
 		config:           scalingConfig,
 		ctx:              ctx,
 		cancel:           cancel,
+		modeSince:        time.Now(),
 	}
 
 	// Start in SingleRing mode (most efficient for low load)
@@ -180,6 +336,9 @@ func NewAutoScalingLogger(cfg Config, scalingConfig AutoScalingConfig, opts ...O
 
 // Start begins auto-scaling operations
 func (asl *AutoScalingLogger) Start() error {
+	if asl.root != nil {
+		return asl.root.Start()
+	}
 	// Start both loggers
 	asl.singleRingLogger.Start()
 	asl.mpscLogger.Start()
@@ -193,6 +352,9 @@ func (asl *AutoScalingLogger) Start() error {
 
 // Close gracefully shuts down auto-scaling logger
 func (asl *AutoScalingLogger) Close() error {
+	if asl.root != nil {
+		return asl.root.Close()
+	}
 	asl.cancel()
 	asl.wg.Wait()
 
@@ -206,9 +368,41 @@ func (asl *AutoScalingLogger) Close() error {
 	return err2
 }
 
+// With returns an auto-scaling logger that adds fields to every record, as
+// Logger.With does. It shares the mode, metrics and lifecycle of asl: Start,
+// Close and the statistics methods of either act on both.
+func (asl *AutoScalingLogger) With(fields ...Field) *AutoScalingLogger {
+	if len(fields) == 0 {
+		return asl
+	}
+	return &AutoScalingLogger{
+		singleRingLogger: asl.singleRingLogger.With(fields...),
+		mpscLogger:       asl.mpscLogger.With(fields...),
+		root:             asl.scaling(),
+	}
+}
+
+// Named returns an auto-scaling logger named as by Logger.Named, sharing
+// the mode, metrics and lifecycle of asl like With.
+func (asl *AutoScalingLogger) Named(name string) *AutoScalingLogger {
+	return &AutoScalingLogger{
+		singleRingLogger: asl.singleRingLogger.Named(name),
+		mpscLogger:       asl.mpscLogger.Named(name),
+		root:             asl.scaling(),
+	}
+}
+
+// scaling returns the logger holding the scaling state shared by asl.
+func (asl *AutoScalingLogger) scaling() *AutoScalingLogger {
+	if asl.root != nil {
+		return asl.root
+	}
+	return asl
+}
+
 // getCurrentLogger returns the currently active logger based on mode
 func (asl *AutoScalingLogger) getCurrentLogger() *Logger {
-	mode := AutoScalingMode(asl.mode.Load())
+	mode := AutoScalingMode(asl.scaling().mode.Load())
 	switch mode {
 	case SingleRingMode:
 		return asl.singleRingLogger
@@ -221,67 +415,87 @@ func (asl *AutoScalingLogger) getCurrentLogger() *Logger {
 
 // Info logs at Info level with automatic scaling
 func (asl *AutoScalingLogger) Info(msg string, fields ...Field) {
+	s := asl.scaling()
 	start := time.Now()
 
 	// Track active goroutine
-	asl.metrics.activeGoroutines.Add(1)
-	defer asl.metrics.activeGoroutines.Add(^uint32(0)) // Subtract 1
+	s.metrics.activeGoroutines.Add(1)
+	defer s.metrics.activeGoroutines.Add(^uint32(0)) // Subtract 1
 
 	// Get current logger (with read lock for performance)
-	asl.transitionMu.RLock()
+	s.transitionMu.RLock()
 	logger := asl.getCurrentLogger()
-	asl.transitionMu.RUnlock()
+	s.transitionMu.RUnlock()
 
 	// Attempt write
 	logger.Info(msg, fields...)
 
 	// Update metrics
-	asl.updateMetrics(start, true)
+	s.updateMetrics(start, true)
+}
+
+// Trace logs at Trace level with automatic scaling
+func (asl *AutoScalingLogger) Trace(msg string, fields ...Field) {
+	s := asl.scaling()
+	start := time.Now()
+
+	s.metrics.activeGoroutines.Add(1)
+	defer s.metrics.activeGoroutines.Add(^uint32(0))
+
+	s.transitionMu.RLock()
+	logger := asl.getCurrentLogger()
+	s.transitionMu.RUnlock()
+
+	logger.Trace(msg, fields...)
+	s.updateMetrics(start, true)
 }
 
 // Debug logs at Debug level with automatic scaling
 func (asl *AutoScalingLogger) Debug(msg string, fields ...Field) {
+	s := asl.scaling()
 	start := time.Now()
 
-	asl.metrics.activeGoroutines.Add(1)
-	defer asl.metrics.activeGoroutines.Add(^uint32(0))
+	s.metrics.activeGoroutines.Add(1)
+	defer s.metrics.activeGoroutines.Add(^uint32(0))
 
-	asl.transitionMu.RLock()
+	s.transitionMu.RLock()
 	logger := asl.getCurrentLogger()
-	asl.transitionMu.RUnlock()
+	s.transitionMu.RUnlock()
 
 	logger.Debug(msg, fields...)
-	asl.updateMetrics(start, true)
+	s.updateMetrics(start, true)
 }
 
 // Warn logs at Warn level with automatic scaling
 func (asl *AutoScalingLogger) Warn(msg string, fields ...Field) {
+	s := asl.scaling()
 	start := time.Now()
 
-	asl.metrics.activeGoroutines.Add(1)
-	defer asl.metrics.activeGoroutines.Add(^uint32(0))
+	s.metrics.activeGoroutines.Add(1)
+	defer s.metrics.activeGoroutines.Add(^uint32(0))
 
-	asl.transitionMu.RLock()
+	s.transitionMu.RLock()
 	logger := asl.getCurrentLogger()
-	asl.transitionMu.RUnlock()
+	s.transitionMu.RUnlock()
 
 	logger.Warn(msg, fields...)
-	asl.updateMetrics(start, true)
+	s.updateMetrics(start, true)
 }
 
 // Error logs at Error level with automatic scaling
 func (asl *AutoScalingLogger) Error(msg string, fields ...Field) {
+	s := asl.scaling()
 	start := time.Now()
 
-	asl.metrics.activeGoroutines.Add(1)
-	defer asl.metrics.activeGoroutines.Add(^uint32(0))
+	s.metrics.activeGoroutines.Add(1)
+	defer s.metrics.activeGoroutines.Add(^uint32(0))
 
-	asl.transitionMu.RLock()
+	s.transitionMu.RLock()
 	logger := asl.getCurrentLogger()
-	asl.transitionMu.RUnlock()
+	s.transitionMu.RUnlock()
 
 	logger.Error(msg, fields...)
-	asl.updateMetrics(start, true)
+	s.updateMetrics(start, true)
 }
 
 // updateMetrics updates performance metrics for scaling decisions
@@ -354,7 +568,7 @@ func (asl *AutoScalingLogger) checkScalingDecision() {
 	currentMode := AutoScalingMode(asl.mode.Load())
 
 	// Determine preferred mode based on metrics
-	preferredMode := asl.determinePreferredMode(metrics)
+	preferredMode, reason := asl.determinePreferredMode(metrics)
 
 	// Update consecutive counters
 	if preferredMode == MPSCMode {
@@ -403,23 +617,15 @@ func (asl *AutoScalingLogger) checkScalingDecision() {
 
 	// Perform scaling if needed
 	if shouldScale {
-		asl.performScaling(targetMode)
+		asl.performScaling(targetMode, reason, metrics)
 	}
 
 	// Reset measurement window metrics
 	asl.resetWindowMetrics()
 }
 
-// scalingMetrics holds calculated metrics for scaling decisions
-type scalingMetrics struct {
-	writesPerSecond  uint64
-	contentionRatio  uint32
-	avgLatency       time.Duration
-	activeGoroutines uint32
-}
-
 // calculateCurrentMetrics computes current performance metrics
-func (asl *AutoScalingLogger) calculateCurrentMetrics() scalingMetrics {
+func (asl *AutoScalingLogger) calculateCurrentMetrics() ScalingMetrics {
 	windowDuration := asl.config.MeasurementWindow
 
 	// Calculate writes per second
@@ -456,37 +662,36 @@ func (asl *AutoScalingLogger) calculateCurrentMetrics() scalingMetrics {
 	// Get active goroutines
 	activeGoroutines := asl.metrics.activeGoroutines.Load()
 
-	return scalingMetrics{
-		writesPerSecond:  writesPerSecond,
-		contentionRatio:  contentionRatio,
-		avgLatency:       avgLatency,
-		activeGoroutines: activeGoroutines,
+	return ScalingMetrics{
+		Time:             time.Now(),
+		Mode:             AutoScalingMode(asl.mode.Load()),
+		WritesPerSecond:  writesPerSecond,
+		ContentionRatio:  contentionRatio,
+		AvgLatency:       avgLatency,
+		ActiveGoroutines: activeGoroutines,
 	}
 }
 
-// determinePreferredMode decides the optimal mode based on metrics
-func (asl *AutoScalingLogger) determinePreferredMode(metrics scalingMetrics) AutoScalingMode {
-	// Scale to MPSC conditions (inspired by Lethe's shouldScaleToMPSC)
-	if metrics.writesPerSecond >= asl.config.ScaleToMPSCWriteThreshold ||
-		metrics.contentionRatio >= asl.config.ScaleToMPSCContentionRatio ||
-		metrics.avgLatency >= asl.config.ScaleToMPSCLatencyThreshold ||
-		metrics.activeGoroutines >= asl.config.ScaleToMPSCGoroutineCount {
-		return MPSCMode
-	}
-
-	// Scale to Single conditions
-	if metrics.writesPerSecond <= asl.config.ScaleToSingleWriteThreshold &&
-		metrics.contentionRatio <= asl.config.ScaleToSingleContentionRatio &&
-		metrics.avgLatency <= asl.config.ScaleToSingleLatencyMax {
-		return SingleRingMode
+// determinePreferredMode asks the configured strategy for the preferred
+// mode and reports why it differs from the current one
+func (asl *AutoScalingLogger) determinePreferredMode(metrics ScalingMetrics) (AutoScalingMode, ScalingReason) {
+	switch strategy := asl.config.Strategy.(type) {
+	case nil:
+		return ThresholdStrategy{Config: asl.config}.decide(metrics)
+	case ThresholdStrategy:
+		return strategy.decide(metrics)
+	default:
+		mode := strategy.DecideMode(metrics)
+		if mode == metrics.Mode {
+			return mode, ScalingReasonNone
+		}
+		return mode, ScalingReasonStrategy
 	}
-
-	// No clear preference, maintain current mode
-	return AutoScalingMode(asl.mode.Load())
 }
 
-// performScaling executes the scaling operation with zero log loss
-func (asl *AutoScalingLogger) performScaling(targetMode AutoScalingMode) {
+// performScaling executes the scaling operation with zero log loss, records
+// it in the transition history and logs it through the new mode's logger
+func (asl *AutoScalingLogger) performScaling(targetMode AutoScalingMode, reason ScalingReason, metrics ScalingMetrics) {
 	currentMode := AutoScalingMode(asl.mode.Load())
 	if currentMode == targetMode {
 		return // No change needed
@@ -502,8 +707,9 @@ func (asl *AutoScalingLogger) performScaling(targetMode AutoScalingMode) {
 	}
 
 	// Perform atomic mode switch
+	now := time.Now()
 	asl.mode.Store(uint32(targetMode))
-	asl.lastScaleTime.Store(time.Now().UnixNano())
+	asl.lastScaleTime.Store(now.UnixNano())
 
 	// Update scaling statistics
 	asl.totalScaleOperations.Add(1)
@@ -516,6 +722,41 @@ func (asl *AutoScalingLogger) performScaling(targetMode AutoScalingMode) {
 	// Reset consecutive counters
 	asl.consecutiveMPSC.Store(0)
 	asl.consecutiveSingle.Store(0)
+
+	decision := ScalingDecision{
+		Time:             now,
+		From:             currentMode,
+		To:               targetMode,
+		Reason:           reason,
+		WritesPerSecond:  metrics.WritesPerSecond,
+		ContentionRatio:  metrics.ContentionRatio,
+		AvgLatency:       metrics.AvgLatency,
+		ActiveGoroutines: metrics.ActiveGoroutines,
+	}
+	asl.recordDecision(decision)
+
+	// Audit record; the transition lock only guards mode selection, so
+	// logging through the underlying logger cannot deadlock
+	asl.getCurrentLogger().Info("auto-scaling mode changed",
+		Str("from", decision.From.String()),
+		Str("to", decision.To.String()),
+		Str("reason", decision.Reason.String()),
+		Uint64("writes_per_sec", decision.WritesPerSecond),
+		Uint64("contention_pct", uint64(decision.ContentionRatio)),
+		Dur("avg_latency", decision.AvgLatency),
+		Uint64("active_goroutines", uint64(decision.ActiveGoroutines)),
+	)
+}
+
+// recordDecision adds a transition to the history and closes the time
+// spent in the previous mode.
+func (asl *AutoScalingLogger) recordDecision(d ScalingDecision) {
+	asl.historyMu.Lock()
+	defer asl.historyMu.Unlock()
+	asl.timeInMode[d.From] += d.Time.Sub(asl.modeSince)
+	asl.modeSince = d.Time
+	asl.history[asl.historyN%scalingHistorySize] = d
+	asl.historyN++
 }
 
 // resetWindowMetrics resets metrics for the next measurement window
@@ -527,12 +768,15 @@ func (asl *AutoScalingLogger) resetWindowMetrics() {
 
 // GetCurrentMode returns the current scaling mode
 func (asl *AutoScalingLogger) GetCurrentMode() AutoScalingMode {
-	return AutoScalingMode(asl.mode.Load())
+	return AutoScalingMode(asl.scaling().mode.Load())
 }
 
 // GetScalingStats returns auto-scaling performance statistics
 func (asl *AutoScalingLogger) GetScalingStats() AutoScalingStats {
-	return AutoScalingStats{
+	if asl.root != nil {
+		return asl.root.GetScalingStats()
+	}
+	stats := AutoScalingStats{
 		CurrentMode:          asl.GetCurrentMode(),
 		TotalScaleOperations: asl.totalScaleOperations.Load(),
 		ScaleToMPSCCount:     asl.scaleToMPSCCount.Load(),
@@ -541,6 +785,29 @@ func (asl *AutoScalingLogger) GetScalingStats() AutoScalingStats {
 		ContentionCount:      asl.metrics.contentionCount.Load(),
 		ActiveGoroutines:     asl.metrics.activeGoroutines.Load(),
 	}
+
+	asl.historyMu.Lock()
+	defer asl.historyMu.Unlock()
+	timeInMode := asl.timeInMode
+	// modeSince belongs to the target of the last recorded decision; the
+	// mode itself may already have moved on to a decision not yet recorded
+	current := SingleRingMode
+	if asl.historyN > 0 {
+		current = asl.history[(asl.historyN-1)%scalingHistorySize].To
+	}
+	timeInMode[current] += time.Since(asl.modeSince)
+	stats.TimeInSingleRing = timeInMode[SingleRingMode]
+	stats.TimeInMPSC = timeInMode[MPSCMode]
+
+	n := min(asl.historyN, scalingHistorySize)
+	if n > 0 {
+		stats.RecentDecisions = make([]ScalingDecision, 0, n)
+		for i := asl.historyN - n; i < asl.historyN; i++ {
+			stats.RecentDecisions = append(stats.RecentDecisions, asl.history[i%scalingHistorySize])
+		}
+		stats.LastReason = stats.RecentDecisions[n-1].Reason
+	}
+	return stats
 }
 
 // AutoScalingStats provides auto-scaling performance insights
@@ -552,4 +819,9 @@ type AutoScalingStats struct {
 	TotalWrites          uint64
 	ContentionCount      uint64
 	ActiveGoroutines     uint32
+
+	TimeInSingleRing time.Duration     // Total time spent in SingleRingMode
+	TimeInMPSC       time.Duration     // Total time spent in MPSCMode
+	LastReason       ScalingReason     // Reason of the most recent transition
+	RecentDecisions  []ScalingDecision // Up to 16 most recent transitions, oldest first
 }
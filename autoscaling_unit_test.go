@@ -316,7 +316,7 @@ func TestAutoScalingLogger_PerformScaling_DirectTest(t *testing.T) {
 
 	// Test same mode (no change)
 	initialMode := logger.GetCurrentMode()
-	logger.performScaling(initialMode)
+	logger.performScaling(initialMode, ScalingReasonNone, ScalingMetrics{})
 	if logger.GetCurrentMode() != initialMode {
 		t.Errorf("Expected mode to remain %v after scaling to same mode", initialMode)
 	}
@@ -327,7 +327,7 @@ func TestAutoScalingLogger_PerformScaling_DirectTest(t *testing.T) {
 		targetMode = SingleRingMode
 	}
 
-	logger.performScaling(targetMode)
+	logger.performScaling(targetMode, ScalingReasonNone, ScalingMetrics{})
 	if logger.GetCurrentMode() != targetMode {
 		t.Errorf("Expected mode to change to %v, got %v", targetMode, logger.GetCurrentMode())
 	}
@@ -344,7 +344,7 @@ func TestAutoScalingLogger_PerformScaling_DirectTest(t *testing.T) {
 		originalMode = MPSCMode
 	}
 
-	logger.performScaling(originalMode)
+	logger.performScaling(originalMode, ScalingReasonNone, ScalingMetrics{})
 	if logger.GetCurrentMode() != originalMode {
 		t.Errorf("Expected mode to change back to %v, got %v", originalMode, logger.GetCurrentMode())
 	}
@@ -387,9 +387,9 @@ func TestAutoScalingLogger_ConcurrentScaling(t *testing.T) {
 
 			// Alternate between modes
 			if idx%2 == 0 {
-				logger.performScaling(MPSCMode)
+				logger.performScaling(MPSCMode, ScalingReasonNone, ScalingMetrics{})
 			} else {
-				logger.performScaling(SingleRingMode)
+				logger.performScaling(SingleRingMode, ScalingReasonNone, ScalingMetrics{})
 			}
 		}(i)
 	}
@@ -408,3 +408,135 @@ func TestAutoScalingLogger_ConcurrentScaling(t *testing.T) {
 		t.Error("Expected some scaling operations after concurrent test")
 	}
 }
+
+func TestAutoScalingLogger_DeterminePreferredModeReason(t *testing.T) {
+	logger, err := NewAutoScalingLogger(Config{Output: &testSyncer{}, Encoder: NewJSONEncoder(), Level: Info},
+		DefaultAutoScalingConfig())
+	if err != nil {
+		t.Fatalf("Failed to create auto-scaling logger: %v", err)
+	}
+	defer safeCloseLogger(t, logger)
+
+	tests := []struct {
+		name    string
+		metrics ScalingMetrics
+		mode    AutoScalingMode
+		reason  ScalingReason
+	}{
+		{"write rate", ScalingMetrics{WritesPerSecond: 5000, ActiveGoroutines: 10}, MPSCMode, ScalingReasonWriteRate},
+		{"contention", ScalingMetrics{ContentionRatio: 50}, MPSCMode, ScalingReasonContention},
+		{"latency", ScalingMetrics{AvgLatency: time.Second}, MPSCMode, ScalingReasonLatency},
+		{"goroutines", ScalingMetrics{ActiveGoroutines: 10}, MPSCMode, ScalingReasonGoroutines},
+		{"low load", ScalingMetrics{}, SingleRingMode, ScalingReasonLowLoad},
+		{"in between", ScalingMetrics{WritesPerSecond: 500}, SingleRingMode, ScalingReasonNone},
+	}
+	for _, tt := range tests {
+		if mode, reason := logger.determinePreferredMode(tt.metrics); mode != tt.mode || reason != tt.reason {
+			t.Errorf("%s: determinePreferredMode = %v, %v; want %v, %v", tt.name, mode, reason, tt.mode, tt.reason)
+		}
+	}
+}
+
+func TestAutoScalingLogger_TransitionAudit(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := NewAutoScalingLogger(Config{Output: out, Encoder: NewJSONEncoder(), Level: Info},
+		DefaultAutoScalingConfig())
+	if err != nil {
+		t.Fatalf("Failed to create auto-scaling logger: %v", err)
+	}
+	if err := logger.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if stats := logger.GetScalingStats(); stats.LastReason != ScalingReasonNone || stats.RecentDecisions != nil {
+		t.Errorf("fresh logger stats = %+v, want no decisions", stats)
+	}
+	time.Sleep(5 * time.Millisecond)
+	logger.performScaling(MPSCMode, ScalingReasonGoroutines, ScalingMetrics{ActiveGoroutines: 7})
+	time.Sleep(5 * time.Millisecond)
+	logger.performScaling(SingleRingMode, ScalingReasonLowLoad, ScalingMetrics{WritesPerSecond: 12})
+
+	stats := logger.GetScalingStats()
+	if stats.LastReason != ScalingReasonLowLoad || len(stats.RecentDecisions) != 2 {
+		t.Fatalf("LastReason = %v with %d decisions, want low_load and 2", stats.LastReason, len(stats.RecentDecisions))
+	}
+	first := stats.RecentDecisions[0]
+	if first.From != SingleRingMode || first.To != MPSCMode || first.Reason != ScalingReasonGoroutines || first.ActiveGoroutines != 7 {
+		t.Errorf("first decision = %+v", first)
+	}
+	if stats.TimeInMPSC < 5*time.Millisecond || stats.TimeInSingleRing < 5*time.Millisecond {
+		t.Errorf("TimeInMPSC = %v, TimeInSingleRing = %v, want at least 5ms each", stats.TimeInMPSC, stats.TimeInSingleRing)
+	}
+
+	// The history keeps the most recent transitions, oldest first
+	for i := 0; i < scalingHistorySize; i++ {
+		logger.performScaling(MPSCMode, ScalingReasonLatency, ScalingMetrics{WritesPerSecond: uint64(i)})
+		logger.performScaling(SingleRingMode, ScalingReasonLowLoad, ScalingMetrics{WritesPerSecond: uint64(i)})
+	}
+	recent := logger.GetScalingStats().RecentDecisions
+	if len(recent) != scalingHistorySize || recent[0].WritesPerSecond != scalingHistorySize/2 || recent[len(recent)-1].To != SingleRingMode {
+		t.Errorf("history after wrapping = %+v", recent)
+	}
+
+	safeCloseLogger(t, logger)
+	output := out.String()
+	if got := strings.Count(output, `"msg":"auto-scaling mode changed"`); got != 2+2*scalingHistorySize {
+		t.Errorf("transition records = %d, want %d", got, 2+2*scalingHistorySize)
+	}
+	if !strings.Contains(output, `"from":"SingleRing","to":"MPSC","reason":"goroutines"`) {
+		t.Errorf("transition record lacks from/to/reason: %s", output)
+	}
+}
+
+func TestAutoScalingLogger_CustomStrategy(t *testing.T) {
+	var seen []AutoScalingMode
+	scalingConfig := DefaultAutoScalingConfig()
+	scalingConfig.ScalingCooldown = 0
+	scalingConfig.StabilityRequirement = 2
+	scalingConfig.Strategy = ScalingStrategyFunc(func(m ScalingMetrics) AutoScalingMode {
+		seen = append(seen, m.Mode)
+		return MPSCMode // Regardless of load
+	})
+	logger, err := NewAutoScalingLogger(Config{Output: &testSyncer{}, Encoder: NewJSONEncoder(), Level: Info}, scalingConfig)
+	if err != nil {
+		t.Fatalf("Failed to create auto-scaling logger: %v", err)
+	}
+	defer safeCloseLogger(t, logger)
+
+	// Driven by hand instead of the scaling goroutine
+	logger.checkScalingDecision()
+	if logger.GetCurrentMode() != SingleRingMode {
+		t.Fatal("switched before StabilityRequirement windows agreed")
+	}
+	logger.checkScalingDecision()
+	logger.checkScalingDecision()
+	if logger.GetCurrentMode() != MPSCMode {
+		t.Fatal("custom strategy did not switch to MPSC")
+	}
+	stats := logger.GetScalingStats()
+	if stats.TotalScaleOperations != 1 || stats.LastReason != ScalingReasonStrategy {
+		t.Errorf("TotalScaleOperations = %d, LastReason = %v; want 1 and strategy", stats.TotalScaleOperations, stats.LastReason)
+	}
+	if want := []AutoScalingMode{SingleRingMode, SingleRingMode, MPSCMode}; len(seen) != 3 || seen[0] != want[0] || seen[1] != want[1] || seen[2] != want[2] {
+		t.Errorf("strategy saw modes %v, want %v", seen, want)
+	}
+}
+
+func TestThresholdStrategy_AsStrategy(t *testing.T) {
+	scalingConfig := DefaultAutoScalingConfig()
+	scalingConfig.Strategy = ThresholdStrategy{Config: DefaultAutoScalingConfig()}
+	logger, err := NewAutoScalingLogger(Config{Output: &testSyncer{}, Encoder: NewJSONEncoder(), Level: Info}, scalingConfig)
+	if err != nil {
+		t.Fatalf("Failed to create auto-scaling logger: %v", err)
+	}
+	defer safeCloseLogger(t, logger)
+
+	// An explicit ThresholdStrategy keeps reporting which threshold tripped
+	metrics := ScalingMetrics{ContentionRatio: 50}
+	if mode, reason := logger.determinePreferredMode(metrics); mode != MPSCMode || reason != ScalingReasonContention {
+		t.Errorf("determinePreferredMode = %v, %v; want MPSC, contention", mode, reason)
+	}
+	if mode := scalingConfig.Strategy.DecideMode(metrics); mode != MPSCMode {
+		t.Errorf("DecideMode = %v, want MPSC", mode)
+	}
+}
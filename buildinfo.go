@@ -0,0 +1,89 @@
+// buildinfo.go: Build information fields for Iris logging library
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	buildInfoOnce   sync.Once
+	buildInfoCached []Field
+)
+
+// BuildInfo returns fields describing the running binary, read once from
+// runtime/debug.ReadBuildInfo:
+//   - "version": main module version (e.g. "v1.4.2" or "(devel)")
+//   - "vcs_revision": VCS commit the binary was built from
+//   - "build_time": VCS commit time
+//   - "vcs_modified": true when built from a dirty working tree
+//
+// Fields whose value is unavailable are omitted, so binaries built without
+// VCS stamping (go build -buildvcs=false, go run) only report the version.
+// The returned slice must not be modified.
+func BuildInfo() []Field {
+	buildInfoOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			buildInfoCached = buildInfoFields(info)
+		}
+	})
+	return buildInfoCached
+}
+
+// WithBuildInfo includes the BuildInfo fields on every record, making each
+// log line attributable to an exact build.
+//
+// The fields are computed once per process; the per-record cost is copying
+// the precomputed values into the record.
+//
+// Returns:
+//   - Option: Configuration function to enable build information fields
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{}, iris.WithBuildInfo())
+//	// {"level":"info","msg":"started","version":"v1.4.2","vcs_revision":"9f2c1e0",...}
+func WithBuildInfo() Option {
+	return func(o *loggerOptions) {
+		for _, field := range BuildInfo() {
+			o.addProvider(fieldProvider{min: StacktraceDisabled, fn: func() Field { return field }})
+		}
+	}
+}
+
+// buildInfoFields converts build information into log fields.
+func buildInfoFields(info *debug.BuildInfo) []Field {
+	var fields []Field
+	if info.Main.Version != "" {
+		fields = append(fields, Str("version", info.Main.Version))
+	}
+
+	var revision, buildTime string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			buildTime = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if revision != "" {
+		fields = append(fields, Str("vcs_revision", revision))
+	}
+	if buildTime != "" {
+		fields = append(fields, Str("build_time", buildTime))
+	}
+	if revision != "" && modified {
+		fields = append(fields, Bool("vcs_modified", true))
+	}
+	return fields
+}
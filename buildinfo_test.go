@@ -0,0 +1,104 @@
+// buildinfo_test.go: Tests for build information fields
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestBuildInfoFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *debug.BuildInfo
+		expected map[string]string
+	}{
+		{
+			name: "full vcs stamping",
+			info: &debug.BuildInfo{
+				Main: debug.Module{Version: "v1.2.3"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "abc123"},
+					{Key: "vcs.time", Value: "2025-09-06T14:30:45Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			expected: map[string]string{
+				"version":      "v1.2.3",
+				"vcs_revision": "abc123",
+				"build_time":   "2025-09-06T14:30:45Z",
+				"vcs_modified": "true",
+			},
+		},
+		{
+			name:     "no vcs information",
+			info:     &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			expected: map[string]string{"version": "(devel)"},
+		},
+		{
+			name: "clean tree",
+			info: &debug.BuildInfo{
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "abc123"},
+					{Key: "vcs.modified", Value: "false"},
+				},
+			},
+			expected: map[string]string{"vcs_revision": "abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := buildInfoFields(tt.info)
+			if len(fields) != len(tt.expected) {
+				t.Fatalf("expected %d fields, got %d: %+v", len(tt.expected), len(fields), fields)
+			}
+			for _, f := range fields {
+				want, ok := tt.expected[f.Key()]
+				if !ok {
+					t.Errorf("unexpected field %q", f.Key())
+					continue
+				}
+				got := f.StringValue()
+				if f.IsBool() {
+					got = "false"
+					if f.BoolValue() {
+						got = "true"
+					}
+				}
+				if got != want {
+					t.Errorf("field %q = %q, want %q", f.Key(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithBuildInfo_AddsFields(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:   Info,
+		Output:  buf,
+		Encoder: NewJSONEncoder(),
+	}, WithBuildInfo())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("started")
+	_ = logger.Sync()
+
+	output := buf.String()
+	for _, f := range BuildInfo() {
+		if !strings.Contains(output, `"`+f.Key()+`":`) {
+			t.Errorf("expected build field %q in output: %s", f.Key(), output)
+		}
+	}
+}
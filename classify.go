@@ -0,0 +1,205 @@
+// classify.go: PHI/PII classification of log records
+//
+// Regulated deployments (healthcare, payments) must know which log records
+// carry personal data so that they can be retained, access-controlled or
+// shipped differently. The classification stage runs in the consumer, just
+// before encoding: every string field and the message are matched against a
+// set of detectors, and a record that matches is tagged with a
+// "data_classification" field listing the detected categories and, when a
+// restricted sink is configured, written there instead of the regular
+// output. Producers pay nothing for it.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultClassificationKey is the field added to classified records.
+const DefaultClassificationKey = "data_classification"
+
+// Detector recognizes one category of sensitive data.
+//
+// Match receives the key and string value of a field (the key is "msg" for
+// the record message) and reports whether the value belongs to the
+// category. Matchers run in the consumer thread and must be safe for
+// concurrent use when a detector is shared between loggers.
+type Detector struct {
+	Name  string // Category written to the classification field (e.g. "ssn")
+	Match func(key, value string) bool
+}
+
+// Built-in detectors.
+var (
+	// DetectSSN matches US Social Security numbers written as 123-45-6789.
+	DetectSSN = RegexpDetector("ssn", regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`))
+
+	// DetectEmail matches email addresses.
+	DetectEmail = RegexpDetector("email", regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`))
+
+	// DetectIP matches IPv4 and IPv6 addresses.
+	DetectIP = Detector{Name: "ip", Match: func(_, value string) bool { return containsIP(value) }}
+)
+
+// ipCandidate finds substrings that may be IP addresses; each is confirmed
+// with netip.ParseAddr.
+var ipCandidate = regexp.MustCompile(`[0-9A-Fa-f:.]{2,45}`)
+
+// containsIP reports whether s contains a valid IPv4 or IPv6 address.
+func containsIP(s string) bool {
+	if !strings.ContainsAny(s, ".:") {
+		return false // Fast path for the common case
+	}
+	for _, c := range ipCandidate.FindAllString(s, -1) {
+		c = strings.Trim(c, ".:")
+		if strings.Count(c, ".") != 3 && strings.Count(c, ":") < 2 {
+			continue // Neither dotted quad nor IPv6 shaped
+		}
+		if _, err := netip.ParseAddr(c); err == nil {
+			return true
+		}
+		if _, err := netip.ParseAddrPort(c); err == nil {
+			return true // IPv4 with port
+		}
+	}
+	return false
+}
+
+// RegexpDetector returns a detector matching values against re.
+func RegexpDetector(name string, re *regexp.Regexp) Detector {
+	return Detector{Name: name, Match: func(_, value string) bool { return re.MatchString(value) }}
+}
+
+// KeyDetector returns a detector matching fields by key (case-insensitive),
+// whatever their non-empty value. Use it for fields known to hold personal
+// data, such as "patient_id" or "dob".
+func KeyDetector(name string, keys ...string) Detector {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return Detector{Name: name, Match: func(key, _ string) bool {
+		_, ok := set[strings.ToLower(key)]
+		return ok
+	}}
+}
+
+// ClassificationConfig configures record classification.
+type ClassificationConfig struct {
+	// Detectors to apply, in the order categories are listed
+	// (default: DetectSSN, DetectEmail, DetectIP)
+	Detectors []Detector
+
+	// Key of the classification field (default: DefaultClassificationKey)
+	Key string
+
+	// Restricted receives classified records instead of the logger output.
+	// When nil, classified records are tagged and written normally.
+	Restricted WriteSyncer
+}
+
+// classifier is the compiled form of a ClassificationConfig.
+type classifier struct {
+	detectors  []Detector
+	key        string
+	restricted WriteSyncer
+	labels     sync.Map // uint64 category mask -> comma-separated names
+}
+
+// WithClassification enables PHI/PII classification of log records.
+//
+// Detectors are matched against the message and every string field of each
+// record (secret fields are already redacted and are skipped; error,
+// stringer and object fields are not inspected). Records with at least one
+// match get a field, "data_classification" by default, whose value lists
+// the matching categories in detector order, e.g. "ssn,email". If
+// cfg.Restricted is set, those records are written to it instead of the
+// logger output; Sync and Close sync it as well.
+//
+// Only the first 64 detectors are used.
+//
+// Performance: detection runs in the consumer thread and costs one pass of
+// every detector over every string value
+//
+// Example:
+//
+//	phi, _ := iris.NewSharedFileWriter("/var/log/app/phi.log")
+//	logger, _ := iris.New(cfg, iris.WithClassification(iris.ClassificationConfig{
+//	    Detectors: []iris.Detector{
+//	        iris.DetectSSN, iris.DetectEmail,
+//	        iris.KeyDetector("patient", "patient_id", "mrn"),
+//	    },
+//	    Restricted: phi,
+//	}))
+func WithClassification(cfg ClassificationConfig) Option {
+	c := &classifier{detectors: cfg.Detectors, key: cfg.Key, restricted: cfg.Restricted}
+	if c.detectors == nil {
+		c.detectors = []Detector{DetectSSN, DetectEmail, DetectIP}
+	}
+	if len(c.detectors) > 64 {
+		c.detectors = c.detectors[:64]
+	}
+	if c.key == "" {
+		c.key = DefaultClassificationKey
+	}
+	return func(o *loggerOptions) { o.classifier = c }
+}
+
+// classify tags rec when it contains sensitive data and returns the writer
+// the record should go to: restricted for classified records when
+// configured, out otherwise.
+func (c *classifier) classify(rec *Record, out WriteSyncer) WriteSyncer {
+	mask := c.match("msg", rec.Msg, 0)
+	for i := int32(0); i < rec.n; i++ {
+		if f := &rec.fields[i]; f.T == kindString {
+			mask = c.match(f.K, f.Str, mask)
+		}
+	}
+	if mask == 0 {
+		return out
+	}
+	rec.AddField(Str(c.key, c.label(mask)))
+	if c.restricted != nil {
+		return c.restricted
+	}
+	return out
+}
+
+// match adds the categories of the detectors matching key/value to mask.
+func (c *classifier) match(key, value string, mask uint64) uint64 {
+	if value == "" {
+		return mask
+	}
+	for i := range c.detectors {
+		if mask&(1<<i) == 0 && c.detectors[i].Match(key, value) {
+			mask |= 1 << i
+		}
+	}
+	return mask
+}
+
+// label returns the comma-separated category names of mask, cached so that
+// classified records do not allocate after the first occurrence.
+func (c *classifier) label(mask uint64) string {
+	if v, ok := c.labels.Load(mask); ok {
+		return v.(string)
+	}
+	var b strings.Builder
+	for i := range c.detectors {
+		if mask&(1<<i) != 0 {
+			if b.Len() > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(c.detectors[i].Name)
+		}
+	}
+	v, _ := c.labels.LoadOrStore(mask, b.String())
+	return v.(string)
+}
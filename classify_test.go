@@ -0,0 +1,108 @@
+// classify_test.go: Tests for PHI/PII classification
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector Detector
+		key      string
+		value    string
+		want     bool
+	}{
+		{"ssn", DetectSSN, "note", "patient ssn 123-45-6789 on file", true},
+		{"ssn too long", DetectSSN, "note", "order 1123-45-67890", false},
+		{"ssn phone", DetectSSN, "note", "call 555-1234", false},
+		{"email", DetectEmail, "user", "jane.doe+test@example.org", true},
+		{"email handle", DetectEmail, "user", "@jane", false},
+		{"ipv4", DetectIP, "peer", "connection from 192.168.1.20:443", true},
+		{"ipv4 out of range", DetectIP, "peer", "300.1.1.1", false},
+		{"ipv6", DetectIP, "peer", "client 2001:db8::1 connected", true},
+		{"version", DetectIP, "build", "v1.2.3", false},
+		{"time", DetectIP, "at", "12:30:45", false},
+		{"key", KeyDetector("patient", "Patient_ID", "mrn"), "patient_id", "P-123", true},
+		{"other key", KeyDetector("patient", "mrn"), "order_id", "P-123", false},
+		{"regexp", RegexpDetector("mrn", regexp.MustCompile(`MRN-\d{6}`)), "note", "see MRN-004211", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.detector.Match(tt.key, tt.value); got != tt.want {
+				t.Errorf("%s.Match(%q, %q) = %v, want %v", tt.detector.Name, tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithClassification_TagsRecords(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true},
+		WithClassification(ClassificationConfig{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("login", Str("user", "jane@example.org"), Str("ssn", "123-45-6789"))
+	logger.Info("login from 10.0.0.7")
+	logger.Info("cache warmed", Int("entries", 42))
+	logger.Info("redacted", Secret("ssn", "123-45-6789"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 records, got %d:\n%s", len(lines), out.String())
+	}
+	wants := []string{`"data_classification":"ssn,email"`, `"data_classification":"ip"`, "", ""}
+	for i, want := range wants {
+		if want == "" {
+			if strings.Contains(lines[i], DefaultClassificationKey) {
+				t.Errorf("record %d unexpectedly classified: %s", i, lines[i])
+			}
+			continue
+		}
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("record %d missing %s: %s", i, want, lines[i])
+		}
+	}
+}
+
+func TestWithClassification_RoutesToRestricted(t *testing.T) {
+	out := &testSyncer{}
+	restricted := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024},
+		WithClassification(ClassificationConfig{
+			Detectors:  []Detector{KeyDetector("patient", "mrn")},
+			Key:        "phi",
+			Restricted: restricted,
+		}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+
+	logger.Info("admitted", Str("mrn", "004211"))
+	logger.Info("bed assigned", Str("ward", "B"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if !strings.Contains(restricted.String(), `"phi":"patient"`) || !strings.Contains(restricted.String(), "admitted") {
+		t.Errorf("restricted sink missing classified record: %s", restricted.String())
+	}
+	if strings.Contains(out.String(), "admitted") {
+		t.Errorf("classified record leaked to the regular output: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "bed assigned") {
+		t.Errorf("regular record missing from output: %s", out.String())
+	}
+}
@@ -0,0 +1,218 @@
+// clock_drift.go: Drift detection between the cached clock and real time
+//
+// Records are stamped with a cached clock, refreshed every 500µs by a
+// background goroutine, so that logging does not pay for time.Now. A long
+// GC pause, a CPU-starved process or a stopped cache leave it behind the
+// real time, and every record logged meanwhile carries a stale timestamp.
+// With WithClockDriftCheck a goroutine compares the cached clock with
+// time.Now periodically; when they drift apart by more than a threshold the
+// logger stamps records with time.Now until they agree again, and the drift
+// is counted and reported.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// Defaults of WithClockDriftCheck.
+const (
+	DefaultClockDriftInterval  = time.Second
+	DefaultClockDriftThreshold = 10 * time.Millisecond
+)
+
+// ClockDriftConfig configures WithClockDriftCheck.
+type ClockDriftConfig struct {
+	// Interval between two checks (default DefaultClockDriftInterval)
+	Interval time.Duration
+
+	// Threshold is the drift above which records are stamped with
+	// time.Now (default DefaultClockDriftThreshold). The cached clock is
+	// used again once the drift is back under half of it.
+	Threshold time.Duration
+
+	// OnDrift, if set, is called with each drift instead of reporting it
+	// to the error handler. It runs on the checking goroutine.
+	OnDrift func(ClockDrift)
+}
+
+// ClockDrift describes a drift of the cached clock detected by
+// WithClockDriftCheck.
+type ClockDrift struct {
+	Logger string        // Name of the logger the check belongs to
+	At     time.Time     // Real time of the check
+	Drift  time.Duration // Real time minus cached time (positive: the cache lags)
+}
+
+// clockDriftState is the drift check of a logger, shared with its clones.
+type clockDriftState struct {
+	cfg ClockDriftConfig
+
+	cached func() int64     // Cached clock, Unix ns (timecache.CachedTimeNano)
+	real   func() time.Time // Real clock (time.Now)
+
+	corrected atomic.Bool  // Stamping with the real clock
+	events    atomic.Int64 // Checks that found the threshold exceeded after agreeing
+	last      atomic.Int64 // Drift of the last check (ns)
+	max       atomic.Int64 // Largest absolute drift seen (ns)
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// WithClockDriftCheck compares the cached clock records are stamped with
+// against time.Now every cfg.Interval. When they differ by more than
+// cfg.Threshold, the logger and its clones stamp records with time.Now
+// until a later check finds them within half the threshold again.
+//
+// Each drift is reported once, when it starts: with ErrCodeClockDrift to
+// the error handler (or Logger.Errors), or to cfg.OnDrift. Logger.Stats
+// reports "clock_drift_events", "clock_drift_ns" (last check),
+// "clock_drift_max_ns" and "clock_corrected" (1 while stamping with
+// time.Now).
+//
+// The check runs on its own goroutine, started by Start and stopped by
+// Close. It applies only to loggers using the default clock: with
+// Config.TimeFn set, the option is ignored.
+//
+// Parameters:
+//   - cfg: Check interval, drift threshold and optional callback
+//
+// Returns:
+//   - Option: Configuration function to enable the drift check
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithClockDriftCheck(iris.ClockDriftConfig{
+//		Threshold: 5 * time.Millisecond,
+//		OnDrift: func(d iris.ClockDrift) {
+//			metrics.LogClockDrift.Observe(d.Drift.Seconds())
+//		},
+//	}))
+func WithClockDriftCheck(cfg ClockDriftConfig) Option {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultClockDriftInterval
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultClockDriftThreshold
+	}
+	return func(o *loggerOptions) {
+		o.clockDrift = &cfg
+	}
+}
+
+// newClockDriftState creates the drift check for cfg (nil without
+// WithClockDriftCheck).
+func newClockDriftState(cfg *ClockDriftConfig) *clockDriftState {
+	if cfg == nil {
+		return nil
+	}
+	return &clockDriftState{
+		cfg:    *cfg,
+		cached: timecache.CachedTimeNano,
+		real:   time.Now,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// now is the clock of a logger with a drift check.
+func (d *clockDriftState) now() time.Time {
+	if d.corrected.Load() {
+		return d.real()
+	}
+	return time.Unix(0, d.cached())
+}
+
+// checkClockDrift compares the clocks once and switches the logger clock
+// if needed.
+func (l *Logger) checkClockDrift() {
+	d := l.clockDrift
+	now := d.real()
+	drift := time.Duration(now.UnixNano() - d.cached())
+	abs := drift.Abs()
+	d.last.Store(int64(drift))
+	for {
+		peak := d.max.Load()
+		if int64(abs) <= peak || d.max.CompareAndSwap(peak, int64(abs)) {
+			break
+		}
+	}
+	switch {
+	case abs > d.cfg.Threshold && !d.corrected.Load():
+		d.corrected.Store(true)
+		d.events.Add(1)
+		l.reportClockDrift(ClockDrift{Logger: l.name, At: now, Drift: drift})
+	case abs <= d.cfg.Threshold/2 && d.corrected.Load():
+		d.corrected.Store(false)
+	}
+}
+
+// startClockDrift launches the checking goroutine once.
+func (l *Logger) startClockDrift() {
+	d := l.clockDrift
+	if d == nil {
+		return
+	}
+	d.startOnce.Do(func() {
+		go func() {
+			defer close(d.done)
+			ticker := time.NewTicker(d.cfg.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					l.checkClockDrift()
+				case <-d.quit:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// stopClockDrift stops the checking goroutine.
+func (l *Logger) stopClockDrift() {
+	d := l.clockDrift
+	if d == nil {
+		return
+	}
+	d.startOnce.Do(func() { close(d.done) }) // Never started
+	d.stopOnce.Do(func() { close(d.quit) })
+	<-d.done
+}
+
+// reportClockDrift hands drift to the OnDrift callback or the error
+// handler.
+func (l *Logger) reportClockDrift(drift ClockDrift) {
+	if l.clockDrift.cfg.OnDrift != nil {
+		l.clockDrift.cfg.OnDrift(drift)
+		return
+	}
+	l.reportError(NewLoggerError(ErrCodeClockDrift, "cached clock drifted from real time: stamping records with time.Now").
+		WithSeverity("warning").
+		WithContext("logger", drift.Logger).
+		WithContext("drift", drift.Drift.String()).
+		WithContext("threshold", l.clockDrift.cfg.Threshold.String()))
+}
+
+// addStats adds the drift check statistics to stats.
+func (d *clockDriftState) addStats(stats map[string]int64) {
+	stats["clock_drift_events"] = d.events.Load()
+	stats["clock_drift_ns"] = d.last.Load()
+	stats["clock_drift_max_ns"] = d.max.Load()
+	stats["clock_corrected"] = 0
+	if d.corrected.Load() {
+		stats["clock_corrected"] = 1
+	}
+}
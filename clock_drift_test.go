@@ -0,0 +1,115 @@
+// clock_drift_test.go: Tests for the cached clock drift check
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClockDrift_CorrectsAndRecovers(t *testing.T) {
+	var drifts []ClockDrift
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true, Name: "api", AutoStart: AutoStartOff},
+		WithClockDriftCheck(ClockDriftConfig{Threshold: 10 * time.Millisecond, OnDrift: func(d ClockDrift) {
+			drifts = append(drifts, d)
+		}}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	wall := time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)
+	var cached atomic.Int64
+	d := logger.clockDrift
+	d.real = func() time.Time { return wall }
+	d.cached = cached.Load
+	clone := logger.With(Str("k", "v"))
+
+	tests := []struct {
+		name      string
+		lag       time.Duration
+		corrected bool
+		events    int64
+	}{
+		{"in sync", time.Millisecond, false, 0},
+		{"drift", 50 * time.Millisecond, true, 1},
+		{"still drifting", 40 * time.Millisecond, true, 1},
+		{"under threshold, over half", 8 * time.Millisecond, true, 1},
+		{"recovered", 2 * time.Millisecond, false, 1},
+		{"ahead", -20 * time.Millisecond, true, 2},
+	}
+	for _, tt := range tests {
+		cached.Store(wall.Add(-tt.lag).UnixNano())
+		logger.checkClockDrift()
+		stats := clone.Stats()
+		if got := stats["clock_corrected"] == 1; got != tt.corrected {
+			t.Errorf("%s: corrected = %v, want %v", tt.name, got, tt.corrected)
+		}
+		if got := stats["clock_drift_events"]; got != tt.events {
+			t.Errorf("%s: events = %d, want %d", tt.name, got, tt.events)
+		}
+		if got := time.Duration(stats["clock_drift_ns"]); got != tt.lag {
+			t.Errorf("%s: drift = %v, want %v", tt.name, got, tt.lag)
+		}
+		want := time.Unix(0, cached.Load())
+		if tt.corrected {
+			want = wall
+		}
+		if got := clone.clock(); !got.Equal(want) {
+			t.Errorf("%s: clock = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	if time.Duration(clone.Stats()["clock_drift_max_ns"]) != 50*time.Millisecond {
+		t.Errorf("max drift = %d", clone.Stats()["clock_drift_max_ns"])
+	}
+	if len(drifts) != 2 || drifts[0].Drift != 50*time.Millisecond || drifts[0].Logger != "api" || !drifts[0].At.Equal(wall) {
+		t.Errorf("OnDrift got %+v", drifts)
+	}
+}
+
+func TestClockDrift_ReportsToErrorHandler(t *testing.T) {
+	reported := captureErrors(t)
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true, AutoStart: AutoStartOff},
+		WithClockDriftCheck(ClockDriftConfig{Interval: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	var lag atomic.Int64
+	lag.Store(int64(time.Second))
+	logger.clockDrift.cached = func() int64 { return time.Now().UnixNano() - lag.Load() }
+	logger.Start()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.Stats()["clock_drift_events"] == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	got := reported()
+	if len(got) != 1 || got[0].ErrorCode() != ErrCodeClockDrift {
+		t.Fatalf("reported %v, want one ErrCodeClockDrift", got)
+	}
+}
+
+func TestClockDrift_IgnoredWithTimeFn(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true, TimeFn: clock.Now},
+		WithClockDriftCheck(ClockDriftConfig{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	if logger.clockDrift != nil {
+		t.Error("drift check enabled with a custom TimeFn")
+	}
+	if _, ok := logger.Stats()["clock_drift_events"]; ok {
+		t.Error("Stats reports drift without a drift check")
+	}
+}
@@ -0,0 +1,310 @@
+// bench.go: "iris-config bench" measures a configuration on the target machine
+//
+// bench runs short standardized workloads (a bare message, a message with
+// five typed fields, and the same from several goroutines) through every
+// selected encoder and sink, using the ring settings of a config file when
+// one is given. It reports producer-side ns/op, process-wide allocations
+// per op, drain throughput and drop rate, which is usually enough to choose
+// an encoder, a capacity and a backpressure policy for the host at hand.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agilira/iris"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// benchEncoders maps encoder names to constructors.
+var benchEncoders = map[string]func() iris.Encoder{
+	"json":    func() iris.Encoder { return iris.NewJSONEncoder() },
+	"text":    func() iris.Encoder { return iris.NewTextEncoder() },
+	"console": func() iris.Encoder { return iris.NewConsoleEncoder() },
+	"binary":  func() iris.Encoder { return iris.NewBinaryEncoder() },
+}
+
+// benchWorkload is one standardized logging pattern.
+type benchWorkload struct {
+	name     string
+	parallel bool
+	log      func(l *iris.Logger, i int)
+}
+
+// benchWorkloads lists the workloads in report order.
+var benchWorkloads = []benchWorkload{
+	{"simple", false, func(l *iris.Logger, _ int) { l.Info("request served") }},
+	{"fields", false, logWithFields},
+	{"parallel", true, logWithFields},
+}
+
+// logWithFields logs a message with five typed fields.
+func logWithFields(l *iris.Logger, i int) {
+	l.Info("request served",
+		iris.Str("method", "GET"),
+		iris.Str("path", "/api/v1/orders"),
+		iris.Int("status", 200),
+		iris.Int("iteration", i),
+		iris.Dur("elapsed", 1500*time.Microsecond),
+	)
+}
+
+// benchResult holds the measurements of one run.
+type benchResult struct {
+	Encoder    string
+	Sink       string
+	Workload   string
+	Ops        int64
+	NsPerOp    float64
+	AllocsOp   float64
+	Throughput float64 // Records per second including the final drain
+	Dropped    int64
+}
+
+// DropRate returns the fraction of records dropped.
+func (r benchResult) DropRate() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Dropped) / float64(r.Ops)
+}
+
+// benchSink opens a sink for a -sinks entry.
+//
+// Supported specs: "discard" (io.Discard), "devnull" (os.DevNull through
+// the file path), "unixgram:PATH" (datagram socket, must be listening) and
+// any other value as a file path opened for appending.
+func benchSink(spec string) (iris.WriteSyncer, func(), error) {
+	switch {
+	case spec == "discard":
+		return iris.WrapWriter(io.Discard), func() {}, nil
+	case spec == "devnull":
+		spec = os.DevNull
+	case strings.HasPrefix(spec, "unixgram:"):
+		w, err := iris.NewUnixDatagramWriter(strings.TrimPrefix(spec, "unixgram:"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return w, func() { _ = w.Close() }, nil
+	}
+	f, err := os.OpenFile(spec, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return nil, nil, err
+	}
+	return iris.WrapWriter(f), func() { _ = f.Close() }, nil
+}
+
+// benchBaseConfig returns the ring settings used for every run: those of
+// the config file when given, smart defaults otherwise.
+func benchBaseConfig(path string) (iris.Config, error) {
+	if path == "" {
+		return iris.Config{Level: iris.Info}, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		return iris.Config{}, err
+	}
+	c, findings := checkConfig(data)
+	for _, f := range findings {
+		if f.Severity == severityError {
+			return iris.Config{}, fmt.Errorf("%s: %s: %s (run iris-config check)", path, f.Key, f.Message)
+		}
+	}
+	cfg := iris.Config{
+		Capacity:  c.Capacity,
+		BatchSize: c.BatchSize,
+		Inline:    c.Inline,
+		Level:     iris.Info, // Workloads log at info
+	}
+	if containsFold(blockPolicies, c.Policy) {
+		cfg.BackpressurePolicy = zephyroslite.BlockOnFull
+	}
+	if c.Idle != "" {
+		if cfg.IdleStrategy, err = iris.ParseIdleStrategy(c.Idle); err != nil {
+			return iris.Config{}, err
+		}
+	}
+	if c.SampleRate != nil {
+		cfg.Sampler = iris.NewDynamicSampler(*c.SampleRate)
+	}
+	return cfg, nil
+}
+
+// runBench executes one workload for duration and returns its measurements.
+func runBench(base iris.Config, encoder string, sink string, w benchWorkload, duration time.Duration, producers int) (benchResult, error) {
+	out, closeSink, err := benchSink(sink)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer closeSink()
+
+	cfg := base
+	cfg.Encoder = benchEncoders[encoder]()
+	cfg.Output = out
+	logger, err := iris.New(cfg)
+	if err != nil {
+		return benchResult{}, err
+	}
+	logger.Start()
+
+	if !w.parallel {
+		producers = 1
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var ops atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	start := time.Now()
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := 0
+			for !stop.Load() {
+				for j := 0; j < 256; j++ {
+					w.log(logger, n)
+					n++
+				}
+			}
+			ops.Add(int64(n))
+		}()
+	}
+	time.Sleep(duration)
+	stop.Store(true)
+	wg.Wait()
+	produced := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+	closeErr := logger.Close() // Drains the ring
+	total := time.Since(start)
+
+	r := benchResult{
+		Encoder:  encoder,
+		Sink:     sink,
+		Workload: w.name,
+		Ops:      ops.Load(),
+		Dropped:  logger.Stats()["dropped"],
+	}
+	if r.Ops > 0 {
+		// Producer time summed over goroutines, per operation
+		r.NsPerOp = float64(produced.Nanoseconds()) * float64(producers) / float64(r.Ops)
+		r.AllocsOp = float64(after.Mallocs-before.Mallocs) / float64(r.Ops)
+		r.Throughput = float64(r.Ops-r.Dropped) / total.Seconds()
+	}
+	if closeErr != nil {
+		return r, fmt.Errorf("drain: %w", closeErr)
+	}
+	return r, nil
+}
+
+// printBenchResults writes results as an aligned table.
+func printBenchResults(w io.Writer, results []benchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "encoder\tsink\tworkload\tops\tns/op\tallocs/op\trecords/s\tdropped\t")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%.1f\t%.2f\t%.0f\t%.2f%%\t\n",
+			r.Encoder, r.Sink, r.Workload, r.Ops, r.NsPerOp, r.AllocsOp, r.Throughput, r.DropRate()*100)
+	}
+	_ = tw.Flush()
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runBenchCommand implements "iris-config bench".
+func runBenchCommand(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "JSON config whose ring settings are benchmarked (default: smart defaults)")
+	encoders := fs.String("encoders", "json,text,binary", "comma-separated encoders: json, text, console, binary")
+	sinks := fs.String("sinks", "discard", "comma-separated sinks: discard, devnull, unixgram:PATH or a file path")
+	workloads := fs.String("workloads", "simple,fields,parallel", "comma-separated workloads: simple, fields, parallel")
+	duration := fs.Duration("duration", time.Second, "duration of each run")
+	producers := fs.Int("producers", runtime.GOMAXPROCS(0), "goroutines for the parallel workload")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	for _, e := range splitList(*encoders) {
+		if _, ok := benchEncoders[e]; !ok {
+			_, _ = fmt.Fprintf(stderr, "iris-config: unknown encoder %q\n", e)
+			return 2
+		}
+	}
+	var selected []benchWorkload
+	for _, name := range splitList(*workloads) {
+		found := false
+		for _, w := range benchWorkloads {
+			if w.name == name {
+				selected = append(selected, w)
+				found = true
+			}
+		}
+		if !found {
+			_, _ = fmt.Fprintf(stderr, "iris-config: unknown workload %q\n", name)
+			return 2
+		}
+	}
+	if *duration <= 0 || *producers <= 0 {
+		_, _ = fmt.Fprintln(stderr, "iris-config: -duration and -producers must be positive")
+		return 2
+	}
+
+	base, err := benchBaseConfig(*configPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "iris-config: %v\n", err)
+		return 1
+	}
+	eff := iris.EffectiveConfig(base)
+	mode := fmt.Sprintf("capacity %d, batch %d, %s", eff.Capacity, eff.BatchSize, eff.BackpressurePolicy)
+	if eff.Inline {
+		mode = "inline"
+	}
+	_, _ = fmt.Fprintf(stdout, "iris-config bench: %s/%s, %d CPUs, GOMAXPROCS %d, %s, %v per run\n\n",
+		runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), runtime.GOMAXPROCS(0), mode, *duration)
+
+	var results []benchResult
+	status := 0
+	for _, sink := range splitList(*sinks) {
+		for _, e := range splitList(*encoders) {
+			for _, w := range selected {
+				r, err := runBench(base, e, sink, w, *duration, *producers)
+				if err != nil {
+					_, _ = fmt.Fprintf(stderr, "iris-config: %s/%s/%s: %v\n", e, sink, w.name, err)
+					status = 1
+					if r.Ops == 0 {
+						continue
+					}
+				}
+				results = append(results, r)
+			}
+		}
+	}
+	printBenchResults(stdout, results)
+	return status
+}
@@ -0,0 +1,136 @@
+// bench_test.go: Tests for the benchmarking subcommand
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+func TestBenchSink(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bench.log")
+
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"discard", false},
+		{"devnull", false},
+		{file, false},
+		{filepath.Join(dir, "missing", "bench.log"), true},
+		{"unixgram:" + filepath.Join(dir, "nobody.sock"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			w, closeSink, err := benchSink(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("benchSink(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer closeSink()
+			if _, err := w.Write([]byte("x\n")); err != nil {
+				t.Errorf("Write failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestBenchBaseConfig(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"capacity": 2048, "batch_size": 64, "backpressure_policy": "block", "idle_strategy": "yielding", "output": "/nonexistent/ignored.log"}`), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"capacity": 1000}`), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, err := benchBaseConfig(good); err == nil {
+		t.Fatal("expected the missing output directory to be reported")
+	}
+
+	if err := os.WriteFile(good, []byte(`{"capacity": 2048, "batch_size": 64, "backpressure_policy": "block", "idle_strategy": "yielding"}`), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	cfg, err := benchBaseConfig(good)
+	if err != nil {
+		t.Fatalf("benchBaseConfig failed: %v", err)
+	}
+	if cfg.Capacity != 2048 || cfg.BatchSize != 64 || cfg.BackpressurePolicy != zephyroslite.BlockOnFull {
+		t.Errorf("unexpected config: capacity=%d batch=%d policy=%v", cfg.Capacity, cfg.BatchSize, cfg.BackpressurePolicy)
+	}
+	if cfg.IdleStrategy == nil || cfg.IdleStrategy.String() != "yielding" {
+		t.Errorf("unexpected idle strategy %v", cfg.IdleStrategy)
+	}
+
+	if _, err := benchBaseConfig(bad); err == nil || !strings.Contains(err.Error(), "power of two") {
+		t.Errorf("expected config error, got %v", err)
+	}
+	if _, err := benchBaseConfig(""); err != nil {
+		t.Errorf("defaults failed: %v", err)
+	}
+}
+
+func TestRunBench_NoDropsWithBlockOnFull(t *testing.T) {
+	base, err := benchBaseConfig("")
+	if err != nil {
+		t.Fatalf("benchBaseConfig failed: %v", err)
+	}
+	base.Capacity = 1024
+	base.BackpressurePolicy = zephyroslite.BlockOnFull
+
+	r, err := runBench(base, "json", "discard", benchWorkloads[1], 20*time.Millisecond, 2)
+	if err != nil {
+		t.Fatalf("runBench failed: %v", err)
+	}
+	if r.Ops == 0 || r.NsPerOp <= 0 || r.Throughput <= 0 {
+		t.Errorf("implausible result: %+v", r)
+	}
+	if r.Dropped != 0 || r.DropRate() != 0 {
+		t.Errorf("BlockOnFull dropped %d records", r.Dropped)
+	}
+}
+
+func TestRunBenchCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"run", []string{"bench", "-duration", "20ms", "-encoders", "json,binary", "-workloads", "simple,parallel", "-producers", "2"}, 0},
+		{"bad encoder", []string{"bench", "-encoders", "yaml"}, 2},
+		{"bad workload", []string{"bench", "-workloads", "burst"}, 2},
+		{"bad duration", []string{"bench", "-duration", "0s"}, 2},
+		{"bad sink", []string{"bench", "-duration", "10ms", "-workloads", "simple", "-sinks", filepath.Join(t.TempDir(), "no", "dir.log")}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, nil, &stdout, &stderr); got != tt.want {
+				t.Fatalf("exit = %d, want %d\nstdout: %s\nstderr: %s", got, tt.want, stdout.String(), stderr.String())
+			}
+			if tt.want == 0 {
+				out := stdout.String()
+				for _, want := range []string{"ns/op", "allocs/op", "json", "binary", "parallel"} {
+					if !strings.Contains(out, want) {
+						t.Errorf("report missing %q:\n%s", want, out)
+					}
+				}
+			}
+		})
+	}
+}
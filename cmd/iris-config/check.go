@@ -0,0 +1,563 @@
+// check.go: "iris-config check" lints a JSON configuration
+//
+// iris.LoadConfigFromJSON is deliberately forgiving: unknown keys are
+// ignored and unknown values fall back to defaults. check is the strict
+// counterpart for CI. It validates every key against the loader's schema,
+// flags combinations that are legal but dangerous in production, and prints
+// the configuration iris.New would actually run with on this host.
+//
+// Exit status: 0 when clean, 1 on errors (or warnings with -strict), 2 on
+// usage errors.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	goerrors "github.com/agilira/go-errors"
+	"github.com/agilira/iris"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// Finding severities.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// finding is one problem reported by check.
+type finding struct {
+	Severity string
+	Key      string
+	Message  string
+}
+
+// Value kinds in the config schema.
+const (
+	kindString = "string"
+	kindInt    = "integer"
+	kindBool   = "boolean"
+	kindNumber = "number"
+	kindObject = "object"
+)
+
+// configSchema lists the keys understood by iris.LoadConfigFromJSON.
+var configSchema = map[string]string{
+	"level":               kindString,
+	"format":              kindString,
+	"output":              kindString,
+	"capacity":            kindInt,
+	"batch_size":          kindInt,
+	"enable_caller":       kindBool,
+	"development":         kindBool,
+	"name":                kindString,
+	"backpressure_policy": kindString,
+	"idle_strategy":       kindString,
+	"inline":              kindBool,
+	"sample_rate":         kindNumber,
+	"console_theme":       kindString,
+	"console_timezone":    kindString,
+	"fields":              kindObject,
+	"pipeline":            kindObject,
+}
+
+// Accepted spellings for enumerated values (mirrors the loader).
+var (
+	validFormats          = []string{"json", "text", "console", "gelf"}
+	validPolicies         = []string{"drop", "drop_on_full", "droponful", "block", "block_on_full", "blockonful"}
+	validIdleStrategies   = []string{"spinning", "sleeping", "yielding", "channel", "progressive", "balanced", "efficient", "hybrid"}
+	blockPolicies         = []string{"block", "block_on_full", "blockonful"}
+	ringOnlyKeys          = []string{"capacity", "batch_size", "backpressure_policy", "idle_strategy"}
+	smallBlockingCapacity = int64(1024)
+)
+
+// checkedConfig holds the decoded values of a config file.
+type checkedConfig struct {
+	raw        map[string]json.RawMessage
+	Level      string
+	Format     string
+	Output     string
+	Name       string
+	Policy     string
+	Idle       string
+	Capacity   int64
+	BatchSize  int64
+	Inline     bool
+	SampleRate *float64
+	Theme      string
+	TimeZone   string
+	Fields     map[string]interface{}
+	Pipeline   *iris.PipelineConfig
+}
+
+// has reports whether key is present in the file.
+func (c *checkedConfig) has(key string) bool {
+	_, ok := c.raw[key]
+	return ok
+}
+
+// checkConfig validates data and returns the decoded config and findings.
+// A nil config means the file could not be parsed at all.
+func checkConfig(data []byte) (*checkedConfig, []finding) {
+	c := &checkedConfig{}
+	if err := json.Unmarshal(data, &c.raw); err != nil {
+		return nil, []finding{{severityError, "", syntaxMessage(data, err)}}
+	}
+
+	var findings []finding
+	report := func(severity, key, format string, args ...interface{}) {
+		findings = append(findings, finding{severity, key, fmt.Sprintf(format, args...)})
+	}
+
+	keys := make([]string, 0, len(c.raw))
+	for key := range c.raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "//") {
+			continue // Comment entries written by iris-config init
+		}
+		kind, known := configSchema[key]
+		if !known {
+			if suggestion := suggestKey(key); suggestion != "" {
+				report(severityError, key, "unknown key (did you mean %q?); the loader ignores it", suggestion)
+			} else {
+				report(severityError, key, "unknown key; the loader ignores it")
+			}
+			continue
+		}
+		if err := decodeKind(c.raw[key], kind, c, key); err != nil {
+			report(severityError, key, "must be a JSON %s", kind)
+		}
+	}
+
+	// Individual values
+	if c.has("level") {
+		if _, err := iris.ParseLevel(c.Level); err != nil {
+			report(severityError, "level", "unknown level %q; the loader falls back to info", c.Level)
+		}
+	}
+	// Encoders registered by the application are unknown to this tool
+	if c.has("format") && !containsFold(validFormats, c.Format) {
+		report(severityWarning, "format", "unknown format %q (want %s, or a name the application registers with iris.RegisterEncoder); otherwise the loader falls back to json", c.Format, strings.Join(validFormats, ", "))
+	}
+	if c.has("console_theme") {
+		if _, err := iris.ParseConsoleTheme(c.Theme); err != nil {
+			report(severityError, "console_theme", "unknown theme %q (want %s); the loader keeps the default colors", c.Theme, strings.Join(iris.ConsoleThemeNames(), ", "))
+		} else if !strings.EqualFold(c.Format, "console") {
+			report(severityWarning, "console_theme", "ignored unless format is console")
+		}
+	}
+	if c.has("console_timezone") {
+		if _, err := iris.ParseConsoleTimeZone(c.TimeZone); err != nil {
+			report(severityError, "console_timezone", "unknown time zone %q (want local, UTC, an IANA name or an offset such as +05:30); the loader keeps the clock's zone", c.TimeZone)
+		} else if !strings.EqualFold(c.Format, "console") {
+			report(severityWarning, "console_timezone", "ignored unless format is console")
+		}
+	}
+	if c.has("backpressure_policy") && !containsFold(validPolicies, c.Policy) {
+		report(severityError, "backpressure_policy", "unknown policy %q; the loader falls back to drop_on_full", c.Policy)
+	}
+	if c.has("idle_strategy") && !containsFold(validIdleStrategies, c.Idle) {
+		report(severityError, "idle_strategy", "unknown strategy %q; the loader falls back to balanced", c.Idle)
+	}
+	if c.has("capacity") {
+		if c.Capacity <= 0 || c.Capacity&(c.Capacity-1) != 0 {
+			report(severityError, "capacity", "%d is not a positive power of two; iris.New rejects it", c.Capacity)
+		}
+	}
+	if c.has("batch_size") && c.BatchSize <= 0 {
+		report(severityError, "batch_size", "must be positive; the loader ignores %d", c.BatchSize)
+	}
+	if c.SampleRate != nil {
+		switch {
+		case *c.SampleRate < 0 || *c.SampleRate > 1:
+			report(severityError, "sample_rate", "%v is outside [0, 1]", *c.SampleRate)
+		case *c.SampleRate == 0:
+			report(severityWarning, "sample_rate", "0 discards every record the sampler sees")
+		}
+	}
+	findings = append(findings, checkFields(c.Fields)...)
+	if c.Pipeline != nil {
+		if err := c.Pipeline.Validate(); err != nil {
+			var ierr *goerrors.Error
+			if errors.As(err, &ierr) {
+				report(severityError, ierr.Field, "%s; the loader rejects the file", strings.TrimPrefix(ierr.Message, ierr.Field+": "))
+			} else {
+				report(severityError, "pipeline", "%v", err)
+			}
+		}
+	}
+	findings = append(findings, checkOutput(c.Output)...)
+	if isGELFOutput(c.Output) && c.has("format") && !strings.EqualFold(c.Format, "gelf") {
+		report(severityWarning, "format", "%s output with format %q; Graylog GELF inputs expect format gelf", c.Output, c.Format)
+	}
+	if isLokiOutput(c.Output) && c.has("format") && !strings.EqualFold(c.Format, "json") {
+		if cfg, err := iris.ParseLokiURL(c.Output); err == nil && len(cfg.LabelFields) > 0 {
+			report(severityWarning, "format", "%s output with format %q; label_fields are only read from json records", c.Output, c.Format)
+		}
+	}
+
+	for _, key := range []string{"enable_caller", "development"} {
+		if c.has(key) {
+			report(severityWarning, key, "not applied by LoadConfigFromJSON; pass iris.WithCaller() or iris.Development() to New")
+		}
+	}
+
+	// Combinations
+	eff := c.effective()
+	if c.Inline {
+		for _, key := range ringOnlyKeys {
+			if c.has(key) {
+				report(severityWarning, key, "ignored because inline is true")
+			}
+		}
+		return c, findings
+	}
+	if eff.Capacity > 0 && eff.BatchSize > eff.Capacity {
+		report(severityError, "batch_size", "%d exceeds capacity %d; iris.New rejects it", eff.BatchSize, eff.Capacity)
+	}
+	if containsFold(blockPolicies, c.Policy) && eff.Capacity < smallBlockingCapacity {
+		if isFileOutput(c.Output) {
+			report(severityError, "backpressure_policy",
+				"block_on_full with a %d-slot ring and a file output: a slow or stalled disk blocks every logging call", eff.Capacity)
+		} else {
+			report(severityWarning, "backpressure_policy",
+				"block_on_full with a %d-slot ring: a slow output blocks logging calls after %d records", eff.Capacity, eff.Capacity)
+		}
+	}
+	return c, findings
+}
+
+// decodeKind unmarshals raw into the field for key, enforcing its kind.
+func decodeKind(raw json.RawMessage, kind string, c *checkedConfig, key string) error {
+	var target interface{}
+	switch key {
+	case "level":
+		target = &c.Level
+	case "format":
+		target = &c.Format
+	case "output":
+		target = &c.Output
+	case "name":
+		target = &c.Name
+	case "backpressure_policy":
+		target = &c.Policy
+	case "idle_strategy":
+		target = &c.Idle
+	case "capacity":
+		target = &c.Capacity
+	case "batch_size":
+		target = &c.BatchSize
+	case "inline":
+		target = &c.Inline
+	case "sample_rate":
+		target = &c.SampleRate
+	case "console_theme":
+		target = &c.Theme
+	case "console_timezone":
+		target = &c.TimeZone
+	case "fields":
+		target = &c.Fields
+	case "pipeline":
+		target = &c.Pipeline
+	default:
+		var ignored interface{}
+		target = &ignored
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var probe interface{}
+	if err := dec.Decode(&probe); err != nil {
+		return err
+	}
+	switch v := probe.(type) {
+	case string:
+		if kind != kindString {
+			return errors.New("type mismatch")
+		}
+	case bool:
+		if kind != kindBool {
+			return errors.New("type mismatch")
+		}
+	case map[string]interface{}:
+		if kind != kindObject {
+			return errors.New("type mismatch")
+		}
+	case json.Number:
+		if kind == kindInt {
+			if _, err := v.Int64(); err != nil {
+				return err
+			}
+		} else if kind != kindNumber {
+			return errors.New("type mismatch")
+		}
+	default:
+		return errors.New("type mismatch")
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// checkFields validates the static fields; the loader accepts only scalar
+// values and rejects the whole file otherwise.
+func checkFields(fields map[string]interface{}) []finding {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var findings []finding
+	for _, key := range keys {
+		switch fields[key].(type) {
+		case string, bool, float64:
+			if key == "" {
+				findings = append(findings, finding{severityError, "fields", "empty field key; the loader rejects the file"})
+			}
+		default:
+			findings = append(findings, finding{severityError, "fields." + key,
+				"must be a string, number or boolean; the loader rejects the file"})
+		}
+	}
+	return findings
+}
+
+// checkOutput validates the output destination.
+func checkOutput(output string) []finding {
+	if isGELFOutput(output) {
+		if _, err := iris.ParseGELFURL(output); err != nil {
+			return []finding{{severityError, "output", errorMessage(err) + "; the loader rejects the file"}}
+		}
+		return nil
+	}
+	if isLokiOutput(output) {
+		if _, err := iris.ParseLokiURL(output); err != nil {
+			return []finding{{severityError, "output", errorMessage(err) + "; the loader rejects the file"}}
+		}
+		return nil
+	}
+	if !isFileOutput(output) {
+		return nil
+	}
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		return []finding{{severityError, "output", output + " is a directory"}}
+	}
+	dir := filepath.Dir(output)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return []finding{{severityError, "output", "directory " + dir + " does not exist"}}
+	}
+	return nil
+}
+
+// isFileOutput reports whether output names a file rather than a stream.
+func isFileOutput(output string) bool {
+	switch strings.ToLower(output) {
+	case "", "stdout", "stderr", iris.OutputStdSplit:
+		return false
+	}
+	return !isGELFOutput(output) && !isLokiOutput(output)
+}
+
+// isGELFOutput reports whether output is a Graylog URL (gelf://...).
+func isGELFOutput(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.HasPrefix(lower, "gelf://") || strings.HasPrefix(lower, "gelf+")
+}
+
+// isLokiOutput reports whether output is a Loki URL (loki://...).
+func isLokiOutput(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.HasPrefix(lower, "loki://") || strings.HasPrefix(lower, "loki+")
+}
+
+// errorMessage returns the message of an Iris error without its code.
+func errorMessage(err error) string {
+	var ierr *goerrors.Error
+	if errors.As(err, &ierr) {
+		return ierr.Message
+	}
+	return err.Error()
+}
+
+// effective resolves the configuration iris.New would use on this host.
+func (c *checkedConfig) effective() iris.Config {
+	cfg := iris.Config{
+		Capacity:  c.Capacity,
+		BatchSize: c.BatchSize,
+		Name:      c.Name,
+		Inline:    c.Inline,
+	}
+	if c.Capacity < 0 {
+		cfg.Capacity = 0
+	}
+	if level, err := iris.ParseLevel(c.Level); err == nil {
+		cfg.Level = level
+	}
+	if containsFold(blockPolicies, c.Policy) {
+		cfg.BackpressurePolicy = zephyroslite.BlockOnFull
+	}
+	return iris.EffectiveConfig(cfg)
+}
+
+// printEffective writes the resolved configuration as an aligned table.
+func printEffective(w io.Writer, c *checkedConfig) {
+	eff := c.effective()
+	format := "json"
+	if isGELFOutput(c.Output) {
+		format = "gelf"
+	}
+	rows := [][2]string{
+		{"level", eff.Level.String()},
+		{"format", orDefault(strings.ToLower(c.Format), format)},
+		{"output", orDefault(c.Output, "stdout")},
+		{"name", orDefault(eff.Name, "(none)")},
+	}
+	if eff.Inline {
+		rows = append(rows, [2]string{"inline", "true (no ring buffer)"})
+	} else {
+		idle := strings.ToLower(c.Idle)
+		if idle == "" {
+			idle = eff.IdleStrategy.String() + " (default)"
+		}
+		rows = append(rows,
+			[2]string{"capacity", fmt.Sprint(eff.Capacity)},
+			[2]string{"batch_size", fmt.Sprint(eff.BatchSize)},
+			[2]string{"backpressure_policy", eff.BackpressurePolicy.String()},
+			[2]string{"idle_strategy", idle},
+		)
+	}
+	sampling := "off"
+	if c.SampleRate != nil {
+		sampling = fmt.Sprintf("keep %.4g of records", *c.SampleRate)
+	}
+	rows = append(rows, [2]string{"sampling", sampling})
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(c.raw["fields"], &fields) == nil && len(fields) > 0 {
+		pairs := make([]string, 0, len(fields))
+		for key, value := range fields {
+			pairs = append(pairs, key+"="+string(value))
+		}
+		sort.Strings(pairs)
+		rows = append(rows, [2]string{"fields", strings.Join(pairs, " ")})
+	}
+
+	_, _ = fmt.Fprintln(w, "Effective configuration:")
+	for _, row := range rows {
+		_, _ = fmt.Fprintf(w, "  %-20s %s\n", row[0], row[1])
+	}
+}
+
+// runCheck implements "iris-config check".
+func runCheck(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	strict := fs.Bool("strict", false, "treat warnings as errors")
+	quiet := fs.Bool("q", false, "only print findings, not the effective configuration")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: iris-config check [-strict] [-q] config.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path supplied by the operator
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "iris-config: %v\n", err)
+		return 1
+	}
+
+	c, findings := checkConfig(data)
+	errorsFound, warnings := 0, 0
+	for _, f := range findings {
+		if f.Severity == severityError {
+			errorsFound++
+		} else {
+			warnings++
+		}
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s: %d error(s), %d warning(s)\n", path, errorsFound, warnings)
+	for _, f := range findings {
+		if f.Key != "" {
+			_, _ = fmt.Fprintf(stdout, "  %-7s %s: %s\n", f.Severity, f.Key, f.Message)
+		} else {
+			_, _ = fmt.Fprintf(stdout, "  %-7s %s\n", f.Severity, f.Message)
+		}
+	}
+	if c != nil && !*quiet {
+		_, _ = fmt.Fprintln(stdout)
+		printEffective(stdout, c)
+	}
+
+	if errorsFound > 0 || (*strict && warnings > 0) {
+		return 1
+	}
+	return 0
+}
+
+// syntaxMessage formats a JSON decoding error with its line and column.
+func syntaxMessage(data []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return "invalid JSON: " + err.Error()
+	}
+	before := data[:syntaxErr.Offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, col, err)
+}
+
+// suggestKey returns the schema key closest to key, if one is plausibly meant.
+func suggestKey(key string) string {
+	normalized := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(key))
+	if _, ok := configSchema[normalized]; ok {
+		return normalized
+	}
+	compact := strings.ReplaceAll(normalized, "_", "")
+	for candidate := range configSchema {
+		if strings.ReplaceAll(candidate, "_", "") == compact {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// orDefault returns s, or def when s is empty.
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
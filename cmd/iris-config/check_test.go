@@ -0,0 +1,171 @@
+// check_test.go: Tests for config linting
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// hasFinding reports whether findings contains severity for key with msg.
+func hasFinding(findings []finding, severity, key, msg string) bool {
+	for _, f := range findings {
+		if f.Severity == severity && f.Key == key && strings.Contains(f.Message, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+
+	tests := []struct {
+		name     string
+		json     string
+		severity string
+		key      string
+		msg      string
+	}{
+		{"syntax", `{"level": "info",}`, severityError, "", "line 1, column 18"},
+		{"unknown key", `{"levle": "info"}`, severityError, "levle", "unknown key"},
+		{"suggestion", `{"batchSize": 64}`, severityError, "batchSize", `did you mean "batch_size"`},
+		{"wrong type", `{"capacity": "1024"}`, severityError, "capacity", "must be a JSON integer"},
+		{"fractional int", `{"capacity": 10.5}`, severityError, "capacity", "must be a JSON integer"},
+		{"bad level", `{"level": "verbose"}`, severityError, "level", "falls back to info"},
+		{"bad format", `{"format": "xml"}`, severityWarning, "format", "unknown format"},
+		{"bad gelf url", `{"output": "gelf://graylog.example?compress=lz4"}`, severityError, "output", "unknown GELF compression"},
+		{"gelf output format", `{"format": "json", "output": "gelf://graylog.example"}`, severityWarning, "format", "expect format gelf"},
+		{"bad loki url", `{"output": "loki://loki.example?labels=job"}`, severityError, "output", "name=value pairs"},
+		{"loki label fields format", `{"format": "text", "output": "loki://loki.example?label_fields=level"}`, severityWarning, "format", "only read from json"},
+		{"bad theme", `{"format": "console", "console_theme": "sepia"}`, severityError, "console_theme", "unknown theme"},
+		{"theme without console", `{"format": "json", "console_theme": "light"}`, severityWarning, "console_theme", "ignored unless"},
+		{"bad timezone", `{"format": "console", "console_timezone": "Mars/Olympus"}`, severityError, "console_timezone", "unknown time zone"},
+		{"timezone without console", `{"format": "text", "console_timezone": "+05:30"}`, severityWarning, "console_timezone", "ignored unless"},
+		{"bad policy", `{"backpressure_policy": "wait"}`, severityError, "backpressure_policy", "unknown policy"},
+		{"bad idle", `{"idle_strategy": "lazy"}`, severityError, "idle_strategy", "unknown strategy"},
+		{"capacity not pow2", `{"capacity": 1000}`, severityError, "capacity", "power of two"},
+		{"batch over capacity", `{"capacity": 1024, "batch_size": 2048}`, severityError, "batch_size", "exceeds capacity"},
+		{"sample rate range", `{"sample_rate": 1.5}`, severityError, "sample_rate", "outside"},
+		{"sample rate zero", `{"sample_rate": 0}`, severityWarning, "sample_rate", "discards"},
+		{"missing dir", `{"output": "` + filepath.ToSlash(filepath.Join(dir, "nope", "app.log")) + `"}`, severityError, "output", "does not exist"},
+		{"output is dir", `{"output": "` + filepath.ToSlash(dir) + `"}`, severityError, "output", "is a directory"},
+		{"caller ignored", `{"enable_caller": true}`, severityWarning, "enable_caller", "not applied"},
+		{"inline ignores ring", `{"inline": true, "capacity": 1024}`, severityWarning, "capacity", "ignored because inline"},
+		{"fields not object", `{"fields": ["service"]}`, severityError, "fields", "must be a JSON object"},
+		{"fields nested", `{"fields": {"owner": {"team": "billing"}}}`, severityError, "fields.owner", "must be a string, number or boolean"},
+		{"pipeline not object", `{"pipeline": []}`, severityError, "pipeline", "must be a JSON object"},
+		{"pipeline bad processor", `{"pipeline": {"processors": [{"type": "dedupe", "window": "soon"}]}}`,
+			severityError, "pipeline.processors[0].window", "positive duration"},
+		{"pipeline undeclared sink", `{"pipeline": {"processors": [{"type": "route", "sink": "alerts"}]}}`,
+			severityError, "pipeline.processors[0].sink", `undeclared sink "alerts"`},
+		{"block tiny", `{"backpressure_policy": "block", "capacity": 256}`, severityWarning, "backpressure_policy", "256-slot ring"},
+		{"block tiny file", `{"backpressure_policy": "block_on_full", "capacity": 256, "output": "` + filepath.ToSlash(logFile) + `"}`,
+			severityError, "backpressure_policy", "stalled disk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, findings := checkConfig([]byte(tt.json))
+			if !hasFinding(findings, tt.severity, tt.key, tt.msg) {
+				t.Errorf("expected %s on %q containing %q, got %+v", tt.severity, tt.key, tt.msg, findings)
+			}
+		})
+	}
+}
+
+func TestCheckConfig_CleanConfigs(t *testing.T) {
+	tests := []string{
+		`{}`,
+		`{"level": "warn", "format": "text", "output": "stderr", "capacity": 8192, "batch_size": 64,
+		  "backpressure_policy": "drop_on_full", "idle_strategy": "progressive", "sample_rate": 0.5}`,
+		`{"pipeline": {"processors": [{"type": "redact", "keys": ["password"]}, {"type": "route", "level": "error", "sink": "alerts"}],
+		  "sinks": [{"name": "alerts", "output": "stderr"}]}}`,
+		`{"// capacity": "comment entries are allowed", "capacity": 4096, "backpressure_policy": "block"}`,
+		`{"inline": true, "level": "debug"}`,
+		`{"fields": {"service": "payments", "region": "eu-1", "shard": 7, "canary": false}}`,
+	}
+	for _, js := range tests {
+		c, findings := checkConfig([]byte(js))
+		if c == nil || len(findings) != 0 {
+			t.Errorf("expected no findings for %s, got %+v", js, findings)
+		}
+	}
+}
+
+func TestCheckConfig_Effective(t *testing.T) {
+	c, _ := checkConfig([]byte(`{"capacity": 2048, "batch_size": 16, "backpressure_policy": "block", "idle_strategy": "Yielding"}`))
+	var out bytes.Buffer
+	printEffective(&out, c)
+	for _, want := range []string{"capacity             2048", "batch_size           16", "BlockOnFull", "yielding"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("effective configuration missing %q:\n%s", want, out.String())
+		}
+	}
+
+	c, _ = checkConfig([]byte(`{"fields": {"service": "payments", "region": "eu-1"}}`))
+	out.Reset()
+	printEffective(&out, c)
+	if !strings.Contains(out.String(), `fields               region="eu-1" service="payments"`) {
+		t.Errorf("effective configuration missing fields:\n%s", out.String())
+	}
+
+	c, _ = checkConfig([]byte(`{"inline": true}`))
+	out.Reset()
+	printEffective(&out, c)
+	if !strings.Contains(out.String(), "no ring buffer") || strings.Contains(out.String(), "capacity") {
+		t.Errorf("unexpected inline effective configuration:\n%s", out.String())
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		return path
+	}
+	clean := write("clean.json", `{"capacity": 4096}`)
+	warn := write("warn.json", `{"enable_caller": true}`)
+	bad := write("bad.json", `{"capacity": 3}`)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"clean", []string{"check", clean}, 0},
+		{"warning", []string{"check", warn}, 0},
+		{"warning strict", []string{"check", "-strict", warn}, 1},
+		{"error", []string{"check", "-q", bad}, 1},
+		{"missing file", []string{"check", filepath.Join(dir, "missing.json")}, 1},
+		{"no args", []string{"check"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, nil, &stdout, &stderr); got != tt.want {
+				t.Errorf("exit = %d, want %d\nstdout: %s\nstderr: %s", got, tt.want, stdout.String(), stderr.String())
+			}
+		})
+	}
+
+	// A generated config passes its own linter
+	generated := filepath.Join(dir, "generated.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init", "-y", "-o", generated}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("init failed: %s", stderr.String())
+	}
+	if code := run([]string{"check", "-strict", generated}, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("generated config fails check:\n%s", stdout.String())
+	}
+}
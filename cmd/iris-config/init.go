@@ -0,0 +1,377 @@
+// init.go: "iris-config init" generates a tuned JSON configuration
+//
+// The generator reads CPU count, GOMAXPROCS, cgroup CPU and memory limits and
+// free disk space, asks a few questions about the workload and writes a
+// config accepted by iris.LoadConfigFromJSON. Every tuned value is preceded
+// by a "// <key>" entry explaining why it was chosen; the loader ignores
+// these keys, so the comments survive in the deployed file.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"github.com/agilira/iris"
+)
+
+// Workload profiles offered by init.
+const (
+	profileBalanced   = "balanced"
+	profileLatency    = "latency"
+	profileThroughput = "throughput"
+	profileMinimal    = "minimal"
+)
+
+// answers holds the user's choices.
+type answers struct {
+	Profile  string
+	Output   string
+	Format   string
+	Level    string
+	Delivery string // "drop" or "block"
+	Name     string
+}
+
+// defaultAnswers returns the choices used with -y.
+func defaultAnswers() answers {
+	return answers{
+		Profile:  profileBalanced,
+		Output:   "stdout",
+		Format:   "json",
+		Level:    "info",
+		Delivery: "drop",
+	}
+}
+
+// configEntry is one key of the generated config, with an optional comment.
+type configEntry struct {
+	Key     string
+	Value   interface{}
+	Comment string
+}
+
+// recordSlotSize is the memory used by one ring buffer slot.
+var recordSlotSize = uint64(unsafe.Sizeof(iris.Record{}))
+
+// Capacity bounds for generated configs.
+const (
+	minGeneratedCapacity = 1024
+	maxGeneratedCapacity = 262144
+	defaultRingBudget    = 256 << 20 // Ring memory cap without a container limit
+)
+
+// recommend derives the config entries for host and the user's answers.
+func recommend(host hostInfo, a answers, freeDisk uint64, haveDisk bool) []configEntry {
+	cpus := host.EffectiveCPUs()
+	entries := []configEntry{
+		{Key: "level", Value: a.Level},
+		{Key: "format", Value: a.Format},
+		{Key: "output", Value: a.Output},
+	}
+	if a.Name != "" {
+		entries = append(entries, configEntry{Key: "name", Value: a.Name})
+	}
+
+	if cpus == 1 || host.OS == "js" {
+		entries = append(entries, configEntry{
+			Key:   "inline",
+			Value: true,
+			Comment: "a single usable CPU: records are encoded in the calling goroutine, " +
+				"no ring buffer or consumer goroutine competes for the core",
+		})
+		return appendRotationNote(entries, a.Output, freeDisk, haveDisk)
+	}
+
+	capacity, capNote := recommendCapacity(host, a.Profile, cpus)
+	entries = append(entries,
+		configEntry{Key: "capacity", Value: capacity, Comment: capNote},
+		configEntry{Key: "batch_size", Value: recommendBatchSize(a.Profile, capacity),
+			Comment: "records drained per consumer pass: larger batches raise throughput, smaller ones cut tail latency"},
+	)
+
+	policy, policyNote := "drop_on_full", "callers never wait; records are dropped (and counted in Stats) when the ring is full"
+	if a.Delivery == "block" {
+		policy, policyNote = "block_on_full", "callers wait when the ring is full: the output must keep up with peak load"
+	}
+	entries = append(entries, configEntry{Key: "backpressure_policy", Value: policy, Comment: policyNote})
+
+	idle, idleNote := recommendIdleStrategy(a.Profile, cpus)
+	entries = append(entries, configEntry{Key: "idle_strategy", Value: idle, Comment: idleNote})
+
+	return appendRotationNote(entries, a.Output, freeDisk, haveDisk)
+}
+
+// appendRotationNote adds the rotation advice for file outputs.
+func appendRotationNote(entries []configEntry, output string, freeDisk uint64, haveDisk bool) []configEntry {
+	if note := rotationNote(output, freeDisk, haveDisk); note != "" {
+		entries = append(entries, configEntry{Key: "rotation", Comment: note})
+	}
+	return entries
+}
+
+// recommendCapacity sizes the ring for the profile and usable CPUs, then
+// halves it until it fits the memory budget.
+func recommendCapacity(host hostInfo, profile string, cpus int) (int64, string) {
+	perCPU := map[string]int64{
+		profileLatency:    4096,
+		profileBalanced:   8192,
+		profileThroughput: 16384,
+		profileMinimal:    1024,
+	}[profile]
+	if perCPU == 0 {
+		perCPU = 8192
+	}
+	capacity := nextPowerOfTwo(perCPU * int64(cpus))
+	if capacity < minGeneratedCapacity {
+		capacity = minGeneratedCapacity
+	}
+	if capacity > maxGeneratedCapacity {
+		capacity = maxGeneratedCapacity
+	}
+
+	budget, budgetNote := uint64(defaultRingBudget), "no memory limit detected"
+	if host.MemLimit > 0 {
+		budget = host.MemLimit / 32
+		budgetNote = "container memory limit " + formatBytes(host.MemLimit)
+	}
+	// #nosec G115 - capacity is positive and bounded
+	for capacity > minGeneratedCapacity && uint64(capacity)*recordSlotSize > budget {
+		capacity /= 2
+	}
+	// #nosec G115 - capacity is positive and bounded
+	note := fmt.Sprintf("%s profile on %d usable CPUs; ring uses %s (%s)",
+		profile, cpus, formatBytes(uint64(capacity)*recordSlotSize), budgetNote)
+	return capacity, note
+}
+
+// recommendBatchSize picks the consumer batch size for the profile.
+func recommendBatchSize(profile string, capacity int64) int64 {
+	batch := map[string]int64{
+		profileLatency:    32,
+		profileBalanced:   128,
+		profileThroughput: 512,
+		profileMinimal:    64,
+	}[profile]
+	if batch == 0 {
+		batch = 128
+	}
+	if batch > capacity {
+		batch = capacity
+	}
+	return batch
+}
+
+// recommendIdleStrategy picks how the consumer waits for records.
+func recommendIdleStrategy(profile string, cpus int) (string, string) {
+	switch profile {
+	case profileLatency:
+		if cpus >= 4 {
+			return "spinning", "lowest latency; dedicates one of the usable CPUs to the consumer"
+		}
+		return "yielding", "low latency without pinning a core on a small CPU budget"
+	case profileThroughput:
+		return "hybrid", "spins briefly under load, then backs off"
+	case profileMinimal:
+		return "efficient", "minimal CPU use while idle at the cost of wake-up latency"
+	default:
+		return "progressive", "adapts between spinning and sleeping to the current load"
+	}
+}
+
+// rotationNote advises on file rotation for file outputs.
+func rotationNote(output string, freeDisk uint64, haveDisk bool) string {
+	switch strings.ToLower(output) {
+	case "", "stdout", "stderr", iris.OutputStdSplit:
+		return ""
+	}
+	dir := filepath.Dir(output)
+	if !haveDisk {
+		return "iris appends to " + output + " and does not rotate it: use NewRotatingFileSyncer as the output, " +
+			"configure logrotate (copytruncate) or call SharedFileWriter.Rotate when several processes share the file"
+	}
+	size := freeDisk / 50
+	if size < 10<<20 {
+		size = 10 << 20
+	}
+	if size > 1<<30 {
+		size = 1 << 30
+	}
+	return fmt.Sprintf("iris appends to %s and does not rotate it; with %s free in %s, rotate at %s keeping 10 archives "+
+		"(NewRotatingFileSyncer with MaxSize and MaxBackups, logrotate with copytruncate, "+
+		"or SharedFileWriter.Rotate when several processes share the file)",
+		output, formatBytes(freeDisk), dir, formatBytes(size))
+}
+
+// nextPowerOfTwo returns the smallest power of two >= n (n > 0).
+func nextPowerOfTwo(n int64) int64 {
+	p := int64(1)
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// render writes entries as indented JSON, each comment as a "// key" entry
+// immediately before its key.
+func render(host hostInfo, entries []configEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+
+	header := fmt.Sprintf("generated by iris-config init for %s/%s, %d CPUs, GOMAXPROCS %d",
+		host.OS, host.Arch, host.CPUs, host.GOMAXPROCS)
+	if host.CPUQuota > 0 {
+		header += fmt.Sprintf(", CPU limit %.2f", host.CPUQuota)
+	}
+	if host.MemLimit > 0 {
+		header += ", memory limit " + formatBytes(host.MemLimit)
+	}
+
+	lines := []configEntry{{Key: "//", Value: header}}
+	for _, e := range entries {
+		if e.Comment != "" {
+			lines = append(lines, configEntry{Key: "// " + e.Key, Value: e.Comment})
+		}
+		if e.Value != nil {
+			lines = append(lines, configEntry{Key: e.Key, Value: e.Value})
+		}
+	}
+
+	for i, line := range lines {
+		key, err := json.Marshal(line.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(line.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("  ")
+		buf.Write(key)
+		buf.WriteString(": ")
+		buf.Write(value)
+		if i < len(lines)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// prompter asks questions on an interactive terminal.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// choose asks question until the answer is one of options (or empty, which
+// selects def). Free-form answers are accepted when options is nil.
+func (p *prompter) choose(question string, options []string, def string) string {
+	for attempt := 0; attempt < 3; attempt++ {
+		if options != nil {
+			_, _ = fmt.Fprintf(p.out, "%s (%s) [%s]: ", question, strings.Join(options, "/"), def)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		}
+		line, err := p.in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return def
+		}
+		if options == nil {
+			return answer
+		}
+		for _, o := range options {
+			if strings.EqualFold(answer, o) {
+				return o
+			}
+		}
+		if err != nil {
+			break // EOF after an invalid answer
+		}
+		_, _ = fmt.Fprintf(p.out, "  please answer one of: %s\n", strings.Join(options, ", "))
+	}
+	return def
+}
+
+// ask collects answers interactively, starting from defaults.
+func (p *prompter) ask(host hostInfo) answers {
+	a := defaultAnswers()
+	_, _ = fmt.Fprintf(p.out, "Detected %d CPUs (%d usable)", host.CPUs, host.EffectiveCPUs())
+	if host.MemLimit > 0 {
+		_, _ = fmt.Fprintf(p.out, ", memory limit %s", formatBytes(host.MemLimit))
+	}
+	_, _ = fmt.Fprintln(p.out)
+
+	a.Profile = p.choose("Workload profile", []string{profileBalanced, profileLatency, profileThroughput, profileMinimal}, a.Profile)
+	a.Output = p.choose("Output (stdout, stderr, std-split or a file path)", nil, a.Output)
+	a.Format = p.choose("Format", []string{"json", "text"}, a.Format)
+	a.Level = p.choose("Minimum level", []string{"debug", "info", "warn", "error"}, a.Level)
+	a.Delivery = p.choose("When the buffer is full", []string{"drop", "block"}, a.Delivery)
+	a.Name = p.choose("Logger name (optional)", nil, a.Name)
+	return a
+}
+
+// runInit implements "iris-config init".
+func runInit(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	outPath := fs.String("o", "", "write the config to this file instead of stdout")
+	assumeYes := fs.Bool("y", false, "accept all defaults without prompting")
+	force := fs.Bool("force", false, "overwrite an existing output file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *outPath != "" && !*force {
+		if _, err := os.Stat(*outPath); err == nil {
+			_, _ = fmt.Fprintf(stderr, "iris-config: %s already exists (use -force to overwrite)\n", *outPath)
+			return 1
+		}
+	}
+
+	host := probeHost()
+	a := defaultAnswers()
+	if !*assumeYes {
+		p := &prompter{in: bufio.NewReader(stdin), out: stderr}
+		a = p.ask(host)
+	}
+
+	var freeDisk uint64
+	var haveDisk bool
+	switch strings.ToLower(a.Output) {
+	case "stdout", "stderr", iris.OutputStdSplit:
+	default:
+		freeDisk, haveDisk = diskFree(filepath.Dir(a.Output))
+	}
+
+	data, err := render(host, recommend(host, a, freeDisk, haveDisk))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "iris-config: %v\n", err)
+		return 1
+	}
+
+	if *outPath == "" {
+		_, _ = stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*outPath, data, 0600); err != nil {
+		_, _ = fmt.Fprintf(stderr, "iris-config: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stderr, "wrote %s\n", *outPath)
+	return 0
+}
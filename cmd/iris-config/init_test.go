@@ -0,0 +1,218 @@
+// init_test.go: Tests for config generation
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agilira/iris"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// entryValue returns the value generated for key, or nil.
+func entryValue(entries []configEntry, key string) interface{} {
+	for _, e := range entries {
+		if e.Key == key {
+			return e.Value
+		}
+	}
+	return nil
+}
+
+func TestHostInfo_EffectiveCPUs(t *testing.T) {
+	tests := []struct {
+		name string
+		host hostInfo
+		want int
+	}{
+		{"unlimited", hostInfo{CPUs: 8, GOMAXPROCS: 8}, 8},
+		{"gomaxprocs", hostInfo{CPUs: 8, GOMAXPROCS: 2}, 2},
+		{"fractional quota", hostInfo{CPUs: 8, GOMAXPROCS: 8, CPUQuota: 1.5}, 2},
+		{"quota above cpus", hostInfo{CPUs: 4, GOMAXPROCS: 4, CPUQuota: 16}, 4},
+		{"zero", hostInfo{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.host.EffectiveCPUs(); got != tt.want {
+				t.Errorf("EffectiveCPUs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaCores(t *testing.T) {
+	tests := []struct {
+		quota, period string
+		want          float64
+	}{
+		{"200000", "100000", 2},
+		{"50000", "100000", 0.5},
+		{"max", "100000", 0},
+		{"-1", "100000", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if got := quotaCores(tt.quota, tt.period); got != tt.want {
+			t.Errorf("quotaCores(%q, %q) = %v, want %v", tt.quota, tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	host := hostInfo{CPUs: 8, GOMAXPROCS: 8, OS: "linux", Arch: "amd64"}
+
+	tests := []struct {
+		name     string
+		host     hostInfo
+		answers  answers
+		capacity interface{}
+		idle     interface{}
+		policy   interface{}
+		inline   interface{}
+	}{
+		{"balanced", host, defaultAnswers(), int64(65536), "progressive", "drop_on_full", nil},
+		{"latency", host, answers{Profile: profileLatency, Delivery: "drop"}, int64(32768), "spinning", "drop_on_full", nil},
+		{"throughput block", host, answers{Profile: profileThroughput, Delivery: "block"}, int64(65536), "hybrid", "block_on_full", nil},
+		{"latency small", hostInfo{CPUs: 2, GOMAXPROCS: 2}, answers{Profile: profileLatency}, int64(8192), "yielding", "drop_on_full", nil},
+		{"single cpu", hostInfo{CPUs: 4, GOMAXPROCS: 1}, defaultAnswers(), nil, nil, nil, true},
+		{"wasm", hostInfo{CPUs: 4, GOMAXPROCS: 4, OS: "js"}, defaultAnswers(), nil, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := recommend(tt.host, tt.answers, 0, false)
+			if got := entryValue(entries, "capacity"); got != tt.capacity {
+				t.Errorf("capacity = %v, want %v", got, tt.capacity)
+			}
+			if got := entryValue(entries, "idle_strategy"); got != tt.idle {
+				t.Errorf("idle_strategy = %v, want %v", got, tt.idle)
+			}
+			if got := entryValue(entries, "backpressure_policy"); got != tt.policy {
+				t.Errorf("backpressure_policy = %v, want %v", got, tt.policy)
+			}
+			if got := entryValue(entries, "inline"); got != tt.inline {
+				t.Errorf("inline = %v, want %v", got, tt.inline)
+			}
+		})
+	}
+}
+
+func TestRecommend_MemoryLimitShrinksRing(t *testing.T) {
+	host := hostInfo{CPUs: 16, GOMAXPROCS: 16, MemLimit: 64 << 20}
+	capacity := entryValue(recommend(host, defaultAnswers(), 0, false), "capacity").(int64)
+	// #nosec G115 - test values are small
+	if uint64(capacity)*recordSlotSize > host.MemLimit/32 && capacity > minGeneratedCapacity {
+		t.Errorf("capacity %d exceeds the memory budget", capacity)
+	}
+	if capacity&(capacity-1) != 0 {
+		t.Errorf("capacity %d is not a power of two", capacity)
+	}
+}
+
+func TestRecommend_RotationNote(t *testing.T) {
+	host := hostInfo{CPUs: 4, GOMAXPROCS: 4}
+
+	a := defaultAnswers()
+	if entryValue(recommend(host, a, 0, false), "rotation") != nil {
+		t.Error("rotation should never carry a value")
+	}
+	for _, e := range recommend(host, a, 0, false) {
+		if e.Key == "rotation" {
+			t.Error("stdout output should not get rotation advice")
+		}
+	}
+
+	a.Output = "/var/log/app.log"
+	var note string
+	for _, e := range recommend(host, a, 5<<30, true) {
+		if e.Key == "rotation" {
+			note = e.Comment
+		}
+	}
+	if !strings.Contains(note, "5.0 GiB free") || !strings.Contains(note, "102.4 MiB") {
+		t.Errorf("unexpected rotation note %q", note)
+	}
+}
+
+func TestRender_LoadableByIris(t *testing.T) {
+	host := hostInfo{CPUs: 8, GOMAXPROCS: 8, MemLimit: 4 << 30, OS: "linux", Arch: "amd64"}
+	a := answers{Profile: profileLatency, Output: "stderr", Format: "text", Level: "warn", Delivery: "block", Name: "api"}
+
+	data, err := render(host, recommend(host, a, 0, false))
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("generated config is not valid JSON: %v\n%s", err, data)
+	}
+	if _, ok := raw["// capacity"]; !ok {
+		t.Errorf("missing capacity comment:\n%s", data)
+	}
+
+	path := filepath.Join(t.TempDir(), "iris.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	cfg, err := iris.LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	if cfg.Level != iris.Warn || cfg.Name != "api" || cfg.Capacity != 32768 || cfg.BatchSize != 32 {
+		t.Errorf("unexpected loaded config: level=%v name=%q capacity=%d batch=%d", cfg.Level, cfg.Name, cfg.Capacity, cfg.BatchSize)
+	}
+	if cfg.BackpressurePolicy != zephyroslite.BlockOnFull {
+		t.Errorf("backpressure policy = %v, want BlockOnFull", cfg.BackpressurePolicy)
+	}
+}
+
+func TestPrompter_Choose(t *testing.T) {
+	var out bytes.Buffer
+	p := &prompter{in: bufio.NewReader(strings.NewReader("\nBLOCK\nnope\nmaybe\n")), out: &out}
+
+	if got := p.choose("q", []string{"drop", "block"}, "drop"); got != "drop" {
+		t.Errorf("empty answer = %q, want default", got)
+	}
+	if got := p.choose("q", []string{"drop", "block"}, "drop"); got != "block" {
+		t.Errorf("case-insensitive answer = %q, want block", got)
+	}
+	// Two invalid answers, then EOF: falls back to the default
+	if got := p.choose("q", []string{"drop", "block"}, "drop"); got != "drop" {
+		t.Errorf("invalid answers = %q, want default", got)
+	}
+	if !strings.Contains(out.String(), "please answer one of") {
+		t.Error("expected a hint after an invalid answer")
+	}
+}
+
+func TestRunInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iris.json")
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"init", "-y", "-o", path}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("init exited %d: %s", code, stderr.String())
+	}
+	if _, err := iris.LoadConfigFromJSON(path); err != nil {
+		t.Errorf("generated file does not load: %v", err)
+	}
+
+	if code := run([]string{"init", "-y", "-o", path}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("expected refusal to overwrite, got exit %d", code)
+	}
+	if code := run([]string{"init", "-y", "-force", "-o", path}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Errorf("expected -force to overwrite, got exit %d", code)
+	}
+	if code := run([]string{"bogus"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("unknown command exited %d, want 2", code)
+	}
+}
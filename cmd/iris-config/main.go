@@ -0,0 +1,68 @@
+// main.go: iris-config, configuration tooling for the Iris logging library
+//
+// Usage:
+//
+//	iris-config init [-o config.json] [-y] [-force]
+//	iris-config check [-strict] [-q] config.json
+//	iris-config bench [-config config.json] [-encoders json,text] [-sinks discard,app.log] [-duration 1s]
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is an iris-config subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+// commands lists the available subcommands in help order.
+var commands = []command{
+	{"init", "interrogate the host and generate a tuned JSON config", runInit},
+	{"check", "validate a JSON config and print the effective settings", runCheck},
+	{"bench", "benchmark encoders and sinks with a config on this machine", runBenchCommand},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+	_, _ = fmt.Fprintf(stderr, "iris-config: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// usage prints the command summary.
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: iris-config <command> [flags]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-8s %s\n", c.name, c.summary)
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Run 'iris-config <command> -h' for command flags.")
+}
@@ -0,0 +1,120 @@
+// probe.go: Host interrogation for config generation
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// hostInfo describes the resources available to the logging process.
+type hostInfo struct {
+	CPUs       int     // runtime.NumCPU
+	GOMAXPROCS int     // Current GOMAXPROCS
+	CPUQuota   float64 // Container CPU limit in cores (0 = unlimited)
+	MemLimit   uint64  // Container memory limit in bytes (0 = unlimited)
+	OS         string
+	Arch       string
+}
+
+// EffectiveCPUs returns the number of cores the process can actually use.
+func (h hostInfo) EffectiveCPUs() int {
+	n := h.CPUs
+	if h.GOMAXPROCS > 0 && h.GOMAXPROCS < n {
+		n = h.GOMAXPROCS
+	}
+	if h.CPUQuota > 0 {
+		if q := int(math.Ceil(h.CPUQuota)); q < n {
+			n = q
+		}
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// Cgroup files consulted for container limits (v2 first, then v1).
+var (
+	cgroupCPUMaxPath      = "/sys/fs/cgroup/cpu.max"
+	cgroupCPUQuotaPathV1  = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupCPUPeriodPathV1 = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	cgroupMemMaxPath      = "/sys/fs/cgroup/memory.max"
+	cgroupMemLimitPathV1  = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+)
+
+// probeHost collects CPU and container limits for the current process.
+// Missing cgroup files simply mean "no limit".
+func probeHost() hostInfo {
+	return hostInfo{
+		CPUs:       runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		CPUQuota:   readCPUQuota(),
+		MemLimit:   readMemLimit(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// readCPUQuota returns the cgroup CPU limit in cores, or 0 if unlimited.
+func readCPUQuota() float64 {
+	if fields := strings.Fields(readSmallFile(cgroupCPUMaxPath)); len(fields) == 2 {
+		return quotaCores(fields[0], fields[1])
+	}
+	return quotaCores(readSmallFile(cgroupCPUQuotaPathV1), readSmallFile(cgroupCPUPeriodPathV1))
+}
+
+// quotaCores converts a CFS quota/period pair to cores ("max" or -1 = unlimited).
+func quotaCores(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// readMemLimit returns the cgroup memory limit in bytes, or 0 if unlimited.
+func readMemLimit() uint64 {
+	for _, path := range []string{cgroupMemMaxPath, cgroupMemLimitPathV1} {
+		v, err := strconv.ParseUint(readSmallFile(path), 10, 64)
+		// cgroup v1 reports "unlimited" as a huge page-aligned value
+		if err == nil && v > 0 && v < 1<<62 {
+			return v
+		}
+	}
+	return 0
+}
+
+// readSmallFile returns the trimmed content of a small pseudo-file, or "".
+func readSmallFile(path string) string {
+	data, err := os.ReadFile(path) // #nosec G304 -- fixed cgroup paths
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// formatBytes renders n with a binary unit suffix.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatUint(n, 10) + " B"
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + string("KMGTP"[exp]) + "iB"
+}
@@ -0,0 +1,14 @@
+// probe_other.go: Free disk space on platforms without statfs support
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !(linux || darwin)
+
+package main
+
+// diskFree is not implemented on this platform.
+func diskFree(dir string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,22 @@
+// probe_statfs.go: Free disk space on Linux and macOS
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux || darwin
+
+package main
+
+import "syscall"
+
+// diskFree returns the bytes available to unprivileged users on the
+// filesystem holding dir.
+func diskFree(dir string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	// #nosec G115 - block size is positive
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
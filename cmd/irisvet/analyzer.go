@@ -0,0 +1,268 @@
+// analyzer.go: go/analysis pass for Iris logging calls
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// irisPath is the import path of the Iris package.
+const irisPath = "github.com/agilira/iris"
+
+// Analyzer reports misuse of Iris fields and logging calls.
+var Analyzer = &analysis.Analyzer{
+	Name:     "irisvet",
+	Doc:      "report duplicate field keys, unguarded expensive Debug arguments and credentials not logged with Secret",
+	URL:      "https://pkg.go.dev/github.com/agilira/iris/cmd/irisvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// verboseLevels are the logging methods of levels usually disabled in
+// production, whose arguments should be cheap or guarded.
+var verboseLevels = map[string]string{
+	"Trace": "Trace", "Tracef": "Trace", "TraceCtx": "Trace",
+	"Debug": "Debug", "Debugf": "Debug", "DebugCtx": "Debug",
+}
+
+// sensitiveKeys are substrings of field keys holding credentials, matched
+// against the lowercased key without '_', '-' and '.'.
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "apikey", "privatekey"}
+
+// cheapFuncs are calls that are not worth guarding.
+var cheapFuncs = map[string]bool{"time.Now": true, "time.Since": true, "time.Until": true}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != irisPath {
+			return true
+		}
+		if isFieldConstructor(fn) {
+			checkSecret(pass, call, fn)
+		}
+		checkDuplicateKeys(pass, call, fn)
+		checkUnguarded(pass, call, fn, stack)
+		return true
+	})
+	return nil, nil
+}
+
+// isFieldConstructor reports whether fn is a package-level function
+// returning an iris.Field whose first parameter is the key.
+func isFieldConstructor(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() != nil || sig.Results().Len() != 1 || sig.Params().Len() == 0 {
+		return false
+	}
+	if !isIrisType(sig.Results().At(0).Type(), "Field") {
+		return false
+	}
+	basic, ok := sig.Params().At(0).Type().(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
+
+// fieldKey returns the constant key of a field constructor call.
+func fieldKey(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	if len(call.Args) == 0 {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// checkSecret reports credential-like keys of fields holding text that
+// are not built with Secret.
+func checkSecret(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) {
+	sig := fn.Type().(*types.Signature)
+	if fn.Name() == "Secret" || sig.Params().Len() < 2 || !holdsText(sig.Params().At(1).Type()) {
+		return
+	}
+	key, ok := fieldKey(pass, call)
+	if !ok {
+		return
+	}
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, s := range sensitiveKeys {
+		if strings.Contains(normalized, s) {
+			pass.Reportf(call.Pos(), "field %q looks like a credential; log it with iris.Secret", key)
+			return
+		}
+	}
+}
+
+// holdsText reports whether a field value of type t may carry a
+// credential: a string, a byte slice or an interface.
+func holdsText(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Kind() == types.String
+	case *types.Slice:
+		elem, ok := u.Elem().Underlying().(*types.Basic)
+		return ok && elem.Kind() == types.Byte
+	case *types.Interface:
+		return true
+	}
+	return false
+}
+
+// checkDuplicateKeys reports fields passed twice with the same key to a
+// call taking ...iris.Field.
+func checkDuplicateKeys(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) {
+	sig := fn.Type().(*types.Signature)
+	if !sig.Variadic() || call.Ellipsis != token.NoPos {
+		return
+	}
+	last := sig.Params().At(sig.Params().Len() - 1).Type().(*types.Slice)
+	if !isIrisType(last.Elem(), "Field") {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, arg := range call.Args[sig.Params().Len()-1:] {
+		field, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		ctor, ok := typeutil.Callee(pass.TypesInfo, field).(*types.Func)
+		if !ok || ctor.Pkg() == nil || ctor.Pkg().Path() != irisPath || !isFieldConstructor(ctor) {
+			continue
+		}
+		key, ok := fieldKey(pass, field)
+		if !ok {
+			continue
+		}
+		if seen[key] {
+			pass.Reportf(field.Pos(), "duplicate field key %q in call to %s", key, fn.Name())
+		}
+		seen[key] = true
+	}
+}
+
+// checkUnguarded reports the first expensive argument of a Trace or Debug
+// call, or of a Verbose call, outside an if statement checking the level.
+func checkUnguarded(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, stack []ast.Node) {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return
+	}
+	var guard string
+	if level, ok := verboseLevels[fn.Name()]; ok && isIrisType(sig.Recv().Type(), "") {
+		guard = "Check(iris." + level + ")"
+	} else if (fn.Name() == "Info" || fn.Name() == "Infof") && isIrisType(sig.Recv().Type(), "Verbose") {
+		guard = "Enabled()"
+	} else {
+		return
+	}
+	if guarded(pass, call, stack) {
+		return
+	}
+	for _, arg := range call.Args {
+		if expensive := findExpensive(pass, arg); expensive != nil {
+			pass.Reportf(expensive.Pos(), "%s is evaluated even when %s is disabled; guard the call with %s",
+				types.ExprString(expensive.Fun), fn.Name(), guard)
+			return
+		}
+	}
+}
+
+// guarded reports whether call is in the body of an if statement whose
+// condition calls an Iris Check or Enabled method.
+func guarded(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		ifStmt, ok := stack[i].(*ast.IfStmt)
+		if !ok || i+1 >= len(stack) || stack[i+1] != ifStmt.Body {
+			continue
+		}
+		found := false
+		ast.Inspect(ifStmt.Cond, func(n ast.Node) bool {
+			c, ok := n.(*ast.CallExpr)
+			if !ok || found {
+				return !found
+			}
+			if fn, ok := typeutil.Callee(pass.TypesInfo, c).(*types.Func); ok && fn.Pkg() != nil &&
+				fn.Pkg().Path() == irisPath && (fn.Name() == "Check" || fn.Name() == "Enabled") {
+				found = true
+			}
+			return true
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// findExpensive returns the first call in expr that does more than build
+// a field, convert a value or read a getter, or nil.
+func findExpensive(pass *analysis.Pass, expr ast.Expr) *ast.CallExpr {
+	var expensive *ast.CallExpr
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if expensive != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // Not called here
+		case *ast.CallExpr:
+			if !cheapCall(pass, n) {
+				expensive = n
+				return false
+			}
+		}
+		return true
+	})
+	return expensive
+}
+
+// cheapCall reports whether call is a conversion, a builtin, an Iris field
+// constructor, a method without arguments (a getter) or a cheap function.
+func cheapCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if tv, ok := pass.TypesInfo.Types[call.Fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+		return true
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok {
+		return false
+	}
+	if fn.Pkg() != nil && fn.Pkg().Path() == irisPath && isFieldConstructor(fn) {
+		return true
+	}
+	if fn.Type().(*types.Signature).Recv() != nil && len(call.Args) == 0 {
+		return true
+	}
+	return fn.Pkg() != nil && cheapFuncs[fn.Pkg().Path()+"."+fn.Name()]
+}
+
+// isIrisType reports whether t, or the type it points to, is the Iris type
+// name (any Iris named type for an empty name).
+func isIrisType(t types.Type, name string) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != irisPath {
+		return false
+	}
+	return name == "" || named.Obj().Name() == name
+}
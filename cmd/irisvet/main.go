@@ -0,0 +1,24 @@
+// main.go: irisvet, a vet tool detecting misuse of the Iris logging library
+//
+// Usage:
+//
+//	irisvet ./...
+//	go vet -vettool=$(which irisvet) ./...
+//
+// irisvet reports:
+//   - duplicate field keys in one logging call
+//   - expensive arguments of Debug and Trace calls not guarded by
+//     Logger.Check or Verbose.Enabled, evaluated even when the level is off
+//   - credential-like fields (password, token, ...) not logged with Secret
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
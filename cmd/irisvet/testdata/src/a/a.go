@@ -0,0 +1,51 @@
+package a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+type request struct{ id string }
+
+func (r request) ID() string { return r.id }
+
+func dump() string { return "" }
+
+func duplicates(l *iris.Logger) {
+	l.Info("login", iris.Str("user", "a"), iris.Int("attempt", 1), iris.Str("user", "b")) // want `duplicate field key "user" in call to Info`
+	l.With(iris.Int("shard", 1), iris.Int("shard", 2))                                    // want `duplicate field key "shard" in call to With`
+	fields := []iris.Field{iris.Str("user", "a"), iris.Str("user", "b")}
+	l.Info("spread", fields...)
+}
+
+func secrets(l *iris.Logger, pw string, key []byte) {
+	l.Info("login", iris.Str("password", pw))        // want `field "password" looks like a credential; log it with iris.Secret`
+	l.Info("call", iris.Bytes("API-Key", key))       // want `field "API-Key" looks like a credential; log it with iris.Secret`
+	l.Info("refresh", iris.Any("refresh_token", pw)) // want `field "refresh_token" looks like a credential; log it with iris.Secret`
+	l.Info("login", iris.Secret("password", pw))
+	l.Info("usage", iris.Int("token_count", 3))
+}
+
+func unguarded(ctx context.Context, l *iris.Logger, r request, start time.Time, v any) {
+	l.Debug("state", iris.Str("dump", dump()))                    // want `dump is evaluated even when Debug is disabled; guard the call with Check\(iris.Debug\)`
+	l.Debug(fmt.Sprintf("request %s", r.ID()))                    // want `fmt.Sprintf is evaluated even when Debug is disabled`
+	l.Debugf("value %s", strconv.Itoa(3))                         // want `strconv.Itoa is evaluated even when Debugf is disabled`
+	l.DebugCtx(ctx, "json", iris.Any("v", must(json.Marshal(v)))) // want `must is evaluated even when DebugCtx is disabled`
+	l.V(3).Info("cache", iris.Str("dump", dump()))                // want `dump is evaluated even when Info is disabled; guard the call with Enabled\(\)`
+
+	l.Debug("cheap", iris.Str("id", r.ID()), iris.Int("n", len(r.id)), iris.Any("took", time.Since(start)), iris.Str("s", string(r.id)))
+	l.Info("not debug", iris.Str("dump", dump()))
+	if l.Check(iris.Debug) {
+		l.Debug("state", iris.Str("dump", dump()))
+	}
+	if vv := l.V(3); vv.Enabled() {
+		vv.Info("cache", iris.Str("dump", dump()))
+	}
+}
+
+func must(b []byte, err error) string { return string(b) }
@@ -0,0 +1,36 @@
+// Package iris is a stub of the Iris API used by the irisvet tests.
+package iris
+
+import "context"
+
+type Level int
+
+const (
+	Trace Level = iota - 2
+	Debug
+	Info
+)
+
+type Field struct{}
+
+func Str(k, v string) Field             { return Field{} }
+func Int(k string, v int) Field         { return Field{} }
+func Bytes(k string, v []byte) Field    { return Field{} }
+func Any(k string, v interface{}) Field { return Field{} }
+func Secret(k, v string) Field          { return Field{} }
+func Err(err error) Field               { return Field{} }
+
+type Logger struct{}
+
+func (l *Logger) Check(level Level) bool                                         { return false }
+func (l *Logger) Debug(msg string, fields ...Field) bool                         { return true }
+func (l *Logger) Debugf(format string, args ...any) bool                         { return true }
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...Field) bool { return true }
+func (l *Logger) Info(msg string, fields ...Field) bool                          { return true }
+func (l *Logger) With(fields ...Field) *Logger                                   { return l }
+func (l *Logger) V(level int) Verbose                                            { return Verbose{} }
+
+type Verbose struct{}
+
+func (v Verbose) Enabled() bool                         { return false }
+func (v Verbose) Info(msg string, fields ...Field) bool { return true }
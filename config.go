@@ -12,7 +12,6 @@ package iris
 
 import (
 	"fmt"
-	"os"
 	"sync/atomic"
 	"time"
 
@@ -59,6 +58,19 @@ func ParseArchitecture(s string) (Architecture, error) {
 	}
 }
 
+// AutoStartMode controls whether New starts the logger's consumer.
+type AutoStartMode uint8
+
+const (
+	// AutoStartOn starts the logger in New (default)
+	AutoStartOn AutoStartMode = iota
+
+	// AutoStartOff leaves starting to an explicit Logger.Start call.
+	// Records logged before Start are buffered and reported once through
+	// the error handler.
+	AutoStartOff
+)
+
 // Config represents the core configuration for an iris logger instance.
 // This structure centralizes all logging parameters with intelligent defaults
 // and performance optimizations. All fields are designed for zero-copy
@@ -108,6 +120,28 @@ type Config struct {
 	// - ProgressiveIdleStrategy: Adaptive strategy for variable workloads (default)
 	IdleStrategy zephyroslite.IdleStrategy
 
+	// SlotPadding keeps a cache line between adjacent ring slots, so that
+	// producers filling one slot do not invalidate the cache line the
+	// consumer (or another producer) is using for its neighbour. It costs
+	// zephyroslite.SlotPadding bytes per slot, under 2% of a Record; enable
+	// it for latency-sensitive deployments with many concurrent producers.
+	// Default: false
+	SlotPadding bool
+
+	// MaxCapacity makes the ring elastic: when it is greater than Capacity,
+	// the ring starts at Capacity and doubles, up to MaxCapacity, whenever
+	// writers keep finding it full for GrowAfter. Bursts then cost memory
+	// only while they last, instead of a permanently over-provisioned
+	// ring; a grown ring does not shrink back. Must be a power of two.
+	// Elastic rings add a striped writer count per write.
+	// Default: 0 (fixed capacity)
+	MaxCapacity int64
+
+	// GrowAfter is how long an elastic ring must stay saturated before it
+	// grows. Ignored unless MaxCapacity is set.
+	// Default: DefaultGrowAfter (10ms)
+	GrowAfter time.Duration
+
 	// Output and formatting configuration
 	// Output specifies where log entries are written. Must implement WriteSyncer
 	// for proper synchronization guarantees.
@@ -134,6 +168,31 @@ type Config struct {
 	// Name provides a human-readable identifier for this logger instance
 	// Useful for debugging and metrics collection
 	Name string
+
+	// Inline processes records synchronously in the calling goroutine instead
+	// of a background consumer. No ring buffer is allocated and Start() does
+	// not spawn a goroutine; Capacity, BatchSize, BackpressurePolicy and
+	// IdleStrategy are ignored. Intended for constrained environments (WASM,
+	// GOMAXPROCS=1, short-lived CLIs) where throughput matters less than
+	// footprint and determinism.
+	Inline bool
+
+	// AutoStart controls whether New starts the logger. With the default,
+	// AutoStartOn, the logger is ready to write as soon as New returns and
+	// calling Start is unnecessary (but harmless). AutoStartOff restores the
+	// explicit New/Start lifecycle, e.g. to attach a custom consumer first.
+	AutoStart AutoStartMode
+
+	// Fields are static context added to every record, as if passed to
+	// Logger.With on the constructed logger (e.g. service, region). The
+	// "fields" object of a JSON config file is loaded here. Logger.SetFields
+	// replaces them at runtime.
+	Fields []Field
+
+	// Pipeline declares processors run on every record in the consumer and
+	// additional sinks (see PipelineConfig). New validates it and opens its
+	// sinks; the "pipeline" object of a JSON config file is loaded here.
+	Pipeline *PipelineConfig
 }
 
 // stats represents internal logger statistics exposed via Logger.Stats().
@@ -191,7 +250,7 @@ func (c *Config) withDefaults() *Config {
 	// Default output to stdout with proper synchronization
 	// WrapWriter automatically handles different writer types optimally
 	if out.Output == nil {
-		out.Output = WrapWriter(os.Stdout)
+		out.Output = defaultOutput()
 	}
 
 	// Default time function to system time
@@ -7,10 +7,12 @@
 package iris
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -54,42 +56,103 @@ func LoadConfigFromJSON(filename string) (*Config, error) {
 
 	// Parse JSON into a temporary structure
 	var jsonConfig struct {
-		Level              string `json:"level"`
-		Format             string `json:"format"`
-		Output             string `json:"output"`
-		Capacity           int64  `json:"capacity"`
-		BatchSize          int64  `json:"batch_size"`
-		EnableCaller       bool   `json:"enable_caller"`
-		Development        bool   `json:"development"`
-		Name               string `json:"name"`
-		BackpressurePolicy string `json:"backpressure_policy"`
-		IdleStrategy       string `json:"idle_strategy"`
+		Level              string                     `json:"level"`
+		Format             string                     `json:"format"`
+		Output             string                     `json:"output"`
+		Capacity           int64                      `json:"capacity"`
+		BatchSize          int64                      `json:"batch_size"`
+		EnableCaller       bool                       `json:"enable_caller"`
+		Development        bool                       `json:"development"`
+		Name               string                     `json:"name"`
+		BackpressurePolicy string                     `json:"backpressure_policy"`
+		IdleStrategy       string                     `json:"idle_strategy"`
+		Inline             bool                       `json:"inline"`
+		SampleRate         *float64                   `json:"sample_rate"`
+		ConsoleTheme       string                     `json:"console_theme"`
+		ConsoleTimeZone    string                     `json:"console_timezone"`
+		Fields             map[string]json.RawMessage `json:"fields"`
+		Pipeline           *PipelineConfig            `json:"pipeline"`
 	}
 
 	if err := json.Unmarshal(data, &jsonConfig); err != nil {
 		return &config, fmt.Errorf("failed to parse JSON config: %w", err)
 	}
 
+	// Static fields are validated before any output file is opened
+	fields, err := parseConfigFields(jsonConfig.Fields)
+	if err != nil {
+		return &config, err
+	}
+	config.Fields = fields
+
+	// The pipeline is validated here too; its sinks are opened by New
+	if jsonConfig.Pipeline != nil {
+		if err := jsonConfig.Pipeline.Validate(); err != nil {
+			return &config, err
+		}
+		config.Pipeline = jsonConfig.Pipeline
+	}
+
 	// Convert level string to Level enum
 	config.Level = parseLevel(jsonConfig.Level)
 
 	// Set encoder based on format
+	format := "json"
 	switch strings.ToLower(jsonConfig.Format) {
 	case "json":
 		config.Encoder = NewJSONEncoder()
-	case "text", "console":
+	case "text":
 		config.Encoder = NewTextEncoder()
+		format = "text"
+	case "console":
+		config.Encoder = consoleFormatEncoder(jsonConfig.ConsoleTheme, jsonConfig.ConsoleTimeZone)
+		format = encoderFormat(config.Encoder)
+	case "gelf":
+		config.Encoder = NewGELFEncoder()
+		format = "gelf"
+	case "":
+		// A Graylog output implies its format
+		if isGELFOutput(jsonConfig.Output) {
+			config.Encoder = NewGELFEncoder()
+			format = "gelf"
+		} else {
+			config.Encoder = NewJSONEncoder()
+		}
 	default:
-		config.Encoder = NewJSONEncoder() // Default to JSON
+		if enc, ok := registeredEncoder(jsonConfig.Format); ok {
+			config.Encoder = enc
+			format = jsonConfig.Format
+		} else {
+			config.Encoder = NewJSONEncoder() // Default to JSON
+		}
 	}
 
 	// Set output
 	switch strings.ToLower(jsonConfig.Output) {
 	case "stdout":
 		config.Output = WrapWriter(os.Stdout)
+	case OutputStdSplit:
+		config.Output = WrapWriter(os.Stdout)
+		config.Pipeline = StdSplitPipeline(config.Pipeline, format)
 	case "stderr":
 		config.Output = WrapWriter(os.Stderr)
 	default:
+		if isGELFOutput(jsonConfig.Output) {
+			out, err := openGELFOutput(jsonConfig.Output)
+			if err != nil {
+				return &config, err
+			}
+			config.Output = out
+			break
+		}
+		if isLokiOutput(jsonConfig.Output) {
+			out, err := openLokiOutput(jsonConfig.Output)
+			if err != nil {
+				return &config, err
+			}
+			config.Output = out
+			break
+		}
 		// Assume it's a file path
 		if jsonConfig.Output != "" {
 			file, err := os.OpenFile(jsonConfig.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
@@ -123,6 +186,14 @@ func LoadConfigFromJSON(filename string) (*Config, error) {
 		config.IdleStrategy = parseIdleStrategy(jsonConfig.IdleStrategy)
 	}
 
+	// Set inline processing mode
+	config.Inline = jsonConfig.Inline
+
+	// Set dynamic sampling rate (only when present, 1.0 keeps everything)
+	if jsonConfig.SampleRate != nil {
+		config.Sampler = NewDynamicSampler(*jsonConfig.SampleRate)
+	}
+
 	return &config, nil
 }
 
@@ -142,10 +213,26 @@ func LoadConfigFromEnv() (*Config, error) {
 	switch strings.ToLower(format) {
 	case "json":
 		config.Encoder = NewJSONEncoder()
-	case "text", "console":
+	case "text":
 		config.Encoder = NewTextEncoder()
+		format = "text"
+	case "console":
+		config.Encoder = consoleFormatEncoder("", "")
+		format = encoderFormat(config.Encoder)
+	case "gelf":
+		config.Encoder = NewGELFEncoder()
+		format = "gelf"
 	default:
-		config.Encoder = NewJSONEncoder() // Default to JSON
+		if enc, ok := registeredEncoder(format); ok {
+			config.Encoder = enc
+		} else if format == "" && isGELFOutput(os.Getenv("IRIS_OUTPUT")) {
+			// A Graylog output implies its format
+			config.Encoder = NewGELFEncoder()
+			format = "gelf"
+		} else {
+			config.Encoder = NewJSONEncoder() // Default to JSON
+			format = "json"
+		}
 	}
 
 	// Output from IRIS_OUTPUT
@@ -153,9 +240,28 @@ func LoadConfigFromEnv() (*Config, error) {
 	switch strings.ToLower(output) {
 	case "stdout", "":
 		config.Output = WrapWriter(os.Stdout)
+	case OutputStdSplit:
+		config.Output = WrapWriter(os.Stdout)
+		config.Pipeline = StdSplitPipeline(nil, format)
 	case "stderr":
 		config.Output = WrapWriter(os.Stderr)
 	default:
+		if isGELFOutput(output) {
+			out, err := openGELFOutput(output)
+			if err != nil {
+				return &config, err
+			}
+			config.Output = out
+			break
+		}
+		if isLokiOutput(output) {
+			out, err := openLokiOutput(output)
+			if err != nil {
+				return &config, err
+			}
+			config.Output = out
+			break
+		}
 		// Validate file path for security
 		if err := validateFilePath(output); err != nil {
 			return &config, fmt.Errorf("invalid output file path: %w", err)
@@ -197,6 +303,20 @@ func LoadConfigFromEnv() (*Config, error) {
 		config.IdleStrategy = parseIdleStrategy(strategyStr)
 	}
 
+	// Inline from IRIS_INLINE
+	if inlineStr := os.Getenv("IRIS_INLINE"); inlineStr != "" {
+		if inline, err := strconv.ParseBool(inlineStr); err == nil {
+			config.Inline = inline
+		}
+	}
+
+	// Sampler from IRIS_SAMPLE_RATE
+	if rateStr := os.Getenv("IRIS_SAMPLE_RATE"); rateStr != "" {
+		if rate, err := strconv.ParseFloat(rateStr, 64); err == nil {
+			config.Sampler = NewDynamicSampler(rate)
+		}
+	}
+
 	return &config, nil
 }
 
@@ -240,6 +360,18 @@ func LoadConfigMultiSource(jsonFile string) (*Config, error) {
 			if jsonConfig.IdleStrategy != nil {
 				config.IdleStrategy = jsonConfig.IdleStrategy
 			}
+			if jsonConfig.Inline {
+				config.Inline = true
+			}
+			if jsonConfig.Sampler != nil {
+				config.Sampler = jsonConfig.Sampler
+			}
+			if len(jsonConfig.Fields) > 0 {
+				config.Fields = jsonConfig.Fields
+			}
+			if jsonConfig.Pipeline != nil {
+				config.Pipeline = jsonConfig.Pipeline
+			}
 		}
 	}
 
@@ -256,8 +388,15 @@ func LoadConfigMultiSource(jsonFile string) (*Config, error) {
 	if format := os.Getenv("IRIS_FORMAT"); format != "" {
 		config.Encoder = envConfig.Encoder
 	}
+	split := hasStdSplit(config.Pipeline)
 	if output := os.Getenv("IRIS_OUTPUT"); output != "" {
 		config.Output = envConfig.Output
+		split = strings.EqualFold(output, OutputStdSplit)
+	}
+	// The std-split sink follows the final output and format
+	config.Pipeline = withoutStdSplit(config.Pipeline)
+	if split {
+		config.Pipeline = StdSplitPipeline(config.Pipeline, encoderFormat(config.Encoder))
 	}
 	if capacityStr := os.Getenv("IRIS_CAPACITY"); capacityStr != "" {
 		config.Capacity = envConfig.Capacity
@@ -274,13 +413,106 @@ func LoadConfigMultiSource(jsonFile string) (*Config, error) {
 	if strategyStr := os.Getenv("IRIS_IDLE_STRATEGY"); strategyStr != "" {
 		config.IdleStrategy = envConfig.IdleStrategy
 	}
+	if inlineStr := os.Getenv("IRIS_INLINE"); inlineStr != "" {
+		config.Inline = envConfig.Inline
+	}
+	if rateStr := os.Getenv("IRIS_SAMPLE_RATE"); rateStr != "" && envConfig.Sampler != nil {
+		config.Sampler = envConfig.Sampler
+	}
 
 	return &config, nil
 }
 
+// openGELFOutput connects the writer of a gelf:// output (see ParseGELFURL).
+func openGELFOutput(output string) (WriteSyncer, error) {
+	cfg, err := ParseGELFURL(output)
+	if err != nil {
+		return nil, err
+	}
+	return NewGELFWriter(cfg)
+}
+
+// openLokiOutput starts the writer of a loki:// output (see ParseLokiURL).
+func openLokiOutput(output string) (WriteSyncer, error) {
+	cfg, err := ParseLokiURL(output)
+	if err != nil {
+		return nil, err
+	}
+	return NewLokiWriter(cfg)
+}
+
+// consoleFormatEncoder returns the encoder of the "console" format: the
+// plain text encoder, or a console encoder when a theme or a time zone is
+// named by the config file, IRIS_CONSOLE_THEME or IRIS_CONSOLE_TIMEZONE.
+// A theme makes it colored. The environment wins over the file; unknown
+// names keep the default colors and time zone.
+func consoleFormatEncoder(theme, zone string) Encoder {
+	envTheme, envZone := os.Getenv(ConsoleThemeEnv), os.Getenv(ConsoleTimeZoneEnv)
+	if theme == "" && zone == "" && envTheme == "" && envZone == "" {
+		return NewTextEncoder()
+	}
+	enc := NewConsoleEncoder()
+	if theme != "" || envTheme != "" {
+		enc = NewColorConsoleEncoder()
+	}
+	if enc.Theme == nil && theme != "" {
+		enc.Theme, _ = ParseConsoleTheme(theme)
+	}
+	if enc.TimeZone == nil && zone != "" {
+		enc.TimeZone, _ = ParseConsoleTimeZone(zone)
+	}
+	return enc
+}
+
+// parseConfigFields converts the "fields" object of a JSON config into
+// fields sorted by key. Values must be strings, numbers or booleans;
+// integral numbers become Int64 fields, others Float64.
+func parseConfigFields(raw map[string]json.RawMessage) ([]Field, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid fields: empty key")
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw[key]))
+		dec.UseNumber()
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid fields.%s: %w", key, err)
+		}
+		switch v := value.(type) {
+		case string:
+			fields = append(fields, Str(key, v))
+		case bool:
+			fields = append(fields, Bool(key, v))
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				fields = append(fields, Int64(key, i))
+			} else if f, err := v.Float64(); err == nil {
+				fields = append(fields, Float64(key, f))
+			} else {
+				return nil, fmt.Errorf("invalid fields.%s: %w", key, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid fields.%s: must be a string, number or boolean", key)
+		}
+	}
+	return fields, nil
+}
+
 // parseLevel converts a string to a Level enum
 func parseLevel(levelStr string) Level {
 	switch strings.ToLower(levelStr) {
+	case "trace":
+		return Trace
 	case "debug":
 		return Debug
 	case "info":
@@ -294,6 +526,10 @@ func parseLevel(levelStr string) Level {
 	case "fatal":
 		return Fatal
 	default:
+		// Custom levels and aliases registered via RegisterLevel/RegisterLevelAlias
+		if level, ok := customLevelValue(strings.ToLower(strings.TrimSpace(levelStr))); ok {
+			return level
+		}
 		return Info // Default level
 	}
 }
@@ -312,24 +548,10 @@ func parseBackpressurePolicy(policyStr string) zephyroslite.BackpressurePolicy {
 
 // parseIdleStrategy converts a string to an IdleStrategy
 func parseIdleStrategy(strategyStr string) IdleStrategy {
-	switch strings.ToLower(strategyStr) {
-	case "spinning":
-		return NewSpinningIdleStrategy()
-	case "sleeping":
-		return NewSleepingIdleStrategy(1*time.Millisecond, 0) // Default 1ms sleep, no spin
-	case "yielding":
-		return NewYieldingIdleStrategy(1000) // Default 1000 spins before yield
-	case "channel":
-		return NewChannelIdleStrategy(100 * time.Millisecond) // Default 100ms timeout
-	case "progressive", "balanced":
-		return NewProgressiveIdleStrategy()
-	case "efficient":
-		return EfficientStrategy
-	case "hybrid":
-		return HybridStrategy
-	default:
-		return BalancedStrategy // Default strategy
+	if strategy, err := ParseIdleStrategy(strategyStr); err == nil {
+		return strategy
 	}
+	return BalancedStrategy // Default strategy
 }
 
 // DynamicConfigWatcher manages dynamic configuration changes using Argus
@@ -337,6 +559,9 @@ func parseIdleStrategy(strategyStr string) IdleStrategy {
 type DynamicConfigWatcher struct {
 	configPath  string
 	atomicLevel *AtomicLevel
+	sampler     *DynamicSampler // Optional: receives "sample_rate" on reload
+	logger      *Logger         // Optional: receives "fields" changes on reload
+	fileFields  []Field         // "fields" of the file when last loaded
 	watcher     *argus.Watcher
 	enabled     int32      // Use atomic int32 instead of bool for thread safety
 	mu          sync.Mutex // Protect start/stop operations
@@ -407,6 +632,67 @@ func NewDynamicConfigWatcher(configPath string, atomicLevel *AtomicLevel) (*Dyna
 	}, nil
 }
 
+// SetSampler attaches a DynamicSampler whose rate follows the "sample_rate"
+// key of the watched configuration file. When the key is absent the current
+// rate is left unchanged; set it to 1.0 to restore full logging.
+// Must be called before Start.
+func (w *DynamicConfigWatcher) SetSampler(sampler *DynamicSampler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sampler = sampler
+}
+
+// SetLogger attaches a logger whose Config.Fields follow the "fields" object
+// of the watched configuration file. Whenever a reload finds the object
+// changed (a field added, removed or given another value), the logger and
+// every logger derived from the same root get the new set via SetFields;
+// records enqueued afterwards carry it. Reloads that leave "fields" as it
+// was do not touch the logger's fields. Must be called before Start.
+//
+// Example (mark a canary in the config file, no restart):
+//
+//	"fields": {"service": "checkout", "deployment_color": "green"}
+func (w *DynamicConfigWatcher) SetLogger(logger *Logger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.logger = logger
+}
+
+// applyFields passes the fields of a loaded configuration to the attached
+// logger if they differ from those of the previous load.
+func (w *DynamicConfigWatcher) applyFields(cfg *Config) {
+	if w.logger == nil || sameFields(cfg.Fields, w.fileFields) {
+		return
+	}
+	w.logger.SetFields(cfg.Fields...)
+	w.fileFields = cfg.Fields
+}
+
+// sameFields reports whether a and b hold the same keys and values in the
+// same order. Only the kinds produced by config files are compared.
+func sameFields(a, b []Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].K != b[i].K || a[i].T != b[i].T || a[i].Str != b[i].Str ||
+			a[i].I64 != b[i].I64 || a[i].U64 != b[i].U64 || a[i].F64 != b[i].F64 {
+			return false
+		}
+	}
+	return true
+}
+
+// applySampleRate updates the attached sampler from a loaded configuration.
+func (w *DynamicConfigWatcher) applySampleRate(cfg *Config) {
+	if w.sampler == nil {
+		return
+	}
+	if ds, ok := cfg.Sampler.(*DynamicSampler); ok {
+		w.sampler.SetRate(ds.Rate())
+	}
+}
+
 // Start begins watching the configuration file for changes
 func (w *DynamicConfigWatcher) Start() error {
 	w.mu.Lock()
@@ -417,10 +703,16 @@ func (w *DynamicConfigWatcher) Start() error {
 	}
 
 	// Load initial configuration
-	if w.atomicLevel != nil {
+	if w.atomicLevel != nil || w.logger != nil {
 		initialConfig, err := LoadConfigFromJSON(w.configPath)
 		if err == nil {
-			w.atomicLevel.SetLevel(initialConfig.Level)
+			if w.atomicLevel != nil {
+				w.atomicLevel.SetLevel(initialConfig.Level)
+			}
+			w.applySampleRate(initialConfig)
+			// The logger was built with these fields or its own: keep them
+			// until the file changes them
+			w.fileFields = initialConfig.Fields
 		}
 		// Don't fail on initial load error - just continue with current level
 	}
@@ -441,6 +733,12 @@ func (w *DynamicConfigWatcher) Start() error {
 			w.atomicLevel.SetLevel(newConfig.Level)
 		}
 
+		// Update the sampling rate if a dynamic sampler is attached
+		w.applySampleRate(newConfig)
+
+		// Update the static fields if a logger is attached
+		w.applyFields(newConfig)
+
 		// Log successful config reload (using our own logger would create a loop!)
 		// Instead we write to stderr for safety
 		fmt.Fprintf(os.Stderr, "[IRIS] Configuration reloaded from %s - Level: %s\n",
@@ -502,6 +800,13 @@ func EnableDynamicLevel(logger *Logger, configPath string) (*DynamicConfigWatche
 		return nil, fmt.Errorf("failed to create dynamic config watcher: %w", err)
 	}
 
+	// Loggers using a DynamicSampler also follow "sample_rate" changes
+	if ds, ok := logger.sampler.(*DynamicSampler); ok {
+		watcher.SetSampler(ds)
+	}
+	// and every logger follows "fields" changes
+	watcher.SetLogger(logger)
+
 	if err := watcher.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start dynamic config watcher: %w", err)
 	}
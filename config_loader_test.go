@@ -8,6 +8,7 @@ package iris
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -127,6 +128,59 @@ func TestLoadConfigFromJSON(t *testing.T) {
 	}
 }
 
+func TestLoadConfigFromJSON_Fields(t *testing.T) {
+	dir := t.TempDir()
+	load := func(t *testing.T, js string) (*Config, error) {
+		t.Helper()
+		path := filepath.Join(dir, "fields.json")
+		if err := os.WriteFile(path, []byte(js), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		return LoadConfigFromJSON(path)
+	}
+
+	config, err := load(t, `{"output": "stderr", "fields": {"service": "payments", "region": "eu-1", "shard": 7, "weight": 0.5, "canary": true}}`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := []Field{Bool("canary", true), Str("region", "eu-1"), Str("service", "payments"), Int64("shard", 7), Float64("weight", 0.5)}
+	if len(config.Fields) != len(want) {
+		t.Fatalf("Expected %d fields, got %+v", len(want), config.Fields)
+	}
+	for i := range want {
+		got := config.Fields[i]
+		if got.K != want[i].K || got.T != want[i].T || got.Value() != want[i].Value() {
+			t.Errorf("Field %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+
+	// The fields become base fields of the constructed logger
+	buf := &bufferedSyncer{}
+	config.Output = buf
+	logger, err := New(*config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("charged")
+	safeCloseConfigLogger(t, logger)
+	for _, s := range []string{`"service":"payments"`, `"region":"eu-1"`, `"shard":7`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected output to contain %s, got %s", s, buf.String())
+		}
+	}
+
+	for _, js := range []string{
+		`{"fields": {"tags": ["a", "b"]}}`,
+		`{"fields": {"owner": {"team": "billing"}}}`,
+		`{"fields": {"zone": null}}`,
+		`{"fields": {"": "x"}}`,
+	} {
+		if _, err := load(t, js); err == nil || !strings.Contains(err.Error(), "invalid fields") {
+			t.Errorf("Expected invalid fields error for %s, got %v", js, err)
+		}
+	}
+}
+
 func TestLoadConfigFromEnv(t *testing.T) {
 	// Test with all environment variables set
 	if err := os.Setenv("IRIS_LEVEL", "warn"); err != nil {
@@ -213,6 +267,7 @@ func TestLevelParsing(t *testing.T) {
 		input    string
 		expected Level
 	}{
+		{"trace", Trace},
 		{"debug", Debug},
 		{"DEBUG", Debug},
 		{"info", Info},
@@ -350,6 +405,7 @@ func TestIdleStrategyConfiguration(t *testing.T) {
 
 // TestDynamicConfigWatcherLifecycle tests the lifecycle of DynamicConfigWatcher
 func TestDynamicConfigWatcherLifecycle(t *testing.T) {
+	isolateConfigAudit(t)
 	t.Run("NewDynamicConfigWatcher", func(t *testing.T) {
 		// Create a temp config file
 		configJSON := `{"level": "info", "format": "json"}`
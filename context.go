@@ -14,6 +14,8 @@ package iris
 
 import (
 	"context"
+	"slices"
+	"strings"
 )
 
 // ContextKey represents a key type for context values that should be logged.
@@ -36,6 +38,40 @@ type ContextExtractor struct {
 
 	// MaxDepth limits how deep to search in context chain (default: 10)
 	MaxDepth int
+
+	// SafeExtract treats Keys as a strict allow-list of small values. Besides
+	// non-empty strings, booleans and fixed-size numbers are extracted;
+	// strings longer than MaxValueLen and every other type (structs, maps,
+	// slices, pointers, Stringers) are rejected, so that a request object or
+	// a blob stored in the context by mistake never reaches the logs.
+	SafeExtract bool
+
+	// MaxValueLen caps the length in bytes of an extracted string in
+	// SafeExtract mode (default: DefaultMaxContextValueLen)
+	MaxValueLen int
+
+	// MaxFields caps the number of fields extracted in SafeExtract mode
+	// (0: no cap). Keys are considered in field name order, so the same
+	// fields are kept on every extraction.
+	MaxFields int
+}
+
+// DefaultMaxContextValueLen is the longest string SafeExtract accepts when
+// ContextExtractor.MaxValueLen is zero.
+const DefaultMaxContextValueLen = 256
+
+// NewSafeContextExtractor returns an extractor in SafeExtract mode for the
+// allow-listed keys, with the default value length cap.
+//
+// Example:
+//
+//	extractor := iris.NewSafeContextExtractor(map[iris.ContextKey]string{
+//		iris.RequestIDKey: "request_id",
+//		iris.TraceIDKey:   "trace_id",
+//	})
+//	cl := logger.WithContextExtractor(ctx, extractor)
+func NewSafeContextExtractor(keys map[ContextKey]string) *ContextExtractor {
+	return &ContextExtractor{Keys: keys, MaxDepth: 10, SafeExtract: true}
 }
 
 // DefaultContextExtractor provides sensible defaults for common use cases.
@@ -59,7 +95,8 @@ type ContextLogger struct {
 
 // WithContext creates a new ContextLogger with fields extracted from context.
 // This is the recommended way to use context integration - extract once,
-// log many times with the same context.
+// log many times with the same context. The fields of WithContextFields
+// are extracted at the same time.
 //
 // Performance: O(k) where k is number of configured keys, not context depth.
 func (l *Logger) WithContext(ctx context.Context) *ContextLogger {
@@ -68,6 +105,13 @@ func (l *Logger) WithContext(ctx context.Context) *ContextLogger {
 
 // WithContextExtractor creates a ContextLogger with custom extraction rules.
 func (l *Logger) WithContextExtractor(ctx context.Context, extractor *ContextExtractor) *ContextLogger {
+	if extractor.SafeExtract {
+		return &ContextLogger{
+			logger: l,
+			fields: l.withContextFields(ctx, extractor.extractSafe(ctx)),
+		}
+	}
+
 	var fields []Field
 
 	// Pre-allocate for common case
@@ -87,7 +131,64 @@ func (l *Logger) WithContextExtractor(ctx context.Context, extractor *ContextExt
 
 	return &ContextLogger{
 		logger: l,
-		fields: fields,
+		fields: l.withContextFields(ctx, fields),
+	}
+}
+
+// extractSafe extracts the allow-listed keys of ctx under the SafeExtract
+// rules.
+func (e *ContextExtractor) extractSafe(ctx context.Context) []Field {
+	maxLen := e.MaxValueLen
+	if maxLen <= 0 {
+		maxLen = DefaultMaxContextValueLen
+	}
+	keys := make([]ContextKey, 0, len(e.Keys))
+	for contextKey := range e.Keys {
+		keys = append(keys, contextKey)
+	}
+	slices.SortFunc(keys, func(a, b ContextKey) int {
+		return strings.Compare(e.Keys[a], e.Keys[b])
+	})
+
+	var fields []Field
+	for _, contextKey := range keys {
+		if e.MaxFields > 0 && len(fields) == e.MaxFields {
+			break
+		}
+		if f, ok := safeContextField(e.Keys[contextKey], ctx.Value(contextKey), maxLen); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// safeContextField converts a context value to a field if SafeExtract
+// accepts it.
+func safeContextField(name string, value any, maxLen int) (Field, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" || len(v) > maxLen {
+			return Field{}, false
+		}
+		return Str(name, v), true
+	case bool:
+		return Bool(name, v), true
+	case int:
+		return Int(name, v), true
+	case int32:
+		return Int32(name, v), true
+	case int64:
+		return Int64(name, v), true
+	case uint:
+		return Uint(name, v), true
+	case uint32:
+		return Uint32(name, v), true
+	case uint64:
+		return Uint64(name, v), true
+	case float64:
+		return Float64(name, v), true
+	default:
+		return Field{}, false // Absent, or not a small value
 	}
 }
 
@@ -104,16 +205,25 @@ func (l *Logger) WithContextValue(ctx context.Context, key ContextKey, fieldName
 
 	return &ContextLogger{
 		logger: l,
-		fields: fields,
+		fields: l.withContextFields(ctx, fields),
 	}
 }
 
 // Logging methods for ContextLogger - all delegate to underlying logger
 // with pre-extracted context fields automatically included.
 
+// Trace logs a message at trace level with context fields
+func (cl *ContextLogger) Trace(msg string, fields ...Field) {
+	if !Trace.Enabled(cl.logger.level.Level()) {
+		return
+	}
+	allFields := append(cl.fields, fields...)
+	cl.logger.Trace(msg, allFields...)
+}
+
 // Debug logs a message at debug level with context fields
 func (cl *ContextLogger) Debug(msg string, fields ...Field) {
-	if cl.logger.level.Level() > Debug {
+	if !Debug.Enabled(cl.logger.level.Level()) {
 		return
 	}
 	allFields := append(cl.fields, fields...)
@@ -122,7 +232,7 @@ func (cl *ContextLogger) Debug(msg string, fields ...Field) {
 
 // Info logs a message at info level with context fields
 func (cl *ContextLogger) Info(msg string, fields ...Field) {
-	if cl.logger.level.Level() > Info {
+	if !Info.Enabled(cl.logger.level.Level()) {
 		return
 	}
 	allFields := append(cl.fields, fields...)
@@ -131,7 +241,7 @@ func (cl *ContextLogger) Info(msg string, fields ...Field) {
 
 // Warn logs a message at warn level with context fields
 func (cl *ContextLogger) Warn(msg string, fields ...Field) {
-	if cl.logger.level.Level() > Warn {
+	if !Warn.Enabled(cl.logger.level.Level()) {
 		return
 	}
 	allFields := append(cl.fields, fields...)
@@ -140,7 +250,7 @@ func (cl *ContextLogger) Warn(msg string, fields ...Field) {
 
 // Error logs a message at error level with context fields
 func (cl *ContextLogger) Error(msg string, fields ...Field) {
-	if cl.logger.level.Level() > Error {
+	if !Error.Enabled(cl.logger.level.Level()) {
 		return
 	}
 	allFields := append(cl.fields, fields...)
@@ -166,6 +276,15 @@ func (cl *ContextLogger) With(fields ...Field) *ContextLogger {
 	}
 }
 
+// Named creates a new ContextLogger with the same context fields whose
+// underlying logger is named as by Logger.Named.
+func (cl *ContextLogger) Named(name string) *ContextLogger {
+	return &ContextLogger{
+		logger: cl.logger.Named(name),
+		fields: cl.fields,
+	}
+}
+
 // WithAdditionalContext extracts additional context values without losing existing ones.
 func (cl *ContextLogger) WithAdditionalContext(ctx context.Context, extractor *ContextExtractor) *ContextLogger {
 	// Extract new context fields
@@ -0,0 +1,73 @@
+// context_fields.go: Fields derived from the context of each call
+//
+// ContextExtractor reads plain values stored in a context under known
+// keys. Tracing libraries keep their state behind their own accessors
+// instead (an OpenTelemetry span context, for instance), and the core must
+// not depend on them. WithContextFields lets such an integration turn a
+// context into fields: the *Ctx logging methods and the slog handler call
+// it for each record they log, and WithContext calls it once when a
+// ContextLogger is created.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import "context"
+
+// ContextFieldsFunc appends the fields to log for ctx to dst and returns
+// the extended slice. It must not modify the fields already in dst, and it
+// runs on the logging goroutine, so it should be cheap and must not log
+// through the same logger.
+type ContextFieldsFunc func(ctx context.Context, dst []Field) []Field
+
+// WithContextFields adds the fields fn derives from a context to the
+// records logged with one:
+//   - InfoCtx and the other *Ctx methods, and the slog handler, call fn for
+//     every record that passes the level and sampling checks
+//   - WithContext, WithContextExtractor and WithContextValue call it once,
+//     so the ContextLogger they return logs the fields without calling fn
+//     again
+//
+// Methods without a context (Info, ...) are not affected. Several
+// WithContextFields options run in the order they were given.
+//
+// Parameters:
+//   - fn: Function extracting the fields (nil is ignored)
+//
+// Returns:
+//   - Option: Configuration function to enable the extraction
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithContextFields(func(ctx context.Context, dst []iris.Field) []iris.Field {
+//		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+//			dst = append(dst, iris.Str("tenant", tenant))
+//		}
+//		return dst
+//	}))
+//	logger.InfoCtx(ctx, "quota checked")
+func WithContextFields(fn ContextFieldsFunc) Option {
+	return func(o *loggerOptions) {
+		if fn == nil {
+			return
+		}
+		if prev := o.contextFields; prev != nil {
+			o.contextFields = func(ctx context.Context, dst []Field) []Field {
+				return fn(ctx, prev(ctx, dst))
+			}
+			return
+		}
+		o.contextFields = fn
+	}
+}
+
+// withContextFields returns fields followed by the WithContextFields fields
+// of ctx. The caller's slice is never written to.
+func (l *Logger) withContextFields(ctx context.Context, fields []Field) []Field {
+	if l.opts.contextFields == nil || ctx == nil {
+		return fields
+	}
+	return l.opts.contextFields(ctx, fields[:len(fields):len(fields)])
+}
@@ -0,0 +1,81 @@
+// context_fields_test.go: Tests for fields derived from call contexts
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// traceKey stores the trace identifier of the tests.
+type traceKey struct{}
+
+// countingTraceFields returns a ContextFieldsFunc adding the traceKey value
+// as "trace_id", and the number of times it ran.
+func countingTraceFields() (ContextFieldsFunc, *int) {
+	calls := new(int)
+	return func(ctx context.Context, dst []Field) []Field {
+		*calls++
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			dst = append(dst, Str("trace_id", id))
+		}
+		return dst
+	}, calls
+}
+
+func TestWithContextFields(t *testing.T) {
+	fn, calls := countingTraceFields()
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true},
+		WithContextFields(fn),
+		WithContextFields(func(_ context.Context, dst []Field) []Field { return append(dst, Str("second", "yes")) }))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f3577b34da6")
+
+	fields := make([]Field, 1, 4)
+	fields[0] = Str("k", "v")
+	logger.InfoCtx(ctx, "with ctx", fields...)
+	logger.DebugCtx(ctx, "filtered")
+	logger.Info("without ctx")
+	cl := logger.WithContext(ctx)
+	cl.Info("context logger 1")
+	cl.Warn("context logger 2")
+	slog.New(NewSlogHandler(logger)).InfoContext(ctx, "from slog")
+
+	tests := []struct {
+		msg   string
+		trace bool
+	}{
+		{"with ctx", true},
+		{"without ctx", false},
+		{"context logger 1", true},
+		{"context logger 2", true},
+		{"from slog", true},
+	}
+	for _, tt := range tests {
+		line := findLine(out.String(), tt.msg)
+		if line == "" {
+			t.Errorf("%q not logged", tt.msg)
+			continue
+		}
+		got := strings.Contains(line, `"trace_id":"4bf92f3577b34da6"`) && strings.Contains(line, `"second":"yes"`)
+		if got != tt.trace {
+			t.Errorf("%q: context fields = %v, want %v: %s", tt.msg, got, tt.trace, line)
+		}
+	}
+	if *calls != 3 {
+		t.Errorf("fn ran %d times, want 3 (InfoCtx, WithContext, slog)", *calls)
+	}
+	if len(fields) != 1 || cap(fields) != 4 || fields[:2][1].K != "" {
+		t.Error("caller's field slice was written to")
+	}
+}
@@ -1395,3 +1395,63 @@ func TestContextLogger_AdvancedLevelFiltering(t *testing.T) {
 		})
 	}
 }
+
+// TestContextExtractor_SafeExtract tests the allow-list and size caps of SafeExtract
+func TestContextExtractor_SafeExtract(t *testing.T) {
+	type session struct{ Token, Email string }
+	const (
+		tenantKey  ContextKey = "tenant"
+		attemptKey ContextKey = "attempt"
+		blobKey    ContextKey = "blob"
+		objectKey  ContextKey = "object"
+	)
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, RequestIDKey, "req-1")
+	ctx = context.WithValue(ctx, tenantKey, "acme")
+	ctx = context.WithValue(ctx, attemptKey, 3)
+	ctx = context.WithValue(ctx, blobKey, strings.Repeat("x", DefaultMaxContextValueLen+1))
+	ctx = context.WithValue(ctx, objectKey, session{Token: "secret", Email: "a@b.c"})
+	ctx = context.WithValue(ctx, UserIDKey, "not-allow-listed")
+
+	keys := map[ContextKey]string{
+		RequestIDKey: "request_id",
+		tenantKey:    "tenant",
+		attemptKey:   "attempt",
+		blobKey:      "blob",
+		objectKey:    "object",
+	}
+
+	tests := []struct {
+		name      string
+		extractor *ContextExtractor
+		want      []string
+	}{
+		{"Defaults", NewSafeContextExtractor(keys), []string{"attempt", "request_id", "tenant"}},
+		{"MaxValueLen", &ContextExtractor{Keys: keys, SafeExtract: true, MaxValueLen: 4}, []string{"attempt", "tenant"}},
+		{"MaxFields", &ContextExtractor{Keys: keys, SafeExtract: true, MaxFields: 2}, []string{"attempt", "request_id"}},
+		{"LargeCapAcceptsBlob", &ContextExtractor{Keys: keys, SafeExtract: true, MaxValueLen: 1 << 10}, []string{"attempt", "blob", "request_id", "tenant"}},
+	}
+
+	logger := setupContextTestLogger(t)
+	defer safeCloseContextLogger(t, logger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := logger.WithContextExtractor(ctx, tt.extractor)
+			var got []string
+			for _, f := range cl.fields {
+				got = append(got, f.Key())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("extracted %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	cl := logger.WithContextExtractor(ctx, NewSafeContextExtractor(keys))
+	for _, f := range cl.fields {
+		if f.Key() == "attempt" && (f.Type() != kindInt64 || f.IntValue() != 3) {
+			t.Errorf("attempt extracted as %+v, want the integer 3", f)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// core_logger.go: Logging interface shared by the IRIS loggers
+//
+// Application code and libraries that only log and derive child loggers
+// can accept CoreLogger instead of a concrete logger type, and so work with
+// a Logger, a ContextLogger, an AutoScalingLogger or a NopLogger alike.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+// CoreLogger is the logging surface shared by *Logger, *ContextLogger,
+// *AutoScalingLogger and *NopLogger. Libraries accept it as a parameter or
+// struct field:
+//
+//	type Client struct {
+//		log iris.CoreLogger
+//	}
+//
+//	func NewClient(log iris.CoreLogger) *Client {
+//		return &Client{log: log.Named("client")}
+//	}
+//
+//	c := NewClient(logger.Core())                  // *Logger
+//	c := NewClient(logger.WithContext(ctx).Core()) // *ContextLogger
+//	c := NewClient(iris.NewNopLogger())            // Discards everything
+//
+// The concrete loggers keep With and Named returning their own type, so
+// they satisfy CoreLogger through their Core method; NopLogger implements
+// it directly.
+type CoreLogger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+
+	// With returns a logger adding fields to every record
+	With(fields ...Field) CoreLogger
+	// Named returns a logger with the given name
+	Named(name string) CoreLogger
+}
+
+// Compile-time checks that every logger provides a CoreLogger.
+var (
+	_ CoreLogger = coreLogger{}
+	_ CoreLogger = coreContextLogger{}
+	_ CoreLogger = coreAutoScalingLogger{}
+	_ CoreLogger = (*NopLogger)(nil)
+)
+
+// Core returns l as a CoreLogger.
+func (l *Logger) Core() CoreLogger {
+	return coreLogger{l}
+}
+
+// Core returns cl as a CoreLogger.
+func (cl *ContextLogger) Core() CoreLogger {
+	return coreContextLogger{cl}
+}
+
+// Core returns asl as a CoreLogger.
+func (asl *AutoScalingLogger) Core() CoreLogger {
+	return coreAutoScalingLogger{asl}
+}
+
+// coreLogger adapts *Logger to CoreLogger.
+type coreLogger struct{ l *Logger }
+
+func (c coreLogger) Debug(msg string, fields ...Field) { c.l.Debug(msg, fields...) }
+func (c coreLogger) Info(msg string, fields ...Field)  { c.l.Info(msg, fields...) }
+func (c coreLogger) Warn(msg string, fields ...Field)  { c.l.Warn(msg, fields...) }
+func (c coreLogger) Error(msg string, fields ...Field) { c.l.Error(msg, fields...) }
+
+func (c coreLogger) With(fields ...Field) CoreLogger { return coreLogger{c.l.With(fields...)} }
+func (c coreLogger) Named(name string) CoreLogger    { return coreLogger{c.l.Named(name)} }
+
+// coreContextLogger adapts *ContextLogger to CoreLogger.
+type coreContextLogger struct{ cl *ContextLogger }
+
+func (c coreContextLogger) Debug(msg string, fields ...Field) { c.cl.Debug(msg, fields...) }
+func (c coreContextLogger) Info(msg string, fields ...Field)  { c.cl.Info(msg, fields...) }
+func (c coreContextLogger) Warn(msg string, fields ...Field)  { c.cl.Warn(msg, fields...) }
+func (c coreContextLogger) Error(msg string, fields ...Field) { c.cl.Error(msg, fields...) }
+
+func (c coreContextLogger) With(fields ...Field) CoreLogger {
+	return coreContextLogger{c.cl.With(fields...)}
+}
+
+func (c coreContextLogger) Named(name string) CoreLogger {
+	return coreContextLogger{c.cl.Named(name)}
+}
+
+// coreAutoScalingLogger adapts *AutoScalingLogger to CoreLogger.
+type coreAutoScalingLogger struct{ asl *AutoScalingLogger }
+
+func (c coreAutoScalingLogger) Debug(msg string, fields ...Field) { c.asl.Debug(msg, fields...) }
+func (c coreAutoScalingLogger) Info(msg string, fields ...Field)  { c.asl.Info(msg, fields...) }
+func (c coreAutoScalingLogger) Warn(msg string, fields ...Field)  { c.asl.Warn(msg, fields...) }
+func (c coreAutoScalingLogger) Error(msg string, fields ...Field) { c.asl.Error(msg, fields...) }
+
+func (c coreAutoScalingLogger) With(fields ...Field) CoreLogger {
+	return coreAutoScalingLogger{c.asl.With(fields...)}
+}
+
+func (c coreAutoScalingLogger) Named(name string) CoreLogger {
+	return coreAutoScalingLogger{c.asl.Named(name)}
+}
+
+// NopLogger is a CoreLogger that discards every record. It is the default
+// for optional loggers in libraries and a stand-in for tests that do not
+// inspect output. The zero value is ready to use.
+type NopLogger struct{}
+
+// NewNopLogger returns a logger that discards every record.
+func NewNopLogger() *NopLogger {
+	return &NopLogger{}
+}
+
+// Debug discards the record.
+func (n *NopLogger) Debug(msg string, fields ...Field) {}
+
+// Info discards the record.
+func (n *NopLogger) Info(msg string, fields ...Field) {}
+
+// Warn discards the record.
+func (n *NopLogger) Warn(msg string, fields ...Field) {}
+
+// Error discards the record.
+func (n *NopLogger) Error(msg string, fields ...Field) {}
+
+// With returns n.
+func (n *NopLogger) With(fields ...Field) CoreLogger { return n }
+
+// Named returns n.
+func (n *NopLogger) Named(name string) CoreLogger { return n }
@@ -0,0 +1,140 @@
+// core_logger_test.go: Tests for the CoreLogger interface
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// coreClient is library code holding only the CoreLogger interface.
+type coreClient struct {
+	log CoreLogger
+}
+
+// logThroughCore logs through code that only knows the CoreLogger interface.
+func logThroughCore(log CoreLogger) {
+	c := coreClient{log: log.Named("svc").With(Str("component", "core"))}
+	c.log.Debug("filtered")
+	c.log.Info("core info")
+	c.log.Warn("core warn")
+	c.log.Error("core error")
+}
+
+func TestCoreLogger_Implementations(t *testing.T) {
+	cfg := func(out *testSyncer) Config {
+		return Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64}
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, out *testSyncer) func() error
+	}{
+		{"Logger", func(t *testing.T, out *testSyncer) func() error {
+			logger, err := New(cfg(out))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			logger.Start()
+			logThroughCore(logger.Core())
+			return logger.Close
+		}},
+		{"ContextLogger", func(t *testing.T, out *testSyncer) func() error {
+			logger, err := New(cfg(out))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			logger.Start()
+			ctx := context.WithValue(context.Background(), RequestIDKey, "req-1")
+			logThroughCore(logger.WithContext(ctx).Core())
+			return logger.Close
+		}},
+		{"AutoScalingLogger", func(t *testing.T, out *testSyncer) func() error {
+			logger, err := NewAutoScalingLogger(cfg(out), DefaultAutoScalingConfig())
+			if err != nil {
+				t.Fatalf("NewAutoScalingLogger failed: %v", err)
+			}
+			if err := logger.Start(); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			logThroughCore(logger.Core())
+			return logger.Close
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &testSyncer{}
+			closeLogger := tt.run(t, out)
+			if err := closeLogger(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			got := out.String()
+			if strings.Contains(got, "filtered") {
+				t.Errorf("Debug record written below the Info level:\n%s", got)
+			}
+			for _, msg := range []string{"core info", "core warn", "core error"} {
+				line := findLine(got, msg)
+				if line == "" {
+					t.Errorf("record %q missing:\n%s", msg, got)
+					continue
+				}
+				if !strings.Contains(line, `"component":"core"`) || !strings.Contains(line, "svc") {
+					t.Errorf("record %q lacks the With field or the name: %s", msg, line)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoScalingLogger_WithSharesScaling(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := NewAutoScalingLogger(Config{Level: Info, Output: out, Encoder: NewJSONEncoder()}, DefaultAutoScalingConfig())
+	if err != nil {
+		t.Fatalf("NewAutoScalingLogger failed: %v", err)
+	}
+	child := logger.With(Int("n", 1)).Named("child")
+	if err := child.Start(); err != nil {
+		t.Fatalf("Start through the child failed: %v", err)
+	}
+	child.Info("from child")
+	logger.Info("from root")
+
+	if got := logger.GetScalingStats().TotalWrites; got != 2 {
+		t.Errorf("root TotalWrites = %d, want 2", got)
+	}
+	if got := child.GetScalingStats().TotalWrites; got != 2 {
+		t.Errorf("child TotalWrites = %d, want 2", got)
+	}
+	if child.GetCurrentMode() != logger.GetCurrentMode() {
+		t.Errorf("child mode %v differs from root mode %v", child.GetCurrentMode(), logger.GetCurrentMode())
+	}
+	if logger.With() != logger {
+		t.Error("With without fields should return the logger itself")
+	}
+
+	if err := child.Close(); err != nil {
+		t.Fatalf("Close through the child failed: %v", err)
+	}
+	if line := findLine(out.String(), "from child"); !strings.Contains(line, `"n":1`) {
+		t.Errorf("child record lacks its field: %q", line)
+	}
+	if line := findLine(out.String(), "from root"); line == "" || strings.Contains(line, `"n":1`) {
+		t.Errorf("root record missing or carrying the child's field: %q", line)
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	var zero NopLogger
+	for _, n := range []*NopLogger{NewNopLogger(), &zero} {
+		logThroughCore(n)
+		if n.With(Str("k", "v")) != CoreLogger(n) || n.Named("x") != CoreLogger(n) {
+			t.Error("NopLogger should derive itself")
+		}
+	}
+}
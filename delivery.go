@@ -0,0 +1,80 @@
+// delivery.go: Delivery counters of asynchronous sinks in Logger.Stats
+//
+// Network sinks accept a record long before its destination does: the
+// logger only sees Write return. What happens next (the batch is sent,
+// acknowledged, retried or given up) is known to the sink alone. Sinks
+// implementing DeliveryReporter publish those counters, and Logger.Stats
+// reports them under a per-sink prefix, so one stats endpoint covers the
+// ring buffer and every hop after it.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import "strconv"
+
+// DeliveryStats are the delivery counters of a sink, in records.
+type DeliveryStats struct {
+	Sent    int64 // Handed to the destination (written to the connection, or in a request it answered)
+	Acked   int64 // Confirmed by the destination; stays 0 for transports without acknowledgements
+	Retried int64 // Delivery attempts repeated after a failure
+	Dropped int64 // Given up: undeliverable, rejected, or discarded by a full queue
+}
+
+// DeliveryReporter is implemented by sinks that deliver records
+// asynchronously and count what happened to them. GELFWriter, SyslogSyncer,
+// CloudWatchWriter and LokiWriter implement it; writers maintained as
+// separate modules (Kafka, Datadog) can implement it too.
+//
+// Logger.Stats reports the counters as "sink.<name>.sent", "acked",
+// "retried" and "dropped". Sinks are named after their place in the
+// logger: "output" for Config.Output, the sink name for pipeline sinks,
+// "output<N>" for the N-th output added with WithOutput, "restricted" for
+// the classification sink and "writer<N>" for the N-th WithSyncWriter
+// writer. Writers combined with MultiWriteSyncer get their position
+// appended ("output.2").
+//
+// DeliveryStats is called by Logger.Stats from any goroutine, so the
+// counters must be read atomically.
+type DeliveryReporter interface {
+	DeliveryStats() DeliveryStats
+}
+
+// addDeliveryStats adds the counters of every sink of the logger
+// implementing DeliveryReporter.
+func (l *Logger) addDeliveryStats(stats map[string]int64) {
+	addSinkDeliveryStats(stats, "output", l.out)
+	for i, o := range l.opts.outputs {
+		name := o.name
+		if name == "" {
+			name = "output" + strconv.Itoa(i+1)
+		}
+		addSinkDeliveryStats(stats, name, o.out)
+	}
+	if l.opts.classifier != nil && l.opts.classifier.restricted != nil {
+		addSinkDeliveryStats(stats, "restricted", l.opts.classifier.restricted)
+	}
+	for i, w := range l.opts.recordWriters {
+		addSinkDeliveryStats(stats, "writer"+strconv.Itoa(i+1), w)
+	}
+}
+
+// addSinkDeliveryStats adds the counters of sink, or of the writers it
+// combines, under "sink.<name>.".
+func addSinkDeliveryStats(stats map[string]int64, name string, sink interface{}) {
+	switch s := sink.(type) {
+	case DeliveryReporter:
+		d := s.DeliveryStats()
+		prefix := "sink." + name + "."
+		stats[prefix+"sent"] = d.Sent
+		stats[prefix+"acked"] = d.Acked
+		stats[prefix+"retried"] = d.Retried
+		stats[prefix+"dropped"] = d.Dropped
+	case *multiWS:
+		for i, w := range s.ws {
+			addSinkDeliveryStats(stats, name+"."+strconv.Itoa(i+1), w)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// delivery_test.go: Tests for sink delivery counters in Logger.Stats
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// reportingSink is a WriteSyncer with fixed delivery counters.
+type reportingSink struct {
+	bytes.Buffer
+	stats DeliveryStats
+}
+
+func (s *reportingSink) Sync() error                  { return nil }
+func (s *reportingSink) DeliveryStats() DeliveryStats { return s.stats }
+
+// reportingWriter is a SyncWriter counting the records it is given.
+type reportingWriter struct{ n atomic.Int64 }
+
+func (w *reportingWriter) WriteRecord(*Record) error { w.n.Add(1); return nil }
+func (w *reportingWriter) Close() error              { return nil }
+func (w *reportingWriter) DeliveryStats() DeliveryStats {
+	return DeliveryStats{Sent: w.n.Load(), Acked: w.n.Load()}
+}
+
+// sinkStats returns the "sink." keys of stats.
+func sinkStats(stats map[string]int64) map[string]int64 {
+	out := make(map[string]int64)
+	for k, v := range stats {
+		if strings.HasPrefix(k, "sink.") {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func TestLoggerStats_DeliveryStats(t *testing.T) {
+	main := &reportingSink{stats: DeliveryStats{Sent: 10, Acked: 8, Retried: 3, Dropped: 2}}
+	extra := &reportingSink{stats: DeliveryStats{Sent: 5}}
+	audit := &reportingSink{stats: DeliveryStats{Dropped: 1}}
+	restricted := &reportingSink{stats: DeliveryStats{Sent: 7}}
+	writer := &reportingWriter{}
+
+	logger, err := New(Config{Level: Info, Output: main, Encoder: NewJSONEncoder(), Inline: true},
+		WithOutput(NewJSONEncoder(), MultiWriteSyncer(WrapWriter(&bytes.Buffer{}), extra)),
+		withRoutedOutput(NewJSONEncoder(), audit, 0, "audit"),
+		WithClassification(ClassificationConfig{Restricted: restricted}),
+		WithSyncWriter(writer))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	logger.Info("shipped")
+
+	want := map[string]int64{
+		"sink.output.sent": 10, "sink.output.acked": 8, "sink.output.retried": 3, "sink.output.dropped": 2,
+		"sink.output1.2.sent": 5, "sink.output1.2.acked": 0, "sink.output1.2.retried": 0, "sink.output1.2.dropped": 0,
+		"sink.audit.sent": 0, "sink.audit.acked": 0, "sink.audit.retried": 0, "sink.audit.dropped": 1,
+		"sink.restricted.sent": 7, "sink.restricted.acked": 0, "sink.restricted.retried": 0, "sink.restricted.dropped": 0,
+		"sink.writer1.sent": 1, "sink.writer1.acked": 1, "sink.writer1.retried": 0, "sink.writer1.dropped": 0,
+	}
+	got := sinkStats(logger.Stats())
+	if len(got) != len(want) {
+		t.Errorf("sink stats = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %d, want %d", k, got[k], v)
+		}
+	}
+}
+
+func TestLoggerStats_NoDeliveryStats(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: WrapWriter(&bytes.Buffer{}), Encoder: NewJSONEncoder()})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	if got := sinkStats(logger.Stats()); len(got) != 0 {
+		t.Errorf("sink stats without reporting sinks = %v", got)
+	}
+}
+
+func TestNetworkWriters_DeliveryStats(t *testing.T) {
+	pc := listenGELFUDP(t)
+	gelf, err := NewGELFWriter(GELFConfig{Address: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewGELFWriter failed: %v", err)
+	}
+	defer func() { _ = gelf.Close() }()
+	client := &fakeCloudWatch{}
+	cw := newTestCloudWatch(t, client, CloudWatchConfig{})
+
+	logger, err := New(Config{Level: Info, Output: gelf, Encoder: &GELFEncoder{Host: "h"}, Inline: true},
+		WithOutput(NewJSONEncoder(), cw))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	logger.Info("one")
+	logger.Info("two")
+	readDatagram(t, pc)
+	readDatagram(t, pc)
+	_ = cw.Sync()
+
+	stats := logger.Stats()
+	if stats["sink.output.sent"] != 2 || stats["sink.output.acked"] != 0 || stats["sink.output.dropped"] != 0 {
+		t.Errorf("GELF stats = %v", sinkStats(stats))
+	}
+	if stats["sink.output1.sent"] != 2 || stats["sink.output1.acked"] != 2 || stats["sink.output1.retried"] != 0 {
+		t.Errorf("CloudWatch stats = %v", sinkStats(stats))
+	}
+
+	// Throttled once: retried, then accepted with one event rejected
+	client.mu.Lock()
+	client.errs = []error{&CloudWatchError{Code: "ThrottlingException", StatusCode: 400}}
+	client.mu.Unlock()
+	_, _ = cw.Write([]byte("three\n"))
+	_ = cw.Sync()
+	if d := cw.DeliveryStats(); d.Sent != 3 || d.Acked != 3 || d.Retried != 1 || d.Dropped != 0 {
+		t.Errorf("DeliveryStats() = %+v", d)
+	}
+}
@@ -0,0 +1,257 @@
+// drop.go: Drop reason accounting for Iris logging library
+//
+// A single aggregate "dropped" counter cannot tell a ring that is too small
+// from a sampler doing its job or a logger used after Close. Drops are
+// therefore counted per reason and reported both in Stats() (as
+// "dropped_<reason>" keys) and to an optional OnDrop callback.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// DropReason identifies why a record was not written.
+type DropReason uint8
+
+const (
+	// DropRingFull: the ring buffer had no free slot (DropOnFull policy)
+	DropRingFull DropReason = iota
+	// DropClosed: the logger was already closed
+	DropClosed
+	// DropSampled: the sampler rejected the record
+	DropSampled
+	// DropLevel: the record was below the minimum level. Only counted with
+	// WithLevelDropCounting, since level filtering is normally not a loss.
+	DropLevel
+	// DropMaxAge: the record exceeded its maximum age before being written
+	DropMaxAge
+	// DropBudget: a rate or volume budget was exhausted (e.g. a KeySampler
+	// bucket)
+	DropBudget
+	// DropCanceled: the caller's context was done while a BlockOnFull
+	// write waited for a free slot (InfoCtx, WriteCtx, ...)
+	DropCanceled
+	// DropFiltered: a pipeline stage discarded the record in the consumer
+	// (e.g. a dedupe processor of Config.Pipeline)
+	DropFiltered
+	// DropShutdown: Shutdown's deadline passed before the record was
+	// written
+	DropShutdown
+
+	dropReasonCount
+)
+
+// dropReasonNames holds the String and Stats key suffix for each reason.
+var dropReasonNames = [dropReasonCount]string{
+	DropRingFull: "ring_full",
+	DropClosed:   "closed",
+	DropSampled:  "sampled",
+	DropLevel:    "level",
+	DropMaxAge:   "max_age",
+	DropBudget:   "budget",
+	DropCanceled: "canceled",
+	DropFiltered: "filtered",
+	DropShutdown: "shutdown",
+}
+
+// String returns the reason name used in Stats keys (e.g. "ring_full").
+func (r DropReason) String() string {
+	if r < dropReasonCount {
+		return dropReasonNames[r]
+	}
+	return "unknown"
+}
+
+// DropHandler is called for every dropped record with its reason and level.
+//
+// It runs synchronously on the logging goroutine, often on a hot path that
+// is already under pressure, so it must be cheap and must not log through
+// the same logger.
+type DropHandler func(reason DropReason, level Level)
+
+// maxCounterStripes caps the number of stripes of a stripedCounter,
+// whatever GOMAXPROCS is.
+const maxCounterStripes = 32
+
+// stripedCounter holds a small set of counts for increments from many
+// goroutines at once.
+//
+// During a drop storm every producer counts every drop; with one atomic per
+// count they all serialize on the same cache line, slowing the logging calls
+// down exactly when they are already losing records. The counts are kept in
+// several stripes instead, each on its own cache lines, and each add picks a
+// stripe at random with the runtime's per-thread generator, so goroutines on
+// different Ps rarely touch the same line. Reads sum the stripes.
+type stripedCounter struct {
+	cells  []atomic.Int64 // stripes × stride, stripe-major
+	stride int            // Counts per stripe plus one cache line of padding
+	mask   uint32         // Stripes - 1
+}
+
+// newStripedCounter returns a counter for width counts with GOMAXPROCS
+// stripes, rounded up to a power of two and capped at maxCounterStripes.
+func newStripedCounter(width int) *stripedCounter {
+	stripes := 1
+	for stripes < runtime.GOMAXPROCS(0) && stripes < maxCounterStripes {
+		stripes <<= 1
+	}
+	stride := (width+7)&^7 + 8 // Whole cache lines of int64s, plus one
+	return &stripedCounter{
+		cells:  make([]atomic.Int64, stripes*stride),
+		stride: stride,
+		mask:   uint32(stripes - 1),
+	}
+}
+
+// add increments count i.
+func (c *stripedCounter) add(i int) {
+	c.cells[c.stripe()*c.stride+i].Add(1)
+}
+
+// stripe picks the stripe for the next update.
+func (c *stripedCounter) stripe() int {
+	if c.mask == 0 {
+		return 0
+	}
+	return int(rand.Uint32() & c.mask)
+}
+
+// addAt adds delta to count i on the given stripe. Callers that undo an
+// update on the stripe they made it on keep every stripe non-negative, so
+// a load of zero means no update is outstanding.
+func (c *stripedCounter) addAt(stripe, i int, delta int64) {
+	c.cells[stripe*c.stride+i].Add(delta)
+}
+
+// load returns count i summed over all stripes.
+func (c *stripedCounter) load(i int) int64 {
+	var total int64
+	for at := i; at < len(c.cells); at += c.stride {
+		total += c.cells[at].Load()
+	}
+	return total
+}
+
+// dropCounters holds per-reason counts shared by a logger and its clones.
+type dropCounters struct {
+	counts *stripedCounter
+}
+
+// newDropCounters returns zeroed per-reason counts.
+func newDropCounters() *dropCounters {
+	return &dropCounters{counts: newStripedCounter(int(dropReasonCount))}
+}
+
+// add counts one drop for reason.
+func (d *dropCounters) add(reason DropReason) {
+	d.counts.add(int(reason))
+}
+
+// load returns the drops counted for reason.
+func (d *dropCounters) load(reason DropReason) int64 {
+	return d.counts.load(int(reason))
+}
+
+// WithOnDrop registers a callback invoked for every dropped record.
+//
+// Parameters:
+//   - fn: Drop handler (nil disables the callback)
+//
+// Returns:
+//   - Option: Configuration function to set the drop handler
+//
+// Example:
+//
+//	logger, _ := iris.New(cfg, iris.WithOnDrop(func(reason iris.DropReason, level iris.Level) {
+//	    dropsMetric.WithLabelValues(reason.String()).Inc()
+//	}))
+func WithOnDrop(fn DropHandler) Option {
+	return func(o *loggerOptions) {
+		o.onDrop = fn
+	}
+}
+
+// WithLevelDropCounting counts records rejected by the level filter as
+// DropLevel drops (and reports them to the OnDrop callback).
+//
+// Disabled by default: filtered debug logging is usually intentional and
+// counting it adds an atomic increment to the disabled-level fast path.
+//
+// Returns:
+//   - Option: Configuration function to enable level drop counting
+func WithLevelDropCounting() Option {
+	return func(o *loggerOptions) {
+		o.countLevelDrops = true
+	}
+}
+
+// DroppedBy returns the number of records dropped for reason by this logger
+// and the loggers derived from the same root.
+func (l *Logger) DroppedBy(reason DropReason) int64 {
+	if l.drops == nil || reason >= dropReasonCount {
+		return 0
+	}
+	return l.drops.load(reason)
+}
+
+// recordDrop counts a drop and notifies the OnDrop callback.
+func (l *Logger) recordDrop(reason DropReason, level Level) {
+	if l.drops != nil {
+		l.drops.add(reason)
+	}
+	if l.summary != nil {
+		l.summary.add(l.name, level, reason)
+	}
+	if l.opts.onDrop != nil {
+		l.opts.onDrop(reason, level)
+	}
+	l.noteDrop(reason)
+}
+
+// recordRingDrop accounts for a failed ring write, telling a full ring
+// apart from a closed one.
+func (l *Logger) recordRingDrop(level Level) {
+	l.countDropped()
+	if l.r.Closed() {
+		l.recordDrop(DropClosed, level)
+		if l.opts.strictLifecycle {
+			l.reportClosed()
+		}
+	} else {
+		l.recordDrop(DropRingFull, level)
+	}
+}
+
+// countDropped increments the logger's aggregate "dropped" count. The
+// counter is allocated on the first drop, so loggers that never drop (most
+// clones made by With) do not pay for its stripes.
+func (l *Logger) countDropped() {
+	c := l.dropped.Load()
+	if c == nil {
+		l.dropped.CompareAndSwap(nil, newStripedCounter(1))
+		c = l.dropped.Load()
+	}
+	c.add(0)
+}
+
+// droppedCount returns the logger's aggregate "dropped" count.
+func (l *Logger) droppedCount() int64 {
+	if c := l.dropped.Load(); c != nil {
+		return c.load(0)
+	}
+	return 0
+}
+
+// addTo adds a "dropped_<reason>" key per reason to stats.
+func (d *dropCounters) addTo(stats map[string]int64) {
+	for i := DropReason(0); i < dropReasonCount; i++ {
+		stats["dropped_"+dropReasonNames[i]] = d.load(i)
+	}
+}
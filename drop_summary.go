@@ -0,0 +1,223 @@
+// drop_summary.go: Periodic drop summaries written to the log output
+//
+// Drop counters in Stats() reach metrics dashboards, but someone reading the
+// logs themselves cannot tell that records are missing. With
+// WithDropSummary the logger periodically writes a synthetic record per
+// logger name summarizing what was dropped since the previous summary, so
+// the loss is visible in the same place as the records that were kept.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DropSummaryMessage is the message of the records written by
+// WithDropSummary.
+const DropSummaryMessage = "records dropped"
+
+// maxDropSummaryKeys bounds the distinct (logger, level, reason) entries
+// kept between summaries; drops beyond it are merged into the
+// "(other)" logger.
+const maxDropSummaryKeys = 1024
+
+// dropSummaryKey groups pending drops.
+type dropSummaryKey struct {
+	logger string
+	level  Level
+	reason DropReason
+}
+
+// dropSummary accumulates drops between summaries. It is shared by a logger
+// and its clones.
+type dropSummary struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[dropSummaryKey]int64
+	since   time.Time // First pending drop
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// newDropSummary creates a summary that flushes every interval.
+func newDropSummary(interval time.Duration) *dropSummary {
+	return &dropSummary{
+		interval: interval,
+		pending:  make(map[dropSummaryKey]int64),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// add records one drop.
+func (s *dropSummary) add(logger string, level Level, reason DropReason) {
+	key := dropSummaryKey{logger: logger, level: level, reason: reason}
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.since = time.Now()
+	}
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= maxDropSummaryKeys {
+		key.logger = "(other)"
+	}
+	s.pending[key]++
+	s.mu.Unlock()
+}
+
+// WithDropSummary periodically writes a record summarizing the records
+// dropped since the previous summary.
+//
+// Every interval, if anything was dropped, one Warn record per logger name
+// is written with message DropSummaryMessage, Record.Logger set to that name
+// and these fields:
+//   - "dropped": total records dropped by that logger
+//   - "dropped_<level>": drops per level (e.g. "dropped_debug")
+//   - "reason_<reason>": drops per DropReason (e.g. "reason_ring_full")
+//   - "since": time of the first drop in the summary
+//
+// A summary that cannot be written (the ring is still full) is kept and
+// merged into the next one, so no drop goes unreported. A final summary is
+// written by Close. Summary records are never counted as drops themselves.
+//
+// Parameters:
+//   - interval: Time between summaries (<= 0 disables them)
+//
+// Returns:
+//   - Option: Configuration function to enable drop summaries
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithDropSummary(10*time.Second))
+//	// {"level":"warn","msg":"records dropped","logger":"api","dropped":1520,
+//	//  "dropped_info":1500,"dropped_debug":20,"reason_ring_full":1520,"since":"..."}
+func WithDropSummary(interval time.Duration) Option {
+	return func(o *loggerOptions) {
+		o.dropSummary = interval
+	}
+}
+
+// startDropSummary launches the summary goroutine once.
+func (l *Logger) startDropSummary() {
+	s := l.summary
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		go func() {
+			defer close(s.done)
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					l.flushDropSummary()
+				case <-s.quit:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// stopDropSummary stops the summary goroutine and writes a final summary.
+func (l *Logger) stopDropSummary() {
+	s := l.summary
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() { close(s.done) }) // Never started
+	s.stopOnce.Do(func() { close(s.quit) })
+	<-s.done
+	if !l.flushDropSummary() && l.r.state.Load() == int32(StateStarted) {
+		// The ring was full: let the consumer drain it, then retry once
+		_ = l.r.Flush()
+		l.flushDropSummary()
+	}
+}
+
+// flushDropSummary writes one summary record per logger name and reports
+// whether all of them were written. Groups that cannot be written are merged
+// back into the pending counts. The lock is not held while writing, so
+// producers dropping records never wait on a blocked write.
+func (l *Logger) flushDropSummary() bool {
+	s := l.summary
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return true
+	}
+	pending, since := s.pending, s.since
+	s.pending = make(map[dropSummaryKey]int64)
+	s.mu.Unlock()
+
+	groups := make(map[string][]dropSummaryKey)
+	for key := range pending {
+		groups[key.logger] = append(groups[key.logger], key)
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	written := true
+	for _, name := range names {
+		keys := groups[name]
+		var total int64
+		var byLevel [Fatal - Trace + 1]int64
+		var byReason [dropReasonCount]int64
+		for _, key := range keys {
+			n := pending[key]
+			total += n
+			if key.level >= Trace && key.level <= Fatal {
+				byLevel[key.level-Trace] += n
+			}
+			if key.reason < dropReasonCount {
+				byReason[key.reason] += n
+			}
+		}
+		ok := l.write(func(rec *Record) {
+			rec.Level = Warn
+			rec.Msg = DropSummaryMessage
+			rec.Logger = name
+			rec.AddField(Int64("dropped", total))
+			for i, n := range byLevel {
+				if n > 0 {
+					rec.AddField(Int64("dropped_"+(Trace+Level(i)).String(), n))
+				}
+			}
+			for i, n := range byReason {
+				if n > 0 {
+					rec.AddField(Int64("reason_"+DropReason(i).String(), n))
+				}
+			}
+			rec.AddField(Time("since", since))
+		})
+		if !ok {
+			s.restore(pending, keys, since)
+			written = false
+		}
+	}
+	return written
+}
+
+// restore merges unwritten counts back into the pending summary.
+func (s *dropSummary) restore(pending map[dropSummaryKey]int64, keys []dropSummaryKey, since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 || since.Before(s.since) {
+		s.since = since
+	}
+	for _, key := range keys {
+		s.pending[key] += pending[key]
+	}
+}
@@ -0,0 +1,132 @@
+// drop_summary_test.go: Tests for periodic drop summaries
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dropSummaries returns the summary records in a JSON output.
+func dropSummaries(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+	var out []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		if rec["msg"] == DropSummaryMessage {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func TestDropSummary_WrittenOnClose(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Debug, Output: out, Encoder: NewJSONEncoder(), Capacity: 16, BatchSize: 8, AutoStart: AutoStartOff},
+		WithDropSummary(time.Hour))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Fill the ring, then overflow it from two loggers
+	api := logger.Named("api")
+	for logger.Info("fill") {
+	}
+	for i := 0; i < 4; i++ {
+		api.Debug("lost")
+	}
+	api.Error("lost")
+
+	// The ring is full: the summary stays pending instead of being lost
+	logger.flushDropSummary()
+	if got := len(logger.summary.pending); got == 0 {
+		t.Fatal("unwritten summary was discarded")
+	}
+
+	logger.Start()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	summaries := dropSummaries(t, out.String())
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2 (root and api):\n%s", len(summaries), out.String())
+	}
+	root, apiSummary := summaries[0], summaries[1]
+	if root["logger"] != nil {
+		root, apiSummary = apiSummary, root
+	}
+	if apiSummary["logger"] != "api" {
+		t.Fatalf("summary logger = %v, want api", apiSummary["logger"])
+	}
+	want := map[string]float64{"dropped": 5, "dropped_debug": 4, "dropped_error": 1, "reason_ring_full": 5}
+	for key, n := range want {
+		if apiSummary[key] != n {
+			t.Errorf("api summary %s = %v, want %v", key, apiSummary[key], n)
+		}
+	}
+	if root["dropped"] != 1.0 || root["dropped_info"] != 1.0 || root["level"] != "warn" {
+		t.Errorf("root summary = %v, want one dropped info record", root)
+	}
+	if _, ok := apiSummary["since"]; !ok {
+		t.Error("summary has no since field")
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 6 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 6 (summaries are not drops)", got)
+	}
+}
+
+func TestDropSummary_Periodic(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithDropSummary(10*time.Millisecond), WithLevelDropCounting())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Debug("filtered")
+	logger.Debug("filtered")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(dropSummaries(t, out.String())) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no summary written within 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond) // Several more intervals without drops
+
+	summaries := dropSummaries(t, out.String())
+	if len(summaries) != 1 {
+		t.Fatalf("got %d summaries, want 1 (no drops after the first)", len(summaries))
+	}
+	if summaries[0]["dropped"] != 2.0 || summaries[0]["reason_level"] != 2.0 {
+		t.Errorf("summary = %v, want 2 level drops", summaries[0])
+	}
+}
+
+func TestDropSummary_Disabled(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithDropSummary(0))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	if logger.summary != nil || logger.With(Str("k", "v")).summary != nil {
+		t.Error("drop summary enabled with a zero interval")
+	}
+}
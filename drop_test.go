@@ -0,0 +1,270 @@
+// drop_test.go: Tests for drop reason accounting
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"testing"
+)
+
+// dropRecorder collects OnDrop notifications
+type dropRecorder struct {
+	mu     sync.Mutex
+	counts map[DropReason]int
+	levels []Level
+}
+
+func (d *dropRecorder) handle(reason DropReason, level Level) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.counts == nil {
+		d.counts = make(map[DropReason]int)
+	}
+	d.counts[reason]++
+	d.levels = append(d.levels, level)
+}
+
+func (d *dropRecorder) count(reason DropReason) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.counts[reason]
+}
+
+func TestDropReason_String(t *testing.T) {
+	tests := []struct {
+		reason DropReason
+		want   string
+	}{
+		{DropRingFull, "ring_full"},
+		{DropClosed, "closed"},
+		{DropSampled, "sampled"},
+		{DropLevel, "level"},
+		{DropMaxAge, "max_age"},
+		{DropBudget, "budget"},
+		{DropCanceled, "canceled"},
+		{DropReason(200), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.reason.String(); got != tt.want {
+			t.Errorf("DropReason(%d).String() = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestDrops_Sampled(t *testing.T) {
+	rec := &dropRecorder{}
+	logger, err := New(Config{
+		Level:    Info,
+		Output:   &testSyncer{},
+		Encoder:  NewJSONEncoder(),
+		Capacity: 1024,
+		Sampler:  NewDynamicSampler(0),
+	}, WithOnDrop(rec.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("sampled out")
+	logger.With(Str("k", "v")).Warn("sampled out too")
+	logger.Errorf("formatted %d", 1)
+	logger.Error("kept", NoSample())
+	logger.Debug("below level") // Not counted by default
+
+	if got := logger.DroppedBy(DropSampled); got != 3 {
+		t.Errorf("DroppedBy(DropSampled) = %d, want 3", got)
+	}
+	if got := logger.DroppedBy(DropLevel); got != 0 {
+		t.Errorf("DroppedBy(DropLevel) = %d, want 0 without WithLevelDropCounting", got)
+	}
+	if rec.count(DropSampled) != 3 {
+		t.Errorf("OnDrop saw %d sampled drops, want 3", rec.count(DropSampled))
+	}
+	if rec.levels[0] != Info || rec.levels[1] != Warn || rec.levels[2] != Error {
+		t.Errorf("unexpected drop levels %v", rec.levels)
+	}
+
+	stats := logger.Stats()
+	if stats["dropped_sampled"] != 3 || stats["dropped_ring_full"] != 0 {
+		t.Errorf("unexpected drop stats: %v", stats)
+	}
+}
+
+func TestDrops_LevelCounting(t *testing.T) {
+	rec := &dropRecorder{}
+	logger, err := New(Config{
+		Level:    Warn,
+		Output:   &testSyncer{},
+		Encoder:  NewJSONEncoder(),
+		Capacity: 1024,
+	}, WithLevelDropCounting(), WithOnDrop(rec.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Debug("filtered")
+	logger.Named("child").Info("filtered")
+	logger.Warn("kept")
+
+	if got := logger.DroppedBy(DropLevel); got != 2 {
+		t.Errorf("DroppedBy(DropLevel) = %d, want 2", got)
+	}
+	if rec.count(DropLevel) != 2 {
+		t.Errorf("OnDrop saw %d level drops, want 2", rec.count(DropLevel))
+	}
+}
+
+func TestDrops_RingFullAndClosed(t *testing.T) {
+	rec := &dropRecorder{}
+	logger, err := New(Config{
+		Level:     Info,
+		Output:    &testSyncer{},
+		Encoder:   NewJSONEncoder(),
+		Capacity:  64,
+		AutoStart: AutoStartOff,
+	}, WithOnDrop(rec.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Not started: the ring fills up after 64 records
+	for i := 0; i < 66; i++ {
+		logger.Info("fill")
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 2 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 2", got)
+	}
+
+	logger.r.Close()
+	logger.With(Int("n", 1)).Error("after close")
+	if got := logger.DroppedBy(DropClosed); got != 1 {
+		t.Errorf("DroppedBy(DropClosed) = %d, want 1", got)
+	}
+
+	stats := logger.Stats()
+	if stats["dropped_ring_full"] != 2 || stats["dropped_closed"] != 1 {
+		t.Errorf("unexpected drop stats: %v", stats)
+	}
+	if rec.count(DropRingFull) != 2 || rec.count(DropClosed) != 1 {
+		t.Errorf("unexpected OnDrop counts: %v", rec.counts)
+	}
+	if got := logger.DroppedBy(DropReason(200)); got != 0 {
+		t.Errorf("DroppedBy(invalid) = %d, want 0", got)
+	}
+}
+
+func TestDrops_WriteRingFull(t *testing.T) {
+	rec := &dropRecorder{}
+	logger, err := New(Config{
+		Output:    &testSyncer{},
+		Encoder:   NewJSONEncoder(),
+		Capacity:  8,
+		BatchSize: 8,
+		AutoStart: AutoStartOff,
+	}, WithOnDrop(rec.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	fill := func(r *Record) { r.Level, r.Msg = Info, "fill" }
+	queued := 0
+	for i := 0; i < 20; i++ {
+		if logger.Write(fill) {
+			queued++
+		}
+	}
+	if queued != 8 {
+		t.Fatalf("Write queued %d records, want 8", queued)
+	}
+	stats := logger.Stats()
+	if stats["dropped"] != 12 || stats["dropped_ring_full"] != 12 {
+		t.Errorf("unexpected drop stats: %v", stats)
+	}
+	if rec.count(DropRingFull) != 12 {
+		t.Errorf("OnDrop(DropRingFull) called %d times, want 12", rec.count(DropRingFull))
+	}
+
+	logger.r.Close()
+	logger.Write(fill)
+	if got := logger.DroppedBy(DropClosed); got != 1 {
+		t.Errorf("DroppedBy(DropClosed) = %d, want 1", got)
+	}
+}
+
+func TestStripedCounter_ConcurrentAdds(t *testing.T) {
+	const goroutines, adds = 16, 1000
+	for _, width := range []int{1, int(dropReasonCount), 9} {
+		c := newStripedCounter(width)
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < adds; i++ {
+					c.add(i % width)
+				}
+			}()
+		}
+		wg.Wait()
+
+		var total int64
+		for i := 0; i < width; i++ {
+			total += c.load(i)
+		}
+		if total != goroutines*adds {
+			t.Errorf("width %d: counts sum to %d, want %d", width, total, goroutines*adds)
+		}
+		if got, want := c.load(0), int64(goroutines*((adds+width-1)/width)); got != want {
+			t.Errorf("width %d: load(0) = %d, want %d", width, got, want)
+		}
+	}
+}
+
+func TestDrops_AggregateCountPerLogger(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	child := logger.With(Str("k", "v"))
+	if logger.Stats()["dropped"] != 0 || child.dropped.Load() != nil {
+		t.Fatal("dropped counter allocated before the first drop")
+	}
+	for i := 0; i < 70; i++ {
+		child.Info("fill")
+	}
+	if got := child.Stats()["dropped"]; got != 6 {
+		t.Errorf("child dropped = %d, want 6", got)
+	}
+	if got := logger.Stats()["dropped"]; got != 0 {
+		t.Errorf("root dropped = %d, want 0", got)
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 6 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 6 (shared with clones)", got)
+	}
+}
+
+func BenchmarkDrops_ParallelRingFull(b *testing.B) {
+	captureErrors(b) // Silence the not-started diagnostic
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 64; i++ {
+		logger.Info("fill")
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("dropped")
+		}
+	})
+}
@@ -10,11 +10,20 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// isolateConfigAudit runs t in a temporary directory, so that the audit
+// trail the watchers write to the working directory stays out of the tree.
+func isolateConfigAudit(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+}
+
 // TestDynamicConfigWatcher tests the dynamic config watcher API
 func TestDynamicConfigWatcher(t *testing.T) {
+	isolateConfigAudit(t)
 	// Create temporary config file
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "test_config.json")
@@ -94,6 +103,7 @@ func TestDynamicConfigWatcher(t *testing.T) {
 
 // TestEnableDynamicLevel tests the convenience function
 func TestEnableDynamicLevel(t *testing.T) {
+	isolateConfigAudit(t)
 	// Create temporary config file
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "dynamic_test.json")
@@ -171,3 +181,101 @@ func TestAtomicLevelIntegration(t *testing.T) {
 		t.Error("AtomicLevel and Logger level are not synchronized")
 	}
 }
+
+// TestDynamicConfigWatcher_SampleRate tests that sample_rate is applied to an attached sampler
+func TestDynamicConfigWatcher_SampleRate(t *testing.T) {
+	isolateConfigAudit(t)
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "sample_config.json")
+	if err := os.WriteFile(configPath, []byte(`{"level":"info","sample_rate":0.2}`), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfigFromJSON(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	ds, ok := cfg.Sampler.(*DynamicSampler)
+	if !ok || ds.Rate() != 0.2 {
+		t.Fatalf("expected DynamicSampler with rate 0.2, got %#v", cfg.Sampler)
+	}
+
+	sampler := NewDynamicSampler(1.0)
+	logger, err := New(Config{Level: Info, Encoder: NewTextEncoder(), Capacity: 1024, Sampler: sampler})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	watcher, err := EnableDynamicLevel(logger, configPath)
+	if err != nil {
+		t.Fatalf("EnableDynamicLevel failed: %v", err)
+	}
+	defer func() { _ = watcher.Stop() }()
+
+	// Initial load applies the configured rate
+	if sampler.Rate() != 0.2 {
+		t.Errorf("expected sampler rate 0.2 after start, got %v", sampler.Rate())
+	}
+
+	// Configs without sample_rate leave the current rate unchanged
+	watcher.applySampleRate(&Config{Level: Info})
+	if sampler.Rate() != 0.2 {
+		t.Errorf("expected rate unchanged, got %v", sampler.Rate())
+	}
+}
+
+// TestLoadConfigFromEnv_SampleRate tests IRIS_SAMPLE_RATE
+func TestLoadConfigFromEnv_SampleRate(t *testing.T) {
+	t.Setenv("IRIS_SAMPLE_RATE", "0.5")
+	cfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	ds, ok := cfg.Sampler.(*DynamicSampler)
+	if !ok || ds.Rate() != 0.5 {
+		t.Errorf("expected DynamicSampler with rate 0.5, got %#v", cfg.Sampler)
+	}
+}
+
+func TestDynamicConfigWatcher_Fields(t *testing.T) {
+	isolateConfigAudit(t)
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "fields_config.json")
+	if err := os.WriteFile(configPath, []byte(`{"level":"info","fields":{"service":"checkout"}}`), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	cfg, err := LoadConfigFromJSON(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	logger, buf := fieldsLogger(t, cfg.Fields...)
+
+	watcher, err := EnableDynamicLevel(logger, configPath)
+	if err != nil {
+		t.Fatalf("EnableDynamicLevel failed: %v", err)
+	}
+	defer func() { _ = watcher.Stop() }()
+
+	// A reload adding a field applies it to the next records
+	watcher.applyFields(&Config{Fields: []Field{Str("deployment_color", "green"), Str("service", "checkout")}})
+	logger.Info("canary")
+	if line := lastLine(buf); !strings.Contains(line, `"deployment_color":"green","service":"checkout"`) {
+		t.Errorf("record = %s", line)
+	}
+
+	// Fields set in code are kept while the file's fields do not change
+	logger.SetFields(Str("service", "checkout"), Str("owner", "code"))
+	watcher.applyFields(&Config{Level: Warn, Fields: []Field{Str("deployment_color", "green"), Str("service", "checkout")}})
+	logger.Info("unchanged")
+	if line := lastLine(buf); !strings.Contains(line, `"owner":"code"`) {
+		t.Errorf("record = %s", line)
+	}
+
+	// Removing the object removes the fields
+	watcher.applyFields(&Config{})
+	logger.Info("done")
+	if line := lastLine(buf); strings.Contains(line, "service") || strings.Contains(line, "deployment_color") {
+		t.Errorf("record = %s", line)
+	}
+}
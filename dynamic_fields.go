@@ -0,0 +1,78 @@
+// dynamic_fields.go: Runtime replacement of the Config.Fields base fields
+//
+// Config.Fields are fixed when the logger is created, yet some context
+// changes while the process runs: the color of a blue/green deployment, a
+// canary flag, the current maintenance window. SetFields replaces them for
+// the whole logger family at once; loggers derived with With keep their own
+// fields after them. Each logger rebuilds its base fields once per change,
+// so the write path only pays an atomic load.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import "sync/atomic"
+
+// dynamicFields holds the fields set with SetFields, shared by a logger and
+// its clones. A nil set means Config.Fields are still in effect.
+type dynamicFields struct {
+	set atomic.Pointer[[]Field]
+}
+
+// mergedFields caches the base fields of a logger for one SetFields call.
+type mergedFields struct {
+	set    *[]Field // The set the fields were built from
+	fields []Field
+}
+
+// SetFields replaces the fields given in Config.Fields with fields, for
+// this logger and every logger derived from the same root. Records
+// enqueued afterwards carry the new fields, in place of the old ones and
+// before those added with With; records already in the ring buffer are
+// not changed. Calling SetFields without fields removes them all.
+//
+// DynamicConfigWatcher calls SetFields when the "fields" object of the
+// watched file changes (see EnableDynamicLevel).
+//
+// Parameters:
+//   - fields: New static fields; the slice is copied
+//
+// Example (canary rollout):
+//
+//	logger.SetFields(iris.Str("service", "checkout"), iris.Str("deployment_color", "green"))
+//
+// Thread Safety: Safe to call concurrently with logging
+func (l *Logger) SetFields(fields ...Field) {
+	if l.dynFields == nil {
+		return
+	}
+	set := append([]Field{}, fields...)
+	l.dynFields.set.Store(&set)
+}
+
+// currentBaseFields returns the base fields of the next record: baseFields
+// with the leading Config.Fields replaced by the fields last passed to
+// SetFields.
+func (l *Logger) currentBaseFields() []Field {
+	if l.dynFields != nil {
+		if set := l.dynFields.set.Load(); set != nil {
+			return l.mergeBaseFields(set)
+		}
+	}
+	return l.baseFields
+}
+
+// mergeBaseFields returns the base fields of the logger for set, building
+// them on the first call after a change.
+func (l *Logger) mergeBaseFields(set *[]Field) []Field {
+	if m := l.merged.Load(); m != nil && m.set == set {
+		return m.fields
+	}
+	own := l.baseFields[l.configFields:]
+	fields := make([]Field, 0, len(*set)+len(own))
+	fields = append(append(fields, *set...), own...)
+	l.merged.Store(&mergedFields{set: set, fields: fields})
+	return fields
+}
@@ -0,0 +1,131 @@
+// dynamic_fields_test.go: Tests for runtime replacement of base fields
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fieldsLogger returns an inline JSON logger with the given Config.Fields.
+func fieldsLogger(t *testing.T, fields ...Field) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	logger, err := New(Config{Level: Info, Output: WrapWriter(buf), Encoder: NewJSONEncoder(), Inline: true, Fields: fields})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, buf
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// lastLine returns the last record written to buf.
+func lastLine(buf *bytes.Buffer) string {
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	return lines[len(lines)-1]
+}
+
+func TestLogger_SetFields(t *testing.T) {
+	logger, buf := fieldsLogger(t, Str("service", "checkout"))
+	child := logger.With(Str("component", "cart"))
+	named := child.Named("api")
+
+	logger.Info("before")
+	if line := lastLine(buf); !strings.Contains(line, `"service":"checkout"`) {
+		t.Fatalf("record = %s", line)
+	}
+
+	// Replaced for the whole family, before the fields added with With
+	logger.SetFields(Str("service", "checkout"), Str("deployment_color", "green"))
+	named.Info("canary")
+	want := `"service":"checkout","deployment_color":"green","component":"cart"`
+	if line := lastLine(buf); !strings.Contains(line, want) {
+		t.Errorf("record = %s, want %s", line, want)
+	}
+
+	// Clones created after the change see it too
+	child.With(Int("attempt", 2)).Info("retry")
+	if line := lastLine(buf); !strings.Contains(line, `"deployment_color":"green","component":"cart","attempt":2`) {
+		t.Errorf("record = %s", line)
+	}
+
+	// Without fields, only those of With remain
+	child.SetFields()
+	logger.Info("bare")
+	child.Info("bare child")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if bare := lines[len(lines)-2]; strings.Contains(bare, "service") || strings.Contains(bare, "deployment_color") {
+		t.Errorf("record = %s, want no base fields", bare)
+	}
+	if line := lastLine(buf); strings.Contains(line, "service") || !strings.Contains(line, `"component":"cart"`) {
+		t.Errorf("record = %s", line)
+	}
+}
+
+func TestLogger_SetFieldsWithoutConfigFields(t *testing.T) {
+	logger, buf := fieldsLogger(t)
+	child := logger.With(Str("request_id", "r1"))
+
+	fields := []Field{Str("region", "eu")}
+	logger.SetFields(fields...)
+	fields[0] = Str("region", "modified") // SetFields keeps a copy
+	child.Info("hello")
+	if line := lastLine(buf); !strings.Contains(line, `"region":"eu","request_id":"r1"`) {
+		t.Errorf("record = %s", line)
+	}
+}
+
+func TestLogger_SetFieldsConcurrent(t *testing.T) {
+	out := &lockedBuffer{}
+	logger, err := New(Config{Level: Info, Output: WrapWriter(out), Encoder: NewJSONEncoder(), Fields: []Field{Str("color", "blue")}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := logger.With(Int("g", 1))
+			for i := 0; i < 200; i++ {
+				child.Info("tick")
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			logger.SetFields(Str("color", "green"))
+		} else {
+			logger.SetFields(Str("color", "blue"))
+		}
+	}
+	wg.Wait()
+	_ = logger.Close()
+
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSpace(out.buf.String()), "\n") {
+		if strings.Count(line, `"color"`) != 1 {
+			t.Fatalf("record = %s, want exactly one color", line)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// emergency.go: Emergency flush of error records under memory pressure
+//
+// When a container approaches its memory limit, the kernel OOM killer ends
+// the process without a chance to run deferred Close calls, and whatever is
+// still in the ring buffer is lost - usually including the errors that
+// explain why memory ran out. An emergency flush drains the ring
+// synchronously, writing the Error and above records first so they reach
+// the outputs before the rest, and syncs the outputs to disk.
+// WithEmergencyFlush triggers it on memory pressure reported by the kernel
+// (PSI) or by the application.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default PSI trigger for WithEmergencyFlush: memory stalls of 100ms within
+// a 2s window. Unprivileged processes may only use windows that are
+// multiples of 2s.
+const (
+	DefaultEmergencyStall  = 100 * time.Millisecond
+	DefaultEmergencyWindow = 2 * time.Second
+)
+
+// EmergencyFlushConfig configures WithEmergencyFlush.
+type EmergencyFlushConfig struct {
+	// PSI enables the kernel memory pressure trigger (Linux 5.2+ with
+	// pressure stall information). It watches the memory.pressure file of
+	// the process's cgroup v2, or /proc/pressure/memory outside a cgroup.
+	// If PSI is not available an ErrCodeResourceLimit error is reported to
+	// the error handler once and only Signal triggers flushes.
+	PSI bool
+
+	// Stall and Window define the PSI trigger: a flush starts when tasks
+	// were stalled on memory for Stall within Window
+	// (default DefaultEmergencyStall and DefaultEmergencyWindow).
+	Stall  time.Duration
+	Window time.Duration
+
+	// PressureFile overrides the PSI file to watch.
+	PressureFile string
+
+	// Signal triggers a flush on every receive, for applications with
+	// their own memory monitoring (e.g. a runtime/metrics watcher or a
+	// container runtime notification). Optional.
+	Signal <-chan struct{}
+}
+
+// emergencyState is the emergency flush state shared by a logger and its
+// clones.
+type emergencyState struct {
+	mu      sync.Mutex // Serializes flushes
+	flushes atomic.Int64
+
+	cfg       *EmergencyFlushConfig // nil = no watcher
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// newEmergencyState creates the state for cfg (nil without WithEmergencyFlush).
+func newEmergencyState(cfg *EmergencyFlushConfig) *emergencyState {
+	return &emergencyState{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// WithEmergencyFlush runs EmergencyFlush when the process comes under
+// memory pressure, so that error records still in the ring reach the disk
+// before an OOM kill.
+//
+// Pressure is detected through Linux PSI (cfg.PSI) and/or an application
+// signal (cfg.Signal). Watching starts with the logger and stops on Close.
+// Flushing is best effort: it cannot help if the process is killed before
+// the trigger fires, and a burst of pressure may trigger several flushes.
+//
+// Parameters:
+//   - cfg: Triggers to watch
+//
+// Returns:
+//   - Option: Configuration function to enable emergency flushes
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithEmergencyFlush(iris.EmergencyFlushConfig{PSI: true}))
+func WithEmergencyFlush(cfg EmergencyFlushConfig) Option {
+	return func(o *loggerOptions) {
+		if cfg.Stall <= 0 {
+			cfg.Stall = DefaultEmergencyStall
+		}
+		if cfg.Window <= 0 {
+			cfg.Window = DefaultEmergencyWindow
+		}
+		o.emergency = &cfg
+	}
+}
+
+// EmergencyFlush synchronously drains the ring buffer and syncs every
+// output. The consumer writes the pending records at Error level and above
+// first, then the others in their original order; nothing is discarded.
+//
+// Records logged while the flush runs are written after the prioritized
+// ones, like any other record. Concurrent calls are serialized. It must not
+// be called from a hook, which runs on the consumer goroutine the flush
+// waits for.
+//
+// Returns:
+//   - error: ErrLoggerClosed after Close, otherwise the first flush or sync
+//     error
+func (l *Logger) EmergencyFlush() error {
+	if l.r.Closed() {
+		return ErrLoggerClosed
+	}
+	e := l.emergency
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushes.Add(1)
+	l.r.prioritize(emergencyFirst)
+	return l.sync()
+}
+
+// emergencyFirst selects the records an emergency flush writes first.
+func emergencyFirst(rec *Record) bool {
+	return rec.Level.Enabled(Error)
+}
+
+// startEmergencyWatch launches the trigger goroutine once.
+func (l *Logger) startEmergencyWatch() {
+	e := l.emergency
+	if e.cfg == nil {
+		return
+	}
+	e.startOnce.Do(func() {
+		var pressure <-chan struct{}
+		var stopPSI func()
+		if e.cfg.PSI {
+			ch, stop, err := watchMemoryPressure(e.cfg)
+			if err != nil {
+				l.reportError(WrapLoggerError(err, ErrCodeResourceLimit, "memory pressure monitoring unavailable"))
+			} else {
+				pressure, stopPSI = ch, stop
+			}
+		}
+		signal := e.cfg.Signal
+		go func() {
+			defer close(e.done)
+			if stopPSI != nil {
+				defer stopPSI()
+			}
+			for {
+				select {
+				case _, ok := <-pressure:
+					if !ok {
+						pressure = nil // Watcher failed (e.g. cgroup removed)
+						continue
+					}
+				case _, ok := <-signal:
+					if !ok {
+						signal = nil
+						continue
+					}
+				case <-e.quit:
+					return
+				}
+				if err := l.EmergencyFlush(); err != nil && err != ErrLoggerClosed {
+					l.reportError(WrapLoggerError(err, ErrCodeFlushFailed, "emergency flush failed"))
+				}
+			}
+		}()
+	})
+}
+
+// stopEmergencyWatch stops the trigger goroutine.
+func (l *Logger) stopEmergencyWatch() {
+	e := l.emergency
+	if e.cfg == nil {
+		return
+	}
+	e.startOnce.Do(func() { close(e.done) }) // Never started
+	e.stopOnce.Do(func() { close(e.quit) })
+	<-e.done
+}
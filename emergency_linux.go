@@ -0,0 +1,118 @@
+// emergency_linux.go: Memory pressure notifications through PSI triggers
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package iris
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// watchMemoryPressure registers a PSI trigger and returns a channel that
+// receives a value each time it fires. The channel is closed if the watch
+// fails later (e.g. the cgroup is removed); stop ends the watch.
+func watchMemoryPressure(cfg *EmergencyFlushConfig) (<-chan struct{}, func(), error) {
+	path := cfg.PressureFile
+	if path == "" {
+		path = memoryPressureFile()
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0) // #nosec G304 -- PSI file from cgroupfs/procfs or explicit configuration
+	if err != nil {
+		return nil, nil, err
+	}
+	// The kernel expects the trigger string with its NUL terminator
+	trigger := fmt.Sprintf("some %d %d\x00", cfg.Stall.Microseconds(), cfg.Window.Microseconds())
+	if _, err := f.Write([]byte(trigger)); err != nil {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("register PSI trigger on %s: %w", path, err)
+	}
+
+	epfd, err := syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	var wake [2]int
+	if err := syscall.Pipe2(wake[:], syscall.O_CLOEXEC|syscall.O_NONBLOCK); err != nil {
+		_ = syscall.Close(epfd)
+		_ = f.Close()
+		return nil, nil, err
+	}
+	cleanup := func() {
+		_ = syscall.Close(epfd)
+		_ = syscall.Close(wake[0])
+		_ = f.Close()
+	}
+	fd := int(f.Fd()) // #nosec G115 -- file descriptors fit in int32
+	for _, reg := range []struct {
+		fd     int
+		events uint32
+	}{{fd, syscall.EPOLLPRI}, {wake[0], syscall.EPOLLIN}} {
+		ev := syscall.EpollEvent{Events: reg.events, Fd: int32(reg.fd)} // #nosec G115 -- file descriptors fit in int32
+		if err := syscall.EpollCtl(epfd, syscall.EPOLL_CTL_ADD, reg.fd, &ev); err != nil {
+			cleanup()
+			_ = syscall.Close(wake[1])
+			return nil, nil, err
+		}
+	}
+
+	ch := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(ch)
+		defer cleanup()
+		events := make([]syscall.EpollEvent, 2)
+		for {
+			n, err := syscall.EpollWait(epfd, events, -1)
+			if err == syscall.EINTR {
+				continue
+			}
+			if err != nil {
+				return
+			}
+			for _, ev := range events[:n] {
+				if int(ev.Fd) == wake[0] || ev.Events&syscall.EPOLLERR != 0 {
+					return // Stopped, or the pressure file went away
+				}
+				select {
+				case ch <- struct{}{}:
+				default: // A flush is already pending
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		_, _ = syscall.Write(wake[1], []byte{0})
+		<-done
+		_ = syscall.Close(wake[1])
+	}
+	return ch, stop, nil
+}
+
+// memoryPressureFile returns the memory.pressure file of the process's
+// cgroup v2, or the system-wide /proc/pressure/memory.
+func memoryPressureFile() string {
+	if f, err := os.Open("/proc/self/cgroup"); err == nil {
+		defer func() { _ = f.Close() }()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+				file := "/sys/fs/cgroup" + strings.TrimSuffix(path, "/") + "/memory.pressure"
+				if _, err := os.Stat(file); err == nil {
+					return file
+				}
+			}
+		}
+	}
+	return "/proc/pressure/memory"
+}
@@ -0,0 +1,16 @@
+// emergency_other.go: No PSI memory pressure notifications outside Linux
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package iris
+
+import "errors"
+
+// watchMemoryPressure is only supported on Linux.
+func watchMemoryPressure(*EmergencyFlushConfig) (<-chan struct{}, func(), error) {
+	return nil, nil, errors.New("pressure stall information is only available on Linux")
+}
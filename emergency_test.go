@@ -0,0 +1,141 @@
+// emergency_test.go: Tests for emergency flushes
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmergencyFlush_ErrorsFirst(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Debug, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	// Not started yet: everything waits in the ring
+	api := logger.Named("api")
+	api.Debug("debug")
+	api.Info("info")
+	api.Warn("warn")
+	api.Error("error")
+	api.Info("info2")
+	api.Error("error2")
+
+	// Request the flush before the consumer starts, so that it is the
+	// consumer's first batch
+	flushed := make(chan error, 1)
+	go func() { flushed <- logger.EmergencyFlush() }()
+	for logger.r.first.Load() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	logger.Start()
+	if err := <-flushed; err != nil {
+		t.Fatalf("EmergencyFlush failed: %v", err)
+	}
+	logger.Info("after")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		msgs = append(msgs, rec["msg"].(string))
+	}
+	want := "error error2 debug info warn info2 after"
+	if got := strings.Join(msgs, " "); got != want {
+		t.Errorf("written order = %q, want %q", got, want)
+	}
+	stats := logger.Stats()
+	if stats["dropped"] != 0 || stats["emergency_flushes"] != 1 {
+		t.Errorf("dropped = %d, emergency_flushes = %d, want 0 and 1",
+			stats["dropped"], stats["emergency_flushes"])
+	}
+
+	_ = logger.Close()
+	if err := logger.EmergencyFlush(); err != ErrLoggerClosed {
+		t.Errorf("EmergencyFlush after Close = %v, want ErrLoggerClosed", err)
+	}
+}
+
+func TestEmergencyFlush_Signal(t *testing.T) {
+	signal := make(chan struct{})
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithEmergencyFlush(EmergencyFlushConfig{Signal: signal}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got := logger.Stats()["emergency_flushes"]; got != 0 {
+		t.Errorf("emergency_flushes = %d before any signal", got)
+	}
+
+	signal <- struct{}{}
+	signal <- struct{}{} // Accepted once the first flush has started
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.Stats()["emergency_flushes"] < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("signals did not trigger two flushes within 5s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(signal) // A closed signal stops triggering instead of spinning
+	time.Sleep(20 * time.Millisecond)
+	if got := logger.Stats()["emergency_flushes"]; got > 3 {
+		t.Errorf("closed signal triggered %d flushes", got)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestEmergencyFlush_PSIUnavailable(t *testing.T) {
+	errs := captureErrors(t)
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithEmergencyFlush(EmergencyFlushConfig{PSI: true, PressureFile: filepath.Join(t.TempDir(), "missing")}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reported := errs()
+	if len(reported) != 1 || reported[0].Code != ErrCodeResourceLimit {
+		t.Fatalf("reported %v, want one %s error", reported, ErrCodeResourceLimit)
+	}
+}
+
+func TestWatchMemoryPressure_Stop(t *testing.T) {
+	cfg := &EmergencyFlushConfig{Stall: DefaultEmergencyStall, Window: DefaultEmergencyWindow}
+	ch, stop, err := watchMemoryPressure(cfg)
+	if err != nil {
+		t.Skipf("PSI not available: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stop did not end the watch within 5s")
+	}
+	for range ch { // Closed by the watcher
+	}
+}
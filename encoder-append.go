@@ -0,0 +1,52 @@
+// encoder-append.go: allocation-free number and time formatting for encoders
+//
+// strconv.FormatInt and friends return a string, which costs an allocation
+// for every value that is not a small integer. The helpers below append the
+// digits straight into the spare capacity of the output buffer instead, so
+// numeric fields cost no allocations once the pooled buffer has grown to its
+// working size.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// Worst-case lengths of the formatted values, reserved before appending so
+// that the append never reallocates behind the buffer's back.
+const (
+	maxIntLen   = 20 // "-9223372036854775808"
+	maxFloatLen = 32 // Shortest 'g' or 'f' of a float64 fits 24; 'f' of 1e21 and up does not
+	maxTimeLen  = 64 // Layouts used by the encoders (RFC3339Nano is 35)
+)
+
+// writeInt writes v in base 10.
+func writeInt(buf *bytes.Buffer, v int64) {
+	buf.Grow(maxIntLen)
+	buf.Write(strconv.AppendInt(buf.AvailableBuffer(), v, 10))
+}
+
+// writeUint writes v in base 10.
+func writeUint(buf *bytes.Buffer, v uint64) {
+	buf.Grow(maxIntLen)
+	buf.Write(strconv.AppendUint(buf.AvailableBuffer(), v, 10))
+}
+
+// writeFloat writes v with the shortest representation in format fmt
+// ('f', 'g', ...), as strconv.FormatFloat(v, fmt, -1, 64) would.
+func writeFloat(buf *bytes.Buffer, v float64, fmt byte) {
+	buf.Grow(maxFloatLen)
+	buf.Write(strconv.AppendFloat(buf.AvailableBuffer(), v, fmt, -1, 64))
+}
+
+// writeTime writes t formatted with layout.
+func writeTime(buf *bytes.Buffer, t time.Time, layout string) {
+	buf.Grow(maxTimeLen)
+	buf.Write(t.AppendFormat(buf.AvailableBuffer(), layout))
+}
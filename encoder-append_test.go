@@ -0,0 +1,112 @@
+// encoder-append_test.go: Tests for allocation-free number formatting
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWriteNumbers(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []int64{0, 7, -42, 1234567890, math.MaxInt64, math.MinInt64} {
+		buf.Reset()
+		writeInt(&buf, v)
+		if buf.String() != strconv.FormatInt(v, 10) {
+			t.Errorf("writeInt(%d) = %q", v, buf.String())
+		}
+	}
+	for _, v := range []uint64{0, 255, math.MaxUint64} {
+		buf.Reset()
+		writeUint(&buf, v)
+		if buf.String() != strconv.FormatUint(v, 10) {
+			t.Errorf("writeUint(%d) = %q", v, buf.String())
+		}
+	}
+	for _, v := range []float64{0, -0.5, 0.1, math.Pi, 1e21, -math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(1), math.NaN()} {
+		for _, format := range []byte{'f', 'g'} {
+			buf.Reset()
+			buf.WriteString("prefix:") // Appends must not overwrite what is already there
+			writeFloat(&buf, v, format)
+			if want := "prefix:" + strconv.FormatFloat(v, format, -1, 64); buf.String() != want {
+				t.Errorf("writeFloat(%v, %c) = %q, want %q", v, format, buf.String(), want)
+			}
+		}
+	}
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	buf.Reset()
+	writeTime(&buf, ts, time.RFC3339Nano)
+	if buf.String() != ts.Format(time.RFC3339Nano) {
+		t.Errorf("writeTime = %q", buf.String())
+	}
+}
+
+// numericRecord returns a record made only of numeric, boolean and time
+// fields, with values large enough that strconv.Itoa would allocate.
+func numericRecord() *Record {
+	rec := NewRecord(Info, "metrics")
+	rec.AddField(Int("requests", 1234567))
+	rec.AddField(Int64("bytes_out", -98765432101))
+	rec.AddField(Uint64("ops", math.MaxUint64))
+	rec.AddField(Float64("p99_ms", 12.3456789))
+	rec.AddField(Float64("ratio", 0.000123))
+	rec.AddField(Float32("load", 0.75))
+	rec.AddField(Bool("healthy", true))
+	rec.AddField(Int32("workers", 512))
+	rec.AddField(TimeField("started", time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)))
+	return rec
+}
+
+// numericEncoders are the encoders that must encode numericRecord without
+// allocating.
+var numericEncoders = []struct {
+	name string
+	enc  Encoder
+}{
+	{"JSON", NewJSONEncoder()},
+	{"Text", NewTextEncoder()},
+	{"Console", NewConsoleEncoder()},
+	{"Binary", NewBinaryEncoder()},
+}
+
+func TestEncoders_NumericRecordZeroAllocs(t *testing.T) {
+	rec := numericRecord()
+	now := time.Date(2025, 9, 6, 14, 30, 40, 123456789, time.UTC) // Historical: no time cache
+	for _, tc := range numericEncoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.enc.Encode(rec, now, &buf) // Grow the buffer to its working size
+			allocs := testing.AllocsPerRun(100, func() {
+				buf.Reset()
+				tc.enc.Encode(rec, now, &buf)
+			})
+			if allocs != 0 {
+				t.Errorf("%s encoder: %.0f allocations per numeric record, want 0", tc.name, allocs)
+			}
+		})
+	}
+}
+
+// BenchmarkEncoders_NumericRecord reports the cost of a record of numeric
+// fields per encoder; all of them report 0 allocs/op.
+func BenchmarkEncoders_NumericRecord(b *testing.B) {
+	rec := numericRecord()
+	now := time.Date(2025, 9, 6, 14, 30, 40, 123456789, time.UTC)
+	for _, tc := range numericEncoders {
+		b.Run(tc.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				tc.enc.Encode(rec, now, &buf)
+			}
+		})
+	}
+}
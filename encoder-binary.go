@@ -12,6 +12,10 @@
 // Field Format:
 // [TYPE][KEY_LEN][KEY][VALUE]
 //
+// The normative specification is docs/BINARY_FORMAT.md; schema/binary holds
+// a Kaitai Struct schema and Python and Rust decoders, all checked against
+// the conformance corpus in testdata/binary.
+//
 // Performance Features:
 // - Zero reflection encoding
 // - Minimal allocations
@@ -27,6 +31,8 @@ package iris
 import (
 	"bytes"
 	"encoding/binary"
+	"math"
+	"strconv"
 	"time"
 )
 
@@ -83,6 +89,10 @@ type BinaryEncoder struct {
 
 	// UseUnixNano uses Unix nanoseconds instead of RFC3339 for timestamps
 	UseUnixNano bool
+
+	// MarkTruncated appends an int field TruncatedFieldsKey with the number
+	// of fields dropped from a full record
+	MarkTruncated bool
 }
 
 // NewBinaryEncoder creates a new binary encoder with optimal defaults.
@@ -165,10 +175,19 @@ func (e *BinaryEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
 
 	// Encode field count and fields
 	// #nosec G115 - Field count is always positive and small
-	e.writeVarint(uint64(rec.n), buf)
+	n := uint64(rec.n)
+	marked := e.MarkTruncated && rec.lost > 0
+	if marked {
+		n++
+	}
+	e.writeVarint(n, buf)
 	for i := int32(0); i < rec.n; i++ {
 		e.encodeField(&rec.fields[i], buf)
 	}
+	if marked {
+		f := Int(TruncatedFieldsKey, int(rec.lost))
+		e.encodeField(&f, buf)
+	}
 }
 
 // encodeTimestamp writes timestamp in the configured format
@@ -178,9 +197,9 @@ func (e *BinaryEncoder) encodeTimestamp(now time.Time, buf *bytes.Buffer) {
 		// #nosec G115 - UnixNano() always returns positive values
 		e.writeVarint(uint64(now.UnixNano()), buf)
 	} else {
-		// RFC3339 as length-prefixed string
-		rfc3339 := now.Format(time.RFC3339Nano)
-		e.writeString(rfc3339, buf)
+		// RFC3339 as length-prefixed string, formatted on the stack
+		var scratch [maxTimeLen]byte
+		e.writeBytes(now.AppendFormat(scratch[:0], time.RFC3339Nano), buf)
 	}
 }
 
@@ -211,7 +230,8 @@ func (e *BinaryEncoder) encodeField(f *Field, buf *bytes.Buffer) {
 	case kindUint64:
 		e.writeVarint(f.U64, buf)
 	case kindFloat64:
-		_ = binary.Write(buf, binary.LittleEndian, f.F64)
+		buf.Grow(8)
+		buf.Write(binary.LittleEndian.AppendUint64(buf.AvailableBuffer(), math.Float64bits(f.F64)))
 	case kindBool:
 		if f.I64 != 0 {
 			buf.WriteByte(1)
@@ -231,6 +251,8 @@ func (e *BinaryEncoder) encodeField(f *Field, buf *bytes.Buffer) {
 	case kindSecret:
 		// Always write redacted marker for secrets
 		e.writeString("[REDACTED]", buf)
+	default:
+		e.writeString("", buf) // Keeps the record parseable (typed as string)
 	}
 }
 
@@ -277,7 +299,8 @@ func (e *BinaryEncoder) fieldToString(f *Field) string {
 		if err, ok := f.Obj.(error); ok {
 			return err.Error()
 		}
-	case kindStringer:
+	case kindStringer, kindObject:
+		// Objects with a text form (Times, Durations, StringMap, ...)
 		if stringer, ok := f.Obj.(interface{ String() string }); ok {
 			return stringer.String()
 		}
@@ -317,12 +340,166 @@ func (e *BinaryEncoder) writeSignedVarint(v int64, buf *bytes.Buffer) {
 	e.writeVarint(uv, buf)
 }
 
-// BinaryDecoder provides utilities for reading binary-encoded log data.
+// BinaryDecoder reads binary-encoded log records.
 //
-// Note: Full decoder implementation would be in a separate package
-// or external tool for log analysis. This is a minimal helper for testing.
+// It is the reference decoder for the format described in
+// docs/BINARY_FORMAT.md.
 type BinaryDecoder struct{}
 
+// BinaryRecord is a decoded binary log record.
+type BinaryRecord struct {
+	// Exactly one of UnixNano and RFC3339 is set, depending on the
+	// encoder's UseUnixNano setting
+	UnixNano int64
+	RFC3339  string
+
+	Level  Level
+	Logger string
+	Msg    string
+	Caller string
+	Stack  string
+	Fields []BinaryField
+}
+
+// BinaryField is a decoded field. Value holds a string (string, error,
+// stringer, object and secret types), int64 (int64, duration and time as
+// Unix nanoseconds), uint64, float64, bool or []byte.
+type BinaryField struct {
+	Type  byte
+	Key   string
+	Value any
+}
+
+// errBinaryTruncated is returned when a record ends prematurely.
+var errBinaryTruncated = NewLoggerError(ErrCodeInvalidFormat, "binary record is truncated or malformed")
+
+// Decode decodes the record at the start of data and returns it with the
+// number of bytes consumed, so that a stream of records can be read by
+// calling Decode repeatedly on the remaining bytes.
+//
+// Returns:
+//   - BinaryRecord: Decoded record
+//   - int: Bytes consumed
+//   - error: ErrCodeInvalidFormat for a bad header, truncated data or an
+//     unknown field type
+func (d *BinaryDecoder) Decode(data []byte) (BinaryRecord, int, error) {
+	var rec BinaryRecord
+	valid, pos := d.DecodeMagic(data)
+	if !valid {
+		return rec, 0, NewLoggerError(ErrCodeInvalidFormat, "invalid binary record header")
+	}
+
+	// A Unix-nanosecond varint starts with a continuation byte; an RFC 3339
+	// string starts with its (short) length
+	if pos >= len(data) {
+		return rec, 0, errBinaryTruncated
+	}
+	var err error
+	if data[pos]&0x80 != 0 {
+		var ts uint64
+		if ts, pos, err = d.ReadVarint(data, pos); err != nil {
+			return rec, 0, errBinaryTruncated
+		}
+		rec.UnixNano = int64(ts) // #nosec G115 -- written from an int64
+	} else if rec.RFC3339, pos, err = d.readString(data, pos); err != nil {
+		return rec, 0, err
+	}
+
+	if pos >= len(data) {
+		return rec, 0, errBinaryTruncated
+	}
+	rec.Level = Level(int8(data[pos])) // #nosec G115 -- levels are signed bytes on the wire
+	pos++
+	for _, s := range []*string{&rec.Logger, &rec.Msg, &rec.Caller, &rec.Stack} {
+		if *s, pos, err = d.readString(data, pos); err != nil {
+			return rec, 0, err
+		}
+	}
+
+	count, pos, err := d.ReadVarint(data, pos)
+	if err != nil {
+		return rec, 0, errBinaryTruncated
+	}
+	if count > uint64(len(data)-pos) {
+		return rec, 0, errBinaryTruncated // Each field takes at least one byte
+	}
+	rec.Fields = make([]BinaryField, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var f BinaryField
+		if f, pos, err = d.readField(data, pos); err != nil {
+			return rec, 0, err
+		}
+		rec.Fields = append(rec.Fields, f)
+	}
+	return rec, pos, nil
+}
+
+// readField decodes one field starting at pos.
+func (d *BinaryDecoder) readField(data []byte, pos int) (BinaryField, int, error) {
+	var f BinaryField
+	if pos >= len(data) {
+		return f, 0, errBinaryTruncated
+	}
+	f.Type = data[pos]
+	var err error
+	if f.Key, pos, err = d.readString(data, pos+1); err != nil {
+		return f, 0, err
+	}
+
+	switch f.Type {
+	case binaryTypeString, binaryTypeError, binaryTypeStringer, binaryTypeObject, binaryTypeSecret:
+		var s string
+		s, pos, err = d.readString(data, pos)
+		f.Value = s
+	case binaryTypeInt64, binaryTypeDur, binaryTypeTime:
+		var v uint64
+		if v, pos, err = d.ReadVarint(data, pos); err != nil {
+			err = errBinaryTruncated
+		}
+		f.Value = int64(v>>1) ^ -int64(v&1) // #nosec G115 -- ZigZag decoding
+	case binaryTypeUint64:
+		var v uint64
+		if v, pos, err = d.ReadVarint(data, pos); err != nil {
+			err = errBinaryTruncated
+		}
+		f.Value = v
+	case binaryTypeFloat64:
+		if len(data)-pos < 8 {
+			return f, 0, errBinaryTruncated
+		}
+		f.Value = math.Float64frombits(binary.LittleEndian.Uint64(data[pos:]))
+		pos += 8
+	case binaryTypeBool:
+		if pos >= len(data) {
+			return f, 0, errBinaryTruncated
+		}
+		f.Value = data[pos] != 0
+		pos++
+	case binaryTypeBytes:
+		var n uint64
+		if n, pos, err = d.ReadVarint(data, pos); err != nil || n > uint64(len(data)-pos) {
+			return f, 0, errBinaryTruncated
+		}
+		f.Value = append([]byte(nil), data[pos:pos+int(n)]...)
+		pos += int(n)
+	default:
+		return f, 0, NewLoggerErrorWithField(ErrCodeInvalidFormat, "unknown binary field type", "type", strconv.Itoa(int(f.Type)))
+	}
+	if err != nil {
+		return f, 0, err
+	}
+	return f, pos, nil
+}
+
+// readString decodes a length-prefixed string starting at pos.
+func (d *BinaryDecoder) readString(data []byte, pos int) (string, int, error) {
+	n, pos, err := d.ReadVarint(data, pos)
+	if err != nil || n > uint64(len(data)-pos) {
+		return "", 0, errBinaryTruncated
+	}
+	return string(data[pos : pos+int(n)]), pos + int(n), nil
+}
+
 // DecodeMagic validates the magic header of a binary log record.
 //
 // Parameters:
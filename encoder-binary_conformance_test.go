@@ -0,0 +1,295 @@
+// encoder-binary_conformance_test.go: Conformance corpus for the binary format
+//
+// The corpus in testdata/binary is shared with the Python and Rust decoders
+// in schema/binary. Each valid/<case>.bin holds a stream of records and
+// valid/<case>.json their canonical JSON rendering (docs/BINARY_FORMAT.md);
+// every invalid/<case>.bin must be rejected. Regenerate after an intended
+// format change with:
+//
+//	go test -run TestBinaryConformance -update-binary-corpus
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateBinaryCorpus = flag.Bool("update-binary-corpus", false, "rewrite testdata/binary from the encoder")
+
+// binaryCorpusEntry is one record of a corpus stream.
+type binaryCorpusEntry struct {
+	enc *BinaryEncoder
+	now time.Time
+	rec func() *Record
+}
+
+// binaryCorpus returns the valid corpus streams by case name.
+func binaryCorpus() map[string][]binaryCorpusEntry {
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	record := func(level Level, msg string, fields ...Field) func() *Record {
+		return func() *Record {
+			r := NewRecord(level, msg)
+			for _, f := range fields {
+				r.AddField(f)
+			}
+			return r
+		}
+	}
+	full := &BinaryEncoder{IncludeLoggerName: true, IncludeCaller: true, IncludeStack: true}
+
+	return map[string][]binaryCorpusEntry{
+		"basic": {{NewBinaryEncoder(), ts, func() *Record {
+			r := NewRecord(Info, "user logged in")
+			r.Logger = "auth"
+			r.AddField(Str("user", "alice"))
+			r.AddField(Int("attempt", 3))
+			return r
+		}}},
+		"all_types": {{NewBinaryEncoder(), ts, record(Warn, "every field type",
+			Str("str", "value"),
+			Str("empty", ""),
+			Int64("int_neg", -42),
+			Int64("int_min", math.MinInt64),
+			Int64("int_max", math.MaxInt64),
+			Uint64("uint_max", math.MaxUint64),
+			Float64("float", -1.5),
+			Float64("float_zero", 0),
+			Float64("float_inf", math.Inf(1)),
+			Bool("bool_true", true),
+			Bool("bool_false", false),
+			Dur("duration", -1500*time.Millisecond),
+			Time("time", time.Date(1969, 7, 20, 20, 17, 40, 0, time.UTC)),
+			Bytes("bytes", []byte{0x00, 0x01, 0xfe, 0xff}),
+			NamedError("error", errors.New("connection refused")),
+			Stringer("stringer", testStringerImpl{value: "as string"}),
+			Object("object", struct{ A int }{1}),
+			Secret("password", "hunter2"),
+		)}},
+		"rfc3339_full": {{full, ts, func() *Record {
+			r := NewRecord(Trace, "quote \" backslash \\ newline \n tab \t bell \a unicode héllo 日本")
+			r.Logger = "svc.db"
+			r.Caller = "db/pool.go:42"
+			r.Stack = "goroutine 1 [running]:\nmain.main()"
+			r.AddField(Str("ключ", "значение"))
+			return r
+		}}},
+		"compact_stream": {
+			{NewCompactBinaryEncoder(), ts, record(Debug, "")},
+			{NewCompactBinaryEncoder(), ts.Add(time.Second), record(Error, strings.Repeat("long message ", 25), Int("n", 300))},
+			{NewCompactBinaryEncoder(), ts.Add(2 * time.Second), func() *Record {
+				r := NewRecord(Fatal, "logger name omitted")
+				r.Logger = "ignored"
+				return r
+			}},
+			{NewBinaryEncoder(), time.Unix(0, 200), record(Level(-100), "custom level near the epoch")},
+		},
+	}
+}
+
+// binaryInvalidCorpus returns inputs every decoder must reject.
+func binaryInvalidCorpus(t *testing.T) map[string][]byte {
+	var valid bytes.Buffer
+	NewBinaryEncoder().Encode(NewRecord(Info, "hello"), time.Unix(1700000000, 0), &valid)
+	b := valid.Bytes()
+
+	var withField bytes.Buffer
+	rec := NewRecord(Info, "x")
+	rec.AddField(Str("k", "v"))
+	NewCompactBinaryEncoder().Encode(rec, time.Unix(1700000000, 0), &withField)
+	unknownType := append([]byte(nil), withField.Bytes()...)
+	typeOffset := bytes.Index(unknownType, []byte{binaryTypeString, 1, 'k'})
+	if typeOffset < 0 {
+		t.Fatal("field not found in encoded record")
+	}
+	unknownType[typeOffset] = 0x7f
+
+	overrun := append([]byte(nil), b...)
+	msgOffset := bytes.Index(overrun, []byte("\x05hello"))
+	if msgOffset < 0 {
+		t.Fatal("message not found in encoded record")
+	}
+	overrun[msgOffset] = 0x7f // Longer than the rest of the record
+
+	return map[string][]byte{
+		"bad_magic":        append([]byte{'X', 'X'}, b[2:]...),
+		"bad_version":      append([]byte{b[0], b[1], 0x02}, b[3:]...),
+		"truncated_header": b[:2],
+		"truncated_msg":    b[:len(b)-5],
+		"trailing_garbage": append(append([]byte(nil), b...), b[:4]...),
+		"unknown_type":     unknownType,
+		"string_overrun":   overrun,
+	}
+}
+
+// canonicalBinaryJSON renders decoded records as the canonical JSON
+// defined in docs/BINARY_FORMAT.md.
+func canonicalBinaryJSON(recs []BinaryRecord) string {
+	var b strings.Builder
+	str := func(s string) {
+		b.WriteByte('"')
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c == '"' || c == '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c < 0x20:
+				b.WriteString(`\u00`)
+				b.WriteString(hex.EncodeToString([]byte{c}))
+			default:
+				b.WriteByte(c)
+			}
+		}
+		b.WriteByte('"')
+	}
+	types := map[byte]string{
+		binaryTypeString: "string", binaryTypeInt64: "int64", binaryTypeUint64: "uint64",
+		binaryTypeFloat64: "float64", binaryTypeBool: "bool", binaryTypeDur: "duration",
+		binaryTypeTime: "time", binaryTypeBytes: "bytes", binaryTypeError: "error",
+		binaryTypeStringer: "stringer", binaryTypeObject: "object", binaryTypeSecret: "secret",
+	}
+
+	b.WriteString("[\n")
+	for i, r := range recs {
+		if i > 0 {
+			b.WriteString(",\n")
+		}
+		if r.RFC3339 != "" {
+			b.WriteString(`{"ts":{"rfc3339":`)
+			str(r.RFC3339)
+		} else {
+			b.WriteString(`{"ts":{"unix_nano":` + strconv.FormatInt(r.UnixNano, 10))
+		}
+		b.WriteString(`},"level":` + strconv.Itoa(int(r.Level)))
+		for _, kv := range [][2]string{{"logger", r.Logger}, {"msg", r.Msg}, {"caller", r.Caller}, {"stack", r.Stack}} {
+			b.WriteString(`,"` + kv[0] + `":`)
+			str(kv[1])
+		}
+		b.WriteString(`,"fields":[`)
+		for j, f := range r.Fields {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`{"type":"` + types[f.Type] + `","key":`)
+			str(f.Key)
+			b.WriteString(`,"value":`)
+			switch v := f.Value.(type) {
+			case string:
+				str(v)
+			case int64:
+				b.WriteString(strconv.FormatInt(v, 10))
+			case uint64:
+				b.WriteString(strconv.FormatUint(v, 10))
+			case float64:
+				fmt.Fprintf(&b, `"0x%016x"`, math.Float64bits(v))
+			case bool:
+				b.WriteString(strconv.FormatBool(v))
+			case []byte:
+				str(hex.EncodeToString(v))
+			}
+			b.WriteByte('}')
+		}
+		b.WriteString("]}")
+	}
+	b.WriteString("\n]\n")
+	return b.String()
+}
+
+// decodeBinaryStream decodes every record of data.
+func decodeBinaryStream(data []byte) ([]BinaryRecord, error) {
+	var d BinaryDecoder
+	var recs []BinaryRecord
+	for len(data) > 0 {
+		rec, n, err := d.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+		data = data[n:]
+	}
+	return recs, nil
+}
+
+func TestBinaryConformance(t *testing.T) {
+	dir := filepath.Join("testdata", "binary")
+	if *updateBinaryCorpus {
+		for _, sub := range []string{"valid", "invalid"} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0750); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for name, entries := range binaryCorpus() {
+		t.Run("valid/"+name, func(t *testing.T) {
+			var stream bytes.Buffer
+			for _, e := range entries {
+				e.enc.Encode(e.rec(), e.now, &stream)
+			}
+			recs, err := decodeBinaryStream(stream.Bytes())
+			if err != nil {
+				t.Fatalf("decoding encoder output failed: %v", err)
+			}
+			canonical := canonicalBinaryJSON(recs)
+
+			binPath := filepath.Join(dir, "valid", name+".bin")
+			jsonPath := filepath.Join(dir, "valid", name+".json")
+			if *updateBinaryCorpus {
+				if err := os.WriteFile(binPath, stream.Bytes(), 0600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(jsonPath, []byte(canonical), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			wantBin, err := os.ReadFile(binPath) // #nosec G304 -- test corpus
+			if err != nil {
+				t.Fatalf("missing corpus file (run with -update-binary-corpus): %v", err)
+			}
+			wantJSON, err := os.ReadFile(jsonPath) // #nosec G304 -- test corpus
+			if err != nil {
+				t.Fatalf("missing corpus file (run with -update-binary-corpus): %v", err)
+			}
+			if !bytes.Equal(stream.Bytes(), wantBin) {
+				t.Errorf("encoder output differs from %s: the wire format changed", binPath)
+			}
+			if canonical != string(wantJSON) {
+				t.Errorf("decoded %s differs from %s:\n%s", binPath, jsonPath, canonical)
+			}
+		})
+	}
+
+	for name, data := range binaryInvalidCorpus(t) {
+		t.Run("invalid/"+name, func(t *testing.T) {
+			path := filepath.Join(dir, "invalid", name+".bin")
+			if *updateBinaryCorpus {
+				if err := os.WriteFile(path, data, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			corpus, err := os.ReadFile(path) // #nosec G304 -- test corpus
+			if err != nil {
+				t.Fatalf("missing corpus file (run with -update-binary-corpus): %v", err)
+			}
+			if _, err := decodeBinaryStream(corpus); !IsLoggerError(err, ErrCodeInvalidFormat) {
+				t.Errorf("expected ErrCodeInvalidFormat, got %v", err)
+			}
+		})
+	}
+}
@@ -8,9 +8,10 @@ package iris
 
 import (
 	"bytes"
-	"strconv"
+	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ConsoleEncoder implements human-readable console output for development and debugging.
@@ -21,10 +22,15 @@ import (
 //
 // Features:
 //   - Configurable timestamp formatting (supports any Go time layout)
+//   - Timestamps rendered in a chosen time zone (local, UTC, IANA or offset)
 //   - Level text casing control (uppercase/lowercase)
 //   - Optional ANSI color codes for different log levels
 //   - Clean field formatting for easy visual scanning
 //   - Terminal-friendly output without excessive escaping
+//   - Per-level verbosity: show or hide sections (timestamp, logger name,
+//     caller, stack) and override colors for individual levels
+//   - Width controls for records with many fields: line wrapping, value
+//     truncation and sorted field order
 //
 // Output Format:
 //
@@ -41,6 +47,13 @@ type ConsoleEncoder struct {
 	// Popular alternatives: time.Kitchen, time.Stamp, custom layouts.
 	TimeFormat string
 
+	// TimeZone renders the leading timestamp and time fields in this
+	// location, e.g. time.Local or a zone from ParseConsoleTimeZone. Only
+	// the rendering changes: records keep their instant, and other encoders
+	// of the same logger are unaffected.
+	// Default: nil (timestamps are rendered as the clock returns them).
+	TimeZone *time.Location
+
 	// LevelCasing controls the case of level text in output.
 	// Values: "upper" (default: INFO, ERROR) or "lower" (info, error).
 	// Affects readability and consistency with your preferred style.
@@ -50,6 +63,77 @@ type ConsoleEncoder struct {
 	// Default: false (safe for all terminals and log files).
 	// Enable only in interactive terminals that support colors.
 	EnableColor bool
+
+	// Theme selects the level colors when EnableColor is set, e.g. a
+	// color-blind safe or light-background palette (see ParseConsoleTheme).
+	// Default: nil (the built-in 16-color scheme).
+	Theme *ConsoleTheme
+
+	// LevelSections selects which optional sections are rendered for a level.
+	// Levels without an entry render every section (ConsoleSectionsAll).
+	// Example: terse Info lines but full detail on Error:
+	//
+	//	enc.LevelSections = map[iris.Level]iris.ConsoleSection{
+	//	    iris.Info:  iris.ConsoleSectionNone,
+	//	    iris.Error: iris.ConsoleSectionsAll,
+	//	}
+	LevelSections map[Level]ConsoleSection
+
+	// LevelColors overrides the ANSI color sequence used for a level when
+	// EnableColor is set (e.g. "\x1b[36m" for cyan). Levels without an entry
+	// use the Theme, or the built-in color scheme.
+	LevelColors map[Level]string
+
+	// MaxLineWidth wraps fields onto indented continuation lines so that no
+	// line is wider than this many characters (ANSI color codes excluded).
+	// A single field wider than the limit is kept whole on its own line.
+	// Default: 0 (no wrapping).
+	MaxLineWidth int
+
+	// MaxFieldValueLen truncates string values longer than this many
+	// characters, ending them with "…" so the result is exactly this wide.
+	// Numbers, durations and other fixed-size values are never truncated.
+	// Default: 0 (no truncation).
+	MaxFieldValueLen int
+
+	// SortFields renders fields in key order instead of the order they were
+	// added, so the same key appears in the same place on every line.
+	// Default: false.
+	SortFields bool
+
+	// MarkTruncated appends _truncated_fields=N (TruncatedFieldsKey) after
+	// the fields of a record that had N fields dropped because it was full.
+	// Default: false.
+	MarkTruncated bool
+}
+
+// consoleEllipsis marks a value truncated by MaxFieldValueLen.
+const consoleEllipsis = "…"
+
+// consoleWrapIndent prefixes continuation lines when MaxLineWidth wraps fields.
+const consoleWrapIndent = "  "
+
+// ConsoleSection is a bitmask of optional sections in console output.
+// The level and message are always rendered; structured fields other than
+// caller and stack are always rendered as well.
+type ConsoleSection uint8
+
+// Console output sections that can be toggled per level
+const (
+	ConsoleSectionTime   ConsoleSection = 1 << iota // Leading timestamp
+	ConsoleSectionLogger                            // Logger name (from Named)
+	ConsoleSectionCaller                            // Caller location (file:line)
+	ConsoleSectionStack                             // Stack trace
+
+	// ConsoleSectionNone renders only level, message and fields
+	ConsoleSectionNone ConsoleSection = 0
+	// ConsoleSectionsAll renders every optional section (default)
+	ConsoleSectionsAll = ConsoleSectionTime | ConsoleSectionLogger | ConsoleSectionCaller | ConsoleSectionStack
+)
+
+// Has reports whether all sections in other are enabled in s.
+func (s ConsoleSection) Has(other ConsoleSection) bool {
+	return s&other == other
 }
 
 // NewConsoleEncoder creates a new console encoder with development-friendly defaults.
@@ -58,6 +142,7 @@ type ConsoleEncoder struct {
 // - TimeFormat: time.RFC3339Nano (precise for development)
 // - LevelCasing: "upper" (traditional log format)
 // - EnableColor: false (safe for all environments)
+// - TimeZone: from IRIS_CONSOLE_TIMEZONE (see ParseConsoleTimeZone), if set
 //
 // These defaults work well in most development environments and can be
 // safely used in both terminals and log files.
@@ -69,6 +154,7 @@ func NewConsoleEncoder() *ConsoleEncoder {
 		TimeFormat:  time.RFC3339Nano,
 		LevelCasing: "upper",
 		EnableColor: false,
+		TimeZone:    consoleTimeZoneFromEnv(),
 	}
 }
 
@@ -84,6 +170,11 @@ func NewConsoleEncoder() *ConsoleEncoder {
 // - INFO:  Default (normal text for regular information)
 // - DEBUG: Cyan (distinct but subtle for debug info)
 //
+// Setting IRIS_CONSOLE_THEME to a theme name (e.g. "colorblind" or
+// "light-256", see ParseConsoleTheme) selects another palette; unknown
+// names keep the default scheme. IRIS_CONSOLE_TIMEZONE selects the time
+// zone of timestamps as for NewConsoleEncoder.
+//
 // Use only in:
 // - Interactive development terminals
 // - IDEs with color support
@@ -101,11 +192,25 @@ func NewColorConsoleEncoder() *ConsoleEncoder {
 		TimeFormat:  time.RFC3339Nano,
 		LevelCasing: "upper",
 		EnableColor: true,
+		Theme:       consoleThemeFromEnv(),
+		TimeZone:    consoleTimeZoneFromEnv(),
 	}
 }
 
+// sectionsFor returns the sections enabled for the given level.
+func (e *ConsoleEncoder) sectionsFor(level Level) ConsoleSection {
+	if e.LevelSections == nil {
+		return ConsoleSectionsAll
+	}
+	if sections, ok := e.LevelSections[level]; ok {
+		return sections
+	}
+	return ConsoleSectionsAll
+}
+
 // Encode writes a log record to the buffer in console-friendly format.
-// The output format is: timestamp level message key=value key=value...
+// The output format is: timestamp level logger message key=value key=value...
+// Optional sections are filtered according to LevelSections.
 func (e *ConsoleEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
 	// Set defaults
 	timeFormat := e.TimeFormat
@@ -116,42 +221,184 @@ func (e *ConsoleEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
 	// Format level with casing preference
 	levelStr := rec.Level.String()
 	if e.LevelCasing == "" || strings.EqualFold(e.LevelCasing, "upper") {
-		levelStr = strings.ToUpper(levelStr)
+		levelStr = upperLevelString(rec.Level, levelStr)
 	}
 
-	// Apply color if enabled
+	// Apply color if enabled; colorWidth counts the invisible escape bytes
+	plainLen := len(levelStr)
 	if e.EnableColor {
-		levelStr = colorizeLevel(rec.Level, levelStr)
+		if color, ok := e.LevelColors[rec.Level]; ok {
+			levelStr = color + levelStr + "\x1b[0m"
+		} else if e.Theme != nil {
+			levelStr = e.Theme.colorize(rec.Level, levelStr)
+		} else {
+			levelStr = colorizeLevel(rec.Level, levelStr)
+		}
 	}
+	colorWidth := len(levelStr) - plainLen
+
+	sections := e.sectionsFor(rec.Level)
 
 	// Pre-allocate buffer space for better performance
 	buf.Grow(128)
+	lineStart := buf.Len()
 
 	// Write timestamp
-	buf.WriteString(now.Format(timeFormat))
-	buf.WriteByte(' ')
+	if sections.Has(ConsoleSectionTime) {
+		if e.TimeZone != nil {
+			now = now.In(e.TimeZone)
+		}
+		writeTime(buf, now, timeFormat)
+		buf.WriteByte(' ')
+	}
 
 	// Write level
 	buf.WriteString(levelStr)
 
+	// Write logger name if present
+	if rec.Logger != "" && sections.Has(ConsoleSectionLogger) {
+		buf.WriteByte(' ')
+		buf.WriteString(rec.Logger)
+	}
+
 	// Write message if present
 	if rec.Msg != "" {
 		buf.WriteByte(' ')
 		buf.WriteString(rec.Msg)
 	}
 
+	showCaller := sections.Has(ConsoleSectionCaller)
+	showStack := sections.Has(ConsoleSectionStack)
+
+	// Current line width, used only when wrapping
+	col := 0
+	if e.MaxLineWidth > 0 {
+		col = utf8.RuneCount(buf.Bytes()[lineStart:]) - colorWidth
+	}
+
 	// Write all fields as key=value pairs
-	for i := int32(0); i < rec.n; i++ {
+	var order [len(rec.fields)]int32
+	rec.fieldOrder(order[:rec.n], e.SortFields)
+	for _, i := range order[:rec.n] {
 		field := rec.fields[i]
+		if (!showCaller && field.K == "caller") || (!showStack && field.K == "stack") {
+			continue
+		}
+		start := buf.Len()
 		buf.WriteByte(' ')
 		buf.WriteString(field.K)
 		buf.WriteByte('=')
-		encodeConsoleValue(field, buf)
+		if field.T == kindTime && e.TimeZone != nil {
+			writeTime(buf, time.Unix(0, field.I64).In(e.TimeZone), time.RFC3339Nano)
+		} else {
+			e.writeValue(field, buf)
+		}
+		col = e.wrap(buf, start, col)
+	}
+	if e.MarkTruncated && rec.lost > 0 {
+		start := buf.Len()
+		buf.WriteString(" " + TruncatedFieldsKey + "=")
+		writeInt(buf, int64(rec.lost))
+		col = e.wrap(buf, start, col)
+	}
+
+	// Caller and stack set directly on the record
+	if rec.Caller != "" && showCaller {
+		start := buf.Len()
+		buf.WriteString(" caller=")
+		writeMaybeQuoted(rec.Caller, buf)
+		e.wrap(buf, start, col)
+	}
+	if rec.Stack != "" && showStack {
+		buf.WriteByte('\n')
+		buf.WriteString(rec.Stack)
 	}
 
 	buf.WriteByte('\n')
 }
 
+// writeValue writes a field value, truncating strings to MaxFieldValueLen.
+func (e *ConsoleEncoder) writeValue(field Field, buf *bytes.Buffer) {
+	if e.MaxFieldValueLen > 0 {
+		switch field.T {
+		case kindString:
+			writeMaybeQuoted(truncateConsoleValue(field.Str, e.MaxFieldValueLen), buf)
+			return
+		case kindObject:
+			if s, ok := field.Obj.(fmt.Stringer); ok {
+				writeMaybeQuoted(truncateConsoleValue(s.String(), e.MaxFieldValueLen), buf)
+			}
+			return
+		}
+	}
+	encodeConsoleValue(field, buf)
+}
+
+// wrap moves the entry written at buf[start:] (a leading space followed by
+// key=value) onto a continuation line if it would make the current line
+// wider than MaxLineWidth, and returns the new line width. The entry is
+// shifted in place, so wrapping does not allocate.
+func (e *ConsoleEncoder) wrap(buf *bytes.Buffer, start, col int) int {
+	if e.MaxLineWidth <= 0 {
+		return col
+	}
+	end := buf.Len()
+	width := utf8.RuneCount(buf.Bytes()[start+1 : end])
+	if col+1+width <= e.MaxLineWidth {
+		return col + 1 + width
+	}
+	// Replace the separating space with a newline and the indent: the
+	// entry grows by len(consoleWrapIndent) bytes
+	buf.WriteString(consoleWrapIndent)
+	b := buf.Bytes()
+	copy(b[start+1+len(consoleWrapIndent):], b[start+1:end])
+	b[start] = '\n'
+	copy(b[start+1:], consoleWrapIndent)
+	return len(consoleWrapIndent) + width
+}
+
+// truncateConsoleValue shortens s to max characters, the last of which is
+// an ellipsis.
+func truncateConsoleValue(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	cut, n := 0, 0
+	for i := range s {
+		if n == max-1 {
+			cut = i
+			break
+		}
+		n++
+	}
+	return s[:cut] + consoleEllipsis
+}
+
+// upperLevelString returns name, the String of level, in upper case without
+// allocating for the built-in levels.
+func upperLevelString(level Level, name string) string {
+	switch level {
+	case Trace:
+		return "TRACE"
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	case DPanic:
+		return "DPANIC"
+	case Panic:
+		return "PANIC"
+	case Fatal:
+		return "FATAL"
+	default:
+		return strings.ToUpper(name)
+	}
+}
+
 // encodeConsoleValue writes a field value to the buffer using console-appropriate formatting.
 // Values are formatted without JSON encoding for better readability.
 func encodeConsoleValue(field Field, buf *bytes.Buffer) {
@@ -159,11 +406,11 @@ func encodeConsoleValue(field Field, buf *bytes.Buffer) {
 	case kindString:
 		writeMaybeQuoted(field.Str, buf)
 	case kindInt64:
-		buf.WriteString(strconv.FormatInt(field.I64, 10))
+		writeInt(buf, field.I64)
 	case kindUint64:
-		buf.WriteString(strconv.FormatUint(field.U64, 10))
+		writeUint(buf, field.U64)
 	case kindFloat64:
-		buf.WriteString(strconv.FormatFloat(field.F64, 'f', -1, 64))
+		writeFloat(buf, field.F64, 'f')
 	case kindBool:
 		if field.I64 != 0 {
 			buf.WriteString("true")
@@ -173,11 +420,15 @@ func encodeConsoleValue(field Field, buf *bytes.Buffer) {
 	case kindDur:
 		buf.WriteString(time.Duration(field.I64).String())
 	case kindTime:
-		buf.WriteString(time.Unix(0, field.I64).Format(time.RFC3339Nano))
+		writeTime(buf, time.Unix(0, field.I64), time.RFC3339Nano)
 	case kindBytes:
 		buf.WriteByte('<')
-		buf.WriteString(strconv.Itoa(len(field.B)))
+		writeInt(buf, int64(len(field.B)))
 		buf.WriteString("B>")
+	case kindObject:
+		if s, ok := field.Obj.(fmt.Stringer); ok {
+			writeMaybeQuoted(s.String(), buf)
+		}
 	}
 }
 
@@ -210,7 +461,7 @@ quote_check_done:
 
 // colorizeLevel applies ANSI color codes to level strings based on severity.
 // Colors are chosen to provide good visibility and semantic meaning:
-// - Debug: gray (low importance)
+// - Trace/Debug: gray (low importance)
 // - Info: blue (informational)
 // - Warn: yellow (caution)
 // - Error: red (problems)
@@ -227,7 +478,7 @@ func colorizeLevel(level Level, levelStr string) string {
 	)
 
 	switch level {
-	case Debug:
+	case Trace, Debug:
 		return gray + levelStr + reset
 	case Info:
 		return blue + levelStr + reset
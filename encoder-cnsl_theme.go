@@ -0,0 +1,234 @@
+// encoder-cnsl_theme.go: Level color themes for the console encoder
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConsoleThemeEnv names the environment variable selecting the theme of
+// NewColorConsoleEncoder and of console output set up by the config loaders.
+const ConsoleThemeEnv = "IRIS_CONSOLE_THEME"
+
+// ConsoleTheme is a set of ANSI sequences coloring each level in console
+// output. Levels without an entry are written without color.
+//
+// Built-in themes are obtained with ParseConsoleTheme; a custom theme is a
+// plain value:
+//
+//	enc := iris.NewColorConsoleEncoder()
+//	enc.Theme = &iris.ConsoleTheme{Name: "mine", Levels: map[iris.Level]string{
+//	    iris.Error: "\x1b[1;31m",
+//	}}
+type ConsoleTheme struct {
+	// Name identifies the theme (e.g. "colorblind-256")
+	Name string
+
+	// Levels maps a level to the ANSI sequence written before it; the
+	// level is followed by a reset sequence
+	Levels map[Level]string
+}
+
+// ConsoleColorMode is the color depth a built-in theme is rendered with.
+type ConsoleColorMode uint8
+
+// Color depths supported by terminals
+const (
+	ColorMode16        ConsoleColorMode = iota // 16 basic ANSI colors, supported everywhere
+	ColorMode256                               // xterm 256-color palette
+	ColorModeTrueColor                         // 24-bit RGB colors
+)
+
+// String returns the mode suffix used in theme names.
+func (m ConsoleColorMode) String() string {
+	switch m {
+	case ColorMode256:
+		return "256"
+	case ColorModeTrueColor:
+		return "truecolor"
+	default:
+		return "16"
+	}
+}
+
+// themeColor is the color of one level in each mode.
+type themeColor struct {
+	basic string   // SGR parameter in 16-color mode ("31", "1;35")
+	xterm uint8    // Palette index in 256-color mode
+	rgb   [3]uint8 // Color in truecolor mode
+	attrs string   // SGR attributes added in 256-color and truecolor modes ("1" bold, "7" reverse)
+}
+
+// consolePalettes are the built-in palettes by name.
+var consolePalettes = map[string]map[Level]themeColor{
+	// The historical scheme: gray, blue, yellow, red, magenta, bright red
+	"default": {
+		Trace:  {basic: "90", xterm: 244, rgb: [3]uint8{128, 128, 128}},
+		Debug:  {basic: "90", xterm: 244, rgb: [3]uint8{128, 128, 128}},
+		Info:   {basic: "34", xterm: 33, rgb: [3]uint8{0, 135, 255}},
+		Warn:   {basic: "33", xterm: 214, rgb: [3]uint8{255, 175, 0}},
+		Error:  {basic: "31", xterm: 196, rgb: [3]uint8{255, 0, 0}},
+		DPanic: {basic: "35", xterm: 201, rgb: [3]uint8{255, 0, 255}},
+		Panic:  {basic: "91", xterm: 196, rgb: [3]uint8{255, 0, 0}, attrs: "1"},
+		Fatal:  {basic: "91", xterm: 196, rgb: [3]uint8{255, 0, 0}, attrs: "1"},
+	},
+	// Okabe-Ito colors, distinguishable with any color vision deficiency.
+	// Red and green are never paired and severity is also carried by bold
+	// and reverse video, so levels stay apart even without hue.
+	"colorblind": {
+		Trace:  {basic: "90", xterm: 246, rgb: [3]uint8{153, 153, 153}},
+		Debug:  {basic: "90", xterm: 246, rgb: [3]uint8{153, 153, 153}},
+		Info:   {basic: "34", xterm: 25, rgb: [3]uint8{0, 114, 178}},
+		Warn:   {basic: "33", xterm: 178, rgb: [3]uint8{230, 159, 0}},
+		Error:  {basic: "1;35", xterm: 166, rgb: [3]uint8{213, 94, 0}, attrs: "1"},
+		DPanic: {basic: "1;7;35", xterm: 175, rgb: [3]uint8{204, 121, 167}, attrs: "1;7"},
+		Panic:  {basic: "1;7;35", xterm: 166, rgb: [3]uint8{213, 94, 0}, attrs: "1;7"},
+		Fatal:  {basic: "1;7;35", xterm: 166, rgb: [3]uint8{213, 94, 0}, attrs: "1;7"},
+	},
+	// Dark colors readable on white and pastel backgrounds, where yellow
+	// and bright colors wash out
+	"light": {
+		Trace:  {basic: "90", xterm: 243, rgb: [3]uint8{110, 110, 110}},
+		Debug:  {basic: "90", xterm: 243, rgb: [3]uint8{110, 110, 110}},
+		Info:   {basic: "34", xterm: 25, rgb: [3]uint8{0, 80, 160}},
+		Warn:   {basic: "35", xterm: 130, rgb: [3]uint8{175, 95, 0}},
+		Error:  {basic: "1;31", xterm: 124, rgb: [3]uint8{175, 0, 0}, attrs: "1"},
+		DPanic: {basic: "1;35", xterm: 90, rgb: [3]uint8{135, 0, 135}, attrs: "1"},
+		Panic:  {basic: "1;7;31", xterm: 124, rgb: [3]uint8{175, 0, 0}, attrs: "1;7"},
+		Fatal:  {basic: "1;7;31", xterm: 124, rgb: [3]uint8{175, 0, 0}, attrs: "1;7"},
+	},
+}
+
+// NewConsoleTheme renders a built-in palette in the given color mode.
+//
+// Palettes:
+//   - "default": gray, blue, yellow, red and magenta on dark backgrounds
+//   - "colorblind": Okabe-Ito colors safe for color vision deficiencies
+//   - "light": dark colors for light terminal backgrounds
+//
+// Parameters:
+//   - palette: Palette name (case-insensitive)
+//   - mode: Color depth of the terminal
+//
+// Returns:
+//   - *ConsoleTheme: The theme, or nil for an unknown palette
+func NewConsoleTheme(palette string, mode ConsoleColorMode) *ConsoleTheme {
+	palette = strings.ToLower(palette)
+	colors, ok := consolePalettes[palette]
+	if !ok {
+		return nil
+	}
+	name := palette
+	if mode != ColorMode16 {
+		name += "-" + mode.String()
+	}
+	theme := &ConsoleTheme{Name: name, Levels: make(map[Level]string, len(colors))}
+	for level, c := range colors {
+		theme.Levels[level] = c.sequence(mode)
+	}
+	return theme
+}
+
+// sequence returns the ANSI sequence of c in mode.
+func (c themeColor) sequence(mode ConsoleColorMode) string {
+	var b strings.Builder
+	b.WriteString("\x1b[")
+	switch mode {
+	case ColorMode256:
+		if c.attrs != "" {
+			b.WriteString(c.attrs)
+			b.WriteByte(';')
+		}
+		b.WriteString("38;5;")
+		b.WriteString(strconv.Itoa(int(c.xterm)))
+	case ColorModeTrueColor:
+		if c.attrs != "" {
+			b.WriteString(c.attrs)
+			b.WriteByte(';')
+		}
+		b.WriteString("38;2;")
+		b.WriteString(strconv.Itoa(int(c.rgb[0])))
+		b.WriteByte(';')
+		b.WriteString(strconv.Itoa(int(c.rgb[1])))
+		b.WriteByte(';')
+		b.WriteString(strconv.Itoa(int(c.rgb[2])))
+	default:
+		b.WriteString(c.basic)
+	}
+	b.WriteByte('m')
+	return b.String()
+}
+
+// ParseConsoleTheme returns the built-in theme with the given name.
+//
+// A name is a palette ("default", "colorblind", "light"), optionally
+// followed by a color mode ("-256", "-truecolor"); a mode alone selects
+// the default palette. Without a mode the 16 basic colors are used.
+// Examples: "colorblind", "light-256", "truecolor", "colorblind-truecolor".
+//
+// Parameters:
+//   - name: Theme name (case-insensitive); empty selects "default"
+//
+// Returns:
+//   - *ConsoleTheme: The theme
+//   - error: ErrCodeInvalidConfig for an unknown name
+func ParseConsoleTheme(name string) (*ConsoleTheme, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" {
+		normalized = "default"
+	}
+	palette, mode := normalized, ColorMode16
+	for _, m := range []ConsoleColorMode{ColorMode256, ColorModeTrueColor} {
+		suffix := m.String()
+		switch {
+		case normalized == suffix:
+			palette, mode = "default", m
+		case strings.HasSuffix(normalized, "-"+suffix):
+			palette, mode = strings.TrimSuffix(normalized, "-"+suffix), m
+		}
+	}
+	if theme := NewConsoleTheme(palette, mode); theme != nil {
+		return theme, nil
+	}
+	return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig,
+		"unknown console theme (want "+strings.Join(ConsoleThemeNames(), ", ")+")", "console_theme", name)
+}
+
+// ConsoleThemeNames returns the names of the built-in themes, sorted.
+func ConsoleThemeNames() []string {
+	names := make([]string, 0, len(consolePalettes)*3)
+	for palette := range consolePalettes {
+		names = append(names, palette, palette+"-256", palette+"-truecolor")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// consoleThemeFromEnv returns the theme named by IRIS_CONSOLE_THEME, or nil
+// when it is unset or unknown.
+func consoleThemeFromEnv() *ConsoleTheme {
+	name := os.Getenv(ConsoleThemeEnv)
+	if name == "" {
+		return nil
+	}
+	theme, err := ParseConsoleTheme(name)
+	if err != nil {
+		return nil
+	}
+	return theme
+}
+
+// colorize wraps levelStr in the theme's sequence for level.
+func (t *ConsoleTheme) colorize(level Level, levelStr string) string {
+	if seq, ok := t.Levels[level]; ok {
+		return seq + levelStr + "\x1b[0m"
+	}
+	return levelStr
+}
@@ -0,0 +1,167 @@
+// encoder-cnsl_theme_test.go: Tests for console color themes
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConsoleTheme(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme string
+		error string
+	}{
+		{"", "default", "\x1b[31m"},
+		{"default", "default", "\x1b[31m"},
+		{"Colorblind", "colorblind", "\x1b[1;35m"},
+		{"light-256", "light-256", "\x1b[1;38;5;124m"},
+		{"256", "default-256", "\x1b[38;5;196m"},
+		{"truecolor", "default-truecolor", "\x1b[38;2;255;0;0m"},
+		{"colorblind-truecolor", "colorblind-truecolor", "\x1b[1;38;2;213;94;0m"},
+	}
+	for _, tt := range tests {
+		theme, err := ParseConsoleTheme(tt.name)
+		if err != nil {
+			t.Fatalf("ParseConsoleTheme(%q): %v", tt.name, err)
+		}
+		if theme.Name != tt.theme || theme.Levels[Error] != tt.error {
+			t.Errorf("ParseConsoleTheme(%q) = %s with Error %q, want %s with %q",
+				tt.name, theme.Name, theme.Levels[Error], tt.theme, tt.error)
+		}
+	}
+
+	for _, name := range []string{"sepia", "light-88", "-256"} {
+		if _, err := ParseConsoleTheme(name); !IsLoggerError(err, ErrCodeInvalidConfig) {
+			t.Errorf("ParseConsoleTheme(%q) error = %v, want ErrCodeInvalidConfig", name, err)
+		}
+	}
+
+	names := ConsoleThemeNames()
+	if len(names) != 9 || names[0] != "colorblind" {
+		t.Errorf("ConsoleThemeNames() = %v", names)
+	}
+	for _, name := range names {
+		if _, err := ParseConsoleTheme(name); err != nil {
+			t.Errorf("listed theme %q does not parse: %v", name, err)
+		}
+	}
+}
+
+func TestConsoleTheme_DefaultMatchesBuiltinScheme(t *testing.T) {
+	theme := NewConsoleTheme("default", ColorMode16)
+	for _, level := range []Level{Trace, Debug, Info, Warn, Error, DPanic, Panic, Fatal} {
+		if got, want := theme.colorize(level, "X"), colorizeLevel(level, "X"); got != want {
+			t.Errorf("%s: theme %q, built-in %q", level, got, want)
+		}
+	}
+}
+
+func TestConsoleTheme_ColorblindLevelsDistinct(t *testing.T) {
+	for _, mode := range []ConsoleColorMode{ColorMode16, ColorMode256, ColorModeTrueColor} {
+		theme := NewConsoleTheme("colorblind", mode)
+		seen := make(map[string]Level)
+		for _, level := range []Level{Debug, Info, Warn, Error, DPanic} {
+			seq := theme.Levels[level]
+			if other, dup := seen[seq]; dup {
+				t.Errorf("%s: %s and %s share %q", mode, level, other, seq)
+			}
+			seen[seq] = level
+		}
+	}
+}
+
+func TestConsoleEncoder_Theme(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	encode := func(enc *ConsoleEncoder, level Level) string {
+		var buf bytes.Buffer
+		enc.Encode(NewRecord(level, "msg"), now, &buf)
+		return buf.String()
+	}
+
+	enc := NewColorConsoleEncoder()
+	enc.Theme = NewConsoleTheme("light", ColorMode256)
+	if out := encode(enc, Warn); !strings.Contains(out, "\x1b[38;5;130mWARN\x1b[0m") {
+		t.Errorf("themed output = %q", out)
+	}
+
+	// LevelColors still override the theme
+	enc.LevelColors = map[Level]string{Warn: "\x1b[36m"}
+	if out := encode(enc, Warn); !strings.Contains(out, "\x1b[36mWARN\x1b[0m") {
+		t.Errorf("overridden output = %q", out)
+	}
+
+	// Themes only apply with EnableColor
+	enc.EnableColor = false
+	if out := encode(enc, Warn); strings.Contains(out, "\x1b[") {
+		t.Errorf("uncolored output = %q", out)
+	}
+}
+
+func TestNewColorConsoleEncoder_ThemeEnv(t *testing.T) {
+	t.Setenv(ConsoleThemeEnv, "colorblind-256")
+	if enc := NewColorConsoleEncoder(); enc.Theme == nil || enc.Theme.Name != "colorblind-256" {
+		t.Errorf("Theme = %+v, want colorblind-256", enc.Theme)
+	}
+
+	t.Setenv(ConsoleThemeEnv, "sepia")
+	if enc := NewColorConsoleEncoder(); enc.Theme != nil {
+		t.Errorf("unknown theme selected %s", enc.Theme.Name)
+	}
+}
+
+func TestConfigLoaders_ConsoleTheme(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iris.json")
+	if err := os.WriteFile(path, []byte(`{"format": "console", "console_theme": "light"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConsoleThemeEnv, "")
+
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON: %v", err)
+	}
+	enc, ok := cfg.Encoder.(*ConsoleEncoder)
+	if !ok || !enc.EnableColor || enc.Theme == nil || enc.Theme.Name != "light" {
+		t.Fatalf("Encoder = %#v, want a colored console encoder with the light theme", cfg.Encoder)
+	}
+
+	// The environment wins over the file
+	t.Setenv(ConsoleThemeEnv, "truecolor")
+	cfg, err = LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON: %v", err)
+	}
+	if enc := cfg.Encoder.(*ConsoleEncoder); enc.Theme.Name != "default-truecolor" {
+		t.Errorf("Theme = %s, want default-truecolor", enc.Theme.Name)
+	}
+
+	t.Setenv("IRIS_FORMAT", "console")
+	cfg, err = LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv: %v", err)
+	}
+	if enc, ok := cfg.Encoder.(*ConsoleEncoder); !ok || enc.Theme.Name != "default-truecolor" {
+		t.Errorf("Encoder = %#v, want the truecolor theme", cfg.Encoder)
+	}
+
+	// Without a theme, console keeps the plain text encoder
+	t.Setenv(ConsoleThemeEnv, "")
+	cfg, err = LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv: %v", err)
+	}
+	if _, ok := cfg.Encoder.(*TextEncoder); !ok {
+		t.Errorf("Encoder = %T, want *TextEncoder", cfg.Encoder)
+	}
+}
@@ -0,0 +1,122 @@
+// encoder-cnsl_tz.go: Time zone of timestamps rendered by the console encoder
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// ConsoleTimeZoneEnv names the environment variable selecting the time zone
+// of NewConsoleEncoder, NewColorConsoleEncoder and of console output set up
+// by the config loaders.
+const ConsoleTimeZoneEnv = "IRIS_CONSOLE_TIMEZONE"
+
+// ParseConsoleTimeZone returns the location named by zone, for
+// ConsoleEncoder.TimeZone.
+//
+// Accepted names are "local" (the zone of the machine), "UTC" (or "Z"), an
+// IANA name such as "Europe/Rome" or "America/New_York", and a fixed
+// offset: "+05:30", "-0800", "+02", optionally prefixed with "UTC". IANA
+// names need the zone database of the system; programs running where it
+// may be missing (scratch containers, Windows) can embed it by importing
+// time/tzdata.
+//
+// Parameters:
+//   - zone: Time zone name (case-insensitive except for IANA names)
+//
+// Returns:
+//   - *time.Location: The location
+//   - error: ErrCodeInvalidConfig for an unknown zone or an invalid offset
+func ParseConsoleTimeZone(zone string) (*time.Location, error) {
+	name := strings.TrimSpace(zone)
+	switch strings.ToLower(name) {
+	case "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+
+	offset := name
+	if len(offset) > 3 && strings.EqualFold(offset[:3], "utc") {
+		offset = offset[3:]
+	}
+	if offset != "" && (offset[0] == '+' || offset[0] == '-') {
+		if seconds, ok := parseZoneOffset(offset); ok {
+			return time.FixedZone(formatZoneOffset(seconds), seconds), nil
+		}
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig,
+			"invalid console time zone offset (want ±HH, ±HHMM or ±HH:MM)", "console_timezone", zone)
+	}
+
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig,
+		"unknown console time zone (want local, UTC, an IANA name or an offset)", "console_timezone", zone)
+}
+
+// parseZoneOffset returns the seconds east of UTC of an offset written as
+// ±HH, ±HHMM or ±HH:MM, up to ±14:00.
+func parseZoneOffset(s string) (int, bool) {
+	sign, rest := 1, s[1:]
+	if s[0] == '-' {
+		sign = -1
+	}
+	if len(rest) == 5 && rest[2] == ':' {
+		rest = rest[:2] + rest[3:]
+	}
+	if len(rest) != 2 && len(rest) != 4 {
+		return 0, false
+	}
+	for i := 0; i < len(rest); i++ {
+		if rest[i] < '0' || rest[i] > '9' {
+			return 0, false
+		}
+	}
+	hours := int(rest[0]-'0')*10 + int(rest[1]-'0')
+	minutes := 0
+	if len(rest) == 4 {
+		minutes = int(rest[2]-'0')*10 + int(rest[3]-'0')
+	}
+	seconds := hours*3600 + minutes*60
+	if minutes >= 60 || seconds > 14*3600 {
+		return 0, false
+	}
+	return sign * seconds, true
+}
+
+// formatZoneOffset names a fixed zone after its offset ("+05:30"), so that
+// layouts printing the zone abbreviation show the offset.
+func formatZoneOffset(seconds int) string {
+	sign := byte('+')
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	b := []byte{sign, 0, 0, ':', 0, 0}
+	hours, minutes := seconds/3600, seconds%3600/60
+	b[1], b[2] = byte('0'+hours/10), byte('0'+hours%10)
+	b[4], b[5] = byte('0'+minutes/10), byte('0'+minutes%10)
+	return string(b)
+}
+
+// consoleTimeZoneFromEnv returns the location named by
+// IRIS_CONSOLE_TIMEZONE, or nil when it is unset or unknown.
+func consoleTimeZoneFromEnv() *time.Location {
+	zone := os.Getenv(ConsoleTimeZoneEnv)
+	if zone == "" {
+		return nil
+	}
+	loc, err := ParseConsoleTimeZone(zone)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
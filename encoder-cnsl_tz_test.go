@@ -0,0 +1,139 @@
+// encoder-cnsl_tz_test.go: Tests for console time zone rendering
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConsoleTimeZone(t *testing.T) {
+	instant := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		zone   string
+		offset int
+	}{
+		{"UTC", 0},
+		{"z", 0},
+		{"+05:30", 5*3600 + 30*60},
+		{"-0800", -8 * 3600},
+		{"+02", 2 * 3600},
+		{"UTC+01:00", 3600},
+		{"utc-03", -3 * 3600},
+	}
+	for _, tt := range tests {
+		loc, err := ParseConsoleTimeZone(tt.zone)
+		if err != nil {
+			t.Fatalf("ParseConsoleTimeZone(%q): %v", tt.zone, err)
+		}
+		if _, offset := instant.In(loc).Zone(); offset != tt.offset {
+			t.Errorf("ParseConsoleTimeZone(%q) offset = %d, want %d", tt.zone, offset, tt.offset)
+		}
+	}
+
+	if loc, _ := ParseConsoleTimeZone(" Local "); loc != time.Local {
+		t.Errorf("ParseConsoleTimeZone(local) = %v, want time.Local", loc)
+	}
+	if loc, _ := ParseConsoleTimeZone("+05:30"); loc.String() != "+05:30" {
+		t.Errorf("fixed zone name = %q, want +05:30", loc.String())
+	}
+	if loc, err := ParseConsoleTimeZone("Europe/Rome"); err == nil {
+		if _, offset := instant.In(loc).Zone(); offset != 2*3600 {
+			t.Errorf("Europe/Rome offset in July = %d", offset)
+		}
+	} // The zone database may be missing on the test machine
+
+	for _, zone := range []string{"", "Mars/Olympus", "+5", "+15:00", "+05:60", "+0a:00", "-05:3"} {
+		if _, err := ParseConsoleTimeZone(zone); !IsLoggerError(err, ErrCodeInvalidConfig) {
+			t.Errorf("ParseConsoleTimeZone(%q) error = %v, want ErrCodeInvalidConfig", zone, err)
+		}
+	}
+}
+
+func TestConsoleEncoder_TimeZone(t *testing.T) {
+	now := time.Date(2025, 9, 6, 22, 30, 0, 0, time.UTC)
+	loc, _ := ParseConsoleTimeZone("+05:30")
+
+	enc := &ConsoleEncoder{TimeFormat: "2006-01-02 15:04 MST", TimeZone: loc}
+	rec := NewRecord(Info, "deploy")
+	rec.AddField(Time("started", now.Add(-time.Hour)))
+	var buf bytes.Buffer
+	enc.Encode(rec, now, &buf)
+
+	want := "2025-09-07 04:00 +05:30 INFO deploy started=2025-09-07T03:00:00+05:30\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	// Rendering does not change the stored instant
+	if rec.fields[0].I64 != now.Add(-time.Hour).UnixNano() {
+		t.Error("time field modified by the encoder")
+	}
+
+	// Without a zone the clock's time is rendered as given
+	buf.Reset()
+	enc.TimeZone = nil
+	enc.Encode(NewRecord(Info, "deploy"), now, &buf)
+	if !strings.HasPrefix(buf.String(), "2025-09-06 22:30 UTC ") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestConsoleEncoder_TimeZoneEnv(t *testing.T) {
+	t.Setenv(ConsoleTimeZoneEnv, "-03:00")
+	for _, enc := range []*ConsoleEncoder{NewConsoleEncoder(), NewColorConsoleEncoder()} {
+		if enc.TimeZone == nil || enc.TimeZone.String() != "-03:00" {
+			t.Errorf("TimeZone = %v, want -03:00", enc.TimeZone)
+		}
+	}
+
+	t.Setenv(ConsoleTimeZoneEnv, "Mars/Olympus")
+	if enc := NewConsoleEncoder(); enc.TimeZone != nil {
+		t.Errorf("TimeZone = %v for an unknown zone, want nil", enc.TimeZone)
+	}
+}
+
+func TestConfigLoaders_ConsoleTimeZone(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iris.json")
+	if err := os.WriteFile(path, []byte(`{"format": "console", "console_timezone": "+01:00"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConsoleThemeEnv, "")
+	t.Setenv(ConsoleTimeZoneEnv, "")
+
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON: %v", err)
+	}
+	enc, ok := cfg.Encoder.(*ConsoleEncoder)
+	if !ok || enc.EnableColor || enc.TimeZone == nil || enc.TimeZone.String() != "+01:00" {
+		t.Fatalf("Encoder = %#v, want an uncolored console encoder in +01:00", cfg.Encoder)
+	}
+
+	// The environment wins over the file
+	t.Setenv(ConsoleTimeZoneEnv, "UTC")
+	cfg, err = LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON: %v", err)
+	}
+	if enc := cfg.Encoder.(*ConsoleEncoder); enc.TimeZone != time.UTC {
+		t.Errorf("TimeZone = %v, want UTC", enc.TimeZone)
+	}
+
+	t.Setenv("IRIS_FORMAT", "console")
+	cfg, err = LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv: %v", err)
+	}
+	if enc, ok := cfg.Encoder.(*ConsoleEncoder); !ok || enc.TimeZone != time.UTC {
+		t.Errorf("Encoder = %#v, want a console encoder in UTC", cfg.Encoder)
+	}
+}
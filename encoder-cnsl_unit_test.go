@@ -594,3 +594,153 @@ func TestConsoleEncoder_EmptyLevelCasing(t *testing.T) {
 		t.Errorf("Expected uppercase level, got: %s", output)
 	}
 }
+
+// TestConsoleEncoder_LevelSections tests per-level section visibility
+func TestConsoleEncoder_LevelSections(t *testing.T) {
+	encoder := NewConsoleEncoder()
+	encoder.TimeFormat = "2006-01-02"
+	encoder.LevelSections = map[Level]ConsoleSection{
+		Info:  ConsoleSectionNone,
+		Warn:  ConsoleSectionTime | ConsoleSectionLogger,
+		Error: ConsoleSectionsAll,
+	}
+	now := time.Date(2025, 9, 6, 14, 30, 45, 0, time.UTC)
+
+	newRecord := func(level Level) *Record {
+		rec := NewRecord(level, "event")
+		rec.Logger = "svc"
+		rec.AddField(Str("caller", "main.go:10"))
+		rec.AddField(Str("stack", "goroutine 1"))
+		rec.AddField(Int("n", 1))
+		return rec
+	}
+
+	tests := []struct {
+		level    Level
+		contains []string
+		absent   []string
+	}{
+		{Info, []string{"INFO event n=1"}, []string{"2025-09-06", "svc", "caller=", "stack="}},
+		{Warn, []string{"2025-09-06 WARN svc event", "n=1"}, []string{"caller=", "stack="}},
+		{Error, []string{"2025-09-06 ERROR svc event", "caller=main.go:10", `stack="goroutine 1"`}, nil},
+		// Levels without an entry render everything
+		{Debug, []string{"2025-09-06 DEBUG svc event", "caller=main.go:10"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder.Encode(newRecord(tt.level), now, &buf)
+			output := buf.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(output, s) {
+					t.Errorf("Expected %q in output: %q", s, output)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(output, s) {
+					t.Errorf("Did not expect %q in output: %q", s, output)
+				}
+			}
+		})
+	}
+}
+
+// TestConsoleEncoder_LevelColors tests per-level color overrides
+func TestConsoleEncoder_LevelColors(t *testing.T) {
+	encoder := NewColorConsoleEncoder()
+	encoder.LevelColors = map[Level]string{Info: "\x1b[36m"}
+
+	var buf bytes.Buffer
+	encoder.Encode(NewRecord(Info, "hello"), time.Now(), &buf)
+	if !strings.Contains(buf.String(), "\x1b[36mINFO\x1b[0m") {
+		t.Errorf("Expected overridden color for Info, got %q", buf.String())
+	}
+
+	buf.Reset()
+	encoder.Encode(NewRecord(Warn, "hello"), time.Now(), &buf)
+	if !strings.Contains(buf.String(), "\x1b[33mWARN\x1b[0m") {
+		t.Errorf("Expected default color for Warn, got %q", buf.String())
+	}
+}
+
+// TestConsoleEncoder_WidthControls tests wrapping, truncation and sorting
+func TestConsoleEncoder_WidthControls(t *testing.T) {
+	now := time.Date(2025, 9, 6, 14, 30, 45, 0, time.UTC)
+	newRecord := func() *Record {
+		rec := NewRecord(Info, "request")
+		rec.AddField(Str("path", "/api/v1/users"))
+		rec.AddField(Int("status", 200))
+		rec.AddField(Str("agent", "Mozilla/5.0 (X11; Linux x86_64)"))
+		rec.AddField(Str("id", "abc"))
+		return rec
+	}
+
+	tests := []struct {
+		name string
+		set  func(e *ConsoleEncoder)
+		want string
+	}{
+		{
+			name: "defaults unchanged",
+			set:  func(e *ConsoleEncoder) {},
+			want: `INFO request path=/api/v1/users status=200 agent="Mozilla/5.0 (X11; Linux x86_64)" id=abc` + "\n",
+		},
+		{
+			name: "truncate values",
+			set:  func(e *ConsoleEncoder) { e.MaxFieldValueLen = 8 },
+			want: `INFO request path=/api/v1… status=200 agent=Mozilla… id=abc` + "\n",
+		},
+		{
+			name: "truncate to ellipsis only",
+			set:  func(e *ConsoleEncoder) { e.MaxFieldValueLen = 1 },
+			want: "INFO request path=… status=200 agent=… id=…\n",
+		},
+		{
+			name: "sort fields",
+			set: func(e *ConsoleEncoder) {
+				e.SortFields = true
+				e.MaxFieldValueLen = 5
+			},
+			want: "INFO request agent=Mozi… id=abc path=/api… status=200\n",
+		},
+		{
+			name: "wrap lines",
+			set:  func(e *ConsoleEncoder) { e.MaxLineWidth = 42 },
+			want: "INFO request path=/api/v1/users status=200\n" +
+				`  agent="Mozilla/5.0 (X11; Linux x86_64)"` + "\n" +
+				"  id=abc\n",
+		},
+		{
+			name: "wrap counts characters not bytes",
+			set: func(e *ConsoleEncoder) {
+				e.MaxLineWidth = 35
+				e.MaxFieldValueLen = 6
+			},
+			want: "INFO request path=/api/… status=200\n  agent=Mozil… id=abc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := NewConsoleEncoder()
+			encoder.LevelSections = map[Level]ConsoleSection{Info: ConsoleSectionNone}
+			tt.set(encoder)
+			var buf bytes.Buffer
+			encoder.Encode(newRecord(), now, &buf)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	// Color codes do not count towards the width
+	encoder := NewColorConsoleEncoder()
+	encoder.LevelSections = map[Level]ConsoleSection{Info: ConsoleSectionNone}
+	encoder.MaxLineWidth = 42
+	var buf bytes.Buffer
+	encoder.Encode(newRecord(), now, &buf)
+	if first := strings.SplitN(buf.String(), "\n", 2)[0]; !strings.HasSuffix(first, "status=200") {
+		t.Errorf("colored first line = %q, want it to end with status=200", first)
+	}
+}
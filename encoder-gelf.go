@@ -0,0 +1,172 @@
+// encoder-gelf.go: GELF 1.1 encoder for Graylog
+//
+// GELFEncoder writes each record as a Graylog Extended Log Format 1.1
+// message: a flat JSON object with the mandatory version, host and
+// short_message, the timestamp in seconds, the syslog severity as level,
+// and every field as an additional field prefixed with '_'. Pair it with
+// NewGELFWriter to ship the messages over UDP (chunked and compressed) or
+// TCP, or write them to any output.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GELFVersion is the GELF specification version of the messages.
+const GELFVersion = "1.1"
+
+// GELFEncoder encodes records as GELF 1.1 messages, one JSON object per
+// line:
+//
+//	{"version":"1.1","host":"web-1","short_message":"...","timestamp":1700000000.123456,"level":6,"_logger":"api","_user":"alice"}
+//
+// The level is the syslog severity (see SyslogEncoder) and _level the Iris
+// level name. The stack trace, when present, is the full_message. GELF
+// forbids nested values and the _id field: objects, errors and other
+// values are written as strings (booleans as "true" and "false"), field keys are reduced to the characters
+// [A-Za-z0-9_.-], and a field named "id" is written as "__id". Durations
+// are written in nanoseconds, as the JSON encoder does, times as RFC 3339
+// strings and byte slices in hex.
+type GELFEncoder struct {
+	// Host fills the host field (NewGELFEncoder uses the host name);
+	// "unknown" when empty.
+	Host string
+}
+
+// NewGELFEncoder creates a GELFEncoder reporting the host name of this
+// machine.
+//
+// Returns:
+//   - *GELFEncoder: Encoder ready for use as Config.Encoder
+//
+// Example:
+//
+//	out, err := iris.NewGELFWriter(iris.GELFConfig{Address: "graylog.example:12201"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewGELFEncoder()})
+func NewGELFEncoder() *GELFEncoder {
+	host, _ := os.Hostname()
+	return &GELFEncoder{Host: host}
+}
+
+// Encode writes rec as a GELF message followed by a newline.
+func (e *GELFEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
+	host := e.Host
+	if host == "" {
+		host = "unknown"
+	}
+	buf.WriteString(`{"version":"` + GELFVersion + `","host":`)
+	quoteString(host, buf)
+
+	// short_message is mandatory and must not be empty
+	buf.WriteString(`,"short_message":`)
+	if rec.Msg != "" {
+		quoteString(rec.Msg, buf)
+	} else {
+		quoteString(rec.Level.String(), buf)
+	}
+	if rec.Stack != "" {
+		buf.WriteString(`,"full_message":`)
+		quoteString(rec.Stack, buf)
+	}
+
+	buf.WriteString(`,"timestamp":`)
+	micros := now.UnixMicro()
+	writeInt(buf, micros/1e6)
+	buf.WriteByte('.')
+	frac := strconv.FormatInt(micros%1e6, 10)
+	for i := len(frac); i < 6; i++ {
+		buf.WriteByte('0')
+	}
+	buf.WriteString(frac)
+
+	buf.WriteString(`,"level":`)
+	writeInt(buf, int64(syslogSeverity(rec.Level)))
+	buf.WriteString(`,"_level":`)
+	quoteString(rec.Level.String(), buf)
+	if rec.Logger != "" {
+		buf.WriteString(`,"_logger":`)
+		quoteString(rec.Logger, buf)
+	}
+	if rec.Caller != "" {
+		buf.WriteString(`,"_caller":`)
+		quoteString(rec.Caller, buf)
+	}
+
+	for i := int32(0); i < rec.n; i++ {
+		f := &rec.fields[i]
+		buf.WriteString(`,"_`)
+		writeGELFName(buf, f.K)
+		buf.WriteString(`":`)
+		writeGELFValue(buf, f)
+	}
+	buf.WriteString("}\n")
+}
+
+// writeGELFName writes a field key without its '_' prefix: characters
+// outside [A-Za-z0-9_.-] become '_', and "id" (the reserved _id) becomes
+// "_id".
+func writeGELFName(buf *bytes.Buffer, key string) {
+	if key == "" || key == "id" {
+		buf.WriteByte('_')
+	}
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.', c == '-':
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte('_')
+		}
+	}
+}
+
+// writeGELFValue writes the value of f as a JSON string or number.
+func writeGELFValue(buf *bytes.Buffer, f *Field) {
+	switch f.T {
+	case kindString:
+		quoteString(f.Str, buf)
+	case kindSecret:
+		buf.WriteString(`"[REDACTED]"`)
+	case kindInt64, kindDur:
+		writeInt(buf, f.I64)
+	case kindUint64:
+		writeUint(buf, f.U64)
+	case kindFloat64:
+		if math.IsNaN(f.F64) || math.IsInf(f.F64, 0) {
+			quoteString(strconv.FormatFloat(f.F64, 'g', -1, 64), buf)
+		} else {
+			writeFloat(buf, f.F64, 'f')
+		}
+	case kindBool:
+		if f.I64 != 0 {
+			buf.WriteString(`"true"`)
+		} else {
+			buf.WriteString(`"false"`)
+		}
+	case kindTime:
+		buf.WriteByte('"')
+		writeTime(buf, time.Unix(0, f.I64).UTC(), time.RFC3339Nano)
+		buf.WriteByte('"')
+	case kindBytes:
+		quoteString(hex.EncodeToString(f.B), buf)
+	default:
+		if v := f.Value(); v != nil {
+			quoteString(fmt.Sprint(v), buf)
+		} else {
+			buf.WriteString(`""`)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// encoder-gelf_test.go: Tests for the GELF encoder
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFEncoder_Encode(t *testing.T) {
+	rec := NewRecord(Warn, "disk almost full")
+	rec.Logger = "storage"
+	rec.Caller = "disk.go:42"
+	rec.AddField(Str("mount", "/var"))
+	rec.AddField(Int("id", 7))
+	rec.AddField(Float64("ratio", 0.93))
+	rec.AddField(Float64("rate", math.Inf(1)))
+	rec.AddField(Bool("degraded", true))
+	rec.AddField(Dur("elapsed", 1500*time.Millisecond))
+	rec.AddField(Secret("token", "s3cr3t"))
+	rec.AddField(Err(errors.New("no space")))
+	rec.AddField(Str("user name", "alice"))
+
+	var buf bytes.Buffer
+	now := time.Date(2025, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	(&GELFEncoder{Host: "web-1"}).Encode(rec, now, &buf)
+
+	if !strings.HasSuffix(buf.String(), "}\n") {
+		t.Fatalf("message not newline terminated: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"timestamp":1740830400.123456,`) {
+		t.Errorf("timestamp not in seconds with microseconds: %s", buf.String())
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "disk almost full",
+		"level":         float64(4),
+		"_level":        "warn",
+		"_logger":       "storage",
+		"_caller":       "disk.go:42",
+		"_mount":        "/var",
+		"__id":          float64(7),
+		"_ratio":        0.93,
+		"_rate":         "+Inf",
+		"_degraded":     "true",
+		"_elapsed":      float64(1500 * time.Millisecond),
+		"_token":        "[REDACTED]",
+		"_error":        "no space",
+		"_user_name":    "alice",
+	}
+	for key, value := range want {
+		if msg[key] != value {
+			t.Errorf("%s = %#v, want %#v", key, msg[key], value)
+		}
+	}
+	if _, ok := msg["_id"]; ok {
+		t.Error("reserved _id field written")
+	}
+}
+
+func TestGELFEncoder_FullMessageAndEmptyMessage(t *testing.T) {
+	rec := NewRecord(Error, "")
+	rec.Stack = "goroutine 1 [running]:\nmain.main()"
+
+	var buf bytes.Buffer
+	(&GELFEncoder{}).Encode(rec, time.Unix(1, 0), &buf)
+	var msg map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+	if msg["short_message"] != "error" || msg["host"] != "unknown" || msg["level"] != float64(3) {
+		t.Errorf("message = %v", msg)
+	}
+	if msg["full_message"] != rec.Stack {
+		t.Errorf("full_message = %q", msg["full_message"])
+	}
+	if !strings.Contains(buf.String(), `"timestamp":1.000000,`) {
+		t.Errorf("timestamp = %s", buf.String())
+	}
+}
@@ -9,7 +9,7 @@ package iris
 import (
 	"bytes"
 	"fmt"
-	"strconv"
+	"math/bits"
 	"time"
 
 	"github.com/agilira/go-timecache"
@@ -24,6 +24,19 @@ type Record struct {
 	Stack  string    // Stack trace
 	fields [32]Field // Optimized field array - 32 fields covers 99.9% of use cases
 	n      int32     // Number of active fields
+	lost   int32     // Fields dropped because the array was full
+	seal   uint64    // Content checksum in record debug mode (0 = unsealed)
+
+	enqueued int64     // Monotonic enqueue time when latency histograms are enabled (0 = unset)
+	time     time.Time // Timestamp override from SetTime or IngestTime (zero = encoding time)
+
+	session     *debugSession // Debug session that captured the record (nil = none)
+	sessionOnly bool          // Admitted only by the session, not by the level filter
+	routes      uint32        // Pipeline sinks the record was routed to (bit per sink)
+
+	// Processed ahead of ring order by Logger.Shutdown; the consumer skips
+	// it. Not cleared by resetForWrite, which runs when it is processed.
+	prioritized bool
 }
 
 // resetForWrite resets a record for reuse in the ring buffer
@@ -34,6 +47,13 @@ func (r *Record) resetForWrite() {
 	r.Caller = ""
 	r.Stack = ""
 	r.n = 0
+	r.lost = 0
+	r.seal = 0
+	r.enqueued = 0
+	r.time = time.Time{}
+	r.session = nil
+	r.sessionOnly = false
+	r.routes = 0
 }
 
 // NewRecord creates a new Record with the specified level and message.
@@ -47,9 +67,18 @@ func NewRecord(level Level, msg string) *Record {
 }
 
 // AddField adds a structured field to this record.
-// Returns false if the field array is full (32 fields max - optimal for performance).
+// Returns false if the field array is full (32 fields max - optimal for performance);
+// the dropped field is then counted in TruncatedFields.
 func (r *Record) AddField(field Field) bool {
+	switch field.T {
+	case kindNoSample:
+		return true // Markers only affect sampling, they are never stored
+	case kindIngestTime:
+		r.time = field.ingestTime()
+		return true
+	}
 	if r.n >= 32 {
+		r.lost++
 		return false
 	}
 	r.fields[r.n] = field
@@ -57,11 +86,67 @@ func (r *Record) AddField(field Field) bool {
 	return true
 }
 
+// SetTime sets the timestamp written for this record, overriding the time
+// at which the logger encodes it. Use it in Logger.Write fill functions to
+// re-ingest historical events with their original timestamps; the zero
+// time restores the default.
+//
+// Example:
+//
+//	logger.Write(func(r *iris.Record) {
+//	    r.Level = iris.Info
+//	    r.Msg = ev.Message
+//	    r.SetTime(ev.Timestamp)
+//	})
+func (r *Record) SetTime(t time.Time) {
+	r.time = t
+}
+
+// Time returns the timestamp set with SetTime or IngestTime, or the zero
+// time if the record is stamped when it is encoded.
+func (r *Record) Time() time.Time {
+	return r.time
+}
+
 // FieldCount returns the number of fields in this record.
 func (r *Record) FieldCount() int {
 	return int(r.n)
 }
 
+// TruncatedFields returns the number of fields that were dropped because the
+// record already held the maximum of 32. Encoders with MarkTruncated set
+// write it as TruncatedFieldsKey.
+func (r *Record) TruncatedFields() int {
+	return int(r.lost)
+}
+
+// EstimatedSize returns an estimate of the encoded size of the record in
+// bytes, used to acquire a large enough buffer before encoding. It adds the
+// lengths of the message, logger name, caller, stack and every field key
+// and value, with fixed allowances for numbers, timestamps and values whose
+// length is only known once formatted (errors, stringers, objects). The
+// estimate ignores escaping, so encoders may still grow the buffer slightly.
+func (r *Record) EstimatedSize() int {
+	size := 96 + len(r.Msg) + len(r.Logger) + len(r.Caller) + len(r.Stack)
+	for i := int32(0); i < r.n; i++ {
+		f := &r.fields[i]
+		size += len(f.K) + 6 // Quotes, separator and comma
+		switch f.T {
+		case kindString:
+			size += len(f.Str)
+		case kindBytes:
+			size += 4 * len(f.B) // Up to "255," per byte in JSON
+		case kindTime:
+			size += 36
+		case kindError, kindStringer, kindObject:
+			size += 64
+		default:
+			size += 20 // Longest int64, uint64 or float64
+		}
+	}
+	return size
+}
+
 // GetField returns the field at the specified index.
 // Panics if index is out of bounds (for test simplicity).
 func (r *Record) GetField(index int) Field {
@@ -72,6 +157,24 @@ func (r *Record) GetField(index int) Field {
 	return r.fields[index]
 }
 
+// fieldOrder fills order (of length FieldCount) with field indexes, sorted
+// by key when sorted is set. The insertion sort is stable, so repeated keys
+// keep their relative order, and allocation-free for the at most 32 fields
+// of a record.
+func (r *Record) fieldOrder(order []int32, sorted bool) {
+	for i := range order {
+		order[i] = int32(i)
+	}
+	if !sorted {
+		return
+	}
+	for i := 1; i < len(order); i++ {
+		for j := i; j > 0 && r.fields[order[j]].K < r.fields[order[j-1]].K; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+}
+
 // Reset clears the record for reuse.
 func (r *Record) Reset() {
 	r.Level = Debug
@@ -80,6 +183,13 @@ func (r *Record) Reset() {
 	r.Caller = ""
 	r.Stack = ""
 	r.n = 0
+	r.lost = 0
+	r.seal = 0
+	r.enqueued = 0
+	r.time = time.Time{}
+	r.session = nil
+	r.sessionOnly = false
+	r.routes = 0
 }
 
 // Encoder astratto (permette anche encoder binari futuri).
@@ -121,8 +231,39 @@ type JSONEncoder struct {
 	//   true:  RFC3339 string format (default, human-readable)
 	//   false: Unix nanoseconds integer (compact, faster)
 	RFC3339 bool
+
+	// ErrorChain encodes error fields (ErrorField, NamedError) as their full
+	// Unwrap() chain instead of the flattened Error() string:
+	//
+	//	"error":[{"type":"*fmt.wrapError","message":"load config: open app.yaml: no such file or directory"},
+	//	         {"type":"*fs.PathError","message":"open app.yaml: no such file or directory"},
+	//	         {"type":"syscall.Errno","message":"no such file or directory"}]
+	//
+	// The outermost error comes first and the root cause last, so pipelines
+	// can filter on the type of the last element. Errors joined with
+	// errors.Join (Unwrap() []error) are walked depth-first in order.
+	// Default false.
+	ErrorChain bool
+
+	// SortFields writes structured fields in key order instead of the order
+	// they were added, so the same record always produces the same bytes
+	// (for golden tests and diffing). Built-in keys (time, level, logger,
+	// message, caller, stack) still come first in their fixed order; fields
+	// with the same key keep their relative order. Default false: the
+	// unordered path has no sorting cost.
+	SortFields bool
+
+	// MarkTruncated appends "_truncated_fields":N (TruncatedFieldsKey) after
+	// the fields of a record that had N fields dropped because it already
+	// held 32, so that consumers know context was lost. Default false:
+	// truncation is silent.
+	MarkTruncated bool
 }
 
+// TruncatedFieldsKey is the key under which encoders with MarkTruncated
+// write the number of fields dropped from a full record.
+const TruncatedFieldsKey = "_truncated_fields"
+
 // NewJSONEncoder creates a new JSON encoder with standard defaults.
 //
 // Default configuration:
@@ -180,6 +321,10 @@ func (e *JSONEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
 	e.encodeLevel(rec, buf)
 	e.encodeOptionalFields(rec, buf)
 	e.encodeFields(rec, buf)
+	if e.MarkTruncated && rec.lost > 0 {
+		buf.WriteString(`,"` + TruncatedFieldsKey + `":`)
+		writeInt(buf, int64(rec.lost))
+	}
 
 	buf.WriteByte('}')
 	buf.WriteByte('\n')
@@ -197,16 +342,16 @@ func (e *JSONEncoder) encodeTimestamp(now time.Time, buf *bytes.Buffer) {
 			buf.WriteString(timecache.CachedTimeString()) // Use cached formatted time for performance
 		} else {
 			// Use exact time for testing or historical timestamps
-			buf.WriteString(now.Format(time.RFC3339Nano))
+			writeTime(buf, now, time.RFC3339Nano)
 		}
 		buf.WriteByte('"')
 	} else {
 		// For Unix timestamps, if time is close to current, use cached nano time
 		if e.shouldUseTimeCache(now) {
-			buf.WriteString(strconv.FormatInt(timecache.CachedTimeNano(), 10))
+			writeInt(buf, timecache.CachedTimeNano())
 		} else {
 			// Use exact Unix nanoseconds for testing
-			buf.WriteString(strconv.FormatInt(now.UnixNano(), 10))
+			writeInt(buf, now.UnixNano())
 		}
 	}
 }
@@ -256,12 +401,28 @@ func (e *JSONEncoder) encodeOptionalFields(rec *Record, buf *bytes.Buffer) {
 
 // encodeFields writes all the custom fields
 func (e *JSONEncoder) encodeFields(rec *Record, buf *bytes.Buffer) {
+	if e.SortFields {
+		e.encodeSortedFields(rec, buf)
+		return
+	}
 	for i := int32(0); i < rec.n; i++ {
 		f := rec.fields[i]
 		buf.WriteByte(',')
-		buf.WriteByte('"')
-		buf.WriteString(f.K)
-		buf.WriteString(`":`)
+		quoteString(f.K, buf) // Keys can come from user input too
+		buf.WriteByte(':')
+		e.encodeFieldValue(&f, buf)
+	}
+}
+
+// encodeSortedFields writes the custom fields in key order
+func (e *JSONEncoder) encodeSortedFields(rec *Record, buf *bytes.Buffer) {
+	var order [len(rec.fields)]int32
+	rec.fieldOrder(order[:rec.n], true)
+	for _, i := range order[:rec.n] {
+		f := rec.fields[i]
+		buf.WriteByte(',')
+		quoteString(f.K, buf)
+		buf.WriteByte(':')
 		e.encodeFieldValue(&f, buf)
 	}
 }
@@ -272,11 +433,11 @@ func (e *JSONEncoder) encodeFieldValue(f *Field, buf *bytes.Buffer) {
 	case kindString:
 		quoteString(f.Str, buf)
 	case kindInt64:
-		buf.WriteString(strconv.FormatInt(f.I64, 10))
+		writeInt(buf, f.I64)
 	case kindUint64:
-		buf.WriteString(strconv.FormatUint(f.U64, 10))
+		writeUint(buf, f.U64)
 	case kindFloat64:
-		buf.WriteString(strconv.FormatFloat(f.F64, 'f', -1, 64))
+		writeFloat(buf, f.F64, 'f')
 	case kindBool:
 		if f.I64 != 0 {
 			buf.WriteString("true")
@@ -285,7 +446,7 @@ func (e *JSONEncoder) encodeFieldValue(f *Field, buf *bytes.Buffer) {
 		}
 	case kindDur:
 		// duration in ns (int64)
-		buf.WriteString(strconv.FormatInt(f.I64, 10))
+		writeInt(buf, f.I64)
 	case kindTime:
 		e.encodeTimeField(f, buf)
 	case kindBytes:
@@ -312,7 +473,7 @@ func (e *JSONEncoder) encodeTimeField(f *Field, buf *bytes.Buffer) {
 		buf.WriteString(timecache.CachedTimeString())
 	} else {
 		// For older timestamps, still need to format but this should be rare
-		buf.WriteString(timeValue.Format(time.RFC3339Nano))
+		writeTime(buf, timeValue, time.RFC3339Nano)
 	}
 	buf.WriteByte('"')
 }
@@ -325,7 +486,7 @@ func (e *JSONEncoder) encodeBytesField(f *Field, buf *bytes.Buffer) {
 		if j > 0 {
 			buf.WriteByte(',')
 		}
-		buf.WriteString(strconv.Itoa(int(b)))
+		writeInt(buf, int64(b))
 	}
 	buf.WriteByte(']')
 }
@@ -335,12 +496,53 @@ func (e *JSONEncoder) encodeErrorField(f *Field, buf *bytes.Buffer) {
 	if f.Obj == nil {
 		buf.WriteString(`null`)
 	} else if err, ok := f.Obj.(error); ok {
+		if e.ErrorChain {
+			encodeErrorChain(err, buf)
+			return
+		}
 		quoteString(err.Error(), buf)
 	} else {
 		quoteString(fmt.Sprintf("%v", f.Obj), buf)
 	}
 }
 
+// maxErrorChain bounds the number of chain elements written for one error,
+// guarding against cyclic or pathologically deep Unwrap implementations.
+const maxErrorChain = 32
+
+// encodeErrorChain writes err and everything it wraps as a JSON array of
+// {"type","message"} objects, outermost first.
+func encodeErrorChain(err error, buf *bytes.Buffer) {
+	buf.WriteByte('[')
+	n := 0
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil || n >= maxErrorChain {
+			return
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+		buf.WriteString(`{"type":`)
+		quoteString(fmt.Sprintf("%T", err), buf)
+		buf.WriteString(`,"message":`)
+		quoteString(err.Error(), buf)
+		buf.WriteByte('}')
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		}
+	}
+	walk(err)
+	buf.WriteByte(']')
+}
+
 // encodeStringerField writes a stringer field
 func (e *JSONEncoder) encodeStringerField(f *Field, buf *bytes.Buffer) {
 	if f.Obj == nil {
@@ -352,6 +554,12 @@ func (e *JSONEncoder) encodeStringerField(f *Field, buf *bytes.Buffer) {
 	}
 }
 
+// jsonObjectEncoder is implemented by library-provided object values that
+// know how to write themselves as a JSON object without reflection.
+type jsonObjectEncoder interface {
+	encodeJSON(buf *bytes.Buffer)
+}
+
 // encodeObjectField writes an object field
 func (e *JSONEncoder) encodeObjectField(f *Field, buf *bytes.Buffer) {
 	if f.Obj == nil {
@@ -371,6 +579,9 @@ func (e *JSONEncoder) encodeObjectField(f *Field, buf *bytes.Buffer) {
 				}
 			}
 			buf.WriteByte(']')
+		} else if obj, ok := f.Obj.(jsonObjectEncoder); ok {
+			// Library-provided structured values (e.g. RuntimeSnapshot)
+			obj.encodeJSON(buf)
 		} else {
 			// Generic object - convert to string
 			quoteString(fmt.Sprintf("%v", f.Obj), buf)
@@ -384,35 +595,34 @@ func quoteString(s string, buf *bytes.Buffer) {
 
 	// Fast path per stringhe senza caratteri speciali
 	start := 0
-	for i := 0; i < len(s); i++ {
+	for i := escapeIndex(s, 0); i < len(s); i = escapeIndex(s, start) {
 		c := s[i]
-		if c < 0x20 || c == '"' || c == '\\' {
-			// Scrivi la parte "pulita" fino a qui
-			if i > start {
-				buf.WriteString(s[start:i])
-			}
 
-			// Gestisci il carattere speciale
-			switch c {
-			case '"':
-				buf.WriteString(`\"`)
-			case '\\':
-				buf.WriteString(`\\`)
-			case '\n':
-				buf.WriteString(`\n`)
-			case '\r':
-				buf.WriteString(`\r`)
-			case '\t':
-				buf.WriteString(`\t`)
-			default:
-				// Carattere di controllo
-				buf.WriteString(`\u00`)
-				const hex = "0123456789abcdef"
-				buf.WriteByte(hex[c>>4])
-				buf.WriteByte(hex[c&0xF])
-			}
-			start = i + 1
+		// Scrivi la parte "pulita" fino a qui
+		if i > start {
+			buf.WriteString(s[start:i])
 		}
+
+		// Gestisci il carattere speciale
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			// Carattere di controllo
+			buf.WriteString(`\u00`)
+			const hex = "0123456789abcdef"
+			buf.WriteByte(hex[c>>4])
+			buf.WriteByte(hex[c&0xF])
+		}
+		start = i + 1
 	}
 
 	// Scrivi la parte finale se rimane
@@ -422,3 +632,44 @@ func quoteString(s string, buf *bytes.Buffer) {
 
 	buf.WriteByte('"')
 }
+
+// SWAR (SIMD within a register) constants: one byte value repeated in each
+// byte of a uint64.
+const (
+	swarOnes  = 0x0101010101010101
+	swarHighs = 0x8080808080808080
+)
+
+// escapeIndex returns the index of the first byte at or after from that
+// needs escaping in a JSON string (a control character, '"' or '\\'), or
+// len(s) if there is none.
+//
+// Most log strings need no escaping at all, so the scan dominates quoting.
+// It checks eight bytes per iteration: the bytes are assembled into a
+// little-endian uint64, which the compiler turns into a single load on
+// amd64 and arm64, and three SWAR tests flag the bytes below 0x20, equal to
+// '"' and equal to '\\'. Borrows only propagate towards higher bytes, so the
+// lowest flagged byte is always a real match and its position is the
+// number of trailing zero bits divided by eight. Bytes of multi-byte UTF-8
+// sequences (0x80 and up) are never flagged.
+func escapeIndex(s string, from int) int {
+	i := from
+	for ; i+8 <= len(s); i += 8 {
+		w := uint64(s[i]) | uint64(s[i+1])<<8 | uint64(s[i+2])<<16 | uint64(s[i+3])<<24 |
+			uint64(s[i+4])<<32 | uint64(s[i+5])<<40 | uint64(s[i+6])<<48 | uint64(s[i+7])<<56
+		ctl := (w - 0x20*swarOnes) &^ w
+		quote := w ^ '"'*swarOnes
+		quote = (quote - swarOnes) &^ quote
+		slash := w ^ '\\'*swarOnes
+		slash = (slash - swarOnes) &^ slash
+		if m := (ctl | quote | slash) & swarHighs; m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == '"' || c == '\\' {
+			return i
+		}
+	}
+	return len(s)
+}
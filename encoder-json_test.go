@@ -9,6 +9,9 @@ package iris
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"strings"
 	"testing"
 	"time"
@@ -233,6 +236,115 @@ func TestJSONEncoderWithFields(t *testing.T) {
 	}
 }
 
+// TestJSONEncoderEscapesFieldKeys tests that keys cannot inject JSON
+func TestJSONEncoderEscapesFieldKeys(t *testing.T) {
+	record := NewRecord(Info, "msg")
+	key := `user","admin":true,"x`
+	record.AddField(Str(key, "alice"))
+
+	buf := &bytes.Buffer{}
+	NewJSONEncoder().Encode(record, time.Unix(1234567890, 0), buf)
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf)
+	}
+	if _, injected := parsed["admin"]; injected {
+		t.Errorf("field key injected a JSON member: %s", buf)
+	}
+	if parsed[key] != "alice" {
+		t.Errorf("Expected field %q to be 'alice', got %v", key, parsed[key])
+	}
+}
+
+// TestJSONEncoderErrorChain tests structured encoding of wrapped errors
+func TestJSONEncoderErrorChain(t *testing.T) {
+	type link struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	root := &fs.PathError{Op: "open", Path: "app.yaml", Err: fs.ErrNotExist}
+	wrapped := fmt.Errorf("load config: %w", root)
+	joined := errors.Join(errors.New("first"), wrapped)
+
+	tests := []struct {
+		name string
+		err  error
+		want []link
+	}{
+		{"plain", errors.New("boom"), []link{{"*errors.errorString", "boom"}}},
+		{"wrapped", wrapped, []link{
+			{"*fmt.wrapError", "load config: open app.yaml: file does not exist"},
+			{"*fs.PathError", "open app.yaml: file does not exist"},
+			{"*errors.errorString", "file does not exist"},
+		}},
+		{"joined", joined, []link{
+			{"*errors.joinError", "first\nload config: open app.yaml: file does not exist"},
+			{"*errors.errorString", "first"},
+			{"*fmt.wrapError", "load config: open app.yaml: file does not exist"},
+			{"*fs.PathError", "open app.yaml: file does not exist"},
+			{"*errors.errorString", "file does not exist"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewRecord(Error, "failed")
+			record.AddField(ErrorField(tt.err))
+			encoder := NewJSONEncoder()
+			encoder.ErrorChain = true
+			buf := &bytes.Buffer{}
+			encoder.Encode(record, time.Unix(1234567890, 0), buf)
+
+			var parsed struct {
+				Error []link `json:"error"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+				t.Fatalf("Output is not valid JSON: %v\n%s", err, buf)
+			}
+			if len(parsed.Error) != len(tt.want) {
+				t.Fatalf("chain = %+v, want %+v", parsed.Error, tt.want)
+			}
+			for i := range tt.want {
+				if parsed.Error[i] != tt.want[i] {
+					t.Errorf("chain[%d] = %+v, want %+v", i, parsed.Error[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		record := NewRecord(Error, "failed")
+		record.AddField(ErrorField(wrapped))
+		buf := &bytes.Buffer{}
+		NewJSONEncoder().Encode(record, time.Unix(1234567890, 0), buf)
+		if !strings.Contains(buf.String(), `"error":"load config: open app.yaml: file does not exist"`) {
+			t.Errorf("Expected flattened error string, got %s", buf)
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		var err error = errors.New("root")
+		for i := 0; i < 2*maxErrorChain; i++ {
+			err = fmt.Errorf("layer: %w", err)
+		}
+		record := NewRecord(Error, "failed")
+		record.AddField(ErrorField(err))
+		encoder := &JSONEncoder{ErrorChain: true}
+		buf := &bytes.Buffer{}
+		encoder.Encode(record, time.Unix(1234567890, 0), buf)
+
+		var parsed struct {
+			Error []link `json:"error"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf)
+		}
+		if len(parsed.Error) != maxErrorChain {
+			t.Errorf("chain length = %d, want %d", len(parsed.Error), maxErrorChain)
+		}
+	})
+}
+
 // TestJSONEncoderCustomKeys tests custom field keys
 func TestJSONEncoderCustomKeys(t *testing.T) {
 	encoder := &JSONEncoder{
@@ -457,3 +569,164 @@ func TestJSONEncoderPerformance(t *testing.T) {
 		t.Errorf("Performance test output is not valid JSON: %v", err)
 	}
 }
+
+func TestJSONEncoderSortFields(t *testing.T) {
+	now := time.Date(2025, 9, 6, 14, 30, 45, 0, time.UTC)
+	newRecord := func(fields ...Field) *Record {
+		record := NewRecord(Info, "sorted")
+		record.Logger = "svc"
+		for _, f := range fields {
+			record.AddField(f)
+		}
+		return record
+	}
+
+	tests := []struct {
+		name   string
+		sort   bool
+		fields []Field
+		want   string
+	}{
+		{"insertion order by default", false, []Field{Str("zeta", "z"), Int("alpha", 1)},
+			`,"zeta":"z","alpha":1}`},
+		{"sorted", true, []Field{Str("zeta", "z"), Int("alpha", 1), Bool("mid", true)},
+			`,"alpha":1,"mid":true,"zeta":"z"}`},
+		{"duplicates keep order", true, []Field{Int("b", 1), Int("a", 2), Int("b", 3)},
+			`,"a":2,"b":1,"b":3}`},
+		{"no fields", true, nil, `"msg":"sorted"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := NewJSONEncoder()
+			encoder.SortFields = tt.sort
+			var buf bytes.Buffer
+			encoder.Encode(newRecord(tt.fields...), now, &buf)
+			got := strings.TrimSpace(buf.String())
+			if !strings.HasPrefix(got, `{"ts":"2025-09-06T14:30:45Z","level":"info","logger":"svc","msg":"sorted"`) {
+				t.Errorf("built-in keys moved: %s", got)
+			}
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("got %s, want suffix %s", got, tt.want)
+			}
+		})
+	}
+
+	// Field order does not change the output
+	encoder := NewJSONEncoder()
+	encoder.SortFields = true
+	var a, b bytes.Buffer
+	encoder.Encode(newRecord(Str("user", "u1"), Int("status", 200), Dur("took", time.Second)), now, &a)
+	encoder.Encode(newRecord(Dur("took", time.Second), Str("user", "u1"), Int("status", 200)), now, &b)
+	if a.String() != b.String() {
+		t.Errorf("sorted output differs:\n%s%s", a.String(), b.String())
+	}
+}
+
+func TestRecordEstimatedSize(t *testing.T) {
+	large := strings.Repeat("x", 20000)
+	tests := []struct {
+		name   string
+		fields []Field
+	}{
+		{"empty", nil},
+		{"scalars", []Field{Int64("n", -1<<63), Uint64("u", 1<<64-1), Float64("f", 1.0/3), Bool("ok", true), Dur("d", time.Hour)}},
+		{"strings", []Field{Str("body", large), Str("k", "v")}},
+		{"bytes", []Field{Bytes("payload", make([]byte, 5000))}},
+		{"time and secret", []Field{Time("at", time.Date(2025, 9, 6, 14, 30, 45, 123456789, time.UTC)), Secret("token", "abc")}},
+		{"error", []Field{ErrorField(errors.New("connection refused"))}},
+	}
+	encoder := NewJSONEncoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewRecord(Info, "estimate")
+			record.Logger = "svc"
+			record.Caller = "main.go:42"
+			for _, f := range tt.fields {
+				record.AddField(f)
+			}
+			var buf bytes.Buffer
+			encoder.Encode(record, time.Now(), &buf)
+			if got := record.EstimatedSize(); got < buf.Len() {
+				t.Errorf("EstimatedSize() = %d, encoded %d bytes", got, buf.Len())
+			}
+		})
+	}
+}
+
+func TestEscapeIndex(t *testing.T) {
+	naive := func(s string, from int) int {
+		for i := from; i < len(s); i++ {
+			if s[i] < 0x20 || s[i] == '"' || s[i] == '\\' {
+				return i
+			}
+		}
+		return len(s)
+	}
+
+	// Every byte value at every position of a string spanning two words and
+	// a tail, surrounded by bytes that sit next to the special ones
+	for c := 0; c < 256; c++ {
+		for pos := 0; pos < 19; pos++ {
+			b := []byte(strings.Repeat("!#[]\x7f\x80\xff ", 3)[:19])
+			b[pos] = byte(c)
+			s := string(b)
+			for _, from := range []int{0, 1, pos, 19} {
+				if got, want := escapeIndex(s, from), naive(s, from); got != want {
+					t.Fatalf("escapeIndex(%q, %d) = %d, want %d", s, from, got, want)
+				}
+			}
+		}
+	}
+
+	// Quoting agrees with encoding/json on mixed input
+	for _, s := range []string{"", "plain ascii message", "tab\tand \"quotes\" and \\ in a long line", "ünïcödé \x01\x1f\x7f€", strings.Repeat("a\"", 20)} {
+		var buf bytes.Buffer
+		quoteString(s, &buf)
+		var back string
+		if err := json.Unmarshal(buf.Bytes(), &back); err != nil || back != s {
+			t.Errorf("quoteString(%q) = %s, decodes to %q (%v)", s, buf.String(), back, err)
+		}
+	}
+}
+
+func BenchmarkQuoteString(b *testing.B) {
+	for _, bc := range []struct{ name, s string }{
+		{"Short", "user login"},
+		{"Clean", "request completed successfully for tenant acme-corp in region eu-west-1"},
+		{"Escaped", `{"query":"select * from users where name = \"bob\""}`},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			b.SetBytes(int64(len(bc.s)))
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				quoteString(bc.s, &buf)
+			}
+		})
+	}
+}
+
+// BenchmarkJSONEncoder_Encode encodes a typical service record. It is the
+// workload behind the PGO profile in pgo/ (see docs/ENCODERS.md).
+func BenchmarkJSONEncoder_Encode(b *testing.B) {
+	encoder := NewJSONEncoder()
+	record := NewRecord(Info, "request completed")
+	record.Logger = "api.http"
+	record.AddField(String("method", "GET"))
+	record.AddField(String("path", "/v1/accounts/42/transactions"))
+	record.AddField(Int("status", 200))
+	record.AddField(Int64("bytes", 18342))
+	record.AddField(Dur("elapsed", 1523*time.Microsecond))
+	record.AddField(Str("user_agent", `Mozilla/5.0 (X11; Linux x86_64) "curl"`))
+	record.AddField(Bool("cached", false))
+	record.AddField(Float64("ratio", 0.8125))
+	record.AddField(Err(errors.New("upstream timeout")))
+	now := time.Date(2025, 9, 6, 14, 30, 40, 123456789, time.UTC)
+
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		encoder.Encode(record, now, &buf)
+	}
+}
@@ -0,0 +1,118 @@
+// encoder-stream.go: streaming encoding straight to an io.Writer
+//
+// Encode fills a bytes.Buffer that the logger then copies to the output
+// with a single Write. Outputs implementing StreamSink skip that copy: the
+// logger asks them for the writer the record should be encoded into, and an
+// encoder implementing StreamEncoder writes the record there directly.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/iris/internal/bufferpool"
+)
+
+// StreamEncoder is implemented by encoders that can write a record straight
+// to an io.Writer instead of into a buffer.
+//
+// The logger uses EncodeTo for outputs implementing StreamSink. A streaming
+// encoder may issue several writes per record (for instance one per field,
+// or one for each string it would otherwise copy), which lets a sink that
+// gathers writes with writev send them without assembling the record first.
+// All built-in encoders implement StreamEncoder.
+type StreamEncoder interface {
+	Encoder
+
+	// EncodeTo writes rec to w and returns the first write error.
+	EncodeTo(rec *Record, now time.Time, w io.Writer) error
+}
+
+// encodeTo implements EncodeTo for encoders built on bytes.Buffer: a
+// *bytes.Buffer destination (such as a StreamSink's own buffer) is encoded
+// into directly, any other writer receives the record in one Write.
+func encodeTo(enc Encoder, rec *Record, now time.Time, w io.Writer) error {
+	if buf, ok := w.(*bytes.Buffer); ok {
+		enc.Encode(rec, now, buf)
+		return nil
+	}
+	buf := bufferpool.GetSized(rec.EstimatedSize())
+	enc.Encode(rec, now, buf)
+	_, err := w.Write(buf.Bytes())
+	bufferpool.Put(buf)
+	return err
+}
+
+// EncodeTo implements StreamEncoder.
+func (e *JSONEncoder) EncodeTo(rec *Record, now time.Time, w io.Writer) error {
+	return encodeTo(e, rec, now, w)
+}
+
+// EncodeTo implements StreamEncoder.
+func (e *TextEncoder) EncodeTo(rec *Record, now time.Time, w io.Writer) error {
+	return encodeTo(e, rec, now, w)
+}
+
+// EncodeTo implements StreamEncoder.
+func (e *ConsoleEncoder) EncodeTo(rec *Record, now time.Time, w io.Writer) error {
+	return encodeTo(e, rec, now, w)
+}
+
+// EncodeTo implements StreamEncoder.
+func (e *BinaryEncoder) EncodeTo(rec *Record, now time.Time, w io.Writer) error {
+	return encodeTo(e, rec, now, w)
+}
+
+// streamRecord encodes rec straight into sink when both the encoder and the
+// output support it. It reports false, leaving the record untouched, when
+// the record has to go through the buffer path instead.
+func (l *Logger) streamRecord(rec *Record, now time.Time, out WriteSyncer) bool {
+	enc, ok := l.enc.(StreamEncoder)
+	if !ok {
+		return false
+	}
+	sink, ok := out.(StreamSink)
+	if !ok {
+		return false
+	}
+	// Debug sessions and additional outputs reuse the encoded bytes
+	if rec.session != nil || ((len(l.opts.outputs) > 0 || len(l.opts.recordWriters) > 0) && out == l.out) {
+		return false
+	}
+	w := sink.RecordWriter()
+	if l.rates != nil {
+		w = l.rates.measure(w)
+	}
+	span := l.profile.begin(phaseEncode)
+	var encErr *errors.Error
+	var err error
+	if l.latency != nil {
+		start := latencyNow()
+		encErr, err = encodeRecordTo(enc, rec, now, w)
+		l.latency.encode.Record(time.Duration(latencyNow() - start))
+	} else {
+		encErr, err = encodeRecordTo(enc, rec, now, w)
+	}
+	l.profile.end(phaseEncode, span)
+	if encErr != nil {
+		l.reportEncodeError(encErr, rec) // Part of the record may reach the sink
+	} else if err != nil {
+		l.reportWriteError(err, out)
+	}
+	span = l.profile.begin(phaseWrite)
+	if err := sink.EndRecord(); err != nil {
+		l.reportWriteError(err, out)
+	}
+	l.profile.end(phaseWrite, span)
+	if l.rates != nil {
+		l.rates.observe(rec.Logger, l.rates.measured())
+	}
+	return true
+}
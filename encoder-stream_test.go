@@ -0,0 +1,157 @@
+// encoder-stream_test.go: Tests for streaming encoders and sinks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bufferStreamSink owns a buffer the encoder writes each record into and
+// collects the records at EndRecord.
+type bufferStreamSink struct {
+	buf     bytes.Buffer
+	records []string
+	writes  int // Records that arrived through Write instead
+}
+
+func (s *bufferStreamSink) Write(p []byte) (int, error) {
+	s.writes++
+	s.records = append(s.records, string(p))
+	return len(p), nil
+}
+
+func (s *bufferStreamSink) Sync() error { return nil }
+
+func (s *bufferStreamSink) RecordWriter() io.Writer { return &s.buf }
+
+func (s *bufferStreamSink) EndRecord() error {
+	s.records = append(s.records, s.buf.String())
+	s.buf.Reset()
+	return nil
+}
+
+// gatherSink stands in for a writev sink: it keeps every write of a record
+// as a separate segment until EndRecord.
+type gatherSink struct {
+	segments [][]byte
+	records  [][][]byte
+}
+
+func (s *gatherSink) Write(p []byte) (int, error) {
+	s.segments = append(s.segments, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (s *gatherSink) Sync() error { return nil }
+
+func (s *gatherSink) RecordWriter() io.Writer { return s }
+
+func (s *gatherSink) EndRecord() error {
+	s.records = append(s.records, s.segments)
+	s.segments = nil
+	return nil
+}
+
+// pieceEncoder streams the message and each field key as separate writes.
+type pieceEncoder struct{}
+
+func (pieceEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
+	_ = pieceEncoder{}.EncodeTo(rec, now, buf)
+}
+
+func (pieceEncoder) EncodeTo(rec *Record, _ time.Time, w io.Writer) error {
+	if _, err := io.WriteString(w, rec.Msg); err != nil {
+		return err
+	}
+	for i := 0; i < rec.FieldCount(); i++ {
+		if _, err := io.WriteString(w, " "+rec.GetField(i).K); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func TestStreamSink_OwnBuffer(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   []Option
+		stream bool
+	}{
+		{"streamed", nil, true},
+		{"additional output", []Option{WithOutput(NewTextEncoder(), &testSyncer{})}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := &bufferStreamSink{}
+			logger, err := New(Config{Level: Info, Output: sink, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, tc.opts...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				logger.Info("streamed", Int("i", i))
+			}
+			_ = logger.Close()
+
+			if len(sink.records) != 3 {
+				t.Fatalf("sink received %d records, want 3: %q", len(sink.records), sink.records)
+			}
+			if want := map[bool]int{true: 0, false: 3}[tc.stream]; sink.writes != want {
+				t.Errorf("%d records arrived through Write, want %d", sink.writes, want)
+			}
+			for _, rec := range sink.records {
+				var m map[string]any
+				if err := json.Unmarshal([]byte(rec), &m); err != nil || m["msg"] != "streamed" {
+					t.Errorf("record %q is not the encoded JSON (%v)", rec, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamSink_Gather(t *testing.T) {
+	sink := &gatherSink{}
+	logger, err := New(Config{Level: Info, Output: sink, Encoder: pieceEncoder{}, Capacity: 64, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("gathered", Str("a", "1"), Str("b", "2"))
+	_ = logger.Close()
+
+	if len(sink.records) != 1 {
+		t.Fatalf("sink received %d records, want 1", len(sink.records))
+	}
+	var got []string
+	for _, seg := range sink.records[0] {
+		got = append(got, string(seg))
+	}
+	if strings.Join(got, "|") != "gathered| a| b|\n" {
+		t.Errorf("segments = %q, want the encoder's writes one by one", got)
+	}
+}
+
+func TestEncodeTo_Writer(t *testing.T) {
+	rec := NewRecord(Info, "to writer")
+	rec.AddField(Int("n", 1))
+	now := time.Date(2025, 9, 6, 14, 30, 40, 0, time.UTC)
+	for _, enc := range []StreamEncoder{NewJSONEncoder(), NewTextEncoder(), NewConsoleEncoder(), NewBinaryEncoder()} {
+		var want bytes.Buffer
+		enc.Encode(rec, now, &want)
+
+		sink := &gatherSink{}
+		if err := enc.EncodeTo(rec, now, sink); err != nil {
+			t.Fatalf("%T.EncodeTo failed: %v", enc, err)
+		}
+		if len(sink.segments) != 1 || !bytes.Equal(sink.segments[0], want.Bytes()) {
+			t.Errorf("%T.EncodeTo wrote %d segments, want the encoded record in one write", enc, len(sink.segments))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// encoder-syslog_test.go: Tests for the RFC 5424 syslog encoder
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogEncoder_Encode(t *testing.T) {
+	now := time.Date(2025, 3, 4, 5, 6, 7, 123456789, time.UTC)
+	enc := &SyslogEncoder{Facility: FacilityLocal0, Hostname: "web-1", AppName: "checkout", ProcID: "42"}
+	tests := []struct {
+		name  string
+		setup func(rec *Record)
+		want  string
+	}{
+		{
+			name:  "no structured data",
+			setup: func(rec *Record) { rec.Msg = "started" },
+			want:  "<134>1 2025-03-04T05:06:07.123456Z web-1 checkout 42 - - started\n",
+		},
+		{
+			name: "fields",
+			setup: func(rec *Record) {
+				rec.Level, rec.Msg, rec.Logger = Warn, "payment declined", "payments"
+				rec.AddField(Str("issuer", `acme "bank"`))
+				rec.AddField(Int("amount", 12))
+				rec.AddField(Bool("retry", true))
+				rec.AddField(Dur("latency", 1500*time.Millisecond))
+				rec.AddField(Secret("card", "4111"))
+				rec.AddField(NamedErr("cause", errors.New("limit]exceeded")))
+			},
+			want: `<132>1 2025-03-04T05:06:07.123456Z web-1 checkout 42 payments [iris@32473 issuer="acme \"bank\"" amount="12" retry="true" latency="1.5s" card="[REDACTED\]" cause="limit\]exceeded"] payment declined` + "\n",
+		},
+		{
+			name: "invalid names and line breaks",
+			setup: func(rec *Record) {
+				rec.Level, rec.Msg = Error, "line one\nline two"
+				rec.AddField(Str("bad key=\"x\"]", `a\b`))
+				rec.AddField(Str(strings.Repeat("k", 40), "v"))
+				rec.Stack = "main.go:1\nmain.go:2"
+			},
+			want: `<131>1 2025-03-04T05:06:07.123456Z web-1 checkout 42 - [iris@32473 bad_key__x__="a\\b" ` + strings.Repeat("k", 32) + `="v" stack="main.go:1\nmain.go:2"] line one\nline two` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		rec := NewRecord(Info, "")
+		tt.setup(rec)
+		var buf bytes.Buffer
+		enc.Encode(rec, now, &buf)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  int
+	}{
+		{Trace, 7}, {Debug, 7}, {Info, 6}, {Warn, 4}, {Error, 3}, {DPanic, 2}, {Panic, 1}, {Fatal, 0},
+	}
+	for _, tt := range tests {
+		if got := syslogSeverity(tt.level); got != tt.want {
+			t.Errorf("syslogSeverity(%v) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestNewSyslogEncoder(t *testing.T) {
+	enc := NewSyslogEncoder()
+	enc.Hostname = "" // Unknown host
+	enc.SDID = "app@1234"
+	enc.BOM = true
+	rec := NewRecord(Info, "hi")
+	rec.AddField(Int("n", 1))
+	var buf bytes.Buffer
+	enc.Encode(rec, time.Now(), &buf)
+	if got := buf.String(); !strings.HasPrefix(got, "<14>1 ") || !strings.Contains(got, " - "+enc.AppName+" "+enc.ProcID+" - [app@1234 n=\"1\"] \ufeffhi\n") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -8,7 +8,7 @@ package iris
 
 import (
 	"bytes"
-	"strconv"
+	"fmt"
 	"strings"
 	"time"
 
@@ -52,6 +52,11 @@ type TextEncoder struct {
 	// Default: true for security (prevents key-based injection).
 	// Set to false only when keys are guaranteed to be safe.
 	SanitizeKeys bool
+
+	// MarkTruncated appends _truncated_fields=N (TruncatedFieldsKey) after
+	// the fields of a record that had N fields dropped because it was full.
+	// Default: false.
+	MarkTruncated bool
 }
 
 // NewTextEncoder creates a new secure text encoder with production-safe defaults.
@@ -85,6 +90,10 @@ func (e *TextEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
 
 	// Encode structured fields
 	e.encodeStructuredFields(rec, buf)
+	if e.MarkTruncated && rec.lost > 0 {
+		buf.WriteString(" " + TruncatedFieldsKey + "=")
+		writeInt(buf, int64(rec.lost))
+	}
 
 	// Encode stack trace if present
 	e.encodeStackTrace(rec, buf)
@@ -99,7 +108,7 @@ func (e *TextEncoder) encodeTimestamp(now time.Time, buf *bytes.Buffer) {
 	if cachedTime := timecache.CachedTime(); now.Sub(cachedTime).Abs() < 500*time.Microsecond {
 		buf.WriteString(timecache.CachedTimeString()) // Fast cached format
 	} else {
-		buf.WriteString(now.Format(e.TimeFormat)) // Exact time for tests/historical
+		writeTime(buf, now, e.TimeFormat) // Exact time for tests/historical
 	}
 }
 
@@ -157,11 +166,11 @@ func (e *TextEncoder) encodeFieldValue(f *Field, buf *bytes.Buffer) {
 		// Security: Always quote and redact secret values
 		buf.WriteString(`"[REDACTED]"`)
 	case kindInt64:
-		buf.WriteString(strconv.FormatInt(f.I64, 10))
+		writeInt(buf, f.I64)
 	case kindUint64:
-		buf.WriteString(strconv.FormatUint(f.U64, 10))
+		writeUint(buf, f.U64)
 	case kindFloat64:
-		buf.WriteString(strconv.FormatFloat(f.F64, 'g', -1, 64))
+		writeFloat(buf, f.F64, 'g')
 	case kindBool:
 		if f.I64 != 0 {
 			buf.WriteString("true")
@@ -171,12 +180,30 @@ func (e *TextEncoder) encodeFieldValue(f *Field, buf *bytes.Buffer) {
 	case kindDur:
 		buf.WriteString(time.Duration(f.I64).String())
 	case kindTime:
-		buf.WriteString(time.Unix(0, f.I64).UTC().Format(e.TimeFormat))
+		writeTime(buf, time.Unix(0, f.I64).UTC(), e.TimeFormat)
 	case kindBytes:
 		// Bytes as hex string for text format
+		const hex = "0123456789abcdef"
 		buf.WriteString("0x")
 		for _, b := range f.B {
-			buf.WriteString(strconv.FormatUint(uint64(b), 16))
+			buf.WriteByte(hex[b>>4])
+			buf.WriteByte(hex[b&0x0f])
+		}
+	case kindError:
+		if err, ok := f.Obj.(error); ok {
+			e.writeValueWithQuoting(err.Error(), buf)
+		} else {
+			e.writeValueWithQuoting(fmt.Sprint(f.Obj), buf)
+		}
+	case kindStringer:
+		if s, ok := f.Obj.(fmt.Stringer); ok {
+			e.writeValueWithQuoting(s.String(), buf)
+		} else {
+			e.writeValueWithQuoting(fmt.Sprint(f.Obj), buf)
+		}
+	case kindObject:
+		if s, ok := f.Obj.(fmt.Stringer); ok {
+			e.writeValueWithQuoting(s.String(), buf)
 		}
 	}
 }
@@ -0,0 +1,147 @@
+// encoder_registry.go: Named encoder registration for Iris logging library
+//
+// The config loader knows the json, text, console, binary and gelf formats
+// by name. Third-party encoders (CEF, logfmt, ...) are registered under
+// a name of their own once at startup; LoadConfigFromJSON, LoadConfigFromEnv
+// and pipeline sinks then resolve "format": "<name>" through the registry,
+// so they can be selected from a config file without code changes.
+//
+// The registry is copy-on-write behind an atomic pointer, like the level
+// registry: registrations are expected at init time and lookups never
+// take a lock.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// builtinFormats are the format names the loaders resolve themselves.
+var builtinFormats = map[string]bool{"json": true, "text": true, "console": true, "binary": true, "gelf": true}
+
+// encoderEntry is a registered encoder factory.
+type encoderEntry struct {
+	factory func() Encoder
+	typ     reflect.Type // Type of the encoders it returns, for encoderFormat
+}
+
+var (
+	// encoderRegistry holds the registered encoders by lowercase name
+	// (nil until the first registration); the map is never modified
+	encoderRegistry atomic.Pointer[map[string]encoderEntry]
+
+	// encoderRegistryMu serializes registrations
+	encoderRegistryMu sync.Mutex
+)
+
+// RegisterEncoder registers an encoder factory under name, so that config
+// files, IRIS_FORMAT and pipeline sinks can select it with
+// "format": "<name>". The factory is called for every logger or sink
+// configured with the name, and once here to check that it returns an
+// encoder.
+//
+// Parameters:
+//   - name: Format name (case-insensitive; json, text, console, binary and
+//     gelf are reserved)
+//   - factory: Returns a new encoder, not shared with other loggers
+//
+// Returns:
+//   - error: ErrCodeInvalidFormat for an empty, reserved or taken name, or
+//     a factory that returns nil
+//
+// Example:
+//
+//	func init() {
+//	    _ = iris.RegisterEncoder("logfmt", func() iris.Encoder { return logfmt.NewEncoder() })
+//	}
+//
+//	// config.json: {"level": "info", "format": "logfmt", "output": "stdout"}
+//	cfg, err := iris.LoadConfigFromJSON("config.json")
+func RegisterEncoder(name string, factory func() Encoder) error {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case normalized == "":
+		return NewLoggerErrorWithField(ErrCodeInvalidFormat, "encoder name cannot be empty", "name", name)
+	case builtinFormats[normalized]:
+		return NewLoggerErrorWithField(ErrCodeInvalidFormat, "encoder name collides with a built-in format", "name", name)
+	case factory == nil:
+		return NewLoggerErrorWithField(ErrCodeInvalidFormat, "encoder factory cannot be nil", "name", name)
+	}
+	enc := factory()
+	if enc == nil {
+		return NewLoggerErrorWithField(ErrCodeInvalidFormat, "encoder factory returned nil", "name", name)
+	}
+
+	encoderRegistryMu.Lock()
+	defer encoderRegistryMu.Unlock()
+
+	current := encoders()
+	if _, ok := current[normalized]; ok {
+		return NewLoggerErrorWithField(ErrCodeInvalidFormat, "encoder name already registered", "name", name)
+	}
+	next := make(map[string]encoderEntry, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	next[normalized] = encoderEntry{factory: factory, typ: reflect.TypeOf(enc)}
+	encoderRegistry.Store(&next)
+	return nil
+}
+
+// RegisteredEncoders returns the names of the registered encoders in
+// ascending order. Built-in formats are not included.
+func RegisteredEncoders() []string {
+	current := encoders()
+	if len(current) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredEncoder returns a new encoder of the format registered as name.
+func registeredEncoder(name string) (Encoder, bool) {
+	entry, ok := encoders()[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, false
+	}
+	enc := entry.factory()
+	return enc, enc != nil
+}
+
+// registeredFormat returns the name under which the type of enc is
+// registered, or "" if none.
+func registeredFormat(enc Encoder) string {
+	current := encoders()
+	if len(current) == 0 {
+		return ""
+	}
+	typ := reflect.TypeOf(enc)
+	for _, name := range RegisteredEncoders() {
+		if current[name].typ == typ {
+			return name
+		}
+	}
+	return ""
+}
+
+// encoders returns the current registry snapshot, nil before the first
+// registration.
+func encoders() map[string]encoderEntry {
+	if m := encoderRegistry.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// encoder_registry_test.go: Tests for named encoder registration
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// resetEncoderRegistryForTest clears the registry and restores it after the test
+func resetEncoderRegistryForTest(t *testing.T) {
+	t.Helper()
+	previous := encoderRegistry.Load()
+	encoderRegistry.Store(nil)
+	t.Cleanup(func() { encoderRegistry.Store(previous) })
+}
+
+// logfmtTestEncoder writes msg=<message> lines.
+type logfmtTestEncoder struct{}
+
+func (logfmtTestEncoder) Encode(rec *Record, now time.Time, buf *bytes.Buffer) {
+	buf.WriteString("msg=")
+	buf.WriteString(rec.Msg)
+	buf.WriteByte('\n')
+}
+
+func newLogfmtTestEncoder() Encoder { return &logfmtTestEncoder{} }
+
+func TestRegisterEncoder_Rejections(t *testing.T) {
+	resetEncoderRegistryForTest(t)
+
+	if err := RegisterEncoder(" LogFmt ", newLogfmtTestEncoder); err != nil {
+		t.Fatalf("RegisterEncoder failed: %v", err)
+	}
+	tests := []struct {
+		name    string
+		factory func() Encoder
+	}{
+		{"", newLogfmtTestEncoder},
+		{"Console", newLogfmtTestEncoder},
+		{"logfmt", newLogfmtTestEncoder},
+		{"cef", nil},
+		{"gelf", func() Encoder { return nil }},
+	}
+	for _, tt := range tests {
+		if err := RegisterEncoder(tt.name, tt.factory); !IsLoggerError(err, ErrCodeInvalidFormat) {
+			t.Errorf("RegisterEncoder(%q) = %v, want %s", tt.name, err, ErrCodeInvalidFormat)
+		}
+	}
+	if got := RegisteredEncoders(); len(got) != 1 || got[0] != "logfmt" {
+		t.Errorf("RegisteredEncoders() = %v, want [logfmt]", got)
+	}
+}
+
+func TestRegisterEncoder_ConfigLoaders(t *testing.T) {
+	resetEncoderRegistryForTest(t)
+	if err := RegisterEncoder("logfmt", newLogfmtTestEncoder); err != nil {
+		t.Fatalf("RegisterEncoder failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"format": "LOGFMT", "output": "std-split", "pipeline": {"sinks": [{"name": "audit", "format": "logfmt", "output": "stdout"}]}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	if _, ok := cfg.Encoder.(*logfmtTestEncoder); !ok {
+		t.Errorf("JSON config encoder = %T, want *logfmtTestEncoder", cfg.Encoder)
+	}
+	if err := cfg.Pipeline.Validate(); err != nil {
+		t.Errorf("pipeline with registered formats rejected: %v", err)
+	}
+	for _, s := range cfg.Pipeline.Sinks {
+		if s.Format != "LOGFMT" && s.Format != "logfmt" {
+			t.Errorf("sink %s format = %q, want logfmt", s.Name, s.Format)
+		}
+	}
+
+	t.Setenv("IRIS_FORMAT", "logfmt")
+	envCfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if _, ok := envCfg.Encoder.(*logfmtTestEncoder); !ok {
+		t.Errorf("env config encoder = %T, want *logfmtTestEncoder", envCfg.Encoder)
+	}
+	if got := encoderFormat(envCfg.Encoder); got != "logfmt" {
+		t.Errorf("encoderFormat = %q, want logfmt", got)
+	}
+
+	t.Setenv("IRIS_FORMAT", "xml")
+	if envCfg, _ = LoadConfigFromEnv(); envCfg.Encoder == nil {
+		t.Fatal("unknown format left no encoder")
+	} else if _, ok := envCfg.Encoder.(*JSONEncoder); !ok {
+		t.Errorf("unknown format encoder = %T, want the JSON fallback", envCfg.Encoder)
+	}
+}
@@ -0,0 +1,303 @@
+// encodertest.go: Conformance and golden test kit for iris.Encoder implementations
+//
+// This package gives authors of custom encoders the corpus the built-in
+// encoders are tested against: records that exercise escaping, every field
+// type, numeric edge cases and time zones. Run checks the properties every
+// encoder must have and, optionally, compares the output with golden files.
+//
+// Usage:
+//
+//	var update = flag.Bool("update", false, "rewrite golden files")
+//
+//	func TestMyEncoder(t *testing.T) {
+//	    encodertest.Run(t, NewMyEncoder(), encodertest.Options{
+//	        LineDelimited: true,
+//	        JSON:          true,
+//	        GoldenDir:     "testdata/golden",
+//	        Update:        *update,
+//	    })
+//	}
+//
+// The golden outputs of the built-in encoders live in testdata/ next to this
+// file and are a useful reference when writing a compatible encoder.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package encodertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// Case is one record of the corpus.
+type Case struct {
+	// Name identifies the case; it is also the golden file name
+	Name string
+
+	// Time is the timestamp passed to Encode
+	Time time.Time
+
+	// Record returns a new record for the case on every call
+	Record func() *iris.Record
+}
+
+// Options selects the checks Run performs beyond the ones every encoder
+// must pass.
+type Options struct {
+	// LineDelimited requires each record to be exactly one line ending in
+	// '\n', whatever the message and field contents
+	LineDelimited bool
+
+	// JSON requires each record to be a JSON object that carries the
+	// message under MsgKey and every string field value unchanged
+	JSON bool
+
+	// MsgKey is the JSON key of the message (default "msg")
+	MsgKey string
+
+	// GoldenDir holds one <case>.golden file per corpus case (empty: no
+	// golden comparison)
+	GoldenDir string
+
+	// Update rewrites the golden files instead of comparing against them
+	Update bool
+}
+
+// stringer is a fmt.Stringer for the corpus.
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+// hostile contains everything an encoder must escape or pass through
+// safely: quotes, backslashes, control characters, separators, non-ASCII
+// text, line and paragraph separators and invalid UTF-8.
+const hostile = "quote\" backslash\\ newline\n return\r tab\t nul\x00 esc\x1b[31m eq= brace{} é 日本 \u2028\u2029 bad\xff"
+
+// Corpus returns the canonical record corpus.
+//
+// Every call returns new cases, and the corpus only grows: cases are never
+// renamed or removed, so golden files stay valid across versions.
+func Corpus() []Case {
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	record := func(level iris.Level, msg string, fields ...iris.Field) func() *iris.Record {
+		return func() *iris.Record {
+			r := iris.NewRecord(level, msg)
+			for _, f := range fields {
+				r.AddField(f)
+			}
+			return r
+		}
+	}
+
+	cases := []Case{
+		{"minimal", ts, record(iris.Info, "hello")},
+		{"empty", ts, record(iris.Info, "", iris.Str("empty", ""), iris.Bytes("no_bytes", nil))},
+		{"escaping_message", ts, record(iris.Warn, hostile)},
+		{"escaping_fields", ts, record(iris.Info, "hostile fields",
+			iris.Str(hostile, hostile),
+			iris.Str("key with spaces", "v"),
+			iris.NamedError("error", errors.New(hostile)),
+			iris.Stringer("stringer", stringer(hostile)),
+		)},
+		{"field_types", ts, record(iris.Info, "every field type",
+			iris.Str("str", "value"),
+			iris.Int("int", -42),
+			iris.Int64("int64", 1<<40),
+			iris.Uint64("uint64", 42),
+			iris.Float64("float64", 3.25),
+			iris.Bool("bool_true", true),
+			iris.Bool("bool_false", false),
+			iris.Dur("duration", 1500*time.Millisecond),
+			iris.Time("time", time.Date(2024, 2, 29, 23, 59, 59, 999999999, time.UTC)),
+			iris.Bytes("bytes", []byte{0x00, 0x01, 0xfe, 0xff}),
+			iris.NamedError("error", errors.New("connection refused")),
+			iris.Errors("errors", []error{errors.New("first"), errors.New("second")}),
+			iris.Stringer("stringer", stringer("as string")),
+			iris.Object("object", map[string]int{"a": 1}),
+			iris.Secret("password", "hunter2"),
+		)},
+		{"numeric_edges", ts, record(iris.Info, "numeric edge cases",
+			iris.Int64("int_min", math.MinInt64),
+			iris.Int64("int_max", math.MaxInt64),
+			iris.Uint64("uint_max", math.MaxUint64),
+			iris.Float64("float_neg_zero", math.Copysign(0, -1)),
+			iris.Float64("float_tiny", 5e-324),
+			iris.Float64("float_huge", math.MaxFloat64),
+			iris.Float64("float_tenth", 0.1),
+			iris.Dur("duration_neg", -time.Nanosecond),
+		)},
+		{"time_offset", time.Date(2025, 3, 14, 20, 39, 26, 0, time.FixedZone("IST", 5*3600+30*60)), record(iris.Info, "non-UTC zone")},
+		{"time_pre_epoch", time.Date(1969, 7, 20, 20, 17, 40, 0, time.UTC), record(iris.Info, "before the Unix epoch",
+			iris.Time("landing", time.Date(1969, 7, 20, 20, 17, 40, 0, time.UTC)))},
+		{"time_whole_second", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), record(iris.Info, "no fractional seconds")},
+		{"metadata", ts, func() *iris.Record {
+			r := iris.NewRecord(iris.Error, "with metadata")
+			r.Logger = "svc.db"
+			r.Caller = "db/pool.go:42"
+			r.Stack = "goroutine 1 [running]:\nmain.main()\n\t/app/main.go:10 +0x1d"
+			return r
+		}},
+		{"max_fields", ts, func() *iris.Record {
+			r := iris.NewRecord(iris.Info, "32 fields")
+			for i := 0; i < 32; i++ {
+				r.AddField(iris.Int(fmt.Sprintf("f%02d", i), i))
+			}
+			return r
+		}},
+	}
+	for _, level := range []iris.Level{iris.Trace, iris.Debug, iris.Info, iris.Warn, iris.Error, iris.DPanic, iris.Panic, iris.Fatal} {
+		cases = append(cases, Case{"level_" + level.String(), ts, record(level, "level "+level.String())})
+	}
+	return cases
+}
+
+// Run encodes every corpus case with enc in a subtest and checks that the
+// encoder:
+//   - does not panic and produces output;
+//   - appends to the buffer instead of overwriting it;
+//   - is deterministic;
+//   - does not modify the record;
+//
+// plus the checks selected in opts.
+func Run(t *testing.T, enc iris.Encoder, opts Options) {
+	t.Helper()
+	if opts.MsgKey == "" {
+		opts.MsgKey = "msg"
+	}
+	if opts.Update && opts.GoldenDir != "" {
+		if err := os.MkdirAll(opts.GoldenDir, 0750); err != nil {
+			t.Fatalf("creating golden directory: %v", err)
+		}
+	}
+	for _, c := range Corpus() {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			out := encode(t, enc, c)
+			if opts.LineDelimited {
+				checkLine(t, out)
+			}
+			if opts.JSON {
+				checkJSON(t, out, c.Record(), opts.MsgKey)
+			}
+			if opts.GoldenDir != "" {
+				checkGolden(t, out, filepath.Join(opts.GoldenDir, c.Name+".golden"), opts.Update)
+			}
+		})
+	}
+}
+
+// encode runs the checks every encoder must pass and returns the output.
+func encode(t *testing.T, enc iris.Encoder, c Case) []byte {
+	t.Helper()
+	const prefix = "previous record\n"
+	run := func() []byte {
+		rec := c.Record()
+		want := describe(rec)
+		var buf bytes.Buffer
+		buf.WriteString(prefix)
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					t.Fatalf("Encode panicked: %v", p)
+				}
+			}()
+			enc.Encode(rec, c.Time, &buf)
+		}()
+		if got := describe(rec); got != want {
+			t.Errorf("Encode modified the record:\nbefore: %s\nafter:  %s", want, got)
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte(prefix)) {
+			t.Fatalf("Encode overwrote existing buffer content: %q", buf.Bytes())
+		}
+		return buf.Bytes()[len(prefix):]
+	}
+
+	out := run()
+	if len(out) == 0 {
+		t.Fatal("Encode produced no output")
+	}
+	if again := run(); !bytes.Equal(out, again) {
+		t.Errorf("Encode is not deterministic:\nfirst:  %q\nsecond: %q", out, again)
+	}
+	return out
+}
+
+// describe renders a record's content for before/after comparison.
+func describe(r *iris.Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v %q %q %q %q", r.Level, r.Msg, r.Logger, r.Caller, r.Stack)
+	for i := 0; i < r.FieldCount(); i++ {
+		f := r.GetField(i)
+		fmt.Fprintf(&b, " %q=%#v", f.Key(), f.Value())
+	}
+	return b.String()
+}
+
+// checkLine verifies that out is a single line.
+func checkLine(t *testing.T, out []byte) {
+	t.Helper()
+	if !bytes.HasSuffix(out, []byte("\n")) {
+		t.Errorf("record does not end with a newline: %q", out)
+	}
+	if n := bytes.Count(out, []byte("\n")); n != 1 {
+		t.Errorf("record spans %d lines, want 1 (unescaped newline?): %q", n, out)
+	}
+	if bytes.IndexByte(out, '\r') >= 0 {
+		t.Errorf("record contains a raw carriage return: %q", out)
+	}
+}
+
+// checkJSON verifies that out is a JSON object carrying the record's
+// message and string fields.
+func checkJSON(t *testing.T, out []byte, rec *iris.Record, msgKey string) {
+	t.Helper()
+	var obj map[string]any
+	if err := json.Unmarshal(out, &obj); err != nil {
+		t.Fatalf("record is not a JSON object: %v\n%s", err, out)
+	}
+	check := func(key, want string) {
+		want = strings.ToValidUTF8(want, "\uFFFD")
+		if got, ok := obj[strings.ToValidUTF8(key, "\uFFFD")].(string); !ok || got != want {
+			t.Errorf("%q = %#v, want %q", key, obj[key], want)
+		}
+	}
+	if rec.Msg != "" {
+		check(msgKey, rec.Msg)
+	}
+	for i := 0; i < rec.FieldCount(); i++ {
+		if f := rec.GetField(i); f.IsString() {
+			check(f.Key(), f.StringValue())
+		}
+	}
+}
+
+// checkGolden compares out with the golden file at path, or rewrites it.
+func checkGolden(t *testing.T, out []byte, path string, update bool) {
+	t.Helper()
+	if update {
+		if err := os.WriteFile(path, out, 0600); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path) // #nosec G304 -- test golden file
+	if err != nil {
+		t.Fatalf("reading golden file (run with Update to create it): %v", err)
+	}
+	if !bytes.Equal(out, want) {
+		t.Errorf("output differs from %s:\ngot:  %q\nwant: %q", path, out, want)
+	}
+}
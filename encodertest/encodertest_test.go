@@ -0,0 +1,50 @@
+// encodertest_test.go: Runs the kit against the built-in encoders
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package encodertest
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/agilira/iris"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the built-in encoders")
+
+func TestBuiltinEncoders(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  iris.Encoder
+		opts Options
+	}{
+		{"json", iris.NewJSONEncoder(), Options{LineDelimited: true, JSON: true}},
+		{"text", iris.NewTextEncoder(), Options{}}, // Stack traces span lines by design
+		{"console", iris.NewConsoleEncoder(), Options{}},
+		{"binary", iris.NewBinaryEncoder(), Options{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.GoldenDir = filepath.Join("testdata", tt.name)
+			tt.opts.Update = *update
+			Run(t, tt.enc, tt.opts)
+		})
+	}
+}
+
+func TestCorpus_UniqueNames(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range Corpus() {
+		if seen[c.Name] {
+			t.Errorf("duplicate case name %q", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Record().FieldCount() > 32 {
+			t.Errorf("%s: more fields than a record holds", c.Name)
+		}
+	}
+}
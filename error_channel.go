@@ -0,0 +1,228 @@
+// error_channel.go: Per-logger error channel
+//
+// Errors the logger cannot return to a caller (a failed reopen on signal, a
+// stalled consumer, a record logged after Close) go to the process-wide
+// handler set with SetErrorHandler, which cannot tell which logger they
+// came from, and write failures are not reported at all. Logger.Errors
+// gives an application a channel of its own for one logger and its clones:
+// once it has been requested, the logger's errors are sent there instead,
+// along with the failures of the writes and encodings done for it and a
+// periodic report of the records it lost.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/go-timecache"
+)
+
+// ErrorChannelCapacity is the buffer size of the channel returned by
+// Logger.Errors.
+const ErrorChannelCapacity = 64
+
+// dropReportInterval is the minimum time between two drop reports sent to
+// the Errors channel.
+const dropReportInterval = time.Second
+
+// lossReasons are the drop reasons reported to the Errors channel: records
+// lost rather than discarded on purpose (sampled, filtered, over budget),
+// or logged after Close, which WithStrictLifecycle already reports.
+var lossReasons = [...]DropReason{DropRingFull, DropCanceled, DropMaxAge, DropShutdown}
+
+// lossMask has bit 1<<reason set for each of lossReasons.
+const lossMask = 1<<DropRingFull | 1<<DropCanceled | 1<<DropMaxAge | 1<<DropShutdown
+
+// errorChannel is the Errors state shared by a logger and its clones.
+type errorChannel struct {
+	once       sync.Once
+	ch         chan *errors.Error
+	subscribed atomic.Bool
+	overflow   atomic.Int64 // Errors not sent because the channel was full
+
+	lastReport atomic.Int64                  // Time of the last drop report (Unix ns)
+	reported   [dropReasonCount]atomic.Int64 // Drops per reason covered by earlier reports
+}
+
+// Errors returns a channel receiving the errors of this logger and the
+// loggers derived from it, instead of the process-wide error handler.
+//
+// Until Errors is first called, the logger reports its errors to the
+// handler set with SetErrorHandler. From then on they are sent to the
+// channel, together with errors that are not reported otherwise:
+//   - ErrCodeEncodingFailed: the encoder panicked on a record, usually in a
+//     field's Stringer or marshaler (the record is not written; without
+//     Errors this is reported to the process-wide handler)
+//   - ErrCodeWriteFailed: a write to the output, an additional output
+//     (WithOutput) or the debug session output failed
+//   - ErrCodeRecordsDropped: records were lost (ring full, canceled,
+//     max age, emergency flush or Shutdown deadline), reported at most
+//     once per second with the count per reason since the previous report
+//
+// Errors never blocks logging: when the channel (ErrorChannelCapacity
+// errors) is full, new errors are discarded and counted in Stats as
+// "errors_overflow". The channel is never closed, since records logged
+// after Close can still report errors; stop reading it when done with the
+// logger.
+//
+// Returns:
+//   - <-chan *errors.Error: Channel shared by every call and every clone
+//
+// Example:
+//
+//	go func() {
+//	    for err := range logger.Errors() {
+//	        errorsMetric.WithLabelValues(string(err.ErrorCode())).Inc()
+//	    }
+//	}()
+func (l *Logger) Errors() <-chan *errors.Error {
+	e := l.errs
+	if e == nil {
+		return nil
+	}
+	e.once.Do(func() {
+		e.ch = make(chan *errors.Error, ErrorChannelCapacity)
+		if l.drops != nil {
+			for _, reason := range lossReasons {
+				e.reported[reason].Store(l.drops.load(reason)) // Report only new losses
+			}
+		}
+		e.subscribed.Store(true)
+	})
+	return e.ch
+}
+
+// errorsWanted reports whether Errors has been called, so that errors only
+// sent to the channel are worth building.
+func (l *Logger) errorsWanted() bool {
+	return l.errs != nil && l.errs.subscribed.Load()
+}
+
+// reportError sends err to the Errors channel, or to the process-wide
+// handler if the channel was never requested.
+func (l *Logger) reportError(err *errors.Error) {
+	if !l.errorsWanted() {
+		handleError(err)
+		return
+	}
+	l.errs.send(err)
+}
+
+// send delivers err without blocking.
+func (e *errorChannel) send(err *errors.Error) {
+	select {
+	case e.ch <- err:
+	default:
+		e.overflow.Add(1)
+	}
+}
+
+// full reports whether a send would be discarded, to skip building errors
+// that would be.
+func (e *errorChannel) full() bool {
+	return len(e.ch) == cap(e.ch)
+}
+
+// reportWriteError reports a failed write to the Errors channel. Called by
+// the consumer.
+func (l *Logger) reportWriteError(err error, out any) {
+	if !l.errorsWanted() {
+		return
+	}
+	if l.errs.full() {
+		l.errs.overflow.Add(1)
+		return
+	}
+	l.errs.send(errors.Wrap(err, ErrCodeWriteFailed, "failed to write log record").
+		WithContext("logger", l.name).
+		WithContext("output", outputName(out)))
+}
+
+// reportEncodeError reports a record the encoder failed to encode. Called
+// by the consumer.
+func (l *Logger) reportEncodeError(err *errors.Error, rec *Record) {
+	l.reportError(err.WithContext("logger", l.name).
+		WithContext("level", rec.Level.String()).
+		WithContext("message", rec.Msg))
+}
+
+// encodeRecord encodes rec into buf. A panic in the encoder, usually raised
+// by a field's Stringer or marshaler, is recovered and returned as an
+// ErrCodeEncodingFailed error instead of taking the consumer down.
+func encodeRecord(enc Encoder, rec *Record, now time.Time, buf *bytes.Buffer) (err *errors.Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = encoderPanic(r)
+		}
+	}()
+	enc.Encode(rec, now, buf)
+	return nil
+}
+
+// encodeRecordTo is encodeRecord for a StreamEncoder, also returning the
+// write error EncodeTo reports.
+func encodeRecordTo(enc StreamEncoder, rec *Record, now time.Time, w io.Writer) (err *errors.Error, werr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = encoderPanic(r)
+		}
+	}()
+	return nil, enc.EncodeTo(rec, now, w)
+}
+
+// encoderPanic returns the error for a panic recovered while encoding.
+func encoderPanic(r any) *errors.Error {
+	return NewLoggerError(ErrCodeEncodingFailed, fmt.Sprintf("encoder panicked: %v", r))
+}
+
+// noteDrop reports a drop for reason to the Errors channel, at most once
+// per dropReportInterval.
+func (l *Logger) noteDrop(reason DropReason) {
+	if lossMask&(1<<reason) == 0 || !l.errorsWanted() || l.drops == nil {
+		return
+	}
+	e := l.errs
+	now := timecache.CachedTimeNano()
+	last := e.lastReport.Load()
+	if now-last < int64(dropReportInterval) || !e.lastReport.CompareAndSwap(last, now) {
+		return
+	}
+	l.reportDrops()
+}
+
+// reportDrops sends a report of the records lost since the previous one
+// to the Errors channel, if there are any.
+func (l *Logger) reportDrops() {
+	e := l.errs
+	err := NewLoggerError(ErrCodeRecordsDropped, "log records dropped").WithSeverity("warning")
+	var total int64
+	for _, reason := range lossReasons {
+		count := l.drops.load(reason)
+		if n := count - e.reported[reason].Swap(count); n > 0 {
+			_ = err.WithContext("dropped_"+reason.String(), n)
+			total += n
+		}
+	}
+	if total == 0 {
+		return
+	}
+	e.send(err.WithContext("dropped", total))
+}
+
+// outputName returns a short description of out for error context.
+func outputName(out any) string {
+	if s, ok := out.(interface{ Name() string }); ok {
+		return s.Name()
+	}
+	return fmt.Sprintf("%T", out)
+}
@@ -0,0 +1,173 @@
+// error_channel_test.go: Tests for the per-logger error channel
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/agilira/go-errors"
+)
+
+// panicStringer panics when the encoder formats it.
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("broken stringer") }
+
+// drainErrors returns the errors waiting in ch.
+func drainErrors(ch <-chan *errors.Error) []*errors.Error {
+	var got []*errors.Error
+	for {
+		select {
+		case err := <-ch:
+			got = append(got, err)
+		default:
+			return got
+		}
+	}
+}
+
+// withCode returns the errors of errs with the given code.
+func withCode(errs []*errors.Error, code errors.ErrorCode) []*errors.Error {
+	var got []*errors.Error
+	for _, err := range errs {
+		if err.ErrorCode() == code {
+			got = append(got, err)
+		}
+	}
+	return got
+}
+
+func TestErrors_WriteFailures(t *testing.T) {
+	reported := captureErrors(t)
+	out := &errorWriter{stderrors.New("disk full")}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, Name: "api"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Info("before subscribing") // Not reported anywhere
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("write failure reported to the global handler: %v", got)
+	}
+
+	errs := logger.Errors()
+	if logger.Errors() != errs {
+		t.Error("Errors returned a different channel")
+	}
+	logger.With(Str("k", "v")).Info("after subscribing") // Clones share the channel
+
+	got := drainErrors(errs)
+	if len(got) != 1 || got[0].ErrorCode() != ErrCodeWriteFailed {
+		t.Fatalf("got %v, want one ErrCodeWriteFailed", got)
+	}
+	if got[0].Context["logger"] != "api" || !stderrors.Is(got[0], out.error) {
+		t.Errorf("error = %v, context %v", got[0], got[0].Context)
+	}
+	if _, ok := logger.Stats()["errors_overflow"]; !ok {
+		t.Error("Stats missing errors_overflow")
+	}
+}
+
+func TestErrors_EncoderPanic(t *testing.T) {
+	for _, subscribe := range []bool{false, true} {
+		name := "global handler"
+		if subscribe {
+			name = "channel"
+		}
+		t.Run(name, func(t *testing.T) {
+			reported := captureErrors(t)
+			out := &testSyncer{}
+			logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, BatchSize: 8})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			var errs <-chan *errors.Error
+			if subscribe {
+				errs = logger.Errors()
+			}
+
+			logger.Info("broken", Stringer("value", panicStringer{}))
+			logger.Info("still logging")
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			if strings.Contains(out.String(), "broken") || !strings.Contains(out.String(), "still logging") {
+				t.Errorf("output = %q", out.String())
+			}
+			got := reported()
+			if subscribe {
+				if len(got) != 0 {
+					t.Errorf("global handler got %v", got)
+				}
+				got = drainErrors(errs)
+			}
+			if got = withCode(got, ErrCodeEncodingFailed); len(got) != 1 {
+				t.Fatalf("got %d ErrCodeEncodingFailed errors, want 1", len(got))
+			}
+			if got[0].Context["message"] != "broken" || !strings.Contains(got[0].Error(), "broken stringer") {
+				t.Errorf("error = %v, context %v", got[0], got[0].Context)
+			}
+		})
+	}
+}
+
+func TestErrors_DropReports(t *testing.T) {
+	reported := captureErrors(t)
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	errs := logger.Errors()
+
+	// Not started: the ring fills up after 64 records, then 3 are dropped
+	for i := 0; i < 67; i++ {
+		logger.Info("fill")
+	}
+	got := withCode(drainErrors(errs), ErrCodeRecordsDropped)
+	if len(got) != 1 || got[0].Context["dropped_ring_full"] != int64(1) {
+		t.Fatalf("got %v, want one report of the first drop", got)
+	}
+
+	// The rest of the burst is reported on Close
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	all := drainErrors(errs)
+	got = withCode(all, ErrCodeRecordsDropped)
+	if len(got) != 1 || got[0].Context["dropped"] != int64(2) {
+		t.Fatalf("got %v, want a final report of 2 drops", got)
+	}
+	if len(withCode(all, ErrLoggerNotStarted.Code)) > 1 {
+		t.Errorf("not started reported more than once: %v", all)
+	}
+	if got := reported(); len(got) != 0 {
+		t.Errorf("global handler got %v", got)
+	}
+}
+
+func TestErrors_Overflow(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &errorWriter{stderrors.New("broken pipe")}, Encoder: NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	errs := logger.Errors()
+
+	for i := 0; i < ErrorChannelCapacity+10; i++ {
+		logger.Info("lost")
+	}
+	if n := len(errs); n != ErrorChannelCapacity {
+		t.Errorf("channel holds %d errors, want %d", n, ErrorChannelCapacity)
+	}
+	if got := logger.Stats()["errors_overflow"]; got != 10 {
+		t.Errorf("errors_overflow = %d, want 10", got)
+	}
+}
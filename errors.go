@@ -46,6 +46,7 @@ const (
 	ErrCodePoolExhausted    errors.ErrorCode = "IRIS_POOL_EXHAUSTED"
 	ErrCodeTimeout          errors.ErrorCode = "IRIS_TIMEOUT"
 	ErrCodeResourceLimit    errors.ErrorCode = "IRIS_RESOURCE_LIMIT"
+	ErrCodeClockDrift       errors.ErrorCode = "IRIS_CLOCK_DRIFT"
 
 	// Ring buffer errors
 	ErrCodeRingInvalidCapacity  errors.ErrorCode = "IRIS_RING_INVALID_CAPACITY"
@@ -53,6 +54,10 @@ const (
 	ErrCodeRingMissingProcessor errors.ErrorCode = "IRIS_RING_MISSING_PROCESSOR"
 	ErrCodeRingClosed           errors.ErrorCode = "IRIS_RING_CLOSED"
 	ErrCodeRingBuildFailed      errors.ErrorCode = "IRIS_RING_BUILD_FAILED"
+	ErrCodeRingFull             errors.ErrorCode = "IRIS_RING_FULL"
+	ErrCodeWriteCanceled        errors.ErrorCode = "IRIS_WRITE_CANCELED"
+	ErrCodeConsumerStalled      errors.ErrorCode = "IRIS_CONSUMER_STALLED"
+	ErrCodeRecordsDropped       errors.ErrorCode = "IRIS_RECORDS_DROPPED"
 
 	// Hook and middleware errors
 	ErrCodeHookExecution   errors.ErrorCode = "IRIS_HOOK_EXECUTION"
@@ -64,6 +69,9 @@ const (
 	ErrCodeFileWrite        errors.ErrorCode = "IRIS_FILE_WRITE"
 	ErrCodeFileRotation     errors.ErrorCode = "IRIS_FILE_ROTATION"
 	ErrCodePermissionDenied errors.ErrorCode = "IRIS_PERMISSION_DENIED"
+
+	// Debug diagnostics
+	ErrCodeRecordMisuse errors.ErrorCode = "IRIS_RECORD_MISUSE"
 )
 
 // ErrorHandler represents a function that handles errors within the logging system
@@ -246,7 +254,7 @@ func validateErrorCodes() {
 		ErrCodeRingBuildFailed, ErrCodeHookExecution,
 		ErrCodeMiddlewareChain, ErrCodeFilterFailed, ErrCodeFileOpen,
 		ErrCodeFileWrite, ErrCodeFileRotation, ErrCodePermissionDenied,
-		ErrCodeLoggerExecution,
+		ErrCodeLoggerExecution, ErrCodeRecordMisuse,
 	}
 
 	for _, code := range codes {
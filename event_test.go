@@ -0,0 +1,222 @@
+// event_test.go: Tests for typed log events
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type eventStatus string
+
+type eventBase struct {
+	Service string `iris:"service"`
+}
+
+type orderPlaced struct {
+	eventBase
+	OrderID  string        `iris:"order_id"`
+	Amount   float64       `iris:"amount"`
+	Quantity int32         `iris:"qty"`
+	Card     string        `iris:"card,secret"`
+	Coupon   string        `iris:"coupon,omitempty"`
+	Status   eventStatus   `iris:"status"`
+	Elapsed  time.Duration `iris:"elapsed"`
+	Failure  error         `iris:"failure,omitempty"`
+	Untagged bool
+	Ignored  string `iris:"-"`
+	internal int
+}
+
+// newEventLogger returns an inline JSON logger writing to a testSyncer.
+func newEventLogger(t *testing.T, level Level) (*Logger, *testSyncer) {
+	t.Helper()
+	out := &testSyncer{}
+	logger, err := New(Config{Level: level, Output: out, Encoder: NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	t.Cleanup(func() { safeCloseWithOptionsLogger(t, logger) })
+	return logger, out
+}
+
+func TestRegisterEvent_Log(t *testing.T) {
+	ev, err := RegisterEvent[orderPlaced]("order placed", Warn)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+	if ev.Level() != Warn {
+		t.Errorf("Level() = %v, want warn", ev.Level())
+	}
+	logger, out := newEventLogger(t, Info)
+
+	order := orderPlaced{
+		eventBase: eventBase{Service: "checkout"},
+		OrderID:   "A-42",
+		Amount:    99.5,
+		Quantity:  3,
+		Card:      "4111111111111111",
+		Status:    "paid",
+		Elapsed:   1500 * time.Millisecond,
+		Untagged:  true,
+		Ignored:   "hidden",
+		internal:  7,
+	}
+	if !ev.Log(logger, order) {
+		t.Fatal("Log returned false")
+	}
+	order.Failure = errors.New("card declined")
+	order.Coupon = "SPRING"
+	if !logger.Event(&order) {
+		t.Fatal("Event(pointer) returned false")
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(lines), out.String())
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[1], err)
+	}
+
+	want := map[string]any{
+		"msg":      "order placed",
+		"level":    "warn",
+		"service":  "checkout",
+		"order_id": "A-42",
+		"amount":   99.5,
+		"qty":      float64(3),
+		"status":   "paid",
+		"Untagged": true,
+	}
+	for k, v := range want {
+		if first[k] != v {
+			t.Errorf("%s = %v, want %v", k, first[k], v)
+		}
+	}
+	if first["card"] == "4111111111111111" {
+		t.Error("secret field was not redacted")
+	}
+	for _, k := range []string{"coupon", "failure", "Ignored", "internal"} {
+		if _, ok := first[k]; ok {
+			t.Errorf("unexpected key %q in %s", k, lines[0])
+		}
+	}
+	if second["coupon"] != "SPRING" || second["failure"] != "card declined" {
+		t.Errorf("omitempty fields missing once set: %s", lines[1])
+	}
+}
+
+func TestRegisterEvent_Errors(t *testing.T) {
+	type dupKey struct {
+		A string `iris:"k"`
+		B string `iris:"k"`
+	}
+	type badType struct {
+		M map[string]int
+	}
+	type badOption struct {
+		A string `iris:"a,compress"`
+	}
+	type secretInt struct {
+		A int `iris:"a,secret"`
+	}
+	type once struct{ A string }
+
+	tests := []struct {
+		name     string
+		register func() error
+		want     string
+	}{
+		{"not a struct", func() error { _, err := RegisterEvent[string]("x", Info); return err }, "must be a struct"},
+		{"duplicate key", func() error { _, err := RegisterEvent[dupKey]("x", Info); return err }, "duplicate"},
+		{"unsupported type", func() error { _, err := RegisterEvent[badType]("x", Info); return err }, "unsupported"},
+		{"unknown option", func() error { _, err := RegisterEvent[badOption]("x", Info); return err }, "compress"},
+		{"secret int", func() error { _, err := RegisterEvent[secretInt]("x", Info); return err }, "string fields only"},
+		{"registered twice", func() error {
+			if _, err := RegisterEvent[once]("x", Info); err != nil {
+				return err
+			}
+			_, err := RegisterEvent[once]("x", Info)
+			return err
+		}, "already registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.register()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoggerEvent_UnregisteredAndFiltered(t *testing.T) {
+	type debugEvent struct {
+		N int `iris:"n"`
+	}
+	ev, err := RegisterEvent[debugEvent]("debug event", Debug)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+	logger, out := newEventLogger(t, Info)
+
+	if logger.Event(struct{ X int }{1}) {
+		t.Error("Event accepted an unregistered type")
+	}
+	if logger.Event((*debugEvent)(nil)) {
+		t.Error("Event accepted a nil pointer")
+	}
+	if !ev.Log(logger, debugEvent{N: 1}) {
+		t.Error("filtered event should report success")
+	}
+	if out.String() != "" {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	fields := ev.Fields(debugEvent{N: 5})
+	if len(fields) != 1 || fields[0].K != "n" || fields[0].IntValue() != 5 {
+		t.Errorf("Fields() = %+v", fields)
+	}
+}
+
+func TestEventType_LogAllocations(t *testing.T) {
+	type plain struct {
+		ID    string  `iris:"id"`
+		Count int     `iris:"count"`
+		Ratio float64 `iris:"ratio"`
+	}
+	ev, err := RegisterEvent[plain]("plain", Info)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+	logger, err := New(Config{Level: Info, Output: WrapWriter(discardWriter{}), Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	p := plain{ID: "x", Count: 1, Ratio: 0.5}
+	allocs := testing.AllocsPerRun(100, func() { ev.Log(logger, p) })
+	if allocs > 0 {
+		t.Errorf("EventType.Log allocated %.1f times per call", allocs)
+	}
+}
+
+// discardWriter drops everything written to it.
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,157 @@
+// fanout.go: Additional encoder/sink pairs fed by the same consumer
+//
+// A service that wants binary records in a file and JSON on stdout used to
+// need two loggers, so every call site logged twice and every record crossed
+// two ring buffers. WithOutput attaches extra outputs to one logger instead:
+// the consumer encodes each record once per output, in the same pass, with
+// the same timestamp.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"time"
+)
+
+// fanoutOutput is an additional encoder and sink added with WithOutput.
+type fanoutOutput struct {
+	enc   Encoder
+	out   WriteSyncer
+	route uint32 // Record.routes bit of a routed pipeline sink (0 = every record)
+	name  string // Pipeline sink name, prefixing its delivery stats
+}
+
+// WithOutput adds an output that receives every record written to the
+// logger, encoded with its own encoder.
+//
+// Records are encoded for Config.Output first and then for each output in
+// the order the options were given, all in the consumer thread and with the
+// same timestamp, so one slow sink delays the others exactly as a slow
+// Config.Output does. Sync, Close and Reopen apply to every output. Records
+// that WithClassification routes to its restricted sink are not copied to
+// additional outputs.
+//
+// Parameters:
+//   - enc: Encoder for this output
+//   - out: Destination for the encoded records
+//
+// A nil enc or out makes the option a no-op.
+//
+// Returns:
+//   - Option: Configuration function to add the output
+//
+// Example:
+//
+//	file, _ := iris.NewSharedFileWriter("/var/log/app/app.bin")
+//	logger, err := iris.New(iris.Config{
+//	    Output:  iris.WrapWriter(os.Stdout),
+//	    Encoder: iris.NewJSONEncoder(),
+//	}, iris.WithOutput(iris.NewBinaryEncoder(), file))
+func WithOutput(enc Encoder, out WriteSyncer) Option {
+	return withRoutedOutput(enc, out, 0, "")
+}
+
+// withRoutedOutput adds the output of the pipeline sink name, receiving
+// only the records whose Record.routes has the route bit set, or every
+// record if route is 0.
+func withRoutedOutput(enc Encoder, out WriteSyncer, route uint32, name string) Option {
+	return func(o *loggerOptions) {
+		if enc == nil || out == nil {
+			return
+		}
+		outputs := make([]fanoutOutput, len(o.outputs), len(o.outputs)+1)
+		copy(outputs, o.outputs)
+		o.outputs = append(outputs, fanoutOutput{enc: enc, out: out, route: route, name: name})
+	}
+}
+
+// WithSyncWriter adds an output that receives every record written to the
+// logger as a structured Record instead of encoded bytes, for writers that
+// ship records to a system with its own data model (an OpenTelemetry
+// collector, for instance).
+//
+// WriteRecord is called in the consumer thread after Config.Output and the
+// WithOutput outputs, and the record is only valid during the call: a
+// writer that buffers must copy what it keeps. Its errors are reported like
+// write errors (see Logger.Errors). Sync also syncs the writer if it has a
+// Sync() error method, and Close closes it once the records are flushed.
+// Records that WithClassification routes to its restricted sink are not
+// passed to it.
+//
+// Parameters:
+//   - w: Writer for the records (nil is ignored)
+//
+// Returns:
+//   - Option: Configuration function to add the writer
+//
+// Example:
+//
+//	exporter, err := otel.NewOTLPWriter(ctx, otel.OTLPConfig{Endpoint: "collector:4317"})
+//	...
+//	logger, err := iris.New(cfg, iris.WithSyncWriter(exporter))
+func WithSyncWriter(w SyncWriter) Option {
+	return func(o *loggerOptions) {
+		if w == nil {
+			return
+		}
+		writers := make([]SyncWriter, len(o.recordWriters), len(o.recordWriters)+1)
+		copy(writers, o.recordWriters)
+		o.recordWriters = append(writers, w)
+		o.owned = append(o.owned[:len(o.owned):len(o.owned)], w)
+	}
+}
+
+// writeOutputs encodes rec for every additional output, reusing buf, and
+// passes it to every SyncWriter.
+func (l *Logger) writeOutputs(rec *Record, now time.Time, buf *bytes.Buffer) {
+	for _, o := range l.opts.outputs {
+		if o.route != 0 && rec.routes&o.route == 0 {
+			continue
+		}
+		buf.Reset()
+		o.enc.Encode(rec, now, buf)
+		if _, err := o.out.Write(buf.Bytes()); err != nil {
+			l.reportWriteError(err, o.out)
+		}
+	}
+	for _, w := range l.opts.recordWriters {
+		if err := w.WriteRecord(rec); err != nil {
+			l.reportWriteError(err, w)
+		}
+	}
+}
+
+// syncOutputs syncs every additional output, and the SyncWriters that
+// support it, and returns the first error.
+func (l *Logger) syncOutputs() error {
+	var first error
+	for _, o := range l.opts.outputs {
+		if err := o.out.Sync(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, w := range l.opts.recordWriters {
+		if s, ok := w.(interface{ Sync() error }); ok {
+			if err := s.Sync(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// reopenOutputs reopens every additional output that supports it and
+// returns the first error.
+func (l *Logger) reopenOutputs() error {
+	var first error
+	for _, o := range l.opts.outputs {
+		if err := reopenSink(o.out); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
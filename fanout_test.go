@@ -0,0 +1,184 @@
+// fanout_test.go: Tests for additional outputs
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// syncCountingSyncer counts Sync and Reopen calls.
+type syncCountingSyncer struct {
+	testSyncer
+	syncs   int
+	reopens int
+	err     error
+}
+
+func (s *syncCountingSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncs++
+	return s.err
+}
+
+func (s *syncCountingSyncer) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reopens++
+	return nil
+}
+
+func TestWithOutput_FanOut(t *testing.T) {
+	primary := &testSyncer{}
+	text := &syncCountingSyncer{}
+	console := &testSyncer{}
+	consoleEnc := NewConsoleEncoder()
+	consoleEnc.LevelSections = map[Level]ConsoleSection{Info: ConsoleSectionNone}
+
+	logger, err := New(Config{Level: Info, Output: primary, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithOutput(NewTextEncoder(), text), WithOutput(consoleEnc, console), WithOutput(nil, console), WithOutput(consoleEnc, nil))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	logger.With(Str("user", "u1")).Info("login")
+	logger.Debug("filtered")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		got    string
+		suffix string
+	}{
+		{"primary", primary.String(), `,"level":"info","msg":"login","user":"u1"}` + "\n"},
+		{"text", text.String(), ` level=info msg="login" user="u1"` + "\n"},
+		{"console", console.String(), "INFO login user=u1\n"},
+	}
+	for _, tt := range tests {
+		if strings.Count(tt.got, "\n") != 1 || !strings.HasSuffix(tt.got, tt.suffix) {
+			t.Errorf("%s output = %q, want one record ending in %q", tt.name, tt.got, tt.suffix)
+		}
+	}
+
+	// Every output encodes the same timestamp
+	ts := strings.SplitN(primary.String(), `"`, 5)[3]
+	if !strings.HasPrefix(text.String(), "time="+ts+" ") {
+		t.Errorf("text timestamp differs from primary %s: %q", ts, text.String())
+	}
+	if text.syncs == 0 {
+		t.Error("Close did not sync the additional output")
+	}
+}
+
+func TestWithOutput_SyncAndReopen(t *testing.T) {
+	failure := errors.New("disk full")
+	extra := &syncCountingSyncer{err: failure}
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithOutput(NewJSONEncoder(), extra))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer func() { _ = logger.Close() }()
+
+	if err := logger.Sync(); !errors.Is(err, failure) {
+		t.Errorf("Sync error = %v, want %v", err, failure)
+	}
+	extra.err = nil
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if extra.reopens != 1 {
+		t.Errorf("additional output reopened %d times, want 1", extra.reopens)
+	}
+}
+
+func TestWithOutput_RestrictedRecordsNotCopied(t *testing.T) {
+	primary, restricted, extra := &testSyncer{}, &testSyncer{}, &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: primary, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithClassification(ClassificationConfig{Detectors: []Detector{KeyDetector("patient", "mrn")}, Restricted: restricted}),
+		WithOutput(NewJSONEncoder(), extra))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	logger.Info("visit", Str("mrn", "12345"))
+	logger.Info("public")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if !strings.Contains(restricted.String(), "12345") {
+		t.Errorf("restricted sink missing classified record: %q", restricted.String())
+	}
+	for name, out := range map[string]string{"primary": primary.String(), "additional": extra.String()} {
+		if strings.Contains(out, "12345") || !strings.Contains(out, "public") {
+			t.Errorf("%s output = %q, want only the public record", name, out)
+		}
+	}
+}
+
+// recordCollector is a SyncWriter keeping the messages it receives.
+type recordCollector struct {
+	msgs   []string
+	err    error
+	syncs  int
+	closed bool
+}
+
+func (c *recordCollector) WriteRecord(rec *Record) error {
+	c.msgs = append(c.msgs, rec.Level.String()+":"+rec.Msg)
+	return c.err
+}
+
+func (c *recordCollector) Sync() error {
+	c.syncs++
+	return nil
+}
+
+func (c *recordCollector) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestWithSyncWriter(t *testing.T) {
+	primary := &testSyncer{}
+	ok, failing := &recordCollector{}, &recordCollector{err: errors.New("collector unavailable")}
+	logger, err := New(Config{Level: Info, Output: primary, Encoder: NewJSONEncoder(), Inline: true},
+		WithSyncWriter(ok), WithSyncWriter(failing), WithSyncWriter(nil))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	errs := logger.Errors()
+
+	logger.Info("shipped")
+	logger.Debug("filtered")
+	logger.Named("db").Warn("slow query")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := strings.Join(ok.msgs, ","); got != "info:shipped,warn:slow query" {
+		t.Errorf("writer got %q", got)
+	}
+	if !strings.Contains(primary.String(), "slow query") {
+		t.Errorf("primary output = %q", primary.String())
+	}
+	if ok.syncs == 0 || !ok.closed || !failing.closed {
+		t.Errorf("syncs = %d, closed = %v/%v", ok.syncs, ok.closed, failing.closed)
+	}
+	if got := withCode(drainErrors(errs), ErrCodeWriteFailed); len(got) != 2 {
+		t.Errorf("got %d write errors, want 2", len(got))
+	}
+}
@@ -39,6 +39,10 @@ const (
 	kindStringer
 	// kindObject represents arbitrary object data (interface{})
 	kindObject
+	// kindNoSample marks a record as exempt from sampling (never encoded)
+	kindNoSample
+	// kindIngestTime sets the record timestamp (never encoded)
+	kindIngestTime
 )
 
 // Field represents a key-value pair with type information for structured logging.
@@ -272,6 +276,37 @@ func (f Field) BytesValue() []byte {
 	return nil
 }
 
+// Value returns the field's value as an interface{}, regardless of its kind.
+// Integers are returned as int64, unsigned integers as uint64, floats as
+// float64, and errors, stringers and objects as the original value.
+// Secrets are returned redacted, exactly as the encoders write them.
+func (f Field) Value() interface{} {
+	switch f.T {
+	case kindString:
+		return f.Str
+	case kindSecret:
+		return "[REDACTED]"
+	case kindInt64:
+		return f.I64
+	case kindUint64:
+		return f.U64
+	case kindFloat64:
+		return f.F64
+	case kindBool:
+		return f.I64 != 0
+	case kindDur:
+		return time.Duration(f.I64)
+	case kindTime:
+		return time.Unix(0, f.I64)
+	case kindBytes:
+		return f.B
+	case kindError, kindStringer, kindObject:
+		return f.Obj
+	default:
+		return nil
+	}
+}
+
 // Error helpers (zap-like)
 
 // Err creates an error field with key "error".
@@ -316,6 +351,57 @@ func Object(k string, val interface{}) Field {
 	return Field{K: k, T: kindObject, Obj: val}
 }
 
+// NoSample marks a record as exempt from sampler suppression.
+//
+// Records carrying this field are always logged when their level is enabled,
+// even if the configured Sampler would drop them. Use it for critical
+// business events (payment failures, security decisions) that must never be
+// statistically dropped. The marker is not written to the output and does
+// not count against the per-record field limit.
+//
+// Passing NoSample() to Logger.With exempts every record of the derived logger.
+//
+// Example:
+//
+//	logger.Info("payment failed", iris.Str("order", id), iris.NoSample())
+func NoSample() Field { return Field{T: kindNoSample} }
+
+// IngestTime sets the timestamp of a record to t instead of the time the
+// logger encodes it.
+//
+// Use it to re-ingest historical events (replayed spill files, imports from
+// other systems) with their original timestamps; records logged through
+// Logger.Write can call Record.SetTime instead. Like NoSample, the marker
+// is not written as a field and does not count against the per-record
+// field limit. See WithIngestionTime to also record when the event was
+// ingested.
+//
+// Example:
+//
+//	logger.Info(ev.Message, iris.Str("source", "legacy"), iris.IngestTime(ev.Timestamp))
+func IngestTime(t time.Time) Field {
+	return Field{T: kindIngestTime, I64: t.UnixNano(), Obj: t.Location()}
+}
+
+// ingestTime returns the timestamp carried by an IngestTime marker.
+func (f Field) ingestTime() time.Time {
+	t := time.Unix(0, f.I64)
+	if loc, ok := f.Obj.(*time.Location); ok {
+		t = t.In(loc)
+	}
+	return t
+}
+
+// hasNoSample reports whether any field is a NoSample marker.
+func hasNoSample(fields []Field) bool {
+	for i := range fields {
+		if fields[i].T == kindNoSample {
+			return true
+		}
+	}
+	return false
+}
+
 // Errors creates a field for multiple errors (like Zap's ErrorsField).
 func Errors(k string, errs []error) Field {
 	return Field{K: k, T: kindObject, Obj: errs}
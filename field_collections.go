@@ -0,0 +1,181 @@
+// field_collections.go: Fields for slices of times and durations, string maps and raw JSON
+//
+// Batch-processing code logs sets of values: the timestamps of a batch, the
+// durations of its stages, the labels of a job. These fields encode them as
+// native JSON arrays and objects instead of a string joined by the caller.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Times creates a field for a slice of timestamps, encoded as a JSON array
+// of RFC3339Nano strings in UTC.
+//
+// Like Bytes, the field refers to v without copying it: v must not be
+// modified until the record has been written (see UnsafeString).
+//
+// Example:
+//
+//	logger.Info("batch closed", iris.Times("received", batch.ReceivedAt))
+//	// {"msg":"batch closed","received":["2025-09-02T12:00:00Z","2025-09-02T12:00:01Z"]}
+func Times(k string, v []time.Time) Field {
+	return Field{K: k, T: kindObject, Obj: timesValue(v)}
+}
+
+// Durations creates a field for a slice of durations, encoded as a JSON
+// array of nanoseconds like Dur. v must not be modified until the record
+// has been written.
+func Durations(k string, v []time.Duration) Field {
+	return Field{K: k, T: kindObject, Obj: durationsValue(v)}
+}
+
+// StringMap creates a field for a map of strings, encoded as a JSON object
+// with its keys in sorted order. m must not be modified until the record
+// has been written: unlike a slice, a map written concurrently with the
+// encoder crashes the program.
+//
+// Example:
+//
+//	logger.Info("job scheduled", iris.StringMap("labels", job.Labels))
+//	// {"msg":"job scheduled","labels":{"queue":"bulk","tenant":"acme"}}
+func StringMap(k string, m map[string]string) Field {
+	return Field{K: k, T: kindObject, Obj: stringMapValue(m)}
+}
+
+// RawJSON creates a field for an already encoded JSON value, written as is
+// by the JSON encoder and as its text by the other encoders. It carries
+// nested values from other JSON sources, such as the objects of log lines
+// converted by the ingest package. A value that is not valid JSON is written
+// as a JSON string instead. Like Bytes, v must not be modified until the
+// record has been written.
+//
+// Example:
+//
+//	logger.Info("webhook received", iris.RawJSON("payload", body))
+func RawJSON(k string, v []byte) Field {
+	return Field{K: k, T: kindObject, Obj: rawJSONValue(v)}
+}
+
+// timesValue is the value of a Times field.
+type timesValue []time.Time
+
+// String returns the timestamps as [t1 t2 ...] for the text and console
+// encoders.
+func (v timesValue) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, t := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeTime(&buf, t.UTC(), time.RFC3339Nano)
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// encodeJSON writes the timestamps as a JSON array of strings.
+func (v timesValue) encodeJSON(buf *bytes.Buffer) {
+	buf.WriteByte('[')
+	for i, t := range v {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('"')
+		writeTime(buf, t.UTC(), time.RFC3339Nano)
+		buf.WriteByte('"')
+	}
+	buf.WriteByte(']')
+}
+
+// durationsValue is the value of a Durations field.
+type durationsValue []time.Duration
+
+// String returns the durations as [1.5s 20ms ...] for the text and console
+// encoders.
+func (v durationsValue) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, d := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(d.String())
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// encodeJSON writes the durations as a JSON array of nanoseconds.
+func (v durationsValue) encodeJSON(buf *bytes.Buffer) {
+	buf.WriteByte('[')
+	for i, d := range v {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeInt(buf, int64(d))
+	}
+	buf.WriteByte(']')
+}
+
+// stringMapValue is the value of a StringMap field.
+type stringMapValue map[string]string
+
+// String returns the map as {k1:v1 k2:v2 ...} in key order for the text and
+// console encoders.
+func (m stringMapValue) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(k)
+		buf.WriteByte(':')
+		buf.WriteString(m[k])
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// encodeJSON writes the map as a JSON object in key order.
+func (m stringMapValue) encodeJSON(buf *bytes.Buffer) {
+	buf.WriteByte('{')
+	for i, k := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		quoteString(k, buf)
+		buf.WriteByte(':')
+		quoteString(m[k], buf)
+	}
+	buf.WriteByte('}')
+}
+
+// rawJSONValue is the value of a RawJSON field.
+type rawJSONValue []byte
+
+// String returns the JSON text for the text and console encoders.
+func (v rawJSONValue) String() string {
+	return string(v)
+}
+
+// encodeJSON writes the value, quoting it if it is not valid JSON so that it
+// cannot break the record.
+func (v rawJSONValue) encodeJSON(buf *bytes.Buffer) {
+	if len(v) == 0 || !json.Valid(v) {
+		quoteString(string(v), buf)
+		return
+	}
+	buf.Write(v)
+}
@@ -0,0 +1,87 @@
+// field_collections_test.go: Tests for the Times, Durations, StringMap and RawJSON fields
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectionFields_JSON(t *testing.T) {
+	t1 := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	t2 := time.Date(2025, 9, 2, 14, 0, 1, 500, time.FixedZone("CEST", 2*3600))
+
+	tests := []struct {
+		name  string
+		field Field
+		want  string
+	}{
+		{"Times", Times("ts", []time.Time{t1, t2}), `"ts":["2025-09-02T12:00:00Z","2025-09-02T12:00:01.0000005Z"]`},
+		{"TimesEmpty", Times("ts", nil), `"ts":[]`},
+		{"Durations", Durations("d", []time.Duration{time.Second, 20 * time.Millisecond}), `"d":[1000000000,20000000]`},
+		{"DurationsEmpty", Durations("d", []time.Duration{}), `"d":[]`},
+		{"StringMap", StringMap("labels", map[string]string{"tenant": "acme", "queue": "bulk"}), `"labels":{"queue":"bulk","tenant":"acme"}`},
+		{"StringMapEscaped", StringMap("m", map[string]string{`a"b`: "line\nbreak"}), `"m":{"a\"b":"line\nbreak"}`},
+		{"StringMapNil", StringMap("m", nil), `"m":{}`},
+		{"RawJSON", RawJSON("req", []byte(`{"id":7,"tags":["a"]}`)), `"req":{"id":7,"tags":["a"]}`},
+		{"RawJSONInvalid", RawJSON("req", []byte(`{"id":`)), `"req":"{\"id\":"`},
+		{"RawJSONEmpty", RawJSON("req", nil), `"req":""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecord(Info, "batch")
+			rec.AddField(tt.field)
+			var buf bytes.Buffer
+			NewJSONEncoder().Encode(rec, time.Unix(0, 0), &buf)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output %s does not contain %s", buf.String(), tt.want)
+			}
+			if !json.Valid(buf.Bytes()) {
+				t.Errorf("invalid JSON: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestCollectionFields_Text(t *testing.T) {
+	fields := []Field{
+		Times("ts", []time.Time{time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)}),
+		Durations("d", []time.Duration{1500 * time.Millisecond, 20 * time.Millisecond}),
+		StringMap("labels", map[string]string{"tenant": "acme", "queue": "bulk"}),
+		RawJSON("req", []byte(`{"id":7}`)),
+	}
+	wants := []string{"2025-09-02T12:00:00Z", "1.5s", "20ms", "queue:bulk", "tenant:acme", ":7}"}
+
+	encoders := []struct {
+		name   string
+		encode func(rec *Record, buf *bytes.Buffer)
+	}{
+		{"Text", func(rec *Record, buf *bytes.Buffer) { NewTextEncoder().Encode(rec, time.Unix(0, 0), buf) }},
+		{"Console", func(rec *Record, buf *bytes.Buffer) { NewConsoleEncoder().Encode(rec, time.Unix(0, 0), buf) }},
+		{"Binary", func(rec *Record, buf *bytes.Buffer) { NewBinaryEncoder().Encode(rec, time.Unix(0, 0), buf) }},
+	}
+
+	for _, enc := range encoders {
+		t.Run(enc.name, func(t *testing.T) {
+			rec := NewRecord(Info, "batch")
+			for _, f := range fields {
+				rec.AddField(f)
+			}
+			var buf bytes.Buffer
+			enc.encode(rec, &buf)
+			for _, want := range wants {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output %q does not contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
@@ -174,3 +174,37 @@ func safeCloseFieldExtendedLogger(t *testing.T, logger *Logger) {
 		t.Logf("Warning: Error closing logger in test: %v", err)
 	}
 }
+
+// TestFieldValue tests the kind-agnostic Value accessor
+func TestFieldValue(t *testing.T) {
+	now := time.Unix(0, 1700000000000000000)
+	err := errors.New("boom")
+
+	tests := []struct {
+		name  string
+		field Field
+		want  interface{}
+	}{
+		{"string", Str("k", "v"), "v"},
+		{"secret redacted", Secret("k", "hunter2"), "[REDACTED]"},
+		{"int", Int("k", -3), int64(-3)},
+		{"uint", Uint16("k", 7), uint64(7)},
+		{"float", Float64("k", 1.5), 1.5},
+		{"bool", Bool("k", true), true},
+		{"duration", Dur("k", time.Second), time.Second},
+		{"time", TimeField("k", now), now},
+		{"error", NamedError("k", err), err},
+		{"marker", NoSample(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Value(); got != tt.want {
+				t.Errorf("Value() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if got := Bytes("k", []byte("raw")).Value().([]byte); string(got) != "raw" {
+		t.Errorf("Value() for bytes = %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+// field_unsafe.go: Zero-copy string fields borrowed from byte slices
+//
+// Hot paths such as HTTP middleware log values that already exist as []byte
+// (header values, path segments from a request buffer). Str(k, string(b))
+// copies every one of them; UnsafeString borrows the bytes instead, which
+// moves the burden of keeping them intact onto the caller. Record debug mode
+// checks that the caller kept its side of the contract.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import "unsafe"
+
+// borrowedString marks a string field created by UnsafeString (stored in
+// Field.I64, which string fields do not otherwise use).
+const borrowedString int64 = 1
+
+// UnsafeString creates a string field that refers to b without copying it.
+//
+// Ownership contract: the logger reads b after the logging call returns,
+// when the consumer encodes the record. The caller must not modify or reuse
+// b until the record has been written, which is guaranteed once Sync or
+// Close returns. In particular b must not be a buffer that is recycled right
+// after the call (a pooled request buffer, a bufio.Reader's internal slice)
+// unless the logger runs in inline mode (Config.Inline), where the record is
+// written before the logging call returns. Hooks registered with
+// WithAsyncHook see the value later still, and fields passed to With live as
+// long as the derived logger: do not use borrowed bytes with either.
+//
+// Breaking the contract writes whatever b contains at encode time, possibly
+// torn. WithRecordDebug detects it: each borrowed value is hashed when the
+// record is enqueued and verified before it is encoded, and a mismatch is
+// reported with ErrCodeRecordMisuse.
+//
+// Parameters:
+//   - k: Field key
+//   - b: Bytes to log as a string (must stay unchanged until written)
+//
+// Returns:
+//   - Field: String field sharing memory with b
+//
+// Example:
+//
+//	// raw is not reused until the logger has been synced
+//	logger.Info("request", iris.UnsafeString("path", raw[pathStart:pathEnd]))
+func UnsafeString(k string, b []byte) Field {
+	return Field{K: k, T: kindString, Str: unsafe.String(unsafe.SliceData(b), len(b)), I64: borrowedString} // #nosec G103 -- read-only view, ownership contract documented above
+}
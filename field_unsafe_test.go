@@ -0,0 +1,87 @@
+// field_unsafe_test.go: Tests for zero-copy string fields
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/agilira/go-errors"
+)
+
+func TestUnsafeString(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want string
+	}{
+		{"value", []byte("Mozilla/5.0"), `"ua":"Mozilla/5.0"`},
+		{"needs escaping", []byte("a\"b"), `"ua":"a\"b"`},
+		{"empty", []byte{}, `"ua":""`},
+		{"nil", nil, `"ua":""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := UnsafeString("ua", tt.b)
+			if f.T != kindString || f.Str != string(tt.b) {
+				t.Fatalf("UnsafeString = %+v, want string field %q", f, tt.b)
+			}
+			if len(tt.b) > 0 && unsafe.StringData(f.Str) != &tt.b[0] {
+				t.Error("UnsafeString copied the bytes")
+			}
+			rec := NewRecord(Info, "m")
+			rec.AddField(f)
+			var buf bytes.Buffer
+			NewJSONEncoder().Encode(rec, time.Now(), &buf)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("encoded %q, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsafeString_RecordDebug(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	collector := &misuseCollector{}
+	logger, err := New(Config{
+		Level:     Debug,
+		Output:    &testSyncer{},
+		Encoder:   NewJSONEncoder(),
+		Capacity:  64,
+		AutoStart: AutoStartOff,
+	}, WithRecordDebug(collector.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	// Not started yet: the records wait in the ring after Info returns
+	kept := []byte("stable")
+	reused := []byte("before")
+	logger.Info("kept", UnsafeString("v", kept))
+	logger.Info("reused", UnsafeString("header", reused), Str("k", "v"))
+	copy(reused, "after!")
+
+	logger.Start()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if collector.count() != 1 {
+		t.Fatalf("expected 1 misuse report, got %d", collector.count())
+	}
+	err = collector.first()
+	if !IsLoggerError(err, ErrCodeRecordMisuse) || !strings.Contains(err.Error(), "UnsafeString") {
+		t.Errorf("unexpected diagnostic: %v", err)
+	}
+	if le, ok := err.(*errors.Error); !ok || le.Value != "header" {
+		t.Errorf("diagnostic does not name the field: %#v", err)
+	}
+}
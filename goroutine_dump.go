@@ -0,0 +1,312 @@
+// goroutine_dump.go: Structured goroutine dump field for crash records
+//
+// A Panic or DPanic record often needs more than the stack of the logging
+// goroutine: the state of the others (who holds the lock, who is stuck on
+// a channel) explains the crash. Attaching runtime.Stack output as a text
+// blob leaves crash aggregation tools unable to group by anything but the
+// whole text. GoroutineDump parses the dump into goroutines, states and
+// frames, so reports can be grouped by the leading frame of the crashing
+// goroutine and filtered by state.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bounds of the runtime.Stack buffer used by Goroutines.
+const (
+	goroutineDumpInitial = 64 << 10
+	goroutineDumpMax     = 8 << 20
+)
+
+// irisPackagePrefix identifies the frames of this package, which are left
+// out of the leading frames of the logging goroutine.
+const irisPackagePrefix = "github.com/agilira/iris."
+
+// GoroutineFrame is one frame of a goroutine stack.
+type GoroutineFrame struct {
+	Function string // Fully qualified function name
+	File     string // Package import path and file name, as in scrubbed stacks
+	Line     int
+}
+
+// GoroutineInfo is one goroutine of a dump.
+type GoroutineInfo struct {
+	ID          int64
+	State       string // "running", "chan receive", "semacquire", ...
+	WaitMinutes int    // Minutes blocked, as reported by the runtime (0 if under a minute)
+	Locked      bool   // Locked to its OS thread
+	Frames      []GoroutineFrame
+	CreatedBy   string // Function that started the goroutine
+}
+
+// Leading returns the function of the top frame, the key crash reports are
+// usually grouped by, or "" for a goroutine without frames.
+func (g GoroutineInfo) Leading() string {
+	if len(g.Frames) == 0 {
+		return ""
+	}
+	return g.Frames[0].Function
+}
+
+// GoroutineDump is a parsed goroutine dump.
+type GoroutineDump struct {
+	Total      int             // Goroutines in the dump
+	States     map[string]int  // Goroutines per state, over the whole dump
+	Goroutines []GoroutineInfo // The first goroutines, the logging one first
+}
+
+// Goroutines returns a field with key "goroutines" holding a structured
+// dump of the goroutines of the program: the total, the count per state,
+// and the first top goroutines with at most frames frames each. The
+// calling goroutine comes first, without the frames of Iris and of the
+// runtime above the logging call.
+//
+// The dump stops the world while the stacks are collected, so it is meant
+// for crash records; see WithGoroutineDump.
+//
+// Output (JSON):
+//
+//	"goroutines":{"total":14,"states":{"chan receive":3,"running":1,...},
+//	  "list":[{"id":1,"state":"running","leading":"main.handle",
+//	    "frames":[{"func":"main.handle","file":"main/server.go","line":42},...]},...]}
+//
+// Example:
+//
+//	logger.Error("deadline exceeded", iris.Goroutines(10, 8))
+func Goroutines(top, frames int) Field {
+	return Object("goroutines", currentGoroutineDump(top, frames))
+}
+
+// WithGoroutineDump attaches Goroutines(top, frames) to DPanic records and
+// above (DPanic, Panic and Fatal), so crash aggregation can group reports
+// by the leading frame of the crashing goroutine.
+//
+// Parameters:
+//   - top: Goroutines listed (values below 1 list the logging goroutine only)
+//   - frames: Frames per goroutine (values below 1 keep 1)
+//
+// Returns:
+//   - Option: Configuration function to enable goroutine dumps
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithGoroutineDump(20, 10))
+func WithGoroutineDump(top, frames int) Option {
+	return func(o *loggerOptions) {
+		o.addProvider(fieldProvider{min: DPanic, fn: func() Field { return Goroutines(top, frames) }})
+	}
+}
+
+// currentGoroutineDump dumps and parses the goroutines of the program.
+func currentGoroutineDump(top, frames int) GoroutineDump {
+	buf := make([]byte, goroutineDumpInitial)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= goroutineDumpMax {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	return ParseGoroutineDump(buf, top, frames)
+}
+
+// ParseGoroutineDump parses the output of runtime.Stack(buf, true) (or of
+// a crash, or of SIGQUIT). The first goroutine is treated as the one
+// logging: its leading frames in the runtime and in Iris are dropped.
+// Goroutines after the first top are only counted.
+func ParseGoroutineDump(dump []byte, top, frames int) GoroutineDump {
+	top = max(top, 1)
+	frames = max(frames, 1)
+	d := GoroutineDump{States: make(map[string]int)}
+	for _, block := range bytes.Split(dump, []byte("\n\n")) {
+		g, ok := parseGoroutine(string(block), d.Total == 0, frames)
+		if !ok {
+			continue
+		}
+		d.Total++
+		d.States[g.State]++
+		if len(d.Goroutines) < top {
+			d.Goroutines = append(d.Goroutines, g)
+		}
+	}
+	return d
+}
+
+// parseGoroutine parses one goroutine block: the header line, then pairs
+// of function and file lines, then an optional "created by" pair.
+func parseGoroutine(block string, current bool, maxFrames int) (GoroutineInfo, bool) {
+	lines := strings.Split(strings.TrimSpace(block), "\n")
+	var g GoroutineInfo
+	if !parseGoroutineHeader(lines[0], &g) {
+		return g, false
+	}
+	skipping := current
+	for i := 1; i+1 < len(lines); i += 2 {
+		fn, loc := lines[i], strings.TrimSpace(lines[i+1])
+		if name, ok := strings.CutPrefix(fn, "created by "); ok {
+			if j := strings.Index(name, " in goroutine "); j >= 0 {
+				name = name[:j]
+			}
+			g.CreatedBy = name
+			break
+		}
+		if j := strings.LastIndexByte(fn, '('); j > 0 {
+			fn = fn[:j]
+		}
+		frame := GoroutineFrame{Function: fn}
+		if j := strings.LastIndex(loc, " +0x"); j >= 0 {
+			loc = loc[:j]
+		}
+		if j := strings.LastIndexByte(loc, ':'); j >= 0 {
+			frame.Line, _ = strconv.Atoi(loc[j+1:])
+			loc = loc[:j]
+		}
+		frame.File = loc
+		if skipping && (strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, irisPackagePrefix)) &&
+			!strings.HasSuffix(loc, "_test.go") {
+			continue
+		}
+		skipping = false
+		if len(g.Frames) < maxFrames {
+			frame.File = relativeFramePath(runtime.Frame{Function: frame.Function, File: frame.File})
+			g.Frames = append(g.Frames, frame)
+		}
+	}
+	return g, true
+}
+
+// parseGoroutineHeader parses "goroutine 7 [chan receive, 3 minutes,
+// locked to thread]:".
+func parseGoroutineHeader(line string, g *GoroutineInfo) bool {
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok {
+		return false
+	}
+	id, status, ok := strings.Cut(rest, " [")
+	if !ok {
+		return false
+	}
+	var err error
+	if g.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return false
+	}
+	status = strings.TrimSuffix(status, "]:")
+	for i, part := range strings.Split(status, ", ") {
+		switch {
+		case i == 0:
+			g.State = part
+		case part == "locked to thread":
+			g.Locked = true
+		case strings.HasSuffix(part, " minutes"):
+			g.WaitMinutes, _ = strconv.Atoi(strings.TrimSuffix(part, " minutes"))
+		}
+	}
+	return true
+}
+
+// String returns a compact summary used by the text and console encoders:
+// the total, the states and the leading frame of each listed goroutine.
+func (d GoroutineDump) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("{total:")
+	writeInt(&buf, int64(d.Total))
+	buf.WriteString(" states:[")
+	for i, state := range d.sortedStates() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(state)
+		buf.WriteByte('=')
+		writeInt(&buf, int64(d.States[state]))
+	}
+	buf.WriteString("] list:[")
+	for i, g := range d.Goroutines {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		writeInt(&buf, g.ID)
+		buf.WriteByte(' ')
+		buf.WriteString(g.State)
+		if leading := g.Leading(); leading != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(leading)
+		}
+	}
+	buf.WriteString("]}")
+	return buf.String()
+}
+
+// encodeJSON writes the dump as a JSON object.
+func (d GoroutineDump) encodeJSON(buf *bytes.Buffer) {
+	buf.WriteString(`{"total":`)
+	writeInt(buf, int64(d.Total))
+	buf.WriteString(`,"states":{`)
+	for i, state := range d.sortedStates() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		quoteString(state, buf)
+		buf.WriteByte(':')
+		writeInt(buf, int64(d.States[state]))
+	}
+	buf.WriteString(`},"list":[`)
+	for i, g := range d.Goroutines {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"id":`)
+		writeInt(buf, g.ID)
+		buf.WriteString(`,"state":`)
+		quoteString(g.State, buf)
+		if g.WaitMinutes > 0 {
+			buf.WriteString(`,"wait_minutes":`)
+			writeInt(buf, int64(g.WaitMinutes))
+		}
+		if g.Locked {
+			buf.WriteString(`,"locked":true`)
+		}
+		buf.WriteString(`,"leading":`)
+		quoteString(g.Leading(), buf)
+		buf.WriteString(`,"frames":[`)
+		for j, f := range g.Frames {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"func":`)
+			quoteString(f.Function, buf)
+			buf.WriteString(`,"file":`)
+			quoteString(f.File, buf)
+			buf.WriteString(`,"line":`)
+			writeInt(buf, int64(f.Line))
+			buf.WriteByte('}')
+		}
+		buf.WriteByte(']')
+		if g.CreatedBy != "" {
+			buf.WriteString(`,"created_by":`)
+			quoteString(g.CreatedBy, buf)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteString("]}")
+}
+
+// sortedStates returns the states of the dump in ascending order.
+func (d GoroutineDump) sortedStates() []string {
+	states := make([]string, 0, len(d.States))
+	for state := range d.States {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	return states
+}
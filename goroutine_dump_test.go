@@ -0,0 +1,134 @@
+// goroutine_dump_test.go: Tests for the structured goroutine dump field
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testGoroutineDump = `goroutine 7 [running]:
+runtime.Stack({0xc000100000, 0x10000, 0x10000}, 0x1)
+	/usr/local/go/src/runtime/mprof.go:1286 +0x7a
+github.com/agilira/iris.(*Logger).Panic(0xc0000a4000, {0x5d1c2a, 0x4}, {0x0, 0x0, 0x0})
+	/home/dev/iris/iris.go:1498 +0x5e
+github.com/acme/api.(*Server).handle(0xc0000b2000)
+	/home/dev/api/server.go:42 +0x1f
+github.com/acme/api.(*Server).Serve(...)
+	/home/dev/api/server.go:30
+created by github.com/acme/api.Start in goroutine 1
+	/home/dev/api/start.go:12 +0x8a
+
+goroutine 1 [chan receive, 3 minutes, locked to thread]:
+main.main()
+	/home/dev/cmd/main.go:20 +0x45
+
+goroutine 9 [chan receive]:
+github.com/acme/api.worker(0xc0000b4000)
+	/home/dev/api/worker.go:8 +0x2d
+created by github.com/acme/api.Start in goroutine 1
+	/home/dev/api/start.go:15 +0x9c
+`
+
+func TestParseGoroutineDump(t *testing.T) {
+	d := ParseGoroutineDump([]byte(testGoroutineDump), 2, 1)
+	if d.Total != 3 || d.States["chan receive"] != 2 || d.States["running"] != 1 {
+		t.Errorf("Total = %d, States = %v", d.Total, d.States)
+	}
+	if len(d.Goroutines) != 2 {
+		t.Fatalf("listed %d goroutines, want 2", len(d.Goroutines))
+	}
+
+	crashing := d.Goroutines[0]
+	if crashing.ID != 7 || crashing.Leading() != "github.com/acme/api.(*Server).handle" {
+		t.Errorf("crashing goroutine %d leads with %q", crashing.ID, crashing.Leading())
+	}
+	want := GoroutineFrame{Function: "github.com/acme/api.(*Server).handle", File: "github.com/acme/api/server.go", Line: 42}
+	if len(crashing.Frames) != 1 || crashing.Frames[0] != want {
+		t.Errorf("Frames = %+v, want [%+v]", crashing.Frames, want)
+	}
+	if crashing.CreatedBy != "github.com/acme/api.Start" {
+		t.Errorf("CreatedBy = %q", crashing.CreatedBy)
+	}
+
+	blocked := d.Goroutines[1]
+	if blocked.State != "chan receive" || blocked.WaitMinutes != 3 || !blocked.Locked || blocked.Leading() != "main.main" {
+		t.Errorf("blocked goroutine = %+v", blocked)
+	}
+}
+
+func TestGoroutineDump_Encoding(t *testing.T) {
+	d := ParseGoroutineDump([]byte(testGoroutineDump), 3, 2)
+
+	var buf bytes.Buffer
+	d.encodeJSON(&buf)
+	var decoded struct {
+		Total  int            `json:"total"`
+		States map[string]int `json:"states"`
+		List   []struct {
+			ID      int64  `json:"id"`
+			Leading string `json:"leading"`
+			Frames  []struct {
+				Func string `json:"func"`
+				Line int    `json:"line"`
+			} `json:"frames"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+	if decoded.Total != 3 || len(decoded.List) != 3 || len(decoded.List[0].Frames) != 2 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if decoded.List[2].Leading != "github.com/acme/api.worker" {
+		t.Errorf("leading = %q", decoded.List[2].Leading)
+	}
+
+	text := d.String()
+	if !strings.Contains(text, "total:3") || !strings.Contains(text, "chan receive=2") {
+		t.Errorf("String() = %s", text)
+	}
+}
+
+func TestWithGoroutineDump(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Debug, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true},
+		WithGoroutineDump(5, 3), WithPanicCapture(NewPanicCapture()))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Error("routine failure")
+	logger.Panic("invariant broken")
+	_ = logger.Close()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d records, want 2: %s", len(lines), out.String())
+	}
+	if strings.Contains(lines[0], `"goroutines"`) {
+		t.Errorf("dump attached below DPanic: %s", lines[0])
+	}
+	var rec struct {
+		Goroutines struct {
+			Total int `json:"total"`
+			List  []struct {
+				Leading string `json:"leading"`
+			} `json:"list"`
+		} `json:"goroutines"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("invalid record %s: %v", lines[1], err)
+	}
+	if rec.Goroutines.Total < 1 || len(rec.Goroutines.List) == 0 {
+		t.Fatalf("no goroutines in %s", lines[1])
+	}
+	if got := rec.Goroutines.List[0].Leading; got != "github.com/agilira/iris.TestWithGoroutineDump" {
+		t.Errorf("leading frame = %q, want the test function", got)
+	}
+}
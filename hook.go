@@ -0,0 +1,98 @@
+// hook.go: Hook registration entries, level filtering and error reporting
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// hookEntry is a registered Hook with its name and level filter resolved.
+type hookEntry struct {
+	hook     Hook
+	name     string
+	levels   uint16     // Bit (level - Trace) per accepted built-in level
+	custom   []Level    // Accepted levels outside Trace..Fatal (RegisterLevel)
+	filtered bool       // Levels() named at least one level; false = every level
+	async    *asyncHook // Non-nil: fire hands records to the async worker
+	errs     atomic.Int64
+}
+
+// newHookEntry resolves h's name and levels. position names unnamed hooks.
+func newHookEntry(h Hook, position int) *hookEntry {
+	e := &hookEntry{hook: h, name: h.Name()}
+	if e.name == "" {
+		e.name = fmt.Sprintf("hook#%d", position)
+	}
+	for _, level := range h.Levels() {
+		e.filtered = true
+		if level >= Trace && level <= Fatal {
+			e.levels |= 1 << uint(level-Trace)
+		} else {
+			e.custom = append(e.custom, level)
+		}
+	}
+	return e
+}
+
+// accepts reports whether the hook runs for level.
+func (e *hookEntry) accepts(level Level) bool {
+	if !e.filtered {
+		return true
+	}
+	if level >= Trace && level <= Fatal {
+		return e.levels&(1<<uint(level-Trace)) != 0
+	}
+	for _, custom := range e.custom {
+		if custom == level {
+			return true
+		}
+	}
+	return false
+}
+
+// fire runs the hook on rec (or queues it for an async hook) if the level
+// is accepted.
+func (e *hookEntry) fire(rec *Record) {
+	if !e.accepts(rec.Level) {
+		return
+	}
+	if e.async != nil {
+		e.async.enqueue(rec)
+		return
+	}
+	e.run(rec)
+}
+
+// run calls the hook and reports a returned error.
+func (e *hookEntry) run(rec *Record) {
+	if err := e.hook.Run(rec); err != nil {
+		e.errs.Add(1)
+		report := WrapLoggerError(err, ErrCodeHookExecution, "hook "+e.name+" failed")
+		_ = report.WithContext("hook", e.name)
+		handleError(report)
+	}
+}
+
+// addHookStats adds the hook error count to stats when hooks are present.
+func (l *Logger) addHookStats(stats map[string]int64) {
+	var errs int64
+	n := len(l.opts.hooks)
+	for _, e := range l.opts.hooks {
+		errs += e.errs.Load()
+	}
+	errs += l.runtimeHooks.removedErrs.Load()
+	if list := l.runtimeHooks.list.Load(); list != nil {
+		n += len(*list)
+		for _, rh := range *list {
+			errs += rh.entry.errs.Load()
+		}
+	}
+	if n > 0 || errs > 0 {
+		stats["hook_errors"] = errs
+	}
+}
@@ -0,0 +1,206 @@
+// hook_async.go: Hooks executed off the consumer thread
+//
+// Hooks registered with WithHook run synchronously in the consumer, so a
+// slow hook (an HTTP forwarder, a metrics push) stalls every logger sharing
+// the ring. An async hook gets its own goroutine and bounded queue instead:
+// the consumer copies the record into the queue and moves on, records that
+// do not fit are dropped and counted, and a panicking hook is recovered
+// without taking the consumer down.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultAsyncHookQueueSize is the queue size used by WithAsyncHook when
+// queueSize is not positive.
+const DefaultAsyncHookQueueSize = 1024
+
+// asyncHook runs a Hook on its own goroutine, fed by a bounded queue.
+type asyncHook struct {
+	entry *hookEntry // The user hook, run by the worker
+	queue chan *Record
+	pool  sync.Pool // *Record copies handed to the worker
+
+	dropped atomic.Int64 // Records not queued because the queue was full
+	panics  atomic.Int64 // Hook calls that panicked
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// newAsyncHook creates an async hook with the given queue size.
+func newAsyncHook(entry *hookEntry, queueSize int) *asyncHook {
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncHookQueueSize
+	}
+	return &asyncHook{
+		entry: entry,
+		queue: make(chan *Record, queueSize),
+		pool:  sync.Pool{New: func() interface{} { return &Record{} }},
+		quit:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// enqueue is run by the consumer for accepted records: it copies rec, since
+// the ring slot is reused as soon as the consumer returns, and never blocks.
+func (a *asyncHook) enqueue(rec *Record) {
+	c := a.pool.Get().(*Record)
+	c.Level = rec.Level
+	c.Msg = rec.Msg
+	c.Logger = rec.Logger
+	c.Caller = rec.Caller
+	c.Stack = rec.Stack
+	c.n = rec.n
+	c.lost = rec.lost
+	copy(c.fields[:rec.n], rec.fields[:rec.n])
+
+	select {
+	case a.queue <- c:
+	default:
+		a.dropped.Add(1)
+		a.release(c)
+	}
+}
+
+// release clears c so it does not retain field values, and pools it.
+func (a *asyncHook) release(c *Record) {
+	for i := int32(0); i < c.n; i++ {
+		c.fields[i] = Field{}
+	}
+	c.Reset()
+	a.pool.Put(c)
+}
+
+// start launches the worker goroutine once.
+func (a *asyncHook) start() {
+	a.startOnce.Do(func() { go a.run() })
+}
+
+// stop delivers the records still queued, then ends the worker. It blocks
+// until the hook has seen every queued record.
+func (a *asyncHook) stop() {
+	a.start() // Records may have been queued by a consumer that ran without Start
+	a.stopOnce.Do(func() { close(a.quit) })
+	<-a.done
+}
+
+// run delivers queued records until stop is called and the queue is empty.
+func (a *asyncHook) run() {
+	defer close(a.done)
+	for {
+		select {
+		case c := <-a.queue:
+			a.call(c)
+		case <-a.quit:
+			for {
+				select {
+				case c := <-a.queue:
+					a.call(c)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// call runs the hook on c, recovering from panics. The first panic is
+// reported through the error handler; later ones are only counted.
+// Errors returned by the hook are reported like those of synchronous hooks.
+func (a *asyncHook) call(c *Record) {
+	defer func() {
+		if r := recover(); r != nil {
+			if a.panics.Add(1) == 1 {
+				handleError(NewLoggerErrorWithField(ErrCodeHookExecution,
+					fmt.Sprintf("async hook %s panicked: %v (further panics are counted in hook_panics)", a.entry.name, r),
+					"hook", a.entry.name))
+			}
+		}
+		a.release(c)
+	}()
+	a.entry.run(c)
+}
+
+// WithAsyncHook adds a hook that runs on its own goroutine.
+//
+// Only records at the hook's Levels are queued. Wrap a plain function with
+// HookFunc.
+//
+// The consumer copies each record into a bounded queue and continues, so a
+// slow hook delays only itself. When the queue is full the record is not
+// delivered to the hook (it is still written to the output) and counted in
+// the "hook_dropped" Stats key. A panic in the hook is recovered, counted in
+// "hook_panics" and reported once through the error handler.
+//
+// Close waits until the hook has processed every queued record. The hook
+// runs on a single goroutine, so calls are never concurrent, but unlike
+// WithHook it runs concurrently with the consumer and other hooks.
+//
+// Parameters:
+//   - h: Hook to execute (nil hooks are ignored)
+//   - queueSize: Maximum records waiting for the hook
+//     (<= 0 uses DefaultAsyncHookQueueSize)
+//
+// Returns:
+//   - Option: Configuration function to add the hook
+//
+// Example:
+//
+//	forward := iris.HookFunc(func(rec *iris.Record) {
+//	    if rec.Level >= iris.Error {
+//	        alerts.Send(rec.Msg) // Network call, may take seconds
+//	    }
+//	})
+//	logger, err := iris.New(cfg, iris.WithAsyncHook(forward, 4096))
+func WithAsyncHook(h Hook, queueSize int) Option {
+	return func(o *loggerOptions) {
+		if h == nil {
+			return
+		}
+		entry := newHookEntry(h, len(o.hooks)+1)
+		entry.async = newAsyncHook(entry, queueSize)
+		asyncHooks := make([]*asyncHook, len(o.asyncHooks), len(o.asyncHooks)+1)
+		copy(asyncHooks, o.asyncHooks)
+		o.asyncHooks = append(asyncHooks, entry.async)
+		o.addHook(entry)
+	}
+}
+
+// startAsyncHooks launches the workers of the logger's async hooks.
+func (l *Logger) startAsyncHooks() {
+	for _, a := range l.opts.asyncHooks {
+		a.start()
+	}
+}
+
+// stopAsyncHooks drains and stops the logger's async hooks.
+func (l *Logger) stopAsyncHooks() {
+	for _, a := range l.opts.asyncHooks {
+		a.stop()
+	}
+}
+
+// addAsyncHookStats adds the async hook counters to stats.
+func (l *Logger) addAsyncHookStats(stats map[string]int64) {
+	if len(l.opts.asyncHooks) == 0 {
+		return
+	}
+	var dropped, panics int64
+	for _, a := range l.opts.asyncHooks {
+		dropped += a.dropped.Load()
+		panics += a.panics.Load()
+	}
+	stats["hook_dropped"] = dropped
+	stats["hook_panics"] = panics
+}
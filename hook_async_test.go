@@ -0,0 +1,157 @@
+// hook_async_test.go: Tests for async hooks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncHook_SlowHookDoesNotStallLogging(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var seen []string
+	hook := func(rec *Record) {
+		<-release
+		mu.Lock()
+		seen = append(seen, rec.Msg)
+		mu.Unlock()
+	}
+
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithAsyncHook(HookFunc(hook), 4))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		logger.Info("record")
+		time.Sleep(100 * time.Microsecond) // Let the consumer keep up with the ring
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(out.String(), "\n") < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("output has %d records while the hook is blocked, want %d", strings.Count(out.String(), "\n"), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	dropped := logger.Stats()["hook_dropped"]
+	if dropped < n-5 {
+		t.Errorf("hook_dropped = %d, want at least %d with a 4-slot queue", dropped, n-5)
+	}
+
+	close(release)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if int64(len(seen))+dropped != n {
+		t.Errorf("hook saw %d records and dropped %d, want %d in total", len(seen), dropped, n)
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 0 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 0", got)
+	}
+}
+
+func TestAsyncHook_CloseDeliversQueuedRecords(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	hook := func(rec *Record) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if rec.FieldCount() != 2 {
+			t.Errorf("hook record has %d fields, want 2", rec.FieldCount())
+			return
+		}
+		seen = append(seen, rec.Msg+":"+rec.GetField(1).StringValue())
+	}
+
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithAsyncHook(HookFunc(hook), 64))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	log := logger.With(Str("base", "x"))
+	for i := 0; i < 20; i++ {
+		log.Info("msg", Str("i", string(rune('a'+i))))
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 20 {
+		t.Fatalf("hook saw %d records after Close, want 20", len(seen))
+	}
+	for i, s := range seen {
+		if want := "msg:" + string(rune('a'+i)); s != want {
+			t.Errorf("record %d = %q, want %q", i, s, want)
+		}
+	}
+	if got := logger.Stats()["hook_dropped"]; got != 0 {
+		t.Errorf("hook_dropped = %d, want 0", got)
+	}
+}
+
+func TestAsyncHook_PanicIsolation(t *testing.T) {
+	errs := captureErrors(t)
+	out := &testSyncer{}
+	noop := func(*Record) {}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithAsyncHook(HookFunc(func(*Record) { panic("hook failure") }), 16),
+		WithHook(noop))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		logger.Info("record")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("output has %d records, want 3", got)
+	}
+	if got := logger.Stats()["hook_panics"]; got != 3 {
+		t.Errorf("hook_panics = %d, want 3", got)
+	}
+	reported := errs()
+	if len(reported) != 1 || reported[0].Code != ErrCodeHookExecution {
+		t.Fatalf("reported errors = %v, want one %s", reported, ErrCodeHookExecution)
+	}
+	if !strings.Contains(reported[0].Message, "hook failure") {
+		t.Errorf("report %q does not include the panic value", reported[0].Message)
+	}
+}
+
+func TestAsyncHook_NilAndStats(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithAsyncHook(nil, 0))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	if len(logger.opts.hooks) != 0 || len(logger.opts.asyncHooks) != 0 {
+		t.Error("nil async hook was registered")
+	}
+	if _, ok := logger.Stats()["hook_dropped"]; ok {
+		t.Error("hook_dropped reported without async hooks")
+	}
+	if a := newAsyncHook(newHookEntry(HookFunc(func(*Record) {}), 1), 0); cap(a.queue) != DefaultAsyncHookQueueSize {
+		t.Errorf("default queue size = %d, want %d", cap(a.queue), DefaultAsyncHookQueueSize)
+	}
+}
@@ -0,0 +1,132 @@
+// hook_runtime.go: Hook registration on a running logger
+//
+// WithHook and WithAsyncHook fix the hook set at construction. Observability
+// agents that attach to a running process need to add and remove record
+// listeners later, so the logger also keeps a copy-on-write hook list: the
+// consumer reads it with a single atomic load per record, and AddHook and
+// RemoveHook publish a new list instead of modifying the current one.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// HookID identifies a hook added with Logger.AddHook.
+type HookID uint64
+
+// registeredHook is an entry of the runtime hook list.
+type registeredHook struct {
+	id    HookID
+	entry *hookEntry
+}
+
+// hookRegistry holds the runtime hooks shared by a logger and its clones.
+type hookRegistry struct {
+	mu     sync.Mutex // Serializes AddHook and RemoveHook
+	nextID HookID
+	list   atomic.Pointer[[]registeredHook] // nil or non-empty, never modified in place
+
+	removedErrs atomic.Int64 // Errors of removed hooks, keeping hook_errors monotonic
+}
+
+// run calls every registered hook on rec.
+func (h *hookRegistry) run(rec *Record) {
+	list := h.list.Load()
+	if list == nil {
+		return
+	}
+	for _, rh := range *list {
+		rh.entry.fire(rec)
+	}
+}
+
+// AddHook registers a hook on a running logger and returns its ID for
+// RemoveHook.
+//
+// The hook runs in the consumer thread after the hooks passed to New, with
+// the same contract as WithTypedHook: it must not retain rec and should not
+// block (wrap slow work in a goroutine or queue of its own). It receives
+// records processed after AddHook returns; the hook set is shared by the
+// logger and every logger derived from it with With, Named or WithOptions.
+// Unnamed hooks are reported as "runtime-hook#<id>".
+//
+// Parameters:
+//   - h: Hook function to execute (nil hooks are ignored and return 0)
+//
+// Returns:
+//   - HookID: Registration ID (never 0 for a registered hook)
+//
+// Example:
+//
+//	id := logger.AddHook(iris.HookFunc(func(rec *iris.Record) {
+//	    if rec.Level >= iris.Error {
+//	        agent.Observe(rec.Msg)
+//	    }
+//	}))
+//	defer logger.RemoveHook(id)
+func (l *Logger) AddHook(h Hook) HookID {
+	if h == nil {
+		return 0
+	}
+	reg := l.runtimeHooks
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.nextID++
+	var list []registeredHook
+	if cur := reg.list.Load(); cur != nil {
+		list = make([]registeredHook, len(*cur), len(*cur)+1)
+		copy(list, *cur)
+	}
+	entry := newHookEntry(h, 0)
+	if h.Name() == "" {
+		entry.name = fmt.Sprintf("runtime-hook#%d", reg.nextID)
+	}
+	list = append(list, registeredHook{id: reg.nextID, entry: entry})
+	reg.list.Store(&list)
+	return reg.nextID
+}
+
+// RemoveHook unregisters a hook added with AddHook.
+//
+// A record the consumer was already processing may still reach the hook
+// after RemoveHook returns; later records do not.
+//
+// Parameters:
+//   - id: ID returned by AddHook
+//
+// Returns:
+//   - bool: true if the hook was registered and has been removed
+func (l *Logger) RemoveHook(id HookID) bool {
+	reg := l.runtimeHooks
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	cur := reg.list.Load()
+	if cur == nil {
+		return false
+	}
+	for i, rh := range *cur {
+		if rh.id != id {
+			continue
+		}
+		reg.removedErrs.Add(rh.entry.errs.Load())
+		if len(*cur) == 1 {
+			reg.list.Store(nil)
+			return true
+		}
+		list := make([]registeredHook, 0, len(*cur)-1)
+		list = append(list, (*cur)[:i]...)
+		list = append(list, (*cur)[i+1:]...)
+		reg.list.Store(&list)
+		return true
+	}
+	return false
+}
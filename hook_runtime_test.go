@@ -0,0 +1,114 @@
+// hook_runtime_test.go: Tests for runtime hook registration
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingHook returns a hook that counts records and a reader for the count.
+func countingHook() (Hook, func() int64) {
+	var n atomic.Int64
+	return HookFunc(func(*Record) { n.Add(1) }), n.Load
+}
+
+func TestAddRemoveHook(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	first, firstCount := countingHook()
+	second, secondCount := countingHook()
+
+	logger.Info("before")
+	id1 := logger.AddHook(first)
+	logger.Info("one hook")
+	// Hooks added through a derived logger are shared with its parent
+	id2 := logger.Named("child").AddHook(second)
+	logger.Info("two hooks")
+
+	if id1 == 0 || id2 == 0 || id1 == id2 {
+		t.Fatalf("hook IDs = %d, %d, want distinct non-zero IDs", id1, id2)
+	}
+	if got := firstCount(); got != 2 {
+		t.Errorf("first hook saw %d records, want 2", got)
+	}
+	if got := secondCount(); got != 1 {
+		t.Errorf("second hook saw %d records, want 1", got)
+	}
+
+	if !logger.RemoveHook(id1) {
+		t.Error("RemoveHook of a registered hook returned false")
+	}
+	if logger.RemoveHook(id1) {
+		t.Error("second RemoveHook of the same ID returned true")
+	}
+	logger.With(Str("k", "v")).Info("after remove")
+	if got := firstCount(); got != 2 {
+		t.Errorf("removed hook saw %d records, want 2", got)
+	}
+	if got := secondCount(); got != 2 {
+		t.Errorf("second hook saw %d records, want 2", got)
+	}
+
+	if !logger.RemoveHook(id2) || logger.runtimeHooks.list.Load() != nil {
+		t.Error("removing the last hook did not clear the list")
+	}
+	if id := logger.AddHook(nil); id != 0 {
+		t.Errorf("AddHook(nil) = %d, want 0", id)
+	}
+	if logger.RemoveHook(0) {
+		t.Error("RemoveHook(0) returned true")
+	}
+}
+
+func TestAddHook_ConcurrentWithLogging(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	persistent, persistentCount := countingHook()
+	logger.AddHook(persistent)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				id := logger.AddHook(HookFunc(func(*Record) {}))
+				logger.RemoveHook(id)
+			}
+		}
+	}()
+
+	var logged int64
+	for i := 0; i < 1000; i++ {
+		if logger.Info("record") {
+			logged++
+		}
+	}
+	close(stop)
+	wg.Wait()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Every accepted record reaches the hook registered before logging
+	if got := persistentCount(); got != logged {
+		t.Errorf("persistent hook saw %d records, want %d", got, logged)
+	}
+}
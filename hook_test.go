@@ -0,0 +1,176 @@
+// hook_test.go: Tests for typed hooks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// testHook is a Hook recording the messages it sees.
+type testHook struct {
+	name   string
+	levels []Level
+	err    error
+
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (h *testHook) Name() string    { return h.name }
+func (h *testHook) Levels() []Level { return h.levels }
+
+func (h *testHook) Run(rec *Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.msgs = append(h.msgs, rec.Msg)
+	return h.err
+}
+
+func (h *testHook) seen() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.msgs...)
+}
+
+func newHookTestLogger(t *testing.T, opts ...Option) *Logger {
+	t.Helper()
+	logger, err := New(Config{Level: Debug, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, opts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return logger
+}
+
+func TestTypedHook_Levels(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []Level
+		want   []string
+	}{
+		{"all", nil, []string{"debug", "info", "warn", "error"}},
+		{"errors only", []Level{Error}, []string{"error"}},
+		{"warn and debug", []Level{Warn, Debug}, []string{"debug", "warn"}},
+		{"out of range ignored", []Level{StacktraceDisabled, Info}, []string{"info"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &testHook{name: "levels", levels: tt.levels}
+			logger := newHookTestLogger(t, WithTypedHook(hook))
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+			_ = logger.Close()
+
+			got := hook.seen()
+			if len(got) != len(tt.want) {
+				t.Fatalf("hook saw %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("hook saw %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestTypedHook_CustomLevels(t *testing.T) {
+	resetCustomLevelsForTest(t)
+	const notice = Level(6)
+	if err := RegisterLevel(notice, "notice"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	hook := &testHook{name: "notice", levels: []Level{notice}}
+	logger := newHookTestLogger(t, WithTypedHook(hook))
+	logger.Info("info")
+	logger.Error("error")
+	logger.Write(func(r *Record) {
+		r.Level = notice
+		r.Msg = "notice"
+	})
+	_ = logger.Close()
+
+	// A hook listing only custom levels is filtered, not run for every level
+	if got := hook.seen(); len(got) != 1 || got[0] != "notice" {
+		t.Errorf("hook saw %v, want [notice]", got)
+	}
+}
+
+func TestTypedHook_ErrorsReported(t *testing.T) {
+	errs := captureErrors(t)
+	failure := errors.New("endpoint unreachable")
+	named := &testHook{name: "forwarder", err: failure}
+	unnamed := HookFunc(func(*Record) {})
+	unnamedFailing := &testHook{err: failure}
+
+	logger := newHookTestLogger(t, WithHook(unnamed), WithTypedHook(named), WithTypedHook(unnamedFailing))
+	logger.Info("one")
+	logger.Info("two")
+	_ = logger.Close()
+
+	if got := logger.Stats()["hook_errors"]; got != 4 {
+		t.Errorf("hook_errors = %d, want 4", got)
+	}
+	reported := errs()
+	if len(reported) != 4 {
+		t.Fatalf("reported %d errors, want 4", len(reported))
+	}
+	wantNames := []string{"forwarder", "hook#3", "forwarder", "hook#3"}
+	for i, err := range reported {
+		if err.Code != ErrCodeHookExecution {
+			t.Errorf("error %d code = %s, want %s", i, err.Code, ErrCodeHookExecution)
+		}
+		if err.Context["hook"] != wantNames[i] {
+			t.Errorf("error %d hook = %v, want %s", i, err.Context["hook"], wantNames[i])
+		}
+		if !errors.Is(err, failure) {
+			t.Errorf("error %d does not wrap the hook error: %v", i, err)
+		}
+	}
+}
+
+func TestTypedHook_AsyncAndRuntime(t *testing.T) {
+	errs := captureErrors(t)
+	failure := errors.New("rejected")
+	async := &testHook{name: "async", levels: []Level{Warn}, err: failure}
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithAsyncHook(async, 8))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	runtime := &testHook{err: failure}
+	id := logger.AddHook(runtime)
+	logger.Info("info")
+	logger.Warn("warn")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	logger.RemoveHook(id)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := async.seen(); len(got) != 1 || got[0] != "warn" {
+		t.Errorf("async hook saw %v, want [warn]", got)
+	}
+	// Errors of the removed runtime hook still count
+	if got := logger.Stats()["hook_errors"]; got != 3 {
+		t.Errorf("hook_errors = %d, want 3", got)
+	}
+	names := map[interface{}]int{}
+	for _, err := range errs() {
+		names[err.Context["hook"]]++
+	}
+	if names["async"] != 1 || names["runtime-hook#1"] != 2 {
+		t.Errorf("reported hook names = %v, want async once and runtime-hook#1 twice", names)
+	}
+}
@@ -0,0 +1,151 @@
+// host.go: Cached hostname and local IP fields for Iris logging library
+//
+// Hostname and IP are resolved once on first use and refreshed in the
+// background at a fixed interval, so adding them to every record costs a
+// single atomic load instead of a syscall per log call.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// hostInfoRefreshInterval controls how often cached host information is refreshed.
+const hostInfoRefreshInterval = time.Minute
+
+// hostInfo is an immutable snapshot of the resolved host information.
+type hostInfo struct {
+	hostname   string
+	ip         string
+	resolvedAt int64 // Unix nanoseconds
+}
+
+var (
+	// hostCache holds the current snapshot (nil until first use)
+	hostCache atomic.Pointer[hostInfo]
+
+	// hostRefreshing guards against concurrent background refreshes
+	hostRefreshing atomic.Bool
+)
+
+// Host returns a field with key "host" containing the cached hostname.
+//
+// The hostname is resolved on first use and refreshed in the background
+// every minute. If resolution fails the value is "unknown".
+//
+// Example:
+//
+//	logger.Info("service started", iris.Host())
+func Host() Field {
+	return Str("host", currentHostInfo().hostname)
+}
+
+// LocalIP returns a field with key "ip" containing the cached local IP address.
+//
+// The address is the first non-loopback unicast address of an interface that
+// is up, preferring IPv4. It is resolved on first use and refreshed in the
+// background every minute. If no address is found the value is "unknown".
+//
+// Example:
+//
+//	logger.Info("service started", iris.Host(), iris.LocalIP())
+func LocalIP() Field {
+	return Str("ip", currentHostInfo().ip)
+}
+
+// WithHostInfo adds the cached "host" and "ip" fields to every log record.
+//
+// This replaces the common pattern of calling os.Hostname() at every logger
+// construction site. Values are read from the shared cache at log time, so
+// refreshed values are picked up without recreating loggers.
+//
+// Returns:
+//   - Option: Configuration function to enable host information fields
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{}, iris.WithHostInfo())
+func WithHostInfo() Option {
+	return func(o *loggerOptions) {
+		o.addProvider(fieldProvider{min: StacktraceDisabled, fn: Host})
+		o.addProvider(fieldProvider{min: StacktraceDisabled, fn: LocalIP})
+	}
+}
+
+// currentHostInfo returns the cached host information, resolving it on first
+// use and scheduling a background refresh once it becomes stale.
+func currentHostInfo() *hostInfo {
+	info := hostCache.Load()
+	if info == nil {
+		hostCache.CompareAndSwap(nil, resolveHostInfo())
+		return hostCache.Load()
+	}
+
+	if timecache.CachedTimeNano()-info.resolvedAt > int64(hostInfoRefreshInterval) &&
+		hostRefreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer hostRefreshing.Store(false)
+			hostCache.Store(resolveHostInfo())
+		}()
+	}
+	return info
+}
+
+// resolveHostInfo queries the operating system for hostname and local IP.
+func resolveHostInfo() *hostInfo {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return &hostInfo{
+		hostname:   hostname,
+		ip:         resolveLocalIP(),
+		resolvedAt: timecache.CachedTimeNano(),
+	}
+}
+
+// resolveLocalIP returns the first non-loopback address of an interface
+// that is up, preferring IPv4 over IPv6.
+func resolveLocalIP() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "unknown"
+	}
+
+	var fallback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				return ip4.String()
+			}
+			if fallback == "" {
+				fallback = ipNet.IP.String()
+			}
+		}
+	}
+
+	if fallback == "" {
+		return "unknown"
+	}
+	return fallback
+}
@@ -0,0 +1,89 @@
+// host_test.go: Tests for cached hostname and local IP fields
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHost_Field(t *testing.T) {
+	field := Host()
+	if field.Key() != "host" || !field.IsString() {
+		t.Fatalf("unexpected host field: %+v", field)
+	}
+
+	expected, err := os.Hostname()
+	if err == nil && expected != "" && field.StringValue() != expected {
+		t.Errorf("Host() = %q, want %q", field.StringValue(), expected)
+	}
+}
+
+func TestLocalIP_Field(t *testing.T) {
+	field := LocalIP()
+	if field.Key() != "ip" || field.StringValue() == "" {
+		t.Fatalf("unexpected ip field: %+v", field)
+	}
+	if strings.HasPrefix(field.StringValue(), "127.") {
+		t.Errorf("LocalIP() should not return a loopback address, got %q", field.StringValue())
+	}
+}
+
+func TestHostInfo_Cached(t *testing.T) {
+	first := currentHostInfo()
+	second := currentHostInfo()
+	if first != second {
+		t.Error("host info should be served from the cache")
+	}
+}
+
+func TestHostInfo_RefreshWhenStale(t *testing.T) {
+	stale := &hostInfo{hostname: "stale-host", ip: "10.0.0.1", resolvedAt: 0}
+	previous := hostCache.Load()
+	hostCache.Store(stale)
+	defer hostCache.Store(previous)
+
+	// The stale value is served while the refresh runs in the background
+	if got := Host().StringValue(); got != "stale-host" {
+		t.Errorf("expected stale value during refresh, got %q", got)
+	}
+
+	for i := 0; i < 1000 && hostCache.Load() == stale; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if hostCache.Load() == stale {
+		t.Error("expected host info to be refreshed")
+	}
+}
+
+func TestWithHostInfo_AddsFields(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:   Info,
+		Output:  buf,
+		Encoder: NewJSONEncoder(),
+	}, WithHostInfo())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("with host")
+	logger.With(Str("k", "v")).Info("with host and fields")
+	_ = logger.Sync()
+
+	output := buf.String()
+	if strings.Count(output, `"host":`) != 2 || strings.Count(output, `"ip":`) != 2 {
+		t.Errorf("expected host and ip on every record, got %q", output)
+	}
+	if !strings.Contains(output, `"k":"v"`) {
+		t.Errorf("expected base fields to be preserved, got %q", output)
+	}
+}
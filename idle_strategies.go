@@ -12,6 +12,8 @@
 package iris
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/agilira/iris/internal/zephyroslite"
@@ -158,3 +160,33 @@ var EfficientStrategy = NewSleepingIdleStrategy(time.Millisecond, 0)
 // Spins briefly then sleeps for 1ms.
 // Equivalent to NewSleepingIdleStrategy(time.Millisecond, 1000).
 var HybridStrategy = NewSleepingIdleStrategy(time.Millisecond, 1000)
+
+// ParseIdleStrategy returns a new idle strategy for a configuration name.
+//
+// Accepted names (case-insensitive) are the ones understood by the JSON and
+// environment config loaders: spinning, sleeping, yielding, channel,
+// progressive (alias balanced), efficient and hybrid.
+//
+// Returns:
+//   - IdleStrategy: Strategy with the loader's default parameters
+//   - error: Non-nil for unknown names
+func ParseIdleStrategy(name string) (IdleStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "spinning":
+		return NewSpinningIdleStrategy(), nil
+	case "sleeping":
+		return NewSleepingIdleStrategy(1*time.Millisecond, 0), nil // Default 1ms sleep, no spin
+	case "yielding":
+		return NewYieldingIdleStrategy(1000), nil // Default 1000 spins before yield
+	case "channel":
+		return NewChannelIdleStrategy(100 * time.Millisecond), nil // Default 100ms timeout
+	case "progressive", "balanced":
+		return NewProgressiveIdleStrategy(), nil
+	case "efficient":
+		return EfficientStrategy, nil
+	case "hybrid":
+		return HybridStrategy, nil
+	default:
+		return nil, fmt.Errorf("unknown idle strategy %q", name)
+	}
+}
@@ -36,7 +36,7 @@ func TestIdleStrategies_Basic(t *testing.T) {
 				atomic.AddInt64(&processed, 1)
 			}
 
-			ring, err := newRing(64, 16, SingleRing, 1, zephyroslite.DropOnFull, test.strategy, processor)
+			ring, err := newRing(64, 16, SingleRing, 1, zephyroslite.DropOnFull, test.strategy, false, processor)
 			if err != nil {
 				t.Fatalf("Failed to create ring with %s strategy: %v", test.name, err)
 			}
@@ -89,7 +89,7 @@ func TestIdleStrategies_Predefined(t *testing.T) {
 				atomic.AddInt64(&processed, 1)
 			}
 
-			ring, err := newRing(64, 16, SingleRing, 1, zephyroslite.DropOnFull, test.strategy, processor)
+			ring, err := newRing(64, 16, SingleRing, 1, zephyroslite.DropOnFull, test.strategy, false, processor)
 			if err != nil {
 				t.Fatalf("Failed to create ring with %s: %v", test.name, err)
 			}
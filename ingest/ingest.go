@@ -0,0 +1,374 @@
+// ingest.go: Conversion of zap and zerolog JSON lines into Iris records
+//
+// This package parses the JSON lines written by zap's and zerolog's JSON
+// encoders into Iris records, mapping their level, timestamp, message,
+// logger name, caller and stack trace keys to the record and keeping every
+// other key as a field in its original order. Services that still log with
+// those libraries can then be funneled through Iris outputs, sinks and the
+// binary format next to the ones that already use Iris.
+//
+// Usage:
+//
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewBinaryEncoder()})
+//	if err != nil {
+//	    return err
+//	}
+//	defer logger.Close()
+//
+//	n, err := ingest.Copy(logger, legacyLogs, ingest.Auto)
+//
+// The original timestamps are preserved with Record.SetTime, so the
+// converted records carry the time at which they were first logged.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// Format is the library that wrote the lines being converted.
+type Format int
+
+const (
+	// Auto detects the format of every line from its keys: "msg" or "ts"
+	// mean zap, "message" or "time" mean zerolog.
+	Auto Format = iota
+
+	// Zap reads the keys of zap's production encoder (level, ts, logger,
+	// caller, msg, stacktrace) and of its development encoder (L, T, N, C,
+	// M, S). The function key is kept as a field.
+	Zap
+
+	// Zerolog reads zerolog's default keys (level, time, caller, message,
+	// stack).
+	Zerolog
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case Auto:
+		return "auto"
+	case Zap:
+		return "zap"
+	case Zerolog:
+		return "zerolog"
+	default:
+		return "unknown"
+	}
+}
+
+// MaxLineSize is the longest line Copy accepts.
+const MaxLineSize = 1 << 20
+
+// Entry is a parsed log line.
+type Entry struct {
+	Level   iris.Level
+	Time    time.Time // Zero if the line had no timestamp Entry could parse
+	Message string
+	Logger  string
+	Caller  string
+	Stack   string
+	Fields  []iris.Field // Every other key, in line order
+}
+
+// keys are the names a format gives to the record attributes.
+type keys struct {
+	level, time, message, logger, caller, stack []string
+}
+
+var (
+	zapKeys = keys{
+		level:   []string{"level", "L"},
+		time:    []string{"ts", "T"},
+		message: []string{"msg", "M"},
+		logger:  []string{"logger", "N"},
+		caller:  []string{"caller", "C"},
+		stack:   []string{"stacktrace", "S"},
+	}
+	zerologKeys = keys{
+		level:   []string{"level"},
+		time:    []string{"time"},
+		message: []string{"message"},
+		caller:  []string{"caller"},
+		stack:   []string{"stack"},
+	}
+)
+
+// member is a key of the line with its undecoded value.
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// Parse converts one zap or zerolog JSON line into an Entry.
+//
+// Level names are matched like iris.ParseLevel, so capitalized zap levels
+// and zerolog's "trace" level are recognized; a line without a level is
+// Info, and an unknown level is Info with the original value kept as a
+// field. Timestamps may be RFC3339 or ISO8601 strings or Unix
+// epoch numbers in seconds (zap's default), milliseconds, microseconds or
+// nanoseconds, told apart by their magnitude; a timestamp that cannot be
+// parsed is kept as a field. Nested objects and arrays become
+// iris.RawJSON fields.
+//
+// Parameters:
+//   - line: One JSON object, as written by zap or zerolog
+//   - format: The library that wrote it, or Auto
+//
+// Returns:
+//   - Entry: The converted line
+//   - error: Non-nil if line is not a JSON object
+func Parse(line []byte, format Format) (Entry, error) {
+	members, err := decodeMembers(line)
+	if err != nil {
+		return Entry{}, err
+	}
+	if format == Auto {
+		format = detect(members)
+	}
+	k := zerologKeys
+	if format == Zap {
+		k = zapKeys
+	}
+
+	entry := Entry{Level: iris.Info}
+	for _, m := range members {
+		var s string
+		isString := json.Unmarshal(m.value, &s) == nil
+		switch {
+		case isString && contains(k.message, m.key):
+			entry.Message = s
+		case isString && contains(k.logger, m.key):
+			entry.Logger = s
+		case isString && contains(k.caller, m.key):
+			entry.Caller = s
+		case isString && contains(k.stack, m.key):
+			entry.Stack = s
+		case isString && contains(k.level, m.key):
+			level, err := iris.ParseLevel(s)
+			if err != nil {
+				entry.Fields = append(entry.Fields, iris.Str(m.key, s))
+				continue
+			}
+			entry.Level = level
+		case contains(k.time, m.key):
+			t, ok := parseTime(m.value)
+			if !ok {
+				entry.Fields = append(entry.Fields, field(m))
+				continue
+			}
+			entry.Time = t
+		default:
+			entry.Fields = append(entry.Fields, field(m))
+		}
+	}
+	return entry, nil
+}
+
+// Fill writes the entry into rec, for use with Logger.Write. Fields beyond
+// the capacity of a record are counted by Record.TruncatedFields.
+//
+// Example:
+//
+//	entry, err := ingest.Parse(line, ingest.Zap)
+//	if err == nil {
+//	    logger.Write(entry.Fill)
+//	}
+func (e *Entry) Fill(rec *iris.Record) {
+	rec.Level = e.Level
+	rec.Msg = e.Message
+	rec.Logger = e.Logger
+	rec.Caller = e.Caller
+	rec.Stack = e.Stack
+	rec.SetTime(e.Time)
+	for _, f := range e.Fields {
+		rec.AddField(f)
+	}
+}
+
+// Record returns a new record holding the entry, for use with encoders
+// directly. Encoders write the time they are given, so pass them
+// Record.Time (the zero time if the line had none).
+func (e *Entry) Record() *iris.Record {
+	rec := iris.NewRecord(e.Level, e.Message)
+	e.Fill(rec)
+	return rec
+}
+
+// Copy reads JSON lines from src until EOF and writes each of them to dst
+// with its original timestamp. Empty lines are skipped.
+//
+// Copy stops at the first line that is not a JSON object or that is longer
+// than MaxLineSize, returning an error with its line number. Records the
+// logger drops under backpressure are not errors; they are counted in
+// Logger.Stats like any other dropped record.
+//
+// Parameters:
+//   - dst: Logger the records are written to
+//   - src: zap or zerolog output, one JSON object per line
+//   - format: The library that wrote src, or Auto
+//
+// Returns:
+//   - int: Number of records accepted by dst
+//   - error: Non-nil if a line could not be converted or src failed
+func Copy(dst *iris.Logger, src io.Reader, format Format) (int, error) {
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	written, lineNo := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := Parse(line, format)
+		if err != nil {
+			return written, fmt.Errorf("ingest: line %d: %w", lineNo, err)
+		}
+		if dst.Write(entry.Fill) {
+			written++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return written, fmt.Errorf("ingest: line %d: %w", lineNo+1, err)
+	}
+	return written, nil
+}
+
+// decodeMembers splits a JSON object into its members, in order.
+func decodeMembers(line []byte) ([]member, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	var members []member
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var m member
+		m.key = tok.(string) // Object keys are always strings
+		if err := dec.Decode(&m.value); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("trailing data after JSON object")
+	}
+	return members, nil
+}
+
+// detect guesses the format of a line from its keys.
+func detect(members []member) Format {
+	for _, m := range members {
+		switch m.key {
+		case "msg", "ts", "M", "T":
+			return Zap
+		case "message", "time":
+			return Zerolog
+		}
+	}
+	return Zerolog
+}
+
+// contains reports whether key is one of names.
+func contains(names []string, key string) bool {
+	for _, n := range names {
+		if n == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ISO8601 layout of zap's ISO8601TimeEncoder, whose zone has no colon.
+const iso8601 = "2006-01-02T15:04:05.000Z0700"
+
+// parseTime decodes a timestamp string or epoch number.
+func parseTime(value json.RawMessage) (time.Time, bool) {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		for _, layout := range []string{time.RFC3339Nano, iso8601} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseFloat(string(value), 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) {
+		return time.Time{}, false
+	}
+	// Epoch unit by magnitude: seconds until the year 5138, then
+	// milliseconds, microseconds and nanoseconds
+	switch {
+	case n < 1e11:
+		sec, frac := math.Modf(n)
+		// A float64 holds current epoch seconds to about a microsecond
+		return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3), true
+	case n < 1e14:
+		return time.UnixMilli(int64(n)), true
+	case n < 1e17:
+		return time.UnixMicro(int64(n)), true
+	default:
+		i, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(0, i), true
+	}
+}
+
+// field converts a member into the field of the closest type.
+func field(m member) iris.Field {
+	switch v := m.value; {
+	case len(v) > 0 && v[0] == '"':
+		var s string
+		_ = json.Unmarshal(v, &s) // Valid by decodeMembers
+		return iris.Str(m.key, s)
+	case string(v) == "true" || string(v) == "false":
+		return iris.Bool(m.key, string(v) == "true")
+	case len(v) > 0 && (v[0] == '-' || v[0] >= '0' && v[0] <= '9'):
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return iris.Int64(m.key, i)
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return iris.Uint64(m.key, u)
+		}
+		if !strings.ContainsAny(string(v), ".eE") {
+			return iris.RawJSON(m.key, v) // Integer beyond 64 bits
+		}
+		f, _ := strconv.ParseFloat(string(v), 64)
+		return iris.Float64(m.key, f)
+	default:
+		// Objects, arrays and null, compacted since the line may be indented
+		var buf bytes.Buffer
+		_ = json.Compact(&buf, v)
+		return iris.RawJSON(m.key, buf.Bytes())
+	}
+}
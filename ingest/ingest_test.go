@@ -0,0 +1,188 @@
+// ingest_test.go: Tests for the zap and zerolog line converter
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package ingest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// lineSyncer collects the encoded records.
+type lineSyncer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *lineSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *lineSyncer) Sync() error { return nil }
+
+func (s *lineSyncer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		format Format
+		want   Entry
+		fields map[string]any // Expected Field.Value by key
+	}{
+		{
+			name:   "ZapProduction",
+			line:   `{"level":"error","ts":1700000000.123456,"logger":"api.db","caller":"db/pool.go:42","msg":"query failed","attempt":3,"retry":true,"stacktrace":"main.main\n\tmain.go:10"}`,
+			format: Zap,
+			want:   Entry{Level: iris.Error, Time: time.Unix(1700000000, 123456000), Message: "query failed", Logger: "api.db", Caller: "db/pool.go:42", Stack: "main.main\n\tmain.go:10"},
+			fields: map[string]any{"attempt": int64(3), "retry": true},
+		},
+		{
+			name:   "ZapDevelopment",
+			line:   `{"L":"WARN","T":"2023-11-14T22:13:20.500+0100","N":"worker","C":"job.go:7","M":"slow job","elapsed":1.5}`,
+			format: Auto,
+			want:   Entry{Level: iris.Warn, Time: time.Date(2023, 11, 14, 21, 13, 20, 5e8, time.UTC), Message: "slow job", Logger: "worker", Caller: "job.go:7"},
+			fields: map[string]any{"elapsed": 1.5},
+		},
+		{
+			name:   "Zerolog",
+			line:   `{"level":"trace","service":"billing","time":"2023-11-14T22:13:20Z","caller":"/src/billing.go:88","message":"charged","error":"card declined"}`,
+			format: Auto,
+			want:   Entry{Level: iris.Trace, Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), Message: "charged", Caller: "/src/billing.go:88"},
+			fields: map[string]any{"service": "billing", "error": "card declined"},
+		},
+		{
+			name:   "ZerologUnixMillis",
+			line:   `{"level":"info","time":1700000000123,"message":"tick"}`,
+			format: Zerolog,
+			want:   Entry{Level: iris.Info, Time: time.UnixMilli(1700000000123), Message: "tick"},
+		},
+		{
+			name:   "EpochNanos",
+			line:   `{"time":1700000000123456789,"message":"tick"}`,
+			format: Zerolog,
+			want:   Entry{Level: iris.Info, Time: time.Unix(0, 1700000000123456789), Message: "tick"},
+		},
+		{
+			name:   "UnknownLevelAndTime",
+			line:   `{"level":"notice","ts":"yesterday","msg":"odd","big":18446744073709551616,"id":18446744073709551615}`,
+			format: Auto,
+			want:   Entry{Level: iris.Info, Message: "odd"},
+			fields: map[string]any{"level": "notice", "ts": "yesterday", "id": uint64(18446744073709551615)},
+		},
+		{
+			name:   "ForeignKeysAreFields",
+			line:   `{"level":"info","ts":1700000000,"msg":"zap line","message":"not the message"}`,
+			format: Zap,
+			want:   Entry{Level: iris.Info, Time: time.Unix(1700000000, 0), Message: "zap line"},
+			fields: map[string]any{"message": "not the message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.line), tt.format)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got.Level != tt.want.Level || !got.Time.Equal(tt.want.Time) || got.Message != tt.want.Message ||
+				got.Logger != tt.want.Logger || got.Caller != tt.want.Caller || got.Stack != tt.want.Stack {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+			values := make(map[string]any, len(got.Fields))
+			for _, f := range got.Fields {
+				values[f.K] = f.Value()
+			}
+			for k, want := range tt.fields {
+				if values[k] != want {
+					t.Errorf("field %s = %#v, want %#v", k, values[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, line := range []string{``, `[1,2]`, `"msg"`, `{"msg":"a"`, `{"msg":"a"} {}`, `panic: boom`} {
+		if _, err := Parse([]byte(line), Auto); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+func TestEntry_Record(t *testing.T) {
+	entry, err := Parse([]byte(`{"level":"warn","ts":1700000000,"msg":"nested","req":{"path":"/a", "ids":[1,2]},"none":null}`), Zap)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var buf bytes.Buffer
+	rec := entry.Record()
+	iris.NewJSONEncoder().Encode(rec, rec.Time(), &buf)
+	for _, want := range []string{`"level":"warn"`, `"ts":"2023-11-14T22:13:20Z"`, `"req":{"path":"/a","ids":[1,2]}`, `"none":null`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output %s does not contain %s", buf.String(), want)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	out := &lineSyncer{}
+	logger, err := iris.New(iris.Config{Level: iris.Debug, Output: out, Encoder: iris.NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	input := strings.Join([]string{
+		`{"level":"info","ts":1700000000,"logger":"api","msg":"from zap"}`,
+		``,
+		`{"level":"debug","time":"2023-11-14T22:13:21Z","message":"from zerolog"}`,
+		`not json`,
+		`{"level":"info","message":"never read"}`,
+	}, "\n")
+	n, err := Copy(logger, strings.NewReader(input), Auto)
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Copy error = %v, want one for line 4", err)
+	}
+	if n != 2 {
+		t.Errorf("Copy wrote %d records, want 2", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `"ts":"2023-11-14T22:13:20Z"`) || !strings.Contains(lines[0], `"logger":"api"`) {
+		t.Errorf("zap line not converted: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"level":"debug"`) || !strings.Contains(lines[1], `"ts":"2023-11-14T22:13:21Z"`) {
+		t.Errorf("zerolog line not converted: %s", lines[1])
+	}
+}
+
+func TestCopy_LineTooLong(t *testing.T) {
+	logger, err := iris.New(iris.Config{Output: &lineSyncer{}, Encoder: iris.NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	long := `{"msg":"` + strings.Repeat("x", MaxLineSize) + `"}`
+	if _, err := Copy(logger, strings.NewReader(long), Zap); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Copy error = %v, want one for line 1", err)
+	}
+}
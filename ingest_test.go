@@ -0,0 +1,100 @@
+// ingest_test.go: Tests for record timestamp overrides
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordTimeOverride(t *testing.T) {
+	historical := time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600))
+	const want = `"ts":"2020-01-02T03:04:05.000000006+01:00"`
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		log      func(l *Logger)
+		override bool
+		ingested bool
+	}{
+		{"IngestTime field", nil, func(l *Logger) {
+			l.Info("event", Str("k", "v"), IngestTime(historical))
+		}, true, false},
+		{"IngestTime base field", nil, func(l *Logger) {
+			l.With(IngestTime(historical)).Warn("event")
+		}, true, false},
+		{"Record.SetTime", nil, func(l *Logger) {
+			l.Write(func(r *Record) {
+				r.Level = Info
+				r.Msg = "event"
+				r.SetTime(historical)
+			})
+		}, true, false},
+		{"ingestion field", []Option{WithIngestionTime("ingested_at")}, func(l *Logger) {
+			l.Info("event", IngestTime(historical))
+		}, true, true},
+		{"ingestion field with Write", []Option{WithIngestionTime("ingested_at")}, func(l *Logger) {
+			l.Write(func(r *Record) {
+				r.Msg = "event"
+				r.SetTime(historical)
+			})
+		}, true, true},
+		{"ingestion field with WriteCtx", []Option{WithIngestionTime("ingested_at")}, func(l *Logger) {
+			_ = l.WriteCtx(context.Background(), func(r *Record) {
+				r.Msg = "event"
+				r.SetTime(historical)
+			})
+		}, true, true},
+		{"live record", []Option{WithIngestionTime("ingested_at")}, func(l *Logger) {
+			l.Info("event", Str("k", "v"))
+		}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &testSyncer{}
+			logger, err := New(Config{Level: Debug, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, tt.opts...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			tt.log(logger)
+			_ = logger.Close()
+
+			line := out.String()
+			if !strings.Contains(line, `"msg":"event"`) {
+				t.Fatalf("record not written: %q", line)
+			}
+			if got := strings.Contains(line, want); got != tt.override {
+				t.Errorf("historical timestamp = %v, want %v: %s", got, tt.override, line)
+			}
+			if got := strings.Contains(line, `"ingested_at":`); got != tt.ingested {
+				t.Errorf("ingestion field = %v, want %v: %s", got, tt.ingested, line)
+			}
+			if strings.Contains(line, `"":`) {
+				t.Errorf("IngestTime marker encoded as a field: %s", line)
+			}
+		})
+	}
+}
+
+func TestRecordAddField_IngestTime(t *testing.T) {
+	historical := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rec := NewRecord(Info, "event")
+	if !rec.Time().IsZero() {
+		t.Fatalf("new record Time() = %v, want zero", rec.Time())
+	}
+	rec.AddField(IngestTime(historical))
+	if !rec.Time().Equal(historical) || rec.FieldCount() != 0 {
+		t.Errorf("Time() = %v with %d fields, want %v and no fields", rec.Time(), rec.FieldCount(), historical)
+	}
+	rec.Reset()
+	if !rec.Time().IsZero() {
+		t.Errorf("Time() = %v after Reset, want zero", rec.Time())
+	}
+}
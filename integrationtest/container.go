@@ -0,0 +1,73 @@
+// container.go: Container plumbing shared by the sinks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// startTimeout bounds the start of a container, image pull included.
+const startTimeout = 5 * time.Minute
+
+// startContainer starts a container for t, skipping t when no Docker daemon
+// is available, and terminates it when t ends.
+func startContainer(t *testing.T, img string, opts ...testcontainers.ContainerCustomizer) *testcontainers.DockerContainer {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	ctr, err := testcontainers.Run(ctx, img, opts...)
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatalf("starting %s failed: %v", img, err)
+	}
+	return ctr
+}
+
+// endpoint returns the host address of a container port.
+func endpoint(t *testing.T, ctr testcontainers.Container, port, proto string) string {
+	t.Helper()
+	addr, err := ctr.PortEndpoint(context.Background(), nat.Port(port), proto)
+	if err != nil {
+		t.Fatalf("mapping port %s failed: %v", port, err)
+	}
+	return addr
+}
+
+// execOutput runs cmd in ctr and returns what it wrote to stdout and
+// stderr, and its exit code.
+func execOutput(ctx context.Context, ctr testcontainers.Container, cmd ...string) (stdout, stderr string, code int, err error) {
+	code, r, err := ctr.Exec(ctx, cmd)
+	if err != nil {
+		return "", "", code, fmt.Errorf("exec %s: %w", cmd[0], err)
+	}
+	var out, errOut bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &errOut, r); err != nil {
+		return "", "", code, fmt.Errorf("exec %s: reading output: %w", cmd[0], err)
+	}
+	return out.String(), errOut.String(), code, nil
+}
+
+// lines splits output into its non-empty lines.
+func lines(output string) []string {
+	var out []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
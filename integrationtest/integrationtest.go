@@ -0,0 +1,311 @@
+// integrationtest.go: End-to-end delivery harness for Iris writers
+//
+// Unit tests of a writer usually stop at a fake server. This package runs
+// the real thing: StartLoki, StartKafka and StartSyslog start the sink in a
+// container (with testcontainers), and Verify logs a numbered run of
+// records through an Iris logger writing to the writer under test, then
+// reads them back from the sink and reports the records that were lost,
+// duplicated or corrupted on the way. In-tree and third-party writers share
+// the same harness and the same definition of "delivered".
+//
+// Usage:
+//
+//	func TestLokiWriter(t *testing.T) {
+//	    loki := integrationtest.StartLoki(t, `{job="iris"}`)
+//	    integrationtest.Verify(t, loki, func(t *testing.T, endpoint string) iris.WriteSyncer {
+//	        w, err := iris.NewLokiWriter(iris.LokiConfig{URL: endpoint, Labels: map[string]string{"job": "iris"}})
+//	        if err != nil {
+//	            t.Fatal(err)
+//	        }
+//	        return w
+//	    }, integrationtest.Options{})
+//	}
+//
+// The Start functions skip the test when no Docker daemon is available, so
+// these tests can live next to ordinary ones. This is a separate module so
+// that the Iris core does not depend on testcontainers.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// Defaults of Options.
+const (
+	DefaultRecords = 100
+	DefaultTimeout = 30 * time.Second
+)
+
+// pollInterval is how often Verify reads the target while waiting.
+const pollInterval = 500 * time.Millisecond
+
+// recordMsg is the message of every record Verify logs.
+const recordMsg = "integrationtest record"
+
+// recordText is a field value that needs escaping in most formats.
+const recordText = "quote \" backslash \\ newline \n tab \t unicode é 日本"
+
+// Target is a log sink a writer delivers to.
+type Target interface {
+	// Endpoint returns the address handed to the writer under test.
+	Endpoint() string
+
+	// Messages returns every message the sink has received so far, one
+	// per record, possibly wrapped in the sink's own framing.
+	Messages(ctx context.Context) ([]string, error)
+}
+
+// NewWriter creates the writer under test for the endpoint of a target.
+type NewWriter func(t *testing.T, endpoint string) iris.WriteSyncer
+
+// Options configures Verify.
+type Options struct {
+	// Records is the number of records logged (DefaultRecords if zero).
+	Records int
+
+	// Timeout bounds the wait for the records once the logger is closed
+	// (DefaultTimeout if zero).
+	Timeout time.Duration
+
+	// Encoder encodes the records (a JSON encoder if nil). Whatever it
+	// is, each record must reach the sink as a JSON object with "msg",
+	// "level" and the record fields at the top level.
+	Encoder iris.Encoder
+
+	// AllowDuplicates accepts records delivered more than once, for
+	// writers that guarantee at-least-once delivery.
+	AllowDuplicates bool
+}
+
+// Result is what Verify found in the sink.
+type Result struct {
+	Delivered  int           // Records found, counted once
+	Missing    []int         // Sequence numbers not found
+	Duplicated []int         // Sequence numbers found more than once
+	Corrupt    []string      // Messages of the run that did not decode to the logged record
+	Elapsed    time.Duration // From Close to the last read of the sink
+	ReadErr    error         // Error of the last read of the sink, if it failed
+}
+
+// OK reports whether every record was delivered intact, once.
+func (r Result) OK() bool {
+	return len(r.Missing) == 0 && len(r.Duplicated) == 0 && len(r.Corrupt) == 0
+}
+
+// Verify logs opts.Records records through a logger writing to the writer
+// newWriter returns for target, closes the logger (and the writer, if it is
+// an io.Closer), then reads the sink until every record has arrived or
+// opts.Timeout passes. Lost, duplicated and corrupted records are reported
+// with t.Errorf.
+//
+// The records carry a run identifier, unique to the call, so that several
+// runs can share a sink, and a sequence number. Their levels cycle through
+// Info, Warn and Error, and each has a field with characters that must be
+// escaped.
+//
+// Parameters:
+//   - t: Test the failures are reported to
+//   - target: Sink the writer delivers to
+//   - newWriter: Creates the writer under test
+//   - opts: Run size, deadline and encoder
+//
+// Returns:
+//   - Result: What was found in the sink
+func Verify(t *testing.T, target Target, newWriter NewWriter, opts Options) Result {
+	t.Helper()
+	opts = opts.withDefaults()
+	runID := fmt.Sprintf("%s-%d", runName(t.Name()), time.Now().UnixNano())
+
+	w := newWriter(t, target.Endpoint())
+	logger, err := iris.New(iris.Config{Level: iris.Debug, Output: w, Encoder: opts.Encoder})
+	if err != nil {
+		t.Fatalf("iris.New failed: %v", err)
+	}
+	logRun(logger, runID, opts.Records)
+	if err := logger.Close(); err != nil {
+		t.Errorf("Logger.Close failed: %v", err)
+	}
+	if c, ok := w.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			t.Errorf("writer Close failed: %v", err)
+		}
+	}
+
+	res := await(context.Background(), target, runID, opts)
+	report(t, res, opts)
+	return res
+}
+
+// withDefaults fills the zero fields of o.
+func (o Options) withDefaults() Options {
+	if o.Records <= 0 {
+		o.Records = DefaultRecords
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Encoder == nil {
+		o.Encoder = iris.NewJSONEncoder()
+	}
+	return o
+}
+
+// logRun logs the records of a run. Every record is retried until the ring
+// accepts it, so that a slow writer cannot make the run lose records before
+// they reach it.
+func logRun(logger *iris.Logger, runID string, records int) {
+	levels := [...]func(string, ...iris.Field) bool{logger.Info, logger.Warn, logger.Error}
+	for seq := 0; seq < records; seq++ {
+		log := levels[seq%len(levels)]
+		for !log(recordMsg, iris.Str("run", runID), iris.Int("seq", seq), iris.Str("text", recordText)) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// await reads target until every record of the run has arrived or the
+// deadline passes, and returns the last reading.
+func await(ctx context.Context, target Target, runID string, opts Options) Result {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	res := check(nil, runID, opts.Records)
+	for {
+		messages, err := target.Messages(ctx)
+		if err == nil {
+			res = check(messages, runID, opts.Records)
+		}
+		res.ReadErr = err
+		res.Elapsed = time.Since(start)
+		if err == nil && len(res.Missing) == 0 {
+			return res
+		}
+		select {
+		case <-ctx.Done():
+			return res
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// runName turns a test name into the prefix of a run identifier, keeping
+// the characters that need no quoting in a sink query.
+func runName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '.'
+		}
+	}, name)
+}
+
+// check matches the messages of a sink against the records of a run.
+func check(messages []string, runID string, records int) Result {
+	var res Result
+	seen := make([]int, records)
+	for _, m := range messages {
+		if !strings.Contains(m, runID) {
+			continue // Another run, or the sink's own messages
+		}
+		seq, ok := decode(m, runID, records)
+		if !ok {
+			res.Corrupt = append(res.Corrupt, m)
+			continue
+		}
+		seen[seq]++
+	}
+	for seq, n := range seen {
+		switch {
+		case n == 0:
+			res.Missing = append(res.Missing, seq)
+		case n > 1:
+			res.Duplicated = append(res.Duplicated, seq)
+		}
+		if n > 0 {
+			res.Delivered++
+		}
+	}
+	return res
+}
+
+// levelNames are the level names of the records, by sequence number modulo
+// their count.
+var levelNames = [...]string{"info", "warn", "error"}
+
+// decode extracts the record from a message, which may be wrapped in the
+// sink's framing (a syslog header, for instance), and reports its sequence
+// number if it is the record logged with that number.
+func decode(message, runID string, records int) (int, bool) {
+	start, end := strings.IndexByte(message, '{'), strings.LastIndexByte(message, '}')
+	if start < 0 || end < start {
+		return 0, false
+	}
+	var rec struct {
+		Msg   string   `json:"msg"`
+		Level string   `json:"level"`
+		Run   string   `json:"run"`
+		Seq   *float64 `json:"seq"`
+		Text  string   `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(message[start:end+1]), &rec); err != nil || rec.Seq == nil {
+		return 0, false
+	}
+	seq := int(*rec.Seq)
+	if float64(seq) != *rec.Seq || seq < 0 || seq >= records {
+		return 0, false
+	}
+	ok := rec.Run == runID && rec.Msg == recordMsg && rec.Text == recordText &&
+		strings.EqualFold(rec.Level, levelNames[seq%len(levelNames)])
+	return seq, ok
+}
+
+// report turns the problems of res into test failures.
+func report(t *testing.T, res Result, opts Options) {
+	t.Helper()
+	if res.ReadErr != nil {
+		t.Errorf("reading the sink failed: %v", res.ReadErr)
+	}
+	if len(res.Missing) > 0 {
+		t.Errorf("%d of %d records not delivered within %v (sequence numbers %s)",
+			len(res.Missing), opts.Records, opts.Timeout, summarize(res.Missing))
+	}
+	if len(res.Duplicated) > 0 && !opts.AllowDuplicates {
+		t.Errorf("%d records delivered more than once (sequence numbers %s)",
+			len(res.Duplicated), summarize(res.Duplicated))
+	}
+	for i, m := range res.Corrupt {
+		if i == 5 {
+			t.Errorf("... and %d more corrupt messages", len(res.Corrupt)-i)
+			break
+		}
+		t.Errorf("corrupt message: %q", m)
+	}
+}
+
+// summarize lists the first sequence numbers of seqs.
+func summarize(seqs []int) string {
+	const max = 10
+	parts := make([]string, 0, max+1)
+	for _, seq := range seqs[:min(len(seqs), max)] {
+		parts = append(parts, fmt.Sprint(seq))
+	}
+	if len(seqs) > max {
+		parts = append(parts, "...")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,127 @@
+// integrationtest_test.go: Tests for the delivery checks, without Docker
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// memorySink is a Target and a writer at once: every line written is a
+// message, optionally wrapped in a header, and every lossEvery-th one is
+// lost.
+type memorySink struct {
+	mu        sync.Mutex
+	messages  []string
+	header    string
+	lossEvery int
+	written   int
+}
+
+func (s *memorySink) Endpoint() string { return "memory" }
+
+func (s *memorySink) Messages(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...), nil
+}
+
+func (s *memorySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, line := range bytes.Split(bytes.TrimSuffix(p, []byte("\n")), []byte("\n")) {
+		s.written++
+		if s.lossEvery > 0 && s.written%s.lossEvery == 0 {
+			continue
+		}
+		s.messages = append(s.messages, s.header+string(line))
+	}
+	return len(p), nil
+}
+
+func (s *memorySink) Sync() error { return nil }
+
+func (s *memorySink) writer(*testing.T, string) iris.WriteSyncer { return s }
+
+func TestVerify_Delivered(t *testing.T) {
+	sink := &memorySink{header: "<14>1 2025-01-15T13:00:00Z host app - - - "}
+	res := Verify(t, sink, sink.writer, Options{Records: 50, Timeout: time.Second})
+	if !res.OK() || res.Delivered != 50 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const runID = "TestCheck-1"
+	sink := &memorySink{}
+	logger, err := iris.New(iris.Config{Level: iris.Info, Output: sink, Encoder: iris.NewJSONEncoder()})
+	if err != nil {
+		t.Fatalf("iris.New failed: %v", err)
+	}
+	logRun(logger, runID, 6)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	good := sink.messages
+
+	tests := []struct {
+		name     string
+		messages []string
+		check    func(Result) bool
+	}{
+		{"all delivered", good, func(r Result) bool { return r.OK() && r.Delivered == 6 }},
+		{"other runs ignored", append([]string{`{"run":"other","seq":0}`, "sink started"}, good...),
+			func(r Result) bool { return r.OK() }},
+		{"missing", good[1:5], func(r Result) bool {
+			return len(r.Missing) == 2 && r.Missing[0] == 0 && r.Missing[1] == 5 && r.Delivered == 4
+		}},
+		{"duplicated", append(good, good[3]), func(r Result) bool {
+			return len(r.Duplicated) == 1 && r.Duplicated[0] == 3 && len(r.Missing) == 0
+		}},
+		{"corrupt", append(good[:5:5], strings.Replace(good[5], "tab", "TAB", 1)), func(r Result) bool {
+			return len(r.Corrupt) == 1 && len(r.Missing) == 1
+		}},
+		{"wrong level", append(good[:5:5], strings.Replace(good[5], `"error"`, `"info"`, 1)), func(r Result) bool {
+			return len(r.Corrupt) == 1
+		}},
+		{"truncated", append(good[:5:5], good[5][:len(good[5])-3]), func(r Result) bool {
+			return len(r.Corrupt) == 1
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := check(tt.messages, runID, 6); !tt.check(res) {
+				t.Errorf("result = %+v", res)
+			}
+		})
+	}
+}
+
+func TestAwait_Lossy(t *testing.T) {
+	sink := &memorySink{lossEvery: 10}
+	logger, err := iris.New(iris.Config{Level: iris.Info, Output: sink, Encoder: iris.NewJSONEncoder()})
+	if err != nil {
+		t.Fatalf("iris.New failed: %v", err)
+	}
+	logRun(logger, "lossy", 30)
+	_ = logger.Close()
+
+	start := time.Now()
+	res := await(context.Background(), sink, "lossy", Options{Records: 30, Timeout: 100 * time.Millisecond})
+	if res.Delivered != 27 || len(res.Missing) != 3 || res.Missing[0] != 9 {
+		t.Errorf("result = %+v", res)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("returned after %v, before the timeout", elapsed)
+	}
+}
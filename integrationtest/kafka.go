@@ -0,0 +1,93 @@
+// kafka.go: Kafka sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+)
+
+// KafkaImage is the image StartKafka runs.
+var KafkaImage = "confluentinc/confluent-local:7.5.0"
+
+// kafkaConsumeTimeout is how long Messages waits for more messages once it
+// has read the topic.
+const kafkaConsumeTimeout = 5 * time.Second
+
+// Kafka is a single-node Kafka (KRaft) started by StartKafka.
+type Kafka struct {
+	ctr    *kafka.KafkaContainer
+	broker string
+	topic  string
+}
+
+// StartKafka starts Kafka for t with topic created, and returns it once it
+// is ready. The container is removed when t ends; t is skipped if Docker is
+// not available.
+//
+// Messages are read with the console consumer inside the container, so the
+// harness needs no Kafka client.
+//
+// Parameters:
+//   - t: Test owning the container
+//   - topic: Topic the writer under test produces to
+//
+// Returns:
+//   - *Kafka: Target whose Endpoint is the broker address, host:port
+func StartKafka(t *testing.T, topic string) *Kafka {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	ctr, err := kafka.Run(ctx, KafkaImage, kafka.WithClusterID("iris-integrationtest"))
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatalf("starting %s failed: %v", KafkaImage, err)
+	}
+	brokers, err := ctr.Brokers(ctx)
+	if err != nil {
+		t.Fatalf("Kafka brokers: %v", err)
+	}
+	_, stderr, code, err := execOutput(ctx, ctr, "kafka-topics", "--bootstrap-server", "localhost:9092",
+		"--create", "--if-not-exists", "--topic", topic, "--partitions", "1", "--replication-factor", "1")
+	if err == nil && code != 0 {
+		err = fmt.Errorf("exit code %d: %s", code, strings.TrimSpace(stderr))
+	}
+	if err != nil {
+		t.Fatalf("creating topic %s failed: %v", topic, err)
+	}
+	return &Kafka{ctr: ctr, broker: brokers[0], topic: topic}
+}
+
+// Endpoint returns the broker address.
+func (k *Kafka) Endpoint() string {
+	return k.broker
+}
+
+// Topic returns the topic the writer under test produces to.
+func (k *Kafka) Topic() string {
+	return k.topic
+}
+
+// Messages returns the values of the messages in the topic, in partition
+// order. The consumer exits once no message arrives for a few seconds,
+// which it may report with a non-zero exit code; only what it read counts.
+func (k *Kafka) Messages(ctx context.Context) ([]string, error) {
+	out, _, _, err := execOutput(ctx, k.ctr, "kafka-console-consumer", "--bootstrap-server", "localhost:9092",
+		"--topic", k.topic, "--from-beginning", "--timeout-ms", strconv.FormatInt(kafkaConsumeTimeout.Milliseconds(), 10))
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
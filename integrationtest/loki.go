@@ -0,0 +1,112 @@
+// loki.go: Grafana Loki sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// LokiImage is the image StartLoki runs.
+var LokiImage = "grafana/loki:3.4.2"
+
+// lokiQueryLimit is the most lines Loki returns for a query with the
+// default limits; Messages returns at most this many.
+const lokiQueryLimit = 5000
+
+// Loki is a single-binary Loki started by StartLoki.
+type Loki struct {
+	url      string
+	selector string
+	client   *http.Client
+}
+
+// StartLoki starts Loki for t and returns it once it is ready. The
+// container is removed when t ends; t is skipped if Docker is not
+// available.
+//
+// Parameters:
+//   - t: Test owning the container
+//   - selector: LogQL stream selector matching the streams the writer
+//     under test pushes, e.g. `{job="iris"}`
+//
+// Returns:
+//   - *Loki: Target whose Endpoint is the push URL
+func StartLoki(t *testing.T, selector string) *Loki {
+	t.Helper()
+	ctr := startContainer(t, LokiImage,
+		testcontainers.WithExposedPorts("3100/tcp"),
+		testcontainers.WithCmd("-config.file=/etc/loki/local-config.yaml"),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/ready").WithPort("3100/tcp").WithStartupTimeout(2*time.Minute)),
+	)
+	return &Loki{
+		url:      endpoint(t, ctr, "3100/tcp", "http"),
+		selector: selector,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Endpoint returns the push URL, http://host:port/loki/api/v1/push.
+func (l *Loki) Endpoint() string {
+	return l.url + "/loki/api/v1/push"
+}
+
+// URL returns the base URL of the Loki HTTP API.
+func (l *Loki) URL() string {
+	return l.url
+}
+
+// Messages returns the lines of the streams matching the selector pushed
+// in the last hour, oldest first.
+func (l *Loki) Messages(ctx context.Context) ([]string, error) {
+	now := time.Now()
+	q := url.Values{
+		"query":     {l.selector},
+		"start":     {strconv.FormatInt(now.Add(-time.Hour).UnixNano(), 10)},
+		"end":       {strconv.FormatInt(now.Add(time.Minute).UnixNano(), 10)},
+		"limit":     {strconv.Itoa(lokiQueryLimit)},
+		"direction": {"forward"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url+"/loki/api/v1/query_range?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki query: %s", resp.Status)
+	}
+	var body struct {
+		Data struct {
+			Result []struct {
+				Values [][2]string `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("loki query: %w", err)
+	}
+	var messages []string
+	for _, stream := range body.Data.Result {
+		for _, v := range stream.Values {
+			messages = append(messages, v[1])
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,73 @@
+// sinks_test.go: Runs the harness against the containerized sinks
+//
+// These tests need Docker and are skipped without it. Loki is reached with
+// the in-tree LokiWriter; the other writers here are minimal references for
+// the harness, not production writers.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// syslogWriter sends each record to a syslog server over TCP with an
+// RFC 5424 header, newline-delimited.
+type syslogWriter struct {
+	conn net.Conn
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	msg := append([]byte("<14>1 - - iris - - - "), p...)
+	if _, err := w.conn.Write(msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *syslogWriter) Sync() error  { return nil }
+func (w *syslogWriter) Close() error { return w.conn.Close() }
+
+func TestLoki(t *testing.T) {
+	loki := StartLoki(t, `{job="iris"}`)
+	Verify(t, loki, func(t *testing.T, endpoint string) iris.WriteSyncer {
+		w, err := iris.NewLokiWriter(iris.LokiConfig{URL: endpoint, Labels: map[string]string{"job": "iris"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}, Options{})
+}
+
+func TestSyslog(t *testing.T) {
+	syslog := StartSyslog(t)
+	Verify(t, syslog, func(t *testing.T, endpoint string) iris.WriteSyncer {
+		conn, err := net.DialTimeout("tcp", endpoint, 5*time.Second)
+		if err != nil {
+			t.Fatalf("dial %s: %v", endpoint, err)
+		}
+		return &syslogWriter{conn: conn}
+	}, Options{})
+}
+
+func TestKafka(t *testing.T) {
+	kafka := StartKafka(t, "iris-logs")
+	if kafka.Endpoint() == "" || kafka.Topic() != "iris-logs" {
+		t.Fatalf("endpoint %q, topic %q", kafka.Endpoint(), kafka.Topic())
+	}
+	messages, err := kafka.Messages(context.Background())
+	if err != nil {
+		t.Fatalf("Messages failed: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("new topic has messages: %v", messages)
+	}
+}
@@ -0,0 +1,96 @@
+// syslog.go: Syslog sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// SyslogImage is the image StartSyslog runs.
+var SyslogImage = "balabit/syslog-ng:4.8.1"
+
+// syslogFile is where the container writes the messages it receives.
+const syslogFile = "/var/log/iris/messages"
+
+// syslogConfig makes syslog-ng write every message received on TCP 601 or
+// UDP 514, unparsed and whole, to syslogFile, one per line. TCP messages
+// are newline-delimited (RFC 6587 non-transparent framing).
+const syslogConfig = `@version: 4.8
+options { create-dirs(yes); };
+source s_net {
+    network(transport("tcp") port(601) flags(no-parse));
+    network(transport("udp") port(514) flags(no-parse));
+};
+destination d_file { file("` + syslogFile + `" template("${MESSAGE}\n")); };
+log { source(s_net); destination(d_file); };
+`
+
+// Syslog is a syslog-ng server started by StartSyslog.
+type Syslog struct {
+	ctr *testcontainers.DockerContainer
+	tcp string
+	udp string
+}
+
+// StartSyslog starts a syslog server for t and returns it once it accepts
+// connections. The container is removed when t ends; t is skipped if Docker
+// is not available.
+//
+// The server keeps each message whole, header included; Verify finds the
+// record in it.
+//
+// Parameters:
+//   - t: Test owning the container
+//
+// Returns:
+//   - *Syslog: Target whose Endpoint is the TCP address, host:port
+func StartSyslog(t *testing.T) *Syslog {
+	t.Helper()
+	ctr := startContainer(t, SyslogImage,
+		testcontainers.WithExposedPorts("601/tcp", "514/udp"),
+		testcontainers.WithFiles(testcontainers.ContainerFile{
+			Reader:            strings.NewReader(syslogConfig),
+			ContainerFilePath: "/etc/syslog-ng/syslog-ng.conf",
+			FileMode:          0o644,
+		}),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("601/tcp").WithStartupTimeout(time.Minute)),
+	)
+	return &Syslog{
+		ctr: ctr,
+		tcp: endpoint(t, ctr, "601/tcp", ""),
+		udp: endpoint(t, ctr, "514/udp", ""),
+	}
+}
+
+// Endpoint returns the TCP address.
+func (s *Syslog) Endpoint() string {
+	return s.tcp
+}
+
+// UDPEndpoint returns the UDP address.
+func (s *Syslog) UDPEndpoint() string {
+	return s.udp
+}
+
+// Messages returns the messages received so far, in arrival order.
+func (s *Syslog) Messages(ctx context.Context) ([]string, error) {
+	out, stderr, code, err := execOutput(ctx, s.ctr, "sh", "-c", "cat "+syslogFile+" 2>/dev/null || true")
+	if err == nil && code != 0 {
+		err = fmt.Errorf("reading %s: exit code %d: %s", syslogFile, code, strings.TrimSpace(stderr))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
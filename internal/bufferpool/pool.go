@@ -31,16 +31,41 @@ const (
 	DefaultCapacity = 512 // 512 bytes
 )
 
-// pool is the global sync.Pool for reusing byte buffers.
-// Using sync.Pool provides automatic garbage collection coordination
+// sizeClasses is the number of pools, each holding buffers of at least
+// DefaultCapacity << (2*class) bytes: 512 B, 2 KiB, 8 KiB, 32 KiB,
+// 128 KiB and 512 KiB.
+const sizeClasses = 6
+
+// pools are the global sync.Pools for reusing byte buffers, one per size
+// class, so a large record gets a large buffer instead of growing a small
+// one. Using sync.Pool provides automatic garbage collection coordination
 // and scales well across multiple goroutines.
-var pool = sync.Pool{
-	New: func() any {
-		atomic.AddInt64(&allocCount, 1)
-		// Pre-allocate with default capacity to reduce early reallocations
-		buf := bytes.NewBuffer(make([]byte, 0, DefaultCapacity))
-		return buf
-	},
+var pools [sizeClasses]sync.Pool
+
+func init() {
+	for i := range pools {
+		capacity := classCapacity(i)
+		pools[i].New = func() any {
+			atomic.AddInt64(&allocCount, 1)
+			// Pre-allocate the class capacity to reduce early reallocations
+			return bytes.NewBuffer(make([]byte, 0, capacity))
+		}
+	}
+}
+
+// classCapacity returns the minimum capacity of buffers in class.
+func classCapacity(class int) int {
+	return DefaultCapacity << (2 * class)
+}
+
+// classFor returns the smallest class whose buffers hold size bytes, or the
+// largest class if none does.
+func classFor(size int) int {
+	class := 0
+	for class < sizeClasses-1 && classCapacity(class) < size {
+		class++
+	}
+	return class
 }
 
 // Get restituisce un *bytes.Buffer pulito (Reset) dal pool.
@@ -48,11 +73,25 @@ var pool = sync.Pool{
 // sia pronto per l'uso immediato senza contenuti precedenti.
 func Get() *bytes.Buffer {
 	atomic.AddInt64(&getCount, 1)
-	b := pool.Get().(*bytes.Buffer)
+	b := pools[0].Get().(*bytes.Buffer)
 	b.Reset() // Ensure buffer is clean
 	return b
 }
 
+// GetSized returns a clean buffer with capacity for at least size bytes,
+// taken from the pool of the matching size class. Use it when the size of
+// the content is known or estimated up front (see iris Record.EstimatedSize)
+// to avoid repeated growth; sizes up to DefaultCapacity behave like Get.
+func GetSized(size int) *bytes.Buffer {
+	atomic.AddInt64(&getCount, 1)
+	b := pools[classFor(size)].Get().(*bytes.Buffer)
+	b.Reset()
+	if b.Cap() < size {
+		b.Grow(size) // Larger than the largest class
+	}
+	return b
+}
+
 // Put restituisce il buffer al pool. Se il buffer è cresciuto troppo,
 // lo azzera per evitare growth non controllato della memoria.
 // Questa strategia bilancia performance e utilizzo memoria.
@@ -73,7 +112,13 @@ func Put(b *bytes.Buffer) {
 	}
 
 	b.Reset() // Clean buffer before returning to pool
-	pool.Put(b)
+
+	// Largest class whose minimum the buffer satisfies
+	class := sizeClasses - 1
+	for class > 0 && b.Cap() < classCapacity(class) {
+		class--
+	}
+	pools[class].Put(b)
 }
 
 // Stats returns current buffer pool statistics for monitoring.
@@ -273,3 +273,51 @@ func TestPoolEfficiency(t *testing.T) {
 	t.Logf("Pool efficiency: %.4f allocations per get (%d allocs for %d gets)",
 		efficiencyRatio, stats.Allocations, stats.Gets)
 }
+
+// TestGetSized tests size-class selection for sized buffers
+func TestGetSized(t *testing.T) {
+	tests := []struct {
+		size    int
+		minCap  int
+		maxCap  int
+		classes int
+	}{
+		{0, DefaultCapacity, MaxBufferSize, 0},
+		{DefaultCapacity, DefaultCapacity, MaxBufferSize, 0},
+		{DefaultCapacity + 1, 2048, MaxBufferSize, 1},
+		{100000, 100000, MaxBufferSize, 4},
+		{600000, 600000, MaxBufferSize, 5},
+		{MaxBufferSize + 1, MaxBufferSize + 1, 2 * MaxBufferSize, 5},
+	}
+	for _, tt := range tests {
+		if got := classFor(tt.size); got != tt.classes {
+			t.Errorf("classFor(%d) = %d, want %d", tt.size, got, tt.classes)
+		}
+		buf := GetSized(tt.size)
+		if buf.Len() != 0 || buf.Cap() < tt.minCap || buf.Cap() > tt.maxCap {
+			t.Errorf("GetSized(%d): len=%d cap=%d, want empty with cap in [%d, %d]",
+				tt.size, buf.Len(), buf.Cap(), tt.minCap, tt.maxCap)
+		}
+		Put(buf)
+	}
+}
+
+// TestPutReturnsToSizeClass tests that grown buffers are reused for large requests
+func TestPutReturnsToSizeClass(t *testing.T) {
+	buf := GetSized(40000)
+	buf.WriteString("large record")
+	Put(buf)
+
+	// A small Get never receives a buffer from a larger class
+	small := Get()
+	if small.Cap() >= classCapacity(3) {
+		t.Errorf("Get returned a %d-byte buffer from a larger class", small.Cap())
+	}
+	Put(small)
+
+	large := GetSized(40000)
+	if large.Len() != 0 || large.Cap() < 40000 {
+		t.Errorf("GetSized(40000): len=%d cap=%d", large.Len(), large.Cap())
+	}
+	Put(large)
+}
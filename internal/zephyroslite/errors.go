@@ -25,4 +25,8 @@ var (
 
 	// ErrRingClosed is returned when operations are attempted on closed ring
 	ErrRingClosed = errors.New("ring buffer is closed")
+
+	// ErrRingFull is returned by WriteContext when a DropOnFull ring has no
+	// free slot
+	ErrRingFull = errors.New("ring buffer is full")
 )
@@ -28,8 +28,11 @@
 package zephyroslite
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"runtime"
+	"sync"
 	"time"
 )
 
@@ -81,13 +84,15 @@ func (bp BackpressurePolicy) String() string {
 //   - Basic padding (no advanced CPU-specific optimizations)
 //   - Simplified idle strategy (no complex spinning algorithms)
 type ZephyrosLight[T any] struct {
-	// Ring buffer core
+	// Ring buffer core: slots live in buffer, or in padded with slot
+	// padding enabled (exactly one of the two is allocated)
 	buffer   []T
+	padded   []paddedSlot[T]
 	capacity int64
 	mask     int64 // capacity - 1 for bit masking
 
 	// MPSC atomic cursors (cache-line padded)
-	writerCursor AtomicPaddedInt64 // Producer claim sequence
+	writerCursor AtomicPaddedInt64 // Producer claim sequence (sealedBit set once drained on close)
 	readerCursor AtomicPaddedInt64 // Consumer sequence
 
 	// Availability tracking for MPSC coordination
@@ -95,21 +100,50 @@ type ZephyrosLight[T any] struct {
 
 	// Configuration
 	processor          ProcessorFunc[T]
+	batchEnd           func() // Called after each processed batch (nil = none)
+	closeHook          func() // Called by the consumer before the final drain (nil = none)
+	requestHook        func() // Called by the consumer before a batch once requested (nil = none)
 	batchSize          int64
 	backpressurePolicy BackpressurePolicy
 	idleStrategy       IdleStrategy
 
 	// Control
-	closed AtomicPaddedInt64 // 0 = open, 1 = closed
+	closed        AtomicPaddedInt64 // 0 = open, 1 = closed
+	hookRequested AtomicPaddedInt64 // 1 = call requestHook before the next batch
+
+	// processMu keeps Snapshot readers and the consumer from touching the
+	// same slots; it is only contended while a snapshot is being taken
+	processMu sync.Mutex
 
 	// Basic statistics (simplified)
 	processed AtomicPaddedInt64 // Total processed count
 	dropped   AtomicPaddedInt64 // Total dropped count
 
+	// Contention counters, only touched on the slow paths: a claim that
+	// lost its CAS to another producer, or a write that found the ring full.
+	// A DropOnFull write dropped on a full ring is both a drop and a full
+	// encounter; it is counted once, in fullDrops, to keep the drop path to
+	// a single atomic add.
+	casRetries     AtomicPaddedInt64
+	fullEncounters AtomicPaddedInt64
+	fullDrops      AtomicPaddedInt64
+
 	// Cache line padding to prevent false sharing
 	_ [64]byte
 }
 
+// SlotPadding is the number of bytes kept free between the items of
+// adjacent slots when slot padding is enabled.
+const SlotPadding = 64
+
+// paddedSlot keeps an item off the cache lines of its neighbours: whatever
+// the item size, a full cache line separates the end of one item from the
+// start of the next.
+type paddedSlot[T any] struct {
+	item T
+	_    [SlotPadding]byte
+}
+
 // Builder provides a fluent interface for creating ZephyrosLight instances
 type Builder[T any] struct {
 	capacity           int64
@@ -117,6 +151,7 @@ type Builder[T any] struct {
 	batchSize          int64
 	backpressurePolicy BackpressurePolicy
 	idleStrategy       IdleStrategy
+	slotPadding        bool
 }
 
 // NewBuilder creates a new builder for ZephyrosLight with specified capacity
@@ -196,6 +231,25 @@ func (b *Builder[T]) WithIdleStrategy(strategy IdleStrategy) *Builder[T] {
 	return b
 }
 
+// WithSlotPadding separates adjacent slots by SlotPadding bytes.
+//
+// Unless the item size is a multiple of the cache line, the end of one slot
+// shares a cache line with the start of the next: a producer filling slot
+// i+1 then invalidates the line the consumer is reading the tail of slot i
+// from, and two producers filling neighbouring slots do the same to each
+// other. Padding removes that false sharing at the cost of SlotPadding
+// bytes per slot, which matters for small items and little for large ones.
+//
+// Parameters:
+//   - enabled: Whether to pad slots (default false)
+//
+// Returns:
+//   - *Builder[T]: Builder instance for method chaining
+func (b *Builder[T]) WithSlotPadding(enabled bool) *Builder[T] {
+	b.slotPadding = enabled
+	return b
+}
+
 // Build creates and initializes the ZephyrosLight ring buffer
 //
 // Returns:
@@ -225,7 +279,6 @@ func (b *Builder[T]) Build() (*ZephyrosLight[T], error) {
 
 	// Create ring buffer
 	z := &ZephyrosLight[T]{
-		buffer:             make([]T, b.capacity),
 		capacity:           b.capacity,
 		mask:               b.capacity - 1,
 		availableBuffer:    make([]AtomicPaddedInt64, b.capacity),
@@ -235,6 +288,12 @@ func (b *Builder[T]) Build() (*ZephyrosLight[T], error) {
 		idleStrategy:       idleStrategy,
 	}
 
+	if b.slotPadding {
+		z.padded = make([]paddedSlot[T], b.capacity)
+	} else {
+		z.buffer = make([]T, b.capacity)
+	}
+
 	// Initialize availability markers to invalid sequence
 	for i := range z.availableBuffer {
 		z.availableBuffer[i].Store(-1)
@@ -278,18 +337,15 @@ func (z *ZephyrosLight[T]) Write(writerFunc func(*T)) bool {
 
 // writeDropOnFull implements the original non-blocking behavior
 func (z *ZephyrosLight[T]) writeDropOnFull(writerFunc func(*T)) bool {
-	// MPSC: Claim sequence number atomically
-	sequence := z.writerCursor.Add(1) - 1
-
-	// Check if we're about to lap the reader (buffer full check)
-	if sequence >= z.readerCursor.Load()+z.capacity {
+	sequence, ok := z.claim()
+	if !ok {
 		// Buffer full - drop the message
-		z.dropped.Add(1)
+		z.fullDrops.Add(1)
 		return false
 	}
 
 	// Write to allocated slot
-	slot := &z.buffer[sequence&z.mask]
+	slot := z.slot(sequence)
 	writerFunc(slot)
 
 	// Mark slot as available for reading
@@ -298,43 +354,197 @@ func (z *ZephyrosLight[T]) writeDropOnFull(writerFunc func(*T)) bool {
 	return true
 }
 
+// slot returns the item stored for sequence.
+func (z *ZephyrosLight[T]) slot(sequence int64) *T {
+	if z.padded != nil {
+		return &z.padded[sequence&z.mask].item
+	}
+	return &z.buffer[sequence&z.mask]
+}
+
+// claim reserves the next sequence number if the ring has room.
+//
+// The full check and the claim happen in one CAS, so a sequence is only
+// handed out when its slot is free. Claiming first and checking afterwards
+// would leave unpublished sequences behind on every drop, and the consumer
+// would stop at the first such gap forever.
+//
+// A sealed cursor (see LoopProcess) is always beyond reader+capacity, so no
+// sequence can be claimed after the final drain.
+func (z *ZephyrosLight[T]) claim() (int64, bool) {
+	for {
+		sequence := z.writerCursor.Load()
+		if sequence >= z.readerCursor.Load()+z.capacity {
+			return 0, false
+		}
+		if z.writerCursor.CompareAndSwap(sequence, sequence+1) {
+			return sequence, true
+		}
+		z.casRetries.Add(1)
+	}
+}
+
+// claimBlock reserves up to n consecutive sequence numbers in one CAS, as
+// many as the ring has room for, and returns the first one and the count
+// (0 if the ring is full or sealed).
+func (z *ZephyrosLight[T]) claimBlock(n int64) (int64, int64) {
+	for {
+		sequence := z.writerCursor.Load()
+		free := z.readerCursor.Load() + z.capacity - sequence
+		if free <= 0 {
+			return 0, 0
+		}
+		count := min(n, free)
+		if z.writerCursor.CompareAndSwap(sequence, sequence+count) {
+			return sequence, count
+		}
+		z.casRetries.Add(1)
+	}
+}
+
+// WriteBatch writes n items, claiming ring slots in contiguous blocks with
+// one CAS per block instead of one per item.
+//
+// Items are written in order: writerFunc is called with i from 0 to n-1 and
+// the slot for item i. Each slot is published as soon as it is filled, so
+// the consumer can start on a block before it is complete. Under DropOnFull
+// the batch stops at the first claim that finds the ring full; under
+// BlockOnFull it waits for room until every item is written or the ring is
+// closed. Items that were not written are counted as dropped.
+//
+// Parameters:
+//   - n: Number of items to write
+//   - writerFunc: Function to populate the slot for item i
+//
+// Returns:
+//   - int: Number of items written (items 0 to the result minus one)
+func (z *ZephyrosLight[T]) WriteBatch(n int, writerFunc func(i int, slot *T)) int {
+	written, waited := 0, false
+	for written < n && z.closed.Load() == 0 {
+		first, count := z.claimBlock(int64(n - written))
+		if count == 0 {
+			if !waited {
+				z.fullEncounters.Add(1)
+				waited = true
+			}
+			if z.backpressurePolicy != BlockOnFull {
+				break
+			}
+			runtime.Gosched()
+			time.Sleep(time.Microsecond)
+			continue
+		}
+		for sequence := first; sequence < first+count; sequence++ {
+			writerFunc(written, z.slot(sequence))
+			z.availableBuffer[sequence&z.mask].Store(sequence)
+			written++
+		}
+	}
+	if written < n {
+		z.dropped.Add(int64(n - written))
+	}
+	return written
+}
+
 // writeBlockOnFull implements blocking behavior for guaranteed delivery
 func (z *ZephyrosLight[T]) writeBlockOnFull(writerFunc func(*T)) bool {
-	// Block until we can successfully write or the ring is closed
+	return z.writeBlocking(context.Background(), writerFunc) == nil
+}
+
+// WriteContext is Write with cancellation: under BlockOnFull, it stops
+// waiting for a free slot once ctx is done. Under DropOnFull it never waits.
+//
+// Returns:
+//   - error: nil if written; ErrRingClosed, ErrRingFull (DropOnFull) or
+//     ctx.Err() if the item was dropped
+func (z *ZephyrosLight[T]) WriteContext(ctx context.Context, writerFunc func(*T)) error {
+	if z.closed.Load() != 0 {
+		z.dropped.Add(1)
+		return ErrRingClosed
+	}
+	if z.backpressurePolicy != BlockOnFull {
+		if !z.writeDropOnFull(writerFunc) {
+			return ErrRingFull
+		}
+		return nil
+	}
+	return z.writeBlocking(ctx, writerFunc)
+}
+
+// writeBlocking waits for a free slot until the ring is closed or ctx is
+// done.
+func (z *ZephyrosLight[T]) writeBlocking(ctx context.Context, writerFunc func(*T)) error {
+	waited := false
 	for {
 		// Check if closed before each attempt
 		if z.closed.Load() != 0 {
 			z.dropped.Add(1)
-			return false
+			return ErrRingClosed
 		}
 
-		// MPSC: Claim sequence number atomically
-		sequence := z.writerCursor.Add(1) - 1
-
-		// Check if we're about to lap the reader (buffer full check)
-		currentReader := z.readerCursor.Load()
-		if sequence < currentReader+z.capacity {
-			// Space available - write the message
-			slot := &z.buffer[sequence&z.mask]
+		// MPSC: Claim a sequence number only when its slot is free
+		if sequence, ok := z.claim(); ok {
+			slot := z.slot(sequence)
 			writerFunc(slot)
 
 			// Mark slot as available for reading
 			z.availableBuffer[sequence&z.mask].Store(sequence)
 
-			return true
+			return nil
 		}
 
-		// Buffer full - yield and retry
-		// We need to "rollback" the sequence claim since we can't use it
-		// Note: This is a simplification - a full implementation would use
-		// more sophisticated coordination to avoid sequence number waste
-		runtime.Gosched()
+		// Buffer full - counted once per write, not once per retry
+		if !waited {
+			z.fullEncounters.Add(1)
+			waited = true
+		}
 
-		// Small delay to prevent tight spinning
+		// Give up if the caller stopped waiting
+		select {
+		case <-ctx.Done():
+			z.dropped.Add(1)
+			return ctx.Err()
+		default:
+		}
+
+		// Yield and retry, with a small delay to prevent tight spinning
+		runtime.Gosched()
 		time.Sleep(time.Microsecond)
 	}
 }
 
+// SetBatchEndHook installs fn to be called by the consumer after each
+// processed batch, before the batch is released to producers and flush
+// waiters. Sinks that coalesce the records of a batch submit them from
+// there. It must be called before the consumer starts.
+func (z *ZephyrosLight[T]) SetBatchEndHook(fn func()) {
+	z.batchEnd = fn
+}
+
+// SetCloseHook installs fn to be called by the consumer once LoopProcess
+// sees the ring closed, before the final drain. It runs on the consumer
+// goroutine, so it may call VisitPending. It must be called before the
+// consumer starts.
+func (z *ZephyrosLight[T]) SetCloseHook(fn func()) {
+	z.closeHook = fn
+}
+
+// SetRequestHook installs fn to be called by the consumer before its next
+// batch each time RequestHook is called. Like the close hook it runs on the
+// consumer goroutine without the consumer lock, so it may call
+// VisitPending. It must be called before the consumer starts.
+func (z *ZephyrosLight[T]) SetRequestHook(fn func()) {
+	z.requestHook = fn
+}
+
+// RequestHook asks the consumer to call the request hook before its next
+// batch. Requests made before the hook runs are merged into one call.
+func (z *ZephyrosLight[T]) RequestHook() {
+	if z.requestHook != nil {
+		z.hookRequested.Store(1)
+	}
+}
+
 // ProcessBatch processes available items in a single batch
 //
 // This is a simplified version that uses fixed batch size rather than
@@ -345,13 +555,25 @@ func (z *ZephyrosLight[T]) writeBlockOnFull(writerFunc func(*T)) bool {
 //
 // Performance: Optimized for zero-allocation batch processing
 func (z *ZephyrosLight[T]) ProcessBatch() int {
+	if z.hookRequested.Load() != 0 && z.hookRequested.CompareAndSwap(1, 0) {
+		z.requestHook()
+	}
+
 	current := z.readerCursor.Load()
-	writerPos := z.writerCursor.Load()
+	writerPos := z.writerPos()
 
 	if current >= writerPos {
 		return 0 // Nothing to process
 	}
 
+	z.processMu.Lock()
+	defer z.processMu.Unlock()
+
+	// Another caller may have processed the batch while we waited
+	if current = z.readerCursor.Load(); current >= writerPos {
+		return 0
+	}
+
 	// Use fixed batch size (simplified vs adaptive)
 	maxProcess := min(z.batchSize, writerPos-current)
 
@@ -376,9 +598,12 @@ func (z *ZephyrosLight[T]) ProcessBatch() int {
 
 	for seq := current; seq <= available; seq++ {
 		idx := seq & z.mask
-		z.processor(&z.buffer[idx])
+		z.processor(z.slot(seq))
 		z.availableBuffer[idx].Store(-1) // Reset availability
 	}
+	if z.batchEnd != nil {
+		z.batchEnd()
+	}
 
 	// Update reader position
 	z.readerCursor.Store(available + 1)
@@ -387,6 +612,66 @@ func (z *ZephyrosLight[T]) ProcessBatch() int {
 	return processed
 }
 
+// Snapshot calls visit for up to max items that have been written but not
+// yet processed, oldest first, and returns the number of items visited.
+//
+// Slots that were claimed but not yet published by their producer are
+// skipped. The consumer is paused while visit runs, so visit must copy what
+// it needs and return quickly; the pointer must not be retained or modified.
+//
+// Parameters:
+//   - max: Maximum number of items to visit (<= 0 visits none)
+//   - visit: Read-only callback for each pending item
+//
+// Returns:
+//   - int: Number of items visited
+func (z *ZephyrosLight[T]) Snapshot(max int, visit func(*T)) int {
+	return z.visitPending(max, visit)
+}
+
+// VisitPending calls visit for every item that has been written but not
+// yet processed, oldest first, and returns the number of items visited.
+// Unlike Snapshot, visit may modify the items (for example to process some
+// of them ahead of the others and mark them), so it is reserved to the
+// consumer goroutine between batches, e.g. from the close hook (see
+// SetCloseHook). The processor and the batch end hook run with the consumer
+// lock held and must not call it.
+//
+// Parameters:
+//   - visit: Callback for each pending item
+//
+// Returns:
+//   - int: Number of items visited
+func (z *ZephyrosLight[T]) VisitPending(visit func(*T)) int {
+	return z.visitPending(math.MaxInt, visit)
+}
+
+// visitPending calls visit for up to max published, unprocessed items
+// while holding the consumer off.
+func (z *ZephyrosLight[T]) visitPending(max int, visit func(*T)) int {
+	if max <= 0 {
+		return 0
+	}
+
+	z.processMu.Lock()
+	defer z.processMu.Unlock()
+
+	// Under processMu the reader cannot advance, so published slots in
+	// [reader, reader+capacity) cannot be reclaimed by producers
+	reader := z.readerCursor.Load()
+	end := min(z.writerPos(), reader+z.capacity)
+
+	visited := 0
+	for seq := reader; seq < end && visited < max; seq++ {
+		if z.availableBuffer[seq&z.mask].Load() != seq {
+			continue // Not published yet, or dropped
+		}
+		visit(z.slot(seq))
+		visited++
+	}
+	return visited
+}
+
 // LoopProcess runs the consumer loop with configurable idle strategy
 //
 // This uses the configured IdleStrategy to control CPU usage when no work
@@ -406,13 +691,46 @@ func (z *ZephyrosLight[T]) LoopProcess() {
 			}
 		}
 	}
+	if z.closeHook != nil && z.writerCursor.Load()&sealedBit == 0 {
+		z.closeHook() // Not on later calls, once the ring is sealed
+	}
 
-	// Final drain on close - process remaining items
-	for z.ProcessBatch() > 0 {
-		// Keep processing until empty
+	// Final drain on close. A producer may have passed the closed check
+	// just before Close and claimed a slot it has not published yet: wait
+	// for it, then seal the cursor in the same CAS that confirms nothing is
+	// pending, so no claim can slip in after the last batch.
+	for {
+		for z.ProcessBatch() > 0 {
+			// Keep processing until empty
+		}
+		writerPos := z.writerCursor.Load()
+		if writerPos&sealedBit != 0 {
+			return // Sealed by an earlier drain
+		}
+		if z.readerCursor.Load() < writerPos {
+			runtime.Gosched() // Claimed but not yet published
+			continue
+		}
+		if z.writerCursor.CompareAndSwap(writerPos, writerPos|sealedBit) {
+			return
+		}
 	}
 }
 
+// sealedBit marks the writer cursor once the consumer has drained a closed
+// ring; sequences never get anywhere near it.
+const sealedBit = int64(1) << 62
+
+// writerPos returns the writer cursor without the sealed bit.
+func (z *ZephyrosLight[T]) writerPos() int64 {
+	return z.writerCursor.Load() &^ sealedBit
+}
+
+// Closed reports whether Close has been called.
+func (z *ZephyrosLight[T]) Closed() bool {
+	return z.closed.Load() != 0
+}
+
 // Close stops the processing loop and marks the ring as closed.
 //
 // The method is idempotent and thread-safe. After Close() is called,
@@ -434,7 +752,7 @@ func (z *ZephyrosLight[T]) Loop() {
 // Always ensure the consumer loop is active before calling Flush().
 func (z *ZephyrosLight[T]) Flush() error {
 	// Get current writer position - this is our target
-	targetPosition := z.writerCursor.Load()
+	targetPosition := z.writerPos()
 
 	// If nothing to flush, return immediately
 	if targetPosition == 0 {
@@ -511,16 +829,28 @@ func (z *ZephyrosLight[T]) Flush() error {
 		targetPosition, currentReader, targetProcessed, currentProcessed)
 }
 
+// FullEncounters returns the number of writes (or batches) that found the
+// ring full, the "full_encounters" statistic without building the map.
+func (z *ZephyrosLight[T]) FullEncounters() int64 {
+	return z.fullEncounters.Load() + z.fullDrops.Load()
+}
+
 // Stats returns basic performance statistics
 //
 // This provides essential metrics without the comprehensive
 // monitoring available in commercial Zephyros.
 //
+// "cas_retries" counts claims that lost their CAS to another producer and
+// had to retry; "full_encounters" counts writes (or batches) that found
+// the ring full, once per write however long it waited. Both are only
+// updated on those slow paths, so an uncontended ring pays nothing for them.
+//
 // Returns:
 //   - map[string]int64: Basic performance metrics
 func (z *ZephyrosLight[T]) Stats() map[string]int64 {
-	writerPos := z.writerCursor.Load()
+	writerPos := z.writerPos()
 	readerPos := z.readerCursor.Load()
+	fullDrops := z.fullDrops.Load()
 
 	return map[string]int64{
 		"writer_position": writerPos,
@@ -528,8 +858,10 @@ func (z *ZephyrosLight[T]) Stats() map[string]int64 {
 		"buffer_size":     z.capacity,
 		"items_buffered":  writerPos - readerPos,
 		"items_processed": z.processed.Load(),
-		"items_dropped":   z.dropped.Load(),
+		"items_dropped":   z.dropped.Load() + fullDrops,
 		"closed":          z.closed.Load(),
 		"batch_size":      z.batchSize,
+		"cas_retries":     z.casRetries.Load(),
+		"full_encounters": z.fullEncounters.Load() + fullDrops,
 	}
 }
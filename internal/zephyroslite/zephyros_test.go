@@ -7,9 +7,13 @@
 package zephyroslite
 
 import (
+	"context"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 )
 
 // TestBackpressurePolicy_String tests the String method for BackpressurePolicy
@@ -514,6 +518,8 @@ func TestZephyrosLight_Stats(t *testing.T) {
 			"items_dropped":   0,
 			"closed":          0,
 			"batch_size":      5,
+			"cas_retries":     0,
+			"full_encounters": 0,
 		}
 
 		for key, expected := range expectedStats {
@@ -557,6 +563,50 @@ func TestZephyrosLight_Stats(t *testing.T) {
 	})
 }
 
+func TestZephyrosLight_ContentionStats(t *testing.T) {
+	z, err := NewBuilder[TestRecord](4).
+		WithProcessor(func(*TestRecord) {}).
+		WithBatchSize(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+
+	// A single producer never loses a CAS
+	for i := 0; i < 6; i++ {
+		z.Write(func(r *TestRecord) { r.ID = int64(i) })
+	}
+	z.WriteBatch(3, func(int, *TestRecord) {})
+	stats := z.Stats()
+	if stats["cas_retries"] != 0 {
+		t.Errorf("cas_retries = %d without concurrent producers, want 0", stats["cas_retries"])
+	}
+	if stats["full_encounters"] != 3 { // Two writes and one batch
+		t.Errorf("full_encounters = %d, want 3", stats["full_encounters"])
+	}
+
+	// A blocked write is counted once however long it waits
+	blocking, err := NewBuilder[TestRecord](4).
+		WithProcessor(func(*TestRecord) {}).
+		WithBatchSize(4).
+		WithBackpressurePolicy(BlockOnFull).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		blocking.Write(func(*TestRecord) {})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := blocking.WriteContext(ctx, func(*TestRecord) {}); err == nil {
+		t.Fatal("WriteContext on a full ring succeeded")
+	}
+	if got := blocking.Stats()["full_encounters"]; got != 1 {
+		t.Errorf("full_encounters = %d after one blocked write, want 1", got)
+	}
+}
+
 // TestZephyrosLight_Concurrent tests concurrent operations
 func TestZephyrosLight_Concurrent(t *testing.T) {
 	t.Run("Concurrent_Writers", func(t *testing.T) {
@@ -772,3 +822,584 @@ func TestZephyrosLight_ResetIdleStrategy(t *testing.T) {
 		}
 	})
 }
+
+// TestZephyrosLight_Snapshot tests read-only inspection of pending items
+func TestZephyrosLight_Snapshot(t *testing.T) {
+	z, err := NewBuilder[TestRecord](8).
+		WithProcessor(func(r *TestRecord) {}).
+		WithBatchSize(2).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		z.Write(func(r *TestRecord) { r.ID = int64(i) })
+	}
+
+	var ids []int64
+	n := z.Snapshot(10, func(r *TestRecord) { ids = append(ids, r.ID) })
+	if n != 5 || len(ids) != 5 || ids[0] != 0 || ids[4] != 4 {
+		t.Errorf("Snapshot visited %d items: %v", n, ids)
+	}
+
+	// Max limits the number of items visited
+	if n := z.Snapshot(3, func(*TestRecord) {}); n != 3 {
+		t.Errorf("expected 3 items with max=3, got %d", n)
+	}
+	if n := z.Snapshot(0, func(*TestRecord) { t.Error("visit called with max=0") }); n != 0 {
+		t.Errorf("expected 0 items with max=0, got %d", n)
+	}
+
+	// Processed items disappear from the snapshot
+	z.ProcessBatch()
+	ids = ids[:0]
+	z.Snapshot(10, func(r *TestRecord) { ids = append(ids, r.ID) })
+	if len(ids) != 3 || ids[0] != 2 {
+		t.Errorf("expected pending IDs [2 3 4], got %v", ids)
+	}
+}
+
+// TestZephyrosLight_RecoversAfterDrops tests that dropped writes leave no
+// unpublished sequences behind for the consumer to wait on
+func TestZephyrosLight_RecoversAfterDrops(t *testing.T) {
+	for _, policy := range []BackpressurePolicy{DropOnFull, BlockOnFull} {
+		t.Run(policy.String(), func(t *testing.T) {
+			var processed []int64
+			z, err := NewBuilder[TestRecord](8).
+				WithProcessor(func(r *TestRecord) { processed = append(processed, r.ID) }).
+				WithBatchSize(8).
+				WithBackpressurePolicy(policy).
+				Build()
+			if err != nil {
+				t.Fatalf("Failed to create ZephyrosLight: %v", err)
+			}
+
+			for i := 0; i < 8; i++ {
+				z.Write(func(r *TestRecord) { r.ID = int64(i) })
+			}
+			if policy == DropOnFull {
+				for i := 0; i < 5; i++ {
+					if z.Write(func(r *TestRecord) { r.ID = -1 }) {
+						t.Fatal("expected write to a full ring to fail")
+					}
+				}
+			} else {
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					z.Write(func(r *TestRecord) { r.ID = 8 })
+				}()
+				time.Sleep(10 * time.Millisecond) // Let the writer retry against the full ring
+				z.ProcessBatch()
+				select {
+				case <-done:
+				case <-time.After(2 * time.Second):
+					t.Fatal("blocked write never claimed the slot freed by the consumer")
+				}
+			}
+
+			for z.ProcessBatch() > 0 {
+			}
+			if !z.Write(func(r *TestRecord) { r.ID = 100 }) {
+				t.Fatal("write after draining failed")
+			}
+			if n := z.ProcessBatch(); n != 1 {
+				t.Fatalf("consumer stalled after full ring: processed %d, want 1", n)
+			}
+			if err := z.Flush(); err != nil {
+				t.Errorf("Flush failed: %v", err)
+			}
+			if processed[len(processed)-1] != 100 {
+				t.Errorf("last processed ID = %d, want 100", processed[len(processed)-1])
+			}
+			z.Close()
+		})
+	}
+}
+
+// BenchmarkZephyrosLight_ContendedWrite measures GOMAXPROCS producers
+// racing for the writer cursor. The ring is drained between rounds with
+// the timer stopped, so writes never find it full and only the claim and
+// publish are timed; run with -cpu 1,4,8 to vary the producers.
+func BenchmarkZephyrosLight_ContendedWrite(b *testing.B) {
+	const capacity = 1 << 16
+	z, err := NewBuilder[TestRecord](capacity).
+		WithProcessor(func(*TestRecord) {}).
+		WithBackpressurePolicy(DropOnFull).
+		WithBatchSize(capacity).
+		Build()
+	if err != nil {
+		b.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	defer z.Close()
+	producers := runtime.GOMAXPROCS(0)
+
+	b.ResetTimer()
+	for remaining := b.N; remaining > 0; {
+		round := remaining
+		if round > capacity {
+			round = capacity
+		}
+		remaining -= round
+		var wg sync.WaitGroup
+		for p := 0; p < producers; p++ {
+			n := round / producers
+			if p < round%producers {
+				n++
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < n; i++ {
+					z.Write(func(r *TestRecord) { r.Value = i })
+				}
+			}()
+		}
+		wg.Wait()
+		b.StopTimer()
+		for z.ProcessBatch() > 0 {
+		}
+		b.StartTimer()
+	}
+	b.StopTimer()
+	if dropped := z.Stats()["items_dropped"]; dropped != 0 {
+		b.Fatalf("%d writes dropped", dropped)
+	}
+}
+
+// TestZephyrosLight_SnapshotConcurrent tests snapshots racing with producers and the consumer
+func TestZephyrosLight_SnapshotConcurrent(t *testing.T) {
+	z, err := NewBuilder[TestRecord](4096).
+		WithProcessor(func(r *TestRecord) { r.Message = "" }).
+		WithBatchSize(8).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	go z.LoopProcess()
+	defer z.Close()
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				z.Write(func(r *TestRecord) { r.Message = "pending" })
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			z.Snapshot(16, func(r *TestRecord) {
+				if r.Message != "pending" {
+					t.Errorf("snapshot saw a processed or unpublished slot: %q", r.Message)
+				}
+			})
+		}
+	}()
+
+	wg.Wait()
+	<-done
+}
+
+// TestZephyrosLight_CloseRacingWriters tests that every write accepted while
+// Close races with producers is processed by the final drain
+func TestZephyrosLight_CloseRacingWriters(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		var processed atomic.Int64
+		z, err := NewBuilder[TestRecord](1024).
+			WithProcessor(func(r *TestRecord) { processed.Add(1) }).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to create ZephyrosLight: %v", err)
+		}
+		done := make(chan struct{})
+		go func() {
+			z.LoopProcess()
+			close(done)
+		}()
+
+		var accepted atomic.Int64
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for n := 0; n < 200; n++ {
+					if z.Write(func(r *TestRecord) { r.ID = int64(n) }) {
+						accepted.Add(1)
+					}
+				}
+			}()
+		}
+		time.Sleep(time.Duration(i) * 10 * time.Microsecond)
+		z.Close()
+		<-done
+		wg.Wait()
+
+		if z.Write(func(r *TestRecord) {}) {
+			t.Fatal("write accepted after the final drain")
+		}
+		if accepted.Load() != processed.Load() {
+			t.Fatalf("accepted %d writes but processed %d", accepted.Load(), processed.Load())
+		}
+		if err := z.Flush(); err != nil {
+			t.Errorf("Flush after close failed: %v", err)
+		}
+	}
+}
+
+// TestZephyrosLight_WriteContext tests that a canceled context ends a
+// BlockOnFull wait and that the other outcomes map to their errors
+func TestZephyrosLight_WriteContext(t *testing.T) {
+	fill := func(r *TestRecord) { r.Message = "ctx" }
+	newRing := func(policy BackpressurePolicy) *ZephyrosLight[TestRecord] {
+		z, err := NewBuilder[TestRecord](4).
+			WithProcessor(func(*TestRecord) {}).
+			WithBackpressurePolicy(policy).
+			WithBatchSize(1).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to create ZephyrosLight: %v", err)
+		}
+		// No consumer runs, so the ring fills up after its capacity
+		for i := 0; i < 4; i++ {
+			if err := z.WriteContext(context.Background(), fill); err != nil {
+				t.Fatalf("Write %d into empty ring failed: %v", i, err)
+			}
+		}
+		return z
+	}
+
+	t.Run("BlockOnFull_Canceled", func(t *testing.T) {
+		z := newRing(BlockOnFull)
+		defer z.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		if err := z.WriteContext(ctx, fill); err != context.DeadlineExceeded {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if dropped := z.Stats()["items_dropped"]; dropped != 1 {
+			t.Errorf("Expected 1 dropped item, got %d", dropped)
+		}
+	})
+
+	t.Run("DropOnFull_Full", func(t *testing.T) {
+		z := newRing(DropOnFull)
+		defer z.Close()
+
+		if err := z.WriteContext(context.Background(), fill); err != ErrRingFull {
+			t.Errorf("Expected ErrRingFull, got %v", err)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		z := newRing(BlockOnFull)
+		z.Close()
+
+		if err := z.WriteContext(context.Background(), fill); err != ErrRingClosed {
+			t.Errorf("Expected ErrRingClosed, got %v", err)
+		}
+	})
+}
+
+func TestZephyrosLight_WriteBatch(t *testing.T) {
+	newRing := func(policy BackpressurePolicy, processor func(*TestRecord)) *ZephyrosLight[TestRecord] {
+		z, err := NewBuilder[TestRecord](8).
+			WithProcessor(processor).
+			WithBackpressurePolicy(policy).
+			WithBatchSize(8).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to create ZephyrosLight: %v", err)
+		}
+		return z
+	}
+	fill := func(i int, r *TestRecord) { r.ID = int64(i) }
+
+	t.Run("DropOnFull_Partial", func(t *testing.T) {
+		var ids []int64
+		z := newRing(DropOnFull, func(r *TestRecord) { ids = append(ids, r.ID) })
+		defer z.Close()
+
+		if !z.Write(func(r *TestRecord) { r.ID = -1 }) {
+			t.Fatal("Write into empty ring failed")
+		}
+		// No consumer runs: 7 free slots for a batch of 10
+		if written := z.WriteBatch(10, fill); written != 7 {
+			t.Errorf("Expected 7 items written, got %d", written)
+		}
+		if dropped := z.Stats()["items_dropped"]; dropped != 3 {
+			t.Errorf("Expected 3 dropped items, got %d", dropped)
+		}
+		z.ProcessBatch()
+		for i, id := range ids {
+			if id != int64(i-1) {
+				t.Fatalf("Expected items in order, got %v", ids)
+			}
+		}
+	})
+
+	t.Run("BlockOnFull_LargerThanRing", func(t *testing.T) {
+		var sum, count atomic.Int64
+		z := newRing(BlockOnFull, func(r *TestRecord) {
+			sum.Add(r.ID)
+			count.Add(1)
+		})
+		go z.LoopProcess()
+		defer z.Close()
+
+		if written := z.WriteBatch(100, fill); written != 100 {
+			t.Errorf("Expected 100 items written, got %d", written)
+		}
+		if err := z.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		if count.Load() != 100 || sum.Load() != 99*100/2 {
+			t.Errorf("Expected items 0..99 processed, got %d items summing to %d", count.Load(), sum.Load())
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		z := newRing(BlockOnFull, func(*TestRecord) {})
+		z.Close()
+
+		if written := z.WriteBatch(3, fill); written != 0 {
+			t.Errorf("Expected no items written to a closed ring, got %d", written)
+		}
+	})
+}
+
+func BenchmarkZephyrosLight_WriteBatch(b *testing.B) {
+	const batch = 64
+	for _, bc := range []struct {
+		name  string
+		write func(z *ZephyrosLight[TestRecord])
+	}{
+		{"Write", func(z *ZephyrosLight[TestRecord]) {
+			for i := 0; i < batch; i++ {
+				z.Write(func(r *TestRecord) { r.Value = i })
+			}
+		}},
+		{"WriteBatch", func(z *ZephyrosLight[TestRecord]) {
+			z.WriteBatch(batch, func(i int, r *TestRecord) { r.Value = i })
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			z, err := NewBuilder[TestRecord](4096).
+				WithProcessor(func(*TestRecord) {}).
+				WithBackpressurePolicy(BlockOnFull).
+				WithBatchSize(256).
+				Build()
+			if err != nil {
+				b.Fatalf("Failed to create ZephyrosLight: %v", err)
+			}
+			go z.LoopProcess()
+			defer z.Close()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bc.write(z)
+				}
+			})
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*batch), "ns/item")
+		})
+	}
+}
+
+func TestZephyrosLight_SlotPadding(t *testing.T) {
+	for _, padded := range []bool{false, true} {
+		var got []int64
+		z, err := NewBuilder[TestRecord](8).
+			WithProcessor(func(r *TestRecord) { got = append(got, r.ID) }).
+			WithBatchSize(8).
+			WithSlotPadding(padded).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to create ZephyrosLight: %v", err)
+		}
+		if (z.padded != nil) != padded || (z.buffer != nil) == padded {
+			t.Fatalf("padded=%v: wrong slot storage allocated", padded)
+		}
+		if padded {
+			first, second := z.slot(0), z.slot(1)
+			if gap := uintptr(unsafe.Pointer(second)) - uintptr(unsafe.Pointer(first)) - unsafe.Sizeof(*first); gap < SlotPadding {
+				t.Errorf("gap between slots = %d bytes, want at least %d", gap, SlotPadding)
+			}
+		}
+
+		// Wrap around the ring twice through every write path
+		var id int64
+		for round := 0; round < 2; round++ {
+			for i := 0; i < 3; i++ {
+				id++
+				z.Write(func(r *TestRecord) { r.ID = id })
+			}
+			id++
+			_ = z.WriteContext(context.Background(), func(r *TestRecord) { r.ID = id })
+			base := id
+			z.WriteBatch(3, func(i int, r *TestRecord) { r.ID = base + int64(i) + 1 })
+			id += 3
+			if n := z.Snapshot(10, func(*TestRecord) {}); n != 7 {
+				t.Errorf("padded=%v: Snapshot visited %d items, want 7", padded, n)
+			}
+			z.ProcessBatch()
+		}
+		for i, v := range got {
+			if v != int64(i+1) {
+				t.Fatalf("padded=%v: processed %v, want 1..14 in order", padded, got)
+			}
+		}
+		if len(got) != 14 {
+			t.Errorf("padded=%v: processed %d items, want 14", padded, len(got))
+		}
+	}
+}
+
+// BenchmarkZephyrosLight_SlotPadding compares concurrent producers with and
+// without slot padding; run with -cpu 1,4,8 on a multi-core machine, since
+// false sharing needs producers and the consumer on different cores.
+func BenchmarkZephyrosLight_SlotPadding(b *testing.B) {
+	for _, padded := range []bool{false, true} {
+		name := "Unpadded"
+		if padded {
+			name = "Padded"
+		}
+		b.Run(name, func(b *testing.B) {
+			z, err := NewBuilder[TestRecord](4096).
+				WithProcessor(func(r *TestRecord) { _ = r.Value }).
+				WithBackpressurePolicy(BlockOnFull).
+				WithBatchSize(256).
+				WithSlotPadding(padded).
+				Build()
+			if err != nil {
+				b.Fatalf("Failed to create ZephyrosLight: %v", err)
+			}
+			go z.LoopProcess()
+			defer z.Close()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					i++
+					z.Write(func(r *TestRecord) { r.ID, r.Value = int64(i), i })
+				}
+			})
+		})
+	}
+}
+
+func TestZephyrosLight_BatchEndHook(t *testing.T) {
+	var ends []int
+	n := 0
+	z, err := NewBuilder[TestRecord](16).
+		WithProcessor(func(*TestRecord) { n++ }).
+		WithBatchSize(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	z.SetBatchEndHook(func() { ends = append(ends, n) })
+
+	for i := 0; i < 10; i++ {
+		z.Write(func(r *TestRecord) { r.ID = int64(i) })
+	}
+	for z.ProcessBatch() > 0 {
+	}
+
+	// Called once per batch, after its records
+	if len(ends) != 3 || ends[0] != 4 || ends[1] != 8 || ends[2] != 10 {
+		t.Errorf("hook saw %v records processed, want [4 8 10]", ends)
+	}
+	if z.ProcessBatch() != 0 || len(ends) != 3 {
+		t.Errorf("hook called for an empty batch")
+	}
+}
+
+// TestZephyrosLight_CloseHook tests that the close hook runs once, on the
+// consumer, before the final drain, and can modify pending items
+func TestZephyrosLight_CloseHook(t *testing.T) {
+	var order []int64
+	z, err := NewBuilder[TestRecord](16).
+		WithProcessor(func(r *TestRecord) { order = append(order, r.ID) }).
+		WithBatchSize(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	hooks := 0
+	z.SetCloseHook(func() {
+		hooks++
+		if len(order) != 0 {
+			t.Errorf("hook ran after %d items were processed", len(order))
+		}
+		visited := z.VisitPending(func(r *TestRecord) { r.ID += 100 })
+		if visited != 6 {
+			t.Errorf("VisitPending visited %d items, want 6", visited)
+		}
+	})
+
+	for i := 0; i < 6; i++ {
+		z.Write(func(r *TestRecord) { r.ID = int64(i) })
+	}
+	// Snapshot leaves the items untouched
+	z.Snapshot(16, func(r *TestRecord) {
+		if r.ID >= 100 {
+			t.Errorf("item %d already modified", r.ID)
+		}
+	})
+	z.Close()
+	z.LoopProcess()
+	z.LoopProcess() // Already sealed: no second hook call
+
+	if hooks != 1 {
+		t.Errorf("hook called %d times, want 1", hooks)
+	}
+	if len(order) != 6 || order[0] != 100 || order[5] != 105 {
+		t.Errorf("processed %v, want 100..105", order)
+	}
+}
+
+func TestZephyrosLight_RequestHook(t *testing.T) {
+	var order []int64
+	z, err := NewBuilder[TestRecord](16).
+		WithProcessor(func(r *TestRecord) { order = append(order, r.ID) }).
+		WithBatchSize(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create ZephyrosLight: %v", err)
+	}
+	defer z.Close()
+	hooks := 0
+	z.SetRequestHook(func() {
+		hooks++
+		z.VisitPending(func(r *TestRecord) { r.ID += 100 })
+	})
+
+	for i := 0; i < 6; i++ {
+		z.Write(func(r *TestRecord) { r.ID = int64(i) })
+	}
+	z.ProcessBatch()
+	if hooks != 0 {
+		t.Fatalf("hook called %d times without a request", hooks)
+	}
+	z.RequestHook()
+	z.RequestHook() // Merged with the first request
+	for z.ProcessBatch() > 0 {
+	}
+
+	if hooks != 1 {
+		t.Errorf("hook called %d times, want 1", hooks)
+	}
+	if len(order) != 6 || order[3] != 3 || order[4] != 104 || order[5] != 105 {
+		t.Errorf("processed %v, want 0..3 then 104, 105", order)
+	}
+}
@@ -40,10 +40,10 @@
 package iris
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -139,16 +139,38 @@ func buildSmartConfig(cfg Config, opts ...Option) Config {
 	if cfg.Capacity != 0 {
 		smartCfg.Capacity = cfg.Capacity
 	}
+	if cfg.BatchSize > 0 {
+		smartCfg.BatchSize = cfg.BatchSize
+	}
 	if cfg.IdleStrategy != nil {
 		smartCfg.IdleStrategy = cfg.IdleStrategy
 	}
 	if cfg.Sampler != nil {
 		smartCfg.Sampler = cfg.Sampler
 	}
+	smartCfg.SlotPadding = cfg.SlotPadding
+	smartCfg.MaxCapacity = cfg.MaxCapacity
+	smartCfg.GrowAfter = cfg.GrowAfter
+	smartCfg.Inline = cfg.Inline
+	smartCfg.AutoStart = cfg.AutoStart
+	smartCfg.Fields = cfg.Fields
+	smartCfg.Pipeline = cfg.Pipeline
 
 	return smartCfg
 }
 
+// EffectiveConfig returns the configuration New would use for cfg and opts,
+// after smart defaults (capacity, batch size, idle strategy, output, encoder,
+// level) have been applied. Useful for diagnostics and config tooling.
+//
+// Example:
+//
+//	eff := iris.EffectiveConfig(cfg)
+//	fmt.Println("ring capacity:", eff.Capacity)
+func EffectiveConfig(cfg Config, opts ...Option) Config {
+	return buildSmartConfig(cfg, opts...)
+}
+
 // Smart detection functions for auto-configuration
 
 func detectOptimalArchitecture() Architecture {
@@ -200,8 +222,8 @@ func detectOutputFromOptions(opts ...Option) WriteSyncer {
 	}
 
 	// Check if output was set (we'll need to add this capability to loggerOptions)
-	// For now, fallback to stdout
-	return WrapWriter(os.Stdout) // Smart default: stdout
+	// For now, fallback to the platform default (stdout, or console.log on js/wasm)
+	return defaultOutput()
 }
 
 func detectEncoderFromOptions(opts ...Option) Encoder {
@@ -294,7 +316,7 @@ var (
 // background goroutine processes and outputs the log records.
 //
 // Thread Safety:
-//   - All logging methods (Debug, Info, Warn, Error) are thread-safe
+//   - All logging methods (Trace, Debug, Info, Warn, Error) are thread-safe
 //   - Multiple goroutines can log concurrently without locks
 //   - Configuration changes (SetLevel) are atomic and thread-safe
 //
@@ -307,8 +329,9 @@ var (
 //   - Context inheritance with With() for repeated fields
 //
 // Lifecycle:
-//   - Create with New() - configures but doesn't start processing
-//   - Call Start() to begin background processing
+//   - Create with New() - starts background processing by default
+//   - Call Start() only when Config.AutoStart is AutoStartOff, e.g. to
+//     attach a custom consumer first (calling it otherwise is harmless)
 //   - Use logging methods (Debug, Info, etc.) for actual logging
 //   - Call Close() for graceful shutdown with guaranteed log processing
 type Logger struct {
@@ -326,15 +349,34 @@ type Logger struct {
 	name       string        // Logger name for hierarchical organization
 
 	// Performance counters
-	dropped atomic.Int64 // Number of dropped records due to ring buffer full
-	started atomic.Int32 // Logger start state (0=stopped, 1=started)
+	latency   *latencyStats                  // Latency histograms shared with clones (nil = disabled)
+	drops     *dropCounters                  // Per-reason drop counts shared with clones
+	templates *templateAnalyzer              // Message template analyzer shared with clones (nil = disabled)
+	rates     *rateTracker                   // Per-name rates shared with clones (nil = disabled)
+	pressure  *pressureState                 // Pressure() window shared with clones
+	dropped   atomic.Pointer[stripedCounter] // Dropped records of this logger (allocated on first drop)
+
+	runtimeHooks *hookRegistry      // AddHook registrations shared with clones
+	summary      *dropSummary       // WithDropSummary state shared with clones (nil = disabled)
+	emergency    *emergencyState    // EmergencyFlush state shared with clones
+	watchdog     *watchdogState     // WithWatchdog state shared with clones (nil = disabled)
+	clockDrift   *clockDriftState   // WithClockDriftCheck state shared with clones (nil = disabled)
+	profile      *profiler          // WithProfiling state, used by the consumer (nil = disabled)
+	samplerState *samplerStateSaver // WithSamplerState saver shared with clones (nil = disabled)
+	sessions     *sessionRegistry   // Debug sessions shared with clones
+	sources      *sourceLevels      // SetSourceLevel rules shared with clones
+	verbosity    *verbosityLevels   // SetVerbosity and SetModuleVerbosity, shared with clones
+	errs         *errorChannel      // Errors channel shared with clones
+	dynFields    *dynamicFields     // SetFields state shared with clones
+	configFields int                // Leading baseFields from Config.Fields, replaced by SetFields
+
+	merged atomic.Pointer[mergedFields] // Base fields after SetFields, rebuilt on change
 }
 
 // New creates a new high-performance logger with the specified configuration and options.
 //
-// The logger is created but not started - call Start() to begin processing.
-// This separation allows for configuration verification and testing setup
-// before actual log processing begins.
+// The logger is started before New returns unless cfg.AutoStart is
+// AutoStartOff, in which case Start() must be called to begin processing.
 //
 // Parameters:
 //   - cfg: Logger configuration with output, encoding, and performance settings
@@ -346,7 +388,8 @@ type Logger struct {
 //   - Nil Output or Encoder will cause an error
 //
 // Returns:
-//   - *Logger: Configured logger ready for Start()
+//   - *Logger: Running logger, ready for logging (or waiting for Start()
+//     with AutoStartOff)
 //   - error: Configuration validation error
 //
 // Example:
@@ -360,32 +403,131 @@ type Logger struct {
 //	if err != nil {
 //	    return err
 //	}
-//	logger.Start()
+//	defer logger.Close()
+//	logger.Info("service started")
 func New(cfg Config, opts ...Option) (*Logger, error) {
+	// Pipeline sinks are opened here; explicit options apply after it
+	if cfg.Pipeline != nil {
+		popts, err := cfg.Pipeline.options()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(popts, opts...)
+	}
+
 	// SMART API: Ignore complex Config and auto-detect everything from opts + smart defaults
 	c := buildSmartConfig(cfg, opts...)
 
 	l := &Logger{
-		out:     c.Output,
-		enc:     c.Encoder,
-		clock:   c.TimeFn,
-		name:    c.Name,
-		sampler: c.Sampler,
-		opts:    newLoggerOptions().merge(opts...),
+		out:      c.Output,
+		enc:      c.Encoder,
+		clock:    c.TimeFn,
+		name:     c.Name,
+		sampler:  c.Sampler,
+		opts:     newLoggerOptions().merge(opts...),
+		drops:    newDropCounters(),
+		pressure: &pressureState{},
+		errs:     &errorChannel{},
+
+		runtimeHooks: &hookRegistry{},
+		sessions:     &sessionRegistry{},
+		sources:      &sourceLevels{},
+		verbosity:    &verbosityLevels{},
+		dynFields:    &dynamicFields{},
 	}
+	l.acquireSuppressors()
+	l.emergency = newEmergencyState(l.opts.emergency)
+	l.watchdog = newWatchdogState(l.opts.watchdog)
+	if cfg.TimeFn == nil {
+		l.clockDrift = newClockDriftState(l.opts.clockDrift)
+		if l.clockDrift != nil {
+			l.clock = l.clockDrift.now
+		}
+	}
+	l.profile = newProfiler(l.opts.profile, l.name)
+	l.samplerState = newSamplerStateSaver(l.opts.samplerState, l.sampler)
 	l.level.SetLevel(c.Level)
+	if len(c.Fields) > 0 {
+		l.baseFields = append([]Field(nil), c.Fields...)
+		l.configFields = len(c.Fields)
+	}
+	if l.opts.latencyHistograms {
+		l.latency = &latencyStats{}
+	}
+	if l.opts.templates != nil {
+		l.templates = newTemplateAnalyzer(*l.opts.templates)
+	}
+	if l.opts.rateMetrics {
+		l.rates = newRateTracker()
+	}
+	if l.opts.dropSummary > 0 {
+		l.summary = newDropSummary(l.opts.dropSummary)
+	}
 
 	// Processor unico (consumer thread): encode + write + hooks
 	var proc ProcessorFunc = func(rec *Record) {
-		buf := bufferpool.Get()
-		l.enc.Encode(rec, l.clock(), buf)
-		_, _ = l.out.Write(buf.Bytes())
-		// Hooks nel consumer (niente contend)
-		for _, h := range l.opts.hooks {
-			h(rec)
+		if l.opts.recordDebug {
+			l.checkFilledSlot(rec)
+			l.checkBorrowed(rec)
+		}
+		l.profile.record()
+		if l.r.abandoned.Load() {
+			l.discardShutdown(rec)
+			return
+		}
+		if len(l.opts.stages) > 0 && !l.runStages(rec) {
+			l.discardFiltered(rec)
+			return
+		}
+		if l.opts.msgTransform != nil {
+			l.transformMessage(rec)
+		}
+		out := l.out
+		if l.opts.classifier != nil {
+			out = l.opts.classifier.classify(rec, out)
+		}
+		now := rec.time
+		if now.IsZero() {
+			now = l.clock()
+		}
+		if l.streamRecord(rec, now, out) {
+			l.finishRecord(rec)
+			return
+		}
+		buf := bufferpool.GetSized(rec.EstimatedSize())
+		span := l.profile.begin(phaseEncode)
+		var encErr *errors.Error
+		if l.latency != nil {
+			start := latencyNow()
+			encErr = encodeRecord(l.enc, rec, now, buf)
+			l.latency.encode.Record(time.Duration(latencyNow() - start))
+		} else {
+			encErr = encodeRecord(l.enc, rec, now, buf)
 		}
+		l.profile.end(phaseEncode, span)
+		if encErr != nil {
+			l.reportEncodeError(encErr, rec)
+			bufferpool.Put(buf)
+			l.finishRecord(rec)
+			return
+		}
+		span = l.profile.begin(phaseWrite)
+		if rec.session == nil || l.writeSession(rec, buf.Bytes()) {
+			if out != l.out || rec.routes&l.opts.exclusiveRoutes == 0 {
+				if _, err := out.Write(buf.Bytes()); err != nil {
+					l.reportWriteError(err, out)
+				}
+				if l.rates != nil {
+					l.rates.observe(rec.Logger, buf.Len())
+				}
+			}
+			if (len(l.opts.outputs) > 0 || len(l.opts.recordWriters) > 0) && out == l.out {
+				l.writeOutputs(rec, now, buf)
+			}
+		}
+		l.profile.end(phaseWrite, span)
 		bufferpool.Put(buf)
-		rec.resetForWrite()
+		l.finishRecord(rec)
 	}
 
 	// Create high-performance MPSC lock-free ring buffer with user-selected architecture
@@ -400,13 +542,38 @@ func New(cfg Config, opts ...Option) (*Logger, error) {
 	//
 	// IdleStrategy controls CPU usage when no work is available, providing different
 	// trade-offs between latency and CPU consumption.
-	rg, err := newRing(c.Capacity, c.BatchSize, c.Architecture, c.NumRings, c.BackpressurePolicy, c.IdleStrategy, proc)
+	//
+	// Inline mode skips the ring entirely and processes records in the caller.
+	var rg *Ring
+	var err error
+	if c.Inline {
+		rg, err = newInlineRing(proc)
+	} else {
+		rg, err = newRing(c.Capacity, c.BatchSize, c.Architecture, c.NumRings, c.BackpressurePolicy, c.IdleStrategy, c.SlotPadding, proc)
+		if err == nil && c.MaxCapacity != 0 && c.MaxCapacity != c.Capacity {
+			err = rg.enableElastic(c.MaxCapacity, c.GrowAfter, c.IdleStrategy)
+		}
+		if err == nil && l.opts.priorityLane != 0 {
+			err = rg.enablePriorityLane(l.opts.priorityLane, c.IdleStrategy)
+		}
+	}
 	if err != nil {
+		for _, c := range l.opts.owned {
+			_ = c.Close()
+		}
 		return nil, errors.Wrap(err, ErrCodeLoggerCreation, "failed to create ring buffer").
 			WithContext("capacity", c.Capacity).
 			WithContext("batch_size", c.BatchSize)
 	}
 	l.r = rg
+	if sinks := l.batchSinks(); l.profile != nil {
+		rg.setBatchEnd(func() { l.profile.flushBatch(sinks) })
+	} else if len(sinks) > 0 {
+		rg.setBatchEnd(func() { endBatch(sinks) })
+	}
+	if c.AutoStart != AutoStartOff {
+		l.Start()
+	}
 	return l, nil
 }
 
@@ -418,8 +585,12 @@ func New(cfg Config, opts ...Option) (*Logger, error) {
 // after the first call.
 //
 // The consumer goroutine will continue processing until Close() is called.
-// All logging operations require Start() to be called first, otherwise
-// log records will accumulate in the ring buffer without being processed.
+// New already starts the logger unless Config.AutoStart is AutoStartOff;
+// otherwise records logged before Start() accumulate in the ring buffer and
+// the first of them is reported through the error handler.
+//
+// A logger and the loggers derived from it (With, Named, WithOptions) share
+// one consumer: whichever of them is started first starts it, exactly once.
 //
 // Performance Notes:
 //   - Uses lock-free atomic operations for state management
@@ -428,10 +599,22 @@ func New(cfg Config, opts ...Option) (*Logger, error) {
 //
 // Thread Safety: Safe to call from multiple goroutines
 func (l *Logger) Start() {
-	if !l.started.CompareAndSwap(0, 1) {
-		return
+	if !l.r.start() {
+		return // Already started, or closed
 	}
-	go l.r.Loop()
+	l.startAsyncHooks()
+	l.startDropSummary()
+	l.startEmergencyWatch()
+	l.startWatchdog()
+	l.startClockDrift()
+	l.startSamplerState()
+	if l.r.inline != nil {
+		return // Inline mode: records are processed by the caller
+	}
+	if l.opts.runtime != nil && l.opts.runtime.attach(l) {
+		return // Consumed by the shared runtime
+	}
+	go l.r.consume()
 }
 
 // Close gracefully shuts down the logger.
@@ -442,11 +625,14 @@ func (l *Logger) Start() {
 // been written to the output.
 //
 // After Close() is called:
-//   - All subsequent logging operations will fail silently
+//   - All subsequent logging operations return false and are counted as
+//     DropClosed (and reported, with WithStrictLifecycle)
 //   - The ring buffer becomes unusable
 //   - All buffered records are guaranteed to be processed
 //
-// The method is idempotent - calling Close() multiple times is safe.
+// The method is idempotent - calling Close() multiple times is safe. Later
+// and concurrent calls wait for the drain and return nil (ErrLoggerClosed
+// with WithStrictLifecycle) without syncing the output again.
 //
 // Close flushes any pending log data and closes the logger
 // Close should be called when the logger is no longer needed
@@ -458,11 +644,49 @@ func (l *Logger) Start() {
 //
 // Thread Safety: Safe to call from multiple goroutines
 func (l *Logger) Close() error {
-	// First stop the ring buffer processing
-	l.r.Close()
+	// Report pending drops while the ring still accepts records
+	if !l.r.Closed() {
+		l.stopBackground()
+	}
+
+	// Then stop the ring buffer processing and wait for the drain
+	if !l.r.Close() {
+		if l.opts.strictLifecycle {
+			return ErrLoggerClosed
+		}
+		return nil // Closed by an earlier call
+	}
+	return l.finishClose()
+}
 
-	// Then sync any remaining output
-	return l.Sync()
+// stopBackground stops the goroutines of the logger's features before the
+// ring is closed, so that what they log on the way out is still accepted.
+func (l *Logger) stopBackground() {
+	l.stopWatchdog()
+	l.stopClockDrift()
+	l.stopSamplerState()
+	l.stopEmergencyWatch()
+	l.stopDropSummary()
+}
+
+// finishClose completes Close once the ring is drained.
+func (l *Logger) finishClose() error {
+	// Let async hooks finish the records the consumer handed them
+	l.stopAsyncHooks()
+
+	// Report the losses of the last interval to an Errors reader
+	if l.errorsWanted() {
+		l.reportDrops()
+	}
+
+	// Then sync any remaining output, and close the ones the logger owns
+	err := l.sync()
+	for _, c := range l.opts.owned {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
 }
 
 // SetLevel atomically changes the minimum logging level.
@@ -472,7 +696,7 @@ func (l *Logger) Close() error {
 // subsequent log operations.
 //
 // Parameters:
-//   - min: New minimum level (Debug, Info, Warn, Error)
+//   - min: New minimum level (Trace, Debug, Info, Warn, Error)
 //
 // Performance Notes:
 //   - Atomic operation with no locks or allocations
@@ -499,6 +723,23 @@ func (l *Logger) SetLevel(min Level) { l.level.SetLevel(min) }
 // Thread Safety: Safe to call from multiple goroutines
 func (l *Logger) Level() Level { return l.level.Level() }
 
+// Check reports whether a record at level, logged from the calling code,
+// passes the level filter: the logger level and the SetSourceLevel rules
+// for the caller's package. It also reports true while a debug session is
+// active, since a session may capture the record by its fields, and does
+// not consult the sampler. Use it to skip building fields that are expensive to compute:
+//
+//	if logger.Check(iris.Debug) {
+//	    logger.Debug("cache state", iris.Str("dump", cache.Dump()))
+//	}
+func (l *Logger) Check(level Level) bool {
+	min := l.level.Level()
+	if l.sources.active() {
+		min = l.sources.levelFor(callerPackage(2+l.opts.callerSkip), min)
+	}
+	return level.Enabled(min) || l.sessions.active()
+}
+
 // AtomicLevel returns a pointer to the logger's atomic level.
 //
 // This method provides access to the underlying atomic level structure,
@@ -521,6 +762,11 @@ func (l *Logger) Level() Level { return l.level.Level() }
 // Thread Safety: The returned AtomicLevel is thread-safe
 func (l *Logger) AtomicLevel() *AtomicLevel { return &l.level }
 
+// Name returns the name of the logger, set by Config.Name and Named
+// ("" = unnamed). Adapters writing records with Write use it as the
+// default Record.Logger.
+func (l *Logger) Name() string { return l.name }
+
 // WithOptions creates a new logger with the specified options applied.
 //
 // This method clones the current logger and applies additional configuration
@@ -567,6 +813,25 @@ func (l *Logger) WithOptions(opts ...Option) *Logger {
 		name:       l.name,
 		baseFields: l.baseFields,
 		opts:       newOpts,
+		latency:    l.latency,
+		drops:      l.drops,
+		templates:  l.templates,
+		rates:      l.rates,
+		pressure:   l.pressure,
+
+		runtimeHooks: l.runtimeHooks,
+		summary:      l.summary,
+		emergency:    l.emergency,
+		watchdog:     l.watchdog,
+		clockDrift:   l.clockDrift,
+		profile:      l.profile,
+		samplerState: l.samplerState,
+		errs:         l.errs,
+		sessions:     l.sessions,
+		sources:      l.sources,
+		verbosity:    l.verbosity,
+		dynFields:    l.dynFields,
+		configFields: l.configFields,
 	}
 	return clone
 }
@@ -607,14 +872,33 @@ func (l *Logger) With(fields ...Field) *Logger {
 	}
 
 	clone := &Logger{
-		r:       l.r,
-		out:     l.out,
-		enc:     l.enc,
-		level:   l.level,
-		clock:   l.clock,
-		sampler: l.sampler,
-		name:    l.name,
-		opts:    l.opts,
+		r:         l.r,
+		out:       l.out,
+		enc:       l.enc,
+		level:     l.level,
+		clock:     l.clock,
+		sampler:   l.sampler,
+		name:      l.name,
+		opts:      l.opts,
+		latency:   l.latency,
+		drops:     l.drops,
+		templates: l.templates,
+		rates:     l.rates,
+		pressure:  l.pressure,
+
+		runtimeHooks: l.runtimeHooks,
+		summary:      l.summary,
+		emergency:    l.emergency,
+		watchdog:     l.watchdog,
+		clockDrift:   l.clockDrift,
+		profile:      l.profile,
+		samplerState: l.samplerState,
+		errs:         l.errs,
+		sessions:     l.sessions,
+		sources:      l.sources,
+		verbosity:    l.verbosity,
+		dynFields:    l.dynFields,
+		configFields: l.configFields,
 	}
 	// Append new fields to existing base fields
 	clone.baseFields = make([]Field, len(l.baseFields)+len(fields))
@@ -658,6 +942,25 @@ func (l *Logger) Named(name string) *Logger {
 		sampler:    l.sampler,
 		baseFields: l.baseFields,
 		opts:       l.opts,
+		latency:    l.latency,
+		drops:      l.drops,
+		templates:  l.templates,
+		rates:      l.rates,
+		pressure:   l.pressure,
+
+		runtimeHooks: l.runtimeHooks,
+		summary:      l.summary,
+		emergency:    l.emergency,
+		watchdog:     l.watchdog,
+		clockDrift:   l.clockDrift,
+		profile:      l.profile,
+		samplerState: l.samplerState,
+		errs:         l.errs,
+		sessions:     l.sessions,
+		sources:      l.sources,
+		verbosity:    l.verbosity,
+		dynFields:    l.dynFields,
+		configFields: l.configFields,
 	}
 	if l.name == "" {
 		clone.name = name
@@ -678,6 +981,8 @@ func (l *Logger) Named(name string) *Logger {
 //
 // Returns:
 //   - bool: true if record was successfully queued, false if ring buffer full
+//     or the logger is closed. A record not queued is counted as dropped
+//     (DropRingFull or DropClosed, at Info level) in Stats and DroppedBy.
 //
 // Performance Features:
 //   - Zero heap allocations during normal operation
@@ -695,20 +1000,141 @@ func (l *Logger) Named(name string) *Logger {
 //
 // Thread Safety: Safe to call from multiple goroutines
 func (l *Logger) Write(fill func(*Record)) bool {
-	return l.r.Write(func(slot *Record) {
-		slot.resetForWrite()
-		fill(slot)
-	})
+	if l.write(fill) {
+		return true
+	}
+	l.recordRingDrop(Info) // The fill never ran: the level is unknown
+	return false
+}
+
+// write queues a record like Write but leaves a failure unaccounted. The
+// drop summary uses it: a summary that finds no slot is kept for the next
+// attempt, so it is not a dropped record.
+func (l *Logger) write(fill func(*Record)) bool {
+	if l.r.state.Load() == int32(StateNew) {
+		l.reportNotStarted()
+	}
+	return l.r.Write(func(slot *Record) { l.fillSlot(slot, fill) })
+}
+
+// WriteBatch writes a batch of records, one per fill function, claiming
+// ring slots in contiguous blocks.
+//
+// It is meant for importers and replay tools that log many records at
+// once: a block of slots costs one atomic claim instead of one per record,
+// and the fill functions run back to back. Records keep the order of fills.
+// Like Write, each fill populates a Record directly (see Record.SetTime to
+// keep original timestamps).
+//
+// Parameters:
+//   - fills: One fill function per record
+//
+// Returns:
+//   - int: Number of records queued, in order (fills[:n] were written).
+//     Under DropOnFull the batch stops when the ring is full; under
+//     BlockOnFull it waits for room, so fewer records are queued only once
+//     the logger is closed. Records not queued are counted as dropped
+//     (DropRingFull or DropClosed) in Stats and DroppedBy.
+//
+// Example:
+//
+//	fills := make([]func(*iris.Record), len(events))
+//	for i, ev := range events {
+//	    fills[i] = func(r *iris.Record) {
+//	        r.Level = iris.Info
+//	        r.Msg = ev.Message
+//	        r.SetTime(ev.Timestamp)
+//	    }
+//	}
+//	if n := logger.WriteBatch(fills); n < len(fills) {
+//	    retry(events[n:])
+//	}
+//
+// Thread Safety: Safe to call from multiple goroutines; records of
+// concurrent batches may interleave between blocks
+func (l *Logger) WriteBatch(fills []func(*Record)) int {
+	if len(fills) == 0 {
+		return 0
+	}
+	if l.r.state.Load() == int32(StateNew) {
+		l.reportNotStarted()
+	}
+	n := l.r.WriteBatch(len(fills), func(i int, slot *Record) { l.fillSlot(slot, fills[i]) })
+	if n < len(fills) {
+		l.recordBatchDrop(len(fills) - n)
+	}
+	return n
+}
+
+// recordBatchDrop accounts for the records of a batch that found no slot,
+// like recordRingDrop does for single records. Their fill functions never
+// ran, so they are counted at Info level.
+func (l *Logger) recordBatchDrop(count int) {
+	reason := DropRingFull
+	closed := l.r.Closed()
+	if closed {
+		reason = DropClosed
+	}
+	for i := 0; i < count; i++ {
+		l.countDropped()
+		l.recordDrop(reason, Info)
+	}
+	if closed && l.opts.strictLifecycle {
+		l.reportClosed()
+	}
+}
+
+// fillSlot prepares a ring slot for a Write, WriteCtx or WriteBatch fill
+// function and applies the logger options that do not depend on the caller.
+func (l *Logger) fillSlot(slot *Record, fill func(*Record)) {
+	if l.opts.recordDebug {
+		l.checkIdleSlot(slot)
+	}
+	slot.resetForWrite()
+	fill(slot)
+	if l.opts.ingestionKey != "" && !slot.time.IsZero() {
+		slot.AddField(TimeField(l.opts.ingestionKey, l.clock()))
+	}
+	if l.opts.scrubPaths {
+		slot.Caller = scrubString(slot.Caller)
+		slot.Stack = scrubString(slot.Stack)
+	}
+	if l.latency != nil {
+		slot.enqueued = latencyNow()
+	}
+	if l.opts.recordDebug {
+		sealRecord(slot) // Verified by the consumer before encoding
+	}
+}
+
+// reportNotStarted reports, once per ring, that records are being logged
+// to a logger whose consumer was never started. Inline loggers process
+// records in the caller and need no consumer. Once reported, each call
+// costs a load rather than a failed CAS.
+func (l *Logger) reportNotStarted() {
+	if l.r.inline != nil || l.r.notStartedReported.Load() || !l.r.notStartedReported.CompareAndSwap(false, true) {
+		return
+	}
+	l.reportError(NewLoggerErrorWithField(ErrLoggerNotStarted.Code,
+		"records are buffered but never written: call Start() or leave Config.AutoStart enabled", "logger", l.name))
 }
 
 // shouldLog performs fast level and sampling checks.
 //
 // This method determines whether a log message should be processed based
 // on the current minimum level and any configured sampling strategy.
-// It's optimized for hot paths with minimal overhead.
+// Records carrying a NoSample() marker (in fields or base fields) bypass
+// the sampler; level filtering always applies. Rejections are counted by
+// drop reason (level rejections only with WithLevelDropCounting, KeySampler
+// rejections as DropBudget).
 //
 // Parameters:
+//   - depth: Frames between shouldLog and the public logging method, used
+//     to find the calling package for SetSourceLevel rules
 //   - level: Level of the message to check
+//   - msg: Message (or format string), inspected by a MessageSampler
+//   - fields: Call-site fields, inspected by a KeySampler and when the
+//     sampler rejects
 //
 // Returns:
 //   - bool: true if the message should be logged
@@ -718,11 +1144,44 @@ func (l *Logger) Write(fill func(*Record)) bool {
 //   - Early return on level filtering
 //   - Optional sampling integration
 //   - Branch prediction friendly
-func (l *Logger) shouldLog(level Level) bool {
-	if level < l.level.Level() {
+func (l *Logger) shouldLog(depth int, level Level, msg string, fields []Field) bool {
+	min := l.level.Level()
+	if l.sources.active() {
+		min = l.sources.levelFor(callerPackage(3+depth+l.opts.callerSkip), min)
+	}
+	if !level.Enabled(min) {
+		if l.sessions.active() && l.sessions.lookup(l.currentBaseFields(), fields) != nil {
+			return true // Captured by a debug session
+		}
+		if l.opts.countLevelDrops {
+			l.recordDrop(DropLevel, level)
+		}
 		return false
 	}
-	if l.sampler != nil && !l.sampler.Allow(level) {
+	return l.allowSampled(level, msg, fields)
+}
+
+// allowSampled applies the sampler to a record that passed the level
+// check, honoring NoSample markers and debug sessions, and counts the
+// rejection.
+func (l *Logger) allowSampled(level Level, msg string, fields []Field) bool {
+	if l.sampler == nil {
+		return true
+	}
+	base := l.currentBaseFields()
+	reason := DropSampled
+	var allowed bool
+	switch s := l.sampler.(type) {
+	case *KeySampler:
+		reason, allowed = DropBudget, s.AllowFields(level, base, fields)
+	case MessageSampler:
+		allowed = s.AllowMessage(level, msg)
+	default:
+		allowed = l.sampler.Allow(level)
+	}
+	if !allowed && !hasNoSample(fields) && !hasNoSample(base) &&
+		(!l.sessions.active() || l.sessions.lookup(base, fields) == nil) {
+		l.recordDrop(reason, level)
 		return false
 	}
 	return true
@@ -752,35 +1211,62 @@ func (l *Logger) shouldLog(level Level) bool {
 
 func (l *Logger) log(level Level, msg string, fields ...Field) bool {
 	// ULTRA-FAST PATH: Early exit for disabled levels
-	if !l.shouldLog(level) {
+	if !l.shouldLog(1, level, msg, fields) {
 		return true
 	}
+	return l.emit(context.Background(), 1, level, msg, fields...)
+}
+
+// emit writes a record that already passed shouldLog. Callers that check
+// early (Info, logf) use it so the sampler sees each record exactly once.
+// depth is the number of frames between emit and the public logging
+// method, so that caller and stack capture skip them. ctx bounds the wait
+// for a free slot under BlockOnFull.
+func (l *Logger) emit(ctx context.Context, depth int, level Level, msg string, fields ...Field) bool {
+	if l.r.state.Load() == int32(StateNew) {
+		l.reportNotStarted()
+	}
 
 	// OPTIMIZED PATH: Check if we need any expensive operations
 	needsCaller := l.opts.addCaller
-	needsStack := l.opts.stackMin != StacktraceDisabled && level >= l.opts.stackMin
-	hasBaseFields := len(l.baseFields) > 0
+	needsStack := l.opts.stackMin != StacktraceDisabled && level.Enabled(l.opts.stackMin)
+	base := l.currentBaseFields()
+	hasBaseFields := len(base) > 0
 	hasFields := len(fields) > 0
+	hasProviders := len(l.opts.providers) > 0
 
 	// FAST PATH: Simple case with no extra work
-	if !needsCaller && !needsStack && !hasBaseFields && !hasFields {
-		ok := l.r.Write(func(slot *Record) {
+	if !needsCaller && !needsStack && !hasBaseFields && !hasFields && !hasProviders {
+		return l.writeSlot(ctx, level, func(slot *Record) {
+			if l.opts.recordDebug {
+				l.checkIdleSlot(slot)
+			}
 			slot.resetForWrite()
 			slot.Level = level
 			slot.Msg = msg
 			slot.Logger = l.name
 			slot.n = 0
+			if l.latency != nil {
+				slot.enqueued = latencyNow()
+			}
 		})
-		if !ok {
-			l.dropped.Add(1)
-		}
-		return ok
 	}
 
 	// COMPLEX PATH: Handle additional fields and context
+	session := l.sessions.lookup(base, fields)
+	sessionOnly := false
+	if session != nil {
+		// Same minimum level as shouldLog, SetSourceLevel rules included
+		min := l.level.Level()
+		if l.sources.active() {
+			min = l.sources.levelFor(callerPackage(3+depth+l.opts.callerSkip), min)
+		}
+		sessionOnly = !level.Enabled(min)
+	}
 	var callerField Field
 	var stackField Field
 	var hasCallerField, hasStackField bool
+	lost := int32(0) // Caller and stack left out because the record is full
 
 	// NOTE: This function is the unexpected heart of our ~10ns benchmark. It originated
 	// not from a quest for micro-optimization, but from a refactoring effort to reduce its
@@ -792,23 +1278,38 @@ func (l *Logger) log(level Level, msg string, fields ...Field) bool {
 	// that extreme performance is often a surprising byproduct of writing simple, disciplined,
 	// and high-quality code. Handle with care.
 	// #nosec G115 - len() result is bounded by field limits, safe conversion
-	total := int32(len(l.baseFields))
+	total := int32(len(base) + len(l.opts.providers))
 
 	if needsCaller && total < maxFields {
-		if c, ok := shortCaller(3 + l.opts.callerSkip); ok {
+		if c, ok := shortCaller(3 + depth + l.opts.callerSkip); ok {
+			if l.opts.scrubPaths {
+				c = scrubString(c)
+			}
 			callerField = Str("caller", c)
 			hasCallerField = true
 			total++
 		}
+	} else if needsCaller {
+		lost++
 	}
-	if needsStack && total < maxFields {
-		st := fastStacktrace(3 + l.opts.callerSkip) // Skip logging infrastructure frames
+	if needsStack && total >= maxFields {
+		lost++
+	} else if needsStack {
+		var st string
+		if l.opts.scrubPaths {
+			st = scrubbedStacktrace(3 + depth + l.opts.callerSkip) // Import-path-relative frames
+		} else {
+			st = fastStacktrace(3 + depth + l.opts.callerSkip) // Skip logging infrastructure frames
+		}
 		stackField = String("stack", st)
 		hasStackField = true
 		// Note: total++ removed as assignment was ineffectual (staticcheck)
 	}
 
-	ok := l.r.Write(func(slot *Record) {
+	return l.writeSlot(ctx, level, func(slot *Record) {
+		if l.opts.recordDebug {
+			l.checkIdleSlot(slot)
+		}
 		slot.resetForWrite()
 		slot.Level = level
 		slot.Msg = msg
@@ -816,10 +1317,24 @@ func (l *Logger) log(level Level, msg string, fields ...Field) bool {
 
 		pos := int32(0)
 		// Add base fields
-		for i := 0; i < len(l.baseFields) && pos < maxFields; i++ {
-			slot.fields[pos] = l.baseFields[i]
+		for i := 0; i < len(base) && pos < maxFields; i++ {
+			switch base[i].T {
+			case kindNoSample:
+				continue
+			case kindIngestTime:
+				slot.time = base[i].ingestTime()
+				continue
+			}
+			slot.fields[pos] = base[i]
 			pos++
 		}
+		// Add provider fields (host info, runtime stats, ...)
+		for i := 0; i < len(l.opts.providers) && pos < maxFields; i++ {
+			if level.Enabled(l.opts.providers[i].min) {
+				slot.fields[pos] = l.opts.providers[i].fn()
+				pos++
+			}
+		}
 		// Add caller field
 		if hasCallerField && pos < maxFields {
 			slot.fields[pos] = callerField
@@ -832,17 +1347,116 @@ func (l *Logger) log(level Level, msg string, fields ...Field) bool {
 		}
 		// Add provided fields
 		for i := 0; i < len(fields) && pos < maxFields; i++ {
-			slot.fields[pos] = fields[i]
+			switch fields[i].T {
+			case kindNoSample:
+				continue
+			case kindIngestTime:
+				slot.time = fields[i].ingestTime()
+				continue
+			}
+			if l.opts.scrubPaths {
+				slot.fields[pos] = scrubField(fields[i])
+			} else {
+				slot.fields[pos] = fields[i]
+			}
+			pos++
+		}
+		if session != nil {
+			if pos < maxFields {
+				slot.fields[pos] = Str(SessionKey, session.id)
+				pos++
+			}
+			slot.session = session
+			slot.sessionOnly = sessionOnly
+		}
+		if l.opts.ingestionKey != "" && !slot.time.IsZero() && pos < maxFields {
+			slot.fields[pos] = TimeField(l.opts.ingestionKey, l.clock())
 			pos++
 		}
 		slot.n = pos
+		if pos == maxFields || lost > 0 {
+			slot.lost = lost + l.overflow(slot, base, fields, hasCallerField, hasStackField, session != nil)
+		}
+		if l.opts.recordDebug {
+			sealBorrowed(slot) // UnsafeString bytes, verified by the consumer
+		}
+		if l.latency != nil {
+			slot.enqueued = latencyNow()
+		}
 	})
-	if !ok {
-		l.dropped.Add(1)
+}
+
+// overflow returns the number of fields that did not fit in a full record
+// filled by the write path from base and fields: the fields it would have
+// stored beyond maxFields.
+func (l *Logger) overflow(slot *Record, base, fields []Field, caller, stack, session bool) int32 {
+	n := int32(0)
+	for _, list := range [2][]Field{base, fields} {
+		for i := range list {
+			if list[i].T != kindNoSample && list[i].T != kindIngestTime {
+				n++
+			}
+		}
 	}
-	return ok
+	for i := range l.opts.providers {
+		if slot.Level.Enabled(l.opts.providers[i].min) {
+			n++
+		}
+	}
+	for _, b := range [3]bool{caller, stack, session} {
+		if b {
+			n++
+		}
+	}
+	if l.opts.ingestionKey != "" && !slot.time.IsZero() {
+		n++
+	}
+	return max(n-maxFields, 0)
+}
+
+// writeSlot writes a record through fill and accounts for a failed write.
+// Under BlockOnFull the wait for a free slot ends when ctx is done, and the
+// record is then counted as DropCanceled.
+func (l *Logger) writeSlot(ctx context.Context, level Level, fill func(*Record)) bool {
+	if l.r.lane != nil && l.r.writeLane(level, fill) {
+		return true
+	}
+	if ctx.Done() == nil { // Cannot be canceled: plain write
+		if l.r.Write(fill) {
+			return true
+		}
+		l.recordRingDrop(level)
+		return false
+	}
+	err := l.r.WriteContext(ctx, fill)
+	switch {
+	case err == nil:
+		return true
+	case err == ctx.Err():
+		l.countDropped()
+		l.recordDrop(DropCanceled, level)
+	default:
+		l.recordRingDrop(level)
+	}
+	return false
 }
 
+// Trace logs a message at Trace level with structured fields.
+//
+// Trace level is intended for very fine-grained diagnostic output, such as
+// per-call tracing inside libraries. It sits below Debug and is normally
+// enabled only while investigating a specific problem.
+//
+// Parameters:
+//   - msg: Primary log message
+//   - fields: Structured key-value pairs (zero-allocation)
+//
+// Returns:
+//   - bool: true if successfully logged, false if dropped or filtered
+//
+// Performance: Optimized for zero allocations with pre-allocated field storage
+func (l *Logger) Trace(msg string, fields ...Field) bool { return l.log(Trace, msg, fields...) }
+
 // Debug logs a message at Debug level with structured fields.
 //
 // Debug level is intended for detailed diagnostic information useful
@@ -874,12 +1488,12 @@ func (l *Logger) Debug(msg string, fields ...Field) bool { return l.log(Debug, m
 // Performance: Zero allocations for simple messages, optimized fast path for messages with fields
 func (l *Logger) Info(msg string, fields ...Field) bool {
 	// ZAP'S EXACT PATTERN: Level check first, NO varargs access if disabled
-	if !l.shouldLog(Info) {
+	if !l.shouldLog(0, Info, msg, fields) {
 		return true // ZERO ALLOCATION: Never touch fields if disabled
 	}
 
 	// ENABLED PATH: Now we can safely use fields
-	return l.log(Info, msg, fields...)
+	return l.emit(context.Background(), 0, Info, msg, fields...)
 }
 
 // InfoFields logs a message at Info level with structured fields.
@@ -930,7 +1544,7 @@ func (l *Logger) Error(msg string, fields ...Field) bool { return l.log(Error, m
 // development while maintaining stability in production.
 //
 // Behavior:
-//   - Development mode: Logs and then panics
+//   - Development mode: Logs and then panics (see WithPanicCapture)
 //   - Production mode: Logs only (no panic)
 //
 // Parameters:
@@ -942,15 +1556,17 @@ func (l *Logger) Error(msg string, fields ...Field) bool { return l.log(Error, m
 func (l *Logger) DPanic(msg string, fields ...Field) bool {
 	ok := l.log(DPanic, msg, fields...)
 	if l.opts.development {
-		panic(msg)
+		l.panicOrCapture(DPanic, msg, fields)
 	}
 	return ok
 }
 
-// Panic logs a message at panic level and panics
+// Panic logs a message at panic level and panics (with WithPanicCapture,
+// records the panic and returns instead)
 func (l *Logger) Panic(msg string, fields ...Field) bool {
-	l.log(Panic, msg, fields...)
-	panic(msg)
+	ok := l.log(Panic, msg, fields...)
+	l.panicOrCapture(Panic, msg, fields)
+	return ok
 }
 
 // Fatal logs a message at fatal level and exits the program
@@ -976,11 +1592,36 @@ func (l *Logger) Fatal(msg string, fields ...Field) {
 //
 // Thread Safety: Safe to call from multiple goroutines
 func (l *Logger) Sync() error {
+	if l.opts.strictLifecycle && l.r.Closed() {
+		return ErrLoggerClosed
+	}
+	return l.sync()
+}
+
+// sync flushes the ring and syncs the outputs.
+func (l *Logger) sync() error {
 	// Flush the ring buffer to ensure all records are processed
 	if err := l.r.Flush(); err != nil {
 		return fmt.Errorf("ring buffer flush failed: %w", err)
 	}
 
+	// Sync the restricted classification sink, if any
+	if l.opts.classifier != nil && l.opts.classifier.restricted != nil {
+		if err := l.opts.classifier.restricted.Sync(); err != nil {
+			return err
+		}
+	}
+
+	// Sync the additional outputs (WithOutput, WithSyncWriter, WithSessionOutput)
+	if err := l.syncOutputs(); err != nil {
+		return err
+	}
+	if l.opts.sessionOut != nil {
+		if err := l.opts.sessionOut.Sync(); err != nil {
+			return err
+		}
+	}
+
 	// Sync the output if it supports synchronization
 	if syncer, ok := l.out.(interface{ Sync() error }); ok {
 		return syncer.Sync()
@@ -989,6 +1630,9 @@ func (l *Logger) Sync() error {
 	return nil
 }
 
+// Tracef logs a message at trace level using printf-style formatting
+func (l *Logger) Tracef(format string, args ...any) bool { return l.logf(Trace, format, args...) }
+
 // Debugf logs a message at debug level using printf-style formatting
 func (l *Logger) Debugf(format string, args ...any) bool { return l.logf(Debug, format, args...) }
 
@@ -1018,13 +1662,13 @@ func (l *Logger) Errorf(format string, args ...any) bool { return l.logf(Error,
 // Performance Note: Uses strings.Builder for efficient string construction
 // but still allocates memory for the final formatted string.
 func (l *Logger) logf(level Level, format string, args ...any) bool {
-	if !l.shouldLog(level) {
+	if !l.shouldLog(1, level, format, nil) {
 		return true
 	}
 	var sb strings.Builder
 	sb.Grow(len(format) + 32)
 	sb.WriteString(fmt.Sprintf(format, args...))
-	return l.log(level, sb.String())
+	return l.emit(context.Background(), 1, level, sb.String())
 }
 
 // Stats returns comprehensive performance statistics for monitoring.
@@ -1049,16 +1693,130 @@ func (l *Logger) logf(level Level, format string, args ...any) bool {
 //   - "utilization_percent": Buffer utilization percentage
 //   - Additional ring buffer specific statistics
 //
+// Drops by logging calls are also broken down by cause in "dropped_ring_full",
+// "dropped_closed", "dropped_sampled", "dropped_level" (WithLevelDropCounting
+// only), "dropped_max_age", "dropped_budget", "dropped_canceled",
+// "dropped_filtered" and "dropped_shutdown"; these counters are shared by
+// a logger and all loggers derived from it.
+//
+// "ring_cas_retries" and "ring_full_encounters" measure producer contention
+// in the ring itself: slot claims that lost a CAS to another goroutine and
+// had to retry, and writes that found the ring full. A high retry rate
+// relative to "processed" suggests ThreadedRings; frequent full encounters
+// suggest a larger Capacity or a faster output.
+//
+// With Config.MaxCapacity, "capacity" is the current capacity of the
+// elastic ring, and "ring_max_capacity" and "ring_grows" report its limit
+// and how many times it has doubled.
+//
+// With WithPriorityLane, "lane_capacity", "lane_size", "lane_processed" and
+// "lane_overflow" report the priority lane: its capacity, the records
+// waiting in it and processed from it, and the Warn+ records that found it
+// full and went to the main ring.
+//
+// With WithLatencyHistograms, "encode_latency_*" and "e2e_latency_*" keys
+// report count, mean, p50, p90, p99, p999 and max in nanoseconds.
+//
+// With WithTemplateAnalysis, "templates", "templates_sampled" and
+// "templates_overflow" report the message template analyzer state.
+//
+// With WithRateMetrics, "rate.<name>.records_per_sec_1m" and "_5m", and
+// "rate.<name>.bytes_per_sec_1m" and "_5m" report the rolling rates of
+// every logger name seen (see Logger.Rates for exact values).
+//
+// With hooks, "hook_errors" counts errors returned by Hook.Run. With
+// WithAsyncHook, "hook_dropped" and "hook_panics" count records that did
+// not fit in an async hook queue and hook calls that panicked.
+//
+// "emergency_flushes" counts EmergencyFlush calls once there has been one,
+// or from the start with WithEmergencyFlush.
+//
+// With a DedupSampler, "dedup_kept" and "dedup_sampled" count the records
+// it let through and rejected; rejections are also in "dropped_sampled".
+//
+// With WithClockDriftCheck, "clock_drift_events", "clock_drift_ns",
+// "clock_drift_max_ns" and "clock_corrected" report the cached clock drift
+// checks.
+//
+// Once Errors has been called, "errors_overflow" counts the errors
+// discarded because its channel was full.
+//
+// Sinks implementing DeliveryReporter (network writers such as GELF,
+// syslog and CloudWatch) add "sink.<name>.sent", "sink.<name>.acked",
+// "sink.<name>.retried" and "sink.<name>.dropped", where name is "output"
+// for Config.Output or the pipeline sink name (see DeliveryReporter).
+//
 // Performance: Atomic reads with zero allocations for metric collection
 func (l *Logger) Stats() map[string]int64 {
 	ringStats := l.r.Stats()
-	return map[string]int64{
+	stats := map[string]int64{
 		"capacity":     ringStats["capacity"],
 		"batch_size":   ringStats["batch_size"],
 		"size":         ringStats["items_buffered"],
 		"processed":    ringStats["items_processed"],
 		"ring_dropped": ringStats["items_dropped"],
-		"dropped":      l.dropped.Load(),
+		"dropped":      l.droppedCount(),
+
+		"ring_cas_retries":     ringStats["cas_retries"],
+		"ring_full_encounters": ringStats["full_encounters"],
+	}
+	if grows, ok := ringStats["grows"]; ok {
+		stats["ring_max_capacity"] = ringStats["max_capacity"]
+		stats["ring_grows"] = grows
+	}
+	if laneCapacity, ok := ringStats["lane_capacity"]; ok {
+		stats["lane_capacity"] = laneCapacity
+		stats["lane_size"] = ringStats["lane_size"]
+		stats["lane_processed"] = ringStats["lane_processed"]
+		stats["lane_overflow"] = ringStats["lane_overflow"]
+	}
+	if l.drops != nil {
+		l.drops.addTo(stats)
+	}
+	if l.latency != nil {
+		l.latency.addTo(stats)
+	}
+	if l.templates != nil {
+		l.templates.addTo(stats)
+	}
+	if l.rates != nil {
+		l.rates.addTo(stats)
+	}
+	l.addHookStats(stats)
+	l.addAsyncHookStats(stats)
+	if n := l.emergency.flushes.Load(); n > 0 || l.emergency.cfg != nil {
+		stats["emergency_flushes"] = n
+	}
+	if ds, ok := l.sampler.(*DedupSampler); ok {
+		ds.addStats(stats)
+	}
+	if l.clockDrift != nil {
+		l.clockDrift.addStats(stats)
+	}
+	if l.errorsWanted() {
+		stats["errors_overflow"] = l.errs.overflow.Load()
+	}
+	l.addDeliveryStats(stats)
+	return stats
+}
+
+// finishRecord runs the consumer's bookkeeping once rec has been written
+// (latency, template analysis, hooks) and releases the slot.
+func (l *Logger) finishRecord(rec *Record) {
+	if l.latency != nil && rec.enqueued != 0 {
+		l.latency.endToEnd.Record(time.Duration(latencyNow() - rec.enqueued))
+	}
+	if l.templates != nil {
+		l.templates.observe(rec)
+	}
+	// Hooks nel consumer (niente contend)
+	for _, h := range l.opts.hooks {
+		h.fire(rec)
+	}
+	l.runtimeHooks.run(rec)
+	rec.resetForWrite()
+	if l.opts.recordDebug {
+		sealRecord(rec) // Detect writes through retained pointers on reuse
 	}
 }
 
@@ -1082,7 +1840,7 @@ func shortCaller(skip int) (string, bool) {
 		buf := bufferpool.Get()
 		buf.WriteString(file)
 		buf.WriteByte(':')
-		buf.WriteString(strconv.Itoa(line))
+		writeInt(buf, int64(line))
 		caller := buf.String()
 		bufferpool.Put(buf)
 		return caller, true
@@ -1095,7 +1853,7 @@ func shortCaller(skip int) (string, bool) {
 		buf := bufferpool.Get()
 		buf.WriteString(file)
 		buf.WriteByte(':')
-		buf.WriteString(strconv.Itoa(line))
+		writeInt(buf, int64(line))
 		caller := buf.String()
 		bufferpool.Put(buf)
 		return caller, true
@@ -1105,7 +1863,7 @@ func shortCaller(skip int) (string, bool) {
 	buf := bufferpool.Get()
 	buf.WriteString(file[idx+1:])
 	buf.WriteByte(':')
-	buf.WriteString(strconv.Itoa(line))
+	writeInt(buf, int64(line))
 	caller := buf.String()
 	bufferpool.Put(buf)
 	return caller, true
@@ -304,3 +304,39 @@ func safeCloseLoggingMethodsLogger(t *testing.T, logger *Logger) {
 		t.Logf("Warning: Error closing logger in test: %v", err)
 	}
 }
+
+// TestLogger_TraceLevel tests Trace/Tracef output and filtering below Debug
+func TestLogger_TraceLevel(t *testing.T) {
+	buf := &logTestSyncer{}
+
+	logger, err := New(Config{
+		Level:   Trace,
+		Encoder: NewJSONEncoder(),
+		Output:  buf,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Start()
+	defer safeCloseLoggingMethodsLogger(t, logger)
+
+	logger.Trace("trace message", Int("step", 1))
+	logger.Tracef("trace %s", "formatted")
+	_ = logger.Sync()
+
+	output := buf.String()
+	if !strings.Contains(output, `"level":"trace"`) {
+		t.Errorf("Expected trace level name in output: %s", output)
+	}
+	if !strings.Contains(output, "trace message") || !strings.Contains(output, "trace formatted") {
+		t.Errorf("Expected trace messages in output: %s", output)
+	}
+
+	// Raising the level to Debug filters Trace
+	logger.SetLevel(Debug)
+	logger.Trace("hidden trace")
+	_ = logger.Sync()
+	if strings.Contains(buf.String(), "hidden trace") {
+		t.Error("Trace message should be filtered at Debug level")
+	}
+}
@@ -418,6 +418,36 @@ func TestLoggerWithCallerInfo(t *testing.T) {
 	}
 }
 
+// TestLoggerCallerPerEntryPoint verifies that every logging method reports
+// its own call site, whatever the internal call depth
+func TestLoggerCallerPerEntryPoint(t *testing.T) {
+	buf := &bufferedSyncer{}
+	logger, err := New(Config{Level: Debug, Encoder: NewJSONEncoder(), Output: buf, Capacity: 64}, WithCaller())
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer safeCloseIrisLogger(t, logger)
+
+	calls := map[string]func(){
+		"info":   func() { logger.Info("info") },
+		"fields": func() { logger.Info("fields", Int("n", 1)) },
+		"debug":  func() { logger.Debug("debug") },
+		"warn":   func() { logger.Warn("warn", Int("n", 1)) },
+		"infof":  func() { logger.Infof("infof %d", 1) },
+	}
+	for name, call := range calls {
+		call()
+		if err := logger.Sync(); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		output := buf.String()
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if last := lines[len(lines)-1]; !strings.Contains(last, `iris_test.go:`) {
+			t.Errorf("%s: caller does not point at the call site: %s", name, last)
+		}
+	}
+}
+
 // TestShortCaller tests the shortCaller function with various path scenarios
 func TestShortCaller(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,18 @@
+module github.com/agilira/iris/irisz
+
+go 1.24.5
+
+require (
+	github.com/agilira/iris v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/agilira/argus v1.0.1 // indirect
+	github.com/agilira/flash-flags v1.0.1 // indirect
+	github.com/agilira/go-errors v1.1.0 // indirect
+	github.com/agilira/go-timecache v1.0.1 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+)
+
+replace github.com/agilira/iris => ../
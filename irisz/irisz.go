@@ -0,0 +1,311 @@
+// irisz.go: zapcore.Core backed by an Iris logger
+//
+// This package lets a codebase written against go.uber.org/zap switch its
+// backend to Iris without touching its call sites: NewCore returns a
+// zapcore.Core that writes every zap entry to an Iris logger's ring buffer,
+// translating zap fields into Iris fields. zap keeps doing what it does
+// above the core (level checks, sampling, caller and stack capture, panics
+// and exits); Iris does the encoding and the writing.
+//
+// Usage:
+//
+//	logger, err := iris.New(iris.Config{Level: iris.Info, Output: os.Stdout})
+//	if err != nil {
+//	    return err
+//	}
+//	defer logger.Close()
+//
+//	zl := zap.New(irisz.NewCore(logger), zap.AddCaller())
+//	zl.Info("request served", zap.String("method", "GET"), zap.Int("status", 200))
+//
+// Scalar fields (strings, integers, floats, booleans, durations, times,
+// errors and stringers) are translated without allocating, so the core adds
+// no allocations to the ones zap itself makes for a call. Objects, arrays,
+// reflected values and inline marshalers are rendered to JSON first, which
+// allocates; so do binary and byte string fields, which are copied because
+// Iris encodes them after the call returns.
+//
+// This is a separate module so that the Iris core does not depend on zap.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package irisz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agilira/iris"
+)
+
+// Core is a zapcore.Core writing to an iris.Logger. Create it with NewCore.
+type Core struct {
+	logger *iris.Logger
+	fields []iris.Field // Fields added with With, translated
+	prefix string       // Key prefix of the namespaces opened with With, "a.b." (empty = none)
+	cache  *cache
+}
+
+// cache holds the values derived from zap entries that would otherwise be
+// rebuilt on every write. It is shared by a core and the cores derived
+// from it with With.
+type cache struct {
+	names   sync.Map  // zap logger name -> record logger name
+	callers sync.Map  // Caller PC -> trimmed file:line
+	writers sync.Pool // *writer
+}
+
+// writer carries one entry into Logger.Write. Its fill function is bound
+// once, when the writer is created, so that writing does not allocate a
+// closure.
+type writer struct {
+	core   *Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	fn     func(*iris.Record)
+}
+
+// fieldAdder is what translated fields are added to: a record, or the
+// fields of a core.
+type fieldAdder interface {
+	AddField(iris.Field) bool
+}
+
+// fieldList collects the fields of With.
+type fieldList []iris.Field
+
+// AddField implements fieldAdder.
+func (l *fieldList) AddField(f iris.Field) bool {
+	*l = append(*l, f)
+	return true
+}
+
+// NewCore returns a zapcore.Core that writes entries to logger.
+//
+// Entries are written with Logger.Write, so what zap already does above the
+// core is not done twice: the level is checked by zap through Enabled,
+// which follows the level of logger, and the sampler, WithCaller and the
+// fields attached to logger with With do not apply. Use zap's With,
+// sampling and AddCaller/AddStacktrace instead. Record.Logger is the zap
+// logger name (zap.Logger.Named), appended to the name of logger if it has
+// one, and the record is stamped by Iris when it is encoded.
+//
+// zap levels map onto the Iris levels of the same name; levels below
+// DebugLevel are Trace. Iris never panics or exits for a record written
+// through the core: zap does that after Write returns, for Panic, DPanic in
+// development and Fatal.
+//
+// Write returns nil for records the logger drops under backpressure; they
+// are counted in Logger.Stats like any other dropped record.
+//
+// Parameters:
+//   - logger: Logger the entries are written to
+//
+// Returns:
+//   - *Core: Core for zap.New
+func NewCore(logger *iris.Logger) *Core {
+	c := &cache{}
+	c.writers.New = func() any {
+		w := &writer{}
+		w.fn = w.fill
+		return w
+	}
+	return &Core{logger: logger, cache: c}
+}
+
+// Enabled implements zapcore.LevelEnabler, following the level of the Iris
+// logger.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return levelFromZap(level).Enabled(c.logger.Level())
+}
+
+// Level returns the minimum enabled level, for zapcore.LevelOf.
+func (c *Core) Level() zapcore.Level {
+	switch level := c.logger.Level(); {
+	case level <= iris.Debug:
+		return zapcore.DebugLevel
+	case level >= iris.Fatal:
+		return zapcore.FatalLevel
+	default:
+		return zapcore.Level(level) // Info to Panic have the same values
+	}
+}
+
+// With implements zapcore.Core. The fields are translated once, here.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	list := fieldList(slices.Clip(c.fields))
+	prefix := c.prefix
+	for i := range fields {
+		prefix = addZapField(&list, prefix, &fields[i])
+	}
+	return &Core{logger: c.logger, fields: list, prefix: prefix, cache: c.cache}
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w := c.cache.writers.Get().(*writer)
+	w.core, w.ent, w.fields = c, ent, fields
+	c.logger.Write(w.fn)
+	w.core, w.ent, w.fields = nil, zapcore.Entry{}, nil
+	c.cache.writers.Put(w)
+	return nil
+}
+
+// Sync implements zapcore.Core by syncing the Iris logger.
+func (c *Core) Sync() error {
+	return c.logger.Sync()
+}
+
+// fill writes the entry of w into rec.
+func (w *writer) fill(rec *iris.Record) {
+	c := w.core
+	rec.Level = levelFromZap(w.ent.Level)
+	rec.Msg = w.ent.Message
+	rec.Logger = c.name(w.ent.LoggerName)
+	if w.ent.Caller.Defined {
+		rec.Caller = c.caller(w.ent.Caller)
+	}
+	rec.Stack = w.ent.Stack
+	for i := range c.fields {
+		rec.AddField(c.fields[i])
+	}
+	prefix := c.prefix
+	for i := range w.fields {
+		prefix = addZapField(rec, prefix, &w.fields[i])
+	}
+}
+
+// name returns the record logger name for a zap logger name.
+func (c *Core) name(zapName string) string {
+	base := c.logger.Name()
+	switch {
+	case zapName == "":
+		return base
+	case base == "":
+		return zapName
+	}
+	if name, ok := c.cache.names.Load(zapName); ok {
+		return name.(string)
+	}
+	name, _ := c.cache.names.LoadOrStore(zapName, base+"."+zapName)
+	return name.(string)
+}
+
+// caller returns the trimmed file:line of a caller, as zap's encoders
+// write it.
+func (c *Core) caller(ec zapcore.EntryCaller) string {
+	if ec.PC == 0 {
+		return ec.TrimmedPath()
+	}
+	if s, ok := c.cache.callers.Load(ec.PC); ok {
+		return s.(string)
+	}
+	s, _ := c.cache.callers.LoadOrStore(ec.PC, ec.TrimmedPath())
+	return s.(string)
+}
+
+// levelFromZap maps a zap level onto the Iris level of the same name.
+func levelFromZap(level zapcore.Level) iris.Level {
+	switch {
+	case level < zapcore.DebugLevel:
+		return iris.Trace
+	case level > zapcore.FatalLevel:
+		return iris.Fatal
+	default:
+		return iris.Level(level) // Debug to Fatal have the same values
+	}
+}
+
+// addZapField adds the Iris translation of f to dst and returns the key
+// prefix for the fields after it, which a namespace extends.
+func addZapField(dst fieldAdder, prefix string, f *zapcore.Field) string {
+	key := f.Key
+	if prefix != "" {
+		key = prefix + key
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		dst.AddField(iris.Str(key, f.String))
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		dst.AddField(iris.Int64(key, f.Integer))
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		dst.AddField(iris.Uint64(key, uint64(f.Integer)))
+	case zapcore.Float64Type:
+		dst.AddField(iris.Float64(key, math.Float64frombits(uint64(f.Integer))))
+	case zapcore.Float32Type:
+		dst.AddField(iris.Float32(key, math.Float32frombits(uint32(f.Integer))))
+	case zapcore.BoolType:
+		dst.AddField(iris.Bool(key, f.Integer == 1))
+	case zapcore.DurationType:
+		dst.AddField(iris.Dur(key, time.Duration(f.Integer)))
+	case zapcore.TimeType:
+		t := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok {
+			t = t.In(loc)
+		}
+		dst.AddField(iris.TimeField(key, t))
+	case zapcore.TimeFullType:
+		dst.AddField(iris.TimeField(key, f.Interface.(time.Time)))
+	case zapcore.ErrorType:
+		dst.AddField(iris.NamedError(key, f.Interface.(error)))
+	case zapcore.StringerType:
+		dst.AddField(iris.Stringer(key, f.Interface.(fmt.Stringer)))
+	case zapcore.BinaryType:
+		// zap encodes before returning, so callers may reuse the slice;
+		// Iris encodes later
+		dst.AddField(iris.Bytes(key, slices.Clone(f.Interface.([]byte))))
+	case zapcore.ByteStringType:
+		dst.AddField(iris.Str(key, string(f.Interface.([]byte))))
+	case zapcore.Complex128Type:
+		dst.AddField(iris.Str(key, strconv.FormatComplex(f.Interface.(complex128), 'g', -1, 128)))
+	case zapcore.Complex64Type:
+		dst.AddField(iris.Str(key, strconv.FormatComplex(complex128(f.Interface.(complex64)), 'g', -1, 64)))
+	case zapcore.NamespaceType:
+		return key + "."
+	case zapcore.SkipType:
+	default:
+		addMarshaled(dst, prefix, f)
+	}
+	return prefix
+}
+
+// addMarshaled adds objects, arrays, reflected values and inline
+// marshalers: f is marshaled by zap into a map, whose entries are added as
+// JSON in key order.
+func addMarshaled(dst fieldAdder, prefix string, f *zapcore.Field) {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		data, err := json.Marshal(enc.Fields[k])
+		if err != nil {
+			dst.AddField(iris.Str(prefix+k, fmt.Sprint(enc.Fields[k])))
+			continue
+		}
+		dst.AddField(iris.RawJSON(prefix+k, data))
+	}
+}
@@ -0,0 +1,206 @@
+// irisz_test.go: Tests for the zapcore.Core adapter
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package irisz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agilira/iris"
+)
+
+// lineSyncer collects the encoded records.
+type lineSyncer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *lineSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *lineSyncer) Sync() error { return nil }
+
+// lines decodes the records written so far.
+func (s *lineSyncer) lines(t *testing.T) []map[string]any {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(s.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func newTestLogger(t *testing.T, name string) (*iris.Logger, *lineSyncer) {
+	t.Helper()
+	out := &lineSyncer{}
+	logger, err := iris.New(iris.Config{Level: iris.Info, Output: out, Encoder: iris.NewJSONEncoder(), Inline: true, Name: name})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, out
+}
+
+// user is an object marshaler.
+type user struct{ name string }
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.name)
+	return nil
+}
+
+func TestCore_Fields(t *testing.T) {
+	logger, out := newTestLogger(t, "")
+	zl := zap.New(NewCore(logger)).With(zap.String("service", "api"), zap.Namespace("req"))
+
+	when := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	zl.Info("served",
+		zap.String("method", "GET"),
+		zap.Int("status", 200),
+		zap.Uint8("retries", 2),
+		zap.Float32("ratio", 0.5),
+		zap.Bool("cached", true),
+		zap.Duration("took", 1500*time.Millisecond),
+		zap.Time("at", when),
+		zap.Error(errors.New("partial")),
+		zap.ByteString("body", []byte("ok")),
+		zap.Object("user", user{"ada"}),
+		zap.Ints("ids", []int{1, 2}),
+		zap.Any("meta", map[string]int{"a": 1}),
+		zap.Complex128("c", 1+2i),
+		zap.Skip(),
+	)
+
+	lines := out.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("got %d records, want 1", len(lines))
+	}
+	want := map[string]any{
+		"service":     "api",
+		"req.method":  "GET",
+		"req.status":  float64(200),
+		"req.retries": float64(2),
+		"req.ratio":   0.5,
+		"req.cached":  true,
+		"req.took":    float64(1500 * time.Millisecond),
+		"req.at":      "2025-09-02T12:00:00Z",
+		"req.error":   "partial",
+		"req.body":    "ok",
+		"req.c":       "(1+2i)",
+	}
+	for k, v := range want {
+		if lines[0][k] != v {
+			t.Errorf("%s = %#v, want %#v", k, lines[0][k], v)
+		}
+	}
+	if u, _ := lines[0]["req.user"].(map[string]any); u["name"] != "ada" {
+		t.Errorf("req.user = %#v, want the marshaled object", lines[0]["req.user"])
+	}
+	if ids, _ := lines[0]["req.ids"].([]any); len(ids) != 2 {
+		t.Errorf("req.ids = %#v, want the array", lines[0]["req.ids"])
+	}
+	if m, _ := lines[0]["req.meta"].(map[string]any); m["a"] != float64(1) {
+		t.Errorf("req.meta = %#v, want the reflected map", lines[0]["req.meta"])
+	}
+}
+
+func TestCore_EntryAndLevels(t *testing.T) {
+	logger, out := newTestLogger(t, "app")
+	core := NewCore(logger)
+	zl := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+
+	if zapcore.LevelOf(core) != zapcore.InfoLevel {
+		t.Errorf("LevelOf = %v, want info", zapcore.LevelOf(core))
+	}
+	zl.Debug("hidden")
+	zl.Named("db").Warn("slow query")
+	zl.Error("failed")
+	func() {
+		defer func() { _ = recover() }()
+		zl.Panic("boom") // zap panics, after the record was written
+	}()
+	logger.SetLevel(iris.Error)
+	zl.Warn("hidden after SetLevel")
+
+	lines := out.lines(t)
+	if len(lines) != 3 {
+		t.Fatalf("got %d records, want 3: %v", len(lines), lines)
+	}
+	tests := []struct {
+		msg, level, logger string
+		stack              bool
+	}{
+		{"slow query", "warn", "app.db", false},
+		{"failed", "error", "app", true},
+		{"boom", "panic", "app", true},
+	}
+	for i, tt := range tests {
+		m := lines[i]
+		if m["msg"] != tt.msg || m["level"] != tt.level || m["logger"] != tt.logger {
+			t.Errorf("record %d = %v, want %s at %s from %s", i, m, tt.msg, tt.level, tt.logger)
+		}
+		if caller, _ := m["caller"].(string); !strings.HasPrefix(caller, "irisz/irisz_test.go:") {
+			t.Errorf("record %d caller = %q, want this file", i, caller)
+		}
+		if _, ok := m["stack"]; ok != tt.stack {
+			t.Errorf("record %d stack present = %v, want %v", i, ok, tt.stack)
+		}
+	}
+}
+
+// acceptCore accepts every entry and does nothing with it: the cost of zap
+// itself.
+type acceptCore struct{}
+
+func (acceptCore) Enabled(zapcore.Level) bool                 { return true }
+func (c acceptCore) With([]zapcore.Field) zapcore.Core        { return c }
+func (acceptCore) Write(zapcore.Entry, []zapcore.Field) error { return nil }
+func (acceptCore) Sync() error                                { return nil }
+func (c acceptCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func TestCore_NoExtraAllocations(t *testing.T) {
+	logger, err := iris.New(iris.Config{Level: iris.Info, Output: iris.WrapWriter(io.Discard), Encoder: iris.NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	allocs := func(core zapcore.Core) float64 {
+		zl := zap.New(core, zap.AddCaller()).Named("api").With(zap.String("service", "api"))
+		logOnce := func() {
+			zl.Info("request served", zap.String("method", "GET"), zap.Int("status", 200), zap.Duration("took", time.Millisecond))
+		}
+		logOnce() // Fill the caller and name caches
+		return testing.AllocsPerRun(1000, logOnce)
+	}
+	if got, zapOnly := allocs(NewCore(logger)), allocs(acceptCore{}); got > zapOnly {
+		t.Errorf("zap call through the core allocates %.1f times, zap alone %.1f", got, zapOnly)
+	}
+}
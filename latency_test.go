@@ -0,0 +1,176 @@
+// latency_test.go: Tests for encode and end-to-end latency histograms
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLatencyBucketIndex(t *testing.T) {
+	tests := []struct {
+		value uint64
+		want  int
+	}{
+		{0, 0},
+		{7, 7},
+		{8, 8},
+		{9, 9},
+		{15, 15},
+		{16, 16},
+		{17, 16},
+		{18, 17},
+		{31, 23},
+		{32, 24},
+		{1<<63 - 1, latencyBucketCount - 9},
+		{1<<64 - 1, latencyBucketCount - 1},
+	}
+	for _, tt := range tests {
+		if got := latencyBucketIndex(tt.value); got != tt.want {
+			t.Errorf("latencyBucketIndex(%d) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+
+	// Every bucket's upper bound maps back to the same bucket
+	for i := 0; i < latencyBucketCount-8; i++ {
+		upper := latencyBucketUpper(i)
+		if got := latencyBucketIndex(uint64(upper)); got != i {
+			t.Fatalf("upper bound %d of bucket %d maps to bucket %d", upper, i, got)
+		}
+		if got := latencyBucketIndex(uint64(upper) + 1); got != i+1 {
+			t.Fatalf("value %d after bucket %d maps to bucket %d", upper+1, i, got)
+		}
+	}
+}
+
+func TestLatencyHistogram_Quantiles(t *testing.T) {
+	var h LatencyHistogram
+	if h.Quantile(0.99) != 0 || h.Mean() != 0 || h.Count() != 0 {
+		t.Fatal("empty histogram should report zeros")
+	}
+
+	// 1..1000 microseconds
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	h.Record(-time.Second) // Clamped to zero
+
+	if h.Count() != 1001 {
+		t.Errorf("Count = %d, want 1001", h.Count())
+	}
+	if h.Max() != time.Millisecond {
+		t.Errorf("Max = %v, want 1ms", h.Max())
+	}
+
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, 500 * time.Microsecond},
+		{0.90, 900 * time.Microsecond},
+		{0.99, 990 * time.Microsecond},
+		{1.00, time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := h.Quantile(tt.q)
+		// Buckets round up by at most 12.5%
+		if got < tt.want || float64(got) > float64(tt.want)*1.125 {
+			t.Errorf("Quantile(%v) = %v, want within 12.5%% above %v", tt.q, got, tt.want)
+		}
+	}
+	if got := h.Quantile(2); got != time.Millisecond {
+		t.Errorf("Quantile(2) = %v, want Max", got)
+	}
+
+	h.Reset()
+	if h.Count() != 0 || h.Max() != 0 || h.Quantile(0.5) != 0 {
+		t.Error("Reset did not clear the histogram")
+	}
+}
+
+func TestLatencyHistogram_Concurrent(t *testing.T) {
+	var h LatencyHistogram
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				h.Record(time.Duration(g*1000 + i))
+			}
+		}()
+	}
+	wg.Wait()
+	if h.Count() != 8000 {
+		t.Errorf("Count = %d, want 8000", h.Count())
+	}
+	if h.Max() != 7999 {
+		t.Errorf("Max = %d, want 7999", h.Max())
+	}
+}
+
+func TestLogger_LatencyHistograms(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:    Debug,
+		Output:   buf,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 1024,
+	}, WithLatencyHistograms())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+
+	logger.Info("fast path")
+	logger.With(Str("k", "v")).Warn("complex path")
+	logger.Named("db").Error("named", Int("n", 1))
+	logger.Write(func(r *Record) {
+		r.Level = Info
+		r.Msg = "direct write"
+	})
+	safeCloseWithOptionsLogger(t, logger)
+
+	stats := logger.Stats()
+	for _, key := range []string{"encode_latency_count", "e2e_latency_count"} {
+		if stats[key] != 4 {
+			t.Errorf("%s = %d, want 4", key, stats[key])
+		}
+	}
+	for _, key := range []string{"encode_latency_p99_ns", "e2e_latency_p99_ns", "e2e_latency_max_ns"} {
+		if stats[key] <= 0 {
+			t.Errorf("%s = %d, want > 0", key, stats[key])
+		}
+	}
+	if stats["e2e_latency_p50_ns"] > stats["e2e_latency_max_ns"] {
+		t.Error("p50 exceeds max")
+	}
+	if logger.EncodeLatency().Count() != 4 || logger.With().EndToEndLatency().Count() != 4 {
+		t.Error("histogram accessors should share state with clones")
+	}
+}
+
+func TestLogger_LatencyHistogramsDisabled(t *testing.T) {
+	logger, err := New(Config{
+		Level:    Debug,
+		Output:   &testSyncer{},
+		Encoder:  NewJSONEncoder(),
+		Capacity: 64,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	if logger.EncodeLatency() != nil || logger.EndToEndLatency() != nil {
+		t.Error("histograms should be nil when disabled")
+	}
+	if _, ok := logger.Stats()["e2e_latency_p99_ns"]; ok {
+		t.Error("latency keys should be absent when disabled")
+	}
+}
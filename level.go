@@ -13,7 +13,9 @@ import (
 )
 
 // Level represents the severity level of a log message.
-// Levels are ordered from least to most severe: Debug < Info < Warn < Error < DPanic < Panic < Fatal
+// Levels are ordered from least to most severe: Trace < Debug < Info < Warn < Error < DPanic < Panic < Fatal.
+// Custom levels registered with RegisterLevelAbove sort between them, so
+// levels are compared with Enabled rather than with < and >.
 //
 // Performance Notes:
 // - Level is implemented as int32 for fast comparisons
@@ -23,7 +25,8 @@ type Level int32
 
 // Log levels in order of increasing severity
 const (
-	Debug  Level = iota - 1 // Debug information, typically disabled in production
+	Trace  Level = iota - 2 // Very fine-grained tracing, finer than Debug
+	Debug                   // Debug information, typically disabled in production
 	Info                    // General information messages
 	Warn                    // Warning messages for potentially harmful situations
 	Error                   // Error messages for failure conditions
@@ -38,6 +41,7 @@ const (
 // levelNamesMap provides reverse lookup from string to level.
 // Pre-computed map for faster parsing operations.
 var levelNamesMap = map[string]Level{
+	"trace":   Trace,
 	"debug":   Debug,
 	"info":    Info,
 	"warn":    Warn,
@@ -54,6 +58,8 @@ var levelNamesMap = map[string]Level{
 // This is used for human-readable output and serialization.
 func (l Level) String() string {
 	switch l {
+	case Trace:
+		return "trace"
 	case Debug:
 		return "debug"
 	case Info:
@@ -69,14 +75,29 @@ func (l Level) String() string {
 	case Fatal:
 		return "fatal"
 	default:
+		// Custom levels registered via RegisterLevel
+		if name, ok := customLevelName(l); ok {
+			return name
+		}
 		return "unknown"
 	}
 }
 
-// Enabled determines if this level is enabled given a minimum level.
-// This is a critical hot path function optimized for maximum performance.
+// Enabled determines if this level is enabled given a minimum level, that
+// is whether l sorts at or above min in level order.
+// This is a critical hot path function optimized for maximum performance:
+// built-in levels compare by value, custom levels by their rank.
 func (l Level) Enabled(min Level) bool {
-	return l >= min
+	if isBuiltinLevel(l) && isBuiltinLevel(min) {
+		return l >= min
+	}
+	return levelRank(l) >= levelRank(min)
+}
+
+// IsTrace returns true if the level is Trace.
+// Convenience method for checking trace level.
+func (l Level) IsTrace() bool {
+	return l == Trace
 }
 
 // IsDebug returns true if the level is Debug.
@@ -133,6 +154,11 @@ func ParseLevel(s string) (Level, error) {
 		return level, nil
 	}
 
+	// Custom levels and aliases registered at runtime
+	if level, exists := customLevelValue(normalized); exists {
+		return level, nil
+	}
+
 	// Return error for unknown levels
 	return Info, fmt.Errorf("unknown level %q", s)
 }
@@ -187,7 +213,7 @@ func (al *AtomicLevel) SetLevel(level Level) {
 // Enabled checks if the given level is enabled atomically.
 // This is a high-performance method for checking levels in hot paths.
 func (al *AtomicLevel) Enabled(level Level) bool {
-	return level >= Level(atomic.LoadInt32(&al.level))
+	return level.Enabled(Level(atomic.LoadInt32(&al.level)))
 }
 
 // String returns the string representation of the current level.
@@ -251,9 +277,17 @@ func (lf *LevelFlag) Type() string {
 }
 
 // AllLevels returns a slice of all valid levels in ascending order.
+// Custom levels registered via RegisterLevel are merged in by severity.
 // This is useful for documentation, validation, and testing.
 func AllLevels() []Level {
-	return []Level{Debug, Info, Warn, Error}
+	levels := []Level{Trace, Debug, Info, Warn, Error}
+	custom := CustomLevels()
+	if len(custom) == 0 {
+		return levels
+	}
+	levels = append(levels, custom...)
+	sortLevels(levels)
+	return levels
 }
 
 // AllLevelNames returns a slice of all valid level names.
@@ -267,7 +301,11 @@ func AllLevelNames() []string {
 	return names
 }
 
-// IsValidLevel checks if the given level is a valid predefined level.
+// IsValidLevel checks if the given level is a valid predefined or registered level.
 func IsValidLevel(level Level) bool {
-	return level >= Debug && level <= Error
+	if level >= Trace && level <= Error {
+		return true
+	}
+	_, ok := customLevelName(level)
+	return ok
 }
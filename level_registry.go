@@ -0,0 +1,317 @@
+// level_registry.go: Custom level registration for Iris logging library
+//
+// Teams migrating from syslog-style or framework-specific level schemes often
+// need names and severities that do not exist in the built-in set (TRACE,
+// NOTICE, CRITICAL, ...). The registry lets applications declare additional
+// levels once at startup; ParseLevel, Level.String, the encoders, level
+// filtering and the config loader then treat them like built-in levels.
+//
+// Level order is not the order of level values: every level has a rank,
+// with the built-in levels levelRankStep apart, so that a custom level
+// registered with RegisterLevelAbove sorts between two built-in levels
+// whatever its value. Other levels rank by their value.
+//
+// Design:
+//   - Copy-on-write registry behind an atomic pointer: lookups on the
+//     encoding path never take a lock
+//   - Built-in levels are never consulted through the registry, so the
+//     common case pays nothing
+//   - Registrations are expected at init time; they are safe but not cheap
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Bounds for custom level values. Levels are serialized as a single signed
+// byte by the binary encoder, so custom values must fit in an int8.
+const (
+	minCustomLevel Level = -128
+	maxCustomLevel Level = 127
+)
+
+// levelRankStep separates the ranks of consecutive level values, leaving
+// room for the custom levels registered above a built-in level.
+const levelRankStep = 256
+
+// levelRegistry is an immutable snapshot of the registered custom levels.
+type levelRegistry struct {
+	names  map[Level]string // level value -> canonical name
+	values map[string]Level // lowercase name or alias -> level value
+	ranks  map[Level]int32  // level value -> rank, for RegisterLevelAbove levels
+}
+
+var (
+	// customLevels holds the current registry snapshot (nil until first registration)
+	customLevels atomic.Pointer[levelRegistry]
+
+	// registryMu serializes writers; readers only load the atomic pointer
+	registryMu sync.Mutex
+)
+
+// RegisterLevel registers a custom level with the given numeric value and name.
+//
+// The level participates in filtering by its numeric value: a level of -3 is
+// below Trace, a level of 6 is above Fatal. Once registered, the name is
+// accepted by ParseLevel, UnmarshalText, LevelFlag and the config loader, and
+// is emitted by every encoder through Level.String().
+//
+// Built-in levels occupy the consecutive values -2..5, so a custom level
+// ordered by its value can only be placed below Trace or above Fatal. Use
+// RegisterLevelAbove to place one between two built-in levels, or
+// RegisterLevelAlias to map a foreign name onto an existing severity.
+//
+// Parameters:
+//   - level: Numeric severity (must fit in an int8 and not collide with a built-in level)
+//   - name: Level name (case-insensitive, must not already be in use)
+//
+// Returns:
+//   - error: ErrCodeInvalidLevel if the value or name is not available
+//
+// Example:
+//
+//	const Critical = iris.Level(6)
+//	if err := iris.RegisterLevel(Critical, "critical"); err != nil {
+//	    return err
+//	}
+//	logger.Write(func(r *iris.Record) {
+//	    r.Level = Critical
+//	    r.Msg = "disk failure"
+//	})
+func RegisterLevel(level Level, name string) error {
+	return registerLevel(level, name, nil)
+}
+
+// RegisterLevelAbove registers a custom level that sorts just above base:
+// below the next built-in level, and above the levels registered above base
+// before it. The value only identifies the level (in records, the binary
+// encoding and level comparisons by value); filtering uses the order.
+//
+// Parameters:
+//   - level: Level value (must fit in an int8 and not collide with a built-in level)
+//   - name: Level name (case-insensitive, must not already be in use)
+//   - base: Built-in level the new level sorts above
+//
+// Returns:
+//   - error: ErrCodeInvalidLevel if the value or name is not available, base
+//     is not a built-in level, or base has no room left
+//
+// Example:
+//
+//	const Notice = iris.Level(10)
+//	if err := iris.RegisterLevelAbove(Notice, "notice", iris.Info); err != nil {
+//	    return err
+//	}
+//	logger.SetLevel(Notice) // Filters Info, keeps Notice and Warn
+func RegisterLevelAbove(level Level, name string, base Level) error {
+	if !isBuiltinLevel(base) {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level base must be a built-in level", "base", fmt.Sprintf("%d", int(base)))
+	}
+	return registerLevel(level, name, &base)
+}
+
+// registerLevel registers a custom level, ranked just above *base when base
+// is not nil and by its value otherwise.
+func registerLevel(level Level, name string, base *Level) error {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level name cannot be empty", "name", name)
+	}
+	if level < minCustomLevel || level > maxCustomLevel {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level value must fit in an int8", "level", fmt.Sprintf("%d", int(level)))
+	}
+	if isBuiltinLevel(level) {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level value collides with a built-in level", "level", fmt.Sprintf("%d", int(level)))
+	}
+	if _, builtin := levelNamesMap[normalized]; builtin {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level name collides with a built-in level", "name", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	current := customLevels.Load()
+	if current != nil {
+		if existing, ok := current.names[level]; ok {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level value already registered as "+existing, "level", fmt.Sprintf("%d", int(level)))
+		}
+		if _, ok := current.values[normalized]; ok {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "custom level name already registered", "name", name)
+		}
+	}
+
+	next := current.clone()
+	if base != nil {
+		rank := levelRank(*base) + 1
+		for _, r := range next.ranks {
+			if r >= rank && r < levelRank(*base)+levelRankStep {
+				rank = r + 1
+			}
+		}
+		if rank == levelRank(*base)+levelRankStep {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "no room left above the custom level base", "base", base.String())
+		}
+		next.ranks[level] = rank
+	}
+	next.names[level] = normalized
+	next.values[normalized] = level
+	customLevels.Store(next)
+	return nil
+}
+
+// RegisterLevelAlias registers an additional name that parses to an existing level.
+//
+// Aliases only affect parsing (ParseLevel, config files, environment
+// variables, flags); output always uses the canonical name of the target
+// level. This is the way to accept foreign level vocabularies whose
+// severities already exist in Iris, e.g. syslog's "notice" or "crit".
+//
+// Parameters:
+//   - alias: Name to accept (case-insensitive, must not already be in use)
+//   - level: Target level (built-in or previously registered)
+//
+// Returns:
+//   - error: ErrCodeInvalidLevel if the alias is taken or the target is unknown
+//
+// Example:
+//
+//	_ = iris.RegisterLevelAlias("notice", iris.Info)
+//	_ = iris.RegisterLevelAlias("crit", iris.Error)
+func RegisterLevelAlias(alias string, level Level) error {
+	normalized := strings.ToLower(strings.TrimSpace(alias))
+	if normalized == "" {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "level alias cannot be empty", "alias", alias)
+	}
+	if _, builtin := levelNamesMap[normalized]; builtin {
+		return NewLoggerErrorWithField(ErrCodeInvalidLevel, "level alias collides with a built-in level name", "alias", alias)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	current := customLevels.Load()
+	if !isBuiltinLevel(level) {
+		if current == nil {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "level alias target is not a known level", "level", fmt.Sprintf("%d", int(level)))
+		}
+		if _, ok := current.names[level]; !ok {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "level alias target is not a known level", "level", fmt.Sprintf("%d", int(level)))
+		}
+	}
+	if current != nil {
+		if _, ok := current.values[normalized]; ok {
+			return NewLoggerErrorWithField(ErrCodeInvalidLevel, "level alias already registered", "alias", alias)
+		}
+	}
+
+	next := current.clone()
+	next.values[normalized] = level
+	customLevels.Store(next)
+	return nil
+}
+
+// CustomLevels returns the registered custom levels in level order (see
+// RegisterLevelAbove). Aliases are not included.
+func CustomLevels() []Level {
+	reg := customLevels.Load()
+	if reg == nil {
+		return nil
+	}
+	levels := make([]Level, 0, len(reg.names))
+	for level := range reg.names {
+		levels = append(levels, level)
+	}
+	sortLevels(levels)
+	return levels
+}
+
+// sortLevels sorts levels in level order.
+func sortLevels(levels []Level) {
+	sort.Slice(levels, func(i, j int) bool { return levelRank(levels[i]) < levelRank(levels[j]) })
+}
+
+// clone returns a mutable copy of the registry (a fresh one for nil receivers).
+func (r *levelRegistry) clone() *levelRegistry {
+	next := &levelRegistry{
+		names:  make(map[Level]string),
+		values: make(map[string]Level),
+		ranks:  make(map[Level]int32),
+	}
+	if r == nil {
+		return next
+	}
+	for k, v := range r.ranks {
+		next.ranks[k] = v
+	}
+	for k, v := range r.names {
+		next.names[k] = v
+	}
+	for k, v := range r.values {
+		next.values[k] = v
+	}
+	return next
+}
+
+// isBuiltinLevel reports whether the value belongs to a built-in level.
+func isBuiltinLevel(level Level) bool {
+	return level >= Trace && level <= Fatal
+}
+
+// levelRank returns the position of level in level order: built-in levels
+// and levels ordered by value at levelRankStep times their value, levels
+// registered with RegisterLevelAbove in between.
+func levelRank(level Level) int32 {
+	if !isBuiltinLevel(level) {
+		if reg := customLevels.Load(); reg != nil {
+			if rank, ok := reg.ranks[level]; ok {
+				return rank
+			}
+		}
+	}
+	return int32(level) * levelRankStep
+}
+
+// builtinBase returns the highest built-in level at or below level in
+// level order, for outputs that only know the built-in severities.
+func builtinBase(level Level) Level {
+	if isBuiltinLevel(level) {
+		return level
+	}
+	rank := levelRank(level)
+	switch {
+	case rank < int32(Trace)*levelRankStep:
+		return Trace
+	case rank > int32(Fatal)*levelRankStep:
+		return Fatal
+	}
+	return Level(rank >> 8) // Arithmetic shift: floor division by levelRankStep
+}
+
+// customLevelName returns the registered name for a custom level.
+func customLevelName(level Level) (string, bool) {
+	reg := customLevels.Load()
+	if reg == nil {
+		return "", false
+	}
+	name, ok := reg.names[level]
+	return name, ok
+}
+
+// customLevelValue resolves a normalized name or alias to a registered level.
+func customLevelValue(name string) (Level, bool) {
+	reg := customLevels.Load()
+	if reg == nil {
+		return 0, false
+	}
+	level, ok := reg.values[name]
+	return level, ok
+}
@@ -0,0 +1,270 @@
+// level_registry_test.go: Tests for custom level registration
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetCustomLevelsForTest clears the registry and restores it after the test
+func resetCustomLevelsForTest(t *testing.T) {
+	t.Helper()
+	previous := customLevels.Load()
+	customLevels.Store(nil)
+	t.Cleanup(func() { customLevels.Store(previous) })
+}
+
+func TestRegisterLevel_Basic(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	const notice = Level(6)
+	if err := RegisterLevel(notice, "NOTICE"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	if got := notice.String(); got != "notice" {
+		t.Errorf("String() = %q, want %q", got, "notice")
+	}
+
+	parsed, err := ParseLevel(" Notice ")
+	if err != nil {
+		t.Fatalf("ParseLevel failed: %v", err)
+	}
+	if parsed != notice {
+		t.Errorf("ParseLevel = %d, want %d", parsed, notice)
+	}
+
+	if !IsValidLevel(notice) {
+		t.Error("registered level should be valid")
+	}
+
+	text, err := notice.MarshalText()
+	if err != nil || string(text) != "notice" {
+		t.Errorf("MarshalText = %q, %v", text, err)
+	}
+}
+
+func TestRegisterLevel_Rejections(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	tests := []struct {
+		name  string
+		level Level
+		label string
+	}{
+		{"empty name", Level(-5), "  "},
+		{"builtin value", Info, "information"},
+		{"builtin name", Level(-5), "debug"},
+		{"out of int8 range", Level(200), "huge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterLevel(tt.level, tt.label)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !IsLoggerError(err, ErrCodeInvalidLevel) {
+				t.Errorf("expected ErrCodeInvalidLevel, got %v", err)
+			}
+		})
+	}
+
+	if err := RegisterLevel(Level(-3), "fine"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	if err := RegisterLevel(Level(-3), "other"); err == nil {
+		t.Error("expected duplicate value to be rejected")
+	}
+	if err := RegisterLevel(Level(-4), "fine"); err == nil {
+		t.Error("expected duplicate name to be rejected")
+	}
+}
+
+func TestRegisterLevelAlias(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	if err := RegisterLevelAlias("notice", Info); err != nil {
+		t.Fatalf("RegisterLevelAlias failed: %v", err)
+	}
+	if err := RegisterLevelAlias("crit", Error); err != nil {
+		t.Fatalf("RegisterLevelAlias failed: %v", err)
+	}
+
+	if level, err := ParseLevel("NOTICE"); err != nil || level != Info {
+		t.Errorf("ParseLevel(notice) = %v, %v", level, err)
+	}
+	if level := parseLevel("crit"); level != Error {
+		t.Errorf("parseLevel(crit) = %v, want error", level)
+	}
+
+	// Aliases never change output names
+	if Info.String() != "info" {
+		t.Errorf("alias must not change canonical name, got %q", Info.String())
+	}
+
+	if err := RegisterLevelAlias("ghost", Level(42)); err == nil {
+		t.Error("expected alias to unknown level to be rejected")
+	}
+	if err := RegisterLevelAlias("notice", Warn); err == nil {
+		t.Error("expected duplicate alias to be rejected")
+	}
+}
+
+func TestAllLevels_IncludesCustomLevels(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	if err := RegisterLevel(Level(-3), "verbose"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	if err := RegisterLevel(Level(9), "alert"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	levels := AllLevels()
+	expected := []Level{Level(-3), Trace, Debug, Info, Warn, Error, Level(9)}
+	if len(levels) != len(expected) {
+		t.Fatalf("AllLevels() = %v, want %v", levels, expected)
+	}
+	for i := range expected {
+		if levels[i] != expected[i] {
+			t.Errorf("AllLevels()[%d] = %d, want %d", i, levels[i], expected[i])
+		}
+	}
+
+	names := AllLevelNames()
+	if names[0] != "verbose" || names[len(names)-1] != "alert" {
+		t.Errorf("AllLevelNames() = %v", names)
+	}
+}
+
+func TestCustomLevel_FilteringAndEncoding(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	const verbose = Level(-3)
+	const alert = Level(9)
+	if err := RegisterLevel(verbose, "verbose"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	if err := RegisterLevel(alert, "alert"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:    Trace,
+		Output:   buf,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 64,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.log(verbose, "below debug")
+	logger.log(alert, "above fatal")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "below debug") {
+		t.Error("custom level below the minimum should be filtered")
+	}
+	if !strings.Contains(out, `"level":"alert"`) {
+		t.Errorf("expected custom level name in JSON output, got %q", out)
+	}
+
+	var text bytes.Buffer
+	rec := NewRecord(alert, "text")
+	NewTextEncoder().Encode(rec, time.Unix(0, 0), &text)
+	if !strings.Contains(text.String(), "level=alert") {
+		t.Errorf("expected custom level name in text output, got %q", text.String())
+	}
+}
+
+func TestRegisterLevelAbove_BetweenBuiltinLevels(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	const notice = Level(10)
+	const audit = Level(11)
+	if err := RegisterLevelAbove(notice, "notice", Info); err != nil {
+		t.Fatalf("RegisterLevelAbove failed: %v", err)
+	}
+	if err := RegisterLevelAbove(audit, "audit", Info); err != nil {
+		t.Fatalf("RegisterLevelAbove failed: %v", err)
+	}
+	if err := RegisterLevelAbove(Level(12), "bogus", notice); err == nil {
+		t.Error("expected a custom base to be rejected")
+	}
+
+	want := []Level{Trace, Debug, Info, notice, audit, Warn, Error}
+	if got := AllLevels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllLevels() = %v, want %v", got, want)
+	}
+	if got := syslogSeverity(notice); got != syslogSeverity(Info) {
+		t.Errorf("syslogSeverity(notice) = %d, want the Info severity %d", got, syslogSeverity(Info))
+	}
+
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:    Info,
+		Output:   buf,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 64,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.log(notice, "notice at info")
+	logger.SetLevel(notice)
+	logger.Info("info at notice")
+	logger.log(notice, "notice at notice")
+	logger.log(audit, "audit at notice")
+	logger.Warn("warn at notice")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "info at notice") {
+		t.Error("SetLevel(notice) should filter Info")
+	}
+	for _, msg := range []string{"notice at info", "notice at notice", "audit at notice", "warn at notice"} {
+		if !strings.Contains(out, msg) {
+			t.Errorf("expected %q in output, got %q", msg, out)
+		}
+	}
+}
+
+func TestCustomLevel_ConfigLoader(t *testing.T) {
+	resetCustomLevelsForTest(t)
+
+	if err := RegisterLevel(Level(-3), "fine"); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	t.Setenv("IRIS_LEVEL", "FINE")
+	cfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if cfg.Level != Level(-3) {
+		t.Errorf("expected custom level from env, got %d", cfg.Level)
+	}
+	if err := cfg.withDefaults().Validate(); err != nil {
+		t.Errorf("config with custom level should validate: %v", err)
+	}
+}
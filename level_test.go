@@ -22,6 +22,7 @@ func TestLevelConstants(t *testing.T) {
 		expected int32
 		name     string
 	}{
+		{Trace, -2, "Trace"},
 		{Debug, -1, "Debug"},
 		{Info, 0, "Info"},
 		{Warn, 1, "Warn"},
@@ -43,6 +44,7 @@ func TestLevelString(t *testing.T) {
 		level    Level
 		expected string
 	}{
+		{Trace, "trace"},
 		{Debug, "debug"},
 		{Info, "info"},
 		{Warn, "warn"},
@@ -177,7 +179,9 @@ func TestParseLevel(t *testing.T) {
 		{"", Info, false, "empty string defaults to info"},
 		{"  ", Info, false, "whitespace only defaults to info after trim"},
 		{"invalid", Info, true, "invalid level"},
-		{"trace", Info, true, "unsupported level"},
+		{"trace", Trace, false, "lowercase trace"},
+		{"TRACE", Trace, false, "uppercase trace"},
+		{"verbose", Info, true, "unsupported level"},
 	}
 
 	for _, tc := range testCases {
@@ -515,7 +519,7 @@ func TestLevelFlagWithCommandLine(t *testing.T) {
 // TestAllLevels tests the AllLevels function
 func TestAllLevels(t *testing.T) {
 	levels := AllLevels()
-	expected := []Level{Debug, Info, Warn, Error}
+	expected := []Level{Trace, Debug, Info, Warn, Error}
 
 	if len(levels) != len(expected) {
 		t.Errorf("Expected %d levels, got %d", len(expected), len(levels))
@@ -532,7 +536,7 @@ func TestAllLevels(t *testing.T) {
 // TestAllLevelNames tests the AllLevelNames function
 func TestAllLevelNames(t *testing.T) {
 	names := AllLevelNames()
-	expected := []string{"debug", "info", "warn", "error"}
+	expected := []string{"trace", "debug", "info", "warn", "error"}
 
 	if len(names) != len(expected) {
 		t.Errorf("Expected %d level names, got %d", len(expected), len(names))
@@ -552,13 +556,14 @@ func TestIsValidLevel(t *testing.T) {
 		expected bool
 		name     string
 	}{
+		{Trace, true, "Trace is valid"},
 		{Debug, true, "Debug is valid"},
 		{Info, true, "Info is valid"},
 		{Warn, true, "Warn is valid"},
 		{Error, true, "Error is valid"},
 		{Level(-10), false, "Level -10 is invalid"},
 		{Level(10), false, "Level 10 is invalid"},
-		{Level(-2), false, "Level -2 is invalid"},
+		{Level(-3), false, "Level -3 is invalid"},
 		{Level(3), false, "Level 3 is invalid"},
 	}
 
@@ -604,7 +609,7 @@ func TestLevelComparison(t *testing.T) {
 // TestLevelNamesMapCompleteness ensures all levels are in the map
 func TestLevelNamesMapCompleteness(t *testing.T) {
 	// Test that all standard level names are in the map
-	standardNames := []string{"debug", "info", "warn", "error"}
+	standardNames := []string{"trace", "debug", "info", "warn", "error"}
 
 	for _, name := range standardNames {
 		if _, exists := levelNamesMap[name]; !exists {
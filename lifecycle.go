@@ -0,0 +1,211 @@
+// lifecycle.go: Logger lifecycle state machine for Iris logging library
+//
+// A logger moves through four states, shared by all loggers derived from
+// the same root since they share one ring:
+//
+//	New ──Start──▶ Started ──Close──▶ Draining ──▶ Closed
+//	 └───────────────Close──────────────┘
+//
+// Transitions are single CAS operations on the ring, so Start and Close are
+// idempotent and safe to race with each other and with logging. Close only
+// returns once the ring is drained: every record accepted before it was
+// called has been handed to the output, and every record logged afterwards
+// is rejected (and counted as DropClosed) instead of being silently lost.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+
+	"github.com/agilira/go-errors"
+)
+
+// LifecycleState is the lifecycle state of a logger.
+type LifecycleState int32
+
+const (
+	// StateNew: created, consumer not started (Config.AutoStart off)
+	StateNew LifecycleState = iota
+	// StateStarted: records are being processed
+	StateStarted
+	// StateDraining: Close was called; buffered records are being written
+	StateDraining
+	// StateClosed: drained; every record is rejected
+	StateClosed
+)
+
+// String returns the lowercase name of the state.
+func (s LifecycleState) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateStarted:
+		return "started"
+	case StateDraining:
+		return "draining"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// State returns the current lifecycle state of the logger.
+func (l *Logger) State() LifecycleState {
+	return LifecycleState(l.r.state.Load())
+}
+
+// WithStrictLifecycle makes use after Close explicit.
+//
+// By default a record logged after Close is dropped and only counted
+// (Stats "dropped_closed"). In strict mode each such record is also
+// reported to the error handler as an ErrCodeLoggerClosed error, and Sync
+// and Close called after the logger was closed return ErrLoggerClosed.
+// Logging methods return false in both modes.
+//
+// Returns:
+//   - Option: Configuration function to enable strict lifecycle checks
+func WithStrictLifecycle() Option {
+	return func(o *loggerOptions) {
+		o.strictLifecycle = true
+	}
+}
+
+// Shutdown is Close bounded by ctx, for shutdown paths with a time budget
+// (e.g. the grace period before a container is killed).
+//
+// Records at Error level and above still in the ring are written first,
+// ahead of older records below Error, so that if the budget runs out the
+// records that explain a failure are the ones that reached the output.
+// The rest are then drained in order. When ctx is done before the drain
+// completes, Shutdown returns at once: the records still pending are
+// discarded and counted as DropShutdown, and the output is synced and the
+// outputs the logger owns are closed in the background once the record
+// being written, if any, is done. A later Close waits for the discarding
+// drain to end.
+//
+// Without a deadline or cancellation (ctx.Done() == nil), Shutdown is Close.
+//
+// Parameters:
+//   - ctx: Context bounding the drain
+//
+// Returns:
+//   - error: An ErrCodeTimeout error wrapping ctx.Err() if records were
+//     discarded, otherwise what Close returns
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := logger.Shutdown(ctx); err != nil {
+//	    fmt.Fprintln(os.Stderr, "log drain incomplete:", err)
+//	}
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if ctx.Done() == nil {
+		return l.Close()
+	}
+	if l.r.Closed() {
+		return l.Close() // Waits for the earlier Close
+	}
+	l.stopBackground()
+
+	// Error and above first, while there is still time
+	first := func(rec *Record) bool {
+		return rec.Level.Enabled(Error) && ctx.Err() == nil
+	}
+	closed := make(chan bool, 1)
+	go func() { closed <- l.r.closeWith(first) }()
+	select {
+	case first := <-closed:
+		if !first {
+			return l.Close() // Raced with another Close
+		}
+		return l.finishClose()
+	case <-ctx.Done():
+	}
+
+	l.r.abandoned.Store(true)
+	go func() {
+		if <-closed {
+			if err := l.finishClose(); err != nil {
+				l.reportError(errors.Wrap(err, ErrCodeSyncFailed, "failed to finish shutdown after the deadline").
+					WithContext("logger", l.name))
+			}
+		}
+	}()
+	return errors.Wrap(ctx.Err(), ErrCodeTimeout, "shutdown deadline passed before the ring was drained").
+		WithContext("logger", l.name)
+}
+
+// discardShutdown drops a record left in the ring when Shutdown's deadline
+// passed. Called by the consumer in place of encoding.
+func (l *Logger) discardShutdown(rec *Record) {
+	l.countDropped()
+	l.recordDrop(DropShutdown, rec.Level)
+	rec.resetForWrite()
+	if l.opts.recordDebug {
+		sealRecord(rec)
+	}
+}
+
+// reportClosed reports a record rejected because the logger is closed.
+func (l *Logger) reportClosed() {
+	l.reportError(NewLoggerErrorWithField(ErrLoggerClosed.Code,
+		"record logged after Close", "logger", l.name))
+}
+
+// start moves a new ring to StateStarted and reports whether this call did.
+func (r *Ring) start() bool {
+	return r.state.CompareAndSwap(int32(StateNew), int32(StateStarted))
+}
+
+// consume runs the consumer until the ring is closed and drained.
+func (r *Ring) consume() {
+	if r.lane != nil {
+		r.consumeLane() // Stepped, like a shared consumer
+		return
+	}
+	if r.elastic != nil {
+		r.elastic.consume()
+	} else {
+		r.z.LoopProcess()
+	}
+	r.finish()
+}
+
+// step processes one batch for a consumer shared with other rings (see
+// Runtime) and reports how many records it processed and whether the ring
+// has been closed and drained, after which it must not be stepped again.
+// The priority lane, if any, is drained before the batch.
+func (r *Ring) step() (int, bool) {
+	first := 0
+	if r.lane != nil {
+		first = r.lane.drain()
+	}
+	switch {
+	case r.elastic != nil:
+		processed, closed := r.elastic.step()
+		if !closed {
+			return first + processed, false
+		}
+	case !r.z.Closed():
+		return first + r.z.ProcessBatch(), false
+	default:
+		r.z.LoopProcess() // Final drain
+	}
+	if r.lane != nil {
+		r.lane.z.LoopProcess() // Closed before the main ring: final drain
+	}
+	r.finish()
+	return first, true
+}
+
+// finish marks a drained ring closed.
+func (r *Ring) finish() {
+	r.state.Store(int32(StateClosed))
+	close(r.drained)
+}
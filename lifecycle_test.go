@@ -0,0 +1,234 @@
+// lifecycle_test.go: Tests for the logger lifecycle state machine
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_States(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	child := logger.Named("child")
+
+	steps := []struct {
+		name string
+		do   func()
+		want LifecycleState
+	}{
+		{"created", func() {}, StateNew},
+		{"started", logger.Start, StateStarted},
+		{"started twice", child.Start, StateStarted},
+		{"closed", func() { safeCloseWithOptionsLogger(t, child) }, StateClosed},
+		{"start after close", logger.Start, StateClosed},
+	}
+	for _, step := range steps {
+		step.do()
+		if got := logger.State(); got != step.want {
+			t.Errorf("%s: State() = %v, want %v", step.name, got, step.want)
+		}
+	}
+	if StateDraining.String() != "draining" || LifecycleState(9).String() != "unknown" {
+		t.Error("unexpected LifecycleState names")
+	}
+}
+
+func TestClose_DrainsUnstartedLogger(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	captureErrors(t) // Silence the not-started diagnostic
+
+	logger.Info("first")
+	logger.Info("second")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 2 {
+		t.Errorf("expected 2 records written by Close, got %d: %q", got, out.String())
+	}
+}
+
+func TestClose_ConcurrentLogging(t *testing.T) {
+	for _, inline := range []bool{false, true} {
+		out := &testSyncer{}
+		logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024, Inline: inline})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+
+		var accepted atomic.Int64
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					if logger.Info("racing") {
+						accepted.Add(1)
+					}
+				}
+			}()
+		}
+		closeErrs := make(chan error, 4)
+		for c := 0; c < 4; c++ {
+			go func() { closeErrs <- logger.Close() }()
+		}
+		for c := 0; c < 4; c++ {
+			if err := <-closeErrs; err != nil {
+				t.Errorf("inline=%v: Close failed: %v", inline, err)
+			}
+		}
+		written := int64(strings.Count(out.String(), "\n")) // Every Close returned: drain is complete
+		wg.Wait()
+
+		if written != accepted.Load() {
+			t.Errorf("inline=%v: %d records accepted but %d written", inline, accepted.Load(), written)
+		}
+		if dropped := logger.DroppedBy(DropClosed) + logger.DroppedBy(DropRingFull); accepted.Load()+dropped != 8*500 {
+			t.Errorf("inline=%v: accepted %d + dropped %d != %d", inline, accepted.Load(), dropped, 8*500)
+		}
+		if logger.Info("after close") {
+			t.Errorf("inline=%v: logging after Close succeeded", inline)
+		}
+		if err := logger.Sync(); err != nil {
+			t.Errorf("inline=%v: Sync after Close failed: %v", inline, err)
+		}
+	}
+}
+
+func TestStrictLifecycle(t *testing.T) {
+	reported := captureErrors(t)
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Name: "svc"},
+		WithStrictLifecycle())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync before Close failed: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("first Close failed: %v", err)
+	}
+
+	if logger.With(Int("n", 1)).Info("late") {
+		t.Error("Info after Close returned true")
+	}
+	if logger.Write(func(r *Record) { r.Msg = "late write" }) {
+		t.Error("Write after Close returned true")
+	}
+	errs := reported()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 reported errors, got %d: %v", len(errs), errs)
+	}
+	for _, e := range errs {
+		if e.Code != ErrCodeLoggerClosed {
+			t.Errorf("unexpected error code %s: %v", e.Code, e)
+		}
+	}
+	if err := logger.Sync(); err != ErrLoggerClosed {
+		t.Errorf("Sync after Close = %v, want ErrLoggerClosed", err)
+	}
+	if err := logger.Close(); err != ErrLoggerClosed {
+		t.Errorf("second Close = %v, want ErrLoggerClosed", err)
+	}
+}
+
+// gateSyncer blocks every write until release is closed.
+type gateSyncer struct {
+	testSyncer
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (g *gateSyncer) Write(p []byte) (int, error) {
+	g.once.Do(func() { close(g.entered) })
+	<-g.release
+	return g.testSyncer.Write(p)
+}
+
+func TestShutdown_WritesErrorsFirst(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	captureErrors(t) // Silence the not-started diagnostic
+
+	logger.Info("info-1")
+	logger.Error("error-1")
+	logger.Warn("warn-1")
+	logger.Error("error-2")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		for _, msg := range []string{"info-1", "error-1", "warn-1", "error-2"} {
+			if strings.Contains(line, `"msg":"`+msg+`"`) {
+				order = append(order, msg)
+			}
+		}
+	}
+	if got := strings.Join(order, ","); got != "error-1,error-2,info-1,warn-1" {
+		t.Errorf("write order %s, want errors first, then the rest in order", got)
+	}
+	if logger.State() != StateClosed {
+		t.Errorf("State() = %v after Shutdown, want closed", logger.State())
+	}
+}
+
+func TestShutdown_Deadline(t *testing.T) {
+	out := &gateSyncer{entered: make(chan struct{}), release: make(chan struct{})}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	captureErrors(t)
+
+	logger.Info("info-1")
+	logger.Error("error-1")
+	logger.Info("info-2")
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-out.entered // error-1 is being written and the sink hangs
+		cancel()
+	}()
+	err = logger.Shutdown(ctx)
+	if !IsLoggerError(err, ErrCodeTimeout) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown returned %v, want ErrCodeTimeout wrapping context.Canceled", err)
+	}
+
+	close(out.release)
+	_ = logger.Close() // Waits for the discarding drain
+	if got := out.String(); !strings.Contains(got, "error-1") || strings.Contains(got, "info-") {
+		t.Errorf("output %q, want only error-1", got)
+	}
+	if got := logger.Stats()["dropped_shutdown"]; got != 2 {
+		t.Errorf("dropped_shutdown = %d, want 2", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown after close returned %v, want nil", err)
+	}
+}
@@ -0,0 +1,168 @@
+// message_transform.go: Message rendering in the consumer
+//
+// Call sites log stable keys ("payment.declined") with their parameters as
+// fields; WithMessageTransformer turns them into the text people read, in
+// the consumer, just before the record is encoded. A catalog per language
+// localizes the output, and a single catalog keeps phrasing consistent
+// across a code base, without touching the call sites. The original key is
+// kept as a field, so the records stay searchable by it whatever the
+// rendered text.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMessageTemplateKey is the field holding the original message of a
+// transformed record when WithMessageTransformer is given no key.
+const DefaultMessageTemplateKey = "msg_template"
+
+// MessageTransformer renders the message of rec, whose Msg is the message
+// (key or template) logged at the call site. It returns the rendered
+// message and true, or false to leave the record as logged. It runs in the
+// consumer, after the pipeline stages, so it sees the fields they added; it
+// must not modify rec or block.
+type MessageTransformer func(rec *Record) (string, bool)
+
+// messageTransform is the configuration of WithMessageTransformer.
+type messageTransform struct {
+	fn  MessageTransformer
+	key string
+}
+
+// WithMessageTransformer renders the message of every record with fn
+// before it is encoded. When fn renders a message, the record gets it as
+// its message and the original one in a field named templateKey
+// (DefaultMessageTemplateKey if empty); the parameter fields are left in
+// place. A panic in fn is recovered and reported as ErrCodeEncodingFailed,
+// and the record is written as logged. Like the other consumer options it
+// must be passed to New.
+//
+// Parameters:
+//   - fn: Message renderer (nil is ignored), e.g. MessageCatalog.Transform
+//   - templateKey: Field preserving the original message
+//
+// Returns:
+//   - Option: Configuration function to enable the transformer
+//
+// Example:
+//
+//	catalog := iris.MessageCatalog{
+//	    "payment.declined": "Payment of {amount} declined by {issuer}",
+//	}
+//	logger, err := iris.New(cfg, iris.WithMessageTransformer(catalog.Transform, ""))
+//	logger.Warn("payment.declined", iris.Float64("amount", 12.5), iris.Str("issuer", "acme"))
+//	// {"level":"warn","msg":"Payment of 12.5 declined by acme","amount":12.5,"issuer":"acme","msg_template":"payment.declined"}
+func WithMessageTransformer(fn MessageTransformer, templateKey string) Option {
+	if templateKey == "" {
+		templateKey = DefaultMessageTemplateKey
+	}
+	return func(o *loggerOptions) {
+		if fn == nil {
+			return
+		}
+		o.msgTransform = &messageTransform{fn: fn, key: templateKey}
+	}
+}
+
+// transformMessage renders the message of rec with the configured
+// transformer.
+func (l *Logger) transformMessage(rec *Record) {
+	t := l.opts.msgTransform
+	msg, ok := func() (msg string, ok bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				l.reportEncodeError(NewLoggerError(ErrCodeEncodingFailed, fmt.Sprintf("message transformer panicked: %v", r)), rec)
+				ok = false
+			}
+		}()
+		return t.fn(rec)
+	}()
+	if !ok {
+		return
+	}
+	rec.AddField(Str(t.key, rec.Msg))
+	rec.Msg = msg
+}
+
+// MessageCatalog maps message keys to templates. Placeholders of the form
+// {name} are replaced by the value of the record field with that key;
+// placeholders without a matching field are left as they are.
+//
+// A catalog per language, chosen when the logger is created, localizes the
+// output:
+//
+//	catalogs := map[string]iris.MessageCatalog{
+//	    "en": {"cart.empty": "Cart {cart_id} is empty"},
+//	    "it": {"cart.empty": "Il carrello {cart_id} è vuoto"},
+//	}
+//	logger, err := iris.New(cfg, iris.WithMessageTransformer(catalogs[lang].Transform, ""))
+type MessageCatalog map[string]string
+
+// Transform implements MessageTransformer: records whose message is a key
+// of the catalog get the rendered template, others are left as logged.
+func (c MessageCatalog) Transform(rec *Record) (string, bool) {
+	tmpl, ok := c[rec.Msg]
+	if !ok {
+		return "", false
+	}
+	return renderTemplate(tmpl, rec), true
+}
+
+// renderTemplate replaces the {name} placeholders of tmpl with the values
+// of the fields of rec.
+func renderTemplate(tmpl string, rec *Record) string {
+	if !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+	var b strings.Builder
+	b.Grow(len(tmpl) + 16)
+	for {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+		b.WriteString(tmpl[:open])
+		if f, ok := recordField(rec, tmpl[open+1:end]); ok {
+			writeFieldText(&b, f)
+		} else {
+			b.WriteString(tmpl[open : end+1])
+		}
+		tmpl = tmpl[end+1:]
+	}
+	b.WriteString(tmpl)
+	return b.String()
+}
+
+// recordField returns the last field of rec with the given key, the one
+// closest to the call site.
+func recordField(rec *Record, key string) (Field, bool) {
+	for i := rec.FieldCount() - 1; i >= 0; i-- {
+		if f := rec.GetField(i); f.K == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// writeFieldText writes the value of f as text.
+func writeFieldText(b *strings.Builder, f Field) {
+	if f.T == kindString {
+		b.WriteString(f.Str)
+		return
+	}
+	if v := f.Value(); v != nil {
+		fmt.Fprint(b, v)
+	}
+}
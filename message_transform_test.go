@@ -0,0 +1,95 @@
+// message_transform_test.go: Tests for message rendering
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageCatalog_Transform(t *testing.T) {
+	catalog := MessageCatalog{
+		"payment.declined": "Payment of {amount} declined by {issuer}",
+		"retry":            "Retrying in {delay} ({attempt}/{max})",
+		"static":           "Nothing to render",
+		"broken":           "Unclosed {issuer",
+		"secret":           "Token {token}",
+	}
+	tests := []struct {
+		name   string
+		msg    string
+		fields []Field
+		want   string
+		ok     bool
+	}{
+		{"rendered", "payment.declined", []Field{Float64("amount", 12.5), Str("issuer", "acme")}, "Payment of 12.5 declined by acme", true},
+		{"missing field kept", "retry", []Field{Dur("delay", 2*time.Second), Int("attempt", 1)}, "Retrying in 2s (1/{max})", true},
+		{"last field wins", "payment.declined", []Field{Str("issuer", "a"), Str("issuer", "b"), Int("amount", 3)}, "Payment of 3 declined by b", true},
+		{"no placeholders", "static", nil, "Nothing to render", true},
+		{"unclosed", "broken", []Field{Str("issuer", "acme")}, "Unclosed {issuer", true},
+		{"secret", "secret", []Field{Secret("token", "hunter2")}, "Token [REDACTED]", true},
+		{"unknown key", "free text", nil, "", false},
+	}
+	for _, tt := range tests {
+		rec := NewRecord(Info, tt.msg)
+		for _, f := range tt.fields {
+			rec.AddField(f)
+		}
+		got, ok := catalog.Transform(rec)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: Transform() = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWithMessageTransformer(t *testing.T) {
+	out := &testSyncer{}
+	catalog := MessageCatalog{"cart.empty": "Cart {cart_id} is empty in {region}"}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, Pipeline: &PipelineConfig{
+		Processors: []ProcessorConfig{{Type: ProcessorEnrich, Fields: map[string]any{"region": "eu-1"}}},
+	}}, WithMessageTransformer(catalog.Transform, "key"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Info("cart.empty", Str("cart_id", "c-42"))
+	logger.Info("not in catalog")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	for _, want := range []string{`"msg":"Cart c-42 is empty in eu-1"`, `"cart_id":"c-42"`, `"key":"cart.empty"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("%s does not contain %s", lines[0], want)
+		}
+	}
+	if !strings.Contains(lines[1], `"msg":"not in catalog"`) || strings.Contains(lines[1], `"key"`) {
+		t.Errorf("untransformed record changed: %s", lines[1])
+	}
+}
+
+func TestWithMessageTransformer_Panic(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true},
+		WithMessageTransformer(func(*Record) (string, bool) { panic("bad catalog") }, ""))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	errs := logger.Errors()
+
+	logger.Info("as logged")
+	if !strings.Contains(out.String(), `"msg":"as logged"`) || strings.Contains(out.String(), DefaultMessageTemplateKey) {
+		t.Errorf("output = %s", out.String())
+	}
+	if got := withCode(drainErrors(errs), ErrCodeEncodingFailed); len(got) != 1 || !strings.Contains(got[0].Error(), "bad catalog") {
+		t.Errorf("got %v, want one ErrCodeEncodingFailed", got)
+	}
+}
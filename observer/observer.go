@@ -0,0 +1,291 @@
+// observer.go: In-memory log observer for testing code that logs with Iris
+//
+// This package provides a logger whose records are captured in memory
+// instead of being written to an output, together with a small query
+// interface for asserting on what was logged. Records are processed inline
+// (see iris.Config.Inline), so every entry is observable as soon as the
+// logging call returns - no Sync, sleeps or polling required.
+//
+// Usage:
+//
+//	logger, logs := observer.New(iris.Debug)
+//	svc := NewService(logger)
+//	svc.Charge(order)
+//
+//	failed := logs.FilterLevel(iris.Error).FilterField("order", order.ID)
+//	if failed.Count() != 1 {
+//	    t.Fatalf("expected one failure, got:\n%s", logs)
+//	}
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package observer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+// LoggedEntry is a captured log record.
+type LoggedEntry struct {
+	Level   iris.Level
+	Message string
+	Logger  string
+	Caller  string
+	Stack   string
+	Fields  []iris.Field
+}
+
+// ContextMap returns the entry's fields as a map keyed by field name,
+// using iris.Field.Value for the values. Later fields win on duplicate keys.
+func (e LoggedEntry) ContextMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(e.Fields))
+	for _, f := range e.Fields {
+		m[f.K] = f.Value()
+	}
+	return m
+}
+
+// String renders the entry on a single line in a stable, timestamp-free
+// format: level, logger name, message and fields in logging order.
+//
+// Example:
+//
+//	error [payments] charge failed order=A1 amount=42
+func (e LoggedEntry) String() string {
+	var b strings.Builder
+	b.WriteString(e.Level.String())
+	if e.Logger != "" {
+		b.WriteString(" [")
+		b.WriteString(e.Logger)
+		b.WriteByte(']')
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.Quote(e.Message))
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		b.WriteString(f.K)
+		b.WriteByte('=')
+		b.WriteString(formatValue(f.Value()))
+	}
+	return b.String()
+}
+
+// ObservedLogs is a concurrency-safe collection of captured entries.
+//
+// Filter methods return a new, independent ObservedLogs so queries can be
+// chained; the receiver is never modified.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []LoggedEntry
+}
+
+// New creates a logger that records every entry at or above level into the
+// returned ObservedLogs. The logger is already started and needs no Close.
+//
+// Options are applied as with iris.New (e.g. iris.WithCaller()); output and
+// encoder are fixed by the observer.
+func New(level iris.Level, opts ...iris.Option) (*iris.Logger, *ObservedLogs) {
+	logs := &ObservedLogs{}
+	logger, err := iris.New(iris.Config{
+		Level:   level,
+		Output:  iris.WrapWriter(io.Discard),
+		Encoder: &captureEncoder{logs: logs},
+		Inline:  true,
+	}, opts...)
+	if err != nil {
+		// Only reachable through an internal misconfiguration
+		panic(fmt.Sprintf("observer: failed to create logger: %v", err))
+	}
+	logger.Start()
+	return logger, logs
+}
+
+// Len returns the number of entries.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// Count is an alias for Len that reads naturally after a filter chain.
+func (o *ObservedLogs) Count() int {
+	return o.Len()
+}
+
+// All returns a copy of all entries in logging order.
+func (o *ObservedLogs) All() []LoggedEntry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	out := make([]LoggedEntry, len(o.entries))
+	copy(out, o.entries)
+	return out
+}
+
+// TakeAll returns all entries and clears the collection.
+func (o *ObservedLogs) TakeAll() []LoggedEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := o.entries
+	o.entries = nil
+	return out
+}
+
+// Filter returns the entries for which keep returns true.
+func (o *ObservedLogs) Filter(keep func(LoggedEntry) bool) *ObservedLogs {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	filtered := &ObservedLogs{}
+	for _, e := range o.entries {
+		if keep(e) {
+			filtered.entries = append(filtered.entries, e)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns the entries logged at exactly the given level.
+func (o *ObservedLogs) FilterLevel(level iris.Level) *ObservedLogs {
+	return o.Filter(func(e LoggedEntry) bool { return e.Level == level })
+}
+
+// FilterMessage returns the entries whose message equals msg.
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(e LoggedEntry) bool { return e.Message == msg })
+}
+
+// FilterMessageContains returns the entries whose message contains substr.
+func (o *ObservedLogs) FilterMessageContains(substr string) *ObservedLogs {
+	return o.Filter(func(e LoggedEntry) bool { return strings.Contains(e.Message, substr) })
+}
+
+// FilterField returns the entries carrying a field with the given key and value.
+//
+// The value may be an iris.Field or a plain Go value. Plain values are
+// compared against iris.Field.Value after widening integers to int64,
+// unsigned integers to uint64 and float32 to float64, so
+// FilterField("count", 3) matches iris.Int("count", 3).
+func (o *ObservedLogs) FilterField(key string, value interface{}) *ObservedLogs {
+	if f, ok := value.(iris.Field); ok {
+		value = f.Value()
+	}
+	want := normalize(value)
+	return o.Filter(func(e LoggedEntry) bool {
+		for _, f := range e.Fields {
+			if f.K == key && reflect.DeepEqual(normalize(f.Value()), want) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterFieldKey returns the entries carrying a field with the given key.
+func (o *ObservedLogs) FilterFieldKey(key string) *ObservedLogs {
+	return o.Filter(func(e LoggedEntry) bool {
+		for _, f := range e.Fields {
+			if f.K == key {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// String renders one entry per line (see LoggedEntry.String). The output
+// contains no timestamps, so expected and actual logs can be compared and
+// diffed directly in test failures.
+func (o *ObservedLogs) String() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var b strings.Builder
+	for _, e := range o.entries {
+		b.WriteString(e.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// add appends an entry to the collection.
+func (o *ObservedLogs) add(e LoggedEntry) {
+	o.mu.Lock()
+	o.entries = append(o.entries, e)
+	o.mu.Unlock()
+}
+
+// captureEncoder copies each record into the observed logs instead of
+// encoding it. Records are reused by the logger, so fields are copied.
+type captureEncoder struct {
+	logs *ObservedLogs
+}
+
+// Encode implements iris.Encoder.
+func (c *captureEncoder) Encode(rec *iris.Record, _ time.Time, _ *bytes.Buffer) {
+	fields := make([]iris.Field, rec.FieldCount())
+	for i := range fields {
+		fields[i] = rec.GetField(i)
+	}
+	c.logs.add(LoggedEntry{
+		Level:   rec.Level,
+		Message: rec.Msg,
+		Logger:  rec.Logger,
+		Caller:  rec.Caller,
+		Stack:   rec.Stack,
+		Fields:  fields,
+	})
+}
+
+// normalize widens numeric values so plain Go values compare equal to
+// field values regardless of the constructor used.
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case uint:
+		return uint64(n)
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	case float32:
+		return float64(n)
+	case time.Time:
+		return n.UnixNano()
+	default:
+		return v
+	}
+}
+
+// formatValue renders a field value for String output.
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return strconv.Quote(val)
+	case []byte:
+		return strconv.Quote(string(val))
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case nil:
+		return "<nil>"
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,139 @@
+// observer_test.go: Tests for the in-memory log observer
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package observer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris"
+)
+
+func TestObserver_CapturesEntriesSynchronously(t *testing.T) {
+	logger, logs := New(iris.Info)
+
+	logger.Debug("filtered")
+	logger.Info("started", iris.Str("service", "api"), iris.Int("port", 8080))
+	logger.Named("db").Error("query failed", iris.Err(errors.New("timeout")))
+
+	if logs.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d:\n%s", logs.Len(), logs)
+	}
+
+	entries := logs.All()
+	if entries[0].Message != "started" || entries[0].Level != iris.Info {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if got := entries[0].ContextMap()["port"]; got != int64(8080) {
+		t.Errorf("ContextMap()[port] = %#v", got)
+	}
+	if entries[1].Logger != "db" {
+		t.Errorf("expected logger name db, got %q", entries[1].Logger)
+	}
+}
+
+func TestObserver_Filters(t *testing.T) {
+	logger, logs := New(iris.Debug)
+
+	logger.Info("order created", iris.Str("order", "A1"), iris.Int("items", 3))
+	logger.Warn("order delayed", iris.Str("order", "A1"), iris.Dur("delay", time.Second))
+	logger.Error("order failed", iris.Str("order", "B2"), iris.Bool("retry", true))
+	logger.Info("heartbeat", iris.Uint32("seq", 7), iris.Float32("load", 0.5))
+
+	tests := []struct {
+		name  string
+		query *ObservedLogs
+		want  int
+	}{
+		{"level info", logs.FilterLevel(iris.Info), 2},
+		{"level error", logs.FilterLevel(iris.Error), 1},
+		{"message exact", logs.FilterMessage("heartbeat"), 1},
+		{"message contains", logs.FilterMessageContains("order"), 3},
+		{"field string", logs.FilterField("order", "A1"), 2},
+		{"field int widened", logs.FilterField("items", 3), 1},
+		{"field uint widened", logs.FilterField("seq", 7), 0},
+		{"field uint typed", logs.FilterField("seq", uint32(7)), 1},
+		{"field float widened", logs.FilterField("load", float32(0.5)), 1},
+		{"field bool", logs.FilterField("retry", true), 1},
+		{"field duration", logs.FilterField("delay", time.Second), 1},
+		{"field as iris.Field", logs.FilterField("order", iris.Str("order", "B2")), 1},
+		{"field mismatch", logs.FilterField("order", "C3"), 0},
+		{"field key", logs.FilterFieldKey("delay"), 1},
+		{"chained", logs.FilterMessageContains("order").FilterLevel(iris.Info).FilterField("order", "A1"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Count(); got != tt.want {
+				t.Errorf("Count() = %d, want %d:\n%s", got, tt.want, tt.query)
+			}
+		})
+	}
+
+	// Filters never modify the receiver
+	if logs.Len() != 4 {
+		t.Errorf("filters must not modify the source, got %d entries", logs.Len())
+	}
+}
+
+func TestObserver_String(t *testing.T) {
+	logger, logs := New(iris.Debug)
+
+	logger.Info("user login", iris.Str("user", "alice"), iris.Secret("password", "hunter2"))
+	logger.Named("payments").Error("charge failed", iris.Int("amount", 42), iris.Bool("retry", false))
+
+	want := `info "user login" user="alice" password="[REDACTED]"` + "\n" +
+		`error [payments] "charge failed" amount=42 retry=false` + "\n"
+	if got := logs.String(); got != want {
+		t.Errorf("String() mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestObserver_TakeAll(t *testing.T) {
+	logger, logs := New(iris.Debug)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	taken := logs.TakeAll()
+	if len(taken) != 2 || taken[1].Message != "two" {
+		t.Errorf("unexpected TakeAll result: %v", taken)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected empty logs after TakeAll, got %d", logs.Len())
+	}
+
+	logger.Info("three")
+	if logs.FilterMessage("three").Count() != 1 {
+		t.Error("logger should keep recording after TakeAll")
+	}
+}
+
+func TestObserver_Concurrent(t *testing.T) {
+	logger, logs := New(iris.Debug)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("tick", iris.Int("worker", g))
+				_ = logs.FilterField("worker", g).Count()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if logs.Count() != 800 {
+		t.Errorf("expected 800 entries, got %d", logs.Count())
+	}
+	if logs.FilterField("worker", 3).Count() != 100 {
+		t.Errorf("expected 100 entries for worker 3")
+	}
+}
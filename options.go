@@ -25,26 +25,62 @@
 
 package iris
 
-// Hook represents a function executed in the consumer thread after log record processing.
+import (
+	"io"
+	"time"
+)
+
+// Hook is a record listener executed in the consumer thread after a record
+// has been written.
 //
 // Hooks are executed in the consumer thread to avoid contention with producer
 // threads. This design ensures maximum performance for logging operations while
 // still allowing powerful post-processing capabilities.
 //
-// Hook functions receive the fully populated Record after encoding but before
-// the buffer is returned to the pool. This allows for:
+// Hooks receive the fully populated Record after encoding but before the
+// buffer is returned to the pool. This allows for:
 //   - Metrics collection
 //   - Log forwarding to external systems
 //   - Custom processing based on log content
 //   - Development-time debugging
 //
+// Methods:
+//   - Name: Identifies the hook in error reports ("" uses "hook#<n>")
+//   - Levels: Levels the hook runs for, custom levels from RegisterLevel
+//     included (empty = every level)
+//   - Run: Processes the record; a non-nil error is reported through the
+//     error handler (ErrCodeHookExecution, with the hook name in the
+//     "hook" context key) and counted in the "hook_errors" Stats key
+//
 // Performance Notes:
 //   - Executed in single consumer thread (no locks needed)
-//   - Called after encoding is complete
+//   - Levels is read once at registration, so filtering a built-in level
+//     costs one bit test
 //   - Should avoid blocking operations to maintain throughput
 //
-// Thread Safety: Hooks are called from single consumer thread only
-type Hook func(rec *Record)
+// Thread Safety: Run is called from the single consumer thread only (or from
+// the hook's own goroutine with WithAsyncHook)
+type Hook interface {
+	Name() string
+	Levels() []Level
+	Run(rec *Record) error
+}
+
+// HookFunc adapts a plain function to Hook. It has no name, runs for every
+// level and never fails.
+type HookFunc func(rec *Record)
+
+// Name returns "", so diagnostics use the hook position.
+func (f HookFunc) Name() string { return "" }
+
+// Levels returns nil: the function runs for every level.
+func (f HookFunc) Levels() []Level { return nil }
+
+// Run calls f(rec).
+func (f HookFunc) Run(rec *Record) error {
+	f(rec)
+	return nil
+}
 
 // loggerOptions contains immutable configuration for a logger instance.
 //
@@ -74,10 +110,114 @@ type loggerOptions struct {
 	development bool // Enable development-specific behaviors (DPanic -> panic)
 
 	// Hook system
-	hooks []Hook // Post-processing hooks executed in consumer thread
+	hooks      []*hookEntry // Post-processing hooks executed in consumer thread
+	asyncHooks []*asyncHook // WithAsyncHook workers (their entries are in hooks)
 
 	// Sampling system
 	sampler Sampler // Log sampling strategy for rate limiting
+
+	// Field providers evaluated at log time (host info, runtime stats, ...)
+	providers []fieldProvider
+
+	// Path scrubbing for caller and stack information
+	scrubPaths bool
+
+	// Record slot misuse detection (development only)
+	recordDebug    bool
+	onRecordMisuse func(error) // nil panics
+
+	// Encode and end-to-end latency histograms
+	latencyHistograms bool
+
+	// Per-logger rate metrics
+	rateMetrics bool
+
+	// Drop accounting
+	onDrop          DropHandler   // Called for every dropped record
+	countLevelDrops bool          // Count level-filtered records as DropLevel
+	dropSummary     time.Duration // WithDropSummary interval (0 = disabled)
+
+	// Emergency flush triggers (nil = EmergencyFlush only on demand)
+	emergency *EmergencyFlushConfig
+
+	// Stalled consumer detection (nil = disabled)
+	watchdog *WatchdogConfig
+
+	// Cached clock drift check (nil = disabled)
+	clockDrift *ClockDriftConfig
+
+	// Consumer phase profiling (nil = disabled)
+	profile *ProfileConfig
+
+	// Sampler budget persistence (nil = disabled)
+	samplerState *SamplerStateConfig
+
+	// Consumer pipeline stages, run in order before encoding
+	stages []PipelineStage
+
+	// Fields derived from the context of *Ctx calls and ContextLoggers
+	// (nil = none)
+	contextFields ContextFieldsFunc
+
+	// Message rendering before encoding (nil = disabled)
+	msgTransform *messageTransform
+
+	// Record.routes bits of the pipeline sinks whose records skip
+	// Config.Output (exclusive routes)
+	exclusiveRoutes uint32
+
+	// Debug session records (nil = logger output)
+	sessionOut WriteSyncer
+
+	// Field recording when re-ingested records were processed ("" = none)
+	ingestionKey string
+
+	// Additional encoder/sink pairs (WithOutput)
+	outputs []fanoutOutput
+
+	// PHI/PII classification (nil = disabled)
+	classifier *classifier
+
+	// Message template analysis (nil = disabled)
+	templates *TemplateConfig
+
+	// Pressure() thresholds (nil = DefaultPressureThresholds)
+	pressure *PressureThresholds
+
+	// Report use after Close (WithStrictLifecycle)
+	strictLifecycle bool
+
+	// Shared consumer goroutines (nil = one consumer per logger)
+	runtime *Runtime
+
+	// Priority lane capacity for Warn and above (0 = no lane)
+	priorityLane int64
+
+	// Panic and DPanic capture for tests (nil = panic)
+	panics *PanicCapture
+
+	// Outputs opened by a constructor, closed by Close (NewFileLogger)
+	owned []io.Closer
+
+	// Structured outputs added with WithSyncWriter
+	recordWriters []SyncWriter
+}
+
+// fieldProvider produces a field at log time for records at or above min.
+//
+// Providers run in the producer thread while the ring slot is held, so they
+// must be cheap (typically an atomic load of a cached value).
+type fieldProvider struct {
+	min Level
+	fn  func() Field
+}
+
+// addProvider appends a field provider without aliasing the slice shared
+// with the options set this one was cloned from.
+func (o *loggerOptions) addProvider(p fieldProvider) {
+	providers := make([]fieldProvider, len(o.providers), len(o.providers)+1)
+	copy(providers, o.providers)
+	o.providers = append(providers, p)
 }
 
 // Option represents a function that modifies logger options during construction.
@@ -211,7 +351,7 @@ func Development() Option {
 	}
 }
 
-// WithHook adds a post-processing hook to the logger.
+// WithHook adds a post-processing function hook to the logger.
 //
 // Hooks are functions executed in the consumer thread after log records are
 // processed but before buffers are returned to the pool. This design ensures
@@ -228,7 +368,8 @@ func Development() Option {
 //
 // Performance Notes:
 //   - Hooks are executed sequentially in consumer thread
-//   - Should avoid blocking operations to maintain throughput
+//   - Should avoid blocking operations to maintain throughput (use
+//     WithAsyncHook for hooks that do I/O)
 //   - No allocation overhead in producer threads
 //
 // Returns:
@@ -242,14 +383,47 @@ func Development() Option {
 //	    }
 //	}
 //	logger := logger.WithOptions(iris.WithHook(metricHook))
-func WithHook(h Hook) Option {
+func WithHook(h HookFunc) Option {
 	return func(o *loggerOptions) {
 		if h != nil {
-			o.hooks = append(o.hooks, h)
+			o.addHook(newHookEntry(h, len(o.hooks)+1))
 		}
 	}
 }
 
+// WithTypedHook adds a Hook with a name, level filter and error reporting.
+//
+// Parameters:
+//   - h: Hook to execute (nil hooks are ignored)
+//
+// Returns:
+//   - Option: Configuration function to add the hook
+//
+// Example:
+//
+//	type alertHook struct{ client *alerts.Client }
+//
+//	func (alertHook) Name() string           { return "pagerduty" }
+//	func (alertHook) Levels() []iris.Level   { return []iris.Level{iris.Error, iris.Fatal} }
+//	func (a alertHook) Run(rec *iris.Record) error { return a.client.Send(rec.Msg) }
+//
+//	logger, err := iris.New(cfg, iris.WithTypedHook(alertHook{client}))
+func WithTypedHook(h Hook) Option {
+	return func(o *loggerOptions) {
+		if h != nil {
+			o.addHook(newHookEntry(h, len(o.hooks)+1))
+		}
+	}
+}
+
+// addHook appends a hook entry without aliasing the slice shared with the
+// options set this one was cloned from.
+func (o *loggerOptions) addHook(e *hookEntry) {
+	hooks := make([]*hookEntry, len(o.hooks), len(o.hooks)+1)
+	copy(hooks, o.hooks)
+	o.hooks = append(hooks, e)
+}
+
 // WithSampler enables log sampling with the specified sampler.
 //
 // Sampling is used to reduce log volume in high-throughput scenarios by
@@ -285,6 +459,31 @@ func WithSampler(s Sampler) Option {
 	}
 }
 
+// WithIngestionTime adds a key field holding the time of the logging call
+// to records whose timestamp was overridden with IngestTime or
+// Record.SetTime.
+//
+// When historical events are re-ingested, the record timestamp says when
+// the event happened; the ingestion field keeps when it entered this
+// pipeline, so replays and late imports can be told apart from live
+// traffic. Records stamped by the logger are not affected.
+//
+// Parameters:
+//   - key: Field name, e.g. "ingested_at" (empty disables the field)
+//
+// Returns:
+//   - Option: Configuration function to set the ingestion field
+//
+// Example:
+//
+//	replay := logger.WithOptions(iris.WithIngestionTime("ingested_at"))
+//	replay.Info(ev.Message, iris.IngestTime(ev.Timestamp))
+func WithIngestionTime(key string) Option {
+	return func(o *loggerOptions) {
+		o.ingestionKey = key
+	}
+}
+
 // newLoggerOptions creates a new loggerOptions with proper default values.
 func newLoggerOptions() loggerOptions {
 	return loggerOptions{
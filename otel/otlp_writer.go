@@ -0,0 +1,394 @@
+// otlp_writer.go: OTLP log record exporter for Iris
+//
+// OTLPWriter ships Iris records to an OpenTelemetry collector (or any OTLP
+// endpoint) as OpenTelemetry log records, over gRPC or HTTP/protobuf. It is
+// an iris.SyncWriter, attached with iris.WithSyncWriter: the consumer
+// converts every record and hands it to the OpenTelemetry SDK, whose batch
+// processor exports it in the background with the configured retry policy
+// and resource attributes. The logger's own output is unaffected, so the
+// exporter can run next to a local file or stdout.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agilira/iris"
+)
+
+// OTLP transport protocols.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// Defaults of OTLPConfig.
+const (
+	DefaultOTLPBatchSize     = 512
+	DefaultOTLPQueueSize     = 2048
+	DefaultOTLPFlushInterval = time.Second
+	DefaultOTLPScopeName     = "github.com/agilira/iris"
+)
+
+// OTLPRetry configures the retry of failed exports. Retryable failures
+// (unavailable collector, throttling) are retried with an exponential
+// backoff from InitialInterval up to MaxInterval, until MaxElapsedTime has
+// passed since the first attempt.
+type OTLPRetry struct {
+	Disabled        bool
+	InitialInterval time.Duration // default 5s
+	MaxInterval     time.Duration // default 30s
+	MaxElapsedTime  time.Duration // default 1m
+}
+
+// OTLPConfig configures an OTLPWriter.
+type OTLPConfig struct {
+	// Protocol is ProtocolGRPC (default) or ProtocolHTTPProtobuf.
+	Protocol string
+
+	// Endpoint is the host:port of the collector. Empty uses the
+	// OTEL_EXPORTER_OTLP_(LOGS_)ENDPOINT environment variables, or
+	// localhost:4317 (gRPC) and localhost:4318 (HTTP).
+	Endpoint string
+
+	// Insecure disables TLS.
+	Insecure bool
+
+	// Headers are sent with every export (authentication tokens, tenants).
+	Headers map[string]string
+
+	// Timeout bounds each export attempt; zero uses the exporter default
+	// of 10s.
+	Timeout time.Duration
+
+	// Retry configures the retry of failed exports.
+	Retry OTLPRetry
+
+	// ServiceName sets the service.name resource attribute. Empty keeps the
+	// one of Resource, or OTEL_SERVICE_NAME.
+	ServiceName string
+
+	// Resource describes the entity producing the logs. It is merged over
+	// resource.Default(), which reads OTEL_RESOURCE_ATTRIBUTES.
+	Resource *resource.Resource
+
+	// BatchSize is the maximum number of records per export
+	// (default DefaultOTLPBatchSize).
+	BatchSize int
+
+	// QueueSize is the number of records buffered for export; records
+	// beyond it are dropped by the SDK (default DefaultOTLPQueueSize).
+	QueueSize int
+
+	// FlushInterval is the maximum time a record waits for its batch
+	// (default DefaultOTLPFlushInterval).
+	FlushInterval time.Duration
+
+	// ScopeName is the instrumentation scope of the records
+	// (default DefaultOTLPScopeName).
+	ScopeName string
+
+	// Exporter replaces the OTLP exporter built from the fields above, for
+	// tests or custom transports. Protocol, Endpoint, Insecure, Headers,
+	// Timeout and Retry are then ignored.
+	Exporter sdklog.Exporter
+}
+
+// OTLPWriter exports Iris records as OpenTelemetry log records. It
+// implements iris.SyncWriter; Sync flushes the batched records and Close
+// flushes them and shuts the exporter down. It is safe for concurrent use.
+type OTLPWriter struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+var _ iris.SyncWriter = (*OTLPWriter)(nil)
+
+// NewOTLPWriter creates an OTLPWriter exporting to the collector described
+// by cfg. Connections are established lazily, so an unreachable collector
+// is reported by the exports, not here.
+//
+// Example:
+//
+//	exporter, err := otel.NewOTLPWriter(ctx, otel.OTLPConfig{
+//	    Endpoint:    "collector:4317",
+//	    Insecure:    true,
+//	    ServiceName: "checkout",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(cfg, iris.WithSyncWriter(exporter))
+func NewOTLPWriter(ctx context.Context, cfg OTLPConfig) (*OTLPWriter, error) {
+	res, err := otlpResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	exp := cfg.Exporter
+	if exp == nil {
+		if exp, err = newOTLPExporter(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultOTLPBatchSize
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultOTLPQueueSize
+	}
+	if queueSize < batchSize {
+		queueSize = batchSize
+	}
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = DefaultOTLPFlushInterval
+	}
+	scope := cfg.ScopeName
+	if scope == "" {
+		scope = DefaultOTLPScopeName
+	}
+
+	processor := sdklog.NewBatchProcessor(exp,
+		sdklog.WithExportMaxBatchSize(batchSize),
+		sdklog.WithMaxQueueSize(queueSize),
+		sdklog.WithExportInterval(interval))
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(processor))
+	return &OTLPWriter{provider: provider, logger: provider.Logger(scope)}, nil
+}
+
+// otlpResource returns the resource of cfg merged over the default one.
+func otlpResource(cfg OTLPConfig) (*resource.Resource, error) {
+	res := resource.Default()
+	var err error
+	if cfg.Resource != nil {
+		if res, err = resource.Merge(res, cfg.Resource); err != nil {
+			return nil, fmt.Errorf("otel: merge resource: %w", err)
+		}
+	}
+	if cfg.ServiceName != "" {
+		// Without a schema URL the merge cannot conflict
+		res, _ = resource.Merge(res, resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+	}
+	return res, nil
+}
+
+// newOTLPExporter creates the gRPC or HTTP/protobuf exporter of cfg.
+func newOTLPExporter(ctx context.Context, cfg OTLPConfig) (sdklog.Exporter, error) {
+	switch cfg.Protocol {
+	case "", ProtocolGRPC:
+		var opts []otlploggrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploggrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+		}
+		if cfg.Timeout > 0 {
+			opts = append(opts, otlploggrpc.WithTimeout(cfg.Timeout))
+		}
+		opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig(cfg.Retry.config())))
+		return otlploggrpc.New(ctx, opts...)
+	case ProtocolHTTPProtobuf:
+		var opts []otlploghttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.Timeout > 0 {
+			opts = append(opts, otlploghttp.WithTimeout(cfg.Timeout))
+		}
+		opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig(cfg.Retry.config())))
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("otel: unknown OTLP protocol %q (want %q or %q)", cfg.Protocol, ProtocolGRPC, ProtocolHTTPProtobuf)
+	}
+}
+
+// config returns r with the defaults applied, in the layout shared by the
+// exporters' RetryConfig.
+func (r OTLPRetry) config() otlploggrpc.RetryConfig {
+	c := otlploggrpc.RetryConfig{
+		Enabled:         !r.Disabled,
+		InitialInterval: r.InitialInterval,
+		MaxInterval:     r.MaxInterval,
+		MaxElapsedTime:  r.MaxElapsedTime,
+	}
+	if c.InitialInterval <= 0 {
+		c.InitialInterval = 5 * time.Second
+	}
+	if c.MaxInterval <= 0 {
+		c.MaxInterval = 30 * time.Second
+	}
+	if c.MaxElapsedTime <= 0 {
+		c.MaxElapsedTime = time.Minute
+	}
+	return c
+}
+
+// WriteRecord converts rec to an OpenTelemetry log record and queues it for
+// export. The trace_id, span_id and trace_flags fields (see ContextFields)
+// become the trace context of the log record instead of attributes.
+func (w *OTLPWriter) WriteRecord(rec *iris.Record) error {
+	now := time.Now()
+	ts := rec.Time()
+	if ts.IsZero() {
+		ts = now
+	}
+
+	var r otellog.Record
+	r.SetTimestamp(ts)
+	r.SetObservedTimestamp(now)
+	r.SetSeverity(severity(rec.Level))
+	r.SetSeverityText(rec.Level.String())
+	r.SetBody(otellog.StringValue(rec.Msg))
+
+	var sc trace.SpanContextConfig
+	for i := 0; i < rec.FieldCount(); i++ {
+		f := rec.GetField(i)
+		if f.IsString() && traceField(&sc, f.K, f.Str) {
+			continue
+		}
+		if kv, ok := attributeOf(f); ok {
+			r.AddAttributes(kv)
+		}
+	}
+	if rec.Logger != "" {
+		r.AddAttributes(otellog.String("logger", rec.Logger))
+	}
+	if rec.Caller != "" {
+		r.AddAttributes(otellog.String("caller", rec.Caller))
+	}
+	if rec.Stack != "" {
+		r.AddAttributes(otellog.String("stacktrace", rec.Stack))
+	}
+
+	ctx := context.Background()
+	if sc.TraceID.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(sc))
+	}
+	w.logger.Emit(ctx, r)
+	return nil
+}
+
+// Sync exports the records queued so far.
+func (w *OTLPWriter) Sync() error {
+	return w.provider.ForceFlush(context.Background())
+}
+
+// Close exports the queued records and shuts the exporter down. Records
+// written after Close are dropped.
+func (w *OTLPWriter) Close() error {
+	return w.provider.Shutdown(context.Background())
+}
+
+// severity maps an Iris level to an OpenTelemetry severity number. The
+// levels above Error are distinct steps of the FATAL range.
+func severity(level iris.Level) otellog.Severity {
+	switch level {
+	case iris.Trace:
+		return otellog.SeverityTrace1
+	case iris.Debug:
+		return otellog.SeverityDebug
+	case iris.Info:
+		return otellog.SeverityInfo
+	case iris.Warn:
+		return otellog.SeverityWarn
+	case iris.Error:
+		return otellog.SeverityError
+	case iris.DPanic:
+		return otellog.SeverityFatal1
+	case iris.Panic:
+		return otellog.SeverityFatal2
+	case iris.Fatal:
+		return otellog.SeverityFatal3
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// traceField stores a span context field in sc and reports whether key is
+// one of them.
+func traceField(sc *trace.SpanContextConfig, key, value string) bool {
+	switch key {
+	case TraceIDField:
+		if id, err := trace.TraceIDFromHex(value); err == nil {
+			sc.TraceID = id
+			return true
+		}
+	case SpanIDField:
+		if id, err := trace.SpanIDFromHex(value); err == nil {
+			sc.SpanID = id
+			return true
+		}
+	case TraceFlagsField:
+		if value == "01" {
+			sc.TraceFlags = trace.FlagsSampled
+			return true
+		}
+		if value == "00" {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeOf converts a field to a log attribute. Fields without a value
+// (sampling markers) have none.
+func attributeOf(f iris.Field) (otellog.KeyValue, bool) {
+	switch v := f.Value().(type) {
+	case nil:
+		return otellog.KeyValue{}, false
+	case string:
+		return otellog.String(f.K, v), true
+	case int64:
+		return otellog.Int64(f.K, v), true
+	case uint64:
+		if v > math.MaxInt64 {
+			return otellog.String(f.K, fmt.Sprint(v)), true
+		}
+		return otellog.Int64(f.K, int64(v)), true
+	case float64:
+		return otellog.Float64(f.K, v), true
+	case bool:
+		return otellog.Bool(f.K, v), true
+	case []byte:
+		// The record, and its byte fields, are reused once written
+		return otellog.Bytes(f.K, append([]byte(nil), v...)), true
+	case time.Time:
+		return otellog.String(f.K, v.Format(time.RFC3339Nano)), true
+	case error:
+		return otellog.String(f.K, v.Error()), true
+	case fmt.Stringer:
+		return otellog.String(f.K, v.String()), true
+	default:
+		return otellog.String(f.K, fmt.Sprint(v)), true
+	}
+}
@@ -0,0 +1,279 @@
+// otlp_writer_test.go: Tests for the OTLP log record exporter
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/agilira/iris"
+)
+
+// memoryExporter keeps the exported records.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i := range records {
+		e.records = append(e.records, records[i].Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *memoryExporter) get() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+// attributes returns the attributes of r by key.
+func attributes(r *sdklog.Record) map[string]otellog.Value {
+	attrs := make(map[string]otellog.Value)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestOTLPWriter_WriteRecord(t *testing.T) {
+	exp := &memoryExporter{}
+	w, err := NewOTLPWriter(context.Background(), OTLPConfig{Exporter: exp, ServiceName: "checkout", FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewOTLPWriter failed: %v", err)
+	}
+	logger, _ := newBufferLogger(t, iris.WithSyncWriter(w), WithTraceFields())
+	logger = logger.Named("payments")
+
+	payload := []byte("raw")
+	logger.InfoCtx(remoteContext(t, trace.FlagsSampled), "payment authorized",
+		iris.Str("user", "u-1"),
+		iris.Int("attempt", 2),
+		iris.Uint64("big", math.MaxUint64),
+		iris.Float64("amount", 12.5),
+		iris.Bool("retry", false),
+		iris.Dur("latency", 1500*time.Millisecond),
+		iris.Bytes("payload", payload),
+		iris.Secret("token", "hunter2"),
+		iris.NamedErr("cause", errors.New("timeout")))
+	payload[0] = 'X'
+	logger.Error("no trace")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	records := exp.get()
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	r := records[0]
+	if r.Body().AsString() != "payment authorized" || r.Severity() != otellog.SeverityInfo || r.SeverityText() != "info" {
+		t.Errorf("body/severity = %q, %v, %q", r.Body().AsString(), r.Severity(), r.SeverityText())
+	}
+	if r.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || r.SpanID().String() != "00f067aa0ba902b7" || !r.TraceFlags().IsSampled() {
+		t.Errorf("trace context = %s/%s/%s", r.TraceID(), r.SpanID(), r.TraceFlags())
+	}
+	if r.Timestamp().IsZero() || r.ObservedTimestamp().IsZero() {
+		t.Error("timestamps not set")
+	}
+	attrs := attributes(&r)
+	checks := []struct {
+		key  string
+		want string
+	}{
+		{"user", "u-1"},
+		{"attempt", "2"},
+		{"big", "18446744073709551615"},
+		{"amount", "12.5"},
+		{"retry", "false"},
+		{"latency", "1.5s"},
+		{"payload", "[114 97 119]"},
+		{"token", "[REDACTED]"},
+		{"cause", "timeout"},
+		{"logger", "payments"},
+	}
+	for _, c := range checks {
+		v, ok := attrs[c.key]
+		if !ok {
+			t.Errorf("attribute %s missing", c.key)
+			continue
+		}
+		if got := v.String(); got != c.want {
+			t.Errorf("attribute %s = %s, want %s", c.key, got, c.want)
+		}
+	}
+	for _, key := range []string{TraceIDField, SpanIDField, TraceFlagsField} {
+		if _, ok := attrs[key]; ok {
+			t.Errorf("%s exported as an attribute", key)
+		}
+	}
+	if attrs["attempt"].Kind() != otellog.KindInt64 || attrs["amount"].Kind() != otellog.KindFloat64 {
+		t.Errorf("numeric attributes exported as %v, %v", attrs["attempt"].Kind(), attrs["amount"].Kind())
+	}
+	if v, _ := r.Resource().Set().Value("service.name"); v.AsString() != "checkout" {
+		t.Errorf("service.name = %q", v.AsString())
+	}
+
+	r = records[1]
+	if r.Severity() != otellog.SeverityError || r.TraceID().IsValid() {
+		t.Errorf("second record: severity %v, trace %s", r.Severity(), r.TraceID())
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level iris.Level
+		want  otellog.Severity
+	}{
+		{iris.Trace, otellog.SeverityTrace1},
+		{iris.Debug, otellog.SeverityDebug},
+		{iris.Info, otellog.SeverityInfo},
+		{iris.Warn, otellog.SeverityWarn},
+		{iris.Error, otellog.SeverityError},
+		{iris.DPanic, otellog.SeverityFatal1},
+		{iris.Panic, otellog.SeverityFatal2},
+		{iris.Fatal, otellog.SeverityFatal3},
+	}
+	for _, tt := range tests {
+		if got := severity(tt.level); got != tt.want {
+			t.Errorf("severity(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+// logsCollector is an OTLP logs service recording the bodies it receives.
+type logsCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu      sync.Mutex
+	bodies  []string
+	service string
+}
+
+func (c *logsCollector) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rl := range req.GetResourceLogs() {
+		for _, attr := range rl.GetResource().GetAttributes() {
+			if attr.GetKey() == "service.name" {
+				c.service = attr.GetValue().GetStringValue()
+			}
+		}
+		for _, sl := range rl.GetScopeLogs() {
+			for _, lr := range sl.GetLogRecords() {
+				c.bodies = append(c.bodies, lr.GetBody().GetStringValue())
+			}
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// serveGRPC starts a gRPC collector and returns its address.
+func serveGRPC(t *testing.T, c *logsCollector) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, c)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// serveHTTP starts an HTTP/protobuf collector and returns its address.
+func serveHTTP(t *testing.T, c *logsCollector) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		req := &collogspb.ExportLogsServiceRequest{}
+		if err == nil {
+			err = proto.Unmarshal(body, req)
+		}
+		if r.URL.Path != "/v1/logs" || err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp, _ := c.Export(r.Context(), req)
+		out, _ := proto.Marshal(resp)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestNewOTLPWriter_Protocols(t *testing.T) {
+	tests := []struct {
+		protocol string
+		serve    func(*testing.T, *logsCollector) string
+	}{
+		{ProtocolGRPC, serveGRPC},
+		{ProtocolHTTPProtobuf, serveHTTP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			c := &logsCollector{}
+			w, err := NewOTLPWriter(context.Background(), OTLPConfig{
+				Protocol:    tt.protocol,
+				Endpoint:    tt.serve(t, c),
+				Insecure:    true,
+				Timeout:     5 * time.Second,
+				ServiceName: "checkout",
+			})
+			if err != nil {
+				t.Fatalf("NewOTLPWriter failed: %v", err)
+			}
+			logger, buf := newBufferLogger(t, iris.WithSyncWriter(w))
+			logger.Info("shipped")
+			logger.Warn("shipped too")
+
+			// Close flushes the records and shuts the exporter down
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if strings.Join(c.bodies, ",") != "shipped,shipped too" || c.service != "checkout" {
+				t.Errorf("collector got %v from %q", c.bodies, c.service)
+			}
+			if !strings.Contains(buf.String(), "shipped too") {
+				t.Errorf("main output = %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestNewOTLPWriter_UnknownProtocol(t *testing.T) {
+	if _, err := NewOTLPWriter(context.Background(), OTLPConfig{Protocol: "http/json"}); err == nil || !strings.Contains(err.Error(), "http/json") {
+		t.Errorf("err = %v, want unknown protocol", err)
+	}
+}
@@ -0,0 +1,84 @@
+// trace_fields.go: Span context injection into Iris records
+//
+// WithTracing builds a ContextLogger from the span that is recording when
+// it is called. Services that log with the context of each call instead
+// (InfoCtx, slog's InfoContext) need the trace identifiers added per call:
+// WithTraceFields installs ContextFields on the logger, so every record
+// logged with a context carrying a valid span context gets its trace_id,
+// span_id and trace_flags, and so does every ContextLogger created with
+// Logger.WithContext. WithOTelContext is the one-off variant for loggers
+// without the option.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agilira/iris"
+)
+
+// Field names of the span context, as in the OpenTelemetry log data model.
+const (
+	TraceIDField    = "trace_id"
+	SpanIDField     = "span_id"
+	TraceFlagsField = "trace_flags"
+)
+
+// ContextFields appends the trace_id, span_id and trace_flags (W3C hex,
+// "01" when sampled) of the span context of ctx to dst. Contexts without a
+// valid span context add nothing. Remote and non-recording spans are
+// included: their identifiers are what correlates the logs with the trace.
+//
+// It is an iris.ContextFieldsFunc.
+func ContextFields(ctx context.Context, dst []iris.Field) []iris.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return dst
+	}
+	return append(dst,
+		iris.Str(TraceIDField, sc.TraceID().String()),
+		iris.Str(SpanIDField, sc.SpanID().String()),
+		iris.Str(TraceFlagsField, sc.TraceFlags().String()))
+}
+
+// WithTraceFields returns an option adding the span context fields of
+// ContextFields to every record logged with a context: InfoCtx and the
+// other *Ctx methods, the slog handler, and the ContextLoggers created with
+// Logger.WithContext (extracted once, when they are created).
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, otel.WithTraceFields())
+//	...
+//	ctx, span := tracer.Start(r.Context(), "checkout")
+//	defer span.End()
+//	logger.InfoCtx(ctx, "payment authorized") // trace_id, span_id, trace_flags
+func WithTraceFields() iris.Option {
+	return iris.WithContextFields(ContextFields)
+}
+
+// noKeys extracts no context value, leaving the fields to ContextFields.
+var noKeys = &iris.ContextExtractor{}
+
+// WithOTelContext returns a ContextLogger logging the span context fields
+// of ctx with every record. The fields are extracted once, so the returned
+// logger is as cheap to log with as any ContextLogger and should not
+// outlive the span.
+//
+// A logger created with WithTraceFields already adds the fields in
+// Logger.WithContext; WithOTelContext is for loggers without the option
+// and would log them twice on one with it.
+//
+// Example:
+//
+//	cl := otel.WithOTelContext(logger, ctx)
+//	cl.Info("cart loaded", iris.Int("items", n))
+func WithOTelContext(logger *iris.Logger, ctx context.Context) *iris.ContextLogger {
+	return logger.WithContextExtractor(ctx, noKeys).With(ContextFields(ctx, nil)...)
+}
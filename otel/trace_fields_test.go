@@ -0,0 +1,107 @@
+// trace_fields_test.go: Tests for span context injection
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agilira/iris"
+)
+
+// remoteContext returns a context carrying a remote, non-recording span
+// context, as extracted from an incoming request.
+func remoteContext(t *testing.T, flags trace.TraceFlags) context.Context {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags, Remote: true})
+	return trace.ContextWithRemoteSpanContext(context.Background(), sc)
+}
+
+// newBufferLogger returns an inline logger writing JSON to a buffer.
+func newBufferLogger(t *testing.T, opts ...iris.Option) (*iris.Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := iris.New(iris.Config{Level: iris.Debug, Output: iris.WrapWriter(&buf), Encoder: iris.NewJSONEncoder(), Inline: true}, opts...)
+	if err != nil {
+		t.Fatalf("iris.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, &buf
+}
+
+func TestContextFields(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []iris.Field
+	}{
+		{"no span", context.Background(), nil},
+		{"sampled", remoteContext(t, trace.FlagsSampled), []iris.Field{
+			iris.Str(TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736"),
+			iris.Str(SpanIDField, "00f067aa0ba902b7"),
+			iris.Str(TraceFlagsField, "01"),
+		}},
+		{"not sampled", remoteContext(t, 0), []iris.Field{
+			iris.Str(TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736"),
+			iris.Str(SpanIDField, "00f067aa0ba902b7"),
+			iris.Str(TraceFlagsField, "00"),
+		}},
+	}
+	for _, tt := range tests {
+		got := ContextFields(tt.ctx, nil)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d fields, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i].K != tt.want[i].K || got[i].Str != tt.want[i].Str {
+				t.Errorf("%s: field %d = %s=%q, want %s=%q", tt.name, i, got[i].K, got[i].Str, tt.want[i].K, tt.want[i].Str)
+			}
+		}
+	}
+}
+
+func TestWithTraceFields(t *testing.T) {
+	logger, buf := newBufferLogger(t, WithTraceFields())
+	ctx := remoteContext(t, trace.FlagsSampled)
+
+	logger.InfoCtx(ctx, "per call")
+	logger.WithContext(ctx).Warn("context logger")
+	logger.InfoCtx(context.Background(), "no span")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines[:2] {
+		for _, want := range []string{`"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`, `"span_id":"00f067aa0ba902b7"`, `"trace_flags":"01"`} {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s does not contain %s", line, want)
+			}
+		}
+	}
+	if strings.Contains(lines[2], "trace_id") {
+		t.Errorf("record without a span got trace fields: %s", lines[2])
+	}
+}
+
+func TestWithOTelContext(t *testing.T) {
+	logger, buf := newBufferLogger(t)
+	cl := WithOTelContext(logger, remoteContext(t, trace.FlagsSampled))
+	cl.Info("one-off", iris.Str("k", "v"))
+
+	line := buf.String()
+	if strings.Count(line, `"trace_id"`) != 1 || !strings.Contains(line, `"span_id":"00f067aa0ba902b7"`) || !strings.Contains(line, `"k":"v"`) {
+		t.Errorf("line = %s", line)
+	}
+}
@@ -0,0 +1,109 @@
+// panic_capture.go: Capturing Panic and DPanic records in tests
+//
+// Panic always panics, and DPanic does in development mode. Asserting that
+// code under test would have panicked normally means wrapping each call in
+// a recover. With WithPanicCapture the logger records those panics in a
+// PanicCapture instead, with the message, fields and stack of the logging
+// call, and execution continues after the logging call.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import "sync"
+
+// CapturedPanic is a Panic or DPanic call that would have panicked.
+type CapturedPanic struct {
+	Level   Level   // Panic or DPanic
+	Message string  // Message passed to the logging call
+	Fields  []Field // Fields passed to the logging call (a copy)
+	Logger  string  // Name of the logger
+	Stack   string  // Stack of the logging call, innermost frame first
+}
+
+// PanicCapture collects the panics of loggers built with WithPanicCapture.
+// It is safe for concurrent use.
+type PanicCapture struct {
+	mu     sync.Mutex
+	panics []CapturedPanic
+}
+
+// NewPanicCapture returns an empty capture buffer.
+func NewPanicCapture() *PanicCapture {
+	return &PanicCapture{}
+}
+
+// Len returns the number of captured panics.
+func (c *PanicCapture) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.panics)
+}
+
+// All returns a copy of the captured panics, oldest first.
+func (c *PanicCapture) All() []CapturedPanic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CapturedPanic(nil), c.panics...)
+}
+
+// TakeAll returns the captured panics and empties the buffer.
+func (c *PanicCapture) TakeAll() []CapturedPanic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	panics := c.panics
+	c.panics = nil
+	return panics
+}
+
+// WithPanicCapture makes Panic, and DPanic in development mode, record the
+// panic in c instead of panicking. The record is still logged as usual.
+// Intended for tests; a nil c is a no-op.
+//
+// Parameters:
+//   - c: Buffer receiving the captured panics
+//
+// Returns:
+//   - Option: Configuration function to capture panics
+//
+// Example:
+//
+//	panics := iris.NewPanicCapture()
+//	logger, _ := iris.New(iris.Config{Output: out}, iris.Development(), iris.WithPanicCapture(panics))
+//	svc := NewService(logger)
+//	svc.Handle(badRequest)
+//	if panics.Len() != 1 {
+//	    t.Fatal("expected Handle to DPanic on a bad request")
+//	}
+func WithPanicCapture(c *PanicCapture) Option {
+	return func(o *loggerOptions) {
+		o.panics = c
+	}
+}
+
+// panicOrCapture panics with msg, or records the panic when the logger
+// captures panics. It must be called directly by the logging method.
+func (l *Logger) panicOrCapture(level Level, msg string, fields []Field) {
+	c := l.opts.panics
+	if c == nil {
+		panic(msg)
+	}
+	var stack string
+	if l.opts.scrubPaths {
+		stack = scrubbedStacktrace(2) // Skip panicOrCapture and the logging method
+	} else {
+		stack = fastStacktrace(2)
+	}
+	p := CapturedPanic{
+		Level:   level,
+		Message: msg,
+		Fields:  append([]Field(nil), fields...),
+		Logger:  l.name,
+		Stack:   stack,
+	}
+	c.mu.Lock()
+	c.panics = append(c.panics, p)
+	c.mu.Unlock()
+}
@@ -0,0 +1,82 @@
+// panic_capture_test.go: Tests for capturing Panic and DPanic in tests
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+// wouldPanic stands in for code under test that panics through the logger.
+func wouldPanic(l *Logger, id int) {
+	l.Panic("invariant broken", Int("id", id))
+}
+
+func TestWithPanicCapture(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		development bool
+		want        []Level
+	}{
+		{"production", false, []Level{Panic}},
+		{"development", true, []Level{DPanic, Panic}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &testSyncer{}
+			panics := NewPanicCapture()
+			opts := []Option{WithPanicCapture(panics)}
+			if tc.development {
+				opts = append(opts, Development())
+			}
+			logger, err := New(Config{Level: Debug, Output: out, Name: "svc", Inline: true}, opts...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			logger.DPanic("suspicious", Str("k", "v"))
+			wouldPanic(logger, 7) // Returns instead of panicking
+
+			got := panics.All()
+			if len(got) != len(tc.want) {
+				t.Fatalf("captured %d panics, want %d", len(got), len(tc.want))
+			}
+			for i, p := range got {
+				if p.Level != tc.want[i] || p.Logger != "svc" {
+					t.Errorf("panic %d: level %s logger %q, want %s from svc", i, p.Level, p.Logger, tc.want[i])
+				}
+			}
+			last := got[len(got)-1]
+			if last.Message != "invariant broken" || len(last.Fields) != 1 || last.Fields[0].I64 != 7 {
+				t.Errorf("captured %q with %v, want the message and fields of the call", last.Message, last.Fields)
+			}
+			if first, _, _ := strings.Cut(last.Stack, "\n"); !strings.Contains(first, "wouldPanic") {
+				t.Errorf("stack starts at %q, want the function that called Panic", first)
+			}
+			if strings.Count(out.String(), "\n") != 2 {
+				t.Errorf("captured panics were not logged: %q", out.String())
+			}
+
+			if taken := panics.TakeAll(); len(taken) != len(tc.want) || panics.Len() != 0 {
+				t.Errorf("TakeAll returned %d panics and left %d", len(taken), panics.Len())
+			}
+		})
+	}
+}
+
+func TestPanic_WithoutCapture(t *testing.T) {
+	logger, err := New(Config{Level: Debug, Output: &testSyncer{}, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() {
+		if r := recover(); r != "still panics" {
+			t.Errorf("recovered %v, want the Panic message", r)
+		}
+	}()
+	logger.Panic("still panics")
+	t.Error("Panic returned without WithPanicCapture")
+}
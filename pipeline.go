@@ -0,0 +1,488 @@
+// pipeline.go: Declarative consumer pipeline (processors → sinks)
+//
+// The consumer grew one feature at a time: path scrubbing, host fields,
+// additional outputs, classification. A PipelineConfig declares what the
+// consumer does with the records of a logger in one place and in the order
+// it happens: processors (redaction, dedupe, enrichment, routing) run on
+// each record before it is encoded, then the record is written to
+// Config.Output and to the declared sinks. It can be written in the
+// "pipeline" object of a JSON config file and is assembled by New.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// Processor types of a PipelineConfig.
+const (
+	ProcessorRedact = "redact" // Replace the values of the fields named in Keys with [REDACTED]
+	ProcessorDedupe = "dedupe" // Drop repeats of a record within Window
+	ProcessorEnrich = "enrich" // Add Fields (and host information with Host)
+	ProcessorRoute  = "route"  // Send records at or above Level to Sink (and only there with Exclusive)
+)
+
+// DuplicatesKey is the field the dedupe processor adds to the first record
+// written after a window in which repeats of it were dropped, with their
+// number.
+const DuplicatesKey = "_duplicates"
+
+const (
+	// maxPipelineSinks is the number of sinks a pipeline can route to
+	// (one Record.routes bit each)
+	maxPipelineSinks = 32
+
+	// maxDedupeKeys bounds the records remembered by a dedupe processor
+	maxDedupeKeys = 4096
+)
+
+// PipelineStage processes a record in the consumer, before it is encoded.
+// It may change the record's fields and returns false to drop it, which is
+// counted as DropFiltered. Stages run on the consumer one record at a time,
+// so they need no locking, but they must not block or log through the same
+// logger.
+type PipelineStage func(rec *Record) bool
+
+// PipelineConfig declares the consumer-side processing of a logger.
+//
+// Processors run in the order they are declared on every record; a record
+// dropped by one is not seen by the next. Sinks receive the records after
+// Config.Output: every record, or only the records routed to them when a
+// route processor names them.
+//
+// The struct carries json and yaml tags, so a pipeline can be decoded from
+// either format; LoadConfigFromJSON reads it from the "pipeline" object.
+//
+// Example:
+//
+//	{
+//	  "pipeline": {
+//	    "processors": [
+//	      {"type": "redact", "keys": ["password", "token"]},
+//	      {"type": "dedupe", "window": "10s", "loggers": ["db"]},
+//	      {"type": "enrich", "fields": {"service": "checkout"}, "host": true},
+//	      {"type": "route", "level": "error", "sink": "alerts"}
+//	    ],
+//	    "sinks": [
+//	      {"name": "alerts", "format": "json", "output": "/var/log/app/alerts.log"}
+//	    ]
+//	  }
+//	}
+type PipelineConfig struct {
+	Processors []ProcessorConfig `json:"processors,omitempty" yaml:"processors,omitempty"`
+	Sinks      []SinkConfig      `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// ProcessorConfig declares one processor of a pipeline. Type selects the
+// processor; the other fields are its parameters.
+type ProcessorConfig struct {
+	Type string `json:"type" yaml:"type"` // ProcessorRedact, ProcessorDedupe, ProcessorEnrich or ProcessorRoute
+
+	// Loggers restricts the processor to records of these logger names
+	// and of the loggers derived from them with Named ("db" matches "db"
+	// and "db.pool"). Empty applies it to every record.
+	Loggers []string `json:"loggers,omitempty" yaml:"loggers,omitempty"`
+
+	Keys   []string       `json:"keys,omitempty" yaml:"keys,omitempty"`     // redact: field keys, case-insensitive
+	Window string         `json:"window,omitempty" yaml:"window,omitempty"` // dedupe: Go duration, e.g. "10s"
+	Fields map[string]any `json:"fields,omitempty" yaml:"fields,omitempty"` // enrich: strings, numbers or booleans
+	Host   bool           `json:"host,omitempty" yaml:"host,omitempty"`     // enrich: add the host and ip fields
+	Level  string         `json:"level,omitempty" yaml:"level,omitempty"`   // route: minimum level ("" = all)
+	Sink   string         `json:"sink,omitempty" yaml:"sink,omitempty"`     // route: name of the sink
+
+	// Exclusive (route) keeps the routed records out of Config.Output, so
+	// that each record is written to one place only
+	Exclusive bool `json:"exclusive,omitempty" yaml:"exclusive,omitempty"`
+}
+
+// SinkConfig declares an output of a pipeline.
+type SinkConfig struct {
+	Name   string `json:"name" yaml:"name"`                         // Referenced by route processors
+	Format string `json:"format,omitempty" yaml:"format,omitempty"` // json (default), text, console, binary or a RegisterEncoder name
+	Output string `json:"output" yaml:"output"`                     // stdout, stderr or a file path
+}
+
+// WithPipelineStage adds a stage to the consumer pipeline, after the
+// processors of Config.Pipeline and the stages added before it. Like the
+// other consumer options it must be passed to New.
+//
+// Parameters:
+//   - s: Stage to run on every record (nil is ignored)
+//
+// Returns:
+//   - Option: Configuration function to add the stage
+//
+// Example:
+//
+//	// Drop health-check noise before it is encoded
+//	logger, err := iris.New(cfg, iris.WithPipelineStage(func(rec *iris.Record) bool {
+//		return rec.Msg != "health check"
+//	}))
+func WithPipelineStage(s PipelineStage) Option {
+	return func(o *loggerOptions) {
+		if s == nil {
+			return
+		}
+		o.stages = append(o.stages[:len(o.stages):len(o.stages)], s)
+	}
+}
+
+// Validate checks the pipeline without opening its sinks: processor types
+// and parameters, sink names, formats and outputs, and that every route
+// names a declared sink.
+//
+// Returns:
+//   - error: ErrCodeInvalidConfig locating the offending entry (e.g.
+//     "pipeline.processors[1].window: ..."), or nil
+func (p *PipelineConfig) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// compiledPipeline is a validated pipeline whose sinks are not open yet.
+type compiledPipeline struct {
+	stages    []PipelineStage
+	routed    []bool // Per sink: receives only the records routed to it
+	exclusive uint32 // Record.routes bits of the sinks routed to exclusively
+}
+
+// compile validates the pipeline and builds its stages.
+func (p *PipelineConfig) compile() (*compiledPipeline, error) {
+	if len(p.Sinks) > maxPipelineSinks {
+		return nil, pipelineError("sinks", fmt.Sprintf("%d sinks, at most %d are supported", len(p.Sinks), maxPipelineSinks))
+	}
+	sinks := make(map[string]int, len(p.Sinks))
+	for i, s := range p.Sinks {
+		key := fmt.Sprintf("sinks[%d]", i)
+		switch _, known := sinkEncoder(s.Format); {
+		case s.Name == "":
+			return nil, pipelineError(key+".name", "sink name is required")
+		case !known:
+			return nil, pipelineError(key+".format", fmt.Sprintf("unknown format %q (want json, text, console, binary or a registered encoder)", s.Format))
+		case s.Output == "":
+			return nil, pipelineError(key+".output", "sink output is required")
+		}
+		if _, dup := sinks[s.Name]; dup {
+			return nil, pipelineError(key+".name", fmt.Sprintf("duplicate sink name %q", s.Name))
+		}
+		sinks[s.Name] = i
+	}
+
+	c := &compiledPipeline{routed: make([]bool, len(p.Sinks))}
+	for i, pc := range p.Processors {
+		stage, err := pc.stage(fmt.Sprintf("processors[%d]", i), sinks, c.routed)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(pc.Type, ProcessorRoute) && pc.Exclusive {
+			c.exclusive |= uint32(1) << sinks[pc.Sink] // #nosec G115 -- index < maxPipelineSinks
+		}
+		c.stages = append(c.stages, matchLoggers(pc.Loggers, stage))
+	}
+	return c, nil
+}
+
+// stage builds the stage of a processor; key names it in errors. A route
+// marks its sink in routed.
+func (pc *ProcessorConfig) stage(key string, sinks map[string]int, routed []bool) (PipelineStage, error) {
+	switch strings.ToLower(pc.Type) {
+	case ProcessorRedact:
+		if len(pc.Keys) == 0 {
+			return nil, pipelineError(key+".keys", "redact needs at least one key")
+		}
+		return redactStage(append([]string(nil), pc.Keys...)), nil
+
+	case ProcessorDedupe:
+		window, err := time.ParseDuration(pc.Window)
+		if err != nil || window <= 0 {
+			return nil, pipelineError(key+".window", fmt.Sprintf("dedupe needs a positive duration, got %q", pc.Window))
+		}
+		d := &dedupeStage{window: window, now: timecache.CachedTime, seen: make(map[dedupeKey]*dedupeEntry)}
+		return d.process, nil
+
+	case ProcessorEnrich:
+		fields, err := pipelineFields(pc.Fields)
+		if err != nil {
+			return nil, pipelineError(key+".fields", err.Error())
+		}
+		if len(fields) == 0 && !pc.Host {
+			return nil, pipelineError(key, "enrich needs fields or host")
+		}
+		return enrichStage(fields, pc.Host), nil
+
+	case ProcessorRoute:
+		i, ok := sinks[pc.Sink]
+		if !ok {
+			return nil, pipelineError(key+".sink", fmt.Sprintf("route to undeclared sink %q", pc.Sink))
+		}
+		all := pc.Level == ""
+		var min Level
+		if !all {
+			var err error
+			if min, err = ParseLevel(pc.Level); err != nil {
+				return nil, pipelineError(key+".level", fmt.Sprintf("unknown level %q", pc.Level))
+			}
+		}
+		routed[i] = true
+		bit := uint32(1) << i // #nosec G115 -- i < maxPipelineSinks
+		return func(rec *Record) bool {
+			if all || rec.Level.Enabled(min) {
+				rec.routes |= bit
+			}
+			return true
+		}, nil
+	}
+	return nil, pipelineError(key+".type", fmt.Sprintf("unknown processor type %q (want redact, dedupe, enrich or route)", pc.Type))
+}
+
+// pipelineError reports an invalid pipeline entry; key locates it, as in
+// "processors[2].window".
+func pipelineError(key, msg string) error {
+	return NewLoggerErrorWithField(ErrCodeInvalidConfig, "pipeline."+key+": "+msg, "pipeline."+key, "")
+}
+
+// options validates the pipeline, opens its sinks and returns the options
+// that install it. Files opened before an error are closed again.
+func (p *PipelineConfig) options() ([]Option, error) {
+	c, err := p.compile()
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]Option, 0, len(c.stages)+2*len(p.Sinks)+1)
+	for _, s := range c.stages {
+		opts = append(opts, WithPipelineStage(s))
+	}
+	if c.exclusive != 0 {
+		opts = append(opts, func(o *loggerOptions) { o.exclusiveRoutes |= c.exclusive })
+	}
+	var opened []io.Closer
+	for i, s := range p.Sinks {
+		out, file, err := openSinkOutput(s.Output)
+		if err != nil {
+			for _, f := range opened {
+				_ = f.Close()
+			}
+			return nil, err
+		}
+		if file != nil {
+			opened = append(opened, file)
+			opts = append(opts, withOwnedOutput(file))
+		}
+		var route uint32
+		if c.routed[i] {
+			route = uint32(1) << i // #nosec G115 -- i < maxPipelineSinks
+		}
+		enc, _ := sinkEncoder(s.Format)
+		opts = append(opts, withRoutedOutput(enc, out, route, s.Name))
+	}
+	return opts, nil
+}
+
+// runStages runs the pipeline stages on rec and reports whether it is kept.
+func (l *Logger) runStages(rec *Record) bool {
+	for _, s := range l.opts.stages {
+		if !s(rec) {
+			return false
+		}
+	}
+	return true
+}
+
+// discardFiltered drops a record rejected by a pipeline stage.
+func (l *Logger) discardFiltered(rec *Record) {
+	l.countDropped()
+	l.recordDrop(DropFiltered, rec.Level)
+	rec.resetForWrite()
+	if l.opts.recordDebug {
+		sealRecord(rec)
+	}
+}
+
+// matchLoggers restricts s to the records of the named loggers and their
+// children; other records pass through unchanged.
+func matchLoggers(names []string, s PipelineStage) PipelineStage {
+	if len(names) == 0 {
+		return s
+	}
+	names = append([]string(nil), names...)
+	return func(rec *Record) bool {
+		for _, n := range names {
+			if strings.HasPrefix(rec.Logger, n) && (len(rec.Logger) == len(n) || rec.Logger[len(n)] == '.') {
+				return s(rec)
+			}
+		}
+		return true
+	}
+}
+
+// redactStage replaces the values of the fields named in keys.
+func redactStage(keys []string) PipelineStage {
+	return func(rec *Record) bool {
+		for i := int32(0); i < rec.n; i++ {
+			f := &rec.fields[i]
+			for _, k := range keys {
+				if strings.EqualFold(f.K, k) {
+					*f = Field{K: f.K, T: kindSecret}
+					break
+				}
+			}
+		}
+		return true
+	}
+}
+
+// enrichStage appends fields, and the host fields if host is set.
+func enrichStage(fields []Field, host bool) PipelineStage {
+	return func(rec *Record) bool {
+		for _, f := range fields {
+			rec.AddField(f)
+		}
+		if host {
+			rec.AddField(Host())
+			rec.AddField(LocalIP())
+		}
+		return true
+	}
+}
+
+// dedupeKey identifies repeats of a record.
+type dedupeKey struct {
+	level  Level
+	logger string
+	msg    string
+}
+
+// dedupeEntry is the current window of a record.
+type dedupeEntry struct {
+	since      time.Time
+	suppressed int64
+}
+
+// dedupeStage drops repeats of a record within window of its first
+// occurrence and reports their number on the next one written.
+type dedupeStage struct {
+	window time.Duration
+	now    func() time.Time
+	seen   map[dedupeKey]*dedupeEntry
+}
+
+// process is the PipelineStage of d.
+func (d *dedupeStage) process(rec *Record) bool {
+	now := d.now()
+	e := d.seen[dedupeKey{rec.Level, rec.Logger, rec.Msg}]
+	if e == nil {
+		if len(d.seen) >= maxDedupeKeys {
+			d.prune(now)
+		}
+		// The record's strings may be borrowed (UnsafeString): keep copies
+		d.seen[dedupeKey{rec.Level, strings.Clone(rec.Logger), strings.Clone(rec.Msg)}] = &dedupeEntry{since: now}
+		return true
+	}
+	if now.Sub(e.since) < d.window {
+		e.suppressed++
+		return false
+	}
+	if e.suppressed > 0 {
+		rec.AddField(Int64(DuplicatesKey, e.suppressed))
+	}
+	e.since, e.suppressed = now, 0
+	return true
+}
+
+// prune forgets the records whose window has passed, or every record if
+// all windows are still open.
+func (d *dedupeStage) prune(now time.Time) {
+	for k, e := range d.seen {
+		if now.Sub(e.since) >= d.window {
+			delete(d.seen, k)
+		}
+	}
+	if len(d.seen) >= maxDedupeKeys {
+		clear(d.seen)
+	}
+}
+
+// pipelineFields converts the fields of an enrich processor, sorted by key.
+// Integral numbers become Int64 fields, others Float64.
+func pipelineFields(m map[string]any) ([]Field, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("empty field key")
+		}
+		switch v := m[k].(type) {
+		case string:
+			fields = append(fields, Str(k, v))
+		case bool:
+			fields = append(fields, Bool(k, v))
+		case int:
+			fields = append(fields, Int64(k, int64(v)))
+		case int64:
+			fields = append(fields, Int64(k, v))
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				fields = append(fields, Int64(k, int64(v)))
+			} else {
+				fields = append(fields, Float64(k, v))
+			}
+		default:
+			return nil, fmt.Errorf("field %q must be a string, number or boolean", k)
+		}
+	}
+	return fields, nil
+}
+
+// sinkEncoder returns the encoder for a sink format. The json, text and
+// console spellings mirror LoadConfigFromJSON; other names are resolved
+// through RegisterEncoder.
+func sinkEncoder(format string) (Encoder, bool) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return NewJSONEncoder(), true
+	case "text", "console":
+		return NewTextEncoder(), true
+	case "binary":
+		return NewBinaryEncoder(), true
+	case "gelf":
+		return NewGELFEncoder(), true
+	}
+	return registeredEncoder(format)
+}
+
+// openSinkOutput opens a sink output. The file is returned for the logger
+// to close, and is nil for stdout and stderr.
+func openSinkOutput(output string) (WriteSyncer, *os.File, error) {
+	switch strings.ToLower(output) {
+	case "stdout":
+		return WrapWriter(os.Stdout), nil, nil
+	case "stderr":
+		return WrapWriter(os.Stderr), nil, nil
+	}
+	if err := validateFilePath(output); err != nil {
+		return nil, nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid sink output: "+err.Error(), "path", output)
+	}
+	cleanPath := filepath.Clean(output)
+	file, err := os.OpenFile(cleanPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path validated above
+	if err != nil {
+		return nil, nil, NewLoggerErrorWithField(ErrCodeFileOpen, "failed to open sink output: "+err.Error(), "path", cleanPath)
+	}
+	return NewFileSyncer(file), file, nil
+}
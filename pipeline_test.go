@@ -0,0 +1,182 @@
+// pipeline_test.go: Tests for the declarative consumer pipeline
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline_ProcessorsAndSinks(t *testing.T) {
+	alerts := filepath.Join(t.TempDir(), "alerts.log")
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, Pipeline: &PipelineConfig{
+		Processors: []ProcessorConfig{
+			{Type: ProcessorRedact, Keys: []string{"Password"}},
+			{Type: ProcessorEnrich, Fields: map[string]any{"service": "checkout", "shard": float64(7)}},
+			{Type: ProcessorEnrich, Fields: map[string]any{"pool": "primary"}, Loggers: []string{"db"}},
+			{Type: ProcessorRoute, Level: "error", Sink: "alerts"},
+		},
+		Sinks: []SinkConfig{{Name: "alerts", Output: alerts}},
+	}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Info("login", Str("user", "ada"), Str("password", "hunter2"))
+	logger.Named("db").Error("query failed")
+	logger.Named("dbx").Info("other component")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	login := findLine(out.String(), "login")
+	for _, want := range []string{`"user":"ada"`, `"password":"[REDACTED]"`, `"service":"checkout"`, `"shard":7`} {
+		if !strings.Contains(login, want) {
+			t.Errorf("login line %s does not contain %s", login, want)
+		}
+	}
+	if strings.Contains(login, "hunter2") || strings.Contains(login, "pool") {
+		t.Errorf("login line %s leaks the password or got db fields", login)
+	}
+	if line := findLine(out.String(), "query failed"); !strings.Contains(line, `"pool":"primary"`) {
+		t.Errorf("db line %s not enriched", line)
+	}
+	if line := findLine(out.String(), "other component"); strings.Contains(line, `"pool"`) {
+		t.Errorf("dbx line %s matched the db processor", line)
+	}
+
+	routed, err := os.ReadFile(alerts)
+	if err != nil {
+		t.Fatalf("reading sink: %v", err)
+	}
+	if lines := strings.Count(string(routed), "\n"); lines != 1 || findLine(string(routed), "query failed") == "" {
+		t.Errorf("alerts sink got %d lines, want only the error:\n%s", lines, routed)
+	}
+}
+
+func TestPipeline_Dedupe(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := &dedupeStage{window: 10 * time.Second, now: func() time.Time { return now }, seen: make(map[dedupeKey]*dedupeEntry)}
+
+	steps := []struct {
+		advance time.Duration
+		level   Level
+		msg     string
+		keep    bool
+		dups    int64 // Expected DuplicatesKey value, 0 = none
+	}{
+		{0, Warn, "retrying", true, 0},
+		{time.Second, Warn, "retrying", false, 0},
+		{time.Second, Warn, "retrying", false, 0},
+		{0, Error, "retrying", true, 0}, // Different level
+		{time.Second, Info, "connected", true, 0},
+		{10 * time.Second, Warn, "retrying", true, 2},
+		{time.Second, Warn, "retrying", false, 0},
+	}
+	for i, s := range steps {
+		now = now.Add(s.advance)
+		rec := NewRecord(s.level, s.msg)
+		if keep := d.process(rec); keep != s.keep {
+			t.Fatalf("step %d: keep = %v, want %v", i, keep, s.keep)
+		}
+		var dups int64
+		if rec.FieldCount() > 0 && rec.GetField(0).K == DuplicatesKey {
+			dups = rec.GetField(0).I64
+		}
+		if dups != s.dups {
+			t.Errorf("step %d: %s = %d, want %d", i, DuplicatesKey, dups, s.dups)
+		}
+	}
+}
+
+func TestPipeline_DroppedRecordsCounted(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, Pipeline: &PipelineConfig{
+		Processors: []ProcessorConfig{{Type: ProcessorDedupe, Window: "1h"}},
+	}}, WithPipelineStage(func(rec *Record) bool { return rec.Msg != "health check" }))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	for i := 0; i < 3; i++ {
+		logger.Info("cache miss")
+		logger.Info("health check")
+	}
+	if got := strings.Count(out.String(), "\n"); got != 1 {
+		t.Errorf("wrote %d records, want 1:\n%s", got, out.String())
+	}
+	if stats := logger.Stats(); stats["dropped_filtered"] != 5 {
+		t.Errorf("dropped_filtered = %d, want 5", stats["dropped_filtered"])
+	}
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	sink := []SinkConfig{{Name: "alerts", Output: "stderr"}}
+	tests := []struct {
+		name string
+		cfg  PipelineConfig
+		want string // Substring of the error, "" = valid
+	}{
+		{"Empty", PipelineConfig{}, ""},
+		{"Valid", PipelineConfig{Processors: []ProcessorConfig{{Type: "Route", Sink: "alerts"}}, Sinks: sink}, ""},
+		{"UnknownType", PipelineConfig{Processors: []ProcessorConfig{{Type: "sample"}}}, "processors[0].type"},
+		{"RedactNoKeys", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorRedact}}}, "processors[0].keys"},
+		{"DedupeWindow", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorDedupe, Window: "-1s"}}}, "processors[0].window"},
+		{"EnrichEmpty", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorEnrich}}}, "enrich needs fields or host"},
+		{"EnrichNested", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorEnrich, Fields: map[string]any{"a": []any{1}}}}}, "processors[0].fields"},
+		{"RouteLevel", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorRoute, Sink: "alerts", Level: "loud"}}, Sinks: sink}, "processors[0].level"},
+		{"RouteSink", PipelineConfig{Processors: []ProcessorConfig{{Type: ProcessorRoute, Sink: "pager"}}, Sinks: sink}, `undeclared sink "pager"`},
+		{"SinkFormat", PipelineConfig{Sinks: []SinkConfig{{Name: "a", Format: "xml", Output: "stdout"}}}, "sinks[0].format"},
+		{"SinkOutput", PipelineConfig{Sinks: []SinkConfig{{Name: "a"}}}, "sinks[0].output"},
+		{"SinkDuplicate", PipelineConfig{Sinks: append(sink, sink...)}, "sinks[1].name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("error %v does not contain %q", err, tt.want)
+			case err != nil && !IsLoggerError(err, ErrCodeInvalidConfig):
+				t.Errorf("error %v is not ErrCodeInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestPipeline_LoadConfigFromJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iris.json")
+	data := `{"output": "stdout", "pipeline": {
+		"processors": [{"type": "redact", "keys": ["token"]}, {"type": "route", "level": "warn", "sink": "audit"}],
+		"sinks": [{"name": "audit", "format": "binary", "output": "stderr"}]}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	if cfg.Pipeline == nil || len(cfg.Pipeline.Processors) != 2 || cfg.Pipeline.Sinks[0].Format != "binary" {
+		t.Fatalf("pipeline not loaded: %+v", cfg.Pipeline)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"pipeline": {"processors": [{"type": "route", "sink": "audit"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigFromJSON(bad); err == nil || !strings.Contains(err.Error(), "processors[0].sink") {
+		t.Errorf("expected a pipeline error, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// pressure.go: Load-shedding aware health status for Iris logging library
+//
+// Stats() exposes raw counters, but a health endpoint or an autoscaler needs
+// a verdict: is logging keeping up, is it shedding records, or has the
+// consumer stopped? Pressure() derives that verdict from ring utilization,
+// the ring-full drop rate and (with WithLatencyHistograms) the mean
+// end-to-end latency observed since the previous call.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"time"
+)
+
+// Pressure is the load level of a logger, from healthy to stalled.
+// Levels are ordered, so callers can compare them (p >= PressureShedding).
+type Pressure uint8
+
+const (
+	// PressureOK: the consumer keeps up with producers
+	PressureOK Pressure = iota
+	// PressureElevated: the ring is filling up, latency is high or a few
+	// records were dropped; no action needed yet
+	PressureElevated
+	// PressureShedding: records are being dropped because the ring is full
+	PressureShedding
+	// PressureStalled: records are buffered but none was written for
+	// PressureThresholds.StallAfter, or the logger is closed
+	PressureStalled
+)
+
+// String returns the lowercase name of the pressure level.
+func (p Pressure) String() string {
+	switch p {
+	case PressureOK:
+		return "ok"
+	case PressureElevated:
+		return "elevated"
+	case PressureShedding:
+		return "shedding"
+	case PressureStalled:
+		return "stalled"
+	default:
+		return "unknown"
+	}
+}
+
+// PressureThresholds configures how Pressure() classifies a logger.
+type PressureThresholds struct {
+	// ElevatedUtilization is the ring utilization (percent) from which the
+	// logger is Elevated (default 50)
+	ElevatedUtilization int64
+
+	// ElevatedLatency is the mean end-to-end latency from which the logger
+	// is Elevated; only used with WithLatencyHistograms (default 10ms)
+	ElevatedLatency time.Duration
+
+	// SheddingDropRate is the fraction of records dropped because the ring
+	// was full from which the logger is Shedding; any lower non-zero rate
+	// is Elevated (default 0.01)
+	SheddingDropRate float64
+
+	// StallAfter is how long buffered records may wait without any record
+	// being written before the logger is Stalled (default 5s)
+	StallAfter time.Duration
+}
+
+// DefaultPressureThresholds returns the thresholds used by Pressure() unless
+// WithPressureThresholds is given.
+func DefaultPressureThresholds() PressureThresholds {
+	return PressureThresholds{
+		ElevatedUtilization: 50,
+		ElevatedLatency:     10 * time.Millisecond,
+		SheddingDropRate:    0.01,
+		StallAfter:          5 * time.Second,
+	}
+}
+
+// WithPressureThresholds overrides the thresholds used by Pressure(). Zero
+// fields keep their default.
+//
+// Parameters:
+//   - t: Thresholds to apply
+//
+// Returns:
+//   - Option: Configuration function to set the pressure thresholds
+func WithPressureThresholds(t PressureThresholds) Option {
+	return func(o *loggerOptions) {
+		d := DefaultPressureThresholds()
+		if t.ElevatedUtilization <= 0 {
+			t.ElevatedUtilization = d.ElevatedUtilization
+		}
+		if t.ElevatedLatency <= 0 {
+			t.ElevatedLatency = d.ElevatedLatency
+		}
+		if t.SheddingDropRate <= 0 {
+			t.SheddingDropRate = d.SheddingDropRate
+		}
+		if t.StallAfter <= 0 {
+			t.StallAfter = d.StallAfter
+		}
+		o.pressure = &t
+	}
+}
+
+// pressureState remembers the counters seen by the previous Pressure()
+// call. It is shared by a logger and its clones.
+type pressureState struct {
+	mu           sync.Mutex
+	processed    int64
+	ringDrops    int64
+	latencyCount uint64
+	latencySum   uint64
+	lastProgress time.Time // Last call that saw the processed count move
+}
+
+// Pressure reports the current load level of the logger and of the loggers
+// derived from the same root.
+//
+// Drop rate and latency are measured over the interval since the previous
+// call (since the logger was created, for the first one), so the result
+// reflects current conditions when Pressure is polled periodically, e.g.
+// by a health endpoint. Stall detection also relies on polling: a logger is
+// Stalled once successive calls over StallAfter saw buffered records but no
+// progress.
+//
+// Example:
+//
+//	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+//	    if p := logger.Pressure(); p >= iris.PressureShedding {
+//	        w.WriteHeader(http.StatusServiceUnavailable)
+//	        fmt.Fprintln(w, "logging:", p)
+//	    }
+//	})
+func (l *Logger) Pressure() Pressure {
+	t := DefaultPressureThresholds()
+	if l.opts.pressure != nil {
+		t = *l.opts.pressure
+	}
+	if l.r.Closed() {
+		return PressureStalled
+	}
+
+	ring := l.r.Stats()
+	processed, buffered := ring["items_processed"], ring["items_buffered"]
+	ringDrops := l.DroppedBy(DropRingFull)
+	now := l.clock()
+
+	s := l.pressure
+	s.mu.Lock()
+	dProcessed, dDrops := processed-s.processed, ringDrops-s.ringDrops
+	s.processed, s.ringDrops = processed, ringDrops
+	if dProcessed > 0 || buffered == 0 || s.lastProgress.IsZero() {
+		s.lastProgress = now
+	}
+	stalledFor := now.Sub(s.lastProgress)
+	var meanLatency time.Duration
+	if l.latency != nil {
+		count, sum := l.latency.endToEnd.count.Load(), l.latency.endToEnd.sum.Load()
+		if count > s.latencyCount {
+			meanLatency = time.Duration((sum - s.latencySum) / (count - s.latencyCount)) // #nosec G115 -- mean of int64 durations
+		}
+		s.latencyCount, s.latencySum = count, sum
+	}
+	s.mu.Unlock()
+
+	switch {
+	case buffered > 0 && stalledFor >= t.StallAfter:
+		return PressureStalled
+	case dDrops > 0 && float64(dDrops) >= t.SheddingDropRate*float64(dDrops+dProcessed):
+		return PressureShedding
+	case dDrops > 0,
+		ring["utilization_percent"] >= t.ElevatedUtilization,
+		meanLatency >= t.ElevatedLatency:
+		return PressureElevated
+	default:
+		return PressureOK
+	}
+}
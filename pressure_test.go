@@ -0,0 +1,120 @@
+// pressure_test.go: Tests for the logger pressure status
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPressure_String(t *testing.T) {
+	tests := []struct {
+		p    Pressure
+		want string
+	}{
+		{PressureOK, "ok"},
+		{PressureElevated, "elevated"},
+		{PressureShedding, "shedding"},
+		{PressureStalled, "stalled"},
+		{Pressure(9), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("Pressure(%d).String() = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPressure_OK(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("steady")
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := logger.Pressure(); got != PressureOK {
+		t.Errorf("Pressure() = %v, want ok", got)
+	}
+}
+
+func TestPressure_UnstartedLogger(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 16, BatchSize: 8, AutoStart: AutoStartOff},
+		WithPressureThresholds(PressureThresholds{StallAfter: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+	child := logger.With(Str("component", "child"))
+
+	// Filling the ring without a consumer: first elevated, then shedding
+	for i := 0; i < 10; i++ {
+		logger.Info("buffered")
+	}
+	if got := logger.Pressure(); got != PressureElevated {
+		t.Errorf("after filling 10/16 slots: Pressure() = %v, want elevated", got)
+	}
+	for i := 0; i < 10; i++ {
+		child.Info("overflow")
+	}
+	if got := child.Pressure(); got != PressureShedding {
+		t.Errorf("after ring-full drops: Pressure() = %v, want shedding", got)
+	}
+	if got := logger.Pressure(); got != PressureElevated {
+		t.Errorf("without new drops: Pressure() = %v, want elevated", got)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := logger.Pressure(); got != PressureStalled {
+		t.Errorf("without progress: Pressure() = %v, want stalled", got)
+	}
+
+	logger.Start()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := logger.Pressure(); got != PressureOK {
+		t.Errorf("after draining: Pressure() = %v, want ok", got)
+	}
+}
+
+func TestPressure_Latency(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithLatencyHistograms(), WithPressureThresholds(PressureThresholds{ElevatedLatency: time.Nanosecond}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("measured")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := logger.Pressure(); got != PressureElevated {
+		t.Errorf("with latency above threshold: Pressure() = %v, want elevated", got)
+	}
+	if got := logger.Pressure(); got != PressureOK {
+		t.Errorf("without new records: Pressure() = %v, want ok", got)
+	}
+}
+
+func TestPressure_Closed(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	safeCloseWithOptionsLogger(t, logger)
+	if got := logger.Pressure(); got != PressureStalled {
+		t.Errorf("after Close: Pressure() = %v, want stalled", got)
+	}
+}
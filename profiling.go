@@ -0,0 +1,191 @@
+// profiling.go: Phase timing of the consumer for performance investigations
+//
+// When logging shows up in a profile, the question is where the consumer
+// spends its time: taking records off the ring and running filters and
+// hooks, encoding them, or waiting on the output. With WithProfiling the
+// consumer times the encode and write phases of every record and reports
+// them per batch to a callback, and can mark them as runtime/trace regions
+// so they show up in `go tool trace` next to the application's own regions.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"runtime/trace"
+	"time"
+)
+
+// Names of the runtime/trace regions created with ProfileConfig.TraceRegions.
+const (
+	TraceRegionBatch  = "iris.batch"
+	TraceRegionEncode = "iris.encode"
+	TraceRegionWrite  = "iris.write"
+)
+
+// ProfileConfig configures WithProfiling.
+type ProfileConfig struct {
+	// OnBatch, if set, receives the phase times of each batch processed by
+	// the consumer. It runs on the consumer, after the batch, so it must
+	// return quickly and must not log through the same logger.
+	OnBatch func(BatchProfile)
+
+	// TraceRegions wraps each batch and the encode and write phases of each
+	// record in runtime/trace regions (TraceRegionBatch, TraceRegionEncode
+	// and TraceRegionWrite). Regions cost little while no trace is running.
+	TraceRegions bool
+}
+
+// BatchProfile is the time the consumer spent on one batch, by phase.
+//
+// Encode and Write add up the phases of every record of the batch; Dequeue
+// is the rest of the batch: reading records off the ring, filtering, hooks
+// and bookkeeping. Streaming encoders (StreamEncoder into a StreamSink)
+// count EncodeTo as Encode and the end of the record as Write. Additional
+// outputs (WithOutput) are encoded and written within Write, and the flush
+// of batch sinks at the end of the batch is part of Write too.
+type BatchProfile struct {
+	Logger  string        // Name of the logger that owns the consumer
+	Records int           // Records processed in the batch
+	Total   time.Duration // From the start of the first record to the end of the batch
+	Dequeue time.Duration // Total not spent encoding or writing
+	Encode  time.Duration // Encoder time
+	Write   time.Duration // Output time
+}
+
+// Consumer phases timed by the profiler
+const (
+	phaseEncode = iota
+	phaseWrite
+	phaseCount
+)
+
+// profiler accumulates the phase times of the batch being processed. It is
+// only used by the consumer, which runs one batch at a time.
+type profiler struct {
+	cfg  ProfileConfig
+	name string
+
+	start   int64 // latencyNow at the first record of the batch (0 = between batches)
+	records int
+	spent   [phaseCount]int64
+	region  *trace.Region // TraceRegionBatch of the current batch
+}
+
+// profileSpan is one timed phase of a record.
+type profileSpan struct {
+	start  int64
+	region *trace.Region
+}
+
+// WithProfiling times the phases of the consumer and reports them per batch
+// to cfg.OnBatch and/or as runtime/trace regions, so that the consumer time
+// can be attributed to encoding, writing and the rest without modifying the
+// library.
+//
+// Each record costs four monotonic clock reads in the consumer, plus the
+// regions with cfg.TraceRegions. In inline mode (Config.Inline) every
+// write is a batch of its own, processed by the caller.
+//
+// Parameters:
+//   - cfg: Batch callback and trace region switch
+//
+// Returns:
+//   - Option: Configuration function to enable consumer profiling
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithProfiling(iris.ProfileConfig{
+//		OnBatch: func(p iris.BatchProfile) {
+//			encodeSeconds.Add(p.Encode.Seconds())
+//			writeSeconds.Add(p.Write.Seconds())
+//		},
+//		TraceRegions: true,
+//	}))
+func WithProfiling(cfg ProfileConfig) Option {
+	return func(o *loggerOptions) {
+		o.profile = &cfg
+	}
+}
+
+// newProfiler creates the profiler for cfg (nil without WithProfiling).
+func newProfiler(cfg *ProfileConfig, name string) *profiler {
+	if cfg == nil {
+		return nil
+	}
+	return &profiler{cfg: *cfg, name: name}
+}
+
+// record counts a record, starting a batch at its first record.
+func (p *profiler) record() {
+	if p == nil {
+		return
+	}
+	if p.start == 0 {
+		p.start = latencyNow()
+		if p.cfg.TraceRegions {
+			p.region = trace.StartRegion(context.Background(), TraceRegionBatch)
+		}
+	}
+	p.records++
+}
+
+// begin starts timing a phase.
+func (p *profiler) begin(phase int) profileSpan {
+	if p == nil {
+		return profileSpan{}
+	}
+	s := profileSpan{start: latencyNow()}
+	if p.cfg.TraceRegions {
+		name := TraceRegionEncode
+		if phase == phaseWrite {
+			name = TraceRegionWrite
+		}
+		s.region = trace.StartRegion(context.Background(), name)
+	}
+	return s
+}
+
+// end adds the time since begin to phase.
+func (p *profiler) end(phase int, s profileSpan) {
+	if p == nil {
+		return
+	}
+	if s.region != nil {
+		s.region.End()
+	}
+	p.spent[phase] += latencyNow() - s.start
+}
+
+// flushBatch ends the batch: it flushes the batch sinks, timed as a write,
+// then reports the batch and starts over.
+func (p *profiler) flushBatch(sinks []BatchSink) {
+	if p.start == 0 {
+		endBatch(sinks) // No record processed since the last batch
+		return
+	}
+	if len(sinks) > 0 {
+		span := p.begin(phaseWrite)
+		endBatch(sinks)
+		p.end(phaseWrite, span)
+	}
+	if p.region != nil {
+		p.region.End()
+		p.region = nil
+	}
+	total := latencyNow() - p.start
+	if p.cfg.OnBatch != nil {
+		p.cfg.OnBatch(BatchProfile{
+			Logger:  p.name,
+			Records: p.records,
+			Total:   time.Duration(total),
+			Dequeue: time.Duration(max(total-p.spent[phaseEncode]-p.spent[phaseWrite], 0)),
+			Encode:  time.Duration(p.spent[phaseEncode]),
+			Write:   time.Duration(p.spent[phaseWrite]),
+		})
+	}
+	p.start, p.records, p.spent = 0, 0, [phaseCount]int64{}
+}
@@ -0,0 +1,108 @@
+// profiling_test.go: Tests for consumer phase profiling
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"runtime/trace"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowSyncer takes delay for every write.
+type slowSyncer struct {
+	delay time.Duration
+}
+
+func (s *slowSyncer) Write(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return len(p), nil
+}
+
+func (s *slowSyncer) Sync() error { return nil }
+
+func TestProfiling_Phases(t *testing.T) {
+	var batches []BatchProfile
+	logger, err := New(Config{Level: Info, Output: &slowSyncer{delay: 2 * time.Millisecond}, Encoder: NewJSONEncoder(), Inline: true, Name: "api"},
+		WithProfiling(ProfileConfig{OnBatch: func(p BatchProfile) { batches = append(batches, p) }}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Info("first", Str("k", "v"))
+	logger.Info("second")
+
+	if len(batches) != 2 {
+		t.Fatalf("got %d batches, want one per inline write", len(batches))
+	}
+	for _, p := range batches {
+		if p.Logger != "api" || p.Records != 1 {
+			t.Errorf("unexpected batch %+v", p)
+		}
+		if p.Write < 2*time.Millisecond || p.Encode <= 0 {
+			t.Errorf("phases not timed: %+v", p)
+		}
+		if p.Total != p.Dequeue+p.Encode+p.Write {
+			t.Errorf("phases do not add up to the total: %+v", p)
+		}
+	}
+}
+
+func TestProfiling_Batches(t *testing.T) {
+	var mu sync.Mutex
+	records := 0
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 256},
+		WithProfiling(ProfileConfig{OnBatch: func(p BatchProfile) {
+			mu.Lock()
+			records += p.Records
+			mu.Unlock()
+		}}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		logger.Info("batched", Int("i", i))
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if records != 100 {
+		t.Errorf("batches reported %d records, want 100", records)
+	}
+}
+
+func TestProfiling_TraceRegions(t *testing.T) {
+	var tr bytes.Buffer
+	if err := trace.Start(&tr); err != nil {
+		t.Skipf("tracing unavailable: %v", err)
+	}
+	batches := 0
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true},
+		WithProfiling(ProfileConfig{TraceRegions: true, OnBatch: func(BatchProfile) { batches++ }}))
+	if err != nil {
+		trace.Stop()
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("traced")
+	_ = logger.Close()
+	trace.Stop()
+
+	if batches != 1 {
+		t.Errorf("got %d batches, want 1", batches)
+	}
+	for _, region := range []string{TraceRegionBatch, TraceRegionEncode, TraceRegionWrite} {
+		if !bytes.Contains(tr.Bytes(), []byte(region)) {
+			t.Errorf("trace does not mention region %q", region)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// quick.go: One-line constructors for small programs
+//
+// New takes a Config and options so that services can tune every aspect of
+// the pipeline. Command-line tools and scripts usually want a started
+// logger writing to stdout or to a file in one line; these constructors
+// provide that on top of New, with its smart defaults for everything else.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Must returns logger, panicking if err is not nil. It wraps constructors
+// that return an error when a failure can only be a programming or
+// deployment mistake:
+//
+//	logger := iris.Must(iris.NewFileLogger("tool.log", iris.Info))
+//	defer logger.Close()
+func Must(logger *Logger, err error) *Logger {
+	if err != nil {
+		panic(err)
+	}
+	return logger
+}
+
+// NewStdoutJSON returns a started logger writing JSON records to stdout.
+//
+// Parameters:
+//   - level: Minimum level to log
+//   - opts: Additional options, as for New
+//
+// Returns:
+//   - *Logger: Started logger; Close it before exiting to flush records
+//
+// New only fails on an invalid configuration, which the options can cause
+// but the rest of the configuration cannot; NewStdoutJSON panics then.
+//
+// Example:
+//
+//	logger := iris.NewStdoutJSON(iris.Info)
+//	defer logger.Close()
+func NewStdoutJSON(level Level, opts ...Option) *Logger {
+	return Must(New(Config{Level: level, Output: WrapWriter(os.Stdout), Encoder: NewJSONEncoder()}, opts...))
+}
+
+// NewStdoutText returns a started logger writing human-readable text
+// records to stdout. See NewStdoutJSON.
+func NewStdoutText(level Level, opts ...Option) *Logger {
+	return Must(New(Config{Level: level, Output: WrapWriter(os.Stdout), Encoder: NewTextEncoder()}, opts...))
+}
+
+// NewFileLogger returns a started logger appending JSON records to the
+// file at path, created with 0600 permissions if missing. Close closes the
+// file after flushing the records.
+//
+// Parameters:
+//   - path: Log file path
+//   - level: Minimum level to log
+//   - opts: Additional options, as for New
+//
+// Returns:
+//   - *Logger: Started logger
+//   - error: ErrCodeFileOpen if the file cannot be opened, or an error from New
+//
+// Example:
+//
+//	logger := iris.Must(iris.NewFileLogger("/var/log/tool.log", iris.Info))
+//	defer logger.Close()
+func NewFileLogger(path string, level Level, opts ...Option) (*Logger, error) {
+	cleanPath := filepath.Clean(path)
+	file, err := os.OpenFile(cleanPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path chosen by the application
+	if err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeFileOpen, "failed to open log file: "+err.Error(), "path", cleanPath)
+	}
+	opts = append(opts[:len(opts):len(opts)], withOwnedOutput(file))
+	logger, err := New(Config{Level: level, Output: NewFileSyncer(file), Encoder: NewJSONEncoder()}, opts...)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return logger, nil
+}
+
+// withOwnedOutput makes Close close c once the records are flushed, for
+// outputs the constructor opened on the caller's behalf.
+func withOwnedOutput(c io.Closer) Option {
+	return func(o *loggerOptions) {
+		o.owned = append(o.owned[:len(o.owned):len(o.owned)], c)
+	}
+}
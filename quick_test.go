@@ -0,0 +1,90 @@
+// quick_test.go: Tests for the one-line constructors
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool.log")
+	logger := Must(NewFileLogger(path, Warn))
+	if logger.State() != StateStarted {
+		t.Errorf("state = %s, want started", logger.State())
+	}
+	logger.Info("filtered")
+	logger.Warn("kept", Int("n", 1))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"msg":"kept"`) {
+		t.Errorf("file holds %q, want the warn record only", data)
+	}
+
+	_, err = NewFileLogger(filepath.Join(t.TempDir(), "missing", "tool.log"), Info)
+	if !IsLoggerError(err, ErrCodeFileOpen) {
+		t.Errorf("NewFileLogger in a missing directory = %v, want %s", err, ErrCodeFileOpen)
+	}
+}
+
+func TestNewStdoutLoggers(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func(Level, ...Option) *Logger
+		want string
+	}{
+		{"json", NewStdoutJSON, `"msg":"to stdout"`},
+		{"text", NewStdoutText, `msg="to stdout"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stdout")
+			f, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stdout := os.Stdout
+			os.Stdout = f
+			logger := tc.new(Info, WithCaller())
+			os.Stdout = stdout
+
+			logger.Debug("filtered")
+			logger.Info("to stdout")
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			_ = f.Close()
+			data, _ := os.ReadFile(path)
+			if strings.Count(string(data), "\n") != 1 || !strings.Contains(string(data), tc.want) || !strings.Contains(string(data), "caller") {
+				t.Errorf("stdout = %q, want one %s record with the options applied", data, tc.name)
+			}
+		})
+	}
+}
+
+func TestMust(t *testing.T) {
+	logger := &Logger{}
+	if Must(logger, nil) != logger {
+		t.Error("Must did not return the logger")
+	}
+	failure := errors.New("construction failed")
+	defer func() {
+		if r := recover(); r != failure {
+			t.Errorf("Must panicked with %v, want the error", r)
+		}
+	}()
+	Must(nil, failure)
+	t.Error("Must returned on an error")
+}
@@ -0,0 +1,231 @@
+// rates.go: Per-logger record and byte rates for SLO alerting
+//
+// Log volume is a health signal in both directions: a component flooding
+// its logs is usually failing, and a component that stops logging may have
+// crashed. With WithRateMetrics the consumer counts the records and encoded
+// bytes of each logger name in one-second buckets, and Stats reports their
+// rolling one- and five-minute rates.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+const (
+	// rateBuckets is the number of one-second buckets kept per name,
+	// covering the longest window
+	rateBuckets = 300
+
+	// maxRateNames bounds the number of names tracked; records of further
+	// names are counted under RateOverflowName
+	maxRateNames = 1024
+)
+
+// RateOverflowName is the name under which WithRateMetrics counts the
+// records of logger names beyond the first 1024.
+const RateOverflowName = "_other"
+
+// LoggerRate is the rolling throughput of one logger name.
+type LoggerRate struct {
+	Name string // Logger name ("" for unnamed loggers)
+
+	RecordsPerSec1m float64 // Records per second over the last minute
+	RecordsPerSec5m float64 // Records per second over the last five minutes
+	BytesPerSec1m   float64 // Encoded bytes per second over the last minute
+	BytesPerSec5m   float64 // Encoded bytes per second over the last five minutes
+
+	LastRecord time.Time // Second in which the last record was written
+}
+
+// rateBucket counts one second of records.
+type rateBucket struct {
+	sec     int64 // Unix second the counts belong to
+	records int64
+	bytes   int64
+}
+
+// rateSeries is the bucket ring of one name.
+type rateSeries struct {
+	buckets [rateBuckets]rateBucket
+	last    int64 // Unix second of the last record
+}
+
+// rateTracker counts records per logger name.
+type rateTracker struct {
+	now   func() int64 // Unix seconds (overridden in tests)
+	start int64        // Unix second the tracker was created
+
+	mu     sync.Mutex
+	series map[string]*rateSeries
+
+	counting countingWriter // Stream path byte counter (consumer only, see measure)
+}
+
+// WithRateMetrics enables per-logger rate metrics.
+//
+// The consumer counts the records written and their encoded size per
+// logger name (as set with Named). Stats then reports, for every name seen,
+// "rate.<name>.records_per_sec_1m", "rate.<name>.records_per_sec_5m",
+// "rate.<name>.bytes_per_sec_1m" and "rate.<name>.bytes_per_sec_5m",
+// rounded to integers ("rate.records_per_sec_1m" and so on for unnamed
+// loggers); Logger.Rates returns the exact values. A name stays listed
+// once seen, so a component that stopped logging shows up with falling and
+// then zero rates instead of disappearing. Until a window has elapsed since
+// New, its rate is computed over the time elapsed so far.
+//
+// The tracker is shared by the logger and all loggers derived from it. It
+// costs a map lookup under an uncontended mutex per record, in the
+// consumer.
+//
+// Returns:
+//   - Option: Configuration function to enable rate metrics
+//
+// Example:
+//
+//	logger, _ := iris.New(cfg, iris.WithRateMetrics())
+//	db := logger.Named("db")
+//	// Alert when stats["rate.db.records_per_sec_1m"] == 0 for 10 minutes
+func WithRateMetrics() Option {
+	return func(o *loggerOptions) { o.rateMetrics = true }
+}
+
+// newRateTracker creates a tracker reading the cached clock.
+func newRateTracker() *rateTracker {
+	t := &rateTracker{
+		now:    func() int64 { return timecache.CachedTimeNano() / int64(time.Second) },
+		series: make(map[string]*rateSeries),
+	}
+	t.start = t.now()
+	return t
+}
+
+// observe counts one record of n encoded bytes for name.
+func (t *rateTracker) observe(name string, n int) {
+	sec := t.now()
+	t.mu.Lock()
+	s := t.series[name]
+	if s == nil {
+		if len(t.series) >= maxRateNames {
+			name = RateOverflowName
+			s = t.series[name]
+		}
+		if s == nil {
+			s = &rateSeries{}
+			t.series[name] = s
+		}
+	}
+	b := &s.buckets[sec%rateBuckets]
+	if b.sec != sec {
+		*b = rateBucket{sec: sec}
+	}
+	b.records++
+	b.bytes += int64(n)
+	s.last = sec
+	t.mu.Unlock()
+}
+
+// rates returns the rates of every name seen, sorted by name.
+func (t *rateTracker) rates() []LoggerRate {
+	now := t.now()
+	elapsed := now - t.start + 1 // The current second counts
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rates := make([]LoggerRate, 0, len(t.series))
+	for name, s := range t.series {
+		var records1, bytes1, records5, bytes5 int64
+		for i := range s.buckets {
+			b := &s.buckets[i]
+			age := now - b.sec
+			if age < 0 || age >= rateBuckets {
+				continue
+			}
+			records5 += b.records
+			bytes5 += b.bytes
+			if age < 60 {
+				records1 += b.records
+				bytes1 += b.bytes
+			}
+		}
+		span1 := float64(min(elapsed, 60))
+		span5 := float64(min(elapsed, rateBuckets))
+		rates = append(rates, LoggerRate{
+			Name:            name,
+			RecordsPerSec1m: float64(records1) / span1,
+			RecordsPerSec5m: float64(records5) / span5,
+			BytesPerSec1m:   float64(bytes1) / span1,
+			BytesPerSec5m:   float64(bytes5) / span5,
+			LastRecord:      time.Unix(s.last, 0),
+		})
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Name < rates[j].Name })
+	return rates
+}
+
+// addTo adds the rounded rates to stats.
+func (t *rateTracker) addTo(stats map[string]int64) {
+	for _, r := range t.rates() {
+		prefix := "rate."
+		if r.Name != "" {
+			prefix += r.Name + "."
+		}
+		stats[prefix+"records_per_sec_1m"] = int64(math.Round(r.RecordsPerSec1m))
+		stats[prefix+"records_per_sec_5m"] = int64(math.Round(r.RecordsPerSec5m))
+		stats[prefix+"bytes_per_sec_1m"] = int64(math.Round(r.BytesPerSec1m))
+		stats[prefix+"bytes_per_sec_5m"] = int64(math.Round(r.BytesPerSec5m))
+	}
+}
+
+// Rates returns the rolling rates of every logger name seen, sorted by
+// name. It returns nil unless the logger was created with WithRateMetrics.
+func (l *Logger) Rates() []LoggerRate {
+	if l.rates == nil {
+		return nil
+	}
+	return l.rates.rates()
+}
+
+// measure prepares to count the bytes encoded into w and returns the
+// writer to encode into. Buffers are measured by their growth, so that
+// encoders keep writing into them directly.
+func (t *rateTracker) measure(w io.Writer) io.Writer {
+	if buf, ok := w.(*bytes.Buffer); ok {
+		t.counting = countingWriter{buf: buf, n: -buf.Len()}
+		return w
+	}
+	t.counting = countingWriter{w: w}
+	return &t.counting
+}
+
+// measured returns the bytes encoded since measure.
+func (t *rateTracker) measured() int {
+	if t.counting.buf != nil {
+		return t.counting.buf.Len() + t.counting.n
+	}
+	return t.counting.n
+}
+
+// countingWriter counts the bytes written through it to w.
+type countingWriter struct {
+	w   io.Writer
+	buf *bytes.Buffer // Measured directly instead (see measure)
+	n   int
+}
+
+// Write implements io.Writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,150 @@
+// rates_test.go: Tests for per-logger rate metrics
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRateMetrics_RollingWindows(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 256}, WithRateMetrics())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	var now atomic.Int64
+	now.Store(1000)
+	logger.rates.now = now.Load
+	logger.rates.start = 1000
+	now.Store(1059) // One minute elapsed, counting the current second
+
+	db := logger.Named("db")
+	for i := 0; i < 60; i++ {
+		db.Info("query")
+		if i%2 == 0 {
+			logger.Info("tick")
+		}
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	dbBytes := 0
+	for _, line := range strings.SplitAfter(out.String(), "\n") {
+		if strings.Contains(line, `"msg":"query"`) {
+			dbBytes += len(line)
+		}
+	}
+
+	tests := []struct {
+		key  string
+		want int64
+	}{
+		{"rate.db.records_per_sec_1m", 1},
+		{"rate.db.bytes_per_sec_1m", int64(math.Round(float64(dbBytes) / 60))},
+		{"rate.records_per_sec_1m", 1}, // 0.5 rounded
+		{"rate.db.records_per_sec_5m", 1},
+	}
+	stats := logger.Stats()
+	for _, tt := range tests {
+		if got, ok := stats[tt.key]; !ok || got != tt.want {
+			t.Errorf("%s = %d (present %v), want %d", tt.key, got, ok, tt.want)
+		}
+	}
+
+	rates := logger.Rates()
+	if len(rates) != 2 || rates[0].Name != "" || rates[1].Name != "db" {
+		t.Fatalf("Rates() = %+v, want the unnamed logger then db", rates)
+	}
+	if rates[0].RecordsPerSec1m != 0.5 {
+		t.Errorf("unnamed RecordsPerSec1m = %v, want 0.5", rates[0].RecordsPerSec1m)
+	}
+	if rates[1].LastRecord.Unix() != 1059 {
+		t.Errorf("db LastRecord = %v, want second 1059", rates[1].LastRecord.Unix())
+	}
+
+	// Two minutes of silence: out of the 1m window, still in the 5m one
+	now.Store(1199)
+	for _, r := range logger.Rates() {
+		if r.RecordsPerSec1m != 0 || r.BytesPerSec1m != 0 {
+			t.Errorf("%q still has a 1m rate after silence: %+v", r.Name, r)
+		}
+		if r.RecordsPerSec5m == 0 {
+			t.Errorf("%q lost its 5m rate within five minutes: %+v", r.Name, r)
+		}
+	}
+	if got, ok := logger.Stats()["rate.db.records_per_sec_1m"]; !ok || got != 0 {
+		t.Errorf("silent db should stay listed with a zero rate, got %d (present %v)", got, ok)
+	}
+}
+
+func TestRateMetrics_StreamSinkBytes(t *testing.T) {
+	var dest bytes.Buffer
+	vw := NewVectoredWriter(&dest, 0)
+	logger, err := New(Config{Level: Info, Output: vw, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, WithRateMetrics())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.rates.now = func() int64 { return logger.rates.start + 9 } // Ten seconds elapsed
+	for i := 0; i < 10; i++ {
+		logger.Info("streamed")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	rates := logger.Rates()
+	if len(rates) != 1 || dest.Len() == 0 {
+		t.Fatalf("Rates() = %+v after writing %d bytes", rates, dest.Len())
+	}
+	if got := rates[0].BytesPerSec1m * 10; math.Abs(got-float64(dest.Len())) > 0.5 {
+		t.Errorf("counted %v bytes, wrote %d", got, dest.Len())
+	}
+}
+
+func TestRateMetrics_Disabled(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	logger.Info("hello")
+	_ = logger.Sync()
+	if logger.Rates() != nil {
+		t.Error("Rates() should be nil without WithRateMetrics")
+	}
+	for key := range logger.Stats() {
+		if strings.HasPrefix(key, "rate.") {
+			t.Errorf("unexpected stats key %q", key)
+		}
+	}
+}
+
+func TestRateTracker_OverflowName(t *testing.T) {
+	rt := newRateTracker()
+	for i := 0; i < maxRateNames+10; i++ {
+		rt.observe(string(rune('a'+i%26))+strings.Repeat("x", i/26), 10)
+	}
+	rates := rt.rates()
+	if len(rates) != maxRateNames+1 {
+		t.Fatalf("tracked %d names, want %d plus the overflow name", len(rates), maxRateNames)
+	}
+	var overflow *LoggerRate
+	for i := range rates {
+		if rates[i].Name == RateOverflowName {
+			overflow = &rates[i]
+		}
+	}
+	if overflow == nil || overflow.RecordsPerSec5m == 0 {
+		t.Errorf("records beyond the name limit not counted under %q: %+v", RateOverflowName, overflow)
+	}
+}
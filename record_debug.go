@@ -0,0 +1,173 @@
+// record_debug.go: Record slot misuse detection for Iris logging library
+//
+// Records passed to Logger.Write fill functions are ring buffer slots that are
+// reused for later log entries. A fill function that keeps the *Record (for
+// example by capturing it in a goroutine or storing it in a struct) silently
+// corrupts unrelated entries once Write returns. This is hard to diagnose in
+// production, so record debug mode seals each slot with a content checksum
+// and verifies it at the two points where a retained pointer shows up:
+//
+//   - When the consumer processes a record filled by Logger.Write, the record
+//     must be unchanged since Write returned
+//   - When a processed slot is handed out again, it must still be in the
+//     reset state the consumer left it in
+//
+// The same check covers UnsafeString fields: their bytes are hashed when the
+// record is enqueued and verified before it is encoded.
+//
+// Checksums cover every scalar, string and byte-slice value in the record;
+// Obj values (errors, stringers, objects) are not inspected. Debug mode hashes
+// every record and is intended for development and tests only.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// WithRecordDebug enables detection of fill functions that retain the
+// *Record after Logger.Write returns.
+//
+// Every misuse is reported to onMisuse as an error with code
+// ErrCodeRecordMisuse. A nil handler panics instead, which is the
+// recommended setting for development builds and tests. Note that reports
+// from the consumer side happen on the consumer goroutine (or the calling
+// goroutine in inline mode).
+//
+// Record debug mode must be set on the root logger (passed to New) for
+// consumer-side checks; loggers derived with WithOptions share its ring.
+//
+// Parameters:
+//   - onMisuse: Diagnostic handler, or nil to panic on misuse
+//
+// Returns:
+//   - Option: Configuration function to enable record debug mode
+//
+// Example:
+//
+//	logger, _ := iris.New(cfg, iris.WithRecordDebug(nil)) // panic in tests
+//
+//	logger, _ := iris.New(cfg, iris.WithRecordDebug(func(err error) {
+//	    fmt.Fprintln(os.Stderr, "iris:", err)
+//	}))
+func WithRecordDebug(onMisuse func(error)) Option {
+	return func(o *loggerOptions) {
+		o.recordDebug = true
+		o.onRecordMisuse = onMisuse
+	}
+}
+
+// checkIdleSlot verifies that a slot handed out by the ring was not modified
+// after the consumer released it. Called before the slot is reset for filling.
+func (l *Logger) checkIdleSlot(slot *Record) {
+	if slot.seal != 0 && recordChecksum(slot) != slot.seal {
+		l.reportRecordMisuse(NewLoggerErrorWithField(ErrCodeRecordMisuse,
+			"record slot modified after it was processed: a Write fill function retained the *Record",
+			"msg", slot.Msg))
+	}
+}
+
+// checkFilledSlot verifies that a record sealed by Logger.Write was not
+// modified between Write returning and the consumer processing it.
+func (l *Logger) checkFilledSlot(rec *Record) {
+	if rec.seal != 0 && recordChecksum(rec) != rec.seal {
+		l.reportRecordMisuse(NewLoggerErrorWithField(ErrCodeRecordMisuse,
+			"record modified after Write returned: the fill function retained the *Record",
+			"msg", rec.Msg))
+	}
+}
+
+// sealBorrowed stores a checksum of every UnsafeString value in rec in the
+// field's U64, which string fields do not otherwise use.
+func sealBorrowed(rec *Record) {
+	for i := int32(0); i < rec.n; i++ {
+		if f := &rec.fields[i]; f.T == kindString && f.I64 == borrowedString {
+			f.U64 = stringChecksum(f.Str)
+		}
+	}
+}
+
+// checkBorrowed verifies that the bytes behind UnsafeString fields sealed by
+// sealBorrowed were not modified before the consumer encoded the record.
+func (l *Logger) checkBorrowed(rec *Record) {
+	for i := int32(0); i < rec.n; i++ {
+		f := &rec.fields[i]
+		if f.T == kindString && f.I64 == borrowedString && f.U64 != 0 && stringChecksum(f.Str) != f.U64 {
+			l.reportRecordMisuse(NewLoggerErrorWithField(ErrCodeRecordMisuse,
+				"bytes of an UnsafeString field modified before the record was written",
+				"field", f.K))
+		}
+	}
+}
+
+// reportRecordMisuse delivers a misuse diagnostic to the configured handler,
+// or panics when none is set.
+func (l *Logger) reportRecordMisuse(err error) {
+	if l.opts.onRecordMisuse == nil {
+		panic(err)
+	}
+	l.opts.onRecordMisuse(err)
+}
+
+// sealRecord stores the record's current checksum.
+func sealRecord(r *Record) {
+	r.seal = recordChecksum(r)
+}
+
+// stringChecksum hashes s. The result is never zero.
+func stringChecksum(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64() | 1
+}
+
+// recordChecksum hashes the record contents (excluding the seal itself).
+// The result is never zero, so zero can mean "unsealed".
+func recordChecksum(r *Record) uint64 {
+	h := fnv.New64a()
+	var scratch [8]byte
+
+	writeUint := func(v uint64) {
+		for i := range scratch {
+			scratch[i] = byte(v >> (8 * i))
+		}
+		_, _ = h.Write(scratch[:])
+	}
+	writeString := func(s string) {
+		writeUint(uint64(len(s)))
+		_, _ = h.Write([]byte(s))
+	}
+
+	// #nosec G115 - level and count are small signed values, only hashed
+	writeUint(uint64(r.Level))
+	writeString(r.Msg)
+	writeString(r.Logger)
+	writeString(r.Caller)
+	writeString(r.Stack)
+	// #nosec G115 - only hashed
+	writeUint(uint64(r.n))
+
+	n := int(r.n)
+	if n < 0 || n > len(r.fields) {
+		n = 0 // Corrupted count is already captured above
+	}
+	for i := 0; i < n; i++ {
+		f := &r.fields[i]
+		writeString(f.K)
+		writeUint(uint64(f.T))
+		// #nosec G115 - only hashed
+		writeUint(uint64(f.I64))
+		writeUint(f.U64)
+		writeUint(math.Float64bits(f.F64))
+		writeString(f.Str)
+		writeUint(uint64(len(f.B)))
+		_, _ = h.Write(f.B)
+	}
+
+	return h.Sum64() | 1
+}
@@ -0,0 +1,224 @@
+// record_debug_test.go: Tests for record slot misuse detection
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// misuseCollector records misuse diagnostics from any goroutine
+type misuseCollector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (c *misuseCollector) handle(err error) {
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+func (c *misuseCollector) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.errs)
+}
+
+func (c *misuseCollector) first() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs[0]
+}
+
+func TestRecordDebug_CorrectUsageIsSilent(t *testing.T) {
+	collector := &misuseCollector{}
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:    Debug,
+		Output:   buf,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 1024,
+	}, WithRecordDebug(collector.handle), WithCaller())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	for i := 0; i < 100; i++ {
+		logger.Info("structured", Int("i", i), Str("k", "v"))
+		logger.Debug("plain")
+		logger.Write(func(r *Record) {
+			r.Level = Warn
+			r.Msg = "via write"
+			r.AddField(Bytes("raw", []byte("abc")))
+		})
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if n := collector.count(); n != 0 {
+		t.Errorf("expected no misuse reports, got %d: %v", n, collector.first())
+	}
+	if !strings.Contains(buf.String(), "via write") {
+		t.Error("expected records to be written normally in debug mode")
+	}
+}
+
+func TestRecordDebug_DetectsModificationBeforeProcessing(t *testing.T) {
+	collector := &misuseCollector{}
+	logger, err := New(Config{
+		Level:     Debug,
+		Output:    &testSyncer{},
+		Encoder:   NewJSONEncoder(),
+		Capacity:  64,
+		AutoStart: AutoStartOff,
+	}, WithRecordDebug(collector.handle))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	// Not started yet: the record waits in the ring after Write returns
+	var retained *Record
+	logger.Write(func(r *Record) {
+		r.Msg = "original"
+		retained = r
+	})
+	retained.Msg = "changed later"
+
+	logger.Start()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if collector.count() != 1 {
+		t.Fatalf("expected 1 misuse report, got %d", collector.count())
+	}
+	if err := collector.first(); !IsLoggerError(err, ErrCodeRecordMisuse) ||
+		!strings.Contains(err.Error(), "after Write returned") {
+		t.Errorf("unexpected diagnostic: %v", err)
+	}
+}
+
+func TestRecordDebug_DetectsModificationAfterProcessing(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Record)
+		reuse  func(*Logger)
+	}{
+		{"message then fast path", func(r *Record) { r.Msg = "late" }, func(l *Logger) { l.Info("next") }},
+		{"field then complex path", func(r *Record) { r.AddField(Int("late", 1)) }, func(l *Logger) { l.Info("next", Int("x", 1)) }},
+		{"level then Write", func(r *Record) { r.Level = Error }, func(l *Logger) {
+			l.Write(func(r *Record) { r.Msg = "next" })
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &misuseCollector{}
+			logger, err := New(Config{
+				Level:   Debug,
+				Output:  &testSyncer{},
+				Encoder: NewJSONEncoder(),
+				Inline:  true, // Single reused slot makes reuse deterministic
+			}, WithRecordDebug(collector.handle))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			logger.Start()
+			defer safeCloseWithOptionsLogger(t, logger)
+
+			var retained *Record
+			logger.Write(func(r *Record) {
+				r.Msg = "first"
+				retained = r
+			})
+			tt.mutate(retained)
+			tt.reuse(logger)
+
+			if collector.count() != 1 {
+				t.Fatalf("expected 1 misuse report, got %d", collector.count())
+			}
+			if err := collector.first(); !strings.Contains(err.Error(), "after it was processed") {
+				t.Errorf("unexpected diagnostic: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordDebug_NilHandlerPanics(t *testing.T) {
+	logger, err := New(Config{
+		Level:   Debug,
+		Output:  &testSyncer{},
+		Encoder: NewJSONEncoder(),
+		Inline:  true,
+	}, WithRecordDebug(nil))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	var retained *Record
+	logger.Write(func(r *Record) { retained = r })
+	retained.Msg = "late"
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic on misuse")
+		}
+		if err, ok := r.(error); !ok || !IsLoggerError(err, ErrCodeRecordMisuse) {
+			t.Errorf("expected ErrCodeRecordMisuse panic, got %v", r)
+		}
+	}()
+	logger.Info("reuse")
+}
+
+func TestRecordChecksum(t *testing.T) {
+	base := NewRecord(Info, "msg")
+	base.AddField(Str("k", "v"))
+	sum := recordChecksum(base)
+	if sum == 0 {
+		t.Fatal("checksum must never be zero")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Record)
+	}{
+		{"level", func(r *Record) { r.Level = Warn }},
+		{"msg", func(r *Record) { r.Msg = "other" }},
+		{"logger", func(r *Record) { r.Logger = "svc" }},
+		{"caller", func(r *Record) { r.Caller = "a.go:1" }},
+		{"stack", func(r *Record) { r.Stack = "trace" }},
+		{"field added", func(r *Record) { r.AddField(Int("n", 1)) }},
+		{"field value", func(r *Record) { r.fields[0].Str = "w" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := *base
+			tt.mutate(&rec)
+			if recordChecksum(&rec) == sum {
+				t.Error("checksum should change")
+			}
+		})
+	}
+
+	// The seal itself is not part of the checksum
+	sealed := *base
+	sealRecord(&sealed)
+	if recordChecksum(&sealed) != sum {
+		t.Error("seal must not affect the checksum")
+	}
+}
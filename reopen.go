@@ -0,0 +1,119 @@
+// reopen.go: Sink reopening for external log rotation
+//
+// Tools such as logrotate rotate files behind the process' back, either by
+// renaming the file ("move") or by copying and truncating it
+// ("copytruncate"). After a move the process keeps appending to the renamed
+// file until it reopens the path; after copytruncate it must write at the
+// new end of the file. Reopen handles both: pending records are flushed to
+// the current file first, then every file-based sink reopens its path under
+// its own write lock, so each record lands in exactly one of the two files.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/agilira/go-errors"
+)
+
+// Reopener is implemented by sinks that can close and reopen their
+// destination at the same path. SharedFileWriter, PartitionedFileWriter,
+// RotatingFileSyncer and writers returned by MultiWriteSyncer implement it.
+type Reopener interface {
+	Reopen() error
+}
+
+// Reopen reopens the logger's file-based sinks at their current paths.
+//
+// Records logged before Reopen is called are written to the old files
+// before they are reopened; records logged afterwards go to the new ones.
+// Outputs that do not implement Reopener are left untouched. Loggers
+// derived from the same root share the outputs, so reopening any of them
+// is enough.
+//
+// Returns:
+//   - error: ErrLoggerClosed after Close, otherwise the first flush or
+//     reopen error
+//
+// Example (logrotate postrotate script: kill -HUP <pid>):
+//
+//	stop := logger.ReopenOnSignal() // SIGHUP on Unix
+//	defer stop()
+func (l *Logger) Reopen() error {
+	if l.r.Closed() {
+		return ErrLoggerClosed
+	}
+	if err := l.sync(); err != nil {
+		return err
+	}
+	err := reopenSink(l.out)
+	if l.opts.classifier != nil && l.opts.classifier.restricted != nil {
+		if rerr := reopenSink(l.opts.classifier.restricted); err == nil {
+			err = rerr
+		}
+	}
+	if rerr := l.reopenOutputs(); err == nil {
+		err = rerr
+	}
+	if l.opts.sessionOut != nil {
+		if rerr := reopenSink(l.opts.sessionOut); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// reopenSink reopens ws if it supports it.
+func reopenSink(ws WriteSyncer) error {
+	if r, ok := ws.(Reopener); ok {
+		return r.Reopen()
+	}
+	return nil
+}
+
+// ReopenOnSignal calls Reopen whenever one of sigs is received, SIGHUP by
+// default on Unix (there is no default elsewhere, so ReopenOnSignal without
+// arguments does nothing). Reopen failures are reported to the error
+// handler with ErrCodeFileRotation.
+//
+// Returns:
+//   - stop: Stops listening for the signals; safe to call more than once
+func (l *Logger) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = defaultReopenSignals
+	}
+	if len(sigs) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := l.Reopen(); err != nil {
+					l.reportError(errors.Wrap(err, ErrCodeFileRotation, "failed to reopen log sinks on signal").
+						WithContext("logger", l.name))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// reopen_other.go: No default reopen signal outside Unix
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !unix
+
+package iris
+
+import "os"
+
+// defaultReopenSignals is empty: SIGHUP is not delivered on these platforms.
+var defaultReopenSignals []os.Signal
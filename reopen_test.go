@@ -0,0 +1,201 @@
+// reopen_test.go: Tests for sink reopening
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countLines returns the number of lines in path (0 if it does not exist).
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func TestLogger_ReopenAfterMove(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("open files cannot be renamed on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	out, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("before")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	archive := filepath.Join(dir, "app.log.1")
+	if err := os.Rename(path, archive); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	logger.Info("still old") // Pending records go to the old file
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	logger.Info("after")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	old, _ := os.ReadFile(archive)
+	cur, _ := os.ReadFile(path)
+	if !strings.Contains(string(old), "before") || !strings.Contains(string(old), "still old") || strings.Contains(string(old), "after") {
+		t.Errorf("unexpected archive contents %q", old)
+	}
+	if !strings.Contains(string(cur), "after") || strings.Contains(string(cur), "before") {
+		t.Errorf("unexpected current file contents %q", cur)
+	}
+}
+
+func TestLogger_ReopenAfterCopyTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	out, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	logger, err := New(Config{Level: Info, Output: MultiWriteSyncer(out, &testSyncer{}), Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("before")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	logger.Info("after")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if len(data) == 0 || data[0] != '{' || strings.Count(string(data), "\n") != 1 {
+		t.Errorf("expected a single record at the start of the truncated file, got %q", data)
+	}
+}
+
+func TestLogger_ReopenConcurrentLogging(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("open files cannot be renamed on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	out, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var accepted atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if logger.Info("rotating") {
+					accepted.Add(1)
+				}
+			}
+		}()
+	}
+	const archives = 5
+	for i := 0; i < archives; i++ {
+		time.Sleep(time.Millisecond)
+		if err := os.Rename(path, filepath.Join(dir, "app.log."+strconv.Itoa(i))); err != nil {
+			t.Fatalf("Rename failed: %v", err)
+		}
+		if err := logger.Reopen(); err != nil {
+			t.Fatalf("Reopen failed: %v", err)
+		}
+	}
+	wg.Wait()
+	safeCloseWithOptionsLogger(t, logger)
+
+	total := countLines(t, path)
+	for i := 0; i < archives; i++ {
+		total += countLines(t, filepath.Join(dir, "app.log."+strconv.Itoa(i)))
+	}
+	if int64(total) != accepted.Load() {
+		t.Errorf("%d records accepted but %d found across rotated files", accepted.Load(), total)
+	}
+}
+
+func TestPartitionedFileWriter_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	w, err := NewPartitionedFileWriter(PartitionConfig{Layout: filepath.Join(dir, "app-%Y%m%d.log"), TimeFn: clock.Now})
+	if err != nil {
+		t.Fatalf("NewPartitionedFileWriter failed: %v", err)
+	}
+	if err := w.Reopen(); err != nil {
+		t.Fatalf("Reopen before the first write failed: %v", err)
+	}
+	_, _ = w.Write([]byte("one\n"))
+	if err := os.Remove(w.Path()); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := w.Reopen(); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	_, _ = w.Write([]byte("two\n"))
+	if data, _ := os.ReadFile(w.Path()); string(data) != "two\n" {
+		t.Errorf("unexpected partition contents %q", data)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.Reopen(); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("expected ErrCodeWriterNotAvailable after Close, got %v", err)
+	}
+}
+
+func TestLogger_ReopenClosedAndNonReopenable(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Errorf("Reopen with a plain output failed: %v", err)
+	}
+	safeCloseWithOptionsLogger(t, logger)
+	if err := logger.Reopen(); err != ErrLoggerClosed {
+		t.Errorf("Reopen after Close = %v, want ErrLoggerClosed", err)
+	}
+}
@@ -0,0 +1,17 @@
+// reopen_unix.go: Default reopen signal on Unix
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package iris
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReopenSignals is what logrotate and most daemons use.
+var defaultReopenSignals = []os.Signal{syscall.SIGHUP}
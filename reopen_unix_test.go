@@ -0,0 +1,67 @@
+// reopen_unix_test.go: Tests for reopening sinks on SIGHUP
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLogger_ReopenOnSIGHUP(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	out, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	stop := logger.ReopenOnSignal()
+	defer stop()
+
+	logger.Info("before")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("Kill failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break // Reopened
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log file not reopened after SIGHUP")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	logger.Info("after")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "after") {
+		t.Errorf("record not written to the reopened file: %q", data)
+	}
+	stop()
+	stop() // Idempotent
+}
@@ -7,6 +7,9 @@
 package iris
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/agilira/go-errors"
 	"github.com/agilira/iris/internal/zephyroslite"
 )
@@ -55,6 +58,33 @@ type Ring struct {
 	// Configuration
 	capacity  int64 // Ring buffer capacity (power of two)
 	batchSize int64 // Processing batch size
+
+	// Record processor and batch end hook, for records processed out of
+	// order by processFirst
+	process  ProcessorFunc
+	batchEnd func()
+
+	// Records the consumer processes first once it sees the ring closed
+	// (see closeWith) or before its next batch (see prioritize); nil once
+	// done or when nothing has priority
+	first atomic.Pointer[func(*Record) bool]
+
+	// Inline processor used instead of z when Config.Inline is set
+	inline *inlineRing
+
+	// Generations used instead of z when Config.MaxCapacity is set, and
+	// the function building a ZephyrosLight of a given capacity for them
+	elastic *elasticRing
+	build   func(capacity int64) (*zephyroslite.ZephyrosLight[Record], error)
+
+	// Ring for Warn and above, drained first (nil = WithPriorityLane unset)
+	lane *priorityLane
+
+	// Lifecycle, shared by every logger writing to this ring
+	state              atomic.Int32  // LifecycleState
+	drained            chan struct{} // Closed once the ring reaches StateClosed
+	notStartedReported atomic.Bool   // Not-started diagnostic already emitted
+	abandoned          atomic.Bool   // Shutdown deadline passed: discard what is left
 }
 
 // newRing creates a new ultra-high performance logging ring buffer with embedded Zephyros Light
@@ -69,6 +99,7 @@ type Ring struct {
 //   - numRings: Ignored (kept for API compatibility, single ring architecture)
 //   - backpressurePolicy: DropOnFull or BlockOnFull behavior when buffer is full
 //   - idleStrategy: Strategy controlling CPU usage when no work is available
+//   - slotPadding: Keep a cache line between adjacent slots (see Config.SlotPadding)
 //   - processor: Function to process each log record
 //
 // Embedded Zephyros Light Features:
@@ -88,7 +119,7 @@ type Ring struct {
 // Returns:
 //   - *Ring: Configured ring buffer with embedded Zephyros Light engine
 //   - error: Configuration error if parameters are invalid
-func newRing(capacity, batchSize int64, architecture Architecture, numRings int, backpressurePolicy zephyroslite.BackpressurePolicy, idleStrategy IdleStrategy, processor ProcessorFunc) (*Ring, error) {
+func newRing(capacity, batchSize int64, architecture Architecture, numRings int, backpressurePolicy zephyroslite.BackpressurePolicy, idleStrategy IdleStrategy, slotPadding bool, processor ProcessorFunc) (*Ring, error) {
 	// Params architecture and numRings are kept for API compatibility but not used
 	// as this simplified version always uses the embedded Zephyros Light engine
 	_ = architecture // Explicitly mark as unused (kept for API compatibility)
@@ -128,22 +159,28 @@ func newRing(capacity, batchSize int64, architecture Architecture, numRings int,
 	ring := &Ring{
 		capacity:  capacity,
 		batchSize: batchSize,
-	}
-
-	// Create embedded Zephyros Light processor wrapper
-	zephyrosProcessor := func(record *Record) {
-		processor(record)
+		process:   processor,
+		drained:   make(chan struct{}),
 	}
 
 	// Create embedded Zephyros Light ring buffer with idle strategy
-	builder := zephyroslite.NewBuilder[Record](capacity).
-		WithProcessor(zephyrosProcessor).
-		WithBatchSize(batchSize).
-		WithBackpressurePolicy(backpressurePolicy).
-		WithIdleStrategy(idleStrategy)
+	ring.build = func(capacity int64) (*zephyroslite.ZephyrosLight[Record], error) {
+		z, err := zephyroslite.NewBuilder[Record](capacity).
+			WithProcessor(ring.processSlot).
+			WithBatchSize(batchSize).
+			WithBackpressurePolicy(backpressurePolicy).
+			WithIdleStrategy(idleStrategy).
+			WithSlotPadding(slotPadding).
+			Build()
+		if err == nil {
+			z.SetCloseHook(ring.processFirst)
+			z.SetRequestHook(ring.processFirst)
+		}
+		return z, err
+	}
 
 	var err error
-	ring.z, err = builder.Build()
+	ring.z, err = ring.build(capacity)
 	if err != nil {
 		return nil, errors.Wrap(err, ErrCodeRingBuildFailed, "failed to build embedded Zephyros Light ring buffer").
 			WithContext("capacity", capacity).
@@ -154,6 +191,16 @@ func newRing(capacity, batchSize int64, architecture Architecture, numRings int,
 	return ring, nil
 }
 
+// processSlot is the ZephyrosLight processor: it processes a record unless
+// processFirst already did.
+func (r *Ring) processSlot(record *Record) {
+	if record.prioritized {
+		record.prioritized = false // Already processed by processFirst
+		return
+	}
+	r.process(record)
+}
+
 // Write adds a log record to the ring buffer using zero-allocation pattern
 //
 // The fill function is called with a pointer to a pre-allocated Record in the
@@ -186,10 +233,62 @@ func newRing(capacity, batchSize int64, architecture Architecture, numRings int,
 //	    r.Timestamp = time.Now()
 //	})
 func (r *Ring) Write(fill func(*Record)) bool {
+	if r.inline != nil {
+		return r.inline.write(fill)
+	}
+	if r.elastic != nil {
+		g, stripe := r.elastic.enter()
+		ok := g.z.Write(fill)
+		g.leave(stripe)
+		return ok
+	}
 	// Simplified: Direct write to embedded ZephyrosLight
 	return r.z.Write(fill)
 }
 
+// WriteBatch writes n records, calling fill with i from 0 to n-1 and the
+// slot for record i. Slots are claimed in contiguous blocks, one atomic
+// operation per block. With DropOnFull the batch stops once the ring is
+// full; with BlockOnFull it waits for room.
+//
+// Returns:
+//   - int: Number of records written (records 0 to the result minus one)
+func (r *Ring) WriteBatch(n int, fill func(i int, rec *Record)) int {
+	if r.inline != nil {
+		return r.inline.writeBatch(n, fill)
+	}
+	if r.elastic != nil {
+		g, stripe := r.elastic.enter()
+		written := g.z.WriteBatch(n, fill)
+		g.leave(stripe)
+		return written
+	}
+	return r.z.WriteBatch(n, fill)
+}
+
+// WriteContext is Write with cancellation: with the BlockOnFull policy it
+// stops waiting for a free slot once ctx is done. Inline rings and the
+// DropOnFull policy never wait.
+//
+// Returns:
+//   - error: nil if written; zephyroslite.ErrRingClosed,
+//     zephyroslite.ErrRingFull or ctx.Err() if the record was dropped
+func (r *Ring) WriteContext(ctx context.Context, fill func(*Record)) error {
+	if r.inline != nil {
+		if !r.inline.write(fill) {
+			return zephyroslite.ErrRingClosed
+		}
+		return nil
+	}
+	if r.elastic != nil {
+		g, stripe := r.elastic.enter()
+		err := g.z.WriteContext(ctx, fill)
+		g.leave(stripe)
+		return err
+	}
+	return r.z.WriteContext(ctx, fill)
+}
+
 // Flush ensures all pending writes are visible to the consumer
 //
 // In the embedded ZephyrosLight architecture, this method ensures that all writes
@@ -199,10 +298,44 @@ func (r *Ring) Write(fill func(*Record)) bool {
 // Note: In normal operation, flushing is automatic and this method exists
 // primarily for API compatibility and testing scenarios.
 func (r *Ring) Flush() error {
+	if r.inline != nil {
+		return nil // Records are processed before Write returns
+	}
+	if r.lane != nil {
+		if err := r.lane.z.Flush(); err != nil {
+			return err
+		}
+	}
+	if r.elastic != nil {
+		return r.elastic.flush()
+	}
 	// Simplified: Direct flush to embedded ZephyrosLight
 	return r.z.Flush()
 }
 
+// Snapshot calls visit with read-only access to up to max records that have
+// been written but not yet processed, oldest first.
+//
+// The consumer is paused while visit runs, so visit must copy what it needs
+// and return quickly without retaining or modifying the record. In inline
+// mode records never wait in a buffer and Snapshot visits nothing.
+//
+// Returns:
+//   - int: Number of records visited
+func (r *Ring) Snapshot(max int, visit func(*Record)) int {
+	if r.inline != nil {
+		return 0
+	}
+	visited := 0
+	if r.lane != nil {
+		visited = r.lane.z.Snapshot(max, visit) // Processed first
+	}
+	if r.elastic != nil {
+		return visited + r.elastic.snapshot(max-visited, visit)
+	}
+	return visited + r.z.Snapshot(max-visited, visit)
+}
+
 // Loop starts the record processing loop (CONSUMER THREAD ONLY)
 //
 // This method should be called from exactly one goroutine to consume and
@@ -217,11 +350,16 @@ func (r *Ring) Flush() error {
 //   - Simplified idle strategy to minimize CPU usage
 //   - Guaranteed processing of all records during shutdown
 //
-// Warning: Only call this method from one goroutine per ring buffer.
-// Multiple consumers will cause race conditions and data loss.
+// Only the first call runs a consumer: Loop returns immediately when the
+// ring was already started (by Loop or Logger.Start) or closed.
 func (r *Ring) Loop() {
-	// Simplified: Direct loop processing with embedded ZephyrosLight
-	r.z.LoopProcess()
+	if r.inline != nil {
+		return // No consumer loop in inline mode
+	}
+	if !r.start() {
+		return // A consumer is already running, or the ring is closed
+	}
+	r.consume()
 }
 
 // ProcessBatch processes a single batch of records and returns the count
@@ -236,30 +374,164 @@ func (r *Ring) Loop() {
 // Note: This is a lower-level method. Most applications should use Loop()
 // which handles the complete consumer lifecycle automatically.
 func (r *Ring) ProcessBatch() int {
+	if r.inline != nil {
+		return 0
+	}
+	if r.lane != nil {
+		if n := r.lane.z.ProcessBatch(); n > 0 {
+			return n // Priority lane first
+		}
+	}
+	if r.elastic != nil {
+		return r.elastic.gen.Load().z.ProcessBatch()
+	}
 	// Simplified: Direct batch processing with embedded ZephyrosLight
 	return r.z.ProcessBatch()
 }
 
+// setBatchEnd installs fn to run on the consumer after each processed
+// batch (after each record or WriteBatch call in inline mode). It must be
+// called before the consumer starts.
+func (r *Ring) setBatchEnd(fn func()) {
+	r.batchEnd = fn
+	if r.inline != nil {
+		r.inline.batchEnd = fn
+		return
+	}
+	if r.lane != nil {
+		r.lane.z.SetBatchEndHook(fn)
+	}
+	if r.elastic != nil {
+		r.elastic.batchEnd = fn
+		r.elastic.gen.Load().z.SetBatchEndHook(fn)
+		return
+	}
+	r.z.SetBatchEndHook(fn)
+}
+
+// processFirst processes the pending records selected by closeWith or
+// prioritize ahead of the others, then runs the batch end hook. It is the
+// close and request hook of the ring's engines, so it runs on the consumer
+// (or on the closing caller when no consumer was started) before the final
+// drain or the next batch, which skip the records it processed. The
+// priority lane is visited first, like it is drained.
+func (r *Ring) processFirst() {
+	first := r.first.Swap(nil)
+	if first == nil {
+		return
+	}
+	processed := 0
+	visit := func(rec *Record) {
+		if rec.prioritized || !(*first)(rec) {
+			return
+		}
+		r.process(rec)
+		rec.prioritized = true
+		processed++
+	}
+	if r.lane != nil {
+		r.lane.z.VisitPending(visit)
+	}
+	if r.elastic != nil {
+		r.elastic.gen.Load().z.VisitPending(visit) // Migrations finish on the consumer
+	} else {
+		r.z.VisitPending(visit)
+	}
+	if processed > 0 && r.batchEnd != nil {
+		r.batchEnd()
+	}
+}
+
+// prioritize makes the consumer process the pending records for which
+// first returns true before its next batch (see processFirst). Records
+// written afterwards keep their order.
+func (r *Ring) prioritize(first func(*Record) bool) {
+	if r.inline != nil {
+		return // Nothing is ever pending
+	}
+	r.first.Store(&first)
+	if r.elastic != nil {
+		r.elastic.gen.Load().z.RequestHook()
+	} else {
+		r.z.RequestHook()
+	}
+}
+
 // Close gracefully shuts down the ring buffer
 //
-// This method signals the consumer to stop processing and ensures all
-// buffered records are processed before shutdown. It is safe to call
-// multiple times and from multiple goroutines.
+// This method signals the consumer to stop processing and waits until all
+// buffered records are processed. It is safe to call multiple times and
+// from multiple goroutines: every call returns once the ring is drained.
 //
 // After Close() is called:
 //   - Write() will return false for all subsequent calls
-//   - Loop() will process all remaining records and then exit
+//   - Loop() processes all remaining records and then exits
 //   - The ring buffer becomes unusable
 //
 // Shutdown Guarantees:
-//   - All buffered records are processed before shutdown
+//   - All records accepted by Write() are processed before Close returns,
+//     by the caller itself when no consumer was ever started
 //   - Multiple Close() calls are safe (idempotent)
 //   - Deterministic shutdown behavior for testing
-func (r *Ring) Close() {
-	// Simplified: Direct close with embedded ZephyrosLight
+//
+// Returns:
+//   - bool: true for the call that closed the ring, false for later calls
+func (r *Ring) Close() bool {
+	return r.closeWith(nil)
+}
+
+// closeWith is Close, first processing the pending records for which first
+// returns true (see processFirst) when first is not nil.
+func (r *Ring) closeWith(first func(*Record) bool) bool {
+	for {
+		s := LifecycleState(r.state.Load())
+		if s != StateNew && s != StateStarted {
+			<-r.drained
+			return false
+		}
+		if !r.state.CompareAndSwap(int32(s), int32(StateDraining)) {
+			continue // Raced with Start or another Close
+		}
+		switch {
+		case r.inline != nil:
+			r.inline.close()
+			r.state.Store(int32(StateClosed))
+			close(r.drained)
+		case s == StateNew:
+			if first != nil {
+				r.first.Store(&first)
+			}
+			r.closeEngine()
+			r.consume() // No consumer: drain in the caller
+		default:
+			if first != nil {
+				r.first.Store(&first)
+			}
+			r.closeEngine()
+		}
+		<-r.drained
+		return true
+	}
+}
+
+// closeEngine closes the ZephyrosLight ring, or the current generation of
+// an elastic ring.
+func (r *Ring) closeEngine() {
+	if r.lane != nil {
+		r.lane.z.Close()
+	}
+	if r.elastic != nil {
+		r.elastic.close()
+		return
+	}
 	r.z.Close()
 }
 
+// Closed reports whether Close has been called.
+func (r *Ring) Closed() bool {
+	return r.state.Load() >= int32(StateDraining)
+}
+
 // Stats returns detailed performance statistics for monitoring and debugging
 //
 // The returned map contains real-time metrics about the embedded ZephyrosLight
@@ -274,8 +546,14 @@ func (r *Ring) Close() {
 //   - "items_processed": Total records processed
 //   - "items_dropped": Total records dropped due to full buffer
 //   - "closed": Ring buffer closed state (0=open, 1=closed)
-//   - "capacity": Configured ring capacity
+//   - "cas_retries": Slot claims retried after losing a CAS to another producer
+//   - "full_encounters": Writes that found the buffer full
+//   - "capacity": Ring capacity (the current one for an elastic ring)
+//   - "max_capacity", "grows": Elastic rings only: Config.MaxCapacity and
+//     the number of times the ring has grown
 //   - "batch_size": Configured batch size
+//   - "lane_capacity", "lane_size", "lane_processed", "lane_overflow":
+//     Rings with a priority lane only (see WithPriorityLane)
 //   - "utilization_percent": Buffer utilization percentage
 //   - "engine": "zephyros_light" (embedded engine identifier)
 //
@@ -288,8 +566,18 @@ func (r *Ring) Close() {
 //	fmt.Printf("Buffer utilization: %d%%\n", stats["utilization_percent"])
 //	fmt.Printf("Items buffered: %d\n", stats["items_buffered"])
 func (r *Ring) Stats() map[string]int64 {
-	// Get stats from embedded ZephyrosLight
-	stats := r.z.Stats()
+	// Get stats from embedded ZephyrosLight (or the inline processor)
+	var stats map[string]int64
+	capacity := r.capacity
+	switch {
+	case r.inline != nil:
+		stats = r.inline.stats()
+	case r.elastic != nil:
+		stats = r.elastic.stats()
+		capacity = stats["buffer_size"]
+	default:
+		stats = r.z.Stats()
+	}
 
 	// Create result map with Ring-specific additions
 	result := make(map[string]int64)
@@ -300,14 +588,20 @@ func (r *Ring) Stats() map[string]int64 {
 	}
 
 	// Add Ring-specific stats
-	result["capacity"] = r.capacity
+	result["capacity"] = capacity
 	result["batch_size"] = r.batchSize
 	result["engine"] = 1      // 1 = zephyros_light embedded
 	result["go_routines"] = 1 // Single processing goroutine (compatibility)
+	if r.inline != nil {
+		result["go_routines"] = 0 // Records are processed by the caller
+	}
+	if r.lane != nil {
+		r.lane.addStats(result)
+	}
 
 	// Calculate utilization percentage
-	if itemsBuffered, exists := stats["items_buffered"]; exists && r.capacity > 0 {
-		result["utilization_percent"] = (itemsBuffered * 100) / r.capacity
+	if itemsBuffered, exists := stats["items_buffered"]; exists && capacity > 0 {
+		result["utilization_percent"] = (itemsBuffered * 100) / capacity
 	}
 
 	return result
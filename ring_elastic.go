@@ -0,0 +1,262 @@
+// ring_elastic.go: Elastic ring capacity
+//
+// An elastic ring starts at Config.Capacity and doubles, up to
+// Config.MaxCapacity, when writers keep finding it full for Config.GrowAfter
+// without the consumer catching up in between. Transient bursts are
+// absorbed without provisioning the peak capacity up front; the ring never
+// shrinks back.
+//
+// Migration protocol: the ring is a sequence of generations, each its own
+// ZephyrosLight. Producers enter the current generation through a striped
+// gate and leave it once their record is published. To grow, the consumer
+// publishes the new generation, retires the old one (producers that see it
+// retired re-enter the new one), then keeps processing the old generation
+// until no producer is inside it and it is empty. Only then does it move
+// on, so records are consumed in the order each producer wrote them.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// DefaultGrowAfter is how long the ring must stay saturated before an
+// elastic ring grows when Config.GrowAfter is zero.
+const DefaultGrowAfter = 10 * time.Millisecond
+
+// ringGen is one generation of an elastic ring.
+type ringGen struct {
+	z        *zephyroslite.ZephyrosLight[Record]
+	capacity int64
+	writers  *stripedCounter // Producers inside z (width 1)
+	retired  atomic.Bool     // Set once a larger generation replaced this one
+}
+
+// Counters of retired generations, carried over into Stats.
+const (
+	retiredProcessed = iota
+	retiredDropped
+	retiredCASRetries
+	retiredFullEncounters
+	retiredCounters
+)
+
+// elasticRing holds the generations of a ring with Config.MaxCapacity set.
+type elasticRing struct {
+	gen      atomic.Pointer[ringGen] // Generation producers write to
+	retiring atomic.Pointer[ringGen] // Generation being drained, if any
+
+	build     func(capacity int64) (*zephyroslite.ZephyrosLight[Record], error)
+	batchEnd  func()
+	idle      IdleStrategy
+	max       int64 // Config.MaxCapacity
+	growAfter time.Duration
+
+	// mu orders a generation switch against Close, so that Close always
+	// closes the last generation
+	mu      sync.Mutex
+	closing bool
+
+	grows   atomic.Int64
+	retired [retiredCounters]atomic.Int64
+
+	// Consumer goroutine only: saturation tracking, and the capacity
+	// growth stops at (lowered below max if a generation cannot be built)
+	lastFull       int64
+	saturatedSince time.Time
+	ceiling        int64
+}
+
+// enableElastic lets the ring grow up to maxCapacity (see Config.MaxCapacity).
+// It must be called before the consumer starts.
+func (r *Ring) enableElastic(maxCapacity int64, growAfter time.Duration, idle IdleStrategy) error {
+	if maxCapacity <= 0 || maxCapacity&(maxCapacity-1) != 0 || maxCapacity < r.capacity {
+		return NewLoggerError(ErrCodeRingInvalidCapacity, "ring max capacity must be a power of two not below the capacity").
+			WithContext("max_capacity", maxCapacity).
+			WithContext("capacity", r.capacity)
+	}
+	if growAfter <= 0 {
+		growAfter = DefaultGrowAfter
+	}
+	if idle == nil {
+		idle = zephyroslite.NewProgressiveIdleStrategy()
+	}
+	e := &elasticRing{build: r.build, idle: idle, max: maxCapacity, growAfter: growAfter, ceiling: maxCapacity}
+	e.gen.Store(&ringGen{z: r.z, capacity: r.capacity, writers: newStripedCounter(1)})
+	r.elastic = e
+	r.z = nil // Every access goes through the current generation
+	return nil
+}
+
+// enter returns the current generation with the caller counted as one of
+// its writers, and the gate stripe to leave it with.
+func (e *elasticRing) enter() (*ringGen, int) {
+	for {
+		g := e.gen.Load()
+		stripe := g.writers.stripe()
+		g.writers.addAt(stripe, 0, 1)
+		if !g.retired.Load() {
+			return g, stripe
+		}
+		g.writers.addAt(stripe, 0, -1) // Replaced meanwhile: use the new one
+	}
+}
+
+// leave ends a write entered with enter.
+func (g *ringGen) leave(stripe int) {
+	g.writers.addAt(stripe, 0, -1)
+}
+
+// consume runs the consumer of an elastic ring until it is closed and
+// drained.
+func (e *elasticRing) consume() {
+	for {
+		processed, closed := e.step()
+		switch {
+		case closed:
+			return
+		case processed > 0:
+			e.idle.Reset()
+		default:
+			e.idle.Idle()
+		}
+	}
+}
+
+// step processes one batch of the current generation, growing the ring if
+// it stays saturated, and reports how many records it processed and whether
+// the ring is closed and drained.
+func (e *elasticRing) step() (int, bool) {
+	g := e.gen.Load()
+	if g.z.Closed() {
+		g.z.LoopProcess() // Final drain of the last generation
+		return 0, true
+	}
+	processed := g.z.ProcessBatch()
+	if processed == 0 {
+		e.saturatedSince = time.Time{} // Caught up: the burst is over
+	} else if g.capacity < e.ceiling && e.saturated(g) {
+		e.grow(g)
+	}
+	return processed, false
+}
+
+// saturated reports whether g has stayed saturated for growAfter: writers
+// found it full at least growAfter apart, and it never emptied in between.
+func (e *elasticRing) saturated(g *ringGen) bool {
+	full := g.z.FullEncounters()
+	if full == e.lastFull {
+		return false
+	}
+	e.lastFull = full
+	now := time.Now()
+	if e.saturatedSince.IsZero() {
+		e.saturatedSince = now
+		return false
+	}
+	return now.Sub(e.saturatedSince) >= e.growAfter
+}
+
+// grow replaces old with a generation of twice its capacity and drains old.
+// It runs on the consumer goroutine.
+func (e *elasticRing) grow(old *ringGen) {
+	capacity := min(old.capacity*2, e.ceiling)
+	z, err := e.build(capacity)
+	if err != nil {
+		handleError(errors.Wrap(err, ErrCodeRingBuildFailed, "failed to grow elastic ring").
+			WithContext("capacity", capacity))
+		e.ceiling = old.capacity // Stop trying
+		return
+	}
+	if e.batchEnd != nil {
+		z.SetBatchEndHook(e.batchEnd)
+	}
+	next := &ringGen{z: z, capacity: capacity, writers: newStripedCounter(1)}
+
+	e.mu.Lock()
+	if e.closing {
+		e.mu.Unlock()
+		return
+	}
+	e.retiring.Store(old)
+	e.gen.Store(next)
+	old.retired.Store(true)
+	e.mu.Unlock()
+
+	// Producers that entered old before it was retired may still be
+	// claiming or publishing slots, or waiting for room under BlockOnFull;
+	// keep consuming until they have all left, then empty it
+	for old.z.ProcessBatch() > 0 || old.writers.load(0) != 0 {
+		runtime.Gosched()
+	}
+	for old.z.ProcessBatch() > 0 {
+	}
+	old.z.Close()
+
+	stats := old.z.Stats()
+	e.retired[retiredProcessed].Add(stats["items_processed"])
+	e.retired[retiredDropped].Add(stats["items_dropped"])
+	e.retired[retiredCASRetries].Add(stats["cas_retries"])
+	e.retired[retiredFullEncounters].Add(stats["full_encounters"])
+	e.retiring.Store(nil)
+	e.grows.Add(1)
+	e.lastFull, e.saturatedSince = 0, time.Time{}
+}
+
+// close closes the current generation; a migration in progress finishes
+// first on the consumer, which then drains the closed generation.
+func (e *elasticRing) close() {
+	e.mu.Lock()
+	e.closing = true
+	e.gen.Load().z.Close()
+	e.mu.Unlock()
+}
+
+// flush waits for the records of the generation being retired, if any,
+// then for those of the current one.
+func (e *elasticRing) flush() error {
+	if old := e.retiring.Load(); old != nil {
+		if err := old.z.Flush(); err != nil {
+			return err
+		}
+	}
+	return e.gen.Load().z.Flush()
+}
+
+// snapshot visits pending records of the retiring generation, then of the
+// current one.
+func (e *elasticRing) snapshot(max int, visit func(*Record)) int {
+	visited := 0
+	if old := e.retiring.Load(); old != nil {
+		visited = old.z.Snapshot(max, visit)
+	}
+	return visited + e.gen.Load().z.Snapshot(max-visited, visit)
+}
+
+// stats returns the current generation's statistics with the counters of
+// retired generations added in, plus the elastic ones.
+func (e *elasticRing) stats() map[string]int64 {
+	g := e.gen.Load()
+	stats := g.z.Stats()
+	stats["items_processed"] += e.retired[retiredProcessed].Load()
+	stats["items_dropped"] += e.retired[retiredDropped].Load()
+	stats["cas_retries"] += e.retired[retiredCASRetries].Load()
+	stats["full_encounters"] += e.retired[retiredFullEncounters].Load()
+	if old := e.retiring.Load(); old != nil {
+		oldStats := old.z.Stats()
+		stats["items_buffered"] += oldStats["items_buffered"]
+	}
+	stats["max_capacity"] = e.max
+	stats["grows"] = e.grows.Load()
+	return stats
+}
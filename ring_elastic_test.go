@@ -0,0 +1,223 @@
+// ring_elastic_test.go: Tests for elastic ring capacity
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// elasticProducers writes records from each of producers goroutines into rg
+// for d, numbering them in the "p" and "seq" fields, and returns the number
+// of writes attempted and the number that succeeded.
+func elasticProducers(rg *Ring, producers int, d time.Duration) (attempted, written int64) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	deadline := time.Now().Add(d)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			var seq, n int64
+			for ; time.Now().Before(deadline); seq++ {
+				if rg.Write(func(r *Record) {
+					r.resetForWrite()
+					r.AddField(Int("p", p))
+					r.AddField(Int64("seq", seq))
+				}) {
+					n++
+				} else {
+					runtime.Gosched() // Let the consumer run on a single CPU
+				}
+			}
+			mu.Lock()
+			attempted += seq
+			written += n
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return attempted, written
+}
+
+// orderChecker records, on the consumer, the records of each producer and
+// whether they arrived in the order they were written.
+type orderChecker struct {
+	last      map[int64]int64
+	processed int64
+	outOfOrd  int
+}
+
+func (c *orderChecker) process(r *Record) {
+	p, seq := r.fields[0].I64, r.fields[1].I64
+	if last, ok := c.last[p]; ok && seq <= last {
+		c.outOfOrd++
+	}
+	c.last[p] = seq
+	c.processed++
+	if c.processed%16 == 0 {
+		time.Sleep(50 * time.Microsecond) // A consumer slower than the producers
+	}
+}
+
+func TestElasticRing_GrowsUnderSaturation(t *testing.T) {
+	for _, policy := range []zephyroslite.BackpressurePolicy{zephyroslite.BlockOnFull, zephyroslite.DropOnFull} {
+		t.Run(policy.String(), func(t *testing.T) {
+			checker := &orderChecker{last: make(map[int64]int64)}
+			rg, err := newRing(64, 16, SingleRing, 1, policy, BalancedStrategy, false, checker.process)
+			if err != nil {
+				t.Fatalf("newRing failed: %v", err)
+			}
+			if err := rg.enableElastic(1024, time.Millisecond, BalancedStrategy); err != nil {
+				t.Fatalf("enableElastic failed: %v", err)
+			}
+			go rg.Loop()
+
+			attempted, written := elasticProducers(rg, 4, 50*time.Millisecond)
+			rg.Close()
+
+			stats := rg.Stats()
+			if stats["grows"] == 0 || stats["capacity"] <= 64 || stats["capacity"] > 1024 {
+				t.Errorf("saturated ring did not grow within bounds: grows=%d capacity=%d", stats["grows"], stats["capacity"])
+			}
+			if stats["max_capacity"] != 1024 {
+				t.Errorf("max_capacity = %d, want 1024", stats["max_capacity"])
+			}
+			if checker.processed != written || stats["items_processed"] != written {
+				t.Errorf("%d records written, %d processed (stats %d): records lost in migration", written, checker.processed, stats["items_processed"])
+			}
+			if written+stats["items_dropped"] != attempted {
+				t.Errorf("written %d + dropped %d != %d attempted", written, stats["items_dropped"], attempted)
+			}
+			if policy == zephyroslite.BlockOnFull && written != attempted {
+				t.Errorf("BlockOnFull wrote %d of %d records", written, attempted)
+			}
+			if checker.outOfOrd != 0 {
+				t.Errorf("%d records processed out of their producer's order", checker.outOfOrd)
+			}
+		})
+	}
+}
+
+func TestElasticRing_IdleDoesNotGrow(t *testing.T) {
+	processed := 0
+	rg, err := newTestRing(64, 16, func(*Record) { processed++ })
+	if err != nil {
+		t.Fatalf("newRing failed: %v", err)
+	}
+	if err := rg.enableElastic(1024, time.Millisecond, BalancedStrategy); err != nil {
+		t.Fatalf("enableElastic failed: %v", err)
+	}
+	go rg.Loop()
+	for i := 0; i < 1000; i++ {
+		rg.Write(func(r *Record) { r.resetForWrite() })
+		if i%32 == 0 {
+			_ = rg.Flush()
+		}
+	}
+	rg.Close()
+	if stats := rg.Stats(); stats["grows"] != 0 || stats["capacity"] != 64 {
+		t.Errorf("unsaturated ring grew: grows=%d capacity=%d", stats["grows"], stats["capacity"])
+	}
+	if processed != 1000 {
+		t.Errorf("processed %d records, want 1000", processed)
+	}
+}
+
+func TestNew_MaxCapacity(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		maxCapacity int64
+		wantErr     bool
+		elastic     bool
+	}{
+		{"fixed", 0, false, false},
+		{"same as capacity", 64, false, false},
+		{"elastic", 256, false, true},
+		{"not a power of two", 100, true, false},
+		{"below capacity", 32, true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := New(Config{Capacity: 64, BatchSize: 16, MaxCapacity: tc.maxCapacity, Output: &testSyncer{}})
+			if tc.wantErr {
+				if !IsLoggerError(err, ErrCodeLoggerCreation) {
+					t.Fatalf("New = %v, want a logger creation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer logger.Close()
+			if got := logger.r.elastic != nil; got != tc.elastic {
+				t.Errorf("elastic = %v, want %v", got, tc.elastic)
+			}
+			if _, ok := logger.Stats()["ring_max_capacity"]; ok != tc.elastic {
+				t.Errorf("Stats reports ring_max_capacity: %v, want %v", ok, tc.elastic)
+			}
+			logger.Info("written")
+			if err := logger.Sync(); err != nil {
+				t.Errorf("Sync failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestElasticRing_CloseDuringGrowth(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		checker := &orderChecker{last: make(map[int64]int64)}
+		rg, err := newRing(64, 16, SingleRing, 1, zephyroslite.BlockOnFull, BalancedStrategy, false, checker.process)
+		if err != nil {
+			t.Fatalf("newRing failed: %v", err)
+		}
+		if err := rg.enableElastic(4096, time.Microsecond, BalancedStrategy); err != nil {
+			t.Fatalf("enableElastic failed: %v", err)
+		}
+		go rg.Loop()
+		done := make(chan int64)
+		go func() {
+			_, written := elasticProducers(rg, 4, 5*time.Millisecond)
+			done <- written
+		}()
+		time.Sleep(time.Duration(i) * 250 * time.Microsecond)
+		rg.Close()
+		written := <-done
+		if checker.processed != written {
+			t.Fatalf("run %d: %d records accepted, %d processed", i, written, checker.processed)
+		}
+	}
+}
+
+// BenchmarkRing_ElasticWrite compares the write path of a fixed ring with
+// that of an elastic one, whose writers also pass the generation gate.
+func BenchmarkRing_ElasticWrite(b *testing.B) {
+	for _, elastic := range []bool{false, true} {
+		name := map[bool]string{false: "fixed", true: "elastic"}[elastic]
+		b.Run(name, func(b *testing.B) {
+			rg, err := newTestRing(8192, 256, func(*Record) {})
+			if err != nil {
+				b.Fatalf("newRing failed: %v", err)
+			}
+			if elastic {
+				if err := rg.enableElastic(8192*4, 0, BalancedStrategy); err != nil {
+					b.Fatalf("enableElastic failed: %v", err)
+				}
+			}
+			go rg.Loop()
+			defer rg.Close()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rg.Write(func(r *Record) { r.Level = Info })
+			}
+		})
+	}
+}
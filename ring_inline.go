@@ -0,0 +1,116 @@
+// ring_inline.go: Inline (goroutine-free) record processing for Iris
+//
+// Inline mode targets constrained environments (WASM, GOMAXPROCS=1, small
+// CLIs) where a background consumer goroutine and a large pre-allocated ring
+// are undesirable. Records are encoded and written synchronously by the
+// calling goroutine using a single reusable record slot.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// inlineRing processes each record synchronously in the producer goroutine.
+//
+// A mutex serializes producers so the processor keeps its single-consumer
+// guarantee (encoders, outputs and hooks never run concurrently). Hooks and
+// outputs must not log through the same logger, since that would re-enter
+// the mutex.
+type inlineRing struct {
+	mu        sync.Mutex
+	slot      Record
+	processor ProcessorFunc
+	batchEnd  func() // Called after each write or batch (nil = none)
+
+	processed atomic.Int64
+	dropped   atomic.Int64
+	closed    atomic.Bool
+}
+
+// newInlineRing creates a Ring that processes records inline without a
+// background goroutine or pre-allocated buffer.
+func newInlineRing(processor ProcessorFunc) (*Ring, error) {
+	if processor == nil {
+		return nil, NewLoggerError(ErrCodeRingMissingProcessor, "ring processor function is required")
+	}
+	return &Ring{
+		capacity:  1,
+		batchSize: 1,
+		inline:    &inlineRing{processor: processor},
+		drained:   make(chan struct{}),
+	}, nil
+}
+
+// write fills the shared slot and processes it immediately.
+// Returns false once the ring has been closed.
+func (ir *inlineRing) write(fill func(*Record)) bool {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.closed.Load() {
+		ir.dropped.Add(1)
+		return false
+	}
+
+	fill(&ir.slot)
+	ir.processor(&ir.slot)
+	if ir.batchEnd != nil {
+		ir.batchEnd()
+	}
+	ir.processed.Add(1)
+	return true
+}
+
+// writeBatch fills and processes n records under one lock acquisition.
+func (ir *inlineRing) writeBatch(n int, fill func(int, *Record)) int {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.closed.Load() {
+		ir.dropped.Add(int64(n))
+		return 0
+	}
+	for i := 0; i < n; i++ {
+		fill(i, &ir.slot)
+		ir.processor(&ir.slot)
+	}
+	if ir.batchEnd != nil {
+		ir.batchEnd()
+	}
+	ir.processed.Add(int64(n))
+	return n
+}
+
+// close marks the ring closed; in-flight writes complete before it returns.
+func (ir *inlineRing) close() {
+	ir.mu.Lock()
+	ir.closed.Store(true)
+	ir.mu.Unlock()
+}
+
+// stats returns counters using the same keys as the Zephyros Light engine.
+func (ir *inlineRing) stats() map[string]int64 {
+	processed := ir.processed.Load()
+	var closed int64
+	if ir.closed.Load() {
+		closed = 1
+	}
+	return map[string]int64{
+		"writer_position": processed,
+		"reader_position": processed,
+		"buffer_size":     1,
+		"items_buffered":  0,
+		"items_processed": processed,
+		"items_dropped":   ir.dropped.Load(),
+		"closed":          closed,
+		"batch_size":      1,
+		"cas_retries":     0, // Callers are serialized by a mutex, not a CAS
+		"full_encounters": 0,
+	}
+}
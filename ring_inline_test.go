@@ -0,0 +1,139 @@
+// ring_inline_test.go: Tests for inline (goroutine-free) processing mode
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestInlineRing_ProcessesSynchronously(t *testing.T) {
+	var processed []string
+	ring, err := newInlineRing(func(r *Record) {
+		processed = append(processed, r.Msg)
+	})
+	if err != nil {
+		t.Fatalf("newInlineRing failed: %v", err)
+	}
+
+	if !ring.Write(func(r *Record) { r.Msg = "first" }) {
+		t.Fatal("write should succeed")
+	}
+	if len(processed) != 1 || processed[0] != "first" {
+		t.Fatalf("record should be processed before Write returns, got %v", processed)
+	}
+
+	stats := ring.Stats()
+	if stats["items_processed"] != 1 || stats["go_routines"] != 0 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+
+	ring.Close()
+	if ring.Write(func(r *Record) { r.Msg = "late" }) {
+		t.Error("write after close should fail")
+	}
+	if ring.Stats()["items_dropped"] != 1 {
+		t.Errorf("expected dropped write to be counted, got %v", ring.Stats())
+	}
+}
+
+func TestInlineRing_NilProcessor(t *testing.T) {
+	if _, err := newInlineRing(nil); err == nil {
+		t.Error("expected error for nil processor")
+	}
+}
+
+func TestLogger_InlineMode(t *testing.T) {
+	buf := &testSyncer{}
+	before := runtime.NumGoroutine()
+
+	logger, err := New(Config{
+		Level:   Debug,
+		Output:  buf,
+		Encoder: NewJSONEncoder(),
+		Inline:  true,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("inline mode should not start goroutines: before=%d after=%d", before, after)
+	}
+
+	logger.Info("inline message", Str("k", "v"))
+	// No Sync needed: output is written before Info returns
+	if !strings.Contains(buf.String(), `"msg":"inline message"`) {
+		t.Errorf("expected message written inline, got %q", buf.String())
+	}
+}
+
+func TestLogger_InlineMode_ConcurrentWriters(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:   Info,
+		Output:  buf,
+		Encoder: NewTextEncoder(),
+		Inline:  true,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	const goroutines, perGoroutine = 8, 100
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.Info("concurrent", Int("i", i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Count(buf.String(), "\n")
+	if lines != goroutines*perGoroutine {
+		t.Errorf("expected %d lines, got %d", goroutines*perGoroutine, lines)
+	}
+	if got := logger.Stats()["processed"]; got != goroutines*perGoroutine {
+		t.Errorf("expected processed=%d, got %d", goroutines*perGoroutine, got)
+	}
+}
+
+func TestLoadConfig_Inline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"level":"info","inline":true}`), 0600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON failed: %v", err)
+	}
+	if !cfg.Inline {
+		t.Error("expected inline from JSON")
+	}
+
+	t.Setenv("IRIS_INLINE", "true")
+	envCfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if !envCfg.Inline {
+		t.Error("expected inline from IRIS_INLINE")
+	}
+}
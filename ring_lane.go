@@ -0,0 +1,154 @@
+// ring_lane.go: Priority lane for Warn and above
+//
+// A flood of Debug or Info records can fill the ring, and a warning or an
+// error logged at that moment is then dropped or waits behind thousands of
+// lower-level records. A priority lane is a second, small ring reserved for
+// records at Warn and above: the consumer drains it before each batch of the
+// main ring, so they reach the output first, and they only compete with
+// each other for its slots. When the lane is full, records fall back to the
+// main ring and its backpressure policy.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync/atomic"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// DefaultPriorityLaneCapacity is the lane capacity used by
+// WithPriorityLane when capacity is zero.
+const DefaultPriorityLaneCapacity = 256
+
+// priorityLaneMin is the lowest level written to the priority lane.
+const priorityLaneMin = Warn
+
+// priorityLane is the second ring of a Ring with a priority lane.
+type priorityLane struct {
+	z        *zephyroslite.ZephyrosLight[Record]
+	capacity int64
+	idle     IdleStrategy // Consumer idle strategy, when the ring runs its own consumer
+	overflow atomic.Int64 // Writes that found the lane full and used the main ring
+}
+
+// WithPriorityLane reserves a second ring of the given capacity (a power of
+// two, DefaultPriorityLaneCapacity if zero) for records at Warn and above,
+// so that they are accepted and written ahead of a flood of lower-level
+// records filling the main ring.
+//
+// The consumer drains the lane before each batch of the main ring, so a
+// warning can reach the output before Info records logged earlier. Records
+// that find the lane full go to the main ring. Records written with Write
+// and WriteBatch, whose level is only known once they are filled, always use
+// the main ring. The lane applies only to loggers created with it by New;
+// inline loggers have no ring and ignore it.
+//
+// Logger.Stats reports "lane_capacity", "lane_size", "lane_processed" and
+// "lane_overflow" (records that fell back to the main ring).
+//
+// Parameters:
+//   - capacity: Number of lane slots
+//
+// Returns:
+//   - Option: Configuration function to enable the priority lane
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithPriorityLane(512))
+func WithPriorityLane(capacity int64) Option {
+	return func(o *loggerOptions) {
+		if capacity == 0 {
+			capacity = DefaultPriorityLaneCapacity
+		}
+		o.priorityLane = capacity
+	}
+}
+
+// enablePriorityLane adds a priority lane of the given capacity. It must be
+// called before the consumer starts.
+func (r *Ring) enablePriorityLane(capacity int64, idle IdleStrategy) error {
+	if capacity <= 0 || capacity&(capacity-1) != 0 {
+		return NewLoggerError(ErrCodeRingInvalidCapacity, "priority lane capacity must be a power of two").
+			WithContext("lane_capacity", capacity)
+	}
+	if idle == nil {
+		idle = zephyroslite.NewProgressiveIdleStrategy()
+	}
+	// Always DropOnFull: a full lane falls back to the main ring, which
+	// applies the configured policy
+	z, err := zephyroslite.NewBuilder[Record](capacity).
+		WithProcessor(r.processSlot).
+		WithBatchSize(min(r.batchSize, capacity)).
+		WithBackpressurePolicy(zephyroslite.DropOnFull).
+		WithIdleStrategy(idle).
+		Build()
+	if err != nil {
+		return errors.Wrap(err, ErrCodeRingBuildFailed, "failed to build priority lane").
+			WithContext("lane_capacity", capacity)
+	}
+	r.lane = &priorityLane{z: z, capacity: capacity, idle: idle}
+	return nil
+}
+
+// writeLane writes a record at level to the priority lane if the level
+// belongs there. It reports false when the record must go to the main ring.
+// The ring must have a lane.
+func (r *Ring) writeLane(level Level, fill func(*Record)) bool {
+	lane := r.lane
+	if !level.Enabled(priorityLaneMin) {
+		return false
+	}
+	if lane.z.Write(fill) {
+		return true
+	}
+	if !lane.z.Closed() {
+		lane.overflow.Add(1)
+	}
+	return false
+}
+
+// drain processes the records waiting in the lane, at most one lane
+// capacity's worth so that a steady stream of warnings cannot starve the
+// main ring.
+func (lane *priorityLane) drain() int {
+	processed := 0
+	for processed < int(lane.capacity) {
+		n := lane.z.ProcessBatch()
+		if n == 0 {
+			break
+		}
+		processed += n
+	}
+	return processed
+}
+
+// consumeLane runs the consumer of a ring with a priority lane until it is
+// closed and drained.
+func (r *Ring) consumeLane() {
+	idle := r.lane.idle
+	for {
+		processed, closed := r.step()
+		switch {
+		case closed:
+			return
+		case processed > 0:
+			idle.Reset()
+		default:
+			idle.Idle()
+		}
+	}
+}
+
+// addStats adds the lane statistics to stats.
+func (lane *priorityLane) addStats(stats map[string]int64) {
+	ls := lane.z.Stats()
+	stats["lane_capacity"] = lane.capacity
+	stats["lane_size"] = ls["items_buffered"]
+	stats["lane_processed"] = ls["items_processed"]
+	stats["lane_overflow"] = lane.overflow.Load()
+}
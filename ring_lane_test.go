@@ -0,0 +1,112 @@
+// ring_lane_test.go: Tests for the priority lane
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+// messages returns the messages of the JSON records in output, in order.
+func messages(output string) []string {
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if _, rest, ok := strings.Cut(line, `"msg":"`); ok {
+			msg, _, _ := strings.Cut(rest, `"`)
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+func TestPriorityLane_SurvivesFlood(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"fixed", Config{Capacity: 8, BatchSize: 4}},
+		{"elastic", Config{Capacity: 8, BatchSize: 4, MaxCapacity: 8 << 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &testSyncer{}
+			cfg := tt.cfg
+			cfg.Level, cfg.Output, cfg.Encoder, cfg.AutoStart = Debug, out, NewJSONEncoder(), AutoStartOff
+			logger, err := New(cfg, WithPriorityLane(4))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			captureErrors(t) // Silence the not-started diagnostic
+
+			for i := 0; i < 20; i++ {
+				logger.Debug("flood")
+			}
+			for _, ok := range []bool{logger.Warn("warn-1"), logger.Error("error-1"), logger.Warn("warn-2")} {
+				if !ok {
+					t.Error("Warn+ record dropped with the main ring full")
+				}
+			}
+			if got, ring := logger.Stats()["lane_size"], logger.r.Stats()["lane_size"]; got != 3 || ring != 3 {
+				t.Errorf("lane_size = %d (ring %d), want 3 records waiting in the lane", got, ring)
+			}
+			logger.Start()
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			msgs := messages(out.String())
+			if len(msgs) != 11 || strings.Join(msgs[:3], ",") != "warn-1,error-1,warn-2" {
+				t.Errorf("wrote %v, want the 3 lane records first, then 8 flood records", msgs)
+			}
+			stats := logger.Stats()
+			if stats["lane_capacity"] != 4 || stats["lane_processed"] != 3 || stats["lane_overflow"] != 0 {
+				t.Errorf("lane stats %v", stats)
+			}
+		})
+	}
+}
+
+func TestPriorityLane_Overflow(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 8, BatchSize: 4, AutoStart: AutoStartOff}, WithPriorityLane(2))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	captureErrors(t)
+
+	logger.Info("info-1")
+	for _, msg := range []string{"warn-1", "warn-2", "warn-3"} {
+		logger.Warn(msg)
+	}
+	if got := logger.Stats()["lane_overflow"]; got != 1 {
+		t.Errorf("lane_overflow = %d, want 1", got)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// warn-3 found the lane full and waited in the main ring
+	if got := strings.Join(messages(out.String()), ","); got != "warn-1,warn-2,info-1,warn-3" {
+		t.Errorf("write order %s", got)
+	}
+}
+
+func TestPriorityLane_InvalidCapacity(t *testing.T) {
+	_, err := New(Config{Output: &testSyncer{}, Encoder: NewJSONEncoder()}, WithPriorityLane(6))
+	if !IsLoggerError(err, ErrCodeRingInvalidCapacity) {
+		t.Errorf("got %v, want ErrCodeRingInvalidCapacity", err)
+	}
+
+	// Inline loggers have no ring to prioritize
+	logger, err := New(Config{Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true}, WithPriorityLane(0))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	if _, ok := logger.Stats()["lane_capacity"]; ok {
+		t.Error("inline logger reports a priority lane")
+	}
+}
@@ -7,6 +7,7 @@
 package iris
 
 import (
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -17,7 +18,7 @@ import (
 
 // Helper function for creating rings with default idle strategy in tests
 func newTestRing(capacity, batchSize int64, processor ProcessorFunc) (*Ring, error) {
-	return newRing(capacity, batchSize, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, processor)
+	return newRing(capacity, batchSize, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, false, processor)
 }
 
 func TestNewRing_ValidConfiguration(t *testing.T) {
@@ -46,6 +47,26 @@ func TestNewRing_ValidConfiguration(t *testing.T) {
 	ring.Close()
 }
 
+func TestNew_SlotPadding(t *testing.T) {
+	if !EffectiveConfig(Config{SlotPadding: true}).SlotPadding {
+		t.Fatal("Config.SlotPadding not carried into the effective configuration")
+	}
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, SlotPadding: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		logger.Info("padded", Int("i", i))
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := strings.Count(out.String(), `"msg":"padded"`); got+int(logger.Stats()["dropped"]) != 100 {
+		t.Errorf("wrote %d records and dropped %d, want 100 in total", got, logger.Stats()["dropped"])
+	}
+}
+
 func TestNewRing_AutoBatchSizing(t *testing.T) {
 	processor := func(r *Record) {}
 
@@ -81,7 +102,7 @@ func TestNewRing_InvalidCapacity(t *testing.T) {
 	invalidCapacities := []int64{0, -1, 3, 5, 6, 7, 9, 15, 17, 100, 1000}
 
 	for _, capacity := range invalidCapacities {
-		ring, err := newRing(capacity, 64, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, processor)
+		ring, err := newRing(capacity, 64, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, false, processor)
 		if err == nil {
 			t.Errorf("Expected error for invalid capacity %d, got nil", capacity)
 			if ring != nil {
@@ -107,7 +128,7 @@ func TestNewRing_InvalidBatchSize(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		ring, err := newRing(tc.capacity, tc.batchSize, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, processor)
+		ring, err := newRing(tc.capacity, tc.batchSize, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, false, processor)
 		if tc.batchSize == 0 {
 			// Zero batch size should auto-size, not error
 			if err != nil {
@@ -125,7 +146,7 @@ func TestNewRing_InvalidBatchSize(t *testing.T) {
 }
 
 func TestNewRing_MissingProcessor(t *testing.T) {
-	ring, err := newRing(1024, 128, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, nil)
+	ring, err := newRing(1024, 128, SingleRing, 1, zephyroslite.DropOnFull, BalancedStrategy, false, nil)
 	if err == nil {
 		t.Error("Expected error for missing processor, got nil")
 		if ring != nil {
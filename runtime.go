@@ -0,0 +1,246 @@
+// runtime.go: Shared consumer goroutines for many loggers
+//
+// Every started logger normally runs its own consumer goroutine. A process
+// made of many components, each constructing its own logger, ends up with
+// as many consumers, each spinning or sleeping on its own. A Runtime bounds
+// that: loggers created with WithRuntime are consumed by the runtime's
+// fixed pool of workers, each worker serving its loggers' rings in turn,
+// one batch per ring per round.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Runtime is a pool of consumer goroutines shared by several loggers.
+//
+// Each logger attached with WithRuntime is assigned, when it starts, to the
+// worker serving the fewest loggers, and stays with it until it is closed.
+// A ring is only ever consumed by one goroutine, so records keep the order
+// and the single-consumer guarantees of a dedicated consumer. The cost is
+// latency: a worker interleaves the batches of all its rings, so a slow
+// output (or a growing elastic ring) delays the other loggers of the same
+// worker.
+//
+// A Runtime is safe for concurrent use. Close it after, or instead of,
+// closing its loggers.
+type Runtime struct {
+	mu      sync.Mutex
+	workers []*runtimeWorker
+	loggers map[*Ring]*Logger // Attached loggers by ring
+	closed  bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// runtimeWorker is one consumer goroutine of a Runtime.
+type runtimeWorker struct {
+	rings atomic.Pointer[[]*Ring] // Copy-on-write under Runtime.mu
+	wake  chan struct{}           // Signals a ring added to an idle worker
+}
+
+// runtimeConfig holds the settings RuntimeOptions apply.
+type runtimeConfig struct {
+	workers int
+	newIdle func() IdleStrategy
+}
+
+// RuntimeOption configures a Runtime.
+type RuntimeOption func(*runtimeConfig)
+
+// WithRuntimeWorkers sets the number of consumer goroutines (default 1).
+// Values below 1 are ignored.
+func WithRuntimeWorkers(n int) RuntimeOption {
+	return func(c *runtimeConfig) {
+		if n >= 1 {
+			c.workers = n
+		}
+	}
+}
+
+// WithRuntimeIdleStrategy sets how workers wait when none of their rings
+// has records (default: NewProgressiveIdleStrategy). Idle strategies keep
+// per-goroutine state, so newStrategy is called once per worker and every
+// worker gets a strategy of its own. The strategies replace
+// Config.IdleStrategy for attached loggers.
+//
+// Parameters:
+//   - newStrategy: Constructor such as NewSpinningIdleStrategy (nil is ignored)
+//
+// Example:
+//
+//	rt := iris.NewRuntime(iris.WithRuntimeIdleStrategy(func() iris.IdleStrategy {
+//	    return iris.NewSleepingIdleStrategy(time.Millisecond, 100)
+//	}))
+func WithRuntimeIdleStrategy(newStrategy func() IdleStrategy) RuntimeOption {
+	return func(c *runtimeConfig) {
+		if newStrategy != nil {
+			c.newIdle = newStrategy
+		}
+	}
+}
+
+// NewRuntime starts a pool of consumer goroutines for loggers created with
+// WithRuntime.
+//
+// Parameters:
+//   - opts: WithRuntimeWorkers, WithRuntimeIdleStrategy
+//
+// Returns:
+//   - *Runtime: Started runtime; call Close when done
+//
+// Example:
+//
+//	rt := iris.NewRuntime(iris.WithRuntimeWorkers(2))
+//	defer rt.Close()
+//	db, _ := iris.New(iris.Config{Name: "db"}, iris.WithRuntime(rt))
+//	api, _ := iris.New(iris.Config{Name: "api"}, iris.WithRuntime(rt))
+func NewRuntime(opts ...RuntimeOption) *Runtime {
+	cfg := runtimeConfig{workers: 1, newIdle: NewProgressiveIdleStrategy}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	rt := &Runtime{
+		workers: make([]*runtimeWorker, cfg.workers),
+		loggers: make(map[*Ring]*Logger),
+		stop:    make(chan struct{}),
+	}
+	for i := range rt.workers {
+		w := &runtimeWorker{wake: make(chan struct{}, 1)}
+		w.rings.Store(&[]*Ring{})
+		rt.workers[i] = w
+		idle := cfg.newIdle()
+		if idle == nil {
+			idle = NewProgressiveIdleStrategy()
+		}
+		rt.wg.Add(1)
+		go rt.run(w, idle)
+	}
+	return rt
+}
+
+// WithRuntime makes the logger's records consumed by rt instead of a
+// goroutine of its own. Start attaches the logger to rt; Close drains it
+// as usual and detaches it. A logger started after rt was closed falls
+// back to its own consumer. Ignored with Config.Inline.
+//
+// Parameters:
+//   - rt: Runtime to attach to (nil is a no-op)
+//
+// Returns:
+//   - Option: Configuration function to share rt's consumers
+func WithRuntime(rt *Runtime) Option {
+	return func(o *loggerOptions) {
+		o.runtime = rt
+	}
+}
+
+// attach assigns l's ring to the least loaded worker. It reports false
+// when the runtime is closed.
+func (rt *Runtime) attach(l *Logger) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.closed {
+		return false
+	}
+	w := rt.workers[0]
+	for _, other := range rt.workers[1:] {
+		if len(*other.rings.Load()) < len(*w.rings.Load()) {
+			w = other
+		}
+	}
+	rings := append(append([]*Ring(nil), *w.rings.Load()...), l.r)
+	w.rings.Store(&rings)
+	rt.loggers[l.r] = l
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// detach removes a drained ring from w.
+func (rt *Runtime) detach(w *runtimeWorker, r *Ring) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	old := *w.rings.Load()
+	rings := make([]*Ring, 0, len(old)-1)
+	for _, other := range old {
+		if other != r {
+			rings = append(rings, other)
+		}
+	}
+	w.rings.Store(&rings)
+	delete(rt.loggers, r)
+}
+
+// run is the loop of worker w: one batch from each of its rings per round,
+// idling when a round finds no records, and blocking while it has no rings.
+func (rt *Runtime) run(w *runtimeWorker, idle IdleStrategy) {
+	defer rt.wg.Done()
+	for {
+		rings := *w.rings.Load()
+		if len(rings) == 0 {
+			select {
+			case <-w.wake:
+				continue
+			case <-rt.stop:
+				return
+			}
+		}
+		processed := 0
+		for _, r := range rings {
+			n, closed := r.step()
+			if closed {
+				rt.detach(w, r)
+			}
+			processed += n
+		}
+		if processed > 0 {
+			idle.Reset()
+		} else {
+			idle.Idle()
+		}
+	}
+}
+
+// Loggers returns the number of loggers currently attached.
+func (rt *Runtime) Loggers() int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return len(rt.loggers)
+}
+
+// Close closes every attached logger, as Logger.Close does, then stops the
+// workers. It returns the first error from closing a logger, other than
+// ErrLoggerClosed for one closed concurrently. Later calls return nil.
+func (rt *Runtime) Close() error {
+	rt.mu.Lock()
+	if rt.closed {
+		rt.mu.Unlock()
+		return nil
+	}
+	rt.closed = true
+	loggers := make([]*Logger, 0, len(rt.loggers))
+	for _, l := range rt.loggers {
+		loggers = append(loggers, l)
+	}
+	rt.mu.Unlock()
+
+	var first error
+	for _, l := range loggers {
+		if err := l.Close(); err != nil && first == nil && !IsLoggerError(err, ErrLoggerClosed.Code) {
+			first = err
+		}
+	}
+	close(rt.stop)
+	rt.wg.Wait()
+	return first
+}
@@ -0,0 +1,151 @@
+// runtime_stats.go: Go runtime snapshot field for Iris logging library
+//
+// Attaching a compact runtime snapshot (goroutines, heap in use, last GC
+// pause) to severe records makes it possible to correlate failures with
+// resource pressure without a separate metrics query.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// runtimeStatsMaxAge bounds how often runtime.ReadMemStats is called.
+// Error storms reuse the same snapshot instead of stopping the world per record.
+const runtimeStatsMaxAge = 100 * time.Millisecond
+
+// RuntimeSnapshot is a compact view of Go runtime resource usage.
+type RuntimeSnapshot struct {
+	Goroutines  int           // Number of live goroutines
+	HeapInUse   uint64        // Bytes in in-use heap spans
+	LastGCPause time.Duration // Duration of the most recent GC stop-the-world pause
+	NumGC       uint32        // Number of completed GC cycles
+}
+
+// runtimeSample is a cached snapshot with its capture time.
+type runtimeSample struct {
+	snap       RuntimeSnapshot
+	capturedAt int64 // Unix nanoseconds
+}
+
+var (
+	// runtimeCache holds the most recent snapshot (nil until first use)
+	runtimeCache atomic.Pointer[runtimeSample]
+
+	// runtimeSampling ensures a single goroutine refreshes the snapshot at a time
+	runtimeSampling atomic.Bool
+)
+
+// RuntimeStats returns a field with key "runtime" containing a snapshot of
+// goroutine count, heap in use and the last GC pause.
+//
+// Snapshots are cached for 100ms, so calling RuntimeStats in a tight error
+// loop does not call runtime.ReadMemStats for every record.
+//
+// Output (JSON):
+//
+//	"runtime":{"goroutines":12,"heap_inuse":4325376,"gc_pause_ns":52300,"num_gc":7}
+//
+// Example:
+//
+//	logger.Error("request failed", iris.ErrorField(err), iris.RuntimeStats())
+func RuntimeStats() Field {
+	return Object("runtime", currentRuntimeSnapshot())
+}
+
+// WithRuntimeStats attaches RuntimeStats() to every record at or above min.
+//
+// Typical usage is iris.WithRuntimeStats(iris.Error): routine records stay
+// lean while failures carry the resource context they happened in.
+//
+// Parameters:
+//   - min: Minimum level that receives the runtime snapshot
+//
+// Returns:
+//   - Option: Configuration function to enable runtime snapshots
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{}, iris.WithRuntimeStats(iris.Error))
+func WithRuntimeStats(min Level) Option {
+	return func(o *loggerOptions) {
+		o.addProvider(fieldProvider{min: min, fn: RuntimeStats})
+	}
+}
+
+// String returns a compact human-readable representation used by the text
+// and console encoders.
+func (s RuntimeSnapshot) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("{goroutines:")
+	writeInt(&buf, int64(s.Goroutines))
+	buf.WriteString(" heap_inuse:")
+	writeUint(&buf, s.HeapInUse)
+	buf.WriteString(" gc_pause:")
+	buf.WriteString(s.LastGCPause.String())
+	buf.WriteString(" num_gc:")
+	writeUint(&buf, uint64(s.NumGC))
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// encodeJSON writes the snapshot as a JSON object.
+func (s RuntimeSnapshot) encodeJSON(buf *bytes.Buffer) {
+	buf.WriteString(`{"goroutines":`)
+	writeInt(buf, int64(s.Goroutines))
+	buf.WriteString(`,"heap_inuse":`)
+	writeUint(buf, s.HeapInUse)
+	buf.WriteString(`,"gc_pause_ns":`)
+	writeInt(buf, int64(s.LastGCPause))
+	buf.WriteString(`,"num_gc":`)
+	writeUint(buf, uint64(s.NumGC))
+	buf.WriteByte('}')
+}
+
+// currentRuntimeSnapshot returns a cached snapshot, refreshing it when older
+// than runtimeStatsMaxAge. Concurrent callers never wait for a refresh in
+// progress; they reuse the previous snapshot instead.
+func currentRuntimeSnapshot() RuntimeSnapshot {
+	sample := runtimeCache.Load()
+	if sample != nil && timecache.CachedTimeNano()-sample.capturedAt < int64(runtimeStatsMaxAge) {
+		return sample.snap
+	}
+	if !runtimeSampling.CompareAndSwap(false, true) {
+		if sample != nil {
+			return sample.snap
+		}
+		return readRuntimeSnapshot()
+	}
+	defer runtimeSampling.Store(false)
+
+	snap := readRuntimeSnapshot()
+	runtimeCache.Store(&runtimeSample{snap: snap, capturedAt: timecache.CachedTimeNano()})
+	return snap
+}
+
+// readRuntimeSnapshot captures the current runtime statistics.
+func readRuntimeSnapshot() RuntimeSnapshot {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	var lastPause time.Duration
+	if ms.NumGC > 0 {
+		// #nosec G115 - PauseNs values are bounded durations
+		lastPause = time.Duration(ms.PauseNs[(ms.NumGC+255)%256])
+	}
+	return RuntimeSnapshot{
+		Goroutines:  runtime.NumGoroutine(),
+		HeapInUse:   ms.HeapInuse,
+		LastGCPause: lastPause,
+		NumGC:       ms.NumGC,
+	}
+}
@@ -0,0 +1,104 @@
+// runtime_stats_test.go: Tests for the Go runtime snapshot field
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStats_Field(t *testing.T) {
+	field := RuntimeStats()
+	if field.Key() != "runtime" {
+		t.Fatalf("expected key runtime, got %q", field.Key())
+	}
+	snap, ok := field.Obj.(RuntimeSnapshot)
+	if !ok {
+		t.Fatalf("expected RuntimeSnapshot object, got %T", field.Obj)
+	}
+	if snap.Goroutines <= 0 || snap.HeapInUse == 0 {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+}
+
+func TestRuntimeStats_Cached(t *testing.T) {
+	runtimeCache.Store(nil)
+	first := currentRuntimeSnapshot()
+	cached := runtimeCache.Load()
+	if cached == nil {
+		t.Fatal("expected snapshot to be cached")
+	}
+	if second := currentRuntimeSnapshot(); second != first {
+		t.Errorf("expected cached snapshot within max age, got %+v vs %+v", second, first)
+	}
+}
+
+func TestRuntimeStats_JSONEncoding(t *testing.T) {
+	snap := RuntimeSnapshot{Goroutines: 3, HeapInUse: 1024, LastGCPause: 1500 * time.Nanosecond, NumGC: 2}
+	rec := NewRecord(Error, "boom")
+	rec.AddField(Object("runtime", snap))
+
+	var buf bytes.Buffer
+	NewJSONEncoder().Encode(rec, time.Unix(0, 0), &buf)
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	obj, ok := decoded["runtime"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected runtime object, got %v", decoded["runtime"])
+	}
+	if obj["goroutines"] != float64(3) || obj["heap_inuse"] != float64(1024) ||
+		obj["gc_pause_ns"] != float64(1500) || obj["num_gc"] != float64(2) {
+		t.Errorf("unexpected runtime object: %v", obj)
+	}
+}
+
+func TestRuntimeStats_TextEncoding(t *testing.T) {
+	snap := RuntimeSnapshot{Goroutines: 3, HeapInUse: 1024, LastGCPause: time.Millisecond, NumGC: 2}
+	rec := NewRecord(Error, "boom")
+	rec.AddField(Object("runtime", snap))
+
+	var buf bytes.Buffer
+	NewConsoleEncoder().Encode(rec, time.Unix(0, 0), &buf)
+	if !strings.Contains(buf.String(), "goroutines:3") {
+		t.Errorf("expected runtime snapshot in console output, got %q", buf.String())
+	}
+}
+
+func TestWithRuntimeStats_MinLevel(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:   Debug,
+		Output:  buf,
+		Encoder: NewJSONEncoder(),
+	}, WithRuntimeStats(Error))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("routine")
+	logger.Error("failure")
+	_ = logger.Sync()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if strings.Contains(lines[0], `"runtime"`) {
+		t.Errorf("Info record should not carry runtime stats: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"runtime":{"goroutines":`) {
+		t.Errorf("Error record should carry runtime stats: %s", lines[1])
+	}
+}
@@ -0,0 +1,176 @@
+// runtime_test.go: Tests for loggers sharing consumer goroutines
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// checkOrdered verifies that out holds n records numbered 0..n-1 in order.
+func checkOrdered(t *testing.T, name, out string, n int) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != n {
+		t.Fatalf("%s: %d records, want %d", name, len(lines), n)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf(`"i":%d}`, i)) {
+			t.Fatalf("%s: record %d is %q", name, i, line)
+		}
+	}
+}
+
+func TestRuntime_SharedConsumers(t *testing.T) {
+	const loggers, records = 20, 500
+	before := runtime.NumGoroutine()
+	rt := NewRuntime(WithRuntimeWorkers(2))
+
+	outs := make([]*testSyncer, loggers)
+	all := make([]*Logger, loggers)
+	for i := range all {
+		outs[i] = &testSyncer{}
+		l, err := New(Config{Level: Info, Output: outs[i], Capacity: 1024, BatchSize: 64,
+			BackpressurePolicy: zephyroslite.BlockOnFull, Name: fmt.Sprint("component-", i)}, WithRuntime(rt))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		all[i] = l
+	}
+	if extra := runtime.NumGoroutine() - before; extra > 2 {
+		t.Errorf("%d loggers on a 2-worker runtime started %d goroutines, want 2", loggers, extra)
+	}
+	if rt.Loggers() != loggers {
+		t.Errorf("Loggers() = %d, want %d", rt.Loggers(), loggers)
+	}
+
+	var wg sync.WaitGroup
+	for _, l := range all {
+		wg.Add(1)
+		go func(l *Logger) {
+			defer wg.Done()
+			for i := 0; i < records; i++ {
+				l.Info("shared", Int("i", i))
+			}
+		}(l)
+	}
+	wg.Wait()
+	if err := rt.Close(); err != nil {
+		t.Fatalf("Runtime.Close failed: %v", err)
+	}
+
+	for i, out := range outs {
+		checkOrdered(t, all[i].name, out.String(), records)
+		if all[i].State() != StateClosed {
+			t.Errorf("logger %d is %s after Runtime.Close, want closed", i, all[i].State())
+		}
+	}
+	if rt.Loggers() != 0 {
+		t.Errorf("Loggers() = %d after Close, want 0", rt.Loggers())
+	}
+}
+
+func TestRuntime_LoggerLifecycle(t *testing.T) {
+	rt := NewRuntime()
+	defer rt.Close()
+
+	first, second := &testSyncer{}, &testSyncer{}
+	a, err := New(Config{Level: Info, Output: first, Capacity: 64}, WithRuntime(rt))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	b, err := New(Config{Level: Info, Output: second, Capacity: 64, AutoStart: AutoStartOff}, WithRuntime(rt))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if rt.Loggers() != 1 {
+		t.Fatalf("Loggers() = %d before Start, want 1", rt.Loggers())
+	}
+	b.Start()
+
+	a.Info("from a", Int("i", 0))
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	checkOrdered(t, "a", first.String(), 1)
+	for deadline := time.Now().Add(time.Second); rt.Loggers() != 1 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if rt.Loggers() != 1 {
+		t.Errorf("Loggers() = %d after closing one of two, want 1", rt.Loggers())
+	}
+
+	// The remaining logger is still consumed
+	b.Info("from b", Int("i", 0))
+	if err := b.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkOrdered(t, "b", second.String(), 1)
+}
+
+func TestRuntime_IdleStrategyPerWorker(t *testing.T) {
+	var mu sync.Mutex
+	var strategies []IdleStrategy
+	rt := NewRuntime(WithRuntimeWorkers(3), WithRuntimeIdleStrategy(func() IdleStrategy {
+		s := NewSleepingIdleStrategy(time.Millisecond, 10)
+		mu.Lock()
+		strategies = append(strategies, s)
+		mu.Unlock()
+		return s
+	}))
+	defer rt.Close()
+
+	mu.Lock()
+	if len(strategies) != 3 {
+		t.Fatalf("the factory built %d strategies for 3 workers, want 3", len(strategies))
+	}
+	for i := range strategies {
+		for j := i + 1; j < len(strategies); j++ {
+			if strategies[i] == strategies[j] {
+				t.Errorf("workers %d and %d share an idle strategy", i, j)
+			}
+		}
+	}
+	mu.Unlock()
+
+	out := &testSyncer{}
+	l, err := New(Config{Level: Info, Output: out, Capacity: 64}, WithRuntime(rt))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	l.Info("idle", Int("i", 0))
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkOrdered(t, "idle", out.String(), 1)
+}
+
+func TestRuntime_StartAfterClose(t *testing.T) {
+	rt := NewRuntime()
+	if err := rt.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	out := &testSyncer{}
+	l, err := New(Config{Level: Info, Output: out, Capacity: 64}, WithRuntime(rt))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	l.Info("own consumer", Int("i", 0))
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	checkOrdered(t, "fallback", out.String(), 1)
+	if rt.Close() != nil {
+		t.Error("second Runtime.Close returned an error")
+	}
+}
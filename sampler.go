@@ -7,6 +7,7 @@
 package iris
 
 import (
+	"math"
 	"sync/atomic"
 	"time"
 
@@ -106,3 +107,68 @@ func (s *TokenBucketSampler) Allow(_ Level) bool {
 		}
 	}
 }
+
+// DynamicSampler keeps a configurable fraction of log entries and allows the
+// fraction to be changed at runtime without recreating the logger.
+//
+// The rate is the fraction of entries kept: 1.0 keeps everything, 0.1 keeps
+// one entry in ten, 0 drops everything (except records flagged with
+// NoSample()). Selection is deterministic and evenly spaced rather than
+// random, so a rate of 0.25 keeps exactly every fourth entry.
+//
+// The rate can be driven by any control plane: call SetRate from an admin
+// endpoint or a feature-flag callback, or attach the sampler to a
+// DynamicConfigWatcher so the "sample_rate" key of the watched config file
+// is applied on every reload.
+//
+// Example:
+//
+//	sampler := iris.NewDynamicSampler(1.0)
+//	logger, _ := iris.New(iris.Config{Sampler: sampler})
+//	// During an incident:
+//	sampler.SetRate(0.05)
+type DynamicSampler struct {
+	rate    atomic.Uint64 // math.Float64bits of the current rate
+	counter atomic.Uint64 // Entries seen since the last rate change
+}
+
+// NewDynamicSampler creates a sampler keeping the given fraction of entries.
+// The rate is clamped to [0, 1].
+func NewDynamicSampler(rate float64) *DynamicSampler {
+	s := &DynamicSampler{}
+	s.SetRate(rate)
+	return s
+}
+
+// SetRate atomically updates the fraction of entries kept.
+// The rate is clamped to [0, 1]; NaN is treated as 1 (keep everything).
+func (s *DynamicSampler) SetRate(rate float64) {
+	switch {
+	case math.IsNaN(rate) || rate > 1:
+		rate = 1
+	case rate < 0:
+		rate = 0
+	}
+	s.rate.Store(math.Float64bits(rate))
+	s.counter.Store(0)
+}
+
+// Rate returns the current fraction of entries kept.
+func (s *DynamicSampler) Rate() float64 {
+	return math.Float64frombits(s.rate.Load())
+}
+
+// Allow implements the Sampler interface.
+// An entry is kept whenever n*rate crosses an integer boundary, which spaces
+// kept entries evenly and needs a single atomic increment per call.
+func (s *DynamicSampler) Allow(_ Level) bool {
+	rate := s.Rate()
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	n := s.counter.Add(1)
+	return math.Floor(float64(n)*rate) != math.Floor(float64(n-1)*rate)
+}
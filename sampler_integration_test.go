@@ -80,8 +80,9 @@ func TestSamplerIntegrationWithLogger(t *testing.T) {
 func TestSamplerRefillIntegration(t *testing.T) {
 	buf := &bufferedSyncer{}
 
-	// Create a sampler with capacity 2 and fast refill for testing
-	sampler := NewTokenBucketSampler(2, 1, 50*time.Millisecond)
+	// Create a sampler with capacity 1 and fast refill for testing: the
+	// second message (~50ms) is blocked, the third (~150ms) passes
+	sampler := NewTokenBucketSampler(1, 1, 100*time.Millisecond)
 
 	logger, err := New(Config{
 		Output:   buf,
@@ -339,3 +340,65 @@ func TestClonedLoggerInheritsSampler(t *testing.T) {
 		}
 	}
 }
+
+// TestSamplerNoSampleExemption tests that NoSample() records bypass sampler suppression
+func TestSamplerNoSampleExemption(t *testing.T) {
+	buf := &bufferedSyncer{}
+
+	// Sampler with its only token already consumed suppresses everything
+	sampler := NewTokenBucketSampler(1, 1, time.Hour)
+	sampler.Allow(Info)
+
+	logger, err := New(Config{
+		Output:   buf,
+		Level:    Info,
+		Encoder:  NewJSONEncoder(),
+		Sampler:  sampler,
+		Capacity: 1024,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer safeCloseIrisLogger(t, logger)
+
+	logger.Start()
+
+	logger.Info("sampled out")                                    // Suppressed
+	logger.Info("payment failed", Str("order", "A1"), NoSample()) // Exempt
+	logger.With(NoSample()).Info("exempt logger")                 // Exempt via base fields
+	logger.Debug("below level", NoSample())                       // Level filter still applies
+	_ = logger.Sync()
+
+	output := buf.String()
+	if strings.Contains(output, "sampled out") {
+		t.Error("unflagged record should be sampled out")
+	}
+	if !strings.Contains(output, "payment failed") || !strings.Contains(output, "exempt logger") {
+		t.Errorf("NoSample records should bypass the sampler, got %q", output)
+	}
+	if strings.Contains(output, "below level") {
+		t.Error("NoSample must not bypass level filtering")
+	}
+
+	// The marker itself is never encoded
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		if _, exists := entry[""]; exists {
+			t.Errorf("NoSample marker leaked into output: %s", line)
+		}
+	}
+}
+
+// TestRecordAddField_IgnoresNoSample tests that NoSample markers are not stored in records
+func TestRecordAddField_IgnoresNoSample(t *testing.T) {
+	rec := NewRecord(Info, "msg")
+	if !rec.AddField(NoSample()) {
+		t.Error("AddField should accept the marker")
+	}
+	if rec.FieldCount() != 0 {
+		t.Errorf("marker should not be stored, got %d fields", rec.FieldCount())
+	}
+}
@@ -0,0 +1,166 @@
+// sampler_key.go: Per-key rate limiting for multi-tenant logging
+//
+// A single token bucket protects the log pipeline as a whole, but it lets
+// one noisy tenant (or endpoint, or client) spend the entire budget and
+// silence everybody else. KeySampler keeps one token bucket per value of a
+// configurable field, so each tenant gets its own burst and sustained rate.
+// Buckets live in an LRU bounded by MaxKeys, so memory stays bounded no
+// matter how many distinct values the field takes.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeySamplerConfig configures a KeySampler.
+type KeySamplerConfig struct {
+	// Key is the field whose value selects the bucket (e.g. "tenant_id").
+	// String, signed and unsigned integer fields are supported.
+	Key string
+
+	// Capacity, Refill and Every configure each key's token bucket, as in
+	// NewTokenBucketSampler
+	Capacity int64
+	Refill   int64
+	Every    time.Duration
+
+	// MaxKeys bounds the number of buckets kept; the least recently used
+	// key is evicted beyond it and starts with a full bucket when it comes
+	// back (default 10000)
+	MaxKeys int
+
+	// Default samples records without the key field (nil: always allowed)
+	Default Sampler
+}
+
+// sampleKey identifies a bucket without formatting the field value.
+type sampleKey struct {
+	t kind
+	s string
+	n uint64
+}
+
+// keyBucket is one LRU entry.
+type keyBucket struct {
+	key    sampleKey
+	bucket *TokenBucketSampler
+}
+
+// KeySampler rate limits records per value of a field.
+//
+// Example:
+//
+//	sampler := iris.NewKeySampler(iris.KeySamplerConfig{
+//	    Key:      "tenant_id",
+//	    Capacity: 100, Refill: 10, Every: time.Second, // Per tenant
+//	})
+//	logger, _ := iris.New(iris.Config{Sampler: sampler})
+//	logger.With(iris.Str("tenant_id", tenant)).Info("request served")
+type KeySampler struct {
+	cfg KeySamplerConfig
+
+	mu      sync.Mutex
+	lru     *list.List // Front: most recently used *keyBucket
+	buckets map[sampleKey]*list.Element
+
+	evicted atomic.Int64
+}
+
+// NewKeySampler creates a per-key sampler. Invalid bucket parameters get
+// the same defaults as NewTokenBucketSampler.
+func NewKeySampler(cfg KeySamplerConfig) *KeySampler {
+	if cfg.MaxKeys <= 0 {
+		cfg.MaxKeys = 10000
+	}
+	return &KeySampler{
+		cfg:     cfg,
+		lru:     list.New(),
+		buckets: make(map[sampleKey]*list.Element),
+	}
+}
+
+// Allow implements Sampler for callers without fields: the record is
+// treated as having no key.
+func (s *KeySampler) Allow(level Level) bool {
+	if s.cfg.Default == nil {
+		return true
+	}
+	return s.cfg.Default.Allow(level)
+}
+
+// AllowFields reports whether a record with the given fields should be
+// logged. base holds the fields attached with With, fields the call-site
+// fields; call-site fields take precedence over base fields with the same
+// key. The logger calls it instead of Allow and counts rejections as
+// DropBudget.
+func (s *KeySampler) AllowFields(level Level, base, fields []Field) bool {
+	f := findField(fields, s.cfg.Key)
+	if f == nil {
+		f = findField(base, s.cfg.Key)
+	}
+	var key sampleKey
+	switch {
+	case f == nil:
+		return s.Allow(level)
+	case f.T == kindString:
+		key = sampleKey{t: kindString, s: f.Str}
+	case f.T == kindInt64:
+		key = sampleKey{t: kindInt64, n: uint64(f.I64)}
+	case f.T == kindUint64:
+		key = sampleKey{t: kindUint64, n: f.U64}
+	default:
+		return s.Allow(level) // Unsupported value type: treated as no key
+	}
+	return s.bucket(key).Allow(level)
+}
+
+// bucket returns the bucket of key, creating it (and evicting the least
+// recently used one) if needed.
+func (s *KeySampler) bucket(key sampleKey) *TokenBucketSampler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.buckets[key]; ok {
+		s.lru.MoveToFront(e)
+		return e.Value.(*keyBucket).bucket
+	}
+	if s.lru.Len() >= s.cfg.MaxKeys {
+		oldest := s.lru.Back()
+		delete(s.buckets, oldest.Value.(*keyBucket).key)
+		s.lru.Remove(oldest)
+		s.evicted.Add(1)
+	}
+	b := &keyBucket{key: key, bucket: NewTokenBucketSampler(s.cfg.Capacity, s.cfg.Refill, s.cfg.Every)}
+	s.buckets[key] = s.lru.PushFront(b)
+	return b.bucket
+}
+
+// Keys returns the number of keys currently tracked.
+func (s *KeySampler) Keys() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Evicted returns the number of keys evicted from the LRU so far.
+func (s *KeySampler) Evicted() int64 {
+	return s.evicted.Load()
+}
+
+// findField returns the last field with key k, or nil.
+func findField(fields []Field, k string) *Field {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].K == k {
+			return &fields[i]
+		}
+	}
+	return nil
+}
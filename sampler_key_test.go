@@ -0,0 +1,100 @@
+// sampler_key_test.go: Tests for per-key sampling
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeySampler_PerKeyBudgets(t *testing.T) {
+	s := NewKeySampler(KeySamplerConfig{Key: "tenant", Capacity: 2, Refill: 1, Every: time.Hour})
+
+	tests := []struct {
+		name   string
+		base   []Field
+		fields []Field
+		want   bool
+	}{
+		{"a 1", nil, []Field{Str("tenant", "a")}, true},
+		{"a 2", nil, []Field{Str("tenant", "a")}, true},
+		{"a exhausted", nil, []Field{Str("tenant", "a")}, false},
+		{"b unaffected", []Field{Str("tenant", "b")}, nil, true},
+		{"call site overrides base", []Field{Str("tenant", "a")}, []Field{Str("tenant", "c")}, true},
+		{"int key", nil, []Field{Int("tenant", 7)}, true},
+		{"uint key distinct from int", nil, []Field{Uint64("tenant", 7)}, true},
+		{"no key", nil, []Field{Str("other", "a")}, true},
+		{"unsupported type", nil, []Field{Float64("tenant", 1)}, true},
+	}
+	for _, tt := range tests {
+		if got := s.AllowFields(Info, tt.base, tt.fields); got != tt.want {
+			t.Errorf("%s: AllowFields() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := s.Keys(); got != 5 {
+		t.Errorf("Keys() = %d, want 5", got)
+	}
+}
+
+func TestKeySampler_LRUEviction(t *testing.T) {
+	s := NewKeySampler(KeySamplerConfig{Key: "k", Capacity: 1, Refill: 1, Every: time.Hour, MaxKeys: 2})
+
+	s.AllowFields(Info, nil, []Field{Str("k", "a")})
+	s.AllowFields(Info, nil, []Field{Str("k", "b")})
+	if s.AllowFields(Info, nil, []Field{Str("k", "a")}) {
+		t.Fatal("key a should be exhausted") // Also makes a the most recent
+	}
+	s.AllowFields(Info, nil, []Field{Str("k", "c")}) // Evicts b
+
+	if s.Keys() != 2 || s.Evicted() != 1 {
+		t.Errorf("Keys() = %d, Evicted() = %d, want 2 and 1", s.Keys(), s.Evicted())
+	}
+	if s.AllowFields(Info, nil, []Field{Str("k", "a")}) {
+		t.Error("key a should still be tracked and exhausted")
+	}
+	if !s.AllowFields(Info, nil, []Field{Str("k", "b")}) {
+		t.Error("evicted key b should start with a full bucket")
+	}
+}
+
+func TestKeySampler_DefaultSampler(t *testing.T) {
+	s := NewKeySampler(KeySamplerConfig{Key: "k", Default: NewDynamicSampler(0)})
+	if s.Allow(Info) || s.AllowFields(Info, nil, nil) {
+		t.Error("records without the key should use the default sampler")
+	}
+}
+
+func TestLogger_KeySamplerIsolatesTenants(t *testing.T) {
+	out := &testSyncer{}
+	sampler := NewKeySampler(KeySamplerConfig{Key: "tenant_id", Capacity: 3, Refill: 1, Every: time.Hour})
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024, Sampler: sampler})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	noisy := logger.With(Str("tenant_id", "noisy"))
+	for i := 0; i < 100; i++ {
+		noisy.Info("spam")
+	}
+	logger.Info("quiet", Str("tenant_id", "quiet"))
+	noisy.Info("must log", NoSample())
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if got := strings.Count(out.String(), "spam"); got != 3 {
+		t.Errorf("noisy tenant logged %d records, want 3", got)
+	}
+	if !strings.Contains(out.String(), "quiet") || !strings.Contains(out.String(), "must log") {
+		t.Errorf("other tenants or NoSample records were limited: %q", out.String())
+	}
+	if got := logger.DroppedBy(DropBudget); got != 97 {
+		t.Errorf("DroppedBy(DropBudget) = %d, want 97", got)
+	}
+}
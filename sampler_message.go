@@ -0,0 +1,177 @@
+// sampler_message.go: First-N-then-every-Mth sampling per message
+//
+// A token bucket limits the volume of the whole pipeline, but when one
+// error starts repeating thousands of times per second it spends the
+// budget on copies of itself and crowds out everything else. DedupSampler
+// counts records per (level, message) and per tick: the first records of
+// each tick are kept, then only every Mth. A flood of identical records
+// is thinned down while a rare message is always logged.
+//
+// Counters live in a fixed table indexed by a hash of the message, as in
+// zap's sampler: memory is bounded and no lock is taken, at the price of
+// messages that collide sharing a counter.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// Defaults of DedupSamplerConfig.
+const (
+	DefaultDedupTick    = time.Second
+	DefaultDedupBuckets = 1024
+)
+
+// dedupLevels is the number of levels with their own counters, Trace to
+// Fatal; levels outside that range share the counters of the nearest one.
+const dedupLevels = int(Fatal-Trace) + 1
+
+// MessageSampler is a Sampler that also sees the message of the record.
+// The logger calls AllowMessage instead of Allow; for Printf-style calls
+// (Infof and the like) the message is the format string, so that records
+// differing only by their arguments are counted together.
+type MessageSampler interface {
+	Sampler
+
+	// AllowMessage reports whether a record with the given level and
+	// message should be logged.
+	AllowMessage(level Level, msg string) bool
+}
+
+// DedupSamplerConfig configures a DedupSampler.
+type DedupSamplerConfig struct {
+	// Tick is the period after which the counters start over
+	// (default DefaultDedupTick)
+	Tick time.Duration
+
+	// First is the number of records of each (level, message) kept per
+	// tick before sampling starts (default 1)
+	First uint64
+
+	// Thereafter keeps every Thereafter-th record of a (level, message)
+	// beyond First within the tick; 0 drops them all
+	Thereafter uint64
+
+	// Buckets is the number of counters per level (default
+	// DefaultDedupBuckets). Messages whose hashes collide share a counter,
+	// so it should comfortably exceed the number of distinct messages
+	// logged in a tick.
+	Buckets int
+}
+
+// dedupCounter counts the records of one bucket within the current tick.
+type dedupCounter struct {
+	resetAt atomic.Int64  // End of the current tick (Unix ns)
+	n       atomic.Uint64 // Records seen in the current tick
+}
+
+// DedupSampler keeps the first records of each (level, message) in every
+// tick, then one in Thereafter.
+//
+// Example:
+//
+//	sampler := iris.NewDedupSampler(iris.DedupSamplerConfig{
+//	    Tick: time.Second, First: 10, Thereafter: 100,
+//	})
+//	logger, _ := iris.New(iris.Config{Sampler: sampler})
+//	// 10 "upstream timeout" records per second, then one in a hundred
+type DedupSampler struct {
+	cfg      DedupSamplerConfig
+	counters []dedupCounter // dedupLevels * cfg.Buckets
+	now      func() int64   // Unix ns (timecache.CachedTimeNano)
+
+	kept    atomic.Int64
+	sampled atomic.Int64
+}
+
+// NewDedupSampler creates a per-message sampler. Zero fields of cfg get
+// their defaults.
+func NewDedupSampler(cfg DedupSamplerConfig) *DedupSampler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = DefaultDedupTick
+	}
+	if cfg.First == 0 {
+		cfg.First = 1
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = DefaultDedupBuckets
+	}
+	return &DedupSampler{
+		cfg:      cfg,
+		counters: make([]dedupCounter, dedupLevels*cfg.Buckets),
+		now:      timecache.CachedTimeNano,
+	}
+}
+
+// Allow implements Sampler for callers without a message: the record is
+// counted with the other records of its level that have an empty message.
+func (s *DedupSampler) Allow(level Level) bool {
+	return s.AllowMessage(level, "")
+}
+
+// AllowMessage implements MessageSampler.
+func (s *DedupSampler) AllowMessage(level Level, msg string) bool {
+	n := s.counter(level, msg).inc(s.now(), s.cfg.Tick)
+	if n <= s.cfg.First || (s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0) {
+		s.kept.Add(1)
+		return true
+	}
+	s.sampled.Add(1)
+	return false
+}
+
+// Kept returns the number of records the sampler has let through.
+func (s *DedupSampler) Kept() int64 {
+	return s.kept.Load()
+}
+
+// Sampled returns the number of records the sampler has rejected.
+func (s *DedupSampler) Sampled() int64 {
+	return s.sampled.Load()
+}
+
+// addStats adds the sampler counters to the statistics of a logger.
+func (s *DedupSampler) addStats(stats map[string]int64) {
+	stats["dedup_kept"] = s.kept.Load()
+	stats["dedup_sampled"] = s.sampled.Load()
+}
+
+// counter returns the counter of (level, msg).
+func (s *DedupSampler) counter(level Level, msg string) *dedupCounter {
+	i := int(level - Trace)
+	switch {
+	case i < 0:
+		i = 0
+	case i >= dedupLevels:
+		i = dedupLevels - 1
+	}
+	// FNV-1a, inlined to avoid the allocation of hash/fnv
+	h := uint32(2166136261)
+	for j := 0; j < len(msg); j++ {
+		h ^= uint32(msg[j])
+		h *= 16777619
+	}
+	return &s.counters[i*s.cfg.Buckets+int(h%uint32(s.cfg.Buckets))]
+}
+
+// inc counts a record seen at now and returns its rank within the tick,
+// starting a new tick if the current one has ended.
+func (c *dedupCounter) inc(now int64, tick time.Duration) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.n.Add(1)
+	}
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+int64(tick)) {
+		return c.n.Add(1) // Another goroutine started the tick
+	}
+	return 1
+}
@@ -0,0 +1,98 @@
+// sampler_message_test.go: Tests for per-message sampling
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupSampler_FirstThenEvery(t *testing.T) {
+	s := NewDedupSampler(DedupSamplerConfig{Tick: time.Second, First: 2, Thereafter: 3})
+	var now int64
+	s.now = func() int64 { return now }
+
+	tests := []struct {
+		name  string
+		level Level
+		msg   string
+		want  bool
+	}{
+		{"first", Error, "timeout", true},
+		{"second", Error, "timeout", true},
+		{"third sampled", Error, "timeout", false},
+		{"fourth sampled", Error, "timeout", false},
+		{"fifth kept", Error, "timeout", true},
+		{"other message", Error, "refused", true},
+		{"other level", Warn, "timeout", true},
+		{"sixth sampled", Error, "timeout", false},
+	}
+	for _, tt := range tests {
+		if got := s.AllowMessage(tt.level, tt.msg); got != tt.want {
+			t.Errorf("%s: AllowMessage() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if s.Kept() != 5 || s.Sampled() != 3 {
+		t.Errorf("Kept() = %d, Sampled() = %d, want 5 and 3", s.Kept(), s.Sampled())
+	}
+
+	now += int64(time.Second) // New tick
+	if !s.AllowMessage(Error, "timeout") || !s.AllowMessage(Error, "timeout") || s.AllowMessage(Error, "timeout") {
+		t.Error("counters not reset after the tick")
+	}
+}
+
+func TestDedupSampler_ThereafterZero(t *testing.T) {
+	s := NewDedupSampler(DedupSamplerConfig{Tick: time.Hour})
+	if !s.Allow(Info) {
+		t.Fatal("first record rejected")
+	}
+	for i := 0; i < 10; i++ {
+		if s.Allow(Info) {
+			t.Fatal("record beyond First kept with Thereafter 0")
+		}
+	}
+	if !s.AllowMessage(Level(100), "out of range") || s.AllowMessage(Level(101), "out of range") {
+		t.Error("out of range levels should share the Fatal counters")
+	}
+}
+
+func TestDedupSampler_Logger(t *testing.T) {
+	out := &testSyncer{}
+	sampler := NewDedupSampler(DedupSamplerConfig{Tick: time.Hour, First: 1, Thereafter: 100})
+	logger, err := New(Config{Level: Debug, Output: out, Encoder: NewJSONEncoder(), Inline: true, Sampler: sampler})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	for i := 0; i < 20; i++ {
+		logger.Error("upstream timeout", Int("attempt", i))
+		logger.Infof("retry %d", i) // Counted by format
+	}
+	logger.Error("upstream timeout", NoSample())
+	logger.Warn("disk almost full")
+
+	if got := strings.Count(out.String(), "upstream timeout"); got != 2 {
+		t.Errorf("logged %d timeouts, want 2 (first and unsampled)", got)
+	}
+	if got := strings.Count(out.String(), "retry"); got != 1 {
+		t.Errorf("logged %d retries, want 1", got)
+	}
+	if !strings.Contains(out.String(), "disk almost full") {
+		t.Error("distinct message sampled away")
+	}
+
+	stats := logger.With(Str("k", "v")).Stats()
+	if stats["dedup_kept"] != 3 || stats["dedup_sampled"] != 39 {
+		t.Errorf("dedup_kept = %d, dedup_sampled = %d, want 3 and 39", stats["dedup_kept"], stats["dedup_sampled"])
+	}
+	if stats["dropped_sampled"] != 38 {
+		t.Errorf("dropped_sampled = %d, want 38", stats["dropped_sampled"])
+	}
+}
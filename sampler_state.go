@@ -0,0 +1,338 @@
+// sampler_state.go: Persistence of sampler budgets across restarts
+//
+// Token buckets start full, so a service that crash-loops gets a fresh
+// burst on every restart and floods the pipeline with exactly the records
+// the sampler was there to hold back. With WithSamplerState the budgets of
+// a TokenBucketSampler or KeySampler are restored from a small file when the
+// logger is created and saved to it periodically and on Close; the time the
+// service was down refills the buckets as it would have while running.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// DefaultSamplerStateInterval is the save interval of WithSamplerState when
+// SamplerStateConfig.Interval is zero.
+const DefaultSamplerStateInterval = 10 * time.Second
+
+// samplerStateVersion is the version of the state file format.
+const samplerStateVersion = 1
+
+// StatefulSampler is a Sampler whose budgets can be saved and restored.
+// TokenBucketSampler and KeySampler implement it.
+type StatefulSampler interface {
+	Sampler
+
+	// SamplerState returns a snapshot of the budgets.
+	SamplerState() SamplerState
+
+	// RestoreSamplerState replaces the budgets with the ones of st.
+	RestoreSamplerState(st SamplerState)
+}
+
+// SamplerState is a snapshot of the budgets of a sampler, as written to
+// the state file.
+type SamplerState struct {
+	Version int              `json:"version"`
+	Bucket  *BucketState     `json:"bucket,omitempty"` // TokenBucketSampler, or the KeySampler default
+	Keys    []KeyBucketState `json:"keys,omitempty"`   // KeySampler buckets, most recently used first
+}
+
+// BucketState is the budget of one token bucket.
+type BucketState struct {
+	Tokens int64     `json:"tokens"` // Tokens left
+	Last   time.Time `json:"last"`   // Last refill
+}
+
+// KeyBucketState is the budget of the bucket of one KeySampler key.
+type KeyBucketState struct {
+	Type string `json:"type"` // "string", "int" or "uint": type of the key field
+	Key  string `json:"key"`  // Field value, integers in decimal
+	BucketState
+}
+
+// SamplerStateConfig configures WithSamplerState.
+type SamplerStateConfig struct {
+	// Path is the state file. It is written with mode 0600, through a
+	// temporary file in the same directory renamed over it.
+	Path string
+
+	// Interval is how often the state is saved while the logger runs
+	// (default DefaultSamplerStateInterval). It is also saved by Close.
+	Interval time.Duration
+}
+
+// samplerStateSaver saves the sampler state of a logger, shared with its
+// clones.
+type samplerStateSaver struct {
+	cfg     SamplerStateConfig
+	sampler StatefulSampler
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// WithSamplerState persists the budgets of the logger's sampler in
+// cfg.Path, so that a restarting service resumes with the budgets it had
+// instead of full buckets. The state is restored by New, saved every
+// cfg.Interval from a goroutine started by Start, and saved a last time by
+// Close.
+//
+// Only a TokenBucketSampler or KeySampler (any StatefulSampler) passed in
+// Config.Sampler or with WithSampler is persisted; with another sampler the
+// option has no effect. A missing state file is not an error; a file that
+// cannot be read or written is reported to the error handler and the logger
+// keeps running with the budgets it has. Buckets are clamped to their
+// capacity, so a file written with a larger configuration is harmless.
+//
+// Parameters:
+//   - cfg: State file and save interval
+//
+// Returns:
+//   - Option: Configuration function to enable sampler state persistence
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{
+//		Sampler: iris.NewKeySampler(iris.KeySamplerConfig{Key: "tenant_id", Capacity: 100, Refill: 10, Every: time.Second}),
+//	}, iris.WithSamplerState(iris.SamplerStateConfig{Path: "/var/lib/api/sampler.json"}))
+func WithSamplerState(cfg SamplerStateConfig) Option {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultSamplerStateInterval
+	}
+	return func(o *loggerOptions) {
+		o.samplerState = &cfg
+	}
+}
+
+// newSamplerStateSaver restores the state of s from cfg.Path and returns
+// the saver (nil without WithSamplerState or with a stateless sampler).
+func newSamplerStateSaver(cfg *SamplerStateConfig, s Sampler) *samplerStateSaver {
+	stateful, ok := s.(StatefulSampler)
+	if cfg == nil || !ok {
+		return nil
+	}
+	st, err := LoadSamplerState(cfg.Path)
+	switch {
+	case err == nil:
+		stateful.RestoreSamplerState(st)
+	case !os.IsNotExist(err):
+		handleError(NewLoggerErrorWithField(ErrCodeFileOpen, "cannot restore sampler state", "path", cfg.Path).
+			WithSeverity("warning").
+			WithContext("error", err.Error()))
+	}
+	return &samplerStateSaver{
+		cfg:     *cfg,
+		sampler: stateful,
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// startSamplerState launches the goroutine saving the state once.
+func (l *Logger) startSamplerState() {
+	s := l.samplerState
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		go func() {
+			defer close(s.done)
+			ticker := time.NewTicker(s.cfg.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					s.save()
+				case <-s.quit:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// stopSamplerState stops the goroutine and saves the state a last time.
+func (l *Logger) stopSamplerState() {
+	s := l.samplerState
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() { close(s.done) }) // Never started
+	s.stopOnce.Do(func() {
+		close(s.quit)
+		<-s.done
+		s.save()
+	})
+}
+
+// save writes the state, reporting a failure to the error handler.
+func (s *samplerStateSaver) save() {
+	if err := SaveSamplerState(s.cfg.Path, s.sampler); err != nil {
+		handleError(NewLoggerErrorWithField(ErrCodeFileWrite, "cannot save sampler state", "path", s.cfg.Path).
+			WithSeverity("warning").
+			WithContext("error", err.Error()))
+	}
+}
+
+// SaveSamplerState writes the budgets of s to path, replacing the file
+// atomically so that a crash while saving leaves the previous state.
+//
+// Parameters:
+//   - path: State file, written with mode 0600
+//   - s: Sampler whose state is saved
+//
+// Returns:
+//   - error: Non-nil if the file could not be written
+func SaveSamplerState(path string, s StatefulSampler) error {
+	data, err := json.Marshal(s.SamplerState())
+	if err != nil {
+		return err
+	}
+	path = filepath.Clean(path)
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSamplerState reads a state written by SaveSamplerState, to be passed
+// to StatefulSampler.RestoreSamplerState.
+//
+// Parameters:
+//   - path: State file
+//
+// Returns:
+//   - SamplerState: The saved budgets
+//   - error: Non-nil if the file cannot be read (os.IsNotExist for a
+//     missing one) or is not a state file of a known version
+func LoadSamplerState(path string) (SamplerState, error) {
+	var st SamplerState
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return SamplerState{}, NewLoggerErrorWithField(ErrCodeInvalidFormat, "invalid sampler state: "+err.Error(), "path", path)
+	}
+	if st.Version != samplerStateVersion {
+		return SamplerState{}, NewLoggerErrorWithField(ErrCodeInvalidFormat,
+			"unsupported sampler state version "+strconv.Itoa(st.Version), "path", path)
+	}
+	return st, nil
+}
+
+// state returns the budget of the bucket.
+func (s *TokenBucketSampler) state() BucketState {
+	return BucketState{Tokens: s.tokens.Load(), Last: time.Unix(0, s.last.Load())}
+}
+
+// restore sets the budget of the bucket, clamped to its capacity. A last
+// refill in the future (clock changes) counts as now.
+func (s *TokenBucketSampler) restore(b BucketState) {
+	last := b.Last.UnixNano()
+	if now := timecache.CachedTimeNano(); b.Last.IsZero() || last > now {
+		last = now
+	}
+	s.tokens.Store(min(max(b.Tokens, 0), s.capacity))
+	s.last.Store(last)
+}
+
+// SamplerState implements StatefulSampler.
+func (s *TokenBucketSampler) SamplerState() SamplerState {
+	b := s.state()
+	return SamplerState{Version: samplerStateVersion, Bucket: &b}
+}
+
+// RestoreSamplerState implements StatefulSampler. A state without a
+// bucket leaves the sampler unchanged.
+func (s *TokenBucketSampler) RestoreSamplerState(st SamplerState) {
+	if st.Bucket != nil {
+		s.restore(*st.Bucket)
+	}
+}
+
+// SamplerState implements StatefulSampler. The default sampler is included
+// if it is a TokenBucketSampler.
+func (s *KeySampler) SamplerState() SamplerState {
+	st := SamplerState{Version: samplerStateVersion}
+	if def, ok := s.cfg.Default.(*TokenBucketSampler); ok {
+		b := def.state()
+		st.Bucket = &b
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.Keys = make([]KeyBucketState, 0, s.lru.Len())
+	for e := s.lru.Front(); e != nil; e = e.Next() {
+		kb := e.Value.(*keyBucket)
+		ks := KeyBucketState{BucketState: kb.bucket.state()}
+		switch kb.key.t {
+		case kindString:
+			ks.Type, ks.Key = "string", kb.key.s
+		case kindInt64:
+			ks.Type, ks.Key = "int", strconv.FormatInt(int64(kb.key.n), 10)
+		default:
+			ks.Type, ks.Key = "uint", strconv.FormatUint(kb.key.n, 10)
+		}
+		st.Keys = append(st.Keys, ks)
+	}
+	return st
+}
+
+// RestoreSamplerState implements StatefulSampler. Buckets are restored up
+// to MaxKeys, most recently used first; keys of an unknown type are
+// skipped, and keys not in st keep their current budget.
+func (s *KeySampler) RestoreSamplerState(st SamplerState) {
+	if def, ok := s.cfg.Default.(*TokenBucketSampler); ok && st.Bucket != nil {
+		def.restore(*st.Bucket)
+	}
+	// Oldest first, so that the LRU order of st is kept
+	for i := min(len(st.Keys), s.cfg.MaxKeys) - 1; i >= 0; i-- {
+		ks := st.Keys[i]
+		var key sampleKey
+		switch ks.Type {
+		case "string":
+			key = sampleKey{t: kindString, s: ks.Key}
+		case "int":
+			n, err := strconv.ParseInt(ks.Key, 10, 64)
+			if err != nil {
+				continue
+			}
+			key = sampleKey{t: kindInt64, n: uint64(n)}
+		case "uint":
+			n, err := strconv.ParseUint(ks.Key, 10, 64)
+			if err != nil {
+				continue
+			}
+			key = sampleKey{t: kindUint64, n: n}
+		default:
+			continue
+		}
+		s.bucket(key).restore(ks.BucketState)
+	}
+}
@@ -0,0 +1,159 @@
+// sampler_state_test.go: Tests for sampler budget persistence
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplerState_TokenBucket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sampler.json")
+	s := NewTokenBucketSampler(3, 1, time.Hour)
+	for i := 0; i < 3; i++ {
+		s.Allow(Info)
+	}
+	if err := SaveSamplerState(path, s); err != nil {
+		t.Fatalf("SaveSamplerState failed: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("state file %v, %v: want mode 0600", info, err)
+	}
+
+	st, err := LoadSamplerState(path)
+	if err != nil {
+		t.Fatalf("LoadSamplerState failed: %v", err)
+	}
+	restarted := NewTokenBucketSampler(3, 1, time.Hour)
+	restarted.RestoreSamplerState(st)
+	if restarted.Allow(Info) {
+		t.Error("restored bucket has tokens, want the exhausted budget")
+	}
+
+	tests := []struct {
+		name   string
+		bucket BucketState
+		want   int64
+	}{
+		{"ClampedToCapacity", BucketState{Tokens: 100, Last: time.Now()}, 3},
+		{"Negative", BucketState{Tokens: -5, Last: time.Now()}, 0},
+		{"FutureLast", BucketState{Tokens: 1, Last: time.Now().Add(time.Hour)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTokenBucketSampler(3, 1, time.Hour)
+			s.RestoreSamplerState(SamplerState{Version: samplerStateVersion, Bucket: &tt.bucket})
+			if got := s.tokens.Load(); got != tt.want {
+				t.Errorf("tokens = %d, want %d", got, tt.want)
+			}
+			if s.last.Load() > time.Now().UnixNano()+int64(time.Second) {
+				t.Error("last refill restored in the future")
+			}
+		})
+	}
+}
+
+func TestSamplerState_KeySampler(t *testing.T) {
+	cfg := KeySamplerConfig{Key: "tenant", Capacity: 1, Refill: 1, Every: time.Hour, Default: NewTokenBucketSampler(1, 1, time.Hour)}
+	s := NewKeySampler(cfg)
+	keys := [][]Field{{Str("tenant", "acme")}, {Int("tenant", -7)}, {Uint64("tenant", 7)}, nil}
+	for _, fields := range keys {
+		s.AllowFields(Info, nil, fields)
+	}
+
+	st := s.SamplerState()
+	if len(st.Keys) != 3 || st.Keys[0].Type != "uint" || st.Keys[1].Key != "-7" || st.Keys[2].Key != "acme" || st.Bucket == nil {
+		t.Fatalf("unexpected state %+v", st)
+	}
+
+	restarted := NewKeySampler(cfg)
+	restarted.RestoreSamplerState(st)
+	for _, fields := range keys {
+		if restarted.AllowFields(Info, nil, fields) {
+			t.Errorf("%v: restored budget not exhausted", fields)
+		}
+	}
+	if got := restarted.SamplerState(); got.Keys[0].Type != "uint" || got.Keys[2].Key != "acme" {
+		t.Errorf("LRU order not restored: %+v", got.Keys)
+	}
+
+	cfg.MaxKeys = 1
+	small := NewKeySampler(cfg)
+	small.RestoreSamplerState(SamplerState{Version: samplerStateVersion, Keys: append(st.Keys, KeyBucketState{Type: "float", Key: "1"})})
+	if small.Keys() != 1 || small.SamplerState().Keys[0].Type != "uint" {
+		t.Errorf("restored %+v, want only the most recent key", small.SamplerState().Keys)
+	}
+}
+
+func TestSamplerState_Logger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sampler.json")
+	run := func() (written int, stats map[string]int64) {
+		out := &testSyncer{}
+		logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, Sampler: NewTokenBucketSampler(2, 1, time.Hour)},
+			WithSamplerState(SamplerStateConfig{Path: path}))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			logger.Info("crash loop")
+		}
+		stats = logger.Stats()
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		return strings.Count(out.String(), "\n"), stats
+	}
+
+	if written, _ := run(); written != 2 {
+		t.Fatalf("first run wrote %d records, want the full burst of 2", written)
+	}
+	written, stats := run()
+	if written != 0 || stats["dropped_sampled"] != 2 {
+		t.Errorf("restart wrote %d records, dropped_sampled = %d: want the budget restored", written, stats["dropped_sampled"])
+	}
+}
+
+func TestSamplerState_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadSamplerState(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("missing file: got %v, want a not-exist error", err)
+	}
+
+	files := map[string]string{
+		"invalid.json": `{"version":`,
+		"version.json": `{"version":2,"bucket":{"tokens":1}}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSamplerState(path); !IsLoggerError(err, ErrCodeInvalidFormat) {
+			t.Errorf("%s: got %v, want ErrCodeInvalidFormat", name, err)
+		}
+	}
+
+	// A corrupt file is reported and the logger starts with full buckets
+	reported := captureErrors(t)
+	out := &testSyncer{}
+	logger, err := New(Config{Output: out, Encoder: NewJSONEncoder(), Inline: true, Sampler: NewTokenBucketSampler(1, 1, time.Hour)},
+		WithSamplerState(SamplerStateConfig{Path: filepath.Join(dir, "invalid.json")}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+	logger.Info("allowed")
+	if findLine(out.String(), "allowed") == "" {
+		t.Error("record dropped with a corrupt state file")
+	}
+	if errs := reported(); len(errs) != 1 || errs[0].Code != ErrCodeFileOpen {
+		t.Errorf("reported %v, want one ErrCodeFileOpen", errs)
+	}
+}
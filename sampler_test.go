@@ -384,3 +384,70 @@ func TestTokenBucketSamplerBurstThenSustained(t *testing.T) {
 		t.Errorf("Sustained phase: expected 1-%d allowed, got %d", refillRate, sustainedAllowed)
 	}
 }
+
+// TestDynamicSamplerRates tests the fraction of entries kept at various rates
+func TestDynamicSamplerRates(t *testing.T) {
+	tests := []struct {
+		rate     float64
+		expected int
+	}{
+		{1.0, 100},
+		{0.5, 50},
+		{0.25, 25},
+		{0.1, 10},
+		{0, 0},
+		{-1, 0},    // Clamped to 0
+		{2.5, 100}, // Clamped to 1
+	}
+
+	for _, tt := range tests {
+		sampler := NewDynamicSampler(tt.rate)
+		allowed := 0
+		for i := 0; i < 100; i++ {
+			if sampler.Allow(Info) {
+				allowed++
+			}
+		}
+		if allowed != tt.expected {
+			t.Errorf("rate %v: expected %d allowed, got %d", tt.rate, tt.expected, allowed)
+		}
+	}
+}
+
+// TestDynamicSamplerSetRate tests changing the rate at runtime
+func TestDynamicSamplerSetRate(t *testing.T) {
+	sampler := NewDynamicSampler(1.0)
+	var s Sampler = sampler // Must satisfy the Sampler interface
+	if !s.Allow(Debug) {
+		t.Fatal("rate 1.0 should allow everything")
+	}
+
+	sampler.SetRate(0)
+	if sampler.Rate() != 0 || sampler.Allow(Error) {
+		t.Error("rate 0 should drop everything")
+	}
+
+	sampler.SetRate(0.5)
+	if sampler.Rate() != 0.5 {
+		t.Errorf("expected rate 0.5, got %v", sampler.Rate())
+	}
+}
+
+// TestDynamicSamplerConcurrency tests concurrent Allow and SetRate calls
+func TestDynamicSamplerConcurrency(t *testing.T) {
+	sampler := NewDynamicSampler(0.5)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				sampler.Allow(Info)
+				if g == 0 && i%100 == 0 {
+					sampler.SetRate(float64(i%3) / 2)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
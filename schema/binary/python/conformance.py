@@ -0,0 +1,103 @@
+"""Checks iris_binary against the conformance corpus.
+
+Usage: python3 schema/binary/python/conformance.py [testdata/binary]
+
+Every valid/<case>.bin must decode to exactly valid/<case>.json in the
+canonical JSON of docs/BINARY_FORMAT.md, and every invalid/<case>.bin must
+be rejected. Exits non-zero on any failure.
+
+Copyright (c) 2025 AGILira
+SPDX-License-Identifier: MPL-2.0
+"""
+
+import os
+import struct
+import sys
+from typing import List
+
+sys.path.insert(0, os.path.dirname(os.path.abspath(__file__)))
+
+from iris_binary import DecodeError, Record, decode_stream  # noqa: E402
+
+
+def _str(s: str) -> bytes:
+    out = bytearray(b'"')
+    for c in s.encode("utf-8", errors="surrogateescape"):
+        if c in (0x22, 0x5C):
+            out += b"\\" + bytes([c])
+        elif c < 0x20:
+            out += b"\\u00%02x" % c
+        else:
+            out.append(c)
+    out += b'"'
+    return bytes(out)
+
+
+def canonical_json(records: List[Record]) -> bytes:
+    lines = []
+    for r in records:
+        if r.rfc3339 is not None:
+            ts = b'{"rfc3339":%s}' % _str(r.rfc3339)
+        else:
+            ts = b'{"unix_nano":%d}' % r.unix_nano
+        fields = []
+        for f in r.fields:
+            if f.type == "float64":
+                value = b'"0x%016x"' % struct.unpack("<Q", struct.pack("<d", f.value))[0]
+            elif f.type == "bool":
+                value = b"true" if f.value else b"false"
+            elif f.type == "bytes":
+                value = b'"%s"' % f.value.hex().encode()
+            elif isinstance(f.value, int):
+                value = b"%d" % f.value
+            else:
+                value = _str(f.value)
+            fields.append(b'{"type":"%s","key":%s,"value":%s}' % (f.type.encode(), _str(f.key), value))
+        lines.append(
+            b'{"ts":%s,"level":%d,"logger":%s,"msg":%s,"caller":%s,"stack":%s,"fields":[%s]}'
+            % (ts, r.level, _str(r.logger), _str(r.msg), _str(r.caller), _str(r.stack), b",".join(fields))
+        )
+    return b"[\n" + b",\n".join(lines) + b"\n]\n"
+
+
+def main() -> int:
+    corpus = sys.argv[1] if len(sys.argv) > 1 else "testdata/binary"
+    failures = 0
+
+    valid = os.path.join(corpus, "valid")
+    for name in sorted(os.listdir(valid)):
+        if not name.endswith(".bin"):
+            continue
+        with open(os.path.join(valid, name), "rb") as f:
+            data = f.read()
+        with open(os.path.join(valid, name[:-4] + ".json"), "rb") as f:
+            want = f.read()
+        try:
+            got = canonical_json(list(decode_stream(data)))
+        except DecodeError as e:
+            print("FAIL valid/%s: %s" % (name, e))
+            failures += 1
+            continue
+        if got != want:
+            print("FAIL valid/%s: canonical JSON differs\n%s" % (name, got.decode("utf-8", "replace")))
+            failures += 1
+        else:
+            print("ok   valid/%s" % name)
+
+    invalid = os.path.join(corpus, "invalid")
+    for name in sorted(os.listdir(invalid)):
+        with open(os.path.join(invalid, name), "rb") as f:
+            data = f.read()
+        try:
+            list(decode_stream(data))
+        except DecodeError:
+            print("ok   invalid/%s" % name)
+            continue
+        print("FAIL invalid/%s: decoded without error" % name)
+        failures += 1
+
+    return 1 if failures else 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
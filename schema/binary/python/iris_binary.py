@@ -0,0 +1,158 @@
+"""Decoder for the Iris binary log format.
+
+Standard library only. The format is specified in docs/BINARY_FORMAT.md;
+this module is checked against testdata/binary by conformance.py.
+
+Example:
+
+    from iris_binary import decode_stream
+
+    with open("app.irisbin", "rb") as f:
+        for record in decode_stream(f.read()):
+            print(record.level, record.msg, record.fields)
+
+Copyright (c) 2025 AGILira
+SPDX-License-Identifier: MPL-2.0
+"""
+
+import struct
+from dataclasses import dataclass, field
+from typing import Iterator, List, Optional, Tuple, Union
+
+MAGIC = b"\x52\x49"
+VERSION = 0x01
+
+FIELD_TYPES = {
+    0x01: "string",
+    0x02: "int64",
+    0x03: "uint64",
+    0x04: "float64",
+    0x05: "bool",
+    0x06: "duration",
+    0x07: "time",
+    0x08: "bytes",
+    0x09: "error",
+    0x0A: "stringer",
+    0x0B: "object",
+    0x0C: "secret",
+}
+
+LEVELS = {-2: "trace", -1: "debug", 0: "info", 1: "warn", 2: "error", 3: "dpanic", 4: "panic", 5: "fatal"}
+
+Value = Union[str, int, float, bool, bytes]
+
+
+class DecodeError(ValueError):
+    """Raised for malformed, truncated or unsupported input."""
+
+
+@dataclass
+class Field:
+    type: str
+    key: str
+    value: Value
+
+
+@dataclass
+class Record:
+    unix_nano: Optional[int]  # Set when the timestamp is Unix nanoseconds
+    rfc3339: Optional[str]  # Set when the timestamp is an RFC 3339 string
+    level: int
+    logger: str
+    msg: str
+    caller: str
+    stack: str
+    fields: List[Field] = field(default_factory=list)
+
+
+class _Reader:
+    def __init__(self, data: bytes, pos: int):
+        self.data = data
+        self.pos = pos
+
+    def byte(self) -> int:
+        if self.pos >= len(self.data):
+            raise DecodeError("truncated record")
+        b = self.data[self.pos]
+        self.pos += 1
+        return b
+
+    def take(self, n: int) -> bytes:
+        if n > len(self.data) - self.pos:
+            raise DecodeError("length exceeds record")
+        b = self.data[self.pos : self.pos + n]
+        self.pos += n
+        return b
+
+    def uvarint(self) -> int:
+        result = 0
+        for i in range(10):
+            b = self.byte()
+            result |= (b & 0x7F) << (7 * i)
+            if b & 0x80 == 0:
+                return result & 0xFFFFFFFFFFFFFFFF
+        raise DecodeError("varint too long")
+
+    def svarint(self) -> int:
+        u = self.uvarint()
+        return (u >> 1) ^ -(u & 1)
+
+    def string(self) -> str:
+        return self.take(self.uvarint()).decode("utf-8", errors="surrogateescape")
+
+
+def _int64(u: int) -> int:
+    return u - (1 << 64) if u >= 1 << 63 else u
+
+
+def decode(data: bytes, pos: int = 0) -> Tuple[Record, int]:
+    """Decode the record at data[pos:] and return it with the end offset."""
+    r = _Reader(data, pos)
+    if data[pos : pos + 2] != MAGIC or data[pos + 2 : pos + 3] != bytes([VERSION]):
+        raise DecodeError("bad magic or unsupported version")
+    r.pos += 3
+
+    if r.pos >= len(data):
+        raise DecodeError("truncated record")
+    unix_nano, rfc3339 = None, None
+    if data[r.pos] & 0x80:
+        unix_nano = _int64(r.uvarint())
+    else:
+        rfc3339 = r.string()
+
+    level = struct.unpack("<b", r.take(1))[0]
+    logger, msg, caller, stack = r.string(), r.string(), r.string(), r.string()
+    count = r.uvarint()
+    if count > len(data) - r.pos:
+        raise DecodeError("field count exceeds record")
+
+    fields = []
+    for _ in range(count):
+        code = r.byte()
+        key = r.string()
+        name = FIELD_TYPES.get(code)
+        if name is None:
+            raise DecodeError("unknown field type 0x%02x" % code)
+        if name in ("string", "error", "stringer", "object", "secret"):
+            value: Value = r.string()
+        elif name in ("int64", "duration", "time"):
+            value = r.svarint()
+        elif name == "uint64":
+            value = r.uvarint()
+        elif name == "float64":
+            value = struct.unpack("<d", r.take(8))[0]
+        elif name == "bool":
+            value = r.byte() != 0
+        else:  # bytes
+            value = r.take(r.uvarint())
+        fields.append(Field(name, key, value))
+
+    return Record(unix_nano, rfc3339, level, logger, msg, caller, stack, fields), r.pos
+
+
+def decode_stream(data: bytes) -> Iterator[Record]:
+    """Decode every record of a stream."""
+    pos = 0
+    while pos < len(data):
+        record, pos = decode(data, pos)
+        yield record
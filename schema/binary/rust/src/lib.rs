@@ -0,0 +1,223 @@
+//! Decoder for the Iris binary log format.
+//!
+//! Dependency free. The format is specified in `docs/BINARY_FORMAT.md`;
+//! `tests/conformance.rs` checks this crate against `testdata/binary`.
+//!
+//! ```no_run
+//! let data = std::fs::read("app.irisbin").unwrap();
+//! for record in iris_binary::decode_stream(&data) {
+//!     let record = record.unwrap();
+//!     println!("{} {}", record.level, String::from_utf8_lossy(&record.msg));
+//! }
+//! ```
+//!
+//! Copyright (c) 2025 AGILira
+//! SPDX-License-Identifier: MPL-2.0
+
+use std::fmt;
+
+pub const MAGIC: [u8; 2] = [0x52, 0x49];
+pub const VERSION: u8 = 0x01;
+
+/// Decoding failure. The rest of the stream cannot be parsed after one.
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum Error {
+    BadHeader,
+    Truncated,
+    VarintTooLong,
+    UnknownFieldType(u8),
+}
+
+impl fmt::Display for Error {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        match self {
+            Error::BadHeader => write!(f, "bad magic or unsupported version"),
+            Error::Truncated => write!(f, "truncated record"),
+            Error::VarintTooLong => write!(f, "varint too long"),
+            Error::UnknownFieldType(t) => write!(f, "unknown field type 0x{:02x}", t),
+        }
+    }
+}
+
+impl std::error::Error for Error {}
+
+/// Record timestamp, in the form chosen by the encoder.
+#[derive(Debug, Clone, PartialEq)]
+pub enum Timestamp {
+    UnixNano(i64),
+    Rfc3339(Vec<u8>),
+}
+
+/// Field value. Strings are kept as raw bytes: the format does not
+/// validate UTF-8.
+#[derive(Debug, Clone, PartialEq)]
+pub enum Value {
+    String(Vec<u8>),
+    Int64(i64),
+    Uint64(u64),
+    Float64(f64),
+    Bool(bool),
+    Duration(i64),
+    Time(i64),
+    Bytes(Vec<u8>),
+    Error(Vec<u8>),
+    Stringer(Vec<u8>),
+    Object(Vec<u8>),
+    Secret(Vec<u8>),
+}
+
+impl Value {
+    /// Type name as used in docs/BINARY_FORMAT.md.
+    pub fn type_name(&self) -> &'static str {
+        match self {
+            Value::String(_) => "string",
+            Value::Int64(_) => "int64",
+            Value::Uint64(_) => "uint64",
+            Value::Float64(_) => "float64",
+            Value::Bool(_) => "bool",
+            Value::Duration(_) => "duration",
+            Value::Time(_) => "time",
+            Value::Bytes(_) => "bytes",
+            Value::Error(_) => "error",
+            Value::Stringer(_) => "stringer",
+            Value::Object(_) => "object",
+            Value::Secret(_) => "secret",
+        }
+    }
+}
+
+#[derive(Debug, Clone, PartialEq)]
+pub struct Field {
+    pub key: Vec<u8>,
+    pub value: Value,
+}
+
+#[derive(Debug, Clone, PartialEq)]
+pub struct Record {
+    pub timestamp: Timestamp,
+    /// -2 trace, -1 debug, 0 info, 1 warn, 2 error, 3 dpanic, 4 panic, 5 fatal
+    pub level: i8,
+    pub logger: Vec<u8>,
+    pub msg: Vec<u8>,
+    pub caller: Vec<u8>,
+    pub stack: Vec<u8>,
+    pub fields: Vec<Field>,
+}
+
+struct Reader<'a> {
+    data: &'a [u8],
+    pos: usize,
+}
+
+impl<'a> Reader<'a> {
+    fn byte(&mut self) -> Result<u8, Error> {
+        let b = *self.data.get(self.pos).ok_or(Error::Truncated)?;
+        self.pos += 1;
+        Ok(b)
+    }
+
+    fn take(&mut self, n: u64) -> Result<&'a [u8], Error> {
+        if n > (self.data.len() - self.pos) as u64 {
+            return Err(Error::Truncated);
+        }
+        let b = &self.data[self.pos..self.pos + n as usize];
+        self.pos += n as usize;
+        Ok(b)
+    }
+
+    fn uvarint(&mut self) -> Result<u64, Error> {
+        let mut result = 0u64;
+        for i in 0..10 {
+            let b = self.byte()?;
+            result |= u64::from(b & 0x7f).wrapping_shl(7 * i);
+            if b & 0x80 == 0 {
+                return Ok(result);
+            }
+        }
+        Err(Error::VarintTooLong)
+    }
+
+    fn svarint(&mut self) -> Result<i64, Error> {
+        let u = self.uvarint()?;
+        Ok((u >> 1) as i64 ^ -((u & 1) as i64))
+    }
+
+    fn string(&mut self) -> Result<Vec<u8>, Error> {
+        let n = self.uvarint()?;
+        Ok(self.take(n)?.to_vec())
+    }
+}
+
+/// Decodes the record at the start of `data` and returns it with the number
+/// of bytes consumed.
+pub fn decode(data: &[u8]) -> Result<(Record, usize), Error> {
+    if data.len() < 3 || data[..2] != MAGIC || data[2] != VERSION {
+        return Err(Error::BadHeader);
+    }
+    let mut r = Reader { data, pos: 3 };
+
+    let first = *data.get(r.pos).ok_or(Error::Truncated)?;
+    let timestamp = if first & 0x80 != 0 {
+        Timestamp::UnixNano(r.uvarint()? as i64)
+    } else {
+        Timestamp::Rfc3339(r.string()?)
+    };
+    let level = r.byte()? as i8;
+    let logger = r.string()?;
+    let msg = r.string()?;
+    let caller = r.string()?;
+    let stack = r.string()?;
+
+    let count = r.uvarint()?;
+    if count > (data.len() - r.pos) as u64 {
+        return Err(Error::Truncated);
+    }
+    let mut fields = Vec::with_capacity(count as usize);
+    for _ in 0..count {
+        let code = r.byte()?;
+        let key = r.string()?;
+        let value = match code {
+            0x01 => Value::String(r.string()?),
+            0x02 => Value::Int64(r.svarint()?),
+            0x03 => Value::Uint64(r.uvarint()?),
+            0x04 => {
+                let b = r.take(8)?;
+                Value::Float64(f64::from_le_bytes(b.try_into().unwrap()))
+            }
+            0x05 => Value::Bool(r.byte()? != 0),
+            0x06 => Value::Duration(r.svarint()?),
+            0x07 => Value::Time(r.svarint()?),
+            0x08 => Value::Bytes(r.string()?),
+            0x09 => Value::Error(r.string()?),
+            0x0a => Value::Stringer(r.string()?),
+            0x0b => Value::Object(r.string()?),
+            0x0c => Value::Secret(r.string()?),
+            t => return Err(Error::UnknownFieldType(t)),
+        };
+        fields.push(Field { key, value });
+    }
+
+    Ok((Record { timestamp, level, logger, msg, caller, stack, fields }, r.pos))
+}
+
+/// Iterates over the records of a stream. Iteration stops after the first
+/// error.
+pub fn decode_stream(data: &[u8]) -> impl Iterator<Item = Result<Record, Error>> + '_ {
+    let mut rest = data;
+    let mut failed = false;
+    std::iter::from_fn(move || {
+        if rest.is_empty() || failed {
+            return None;
+        }
+        match decode(rest) {
+            Ok((record, n)) => {
+                rest = &rest[n..];
+                Some(Ok(record))
+            }
+            Err(e) => {
+                failed = true;
+                Some(Err(e))
+            }
+        }
+    })
+}
@@ -0,0 +1,104 @@
+//! Checks the decoder against the shared corpus in testdata/binary.
+
+use iris_binary::{decode_stream, Record, Timestamp, Value};
+use std::fs;
+use std::path::{Path, PathBuf};
+
+fn corpus() -> PathBuf {
+    Path::new(env!("CARGO_MANIFEST_DIR")).join("../../../testdata/binary")
+}
+
+fn push_str(out: &mut Vec<u8>, s: &[u8]) {
+    out.push(b'"');
+    for &c in s {
+        match c {
+            b'"' | b'\\' => out.extend_from_slice(&[b'\\', c]),
+            0..=0x1f => out.extend_from_slice(format!("\\u00{:02x}", c).as_bytes()),
+            _ => out.push(c),
+        }
+    }
+    out.push(b'"');
+}
+
+/// Renders records as the canonical JSON of docs/BINARY_FORMAT.md.
+fn canonical_json(records: &[Record]) -> Vec<u8> {
+    let mut out = b"[\n".to_vec();
+    for (i, r) in records.iter().enumerate() {
+        if i > 0 {
+            out.extend_from_slice(b",\n");
+        }
+        match &r.timestamp {
+            Timestamp::UnixNano(n) => out.extend_from_slice(format!("{{\"ts\":{{\"unix_nano\":{}", n).as_bytes()),
+            Timestamp::Rfc3339(s) => {
+                out.extend_from_slice(b"{\"ts\":{\"rfc3339\":");
+                push_str(&mut out, s);
+            }
+        }
+        out.extend_from_slice(format!("}},\"level\":{}", r.level).as_bytes());
+        for (name, s) in [("logger", &r.logger), ("msg", &r.msg), ("caller", &r.caller), ("stack", &r.stack)] {
+            out.extend_from_slice(format!(",\"{}\":", name).as_bytes());
+            push_str(&mut out, s);
+        }
+        out.extend_from_slice(b",\"fields\":[");
+        for (j, f) in r.fields.iter().enumerate() {
+            if j > 0 {
+                out.push(b',');
+            }
+            out.extend_from_slice(format!("{{\"type\":\"{}\",\"key\":", f.value.type_name()).as_bytes());
+            push_str(&mut out, &f.key);
+            out.extend_from_slice(b",\"value\":");
+            match &f.value {
+                Value::String(s) | Value::Error(s) | Value::Stringer(s) | Value::Object(s) | Value::Secret(s) => {
+                    push_str(&mut out, s)
+                }
+                Value::Int64(n) | Value::Duration(n) | Value::Time(n) => out.extend_from_slice(n.to_string().as_bytes()),
+                Value::Uint64(n) => out.extend_from_slice(n.to_string().as_bytes()),
+                Value::Float64(v) => out.extend_from_slice(format!("\"0x{:016x}\"", v.to_bits()).as_bytes()),
+                Value::Bool(b) => out.extend_from_slice(b.to_string().as_bytes()),
+                Value::Bytes(b) => {
+                    let hex: String = b.iter().map(|c| format!("{:02x}", c)).collect();
+                    push_str(&mut out, hex.as_bytes())
+                }
+            }
+            out.push(b'}');
+        }
+        out.extend_from_slice(b"]}");
+    }
+    out.extend_from_slice(b"\n]\n");
+    out
+}
+
+fn bin_files(dir: &Path) -> Vec<PathBuf> {
+    let mut files: Vec<PathBuf> = fs::read_dir(dir)
+        .unwrap_or_else(|e| panic!("reading {}: {}", dir.display(), e))
+        .map(|e| e.unwrap().path())
+        .filter(|p| p.extension().map_or(false, |e| e == "bin"))
+        .collect();
+    files.sort();
+    files
+}
+
+#[test]
+fn valid_corpus_decodes_to_canonical_json() {
+    let files = bin_files(&corpus().join("valid"));
+    assert!(!files.is_empty(), "empty corpus");
+    for bin in files {
+        let data = fs::read(&bin).unwrap();
+        let want = fs::read(bin.with_extension("json")).unwrap();
+        let records: Vec<Record> = decode_stream(&data)
+            .collect::<Result<_, _>>()
+            .unwrap_or_else(|e| panic!("{}: {}", bin.display(), e));
+        let got = canonical_json(&records);
+        assert!(got == want, "{}: canonical JSON differs:\n{}", bin.display(), String::from_utf8_lossy(&got));
+    }
+}
+
+#[test]
+fn invalid_corpus_is_rejected() {
+    let files = bin_files(&corpus().join("invalid"));
+    assert!(!files.is_empty(), "empty corpus");
+    for bin in files {
+        let data = fs::read(&bin).unwrap();
+        assert!(decode_stream(&data).any(|r| r.is_err()), "{}: decoded without error", bin.display());
+    }
+}
@@ -0,0 +1,208 @@
+// scrub.go: Path and username scrubbing for caller and stack information
+//
+// Absolute source paths in caller and stack fields leak details about the
+// build machine (directory layout, OS usernames). When scrubbing is enabled,
+// stack frames are rewritten to import-path-relative locations and any
+// remaining home directory or username is masked.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	osuser "os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/agilira/iris/internal/bufferpool"
+)
+
+// Replacement markers used by the scrubber
+const (
+	scrubHomeMarker = "~"
+	scrubUserMarker = "<user>"
+)
+
+var (
+	scrubOnce     sync.Once
+	scrubHomeDir  string
+	scrubUsername string
+)
+
+// WithPathScrubbing removes machine-specific paths from caller and stack data.
+//
+// Stack frames captured by AddStacktrace are written as
+// "<import path>/<file>:<line>" (e.g. "github.com/acme/api/handler/user.go:42")
+// instead of absolute build paths. Caller and stack values, including
+// "caller"/"stack" fields supplied by the application, additionally have the
+// home directory replaced with "~" and the OS username with "<user>".
+//
+// Scrubbing runs only for records that carry caller or stack information.
+//
+// Returns:
+//   - Option: Configuration function to enable path scrubbing
+//
+// Example:
+//
+//	logger := logger.WithOptions(
+//	    iris.WithCaller(),
+//	    iris.AddStacktrace(iris.Error),
+//	    iris.WithPathScrubbing(),
+//	)
+func WithPathScrubbing() Option {
+	return func(o *loggerOptions) { o.scrubPaths = true }
+}
+
+// scrubString masks the home directory and username in s.
+// It returns s unchanged (without allocating) when nothing matches.
+func scrubString(s string) string {
+	scrubOnce.Do(loadScrubIdentity)
+
+	if scrubHomeDir != "" && strings.Contains(s, scrubHomeDir) {
+		s = strings.ReplaceAll(s, scrubHomeDir, scrubHomeMarker)
+	}
+	if scrubUsername != "" && containsPathSegment(s, scrubUsername) {
+		s = replacePathSegment(s, scrubUsername, scrubUserMarker)
+	}
+	return s
+}
+
+// scrubField scrubs string caller/stack fields; other fields are returned as-is.
+func scrubField(f Field) Field {
+	if f.T == kindString && (f.K == "caller" || f.K == "stack") {
+		f.Str = scrubString(f.Str)
+	}
+	return f
+}
+
+// loadScrubIdentity resolves the home directory and username once.
+func loadScrubIdentity() {
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		scrubHomeDir = filepath.ToSlash(filepath.Clean(home))
+	}
+	if u, err := osuser.Current(); err == nil {
+		name := u.Username
+		// Windows usernames are DOMAIN\user
+		if idx := strings.LastIndexByte(name, '\\'); idx >= 0 {
+			name = name[idx+1:]
+		}
+		// Very short names would produce false positives in unrelated paths
+		if len(name) >= 3 {
+			scrubUsername = name
+		}
+	}
+}
+
+// containsPathSegment reports whether name appears as a complete path segment.
+func containsPathSegment(s, name string) bool {
+	for i := 0; ; {
+		idx := strings.Index(s[i:], name)
+		if idx < 0 {
+			return false
+		}
+		start := i + idx
+		end := start + len(name)
+		if isPathBoundary(s, start-1) && isPathBoundary(s, end) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// replacePathSegment replaces every complete path segment equal to name.
+func replacePathSegment(s, name, repl string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], name) && isPathBoundary(s, i-1) && isPathBoundary(s, i+len(name)) {
+			b.WriteString(repl)
+			i += len(name)
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// isPathBoundary reports whether position i is outside s or a path separator.
+func isPathBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	switch s[i] {
+	case '/', '\\', ':', '\n', '\t', ' ':
+		return true
+	}
+	return false
+}
+
+// scrubbedStacktrace captures a stack trace with import-path-relative file names.
+func scrubbedStacktrace(skip int) string {
+	stack := CaptureStack(skip+1, FullStack) // +1 to skip scrubbedStacktrace itself
+	defer FreeStack(stack)
+	return stack.formatStackScrubbed()
+}
+
+// formatStackScrubbed formats the stack like FormatStack, replacing each
+// absolute file path with the frame's package import path plus file name.
+func (s *Stack) formatStackScrubbed() string {
+	if s == nil {
+		return ""
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	s.frames = runtime.CallersFrames(s.pcs)
+
+	nonEmpty := false
+	for frame, more := s.frames.Next(); more; frame, more = s.frames.Next() {
+		if nonEmpty {
+			buf.WriteByte('\n')
+		}
+		nonEmpty = true
+
+		buf.WriteString(frame.Function)
+		buf.WriteByte('\n')
+		buf.WriteByte('\t')
+		buf.WriteString(relativeFramePath(frame))
+		buf.WriteByte(':')
+		writeInt(buf, int64(frame.Line))
+	}
+
+	return scrubString(buf.String())
+}
+
+// relativeFramePath returns "<package import path>/<file name>" for a frame,
+// falling back to the file name alone when the package cannot be determined.
+func relativeFramePath(frame runtime.Frame) string {
+	file := frame.File
+	if idx := strings.LastIndexByte(file, '/'); idx >= 0 {
+		file = file[idx+1:]
+	}
+	pkg := packagePath(frame.Function)
+	if pkg == "" {
+		return file
+	}
+	return pkg + "/" + file
+}
+
+// packagePath extracts the import path from a fully qualified function name
+// such as "github.com/acme/api/handler.(*User).Get".
+func packagePath(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	dot := strings.IndexByte(function[lastSlash:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:lastSlash+dot]
+}
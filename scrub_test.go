@@ -0,0 +1,109 @@
+// scrub_test.go: Tests for caller and stack path scrubbing
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+// withScrubIdentity overrides the detected home directory and username for a test
+func withScrubIdentity(t *testing.T, home, username string) {
+	t.Helper()
+	scrubOnce.Do(loadScrubIdentity)
+	prevHome, prevUser := scrubHomeDir, scrubUsername
+	scrubHomeDir, scrubUsername = home, username
+	t.Cleanup(func() { scrubHomeDir, scrubUsername = prevHome, prevUser })
+}
+
+func TestScrubString(t *testing.T) {
+	withScrubIdentity(t, "/home/alice", "alice")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/home/alice/src/app/main.go:10", "~/src/app/main.go:10"},
+		{"/srv/build/alice/app/main.go:10", "/srv/build/<user>/app/main.go:10"},
+		{`C:\Users\alice\app\main.go:10`, `C:\Users\<user>\app\main.go:10`},
+		{"/opt/malice/app.go:1", "/opt/malice/app.go:1"},
+		{"handler/user.go:42", "handler/user.go:42"},
+	}
+	for _, tt := range tests {
+		if got := scrubString(tt.input); got != tt.expected {
+			t.Errorf("scrubString(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/acme/api/handler.(*User).Get", "github.com/acme/api/handler"},
+		{"github.com/acme/api/handler.Get.func1", "github.com/acme/api/handler"},
+		{"runtime.goexit", "runtime"},
+		{"main.main", "main"},
+		{"nodot", ""},
+	}
+	for _, tt := range tests {
+		if got := packagePath(tt.function); got != tt.expected {
+			t.Errorf("packagePath(%q) = %q, want %q", tt.function, got, tt.expected)
+		}
+	}
+}
+
+func TestScrubbedStacktrace_NoAbsolutePaths(t *testing.T) {
+	st := scrubbedStacktrace(0)
+	if st == "" {
+		t.Fatal("expected non-empty stack trace")
+	}
+	if !strings.Contains(st, "github.com/agilira/iris/scrub_test.go:") {
+		t.Errorf("expected import-path-relative test frame, got:\n%s", st)
+	}
+	for _, line := range strings.Split(st, "\n") {
+		if strings.HasPrefix(line, "\t/") {
+			t.Errorf("absolute path leaked in stack frame %q", line)
+		}
+	}
+}
+
+func TestWithPathScrubbing_Logger(t *testing.T) {
+	withScrubIdentity(t, "/home/alice", "alice")
+
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:   Debug,
+		Output:  buf,
+		Encoder: NewJSONEncoder(),
+	}, AddStacktrace(Error), WithPathScrubbing())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Error("failure", Str("caller", "/home/alice/app/main.go:7"))
+	logger.Write(func(r *Record) {
+		r.Level = Info
+		r.Msg = "manual"
+		r.Stack = "main.main\n\t/home/alice/app/main.go:7"
+	})
+	_ = logger.Sync()
+
+	output := buf.String()
+	if strings.Contains(output, "/home/alice") {
+		t.Errorf("home directory leaked: %s", output)
+	}
+	if !strings.Contains(output, `"caller":"~/app/main.go:7"`) {
+		t.Errorf("expected scrubbed caller field, got %s", output)
+	}
+	if strings.Contains(output, `\t/`) {
+		t.Errorf("absolute path leaked in stack: %s", output)
+	}
+}
@@ -0,0 +1,237 @@
+// session.go: Debug sessions that lift filtering for matching records
+//
+// Raising the level to Debug for a whole service to chase one customer's
+// problem floods the logs, and lowering it again loses the trail. A debug
+// session instead lets records matching a field predicate (user_id == X,
+// a canary's tenant) through the level filter and the sampler for a limited
+// time, tags them with the session ID and optionally copies them to a
+// dedicated sink, while every other record keeps the normal settings.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// SessionKey is the field added to records captured by a debug session;
+// its value is the session ID.
+const SessionKey = "debug_session"
+
+// SessionMatcher reports whether a field selects a record for a debug
+// session. A record matches if any of its fields (including fields added
+// with With) matches. It runs on the logging goroutine for every record
+// while a session is active, so it must be cheap.
+type SessionMatcher func(f Field) bool
+
+// MatchField returns a SessionMatcher selecting records with a field named
+// key whose value is value: string fields compare their text, integer
+// fields their decimal representation and bool fields "true"/"false".
+//
+// Example:
+//
+//	logger.EnableSession("ticket-4711", iris.MatchField("user_id", "42"), 15*time.Minute)
+func MatchField(key, value string) SessionMatcher {
+	i, intErr := strconv.ParseInt(value, 10, 64)
+	u, uintErr := strconv.ParseUint(value, 10, 64)
+	b, boolErr := strconv.ParseBool(value)
+	return func(f Field) bool {
+		if f.K != key {
+			return false
+		}
+		switch f.T {
+		case kindString:
+			return f.Str == value
+		case kindInt64:
+			return intErr == nil && f.I64 == i
+		case kindUint64:
+			return uintErr == nil && f.U64 == u
+		case kindBool:
+			return boolErr == nil && (f.I64 != 0) == b
+		}
+		return false
+	}
+}
+
+// debugSession is an active session.
+type debugSession struct {
+	id       string
+	match    SessionMatcher
+	deadline int64 // Unix nanoseconds
+	timer    *time.Timer
+}
+
+// sessionRegistry holds the debug sessions shared by a logger and its clones.
+type sessionRegistry struct {
+	mu   sync.Mutex                      // Serializes EnableSession and DisableSession
+	list atomic.Pointer[[]*debugSession] // nil or non-empty, never modified in place
+}
+
+// active reports whether any session may match. One atomic load.
+func (s *sessionRegistry) active() bool {
+	return s.list.Load() != nil
+}
+
+// lookup returns the first unexpired session matching a field of base or
+// fields, or nil.
+func (s *sessionRegistry) lookup(base, fields []Field) *debugSession {
+	list := s.list.Load()
+	if list == nil {
+		return nil
+	}
+	now := timecache.CachedTimeNano()
+	for _, session := range *list {
+		if now >= session.deadline {
+			continue // Expired; its timer removes it
+		}
+		for i := range base {
+			if session.match(base[i]) {
+				return session
+			}
+		}
+		for i := range fields {
+			if session.match(fields[i]) {
+				return session
+			}
+		}
+	}
+	return nil
+}
+
+// remove unregisters the session with id, if it is still the one given
+// (nil removes whichever session has id).
+func (s *sessionRegistry) remove(id string, only *debugSession) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.removeLocked(id, only)
+}
+
+// removeLocked is remove with s.mu held.
+func (s *sessionRegistry) removeLocked(id string, only *debugSession) bool {
+	cur := s.list.Load()
+	if cur == nil {
+		return false
+	}
+	for i, session := range *cur {
+		if session.id != id || (only != nil && session != only) {
+			continue
+		}
+		session.timer.Stop()
+		if len(*cur) == 1 {
+			s.list.Store(nil)
+			return true
+		}
+		list := make([]*debugSession, 0, len(*cur)-1)
+		list = append(list, (*cur)[:i]...)
+		list = append(list, (*cur)[i+1:]...)
+		s.list.Store(&list)
+		return true
+	}
+	return false
+}
+
+// WithSessionOutput sets the sink that receives records captured by debug
+// sessions (see Logger.EnableSession).
+//
+// Captured records that pass the normal level filter are written both to
+// the logger output and to out; records admitted only because of the
+// session go to out alone, keeping the regular logs at their configured
+// verbosity. Without this option every captured record goes to the logger
+// output. Sync, Close and Reopen apply to out as well.
+//
+// Parameters:
+//   - out: Destination for session records (nil keeps the default)
+//
+// Returns:
+//   - Option: Configuration function to set the session output
+func WithSessionOutput(out WriteSyncer) Option {
+	return func(o *loggerOptions) {
+		if out != nil {
+			o.sessionOut = out
+		}
+	}
+}
+
+// EnableSession starts a debug session: for duration d, records with a
+// field matching match are logged regardless of the minimum level and the
+// sampler, and carry a SessionKey field with value id.
+//
+// Where captured records are written is set with WithSessionOutput.
+// Sessions are shared by the logger and every logger derived from it.
+// Enabling an ID that is already active replaces its matcher and deadline.
+// Records logged through Logger.Write are not matched.
+//
+// Parameters:
+//   - id: Session identifier, written in SessionKey
+//   - match: Field predicate selecting records (see MatchField)
+//   - d: Session lifetime; the session ends by itself afterwards
+//
+// Returns:
+//   - error: ErrCodeInvalidConfig for an empty id, nil match or d <= 0
+//
+// Example:
+//
+//	err := logger.EnableSession("ticket-4711", iris.MatchField("user_id", "42"), 15*time.Minute)
+func (l *Logger) EnableSession(id string, match SessionMatcher, d time.Duration) error {
+	if id == "" || match == nil || d <= 0 {
+		return NewLoggerErrorWithField(ErrCodeInvalidConfig,
+			"debug session needs an id, a matcher and a positive duration", "id", id)
+	}
+	reg := l.sessions
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.removeLocked(id, nil)
+
+	session := &debugSession{id: id, match: match, deadline: time.Now().Add(d).UnixNano()}
+	var list []*debugSession
+	if cur := reg.list.Load(); cur != nil {
+		list = make([]*debugSession, len(*cur), len(*cur)+1)
+		copy(list, *cur)
+	}
+	list = append(list, session)
+	reg.list.Store(&list)
+	// Started last: the callback waits for reg.mu, so it sees the new list
+	session.timer = time.AfterFunc(d, func() { reg.remove(id, session) })
+	return nil
+}
+
+// DisableSession ends a debug session before its duration elapses.
+//
+// Returns:
+//   - bool: true if the session was active
+func (l *Logger) DisableSession(id string) bool {
+	return l.sessions.remove(id, nil)
+}
+
+// Sessions returns the IDs of the active debug sessions.
+func (l *Logger) Sessions() []string {
+	list := l.sessions.list.Load()
+	if list == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(*list))
+	for _, session := range *list {
+		ids = append(ids, session.id)
+	}
+	return ids
+}
+
+// writeSession copies an encoded session record to the session output and
+// reports whether the record should still go to the regular outputs.
+func (l *Logger) writeSession(rec *Record, encoded []byte) bool {
+	if l.opts.sessionOut == nil {
+		return true
+	}
+	if _, err := l.opts.sessionOut.Write(encoded); err != nil {
+		l.reportWriteError(err, l.opts.sessionOut)
+	}
+	return !rec.sessionOnly
+}
@@ -0,0 +1,203 @@
+// session_test.go: Tests for debug sessions
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchField(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		field Field
+		want  bool
+	}{
+		{"string", "42", Str("user_id", "42"), true},
+		{"string mismatch", "42", Str("user_id", "43"), false},
+		{"other key", "42", Str("order_id", "42"), false},
+		{"int", "42", Int("user_id", 42), true},
+		{"negative int", "-7", Int64("user_id", -7), true},
+		{"uint", "42", Uint64("user_id", 42), true},
+		{"int from non-number", "abc", Int("user_id", 0), false},
+		{"bool", "true", Bool("user_id", true), true},
+		{"bool mismatch", "false", Bool("user_id", true), false},
+		{"unsupported kind", "1s", Dur("user_id", time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchField("user_id", tt.value)(tt.field); got != tt.want {
+				t.Errorf("MatchField(user_id, %q)(%v) = %v, want %v", tt.value, tt.field.Value(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugSession_Routing(t *testing.T) {
+	main, debug := &testSyncer{}, &testSyncer{}
+	logger, err := New(Config{Level: Warn, Output: main, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true},
+		WithSessionOutput(debug))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	if err := logger.EnableSession("ticket-1", MatchField("user_id", "42"), time.Hour); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	user := logger.With(Str("user_id", "42"))
+	user.Debug("user debug")
+	user.Error("user error")
+	logger.Info("other user info", Int("user_id", 7))
+	logger.Debug("call field debug", Int("user_id", 42))
+	logger.Error("other user error")
+
+	mainOut, debugOut := main.String(), debug.String()
+	tests := []struct {
+		msg          string
+		inMain       bool
+		inDebug      bool
+		sessionField bool
+	}{
+		{"user debug", false, true, true},
+		{"user error", true, true, true},
+		{"other user info", false, false, false},
+		{"call field debug", false, true, true},
+		{"other user error", true, false, false},
+	}
+	for _, tt := range tests {
+		line := findLine(mainOut+debugOut, tt.msg)
+		if got := strings.Contains(mainOut, `"msg":"`+tt.msg+`"`); got != tt.inMain {
+			t.Errorf("%q in main output = %v, want %v", tt.msg, got, tt.inMain)
+		}
+		if got := strings.Contains(debugOut, `"msg":"`+tt.msg+`"`); got != tt.inDebug {
+			t.Errorf("%q in session output = %v, want %v", tt.msg, got, tt.inDebug)
+		}
+		if got := strings.Contains(line, `"debug_session":"ticket-1"`); got != tt.sessionField {
+			t.Errorf("%q session field = %v, want %v: %s", tt.msg, got, tt.sessionField, line)
+		}
+	}
+
+	if !logger.DisableSession("ticket-1") || logger.DisableSession("ticket-1") {
+		t.Error("DisableSession should report true once")
+	}
+	user.Debug("after disable")
+	if strings.Contains(debug.String(), "after disable") {
+		t.Error("record captured after DisableSession")
+	}
+}
+
+func TestDebugSession_SourceLevel(t *testing.T) {
+	main, debug := &testSyncer{}, &testSyncer{}
+	logger, err := New(Config{Level: Warn, Output: main, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true},
+		WithSessionOutput(debug))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	// Debug records of this package pass the level filter on their own,
+	// so the session copies them without taking them from the main output
+	if err := logger.SetSourceLevel("github.com/agilira/iris", Debug); err != nil {
+		t.Fatalf("SetSourceLevel failed: %v", err)
+	}
+	if err := logger.EnableSession("ticket-2", MatchField("user_id", "42"), time.Hour); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	logger.Debug("source debug", Int("user_id", 42))
+	logger.Trace("session trace", Int("user_id", 42))
+
+	if !strings.Contains(main.String(), `"msg":"source debug"`) || !strings.Contains(debug.String(), `"msg":"source debug"`) {
+		t.Errorf("source debug record should reach both outputs: main=%s session=%s", main.String(), debug.String())
+	}
+	if strings.Contains(main.String(), `"msg":"session trace"`) || !strings.Contains(debug.String(), `"msg":"session trace"`) {
+		t.Errorf("session trace record should reach the session output only: main=%s session=%s", main.String(), debug.String())
+	}
+}
+
+// findLine returns the first line of output containing msg.
+func findLine(output, msg string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, `"msg":"`+msg+`"`) {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestDebugSession_BypassesSampling(t *testing.T) {
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true},
+		WithSampler(NewTokenBucketSampler(1, 0, time.Hour)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	if err := logger.EnableSession("canary", MatchField("tenant", "acme"), time.Hour); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		logger.Info("tenant event", Str("tenant", "acme"))
+		logger.Info("other event", Str("tenant", "other"))
+	}
+	if got := strings.Count(out.String(), "tenant event"); got != 5 {
+		t.Errorf("session records written = %d, want 5 (sampling bypassed)", got)
+	}
+	if got := strings.Count(out.String(), "other event"); got > 1 {
+		t.Errorf("unmatched records written = %d, want at most 1 (sampled)", got)
+	}
+}
+
+func TestDebugSession_Expiry(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	if err := logger.EnableSession("short", MatchField("k", "v"), 20*time.Millisecond); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	if err := logger.EnableSession("long", MatchField("k", "v"), time.Hour); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	// Re-enabling replaces the session instead of adding a second one
+	if err := logger.EnableSession("long", MatchField("k", "w"), time.Hour); err != nil {
+		t.Fatalf("EnableSession failed: %v", err)
+	}
+	if got := logger.Named("child").Sessions(); len(got) != 2 {
+		t.Fatalf("Sessions() = %v, want short and long", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logger.Sessions()) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("session did not expire: %v", logger.Sessions())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := logger.Sessions(); got[0] != "long" {
+		t.Errorf("Sessions() = %v, want [long]", got)
+	}
+
+	for _, bad := range []struct {
+		id    string
+		match SessionMatcher
+		d     time.Duration
+	}{
+		{"", MatchField("k", "v"), time.Second},
+		{"x", nil, time.Second},
+		{"x", MatchField("k", "v"), 0},
+	} {
+		if err := logger.EnableSession(bad.id, bad.match, bad.d); !IsLoggerError(err, ErrCodeInvalidConfig) {
+			t.Errorf("EnableSession(%q, ..., %v) = %v, want %s", bad.id, bad.d, err, ErrCodeInvalidConfig)
+		}
+	}
+}
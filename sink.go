@@ -29,6 +29,31 @@ type WriteSyncer interface {
 	Sync() error
 }
 
+// StreamSink is implemented by outputs that take records straight from a
+// StreamEncoder instead of as one pre-encoded buffer.
+//
+// For each record the logger calls RecordWriter, has the encoder write the
+// record to the returned writer, then calls EndRecord. A sink with its own
+// buffer returns it from RecordWriter (the built-in encoders encode into a
+// *bytes.Buffer directly, with no intermediate copy) and decides in
+// EndRecord when to flush it; a sink that gathers writes with writev
+// returns itself and submits the gathered writes in EndRecord. Both
+// methods are called from the logger's consumer goroutine only; Sync must
+// flush whatever the sink still holds.
+//
+// Records seen by a debug session, or also written to additional outputs
+// (WithOutput), take the buffered path and arrive through Write.
+type StreamSink interface {
+	WriteSyncer
+
+	// RecordWriter returns the writer the next record is encoded into.
+	RecordWriter() io.Writer
+
+	// EndRecord is called once the record has been written to the writer
+	// returned by RecordWriter.
+	EndRecord() error
+}
+
 // SyncWriter provides enhanced writer capabilities for external output destinations
 // such as Loki, Kafka, Prometheus, etc. This interface enables modular output
 // architecture where specialized writers are maintained as separate modules.
@@ -97,7 +122,13 @@ type fileSyncer struct{ *os.File }
 // Sync implements WriteSyncer.Sync() by calling the underlying file's Sync().
 // This forces a flush of all written data to persistent storage, ensuring
 // durability at the cost of potential I/O blocking.
-func (f fileSyncer) Sync() error { return f.File.Sync() }
+// On platforms without fsync (js/wasm) this is a no-op.
+func (f fileSyncer) Sync() error {
+	if !fileSyncSupported {
+		return nil
+	}
+	return f.File.Sync()
+}
 
 // WrapWriter intelligently converts any io.Writer into a WriteSyncer.
 // This function provides automatic detection and wrapping of different writer
@@ -176,6 +207,18 @@ func (m *multiWS) Sync() error {
 	return firstErr
 }
 
+// Reopen reopens every writer that implements Reopener and returns the
+// first error.
+func (m *multiWS) Reopen() error {
+	var firstErr error
+	for _, w := range m.ws {
+		if err := reopenSink(w); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 // NewFileSyncer creates a WriteSyncer specifically for file operations.
 // This function provides explicit file syncing capabilities and should be
 // used when you need guaranteed durability for file-based logging.
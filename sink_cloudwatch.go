@@ -0,0 +1,506 @@
+// sink_cloudwatch.go: AWS CloudWatch Logs transport
+//
+// CloudWatchWriter ships records to a CloudWatch Logs stream. Writes only
+// append the record to the current batch; a background goroutine sends
+// batches with PutLogEvents when they reach the API limits (10,000 events
+// or 1,048,576 bytes, counting 26 bytes of overhead per event) or when
+// FlushInterval elapses, so the logger's consumer never waits on AWS.
+//
+// Delivery follows the API contract: the sequence token returned by each
+// call is passed to the next one and refreshed from InvalidSequenceToken
+// and DataAlreadyAccepted errors, and throttling, 5xx and network errors
+// are retried with exponential backoff and jitter. A batch that still
+// fails, or that finds the queue full, is dropped and counted in Stats().
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PutLogEvents limits.
+const (
+	// MaxCloudWatchBatchEvents is the most events one call accepts
+	MaxCloudWatchBatchEvents = 10000
+
+	// MaxCloudWatchBatchBytes is the largest batch one call accepts,
+	// counting CloudWatchEventOverhead per event
+	MaxCloudWatchBatchBytes = 1048576
+
+	// MaxCloudWatchEventBytes is the largest event, overhead included;
+	// larger records are dropped
+	MaxCloudWatchEventBytes = 262144
+
+	// CloudWatchEventOverhead is added to the message size of each event
+	CloudWatchEventOverhead = 26
+)
+
+// CloudWatchWriter defaults.
+const (
+	DefaultCloudWatchFlushInterval = 5 * time.Second
+	DefaultCloudWatchMaxRetries    = 5
+	DefaultCloudWatchMinBackoff    = 100 * time.Millisecond
+	DefaultCloudWatchMaxBackoff    = 10 * time.Second
+	DefaultCloudWatchQueueSize     = 4
+	DefaultCloudWatchTimeout       = 10 * time.Second
+)
+
+// CloudWatch Logs error codes handled by CloudWatchWriter.
+const (
+	cloudWatchInvalidSequenceToken = "InvalidSequenceTokenException"
+	cloudWatchDataAlreadyAccepted  = "DataAlreadyAcceptedException"
+	cloudWatchThrottling           = "ThrottlingException"
+	cloudWatchServiceUnavailable   = "ServiceUnavailableException"
+)
+
+// CloudWatchEvent is one log event of a PutLogEvents call.
+type CloudWatchEvent struct {
+	Timestamp int64  // Milliseconds since the Unix epoch
+	Message   string // The encoded record, without its trailing newline
+}
+
+// CloudWatchPutRequest is the input of a PutLogEvents call. Events are in
+// chronological order.
+type CloudWatchPutRequest struct {
+	LogGroup      string
+	LogStream     string
+	SequenceToken string // Empty for the first call on a stream
+	Events        []CloudWatchEvent
+}
+
+// CloudWatchPutResult is the output of a PutLogEvents call.
+type CloudWatchPutResult struct {
+	// NextSequenceToken is passed to the next call (may be empty)
+	NextSequenceToken string
+
+	// RejectedEvents counts events CloudWatch refused as too old, too new
+	// or expired
+	RejectedEvents int
+}
+
+// CloudWatchClient sends PutLogEvents calls. NewCloudWatchHTTPClient
+// implements it over HTTPS; an adapter around the AWS SDK is a few lines
+// and brings its credential chain (IAM roles, SSO, ...). Failures
+// reported by CloudWatch should be returned as *CloudWatchError, so the
+// writer can tell throttling and sequence token errors apart.
+type CloudWatchClient interface {
+	PutLogEvents(ctx context.Context, req *CloudWatchPutRequest) (*CloudWatchPutResult, error)
+}
+
+// CloudWatchError is a failure reported by CloudWatch Logs.
+type CloudWatchError struct {
+	// Code is the exception name (e.g. "ThrottlingException")
+	Code string
+
+	// Message is the description returned by the service
+	Message string
+
+	// StatusCode is the HTTP status, 0 if unknown
+	StatusCode int
+
+	// ExpectedSequenceToken is set on InvalidSequenceTokenException and
+	// DataAlreadyAcceptedException
+	ExpectedSequenceToken string
+}
+
+// Error implements error.
+func (e *CloudWatchError) Error() string {
+	if e.Message == "" {
+		return "cloudwatch: " + e.Code
+	}
+	return "cloudwatch: " + e.Code + ": " + e.Message
+}
+
+// retryable reports whether the call may succeed if repeated later.
+func (e *CloudWatchError) retryable() bool {
+	return e.Code == cloudWatchThrottling || e.Code == cloudWatchServiceUnavailable || e.StatusCode >= 500
+}
+
+// CloudWatchConfig configures a CloudWatchWriter.
+type CloudWatchConfig struct {
+	// LogGroup and LogStream receive the events; both must exist.
+	LogGroup  string
+	LogStream string
+
+	// Client sends the batches (default: NewCloudWatchHTTPClient with the
+	// region and credentials of the AWS_* environment variables).
+	Client CloudWatchClient
+
+	// FlushInterval bounds how long a record waits in a partial batch
+	// (default DefaultCloudWatchFlushInterval).
+	FlushInterval time.Duration
+
+	// MaxBatchEvents and MaxBatchBytes cap a batch below the API limits
+	// (defaults and maximums MaxCloudWatchBatchEvents and
+	// MaxCloudWatchBatchBytes).
+	MaxBatchEvents int
+	MaxBatchBytes  int
+
+	// MaxRetries bounds the attempts after the first one for a throttled
+	// or failing batch (default DefaultCloudWatchMaxRetries; negative
+	// disables retries).
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries (defaults DefaultCloudWatchMinBackoff and
+	// DefaultCloudWatchMaxBackoff).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// QueueSize is the number of full batches waiting to be sent before
+	// new ones are dropped (default DefaultCloudWatchQueueSize).
+	QueueSize int
+
+	// Timeout bounds each PutLogEvents call (default
+	// DefaultCloudWatchTimeout).
+	Timeout time.Duration
+}
+
+// CloudWatchWriter writes records to a CloudWatch Logs stream in batches.
+// It is a WriteSyncer: Sync sends the pending records and waits for the
+// queued batches. Close flushes and stops the sender; call it before
+// exiting, after closing the logger.
+type CloudWatchWriter struct {
+	group, stream string
+	client        CloudWatchClient
+	maxEvents     int
+	maxBytes      int
+	maxRetries    int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	timeout       time.Duration
+
+	mu         sync.Mutex
+	batch      []CloudWatchEvent
+	batchBytes int
+	lastTime   int64 // Timestamp of the last event, keeping batches ordered
+	closed     bool
+
+	queue   chan []CloudWatchEvent
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	token   string // Owned by the sender goroutine
+
+	batchesSent    atomic.Int64
+	batchesDropped atomic.Int64
+	eventsSent     atomic.Int64
+	eventsDropped  atomic.Int64
+	eventsRejected atomic.Int64
+	retries        atomic.Int64
+	throttled      atomic.Int64
+	tokenRefreshes atomic.Int64
+}
+
+// NewCloudWatchWriter starts a writer for the stream described by cfg.
+//
+// Parameters:
+//   - cfg: Stream, client and batching settings
+//
+// Returns:
+//   - *CloudWatchWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidConfig for a missing group or stream or limits
+//     above the API maximums, or the error of the default client
+//
+// Example:
+//
+//	out, err := iris.NewCloudWatchWriter(iris.CloudWatchConfig{
+//	    LogGroup:  "/ecs/checkout",
+//	    LogStream: hostname,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer out.Close()
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewCloudWatchWriter(cfg CloudWatchConfig) (*CloudWatchWriter, error) {
+	if cfg.LogGroup == "" || cfg.LogStream == "" {
+		return nil, NewLoggerError(ErrCodeInvalidConfig, "CloudWatch log group and stream are required")
+	}
+	if cfg.MaxBatchEvents > MaxCloudWatchBatchEvents || cfg.MaxBatchEvents < 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "CloudWatch batches hold at most 10000 events", "max_batch_events", strconv.Itoa(cfg.MaxBatchEvents))
+	}
+	if cfg.MaxBatchBytes > MaxCloudWatchBatchBytes || cfg.MaxBatchBytes < 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "CloudWatch batches hold at most 1048576 bytes", "max_batch_bytes", strconv.Itoa(cfg.MaxBatchBytes))
+	}
+	if cfg.Client == nil {
+		client, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{})
+		if err != nil {
+			return nil, err
+		}
+		cfg.Client = client
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultCloudWatchFlushInterval
+	}
+	if cfg.MaxBatchEvents == 0 {
+		cfg.MaxBatchEvents = MaxCloudWatchBatchEvents
+	}
+	if cfg.MaxBatchBytes == 0 {
+		cfg.MaxBatchBytes = MaxCloudWatchBatchBytes
+	}
+	switch {
+	case cfg.MaxRetries == 0:
+		cfg.MaxRetries = DefaultCloudWatchMaxRetries
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultCloudWatchMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(DefaultCloudWatchMaxBackoff, cfg.MinBackoff)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultCloudWatchQueueSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultCloudWatchTimeout
+	}
+
+	w := &CloudWatchWriter{
+		group:      cfg.LogGroup,
+		stream:     cfg.LogStream,
+		client:     cfg.Client,
+		maxEvents:  cfg.MaxBatchEvents,
+		maxBytes:   cfg.MaxBatchBytes,
+		maxRetries: cfg.MaxRetries,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		timeout:    cfg.Timeout,
+		queue:      make(chan []CloudWatchEvent, cfg.QueueSize),
+		flushes:    make(chan chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go w.run(cfg.FlushInterval)
+	return w, nil
+}
+
+// Write adds p, one encoded record, to the current batch. A trailing
+// newline is removed. Records larger than MaxCloudWatchEventBytes are
+// dropped.
+func (w *CloudWatchWriter) Write(p []byte) (int, error) {
+	msg := bytes.TrimSuffix(p, []byte{'\n'})
+	size := len(msg) + CloudWatchEventOverhead
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "CloudWatch writer is closed")
+	}
+	if size > MaxCloudWatchEventBytes || size > w.maxBytes {
+		w.eventsDropped.Add(1)
+		return len(p), nil
+	}
+	if len(w.batch) >= w.maxEvents || w.batchBytes+size > w.maxBytes {
+		w.enqueue()
+	}
+
+	// Events of a batch must be in chronological order
+	ts := time.Now().UnixMilli()
+	if ts < w.lastTime {
+		ts = w.lastTime
+	}
+	w.lastTime = ts
+	w.batch = append(w.batch, CloudWatchEvent{Timestamp: ts, Message: string(msg)})
+	w.batchBytes += size
+	return len(p), nil
+}
+
+// enqueue hands the current batch to the sender, dropping it when the
+// queue is full. Must be called with w.mu held.
+func (w *CloudWatchWriter) enqueue() {
+	if len(w.batch) == 0 {
+		return
+	}
+	select {
+	case w.queue <- w.batch:
+	default:
+		w.batchesDropped.Add(1)
+		w.eventsDropped.Add(int64(len(w.batch)))
+	}
+	w.batch = make([]CloudWatchEvent, 0, len(w.batch))
+	w.batchBytes = 0
+}
+
+// Sync sends the pending records and waits until every queued batch has
+// been delivered or dropped.
+func (w *CloudWatchWriter) Sync() error {
+	reply := make(chan struct{})
+	select {
+	case w.flushes <- reply:
+		<-reply
+	case <-w.done:
+	}
+	return nil
+}
+
+// Close sends the pending records and stops the sender. Subsequent writes
+// fail.
+func (w *CloudWatchWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	close(w.stop)
+	<-w.done
+	return nil
+}
+
+// Dropped returns the number of records that were not delivered.
+func (w *CloudWatchWriter) Dropped() int64 {
+	return w.eventsDropped.Load()
+}
+
+// DeliveryStats returns the events in calls CloudWatch answered (Sent),
+// the part of them it accepted (Acked), the repeated calls (Retried) and
+// the events given up (Dropped).
+func (w *CloudWatchWriter) DeliveryStats() DeliveryStats {
+	rejected := w.eventsRejected.Load()
+	sent := w.eventsSent.Load()
+	return DeliveryStats{
+		Sent:    sent,
+		Acked:   sent - rejected,
+		Retried: w.retries.Load(),
+		Dropped: w.eventsDropped.Load(),
+	}
+}
+
+// Stats returns the delivery counters of the writer:
+//   - batches_sent, events_sent: delivered by PutLogEvents
+//   - batches_dropped, events_dropped: given up after the retries, or
+//     rejected by a full queue or the size limits
+//   - events_rejected: accepted calls whose events CloudWatch refused as
+//     too old or too new
+//   - retries: repeated calls, throttled: ThrottlingException responses
+//   - sequence_token_refreshes: calls repeated with the expected token
+func (w *CloudWatchWriter) Stats() map[string]int64 {
+	return map[string]int64{
+		"batches_sent":             w.batchesSent.Load(),
+		"batches_dropped":          w.batchesDropped.Load(),
+		"events_sent":              w.eventsSent.Load(),
+		"events_dropped":           w.eventsDropped.Load(),
+		"events_rejected":          w.eventsRejected.Load(),
+		"retries":                  w.retries.Load(),
+		"throttled":                w.throttled.Load(),
+		"sequence_token_refreshes": w.tokenRefreshes.Load(),
+	}
+}
+
+// run is the sender goroutine.
+func (w *CloudWatchWriter) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case batch := <-w.queue:
+			w.send(batch)
+		case <-ticker.C:
+			w.drain()
+		case reply := <-w.flushes:
+			w.drain()
+			close(reply)
+		case <-w.stop:
+			w.drain()
+			return
+		}
+	}
+}
+
+// drain sends the queued batches, then the pending one.
+func (w *CloudWatchWriter) drain() {
+	for len(w.queue) > 0 { // Only this goroutine receives
+		w.send(<-w.queue)
+	}
+	w.mu.Lock()
+	batch := w.batch
+	w.batch, w.batchBytes = nil, 0
+	w.mu.Unlock()
+	if len(batch) > 0 {
+		w.send(batch)
+	}
+}
+
+// send delivers one batch, retrying throttled and failed calls.
+func (w *CloudWatchWriter) send(batch []CloudWatchEvent) {
+	req := &CloudWatchPutRequest{LogGroup: w.group, LogStream: w.stream, Events: batch}
+	for attempt := 0; ; attempt++ {
+		req.SequenceToken = w.token
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		res, err := w.client.PutLogEvents(ctx, req)
+		cancel()
+		if err == nil {
+			// Sent before rejected, so that DeliveryStats never reads
+			// more rejected events than sent ones
+			w.delivered(batch)
+			if res != nil {
+				w.token = res.NextSequenceToken
+				w.eventsRejected.Add(int64(res.RejectedEvents))
+			}
+			return
+		}
+
+		var cwErr *CloudWatchError
+		retry := true
+		if errors.As(err, &cwErr) {
+			switch {
+			case cwErr.Code == cloudWatchDataAlreadyAccepted:
+				w.token = cwErr.ExpectedSequenceToken
+				w.delivered(batch)
+				return
+			case cwErr.Code == cloudWatchInvalidSequenceToken:
+				// Retried at once with the right token
+				w.token = cwErr.ExpectedSequenceToken
+				w.tokenRefreshes.Add(1)
+				if attempt < w.maxRetries {
+					w.retries.Add(1)
+					continue
+				}
+			case cwErr.Code == cloudWatchThrottling:
+				w.throttled.Add(1)
+			default:
+				retry = cwErr.retryable()
+			}
+		}
+		if !retry || attempt >= w.maxRetries {
+			w.batchesDropped.Add(1)
+			w.eventsDropped.Add(int64(len(batch)))
+			return
+		}
+		w.retries.Add(1)
+		time.Sleep(w.backoff(attempt))
+	}
+}
+
+// delivered counts a batch accepted by CloudWatch.
+func (w *CloudWatchWriter) delivered(batch []CloudWatchEvent) {
+	w.batchesSent.Add(1)
+	w.eventsSent.Add(int64(len(batch)))
+}
+
+// backoff returns the delay before retry attempt+1: MinBackoff doubled per
+// attempt up to MaxBackoff, half of it randomized.
+func (w *CloudWatchWriter) backoff(attempt int) time.Duration {
+	d := w.maxBackoff
+	if attempt < 30 {
+		d = min(w.minBackoff<<attempt, w.maxBackoff)
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
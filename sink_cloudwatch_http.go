@@ -0,0 +1,266 @@
+// sink_cloudwatch_http.go: PutLogEvents over HTTPS with AWS Signature V4
+//
+// CloudWatchHTTPClient calls the CloudWatch Logs JSON API directly, signing
+// requests with static credentials, so shipping logs to CloudWatch needs no
+// AWS SDK. Applications that rely on the SDK credential chain (instance
+// roles, SSO, assume-role) implement CloudWatchClient around it instead.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cloudWatchTarget is the X-Amz-Target of PutLogEvents.
+const cloudWatchTarget = "Logs_20140328.PutLogEvents"
+
+// CloudWatchCredentials are the static AWS credentials signing requests.
+type CloudWatchCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// CloudWatchHTTPConfig configures a CloudWatchHTTPClient.
+type CloudWatchHTTPConfig struct {
+	// Region of the log group (default AWS_REGION, then
+	// AWS_DEFAULT_REGION).
+	Region string
+
+	// Endpoint overrides https://logs.<region>.amazonaws.com (VPC
+	// endpoints, LocalStack).
+	Endpoint string
+
+	// Credentials sign the requests (default AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN).
+	Credentials CloudWatchCredentials
+
+	// HTTPClient sends the requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// CloudWatchHTTPClient implements CloudWatchClient with the CloudWatch
+// Logs JSON API and Signature Version 4.
+type CloudWatchHTTPClient struct {
+	region   string
+	endpoint string
+	creds    CloudWatchCredentials
+	http     *http.Client
+	now      func() time.Time
+}
+
+// NewCloudWatchHTTPClient creates a client for the region and credentials
+// of cfg, completed from the standard AWS environment variables.
+//
+// Parameters:
+//   - cfg: Region, endpoint and credentials
+//
+// Returns:
+//   - *CloudWatchHTTPClient: Client for CloudWatchConfig.Client
+//   - error: ErrCodeInvalidConfig when no region or credentials are found
+func NewCloudWatchHTTPClient(cfg CloudWatchHTTPConfig) (*CloudWatchHTTPClient, error) {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if cfg.Region == "" {
+		return nil, NewLoggerError(ErrCodeInvalidConfig, "CloudWatch region not set (CloudWatchHTTPConfig.Region or AWS_REGION)")
+	}
+	if cfg.Credentials.AccessKeyID == "" {
+		cfg.Credentials = CloudWatchCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, NewLoggerError(ErrCodeInvalidConfig, "CloudWatch credentials not set (CloudWatchHTTPConfig.Credentials or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://logs." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &CloudWatchHTTPClient{
+		region:   cfg.Region,
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/") + "/",
+		creds:    cfg.Credentials,
+		http:     cfg.HTTPClient,
+		now:      time.Now,
+	}, nil
+}
+
+// cloudWatchPutBody is the JSON request of PutLogEvents.
+type cloudWatchPutBody struct {
+	LogGroupName  string               `json:"logGroupName"`
+	LogStreamName string               `json:"logStreamName"`
+	SequenceToken string               `json:"sequenceToken,omitempty"`
+	LogEvents     []cloudWatchPutEvent `json:"logEvents"`
+}
+
+// cloudWatchPutEvent is one event of the JSON request.
+type cloudWatchPutEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// cloudWatchPutResponse is the JSON response of PutLogEvents, or its
+// error.
+type cloudWatchPutResponse struct {
+	NextSequenceToken string `json:"nextSequenceToken"`
+	Rejected          *struct {
+		TooNewStartIndex *int `json:"tooNewLogEventStartIndex"`
+		TooOldEndIndex   *int `json:"tooOldLogEventEndIndex"`
+		ExpiredEndIndex  *int `json:"expiredLogEventEndIndex"`
+	} `json:"rejectedLogEventsInfo"`
+
+	Type                  string `json:"__type"`
+	Message               string `json:"message"` // Also matches "Message"
+	ExpectedSequenceToken string `json:"expectedSequenceToken"`
+}
+
+// PutLogEvents sends req and returns the next sequence token. Service
+// failures are returned as *CloudWatchError.
+func (c *CloudWatchHTTPClient) PutLogEvents(ctx context.Context, req *CloudWatchPutRequest) (*CloudWatchPutResult, error) {
+	body := cloudWatchPutBody{
+		LogGroupName:  req.LogGroup,
+		LogStreamName: req.LogStream,
+		SequenceToken: req.SequenceToken,
+		LogEvents:     make([]cloudWatchPutEvent, len(req.Events)),
+	}
+	for i, e := range req.Events {
+		body.LogEvents[i] = cloudWatchPutEvent(e)
+	}
+	payload, err := json.Marshal(&body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpReq.Header.Set("X-Amz-Target", cloudWatchTarget)
+	signAWSRequest(httpReq, payload, c.creds, c.region, "logs", c.now())
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var out cloudWatchPutResponse
+	_ = json.Unmarshal(data, &out)
+	if resp.StatusCode != http.StatusOK {
+		code := out.Type[strings.LastIndexByte(out.Type, '#')+1:]
+		if code == "" {
+			code = http.StatusText(resp.StatusCode)
+		}
+		return nil, &CloudWatchError{Code: code, Message: out.Message, StatusCode: resp.StatusCode, ExpectedSequenceToken: out.ExpectedSequenceToken}
+	}
+
+	res := &CloudWatchPutResult{NextSequenceToken: out.NextSequenceToken}
+	if r := out.Rejected; r != nil {
+		// Events before the too-old and expired indexes and from the
+		// too-new index on were refused
+		old := -1
+		if r.TooOldEndIndex != nil {
+			old = *r.TooOldEndIndex
+		}
+		if r.ExpiredEndIndex != nil && *r.ExpiredEndIndex > old {
+			old = *r.ExpiredEndIndex
+		}
+		res.RejectedEvents = old + 1
+		if r.TooNewStartIndex != nil {
+			res.RejectedEvents += len(req.Events) - *r.TooNewStartIndex
+		}
+	}
+	return res, nil
+}
+
+// signAWSRequest adds the Signature Version 4 headers to req, whose body
+// is payload, for service in region.
+func signAWSRequest(req *http.Request, payload []byte, creds CloudWatchCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers: host and every header set on the request
+	headers := map[string]string{"host": req.Host}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// hmacSHA256 returns HMAC-SHA256(key, data).
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,136 @@
+// sink_cloudwatch_http_test.go: Tests for the CloudWatch Logs HTTPS client
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAWSRequest_TestSuite(t *testing.T) {
+	// post-vanilla from the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := CloudWatchCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCloudWatchHTTPClient_PutLogEvents(t *testing.T) {
+	var got struct {
+		LogGroupName  string `json:"logGroupName"`
+		LogStreamName string `json:"logStreamName"`
+		SequenceToken string `json:"sequenceToken"`
+		LogEvents     []struct {
+			Timestamp int64  `json:"timestamp"`
+			Message   string `json:"message"`
+		} `json:"logEvents"`
+	}
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		_, _ = io.WriteString(w, `{"nextSequenceToken":"next-1","rejectedLogEventsInfo":{"tooOldLogEventEndIndex":0,"tooNewLogEventStartIndex":2}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{
+		Region:      "eu-west-1",
+		Endpoint:    server.URL,
+		Credentials: CloudWatchCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"},
+	})
+	if err != nil {
+		t.Fatalf("NewCloudWatchHTTPClient failed: %v", err)
+	}
+	res, err := client.PutLogEvents(context.Background(), &CloudWatchPutRequest{
+		LogGroup: "/app", LogStream: "web-1", SequenceToken: "prev",
+		Events: []CloudWatchEvent{{1, "old"}, {2, "ok"}, {3, "future"}},
+	})
+	if err != nil {
+		t.Fatalf("PutLogEvents failed: %v", err)
+	}
+	if res.NextSequenceToken != "next-1" || res.RejectedEvents != 2 {
+		t.Errorf("result = %+v", res)
+	}
+	if got.LogGroupName != "/app" || got.LogStreamName != "web-1" || got.SequenceToken != "prev" || len(got.LogEvents) != 3 || got.LogEvents[1].Message != "ok" {
+		t.Errorf("request = %+v", got)
+	}
+	if header.Get("X-Amz-Target") != "Logs_20140328.PutLogEvents" || header.Get("Content-Type") != "application/x-amz-json-1.1" {
+		t.Errorf("headers = %v", header)
+	}
+	auth := header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/logs/aws4_request") ||
+		!strings.Contains(auth, "x-amz-security-token") || header.Get("X-Amz-Security-Token") != "session" {
+		t.Errorf("Authorization = %s", auth)
+	}
+}
+
+func TestCloudWatchHTTPClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"__type":"com.amazonaws.logs#InvalidSequenceTokenException","expectedSequenceToken":"exp-9","message":"The given sequenceToken is invalid."}`)
+	}))
+	defer server.Close()
+
+	client, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{
+		Region: "us-east-1", Endpoint: server.URL,
+		Credentials: CloudWatchCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("NewCloudWatchHTTPClient failed: %v", err)
+	}
+	_, err = client.PutLogEvents(context.Background(), &CloudWatchPutRequest{LogGroup: "g", LogStream: "s", Events: []CloudWatchEvent{{1, "m"}}})
+	var cwErr *CloudWatchError
+	if !errors.As(err, &cwErr) {
+		t.Fatalf("error = %v, want *CloudWatchError", err)
+	}
+	if cwErr.Code != "InvalidSequenceTokenException" || cwErr.ExpectedSequenceToken != "exp-9" || cwErr.StatusCode != 400 {
+		t.Errorf("error = %+v", cwErr)
+	}
+	if !strings.Contains(cwErr.Error(), "sequenceToken is invalid") {
+		t.Errorf("Error() = %s", cwErr.Error())
+	}
+}
+
+func TestNewCloudWatchHTTPClient_Environment(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if _, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{}); !IsLoggerError(err, ErrCodeInvalidConfig) {
+		t.Errorf("error without region = %v", err)
+	}
+
+	t.Setenv("AWS_DEFAULT_REGION", "ap-south-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if _, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{}); !IsLoggerError(err, ErrCodeInvalidConfig) {
+		t.Errorf("error without credentials = %v", err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	client, err := NewCloudWatchHTTPClient(CloudWatchHTTPConfig{})
+	if err != nil {
+		t.Fatalf("NewCloudWatchHTTPClient failed: %v", err)
+	}
+	if client.endpoint != "https://logs.ap-south-1.amazonaws.com/" || client.creds.AccessKeyID != "AKID" {
+		t.Errorf("client = %s %+v", client.endpoint, client.creds.AccessKeyID)
+	}
+}
@@ -0,0 +1,248 @@
+// sink_cloudwatch_test.go: Tests for the CloudWatch Logs transport
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCloudWatch records PutLogEvents calls, failing them with the
+// scripted errors first.
+type fakeCloudWatch struct {
+	mu     sync.Mutex
+	errs   []error
+	calls  []CloudWatchPutRequest
+	tokens []string // Sequence token of each call
+	next   int
+}
+
+func (f *fakeCloudWatch) PutLogEvents(_ context.Context, req *CloudWatchPutRequest) (*CloudWatchPutResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = append(f.tokens, req.SequenceToken)
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	f.calls = append(f.calls, CloudWatchPutRequest{
+		LogGroup:  req.LogGroup,
+		LogStream: req.LogStream,
+		Events:    append([]CloudWatchEvent(nil), req.Events...),
+	})
+	f.next++
+	return &CloudWatchPutResult{NextSequenceToken: "token-" + string(rune('0'+f.next))}, nil
+}
+
+// delivered returns the calls that succeeded.
+func (f *fakeCloudWatch) delivered() []CloudWatchPutRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]CloudWatchPutRequest(nil), f.calls...)
+}
+
+// newTestCloudWatch returns a writer on a fake client that flushes only
+// on Sync and retries without waiting.
+func newTestCloudWatch(t *testing.T, client CloudWatchClient, cfg CloudWatchConfig) *CloudWatchWriter {
+	t.Helper()
+	cfg.LogGroup, cfg.LogStream, cfg.Client = "/app", "web-1", client
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Hour
+	}
+	cfg.MinBackoff, cfg.MaxBackoff = time.Millisecond, 2*time.Millisecond
+	w, err := NewCloudWatchWriter(cfg)
+	if err != nil {
+		t.Fatalf("NewCloudWatchWriter failed: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func TestCloudWatchWriter_Batching(t *testing.T) {
+	client := &fakeCloudWatch{}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{MaxBatchEvents: 3})
+
+	logger, err := New(Config{Level: Info, Output: w, Encoder: NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		logger.Info("event", Int("i", i))
+	}
+	_ = logger.Close()
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	calls := client.delivered()
+	if len(calls) != 3 || len(calls[0].Events) != 3 || len(calls[2].Events) != 1 {
+		t.Fatalf("batches = %+v, want 3+3+1 events", calls)
+	}
+	if calls[0].LogGroup != "/app" || calls[0].LogStream != "web-1" {
+		t.Errorf("stream = %s/%s", calls[0].LogGroup, calls[0].LogStream)
+	}
+	var last int64
+	for _, call := range calls {
+		for _, e := range call.Events {
+			if e.Timestamp < last || strings.HasSuffix(e.Message, "\n") || !strings.Contains(e.Message, `"msg":"event"`) {
+				t.Errorf("event %+v after timestamp %d", e, last)
+			}
+			last = e.Timestamp
+		}
+	}
+
+	// Each call carries the token returned by the previous one
+	if got := strings.Join(client.tokens, ","); got != ",token-1,token-2" {
+		t.Errorf("sequence tokens = %q", got)
+	}
+	stats := w.Stats()
+	if stats["batches_sent"] != 3 || stats["events_sent"] != 7 || stats["batches_dropped"] != 0 {
+		t.Errorf("Stats() = %v", stats)
+	}
+}
+
+func TestCloudWatchWriter_ByteLimit(t *testing.T) {
+	client := &fakeCloudWatch{}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{MaxBatchBytes: 200})
+
+	msg := strings.Repeat("x", 74) // 100 bytes with the overhead
+	for i := 0; i < 5; i++ {
+		_, _ = w.Write([]byte(msg + "\n"))
+	}
+	_, _ = w.Write([]byte(strings.Repeat("y", 200))) // Larger than a batch
+	_ = w.Sync()
+
+	calls := client.delivered()
+	if len(calls) != 3 || len(calls[0].Events) != 2 || len(calls[2].Events) != 1 {
+		t.Fatalf("got %d batches, want 2+2+1 events", len(calls))
+	}
+	if calls[0].Events[0].Message != msg {
+		t.Errorf("message = %q", calls[0].Events[0].Message)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want the oversized record", w.Dropped())
+	}
+}
+
+func TestCloudWatchWriter_SequenceToken(t *testing.T) {
+	client := &fakeCloudWatch{errs: []error{
+		&CloudWatchError{Code: "InvalidSequenceTokenException", StatusCode: 400, ExpectedSequenceToken: "expected-1"},
+	}}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{})
+	_, _ = w.Write([]byte("first\n"))
+	_ = w.Sync()
+
+	if got := strings.Join(client.tokens, ","); got != ",expected-1" {
+		t.Errorf("sequence tokens = %q, want the expected token on retry", got)
+	}
+
+	// DataAlreadyAccepted counts as delivered and refreshes the token
+	client.mu.Lock()
+	client.errs = []error{&CloudWatchError{Code: "DataAlreadyAcceptedException", StatusCode: 400, ExpectedSequenceToken: "expected-2"}}
+	client.mu.Unlock()
+	_, _ = w.Write([]byte("second\n"))
+	_, _ = w.Write([]byte("third\n"))
+	_ = w.Sync()
+	_, _ = w.Write([]byte("fourth\n"))
+	_ = w.Sync()
+
+	stats := w.Stats()
+	if stats["batches_sent"] != 3 || stats["sequence_token_refreshes"] != 1 || stats["retries"] != 1 {
+		t.Errorf("Stats() = %v", stats)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if last := client.tokens[len(client.tokens)-1]; last != "expected-2" {
+		t.Errorf("token after DataAlreadyAccepted = %q", last)
+	}
+}
+
+func TestCloudWatchWriter_Throttling(t *testing.T) {
+	throttled := &CloudWatchError{Code: "ThrottlingException", StatusCode: 400, Message: "Rate exceeded"}
+	client := &fakeCloudWatch{errs: []error{throttled, throttled, errors.New("connection reset")}}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{MaxRetries: 3})
+	_, _ = w.Write([]byte("eventually\n"))
+	_ = w.Sync()
+
+	if calls := client.delivered(); len(calls) != 1 {
+		t.Fatalf("delivered %d batches after retrying", len(calls))
+	}
+	stats := w.Stats()
+	if stats["throttled"] != 2 || stats["retries"] != 3 || stats["batches_sent"] != 1 {
+		t.Errorf("Stats() = %v", stats)
+	}
+
+	// Retries are bounded
+	client.mu.Lock()
+	client.errs = []error{throttled, throttled, throttled, throttled}
+	client.mu.Unlock()
+	_, _ = w.Write([]byte("lost\n"))
+	_ = w.Sync()
+	stats = w.Stats()
+	if stats["batches_dropped"] != 1 || stats["events_dropped"] != 1 {
+		t.Errorf("Stats() = %v, want the batch dropped after 3 retries", stats)
+	}
+}
+
+func TestCloudWatchWriter_PermanentError(t *testing.T) {
+	client := &fakeCloudWatch{errs: []error{
+		&CloudWatchError{Code: "ResourceNotFoundException", StatusCode: 400, Message: "The specified log stream does not exist."},
+	}}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{})
+	_, _ = w.Write([]byte("nowhere\n"))
+	_ = w.Sync()
+
+	stats := w.Stats()
+	if stats["retries"] != 0 || stats["batches_dropped"] != 1 {
+		t.Errorf("Stats() = %v, want the batch dropped without retries", stats)
+	}
+}
+
+func TestCloudWatchWriter_FlushIntervalAndClose(t *testing.T) {
+	client := &fakeCloudWatch{}
+	w := newTestCloudWatch(t, client, CloudWatchConfig{FlushInterval: 20 * time.Millisecond})
+	_, _ = w.Write([]byte("ticked\n"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.delivered()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(client.delivered()) != 1 {
+		t.Fatal("partial batch not sent after the flush interval")
+	}
+
+	_, _ = w.Write([]byte("at close\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if calls := client.delivered(); len(calls) != 2 || calls[1].Events[0].Message != "at close" {
+		t.Errorf("pending record not sent by Close: %+v", calls)
+	}
+	if _, err := w.Write([]byte("late\n")); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("Write after Close error = %v", err)
+	}
+	_ = w.Sync() // Returns at once
+}
+
+func TestNewCloudWatchWriter_Invalid(t *testing.T) {
+	client := &fakeCloudWatch{}
+	for _, cfg := range []CloudWatchConfig{
+		{LogStream: "s", Client: client},
+		{LogGroup: "g", Client: client},
+		{LogGroup: "g", LogStream: "s", Client: client, MaxBatchEvents: 10001},
+		{LogGroup: "g", LogStream: "s", Client: client, MaxBatchBytes: 2 << 20},
+	} {
+		if _, err := NewCloudWatchWriter(cfg); !IsLoggerError(err, ErrCodeInvalidConfig) {
+			t.Errorf("NewCloudWatchWriter(%+v) error = %v, want ErrCodeInvalidConfig", cfg, err)
+		}
+	}
+}
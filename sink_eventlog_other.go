@@ -0,0 +1,24 @@
+// sink_eventlog_other.go: Event Log sink on platforms other than Windows
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows
+
+package iris
+
+// NewEventLogWriter is only supported on Windows.
+func NewEventLogWriter(cfg EventLogConfig) (*EventLogWriter, error) {
+	return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "the Windows Event Log is only supported on Windows", "source", cfg.Source)
+}
+
+// WriteRecord discards rec on this platform.
+func (w *EventLogWriter) WriteRecord(rec *Record) error {
+	return nil
+}
+
+// Close is a no-op on this platform.
+func (w *EventLogWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,62 @@
+// sink_eventlog_test.go: Tests for the Windows Event Log sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  uint16
+	}{
+		{Debug, eventLogInformation},
+		{Info, eventLogInformation},
+		{Warn, eventLogWarning},
+		{Error, eventLogError},
+		{Fatal, eventLogError},
+	}
+	for _, tt := range tests {
+		if got := eventType(tt.level); got != tt.want {
+			t.Errorf("eventType(%s) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateEventMessage(t *testing.T) {
+	short := "ok"
+	if got := truncateEventMessage(short); got != short {
+		t.Errorf("short message changed: %q", got)
+	}
+	// Each emoji takes two UTF-16 code units
+	long := strings.Repeat("😀", eventLogMaxMessage)
+	got := truncateEventMessage(long)
+	if n := len([]rune(got)); n != eventLogMaxMessage/2 {
+		t.Errorf("truncated to %d runes, want %d", n, eventLogMaxMessage/2)
+	}
+}
+
+func TestEventLogWriter_Message(t *testing.T) {
+	if _, err := eventLogConfig(EventLogConfig{}); !IsLoggerError(err, ErrCodeInvalidOutput) {
+		t.Errorf("eventLogConfig without a source = %v, want %s", err, ErrCodeInvalidOutput)
+	}
+	w, err := eventLogConfig(EventLogConfig{Source: "svc"})
+	if err != nil {
+		t.Fatalf("eventLogConfig failed: %v", err)
+	}
+	if w.eventID != DefaultEventID {
+		t.Errorf("eventID = %d, want %d", w.eventID, DefaultEventID)
+	}
+	rec := NewRecord(Info, "started")
+	rec.AddField(Int("port", 8080))
+	msg := w.message(rec)
+	if !strings.Contains(msg, `"msg":"started"`) || !strings.Contains(msg, `"port":8080`) || strings.HasSuffix(msg, "\n") {
+		t.Errorf("message = %q", msg)
+	}
+}
@@ -0,0 +1,99 @@
+// sink_eventlog_windows.go: Windows Event Log reporting through advapi32
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build windows
+
+package iris
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+)
+
+// NewEventLogWriter opens the event source of cfg on the local computer.
+//
+// The source should be registered beforehand, usually by the installer of
+// the service; events of an unregistered source are still reported, but
+// Event Viewer shows them with a "description cannot be found" preamble.
+//
+// Parameters:
+//   - cfg: Event source, message encoder and event identifier
+//
+// Returns:
+//   - *EventLogWriter: Writer ready for use with WithSyncWriter
+//   - error: ErrCodeInvalidOutput without a source, ErrCodeWriterNotAvailable
+//     if the source cannot be opened
+//
+// Example:
+//
+//	// New-EventLog -LogName Application -Source checkout
+//	events, err := iris.NewEventLogWriter(iris.EventLogConfig{Source: "checkout"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(cfg, iris.WithSyncWriter(events))
+func NewEventLogWriter(cfg EventLogConfig) (*EventLogWriter, error) {
+	w, err := eventLogConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	source, err := syscall.UTF16PtrFromString(w.source)
+	if err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid event source name", "source", w.source)
+	}
+	h, _, callErr := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(source)))
+	if h == 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "failed to open event source: "+callErr.Error(), "source", w.source)
+	}
+	w.handle = h
+	return w, nil
+}
+
+// WriteRecord reports rec as one event.
+func (w *EventLogWriter) WriteRecord(rec *Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "event log writer is closed")
+	}
+	// Event strings are NUL-terminated
+	msg, err := syscall.UTF16PtrFromString(strings.ReplaceAll(w.message(rec), "\x00", " "))
+	if err != nil {
+		w.dropped.Add(1)
+		return nil
+	}
+	strs := [1]*uint16{msg}
+	ok, _, _ := procReportEventW.Call(w.handle,
+		uintptr(eventType(rec.Level)), 0, uintptr(w.eventID),
+		0, 1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+	if ok == 0 {
+		w.dropped.Add(1)
+	}
+	return nil
+}
+
+// Close closes the event source. Subsequent writes fail.
+func (w *EventLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if ok, _, err := procDeregisterEventSource.Call(w.handle); ok == 0 {
+		return err
+	}
+	return nil
+}
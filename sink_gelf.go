@@ -0,0 +1,401 @@
+// sink_gelf.go: GELF transport for Graylog
+//
+// GELFWriter sends each record to a Graylog GELF input. Over UDP a message
+// is compressed (gzip or zlib) and, when larger than one datagram, split
+// into GELF chunks of at most 128 datagrams; over TCP and TLS messages are
+// uncompressed and terminated by a null byte, as Graylog expects. It is a
+// transport only; GELFEncoder produces the messages it carries.
+//
+// Like SyslogSyncer, the writer never stalls the consumer for long: a
+// message that cannot be delivered within the timeout is dropped and
+// counted, the connection is closed, and the writer reconnects at most once
+// per second.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/tls"
+	"encoding/binary"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// GELFCompression selects how UDP messages are compressed.
+type GELFCompression int
+
+const (
+	// GELFCompressGzip compresses UDP messages with gzip (the default)
+	GELFCompressGzip GELFCompression = iota
+
+	// GELFCompressZlib compresses UDP messages with zlib
+	GELFCompressZlib
+
+	// GELFCompressNone sends UDP messages uncompressed, trading bandwidth
+	// for CPU on fast local networks
+	GELFCompressNone
+)
+
+// GELF transport limits.
+const (
+	// DefaultGELFPort is the port of Graylog GELF inputs
+	DefaultGELFPort = 12201
+
+	// DefaultGELFChunkSize is the UDP datagram size when
+	// GELFConfig.ChunkSize is zero, safe on WAN paths
+	DefaultGELFChunkSize = 1420
+
+	// MaxGELFChunkSize is the largest datagram Graylog accepts
+	MaxGELFChunkSize = 8192
+
+	// DefaultGELFTimeout bounds the connection and each write when
+	// GELFConfig.Timeout is zero
+	DefaultGELFTimeout = 5 * time.Second
+
+	// gelfMaxChunks is the number of chunks a message may span
+	gelfMaxChunks = 128
+
+	// gelfChunkHeader is the size of the chunk header: magic bytes,
+	// message id, sequence number and count
+	gelfChunkHeader = 12
+)
+
+// GELFConfig configures a GELFWriter.
+type GELFConfig struct {
+	// Network is "udp" (the default), "tcp" or "tls".
+	Network string
+
+	// Address is host:port of the Graylog input (e.g.
+	// "graylog.example:12201").
+	Address string
+
+	// TLSConfig is used with "tls"; nil verifies the server against the
+	// system roots.
+	TLSConfig *tls.Config
+
+	// Compression of UDP messages (default GELFCompressGzip). Graylog
+	// does not accept compressed messages over TCP.
+	Compression GELFCompression
+
+	// ChunkSize is the largest UDP datagram, chunk header included
+	// (default DefaultGELFChunkSize, at most MaxGELFChunkSize).
+	ChunkSize int
+
+	// Timeout bounds the connection and each write (default
+	// DefaultGELFTimeout).
+	Timeout time.Duration
+}
+
+// GELFWriter writes each record to a Graylog GELF input as one message.
+// Its Write never fails for transient conditions: a message that cannot be
+// delivered, or that needs more than 128 UDP chunks, is dropped and counted
+// in Dropped(), and the connection is re-established at most once per
+// second.
+type GELFWriter struct {
+	network     string
+	address     string
+	tls         *tls.Config
+	compression GELFCompression
+	chunkSize   int
+	timeout     time.Duration
+
+	mu       sync.Mutex
+	conn     net.Conn
+	retryAt  int64 // Cached-clock nanoseconds of the next dial attempt
+	closed   bool
+	payload  bytes.Buffer
+	gzip     *gzip.Writer
+	zlib     *zlib.Writer
+	datagram []byte
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewGELFWriter connects to the Graylog input described by cfg. Use it
+// with GELFEncoder, which produces the GELF messages.
+//
+// Parameters:
+//   - cfg: Input to connect to
+//
+// Returns:
+//   - *GELFWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidOutput for an unknown network, compression or
+//     chunk size, ErrCodeWriterNotAvailable if the input cannot be reached
+//
+// Example:
+//
+//	out, err := iris.NewGELFWriter(iris.GELFConfig{Address: "graylog.example:12201"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer out.Close()
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewGELFEncoder()})
+func NewGELFWriter(cfg GELFConfig) (*GELFWriter, error) {
+	switch cfg.Network {
+	case "":
+		cfg.Network = "udp"
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "tls":
+	default:
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown GELF network (want udp, tcp or tls)", "network", cfg.Network)
+	}
+	if cfg.Compression < GELFCompressGzip || cfg.Compression > GELFCompressNone {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown GELF compression", "compression", strconv.Itoa(int(cfg.Compression)))
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = DefaultGELFChunkSize
+	}
+	if cfg.ChunkSize <= gelfChunkHeader || cfg.ChunkSize > MaxGELFChunkSize {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "GELF chunk size must be between 13 and 8192 bytes", "chunk_size", strconv.Itoa(cfg.ChunkSize))
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultGELFTimeout
+	}
+	w := &GELFWriter{
+		network:     cfg.Network,
+		address:     cfg.Address,
+		tls:         cfg.TLSConfig,
+		compression: cfg.Compression,
+		chunkSize:   cfg.ChunkSize,
+		timeout:     cfg.Timeout,
+	}
+	if err := w.dial(); err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "failed to connect to GELF input: "+err.Error(), "address", w.address)
+	}
+	return w, nil
+}
+
+// ParseGELFURL parses a GELF output URL into a GELFConfig:
+//
+//	gelf://host[:port][?compress=gzip|zlib|none&chunk_size=N&timeout=D]
+//
+// The scheme selects the network: gelf and gelf+udp for UDP, gelf+tcp
+// for TCP and gelf+tls for TLS. The port defaults to DefaultGELFPort.
+//
+// Parameters:
+//   - raw: Output URL (e.g. "gelf://graylog.example:12201")
+//
+// Returns:
+//   - GELFConfig: Configuration for NewGELFWriter
+//   - error: ErrCodeInvalidOutput for a malformed URL or parameter
+func ParseGELFURL(raw string) (GELFConfig, error) {
+	var cfg GELFConfig
+	u, err := url.Parse(raw)
+	if err != nil {
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid GELF URL: "+err.Error(), "output", raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "gelf", "gelf+udp":
+		cfg.Network = "udp"
+	case "gelf+tcp":
+		cfg.Network = "tcp"
+	case "gelf+tls":
+		cfg.Network = "tls"
+	default:
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown GELF scheme (want gelf, gelf+udp, gelf+tcp or gelf+tls)", "output", raw)
+	}
+	if u.Hostname() == "" {
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "GELF URL has no host", "output", raw)
+	}
+	port := u.Port()
+	if port == "" {
+		port = strconv.Itoa(DefaultGELFPort)
+	}
+	cfg.Address = net.JoinHostPort(u.Hostname(), port)
+
+	query := u.Query()
+	switch strings.ToLower(query.Get("compress")) {
+	case "", "gzip":
+	case "zlib":
+		cfg.Compression = GELFCompressZlib
+	case "none":
+		cfg.Compression = GELFCompressNone
+	default:
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown GELF compression (want gzip, zlib or none)", "compress", query.Get("compress"))
+	}
+	if s := query.Get("chunk_size"); s != "" {
+		if cfg.ChunkSize, err = strconv.Atoi(s); err != nil {
+			return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid GELF chunk size", "chunk_size", s)
+		}
+	}
+	if s := query.Get("timeout"); s != "" {
+		if cfg.Timeout, err = time.ParseDuration(s); err != nil {
+			return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid GELF timeout", "timeout", s)
+		}
+	}
+	return cfg, nil
+}
+
+// isGELFOutput reports whether output is a GELF URL.
+func isGELFOutput(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.HasPrefix(lower, "gelf://") || strings.HasPrefix(lower, "gelf+")
+}
+
+// Write sends p, one encoded record, as one message. A trailing newline is
+// removed.
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "GELF writer is closed")
+	}
+	if w.conn == nil {
+		if timecache.CachedTimeNano() < w.retryAt || w.dial() != nil {
+			w.dropped.Add(1)
+			return len(p), nil
+		}
+	}
+
+	msg := bytes.TrimSuffix(p, []byte{'\n'})
+	_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	var err error
+	if strings.HasPrefix(w.network, "udp") {
+		err = w.sendDatagrams(msg)
+	} else {
+		w.payload.Reset()
+		w.payload.Write(msg)
+		w.payload.WriteByte(0)
+		_, err = w.conn.Write(w.payload.Bytes())
+	}
+	if err != nil {
+		if err != errGELFTooLarge {
+			w.disconnect()
+		}
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	w.sent.Add(1)
+	return len(p), nil
+}
+
+// errGELFTooLarge reports a message needing more than gelfMaxChunks chunks.
+var errGELFTooLarge = NewLoggerError(ErrCodeBufferOverflow, "GELF message exceeds 128 chunks")
+
+// sendDatagrams compresses msg and sends it in one datagram, or in GELF
+// chunks when it does not fit. Must be called with w.mu held.
+func (w *GELFWriter) sendDatagrams(msg []byte) error {
+	w.payload.Reset()
+	switch w.compression {
+	case GELFCompressGzip:
+		if w.gzip == nil {
+			w.gzip = gzip.NewWriter(&w.payload)
+		} else {
+			w.gzip.Reset(&w.payload)
+		}
+		_, _ = w.gzip.Write(msg)
+		_ = w.gzip.Close()
+	case GELFCompressZlib:
+		if w.zlib == nil {
+			w.zlib = zlib.NewWriter(&w.payload)
+		} else {
+			w.zlib.Reset(&w.payload)
+		}
+		_, _ = w.zlib.Write(msg)
+		_ = w.zlib.Close()
+	default:
+		w.payload.Write(msg)
+	}
+	data := w.payload.Bytes()
+	if len(data) <= w.chunkSize {
+		_, err := w.conn.Write(data)
+		return err
+	}
+
+	room := w.chunkSize - gelfChunkHeader
+	count := (len(data) + room - 1) / room
+	if count > gelfMaxChunks {
+		return errGELFTooLarge
+	}
+	if cap(w.datagram) < w.chunkSize {
+		w.datagram = make([]byte, w.chunkSize)
+	}
+	id := rand.Uint64()
+	for seq := 0; seq < count; seq++ {
+		part := data[seq*room : min((seq+1)*room, len(data))]
+		chunk := w.datagram[:gelfChunkHeader+len(part)]
+		chunk[0], chunk[1] = 0x1e, 0x0f
+		binary.BigEndian.PutUint64(chunk[2:10], id)
+		chunk[10], chunk[11] = byte(seq), byte(count)
+		copy(chunk[gelfChunkHeader:], part)
+		if _, err := w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync is a no-op: messages are sent as they are written.
+func (w *GELFWriter) Sync() error {
+	return nil
+}
+
+// Dropped returns the number of messages that could not be delivered.
+func (w *GELFWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// DeliveryStats returns the messages written to the connection and those
+// dropped. The transport has no acknowledgements and messages are not
+// retried, so Acked and Retried stay 0.
+func (w *GELFWriter) DeliveryStats() DeliveryStats {
+	return DeliveryStats{Sent: w.sent.Load(), Dropped: w.dropped.Load()}
+}
+
+// Close closes the connection. Subsequent writes fail.
+func (w *GELFWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.conn != nil {
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+// dial connects to the input, scheduling a retry on failure.
+// Must be called with w.mu held (or before the writer is shared).
+func (w *GELFWriter) dial() error {
+	var err error
+	if w.network == "tls" {
+		dialer := &net.Dialer{Timeout: w.timeout}
+		w.conn, err = tls.DialWithDialer(dialer, "tcp", w.address, w.tls)
+	} else {
+		w.conn, err = net.DialTimeout(w.network, w.address, w.timeout)
+	}
+	if err != nil {
+		w.conn = nil
+		w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+	}
+	return err
+}
+
+// disconnect drops the current connection and schedules a reconnect.
+// Must be called with w.mu held.
+func (w *GELFWriter) disconnect() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+}
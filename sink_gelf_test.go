@@ -0,0 +1,318 @@
+// sink_gelf_test.go: Tests for the GELF transport
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenGELFUDP returns a UDP socket standing in for a Graylog input.
+func listenGELFUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+	return pc
+}
+
+// readDatagram returns the next datagram received on pc.
+func readDatagram(t *testing.T, pc net.PacketConn) []byte {
+	t.Helper()
+	buf := make([]byte, MaxGELFChunkSize)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return buf[:n]
+}
+
+// gelfLogger returns an inline logger writing GELF messages to out.
+func gelfLogger(t *testing.T, out WriteSyncer) *Logger {
+	t.Helper()
+	logger, err := New(Config{Level: Info, Output: out, Encoder: &GELFEncoder{Host: "h"}, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger
+}
+
+func TestGELFWriter_UDPCompression(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression GELFCompression
+		decompress  func([]byte) (io.Reader, error)
+	}{
+		{"gzip", GELFCompressGzip, func(b []byte) (io.Reader, error) { return gzip.NewReader(bytes.NewReader(b)) }},
+		{"zlib", GELFCompressZlib, func(b []byte) (io.Reader, error) { return zlib.NewReader(bytes.NewReader(b)) }},
+		{"none", GELFCompressNone, func(b []byte) (io.Reader, error) { return bytes.NewReader(b), nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := listenGELFUDP(t)
+			out, err := NewGELFWriter(GELFConfig{Address: pc.LocalAddr().String(), Compression: tt.compression})
+			if err != nil {
+				t.Fatalf("NewGELFWriter failed: %v", err)
+			}
+			defer func() { _ = out.Close() }()
+			logger := gelfLogger(t, out)
+
+			for i := 0; i < 2; i++ { // The second message reuses the compressor
+				logger.Info("user login", Str("user", "alice"))
+				r, err := tt.decompress(readDatagram(t, pc))
+				if err != nil {
+					t.Fatalf("decompress: %v", err)
+				}
+				var msg map[string]interface{}
+				if err := json.NewDecoder(r).Decode(&msg); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				if msg["short_message"] != "user login" || msg["_user"] != "alice" {
+					t.Errorf("message = %v", msg)
+				}
+			}
+		})
+	}
+}
+
+func TestGELFWriter_Chunking(t *testing.T) {
+	pc := listenGELFUDP(t)
+	out, err := NewGELFWriter(GELFConfig{Address: pc.LocalAddr().String(), Compression: GELFCompressNone, ChunkSize: 112})
+	if err != nil {
+		t.Fatalf("NewGELFWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+	logger := gelfLogger(t, out)
+
+	payload := strings.Repeat("x", 1000)
+	logger.Info("large", Str("payload", payload))
+
+	var parts [][]byte
+	var id uint64
+	count := -1
+	for count < 0 || len(parts) < count {
+		chunk := readDatagram(t, pc)
+		if len(chunk) > 112 || chunk[0] != 0x1e || chunk[1] != 0x0f {
+			t.Fatalf("invalid chunk %q", chunk)
+		}
+		if count < 0 {
+			id, count = binary.BigEndian.Uint64(chunk[2:10]), int(chunk[11])
+			parts = make([][]byte, 0, count)
+		}
+		if binary.BigEndian.Uint64(chunk[2:10]) != id || int(chunk[10]) != len(parts) || int(chunk[11]) != count {
+			t.Fatalf("chunk header % x, want id %x seq %d of %d", chunk[:12], id, len(parts), count)
+		}
+		parts = append(parts, chunk[12:])
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(bytes.Join(parts, nil), &msg); err != nil {
+		t.Fatalf("reassembled message is invalid: %v", err)
+	}
+	if msg["_payload"] != payload {
+		t.Errorf("payload lost in %d chunks", count)
+	}
+
+	// More than 128 chunks are dropped
+	logger.Info("huge", Str("payload", strings.Repeat("x", 128*100)))
+	if out.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", out.Dropped())
+	}
+}
+
+func TestGELFWriter_TCP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = lis.Close() }()
+	got := acceptStream(t, lis)
+
+	// Compression is ignored over TCP
+	out, err := NewGELFWriter(GELFConfig{Network: "tcp", Address: lis.Addr().String(), Compression: GELFCompressZlib})
+	if err != nil {
+		t.Fatalf("NewGELFWriter failed: %v", err)
+	}
+	logger := gelfLogger(t, out)
+	logger.Info("first")
+	logger.Warn("second")
+	_ = logger.Close()
+	_ = out.Close()
+
+	messages := strings.Split(<-got, "\x00")
+	if len(messages) != 3 || messages[2] != "" {
+		t.Fatalf("received %q, want two null-terminated messages", messages)
+	}
+	for i, want := range []string{"first", "second"} {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(messages[i]), &msg); err != nil || msg["short_message"] != want {
+			t.Errorf("message %d = %q (%v)", i, messages[i], err)
+		}
+	}
+}
+
+func TestGELFWriter_Reconnect(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	out, err := NewGELFWriter(GELFConfig{Network: "tcp", Address: addr, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewGELFWriter failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	// The server goes away: writes are dropped, never returned as errors
+	conn, err := lis.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	_ = conn.Close()
+	_ = lis.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for out.Dropped() == 0 && time.Now().Before(deadline) {
+		if _, err := out.Write([]byte("{}\n")); err != nil {
+			t.Fatalf("Write returned %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if out.Dropped() == 0 {
+		t.Fatal("no message dropped after the server closed")
+	}
+
+	// The writer reconnects once the server is back
+	lis, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s again: %v", addr, err)
+	}
+	defer func() { _ = lis.Close() }()
+	got := acceptStream(t, lis)
+	dropped := out.Dropped()
+	for time.Now().Before(deadline.Add(5 * time.Second)) {
+		_, _ = out.Write([]byte(`{"short_message":"back"}` + "\n"))
+		if out.Dropped() == dropped {
+			break
+		}
+		dropped = out.Dropped()
+		time.Sleep(100 * time.Millisecond)
+	}
+	_ = out.Close()
+	if data := <-got; !strings.Contains(data, `{"short_message":"back"}`+"\x00") {
+		t.Errorf("received %q after reconnecting", data)
+	}
+}
+
+func TestNewGELFWriter_Invalid(t *testing.T) {
+	for _, cfg := range []GELFConfig{
+		{Network: "http", Address: "127.0.0.1:12201"},
+		{Address: "127.0.0.1:12201", Compression: GELFCompression(9)},
+		{Address: "127.0.0.1:12201", ChunkSize: 12},
+		{Address: "127.0.0.1:12201", ChunkSize: 9000},
+	} {
+		if _, err := NewGELFWriter(cfg); !IsLoggerError(err, ErrCodeInvalidOutput) {
+			t.Errorf("NewGELFWriter(%+v) error = %v, want ErrCodeInvalidOutput", cfg, err)
+		}
+	}
+}
+
+func TestParseGELFURL(t *testing.T) {
+	cfg, err := ParseGELFURL("gelf://graylog.example")
+	if err != nil {
+		t.Fatalf("ParseGELFURL: %v", err)
+	}
+	if cfg.Network != "udp" || cfg.Address != "graylog.example:12201" || cfg.Compression != GELFCompressGzip {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	cfg, err = ParseGELFURL("gelf+tcp://10.0.0.5:5555?timeout=2s")
+	if err != nil {
+		t.Fatalf("ParseGELFURL: %v", err)
+	}
+	if cfg.Network != "tcp" || cfg.Address != "10.0.0.5:5555" || cfg.Timeout != 2*time.Second {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	cfg, err = ParseGELFURL("gelf://[::1]:12201?compress=none&chunk_size=8192")
+	if err != nil {
+		t.Fatalf("ParseGELFURL: %v", err)
+	}
+	if cfg.Address != "[::1]:12201" || cfg.Compression != GELFCompressNone || cfg.ChunkSize != 8192 {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	for _, raw := range []string{"gelf+http://h", "gelf://", "gelf://h?compress=lz4", "gelf://h?chunk_size=big", "gelf://h?timeout=soon"} {
+		if _, err := ParseGELFURL(raw); !IsLoggerError(err, ErrCodeInvalidOutput) {
+			t.Errorf("ParseGELFURL(%q) error = %v, want ErrCodeInvalidOutput", raw, err)
+		}
+	}
+}
+
+func TestConfigLoaders_GELFOutput(t *testing.T) {
+	pc := listenGELFUDP(t)
+	output := "gelf://" + pc.LocalAddr().String() + "?compress=none"
+
+	path := filepath.Join(t.TempDir(), "iris.json")
+	if err := os.WriteFile(path, []byte(`{"level": "info", "output": "`+output+`"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFromJSON(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromJSON: %v", err)
+	}
+	if _, ok := cfg.Encoder.(*GELFEncoder); !ok {
+		t.Errorf("Encoder = %T, want *GELFEncoder implied by the output", cfg.Encoder)
+	}
+	out, ok := cfg.Output.(*GELFWriter)
+	if !ok {
+		t.Fatalf("Output = %T, want *GELFWriter", cfg.Output)
+	}
+	defer func() { _ = out.Close() }()
+	cfg.Inline = true
+	logger, err := New(*cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("from config")
+	_ = logger.Close()
+	if msg := readDatagram(t, pc); !bytes.Contains(msg, []byte(`"short_message":"from config"`)) {
+		t.Errorf("datagram = %s", msg)
+	}
+
+	t.Setenv("IRIS_FORMAT", "gelf")
+	t.Setenv("IRIS_OUTPUT", output)
+	cfg, err = LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv: %v", err)
+	}
+	if _, ok := cfg.Encoder.(*GELFEncoder); !ok {
+		t.Errorf("Encoder = %T, want *GELFEncoder", cfg.Encoder)
+	}
+	if out, ok := cfg.Output.(*GELFWriter); !ok {
+		t.Errorf("Output = %T, want *GELFWriter", cfg.Output)
+	} else {
+		_ = out.Close()
+	}
+
+	t.Setenv("IRIS_OUTPUT", "gelf://graylog.example?compress=lz4")
+	if _, err := LoadConfigFromEnv(); !IsLoggerError(err, ErrCodeInvalidOutput) {
+		t.Errorf("LoadConfigFromEnv error = %v, want ErrCodeInvalidOutput", err)
+	}
+}
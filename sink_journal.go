@@ -0,0 +1,238 @@
+// sink_journal.go: systemd-journald sink for Iris logging library
+//
+// JournalWriter sends each record to systemd-journald over its native
+// protocol, the one sd_journal_send uses: every field of the record becomes
+// a journal field of its own, so `journalctl ORDER_ID=42` finds it without
+// parsing a message. The level becomes PRIORITY, the message MESSAGE and
+// the logger name SYSLOG_IDENTIFIER unless one is configured.
+//
+// It is a SyncWriter, attached with WithSyncWriter, and only available on
+// Linux; elsewhere NewJournalWriter returns ErrCodeWriterNotAvailable.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultJournalSocket is the native socket of systemd-journald.
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// DefaultJournalTimeout bounds each send when JournalConfig.Timeout is zero.
+const DefaultJournalTimeout = time.Second
+
+// journalMaxFieldName is the longest field name journald accepts.
+const journalMaxFieldName = 64
+
+// JournalConfig configures a JournalWriter.
+type JournalConfig struct {
+	// Identifier is the SYSLOG_IDENTIFIER of every entry. Empty uses the
+	// executable name.
+	Identifier string
+
+	// Socket is the path of the journal socket (default
+	// DefaultJournalSocket).
+	Socket string
+
+	// Fields are added to every entry, e.g. {"UNIT_ROLE": "worker"}.
+	// Names are normalized as record field names are.
+	Fields map[string]string
+
+	// Timeout bounds each send while the journal is backed up (default
+	// DefaultJournalTimeout). Entries not sent in time are dropped.
+	Timeout time.Duration
+}
+
+// JournalWriter sends records to systemd-journald as native entries. It
+// implements SyncWriter; entries that cannot be delivered are dropped and
+// counted in Dropped(). It is safe for concurrent use.
+//
+// Record fields are named as journald requires: letters are uppercased,
+// other characters than A-Z, 0-9 and '_' become '_', leading underscores
+// (reserved for trusted fields) are removed, a leading digit gets an "F"
+// prefix, and names are cut at 64 bytes. The caller becomes CODE_FILE and
+// CODE_LINE, the stack trace STACKTRACE, the level name IRIS_LEVEL and
+// the record time, when set, IRIS_TIME.
+type JournalWriter struct {
+	socket     string
+	identifier string
+	static     []byte // Encoded JournalConfig.Fields
+	timeout    time.Duration
+
+	mu     sync.Mutex
+	fd     int
+	closed bool
+	buf    bytes.Buffer
+
+	dropped atomic.Int64
+}
+
+var _ SyncWriter = (*JournalWriter)(nil)
+
+// Sync is a no-op: entries are sent as they are written.
+func (w *JournalWriter) Sync() error {
+	return nil
+}
+
+// Dropped returns the number of entries that could not be delivered.
+func (w *JournalWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// journalConfig applies the defaults of cfg and returns the writer it
+// describes, without a socket.
+func journalConfig(cfg JournalConfig) *JournalWriter {
+	w := &JournalWriter{
+		socket:     cfg.Socket,
+		identifier: cfg.Identifier,
+		timeout:    cfg.Timeout,
+		fd:         -1,
+	}
+	if w.socket == "" {
+		w.socket = DefaultJournalSocket
+	}
+	if w.identifier == "" {
+		w.identifier = filepath.Base(os.Args[0])
+	}
+	if w.timeout <= 0 {
+		w.timeout = DefaultJournalTimeout
+	}
+	if len(cfg.Fields) > 0 {
+		var static bytes.Buffer
+		for name, value := range cfg.Fields {
+			if key := journalFieldName(name); key != "" {
+				appendJournalField(&static, key, value)
+			}
+		}
+		w.static = static.Bytes()
+	}
+	return w
+}
+
+// encode writes the native protocol entry of rec to buf.
+func (w *JournalWriter) encode(rec *Record, buf *bytes.Buffer) {
+	appendJournalField(buf, "MESSAGE", rec.Msg)
+	appendJournalField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(rec.Level)))
+	appendJournalField(buf, "IRIS_LEVEL", rec.Level.String())
+	appendJournalField(buf, "SYSLOG_IDENTIFIER", w.identifier)
+	if rec.Logger != "" {
+		appendJournalField(buf, "LOGGER", rec.Logger)
+	}
+	if ts := rec.Time(); !ts.IsZero() {
+		appendJournalField(buf, "IRIS_TIME", ts.UTC().Format(time.RFC3339Nano))
+	}
+	if rec.Caller != "" {
+		if i := strings.LastIndexByte(rec.Caller, ':'); i > 0 {
+			appendJournalField(buf, "CODE_FILE", rec.Caller[:i])
+			appendJournalField(buf, "CODE_LINE", rec.Caller[i+1:])
+		} else {
+			appendJournalField(buf, "CODE_FILE", rec.Caller)
+		}
+	}
+	if rec.Stack != "" {
+		appendJournalField(buf, "STACKTRACE", rec.Stack)
+	}
+	buf.Write(w.static)
+
+	var value bytes.Buffer
+	for i := int32(0); i < rec.n; i++ {
+		f := &rec.fields[i]
+		key := journalFieldName(f.K)
+		if key == "" {
+			continue
+		}
+		value.Reset()
+		if !writeJournalValue(&value, f) {
+			continue
+		}
+		appendJournalField(buf, key, value.String())
+	}
+}
+
+// journalFieldName normalizes name to a journal field name, or returns ""
+// if nothing is left of it.
+func journalFieldName(name string) string {
+	name = strings.TrimLeft(name, "_")
+	if name == "" {
+		return ""
+	}
+	b := make([]byte, 0, min(len(name)+1, journalMaxFieldName))
+	if name[0] >= '0' && name[0] <= '9' {
+		b = append(b, 'F')
+	}
+	for i := 0; i < len(name) && len(b) < journalMaxFieldName; i++ {
+		switch c := name[i]; {
+		case c >= 'a' && c <= 'z':
+			b = append(b, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+	return string(b)
+}
+
+// appendJournalField writes one field in the native protocol: KEY=value
+// and a newline, or, for values containing a newline, the key, a newline,
+// the length as a little-endian uint64, the value and a newline.
+func appendJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// writeJournalValue writes the value of f as text and reports whether it
+// has one; sampling markers have none.
+func writeJournalValue(buf *bytes.Buffer, f *Field) bool {
+	switch f.T {
+	case kindString:
+		buf.WriteString(f.Str)
+	case kindSecret:
+		buf.WriteString("[REDACTED]")
+	case kindInt64:
+		writeInt(buf, f.I64)
+	case kindUint64:
+		writeUint(buf, f.U64)
+	case kindFloat64:
+		writeFloat(buf, f.F64, 'g')
+	case kindBool:
+		buf.WriteString(strconv.FormatBool(f.I64 != 0))
+	case kindDur:
+		buf.WriteString(time.Duration(f.I64).String())
+	case kindTime:
+		writeTime(buf, time.Unix(0, f.I64).UTC(), time.RFC3339Nano)
+	case kindBytes:
+		buf.Write(f.B)
+	default:
+		v := f.Value()
+		if v == nil {
+			return false
+		}
+		fmt.Fprint(buf, v)
+	}
+	return true
+}
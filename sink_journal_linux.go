@@ -0,0 +1,107 @@
+// sink_journal_linux.go: systemd-journald native protocol transport
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package iris
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// journalSendBuffer is the socket send buffer requested, as sd_journal
+// does, so bursts do not block on the default buffer size.
+const journalSendBuffer = 8 << 20
+
+// NewJournalWriter creates a JournalWriter sending to the journal socket of
+// cfg. The socket is not connected: each entry is addressed to the path, so
+// a restarted journald is picked up without reconnecting.
+//
+// Parameters:
+//   - cfg: Identifier, socket and static fields of the entries
+//
+// Returns:
+//   - *JournalWriter: Writer ready for use with WithSyncWriter
+//   - error: ErrCodeWriterNotAvailable if the journal socket does not exist
+//
+// Example:
+//
+//	journal, err := iris.NewJournalWriter(iris.JournalConfig{Identifier: "checkout"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(cfg, iris.WithSyncWriter(journal))
+func NewJournalWriter(cfg JournalConfig) (*JournalWriter, error) {
+	w := journalConfig(cfg)
+	if fi, err := os.Stat(w.socket); err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "journal socket not found", "socket", w.socket)
+	}
+	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "failed to create journal socket: "+err.Error(), "socket", w.socket)
+	}
+	tv := syscall.NsecToTimeval(w.timeout.Nanoseconds())
+	_ = syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_SNDTIMEO, &tv)
+	_ = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF, journalSendBuffer)
+	w.fd = fd
+	return w, nil
+}
+
+// WriteRecord sends rec as one journal entry. Entries larger than a
+// datagram are passed to journald in an unlinked file, as sd_journal does.
+func (w *JournalWriter) WriteRecord(rec *Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "journal writer is closed")
+	}
+	w.buf.Reset()
+	w.encode(rec, &w.buf)
+
+	addr := &syscall.SockaddrUnix{Name: w.socket}
+	err := syscall.Sendmsg(w.fd, w.buf.Bytes(), nil, addr, 0)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		err = w.sendFile(addr)
+	}
+	if err != nil {
+		w.dropped.Add(1)
+	}
+	return nil
+}
+
+// sendFile passes the entry in w.buf as a file descriptor.
+// Must be called with w.mu held.
+func (w *JournalWriter) sendFile(addr *syscall.SockaddrUnix) error {
+	f, err := os.CreateTemp("/dev/shm", "iris-journal-")
+	if err != nil {
+		if f, err = os.CreateTemp("", "iris-journal-"); err != nil {
+			return err
+		}
+	}
+	defer func() { _ = f.Close() }()
+	// journald only accepts files without links
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	return syscall.Sendmsg(w.fd, nil, syscall.UnixRights(int(f.Fd())), addr, 0) // #nosec G115 -- fd fits in int
+}
+
+// Close closes the socket. Subsequent writes fail.
+func (w *JournalWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return syscall.Close(w.fd)
+}
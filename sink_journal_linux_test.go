@@ -0,0 +1,109 @@
+// sink_journal_linux_test.go: Tests for the journald native transport
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package iris
+
+import (
+	"bytes"
+	"net"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// listenJournal starts a datagram socket standing in for journald.
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, path
+}
+
+func TestJournalWriter_Send(t *testing.T) {
+	conn, path := listenJournal(t)
+	w, err := NewJournalWriter(JournalConfig{Socket: path, Identifier: "svc"})
+	if err != nil {
+		t.Fatalf("NewJournalWriter failed: %v", err)
+	}
+
+	rec := NewRecord(Error, "payment failed")
+	rec.AddField(Str("order_id", "42"))
+	if err := w.WriteRecord(rec); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	got := string(buf[:n])
+	for _, want := range []string{"MESSAGE=payment failed\n", "PRIORITY=3\n", "ORDER_ID=42\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("entry missing %q:\n%s", want, got)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if err := w.WriteRecord(rec); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("WriteRecord after Close = %v, want %s", err, ErrCodeWriterNotAvailable)
+	}
+}
+
+// TestJournalWriter_LargeEntry checks that entries above the datagram
+// size are passed as an unlinked file descriptor.
+func TestJournalWriter_LargeEntry(t *testing.T) {
+	conn, path := listenJournal(t)
+	w, err := NewJournalWriter(JournalConfig{Socket: path})
+	if err != nil {
+		t.Fatalf("NewJournalWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	large := strings.Repeat("x", 16<<20) // Above any datagram send buffer
+	if err := w.WriteRecord(NewRecord(Info, large)); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, oobn, _, _, err := conn.ReadMsgUnix(nil, oob)
+	if err != nil {
+		t.Fatalf("ReadMsgUnix failed: %v", err)
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("control messages = %v, %v", msgs, err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("rights = %v, %v", fds, err)
+	}
+	defer func() { _ = syscall.Close(fds[0]) }()
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(fds[0], &st); err != nil || st.Nlink != 0 {
+		t.Errorf("passed file: nlink %d, err %v; want an unlinked file", st.Nlink, err)
+	}
+	data := make([]byte, 64)
+	if _, err := syscall.Pread(fds[0], data, 0); err != nil || !bytes.HasPrefix(data, []byte("MESSAGE=xxx")) {
+		t.Errorf("passed file starts with %q, %v", data, err)
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("Dropped() = %d, want 0", w.Dropped())
+	}
+}
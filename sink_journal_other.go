@@ -0,0 +1,24 @@
+// sink_journal_other.go: Journal sink on platforms without systemd-journald
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package iris
+
+// NewJournalWriter is only supported on Linux.
+func NewJournalWriter(cfg JournalConfig) (*JournalWriter, error) {
+	return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "systemd-journald is only supported on Linux", "socket", cfg.Socket)
+}
+
+// WriteRecord discards rec on this platform.
+func (w *JournalWriter) WriteRecord(rec *Record) error {
+	return nil
+}
+
+// Close is a no-op on this platform.
+func (w *JournalWriter) Close() error {
+	return nil
+}
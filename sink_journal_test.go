@@ -0,0 +1,81 @@
+// sink_journal_test.go: Tests for the systemd-journald sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJournalFieldName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"order_id", "ORDER_ID"},
+		{"http.status-code", "HTTP_STATUS_CODE"},
+		{"__trusted", "TRUSTED"},
+		{"2fa", "F2FA"},
+		{"___", ""},
+		{strings.Repeat("k", 80), strings.Repeat("K", 64)},
+	}
+	for _, tt := range tests {
+		if got := journalFieldName(tt.name); got != tt.want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAppendJournalField(t *testing.T) {
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", "hello")
+	if got := buf.String(); got != "MESSAGE=hello\n" {
+		t.Errorf("simple field = %q", got)
+	}
+
+	buf.Reset()
+	appendJournalField(&buf, "STACKTRACE", "a\nb")
+	want := []byte("STACKTRACE\n")
+	want = binary.LittleEndian.AppendUint64(want, 3)
+	want = append(want, "a\nb\n"...)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("multi-line field = %q, want %q", buf.Bytes(), want)
+	}
+}
+
+func TestJournalWriter_Encode(t *testing.T) {
+	w := journalConfig(JournalConfig{Identifier: "svc", Fields: map[string]string{"role": "worker"}})
+	rec := NewRecord(Warn, "disk low")
+	rec.Logger = "storage"
+	rec.Caller = "disk.go:42"
+	rec.AddField(Int("free_mb", 12))
+	rec.AddField(Secret("token", "hunter2"))
+	rec.AddField(NamedErr("err", errors.New("quota")))
+
+	var buf bytes.Buffer
+	w.encode(rec, &buf)
+	got := buf.String()
+	for _, want := range []string{
+		"MESSAGE=disk low\n", "PRIORITY=4\n", "IRIS_LEVEL=warn\n", "SYSLOG_IDENTIFIER=svc\n",
+		"LOGGER=storage\n", "CODE_FILE=disk.go\n", "CODE_LINE=42\n", "ROLE=worker\n",
+		"FREE_MB=12\n", "TOKEN=[REDACTED]\n", "ERR=quota\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("entry missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestNewJournalWriter_NoSocket(t *testing.T) {
+	_, err := NewJournalWriter(JournalConfig{Socket: t.TempDir() + "/missing"})
+	if !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("NewJournalWriter without a socket = %v, want %s", err, ErrCodeWriterNotAvailable)
+	}
+}
@@ -0,0 +1,95 @@
+// sink_js.go: Browser console sink for GOOS=js/GOARCH=wasm builds
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build js && wasm
+
+package iris
+
+import (
+	"bytes"
+	"sync"
+	"syscall/js"
+)
+
+// fileSyncSupported reports whether *os.File.Sync is meaningful on this
+// platform. The js/wasm runtime has no fsync; Sync on stdout/stderr fails.
+const fileSyncSupported = false
+
+// jsConsoleSyncer writes each complete log line to the JavaScript console.
+type jsConsoleSyncer struct {
+	mu      sync.Mutex
+	console js.Value
+	pending bytes.Buffer // Partial line waiting for its newline
+}
+
+// NewJSConsoleSyncer returns a WriteSyncer that forwards every log line to
+// the JavaScript console.log function.
+//
+// Each encoded record becomes one console.log call with the trailing newline
+// removed, so browser devtools show one entry per record. Partial writes are
+// buffered until a newline arrives; Sync flushes any remainder.
+//
+// On platforms other than GOOS=js/GOARCH=wasm this returns a stdout writer,
+// so code shared between front-end and back-end builds compiles unchanged.
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{
+//	    Output: iris.NewJSConsoleSyncer(),
+//	    Inline: true, // no background goroutine in the browser
+//	})
+func NewJSConsoleSyncer() WriteSyncer {
+	return &jsConsoleSyncer{console: js.Global().Get("console")}
+}
+
+// defaultOutput returns the default log destination for this platform.
+func defaultOutput() WriteSyncer {
+	return NewJSConsoleSyncer()
+}
+
+// Write implements io.Writer, emitting one console.log call per line.
+func (s *jsConsoleSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := p
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			s.pending.Write(data)
+			break
+		}
+		if s.pending.Len() > 0 {
+			s.pending.Write(data[:idx])
+			s.emit(s.pending.String())
+			s.pending.Reset()
+		} else {
+			s.emit(string(data[:idx]))
+		}
+		data = data[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Sync flushes a pending partial line, if any.
+func (s *jsConsoleSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending.Len() > 0 {
+		s.emit(s.pending.String())
+		s.pending.Reset()
+	}
+	return nil
+}
+
+// emit calls console.log with a single line.
+func (s *jsConsoleSyncer) emit(line string) {
+	if s.console.IsUndefined() || s.console.IsNull() {
+		return
+	}
+	s.console.Call("log", line)
+}
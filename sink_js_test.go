@@ -0,0 +1,67 @@
+// sink_js_test.go: Tests for the browser console sink (js/wasm only)
+//
+// Run with: GOOS=js GOARCH=wasm go test -exec="$(go env GOROOT)/lib/wasm/go_js_wasm_exec" -run JSConsole .
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build js && wasm
+
+package iris
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+// captureConsole replaces console.log with a recorder for the test duration.
+func captureConsole(t *testing.T) *[]string {
+	t.Helper()
+	var lines []string
+	console := js.Global().Get("console")
+	original := console.Get("log")
+	fn := js.FuncOf(func(this js.Value, args []js.Value) any {
+		lines = append(lines, args[0].String())
+		return nil
+	})
+	console.Set("log", fn)
+	t.Cleanup(func() {
+		console.Set("log", original)
+		fn.Release()
+	})
+	return &lines
+}
+
+func TestJSConsoleSyncer_LinePerCall(t *testing.T) {
+	lines := captureConsole(t)
+	ws := NewJSConsoleSyncer()
+
+	_, _ = ws.Write([]byte("first\nsec"))
+	_, _ = ws.Write([]byte("ond\nthird"))
+	if len(*lines) != 2 || (*lines)[0] != "first" || (*lines)[1] != "second" {
+		t.Fatalf("unexpected console lines: %q", *lines)
+	}
+
+	if err := ws.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(*lines) != 3 || (*lines)[2] != "third" {
+		t.Fatalf("expected partial line flushed on Sync, got %q", *lines)
+	}
+}
+
+func TestJSConsoleSyncer_Logger(t *testing.T) {
+	lines := captureConsole(t)
+	logger, err := New(Config{Level: Info, Output: NewJSConsoleSyncer(), Encoder: NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("hello wasm")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(*lines) != 1 {
+		t.Fatalf("expected one console line, got %q", *lines)
+	}
+}
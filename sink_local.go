@@ -0,0 +1,100 @@
+// sink_local.go: FIFO and unix datagram socket sinks for Iris logging library
+//
+// Local collectors (systemd-journald, vector, fluent-bit, custom agents)
+// commonly accept logs on a named pipe or a unix datagram socket. Writing to
+// them directly avoids a TCP loopback hop and the framing it requires.
+//
+// Both writers are non-blocking: if the collector is slow, absent or has
+// restarted, records are dropped and counted instead of stalling the
+// logger's consumer goroutine. The writers reconnect on their own once the
+// collector is back.
+//
+// Supported on unix platforms; on others the constructors return an
+// ErrCodeWriterNotAvailable error.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// localSinkRetryInterval is the minimum delay between reconnection attempts
+// after the collector went away.
+const localSinkRetryInterval = time.Second
+
+// FIFOWriter writes records to a POSIX named pipe without blocking.
+//
+// Records up to PIPE_BUF bytes (at least 512, 4096 on Linux) are written
+// atomically, so several processes may share one FIFO. Larger records can be
+// split by the kernel when the pipe is nearly full; they are counted as
+// dropped if not written in full.
+//
+// If no process has the FIFO open for reading, records are dropped until a
+// reader appears.
+type FIFOWriter struct {
+	path string
+
+	mu      sync.Mutex
+	fd      int   // -1 while disconnected
+	retryAt int64 // Cached-clock nanoseconds of the next open attempt
+	closed  bool
+
+	dropped atomic.Int64
+}
+
+// UnixDatagramWriter sends each record as one SOCK_DGRAM datagram to a unix
+// socket, without blocking.
+//
+// Datagram sockets preserve record boundaries, so no framing is added.
+// Records that do not fit in a single datagram (EMSGSIZE), that find the
+// receiver's queue full, or that are sent while the receiver is down are
+// dropped and counted.
+type UnixDatagramWriter struct {
+	path string
+
+	mu      sync.Mutex
+	conn    *net.UnixConn // nil while disconnected
+	retryAt int64         // Cached-clock nanoseconds of the next dial attempt
+	closed  bool
+
+	dropped atomic.Int64
+}
+
+// Dropped returns the number of records dropped because the FIFO was full,
+// had no reader, or accepted only part of a record.
+func (w *FIFOWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Sync is a no-op: data written to a pipe is immediately visible to the reader.
+func (w *FIFOWriter) Sync() error {
+	return nil
+}
+
+// Path returns the FIFO path.
+func (w *FIFOWriter) Path() string {
+	return w.path
+}
+
+// Dropped returns the number of records dropped because the socket was
+// full, the receiver was unavailable, or the record was too large.
+func (w *UnixDatagramWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Sync is a no-op: datagrams are delivered when sent.
+func (w *UnixDatagramWriter) Sync() error {
+	return nil
+}
+
+// Path returns the socket path.
+func (w *UnixDatagramWriter) Path() string {
+	return w.path
+}
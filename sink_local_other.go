@@ -0,0 +1,39 @@
+// sink_local_other.go: FIFO and datagram socket sinks on non-unix platforms
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !unix
+
+package iris
+
+// NewFIFOWriter is not supported on this platform.
+func NewFIFOWriter(path string) (*FIFOWriter, error) {
+	return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "FIFO sinks are only supported on unix platforms", "path", path)
+}
+
+// Write always fails: FIFO writers cannot be created on this platform.
+func (w *FIFOWriter) Write([]byte) (int, error) {
+	return 0, NewLoggerError(ErrCodeWriterNotAvailable, "FIFO sinks are only supported on unix platforms")
+}
+
+// Close is a no-op on this platform.
+func (w *FIFOWriter) Close() error {
+	return nil
+}
+
+// NewUnixDatagramWriter is not supported on this platform.
+func NewUnixDatagramWriter(path string) (*UnixDatagramWriter, error) {
+	return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "unix datagram sinks are only supported on unix platforms", "path", path)
+}
+
+// Write always fails: datagram writers cannot be created on this platform.
+func (w *UnixDatagramWriter) Write([]byte) (int, error) {
+	return 0, NewLoggerError(ErrCodeWriterNotAvailable, "unix datagram sinks are only supported on unix platforms")
+}
+
+// Close is a no-op on this platform.
+func (w *UnixDatagramWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,248 @@
+// sink_local_unix.go: Unix implementation of the FIFO and datagram socket sinks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package iris
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/agilira/go-timecache"
+)
+
+// NewFIFOWriter creates a non-blocking writer for the named pipe at path.
+//
+// The FIFO must already exist (see mkfifo(1)). It is fine for no reader to
+// be attached yet: records are dropped until one is, and the writer retries
+// opening the FIFO at most once per second.
+//
+// Write never blocks and never fails for transient conditions; it reports
+// len(p) and counts the record in Dropped() when it could not be delivered.
+//
+// Parameters:
+//   - path: Path of an existing FIFO
+//
+// Returns:
+//   - *FIFOWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeFileOpen if path does not exist, ErrCodeInvalidOutput if it is not a FIFO
+//
+// Example:
+//
+//	out, err := iris.NewFIFOWriter("/run/vector/app.fifo")
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewFIFOWriter(path string) (*FIFOWriter, error) {
+	cleanPath := filepath.Clean(path)
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeFileOpen, "failed to stat FIFO: "+err.Error(), "path", cleanPath)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "path is not a FIFO", "path", cleanPath)
+	}
+
+	w := &FIFOWriter{path: cleanPath, fd: -1}
+	w.open() // A missing reader is not an error
+	return w, nil
+}
+
+// Write sends p to the FIFO, dropping it if the pipe is full or has no reader.
+func (w *FIFOWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "FIFO writer is closed")
+	}
+	if w.fd < 0 {
+		if timecache.CachedTimeNano() < w.retryAt {
+			w.dropped.Add(1)
+			return len(p), nil
+		}
+		w.open()
+		if w.fd < 0 {
+			w.dropped.Add(1)
+			return len(p), nil
+		}
+	}
+
+	n, err := writeNoIntr(w.fd, p)
+	if err != nil {
+		if errors.Is(err, syscall.EPIPE) {
+			// Reader went away; reopen once a new one may have attached
+			_ = syscall.Close(w.fd)
+			w.fd = -1
+			w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+		}
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	if n < len(p) {
+		w.dropped.Add(1) // Partial record: the reader sees a truncated line
+	}
+	return len(p), nil
+}
+
+// Close closes the FIFO. Subsequent writes fail.
+func (w *FIFOWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.fd >= 0 {
+		err := syscall.Close(w.fd)
+		w.fd = -1
+		return err
+	}
+	return nil
+}
+
+// open attempts a non-blocking open; it fails with ENXIO while no reader is attached.
+// Must be called with w.mu held (or before the writer is shared).
+func (w *FIFOWriter) open() {
+	fd, err := syscall.Open(w.path, syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+		return
+	}
+	w.fd = fd
+}
+
+// NewUnixDatagramWriter connects a non-blocking SOCK_DGRAM writer to the unix
+// socket at path.
+//
+// The socket must be accepting datagrams when the writer is created. If the
+// receiver later goes away or is restarted, records are dropped and the
+// writer reconnects at most once per second.
+//
+// Write never blocks and never fails for transient conditions; it reports
+// len(p) and counts the record in Dropped() when it could not be delivered.
+//
+// Parameters:
+//   - path: Path of a unix datagram socket
+//
+// Returns:
+//   - *UnixDatagramWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeWriterNotAvailable if the socket cannot be reached
+//
+// Example:
+//
+//	out, err := iris.NewUnixDatagramWriter("/run/vector/app.sock")
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewUnixDatagramWriter(path string) (*UnixDatagramWriter, error) {
+	w := &UnixDatagramWriter{path: path}
+	if err := w.dial(); err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "failed to connect unix datagram socket: "+err.Error(), "path", path)
+	}
+	return w, nil
+}
+
+// Write sends p as a single datagram, dropping it if it cannot be sent immediately.
+func (w *UnixDatagramWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "unix datagram writer is closed")
+	}
+	if w.conn == nil {
+		if timecache.CachedTimeNano() < w.retryAt || w.dial() != nil {
+			w.dropped.Add(1)
+			return len(p), nil
+		}
+	}
+
+	rc, err := w.conn.SyscallConn()
+	if err != nil {
+		w.disconnect()
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	var werr error
+	err = rc.Write(func(fd uintptr) bool {
+		// #nosec G115 - file descriptors fit in an int
+		_, werr = writeNoIntr(int(fd), p)
+		return true // Never wait for the socket to become writable
+	})
+	if err == nil {
+		err = werr
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.ENOBUFS), errors.Is(err, syscall.EMSGSIZE):
+			// Receiver queue full or record too large: drop this record only
+		default:
+			// Receiver gone (ECONNREFUSED, ENOENT, ...): reconnect later
+			w.disconnect()
+		}
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Close closes the socket. Subsequent writes fail.
+func (w *UnixDatagramWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.conn != nil {
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+// dial connects to the socket, scheduling a retry on failure.
+// Must be called with w.mu held (or before the writer is shared).
+func (w *UnixDatagramWriter) dial() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: w.path, Net: "unixgram"})
+	if err != nil {
+		w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// disconnect drops the current connection and schedules a reconnect.
+// Must be called with w.mu held.
+func (w *UnixDatagramWriter) disconnect() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	w.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+}
+
+// writeNoIntr performs a single write system call, retrying only on EINTR
+// (which guarantees nothing was written).
+func writeNoIntr(fd int, p []byte) (int, error) {
+	for {
+		n, err := syscall.Write(fd, p)
+		if err != syscall.EINTR {
+			if n < 0 {
+				n = 0
+			}
+			return n, err
+		}
+	}
+}
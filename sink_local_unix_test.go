@@ -0,0 +1,233 @@
+// sink_local_unix_test.go: Tests for the FIFO and unix datagram socket sinks
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package iris
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/agilira/go-errors"
+)
+
+// shortTempDir returns a temp directory whose paths fit in a sockaddr_un
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "iris")
+	if err != nil {
+		t.Fatalf("MkdirTemp failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+func TestNewFIFOWriter_Validation(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regular, nil, 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		code errors.ErrorCode
+	}{
+		{"missing", filepath.Join(dir, "missing"), ErrCodeFileOpen},
+		{"not a fifo", regular, ErrCodeInvalidOutput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFIFOWriter(tt.path)
+			if !IsLoggerError(err, tt.code) {
+				t.Errorf("expected %s error, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestFIFOWriter_DeliveryAndDrops(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.fifo")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("mkfifo not available: %v", err)
+	}
+
+	w, err := NewFIFOWriter(path)
+	if err != nil {
+		t.Fatalf("NewFIFOWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	// No reader yet: dropped, not blocked
+	if n, err := w.Write([]byte("lost\n")); err != nil || n != 5 {
+		t.Errorf("Write without reader = %d, %v", n, err)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 drop without reader, got %d", w.Dropped())
+	}
+
+	reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("open reader failed: %v", err)
+	}
+
+	w.mu.Lock()
+	w.retryAt = 0 // Skip the reconnect delay
+	w.mu.Unlock()
+
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	buf := make([]byte, 64)
+	n, err := reader.Read(buf)
+	if err != nil || string(buf[:n]) != "hello\n" {
+		t.Errorf("reader got %q, %v", buf[:n], err)
+	}
+
+	// A full pipe drops instead of blocking
+	record := []byte(strings.Repeat("x", 1023) + "\n")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1024; i++ {
+			_, _ = w.Write(record)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write blocked on a full FIFO")
+	}
+	if w.Dropped() <= 1 {
+		t.Errorf("expected drops on a full FIFO, got %d", w.Dropped())
+	}
+
+	_ = reader.Close()
+	before := w.Dropped()
+	_, _ = w.Write([]byte("reader gone\n"))
+	if w.Dropped() != before+1 {
+		t.Error("expected a drop after the reader closed")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, err := w.Write([]byte("x")); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("expected ErrCodeWriterNotAvailable after Close, got %v", err)
+	}
+}
+
+func TestUnixDatagramWriter_Delivery(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "s.sock")
+
+	if _, err := NewUnixDatagramWriter(path); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("expected ErrCodeWriterNotAvailable without receiver, got %v", err)
+	}
+
+	receiver, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+
+	w, err := NewUnixDatagramWriter(path)
+	if err != nil {
+		t.Fatalf("NewUnixDatagramWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	logger, err := New(Config{
+		Level:    Info,
+		Output:   w,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 64,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	logger.Info("first datagram")
+	logger.Info("second datagram")
+	safeCloseWithOptionsLogger(t, logger)
+
+	_ = receiver.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 4096)
+	for _, want := range []string{"first datagram", "second datagram"} {
+		n, err := receiver.Read(buf)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		// Each datagram carries exactly one record
+		got := string(buf[:n])
+		if !strings.Contains(got, want) || strings.Count(got, "\n") != 1 {
+			t.Errorf("unexpected datagram %q", got)
+		}
+	}
+
+	// Receiver gone: drop without blocking, then reconnect once it returns
+	_ = receiver.Close()
+	_ = os.Remove(path)
+	_, _ = w.Write([]byte("lost\n"))
+	if w.Dropped() == 0 {
+		t.Error("expected a drop while the receiver is down")
+	}
+
+	receiver, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram failed: %v", err)
+	}
+	defer func() { _ = receiver.Close() }()
+
+	w.mu.Lock()
+	w.retryAt = 0 // Skip the reconnect delay
+	w.mu.Unlock()
+
+	_, _ = w.Write([]byte("back\n"))
+	_ = receiver.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := receiver.Read(buf)
+	if err != nil || string(buf[:n]) != "back\n" {
+		t.Errorf("expected delivery after reconnect, got %q, %v", buf[:n], err)
+	}
+}
+
+func TestUnixDatagramWriter_FullQueueDrops(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "q.sock")
+	receiver, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer func() { _ = receiver.Close() }()
+
+	w, err := NewUnixDatagramWriter(path)
+	if err != nil {
+		t.Fatalf("NewUnixDatagramWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	// Nobody reads: the receive queue fills and writes must drop, not block
+	record := []byte(strings.Repeat("y", 512))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5000; i++ {
+			_, _ = w.Write(record)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write blocked on a full socket")
+	}
+	if w.Dropped() == 0 {
+		t.Error("expected drops once the receive queue is full")
+	}
+}
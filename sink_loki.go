@@ -0,0 +1,845 @@
+// sink_loki.go: Grafana Loki push transport
+//
+// LokiWriter ships records to the Loki push API (/loki/api/v1/push)
+// without a second module. Records are grouped into streams by their
+// labels: static ones from the configuration, plus the values of chosen
+// record fields, the level or the logger name. Writes only append to the
+// current batch; a background goroutine pushes batches, gzip-compressed,
+// when they reach MaxBatchBytes or when FlushInterval elapses.
+//
+// Rate limited (429), 5xx and network failures are retried with
+// exponential backoff and jitter; batches Loki refuses with another 4xx
+// status, that still fail after the retries, or that find the queue full
+// are dropped and counted in Stats().
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Loki push API.
+const (
+	// LokiPushPath is the path of the push endpoint, appended to
+	// LokiConfig.URL unless it already ends with it
+	LokiPushPath = "/loki/api/v1/push"
+
+	// LokiTenantHeader carries the tenant of multi-tenant deployments
+	LokiTenantHeader = "X-Scope-OrgID"
+
+	// lokiEntryOverhead approximates the JSON framing of one entry
+	// (timestamp, quotes, brackets) when sizing batches
+	lokiEntryOverhead = 32
+)
+
+// LokiWriter defaults.
+const (
+	DefaultLokiFlushInterval = time.Second
+	DefaultLokiMaxBatchBytes = 1 << 20
+	DefaultLokiMaxRetries    = 5
+	DefaultLokiMinBackoff    = 500 * time.Millisecond
+	DefaultLokiMaxBackoff    = 30 * time.Second
+	DefaultLokiQueueSize     = 4
+	DefaultLokiTimeout       = 10 * time.Second
+
+	// DefaultLokiJob is the "job" label of writers configured without
+	// static labels; Loki rejects streams without labels
+	DefaultLokiJob = "iris"
+)
+
+// LokiConfig configures a LokiWriter.
+type LokiConfig struct {
+	// URL is the Loki base URL (e.g. "http://loki:3100"), or the full
+	// push URL. Required.
+	URL string
+
+	// Labels are attached to every stream (default {job="iris"}).
+	Labels map[string]string
+
+	// LabelFields name the record fields whose values become labels;
+	// "level" and "logger" select the level and the logger name. Keys
+	// are turned into valid label names ("http.method" becomes
+	// "http_method"). Every distinct value creates a stream in Loki:
+	// choose fields with few values.
+	LabelFields []string
+
+	// TenantID is sent as X-Scope-OrgID when set.
+	TenantID string
+
+	// Username and Password enable basic authentication (Grafana Cloud:
+	// the instance ID and an access token).
+	Username string
+	Password string
+
+	// Headers are added to every push request.
+	Headers map[string]string
+
+	// Encoder renders the log lines of records given to WriteRecord
+	// (default NewJSONEncoder()). Write sends its input as is.
+	Encoder Encoder
+
+	// DisableCompression sends batches without gzip.
+	DisableCompression bool
+
+	// FlushInterval bounds how long a record waits in a partial batch
+	// (default DefaultLokiFlushInterval).
+	FlushInterval time.Duration
+
+	// MaxBatchBytes caps the size of a batch before compression (default
+	// DefaultLokiMaxBatchBytes). Larger records are sent alone.
+	MaxBatchBytes int
+
+	// MaxRetries bounds the attempts after the first one for a rate
+	// limited or failing batch (default DefaultLokiMaxRetries; negative
+	// disables retries).
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries (defaults DefaultLokiMinBackoff and DefaultLokiMaxBackoff).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// QueueSize is the number of full batches waiting to be sent before
+	// new ones are dropped (default DefaultLokiQueueSize).
+	QueueSize int
+
+	// Timeout bounds each push request (default DefaultLokiTimeout).
+	Timeout time.Duration
+
+	// HTTPClient sends the requests (default: a client of its own).
+	HTTPClient *http.Client
+}
+
+// lokiStream is one stream of a push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"` // Nanosecond timestamp and line
+}
+
+// lokiBatch is a push request being filled.
+type lokiBatch struct {
+	streams []*lokiStream
+	index   map[string]*lokiStream // By stream key
+	entries int
+	bytes   int
+}
+
+// LokiWriter pushes records to Grafana Loki in batches. It is both a
+// WriteSyncer, for Config.Output and WithOutput, and a SyncWriter, for
+// WithSyncWriter: Sync sends the pending records and waits for the queued
+// batches. Close flushes and stops the sender; call it before exiting,
+// after closing the logger.
+type LokiWriter struct {
+	pushURL     string
+	header      http.Header
+	client      *http.Client
+	static      map[string]string
+	staticKey   string
+	labelFields map[string]string // Field key to label name
+	enc         Encoder
+	compress    bool
+	maxBytes    int
+	maxRetries  int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	timeout     time.Duration
+
+	mu     sync.Mutex
+	batch  *lokiBatch
+	buf    bytes.Buffer // WriteRecord encoding
+	closed bool
+
+	queue   chan *lokiBatch
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	body    bytes.Buffer // Owned by the sender goroutine
+	zw      *gzip.Writer // Owned by the sender goroutine
+
+	batchesSent    atomic.Int64
+	batchesDropped atomic.Int64
+	entriesSent    atomic.Int64
+	entriesDropped atomic.Int64
+	retries        atomic.Int64
+	throttled      atomic.Int64
+}
+
+var (
+	_ WriteSyncer = (*LokiWriter)(nil)
+	_ SyncWriter  = (*LokiWriter)(nil)
+)
+
+// NewLokiWriter starts a writer pushing to the Loki instance described by
+// cfg.
+//
+// Parameters:
+//   - cfg: Endpoint, labels, tenant and batching settings
+//
+// Returns:
+//   - *LokiWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidConfig for a missing or invalid URL, or an
+//     empty label name
+//
+// Example:
+//
+//	out, err := iris.NewLokiWriter(iris.LokiConfig{
+//	    URL:         "http://loki:3100",
+//	    Labels:      map[string]string{"job": "checkout", "env": "prod"},
+//	    LabelFields: []string{"level"},
+//	    TenantID:    "team-payments",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer out.Close()
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewLokiWriter(cfg LokiConfig) (*LokiWriter, error) {
+	pushURL, err := lokiPushURL(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	static := make(map[string]string, max(len(cfg.Labels), 1))
+	for name, value := range cfg.Labels {
+		label := lokiLabelName(name)
+		if label == "" {
+			return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "Loki label names cannot be empty", "labels", name)
+		}
+		static[label] = value
+	}
+	if len(static) == 0 {
+		static["job"] = DefaultLokiJob
+	}
+	labelFields := make(map[string]string, len(cfg.LabelFields))
+	for _, key := range cfg.LabelFields {
+		label := lokiLabelName(key)
+		if label == "" {
+			return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "Loki label fields cannot be empty", "label_fields", key)
+		}
+		labelFields[key] = label
+	}
+
+	header := make(http.Header)
+	for name, value := range cfg.Headers {
+		header.Set(name, value)
+	}
+	header.Set("Content-Type", "application/json")
+	if !cfg.DisableCompression {
+		header.Set("Content-Encoding", "gzip")
+	}
+	if cfg.TenantID != "" {
+		header.Set(LokiTenantHeader, cfg.TenantID)
+	}
+	if cfg.Username != "" || cfg.Password != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+		header.Set("Authorization", "Basic "+auth)
+	}
+
+	if cfg.Encoder == nil {
+		cfg.Encoder = NewJSONEncoder()
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{}
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultLokiFlushInterval
+	}
+	if cfg.MaxBatchBytes <= 0 {
+		cfg.MaxBatchBytes = DefaultLokiMaxBatchBytes
+	}
+	switch {
+	case cfg.MaxRetries == 0:
+		cfg.MaxRetries = DefaultLokiMaxRetries
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultLokiMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(DefaultLokiMaxBackoff, cfg.MinBackoff)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultLokiQueueSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultLokiTimeout
+	}
+
+	w := &LokiWriter{
+		pushURL:     pushURL,
+		header:      header,
+		client:      cfg.HTTPClient,
+		static:      static,
+		staticKey:   lokiStreamKey(static),
+		labelFields: labelFields,
+		enc:         cfg.Encoder,
+		compress:    !cfg.DisableCompression,
+		maxBytes:    cfg.MaxBatchBytes,
+		maxRetries:  cfg.MaxRetries,
+		minBackoff:  cfg.MinBackoff,
+		maxBackoff:  cfg.MaxBackoff,
+		timeout:     cfg.Timeout,
+		batch:       newLokiBatch(),
+		queue:       make(chan *lokiBatch, cfg.QueueSize),
+		flushes:     make(chan chan struct{}),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go w.run(cfg.FlushInterval)
+	return w, nil
+}
+
+// ParseLokiURL parses a Loki output URL, as accepted by the "output"
+// setting of the configuration loaders:
+//
+//	loki://host:port/path?tenant=...&labels=...&label_fields=...
+//
+// The scheme selects the transport: loki and loki+http push over HTTP,
+// loki+https over HTTPS. The path, empty by default, prefixes the push
+// path, and user information becomes basic authentication. The query
+// parameters are tenant (X-Scope-OrgID), labels (comma-separated
+// name=value pairs), label_fields (comma-separated field names), compress
+// (gzip or none), flush_interval and timeout (durations).
+//
+// Parameters:
+//   - raw: Output URL
+//
+// Returns:
+//   - LokiConfig: Writer configuration
+//   - error: ErrCodeInvalidOutput for a malformed URL, an unknown scheme
+//     or an invalid parameter
+//
+// Example:
+//
+//	cfg, err := iris.ParseLokiURL("loki://loki:3100?tenant=payments&labels=job=checkout,env=prod&label_fields=level")
+func ParseLokiURL(raw string) (LokiConfig, error) {
+	var cfg LokiConfig
+	u, err := url.Parse(raw)
+	if err != nil {
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid Loki URL: "+err.Error(), "output", raw)
+	}
+	scheme := "http"
+	switch strings.ToLower(u.Scheme) {
+	case "loki", "loki+http":
+	case "loki+https":
+		scheme = "https"
+	default:
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown Loki scheme (want loki, loki+http or loki+https)", "output", raw)
+	}
+	if u.Hostname() == "" {
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "Loki URL has no host", "output", raw)
+	}
+	if u.User != nil {
+		cfg.Username = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+	cfg.URL = (&url.URL{Scheme: scheme, Host: u.Host, Path: u.Path}).String()
+
+	query := u.Query()
+	cfg.TenantID = query.Get("tenant")
+	if s := query.Get("labels"); s != "" {
+		cfg.Labels = make(map[string]string)
+		for _, pair := range strings.Split(s, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			name = strings.TrimSpace(name)
+			if !ok || name == "" {
+				return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "Loki labels must be name=value pairs", "labels", pair)
+			}
+			cfg.Labels[name] = strings.TrimSpace(value)
+		}
+	}
+	if s := query.Get("label_fields"); s != "" {
+		for _, key := range strings.Split(s, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				cfg.LabelFields = append(cfg.LabelFields, key)
+			}
+		}
+	}
+	switch strings.ToLower(query.Get("compress")) {
+	case "", "gzip":
+	case "none":
+		cfg.DisableCompression = true
+	default:
+		return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown Loki compression (want gzip or none)", "compress", query.Get("compress"))
+	}
+	if s := query.Get("flush_interval"); s != "" {
+		if cfg.FlushInterval, err = time.ParseDuration(s); err != nil {
+			return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid Loki flush interval", "flush_interval", s)
+		}
+	}
+	if s := query.Get("timeout"); s != "" {
+		if cfg.Timeout, err = time.ParseDuration(s); err != nil {
+			return cfg, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid Loki timeout", "timeout", s)
+		}
+	}
+	return cfg, nil
+}
+
+// isLokiOutput reports whether output is a Loki URL.
+func isLokiOutput(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.HasPrefix(lower, "loki://") || strings.HasPrefix(lower, "loki+")
+}
+
+// lokiPushURL validates raw and returns the push endpoint it designates.
+func lokiPushURL(raw string) (string, error) {
+	if raw == "" {
+		return "", NewLoggerError(ErrCodeInvalidConfig, "Loki URL is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", NewLoggerErrorWithField(ErrCodeInvalidConfig, "Loki URL must be an http or https URL", "url", raw)
+	}
+	if !strings.HasSuffix(u.Path, LokiPushPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + LokiPushPath
+	}
+	return u.String(), nil
+}
+
+// lokiLabelName turns name into a valid label name: characters other than
+// ASCII letters, digits and underscores become underscores, and a leading
+// digit is prefixed with one. Returns "" for an empty name.
+func lokiLabelName(name string) string {
+	if name == "" {
+		return ""
+	}
+	b := make([]byte, 0, len(name)+1)
+	if name[0] >= '0' && name[0] <= '9' {
+		b = append(b, '_')
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+	return string(b)
+}
+
+// lokiStreamKey returns the LogQL selector of labels, used to group
+// entries by stream.
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func newLokiBatch() *lokiBatch {
+	return &lokiBatch{index: make(map[string]*lokiStream)}
+}
+
+// Write adds p, one encoded record, to the current batch. A trailing
+// newline is removed. When LabelFields are configured and p is a JSON
+// object, its top-level string, number and boolean members of those names
+// become labels.
+func (w *LokiWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimSuffix(p, []byte{'\n'})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "Loki writer is closed")
+	}
+	w.add(w.jsonLabels(line), time.Now(), string(line))
+	return len(p), nil
+}
+
+// WriteRecord encodes rec with the configured encoder and adds it to the
+// current batch. LabelFields are read from the record fields, "level"
+// and "logger" from its level and logger name. Timestamps set with
+// SetTime or IngestTime are kept.
+func (w *LokiWriter) WriteRecord(rec *Record) error {
+	now := rec.Time()
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "Loki writer is closed")
+	}
+	w.buf.Reset()
+	w.enc.Encode(rec, now, &w.buf)
+	w.add(w.recordLabels(rec), now, string(bytes.TrimRight(w.buf.Bytes(), "\r\n")))
+	return nil
+}
+
+// jsonLabels returns the labels taken from the members of line, or nil
+// when there are none.
+func (w *LokiWriter) jsonLabels(line []byte) map[string]string {
+	if len(w.labelFields) == 0 || len(line) == 0 || line[0] != '{' {
+		return nil
+	}
+	var members map[string]json.RawMessage
+	if json.Unmarshal(line, &members) != nil {
+		return nil
+	}
+	var labels map[string]string
+	for key, label := range w.labelFields {
+		raw, ok := members[key]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		var value string
+		switch raw[0] {
+		case '"':
+			if json.Unmarshal(raw, &value) != nil {
+				continue
+			}
+		case '{', '[', 'n':
+			continue // Objects, arrays and null make no label
+		default:
+			value = string(raw)
+		}
+		if labels == nil {
+			labels = make(map[string]string, len(w.labelFields))
+		}
+		labels[label] = value
+	}
+	return labels
+}
+
+// recordLabels returns the labels taken from rec, or nil when there are
+// none.
+func (w *LokiWriter) recordLabels(rec *Record) map[string]string {
+	if len(w.labelFields) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(w.labelFields))
+	if label, ok := w.labelFields["level"]; ok {
+		labels[label] = rec.Level.String()
+	}
+	if label, ok := w.labelFields["logger"]; ok && rec.Logger != "" {
+		labels[label] = rec.Logger
+	}
+	for i := int32(0); i < rec.n; i++ {
+		f := &rec.fields[i]
+		label, ok := w.labelFields[f.K]
+		if !ok {
+			continue
+		}
+		if value, ok := lokiLabelValue(f); ok {
+			labels[label] = value
+		}
+	}
+	return labels
+}
+
+// lokiLabelValue returns the label value of f the way jsonLabels reads it
+// from a JSON record: strings (errors and stringers included), numbers and
+// booleans, with durations in nanoseconds and times in RFC 3339. Secrets,
+// bytes and objects make no label.
+func lokiLabelValue(f *Field) (string, bool) {
+	switch f.T {
+	case kindString:
+		return f.Str, true
+	case kindInt64, kindDur:
+		return strconv.FormatInt(f.I64, 10), true
+	case kindUint64:
+		return strconv.FormatUint(f.U64, 10), true
+	case kindFloat64:
+		return strconv.FormatFloat(f.F64, 'g', -1, 64), true
+	case kindBool:
+		return strconv.FormatBool(f.I64 != 0), true
+	case kindTime:
+		return time.Unix(0, f.I64).UTC().Format(time.RFC3339Nano), true
+	case kindError:
+		if err, ok := f.Obj.(error); ok && err != nil {
+			return err.Error(), true
+		}
+	case kindStringer:
+		if s, ok := f.Obj.(interface{ String() string }); ok && s != nil {
+			return s.String(), true
+		}
+	}
+	return "", false
+}
+
+// add appends an entry to the stream of the static labels merged with
+// extra, handing the batch to the sender first if the entry would
+// overflow it. Must be called with w.mu held.
+func (w *LokiWriter) add(extra map[string]string, ts time.Time, line string) {
+	labels, key := w.static, w.staticKey
+	if len(extra) > 0 {
+		labels = make(map[string]string, len(w.static)+len(extra))
+		for name, value := range w.static {
+			labels[name] = value
+		}
+		for name, value := range extra {
+			labels[name] = value
+		}
+		key = lokiStreamKey(labels)
+	}
+
+	size := len(line) + lokiEntryOverhead
+	stream := w.batch.index[key]
+	if stream == nil {
+		size += len(key)
+	}
+	if w.batch.entries > 0 && w.batch.bytes+size > w.maxBytes {
+		w.enqueue()
+		stream = nil
+	}
+	if stream == nil {
+		stream = &lokiStream{Stream: labels}
+		w.batch.index[key] = stream
+		w.batch.streams = append(w.batch.streams, stream)
+	}
+	stream.Values = append(stream.Values, [2]string{strconv.FormatInt(ts.UnixNano(), 10), line})
+	w.batch.entries++
+	w.batch.bytes += size
+}
+
+// enqueue hands the current batch to the sender, dropping it when the
+// queue is full. Must be called with w.mu held.
+func (w *LokiWriter) enqueue() {
+	if w.batch.entries == 0 {
+		return
+	}
+	select {
+	case w.queue <- w.batch:
+	default:
+		w.batchesDropped.Add(1)
+		w.entriesDropped.Add(int64(w.batch.entries))
+	}
+	w.batch = newLokiBatch()
+}
+
+// Sync sends the pending records and waits until every queued batch has
+// been delivered or dropped.
+func (w *LokiWriter) Sync() error {
+	reply := make(chan struct{})
+	select {
+	case w.flushes <- reply:
+		<-reply
+	case <-w.done:
+	}
+	return nil
+}
+
+// Close sends the pending records and stops the sender. Subsequent writes
+// fail. Pending batches get one more attempt: a batch that would need a
+// retry, including one backing off when Close is called, is dropped rather
+// than holding Close up.
+func (w *LokiWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	close(w.stop)
+	<-w.done
+	return nil
+}
+
+// Dropped returns the number of records that were not delivered.
+func (w *LokiWriter) Dropped() int64 {
+	return w.entriesDropped.Load()
+}
+
+// DeliveryStats returns the entries of pushes Loki accepted (Sent and
+// Acked: a 2xx response confirms the whole batch), the repeated pushes
+// (Retried) and the entries given up (Dropped).
+func (w *LokiWriter) DeliveryStats() DeliveryStats {
+	sent := w.entriesSent.Load()
+	return DeliveryStats{
+		Sent:    sent,
+		Acked:   sent,
+		Retried: w.retries.Load(),
+		Dropped: w.entriesDropped.Load(),
+	}
+}
+
+// Stats returns the delivery counters of the writer:
+//   - batches_sent, entries_sent: accepted by Loki
+//   - batches_dropped, entries_dropped: refused, given up after the
+//     retries, or rejected by a full queue
+//   - retries: repeated pushes, throttled: 429 responses
+func (w *LokiWriter) Stats() map[string]int64 {
+	return map[string]int64{
+		"batches_sent":    w.batchesSent.Load(),
+		"batches_dropped": w.batchesDropped.Load(),
+		"entries_sent":    w.entriesSent.Load(),
+		"entries_dropped": w.entriesDropped.Load(),
+		"retries":         w.retries.Load(),
+		"throttled":       w.throttled.Load(),
+	}
+}
+
+// run is the sender goroutine.
+func (w *LokiWriter) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case batch := <-w.queue:
+			w.send(batch)
+		case <-ticker.C:
+			w.drain()
+		case reply := <-w.flushes:
+			w.drain()
+			close(reply)
+		case <-w.stop:
+			w.drain()
+			return
+		}
+	}
+}
+
+// drain sends the queued batches, then the pending one.
+func (w *LokiWriter) drain() {
+	for len(w.queue) > 0 { // Only this goroutine receives
+		w.send(<-w.queue)
+	}
+	w.mu.Lock()
+	batch := w.batch
+	if batch.entries > 0 {
+		w.batch = newLokiBatch()
+	}
+	w.mu.Unlock()
+	if batch.entries > 0 {
+		w.send(batch)
+	}
+}
+
+// send pushes one batch, retrying rate limited and failed requests.
+func (w *LokiWriter) send(batch *lokiBatch) {
+	if err := w.encodeBatch(batch); err != nil {
+		w.drop(batch)
+		return
+	}
+	for attempt := 0; ; attempt++ {
+		status, err := w.push()
+		if err == nil && status >= 200 && status < 300 {
+			w.batchesSent.Add(1)
+			w.entriesSent.Add(int64(batch.entries))
+			return
+		}
+		if status == http.StatusTooManyRequests {
+			w.throttled.Add(1)
+		}
+		retry := err != nil || status == http.StatusTooManyRequests || status >= 500
+		if !retry || attempt >= w.maxRetries {
+			w.drop(batch)
+			return
+		}
+		w.retries.Add(1)
+		if !w.wait(w.backoff(attempt)) {
+			w.drop(batch) // Closed while backing off
+			return
+		}
+	}
+}
+
+// wait sleeps for d and reports true, or returns false as soon as Close is
+// called, so that a backoff does not hold Close up.
+func (w *LokiWriter) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.stop:
+		return false
+	}
+}
+
+// drop counts a batch given up.
+func (w *LokiWriter) drop(batch *lokiBatch) {
+	w.batchesDropped.Add(1)
+	w.entriesDropped.Add(int64(batch.entries))
+}
+
+// encodeBatch renders the push request of batch into w.body, compressed
+// unless DisableCompression is set.
+func (w *LokiWriter) encodeBatch(batch *lokiBatch) error {
+	w.body.Reset()
+	var dst io.Writer = &w.body
+	if w.compress {
+		if w.zw == nil {
+			w.zw = gzip.NewWriter(&w.body)
+		} else {
+			w.zw.Reset(&w.body)
+		}
+		dst = w.zw
+	}
+	req := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{batch.streams}
+	if err := json.NewEncoder(dst).Encode(req); err != nil {
+		return err
+	}
+	if w.compress {
+		return w.zw.Close()
+	}
+	return nil
+}
+
+// push sends w.body and returns the response status.
+func (w *LokiWriter) push() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.pushURL, bytes.NewReader(w.body.Bytes()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header = w.header.Clone()
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Drained so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before retry attempt+1: MinBackoff doubled per
+// attempt up to MaxBackoff, half of it randomized.
+func (w *LokiWriter) backoff(attempt int) time.Duration {
+	d := w.maxBackoff
+	if attempt < 30 {
+		d = min(w.minBackoff<<attempt, w.maxBackoff)
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
@@ -0,0 +1,28 @@
+// sink_other.go: Platform defaults for non-WASM builds
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !(js && wasm)
+
+package iris
+
+import "os"
+
+// fileSyncSupported reports whether *os.File.Sync is meaningful on this platform.
+const fileSyncSupported = true
+
+// NewJSConsoleSyncer returns a WriteSyncer for the JavaScript console.
+//
+// Outside GOOS=js/GOARCH=wasm there is no JavaScript console, so this
+// returns a stdout writer. It exists so code shared between WASM and native
+// builds compiles unchanged on every platform.
+func NewJSConsoleSyncer() WriteSyncer {
+	return WrapWriter(os.Stdout)
+}
+
+// defaultOutput returns the default log destination for this platform.
+func defaultOutput() WriteSyncer {
+	return WrapWriter(os.Stdout)
+}
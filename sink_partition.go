@@ -0,0 +1,504 @@
+// sink_partition.go: Time-partitioned file output for Iris logging library
+//
+// Batch pipelines usually pick up logs per time window: "everything written
+// between 13:00 and 14:00 UTC". PartitionedFileWriter writes each window to
+// its own file, derived from a path layout such as
+// "logs/%Y/%m/%d/app-%H.log", and switches files when the window ends. It
+// is independent of size-based rotation: the per-partition writer can be
+// supplied by the application (for instance a rotating writer), and
+// partitions older than a configurable age are removed automatically.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// partitionUnit is the granularity of a layout verb.
+type partitionUnit int
+
+const (
+	partitionNone partitionUnit = iota
+	partitionYear
+	partitionMonth
+	partitionDay
+	partitionHour
+	partitionMinute
+)
+
+// partitionVerbs maps layout verbs to their unit and width.
+var partitionVerbs = map[byte]struct {
+	unit  partitionUnit
+	width int
+}{
+	'Y': {partitionYear, 4},
+	'm': {partitionMonth, 2},
+	'd': {partitionDay, 2},
+	'H': {partitionHour, 2},
+	'M': {partitionMinute, 2},
+}
+
+// partitionSegment is a literal or a time verb of a parsed layout.
+type partitionSegment struct {
+	literal string
+	unit    partitionUnit // partitionNone for literals
+	width   int
+}
+
+// PartitionConfig configures a PartitionedFileWriter.
+type PartitionConfig struct {
+	// Layout is the file path of a partition, with time verbs: %Y (year),
+	// %m (month), %d (day), %H (hour), %M (minute) and %% (a literal %).
+	// The smallest verb sets the partition length. Use "/" as separator.
+	Layout string
+
+	// Location of the partition times (default: UTC)
+	Location *time.Location
+
+	// MaxAge removes partitions that ended more than MaxAge ago
+	// (0 = keep everything). Requires a layout whose verbs run from %Y down
+	// to the partition length without gaps.
+	MaxAge time.Duration
+
+	// Open opens the writer of a partition file. The default creates
+	// missing directories (0750) and appends to the file (0600). Returned
+	// writers implementing io.Closer are closed when the partition ends.
+	Open func(path string) (WriteSyncer, error)
+
+	// TimeFn returns the current time (default: cached clock)
+	TimeFn func() time.Time
+}
+
+// PartitionedFileWriter is a WriteSyncer that writes to one file per time
+// partition.
+type PartitionedFileWriter struct {
+	segments []partitionSegment
+	unit     partitionUnit
+	loc      *time.Location
+	maxAge   time.Duration
+	open     func(path string) (WriteSyncer, error)
+	now      func() time.Time
+	root     string         // Directory holding every partition
+	pattern  *regexp.Regexp // Matches partition paths relative to root
+
+	mu       sync.Mutex
+	cur      WriteSyncer
+	path     string
+	boundary time.Time // End of the current partition
+	closed   bool
+
+	cleaning sync.WaitGroup
+	cleanMu  sync.Mutex // Serializes cleanups
+}
+
+// NewPartitionedFileWriter creates a writer for cfg. The first partition is
+// opened on the first write.
+//
+// Returns:
+//   - *PartitionedFileWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidConfig if the layout is invalid
+//
+// Example:
+//
+//	out, err := iris.NewPartitionedFileWriter(iris.PartitionConfig{
+//	    Layout: "/var/log/app/%Y/%m/%d/app-%H.log",
+//	    MaxAge: 7 * 24 * time.Hour,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewPartitionedFileWriter(cfg PartitionConfig) (*PartitionedFileWriter, error) {
+	segments, err := parsePartitionLayout(cfg.Layout)
+	if err != nil {
+		return nil, err
+	}
+	w := &PartitionedFileWriter{
+		segments: segments,
+		loc:      cfg.Location,
+		maxAge:   cfg.MaxAge,
+		open:     cfg.Open,
+		now:      cfg.TimeFn,
+	}
+	seen := map[partitionUnit]bool{}
+	for _, s := range segments {
+		if s.unit > w.unit {
+			w.unit = s.unit
+		}
+		seen[s.unit] = true
+	}
+	if w.maxAge > 0 {
+		for u := partitionYear; u <= w.unit; u++ {
+			if !seen[u] {
+				return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig,
+					"partition cleanup needs every verb from %Y down to the partition length", "layout", cfg.Layout)
+			}
+		}
+	}
+	if w.loc == nil {
+		w.loc = time.UTC
+	}
+	if w.open == nil {
+		w.open = openPartitionFile
+	}
+	if w.now == nil {
+		w.now = timecache.CachedTime
+	}
+	w.root, w.pattern = partitionRoot(segments)
+	return w, nil
+}
+
+// parsePartitionLayout splits a layout into literals and verbs.
+func parsePartitionLayout(layout string) ([]partitionSegment, error) {
+	var segments []partitionSegment
+	var lit strings.Builder
+	hasVerb := false
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			lit.WriteByte(layout[i])
+			continue
+		}
+		if i+1 == len(layout) {
+			return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "partition layout ends with %", "layout", layout)
+		}
+		i++
+		if layout[i] == '%' {
+			lit.WriteByte('%')
+			continue
+		}
+		verb, ok := partitionVerbs[layout[i]]
+		if !ok {
+			return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "unknown partition layout verb %"+string(layout[i]), "layout", layout)
+		}
+		if lit.Len() > 0 {
+			segments = append(segments, partitionSegment{literal: lit.String()})
+			lit.Reset()
+		}
+		segments = append(segments, partitionSegment{unit: verb.unit, width: verb.width})
+		hasVerb = true
+	}
+	if lit.Len() > 0 {
+		segments = append(segments, partitionSegment{literal: lit.String()})
+	}
+	if !hasVerb {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "partition layout has no time verb", "layout", layout)
+	}
+	return segments, nil
+}
+
+// partitionRoot returns the directory containing every partition (the
+// directory part of the layout before the first verb) and a pattern
+// matching partition paths relative to it, slash-separated.
+func partitionRoot(segments []partitionSegment) (string, *regexp.Regexp) {
+	root, first := ".", ""
+	if segments[0].unit == partitionNone {
+		first = segments[0].literal
+		if i := strings.LastIndexByte(first, '/'); i >= 0 {
+			root, first = first[:i], first[i+1:]
+			if root == "" {
+				root = "/"
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i, s := range segments {
+		switch {
+		case s.unit != partitionNone:
+			b.WriteString(`(\d{` + strconv.Itoa(s.width) + `})`)
+		case i == 0:
+			b.WriteString(regexp.QuoteMeta(first))
+		default:
+			b.WriteString(regexp.QuoteMeta(s.literal))
+		}
+	}
+	b.WriteString("$")
+	return filepath.FromSlash(root), regexp.MustCompile(b.String())
+}
+
+// openPartitionFile is the default PartitionConfig.Open.
+func openPartitionFile(path string) (WriteSyncer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeFileOpen, "failed to create partition directory: "+err.Error(), "path", path)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path derived from the application layout
+	if err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeFileOpen, "failed to open partition file: "+err.Error(), "path", path)
+	}
+	return file, nil
+}
+
+// partitionStart truncates t to the start of its partition.
+func (w *PartitionedFileWriter) partitionStart(t time.Time) time.Time {
+	return truncatePartition(t.In(w.loc), w.unit)
+}
+
+// truncatePartition truncates t (already in the partition location) to unit.
+func truncatePartition(t time.Time, unit partitionUnit) time.Time {
+	y, mo, d := t.Date()
+	h, mi := t.Hour(), t.Minute()
+	switch unit {
+	case partitionYear:
+		mo, d, h, mi = time.January, 1, 0, 0
+	case partitionMonth:
+		d, h, mi = 1, 0, 0
+	case partitionDay:
+		h, mi = 0, 0
+	case partitionHour:
+		mi = 0
+	}
+	return time.Date(y, mo, d, h, mi, 0, 0, t.Location())
+}
+
+// nextPartition returns the start of the partition following start.
+func nextPartition(start time.Time, unit partitionUnit) time.Time {
+	y, mo, d := start.Date()
+	h, mi := start.Hour(), start.Minute()
+	switch unit {
+	case partitionYear:
+		y++
+	case partitionMonth:
+		mo++
+	case partitionDay:
+		d++
+	case partitionHour:
+		h++
+	default:
+		mi++
+	}
+	return time.Date(y, mo, d, h, mi, 0, 0, start.Location())
+}
+
+// format returns the partition path for start.
+func (w *PartitionedFileWriter) format(start time.Time) string {
+	var b strings.Builder
+	for _, s := range w.segments {
+		var v int
+		switch s.unit {
+		case partitionNone:
+			b.WriteString(s.literal)
+			continue
+		case partitionYear:
+			v = start.Year()
+		case partitionMonth:
+			v = int(start.Month())
+		case partitionDay:
+			v = start.Day()
+		case partitionHour:
+			v = start.Hour()
+		case partitionMinute:
+			v = start.Minute()
+		}
+		digits := strconv.Itoa(v)
+		for i := len(digits); i < s.width; i++ {
+			b.WriteByte('0')
+		}
+		b.WriteString(digits)
+	}
+	return filepath.FromSlash(b.String())
+}
+
+// Write appends p to the current partition, switching partitions when the
+// previous one has ended.
+func (w *PartitionedFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "partitioned file writer is closed")
+	}
+	if now := w.now(); w.cur == nil || !now.Before(w.boundary) {
+		if err := w.switchTo(now); err != nil {
+			return 0, err
+		}
+	}
+	return w.cur.Write(p)
+}
+
+// switchTo opens the partition containing now. Must be called with w.mu held.
+func (w *PartitionedFileWriter) switchTo(now time.Time) error {
+	start := w.partitionStart(now)
+	path := w.format(start)
+	if w.cur == nil || path != w.path {
+		next, err := w.open(path)
+		if err != nil {
+			return err
+		}
+		w.closeCurrent()
+		w.cur, w.path = next, path
+	}
+	w.boundary = nextPartition(start, w.unit)
+
+	if w.maxAge > 0 {
+		w.cleaning.Add(1)
+		go func() {
+			defer w.cleaning.Done()
+			_ = w.cleanup(now)
+		}()
+	}
+	return nil
+}
+
+// closeCurrent syncs and closes the current partition writer, if any.
+// Must be called with w.mu held.
+func (w *PartitionedFileWriter) closeCurrent() {
+	if w.cur == nil {
+		return
+	}
+	_ = w.cur.Sync()
+	if c, ok := w.cur.(io.Closer); ok {
+		_ = c.Close()
+	}
+	w.cur = nil
+}
+
+// Sync flushes the current partition.
+func (w *PartitionedFileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cur == nil {
+		return nil
+	}
+	return w.cur.Sync()
+}
+
+// Close closes the current partition and waits for running cleanups.
+// Subsequent writes fail.
+func (w *PartitionedFileWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	var err error
+	if w.cur != nil {
+		err = w.cur.Sync()
+		if c, ok := w.cur.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+		w.cur = nil
+	}
+	w.mu.Unlock()
+	w.cleaning.Wait()
+	return err
+}
+
+// Reopen closes the current partition and reopens it at the same path.
+// Writes are held while the file is swapped.
+func (w *PartitionedFileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "partitioned file writer is closed")
+	}
+	if w.cur == nil {
+		return nil // Opened on the next write
+	}
+	next, err := w.open(w.path)
+	if err != nil {
+		return err
+	}
+	w.closeCurrent()
+	w.cur = next
+	return nil
+}
+
+// Path returns the path of the current partition ("" before the first write).
+func (w *PartitionedFileWriter) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// Cleanup removes the partitions that ended more than MaxAge ago, and the
+// directories left empty by their removal. It runs automatically whenever
+// a partition is opened; it does nothing when MaxAge is zero.
+func (w *PartitionedFileWriter) Cleanup() error {
+	return w.cleanup(w.now())
+}
+
+// cleanup removes partitions that ended before now - MaxAge.
+func (w *PartitionedFileWriter) cleanup(now time.Time) error {
+	if w.maxAge <= 0 {
+		return nil
+	}
+	w.cleanMu.Lock()
+	defer w.cleanMu.Unlock()
+
+	cutoff := now.Add(-w.maxAge)
+	var expired []string
+	err := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil // Unreadable entries are skipped
+		}
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil {
+			return nil
+		}
+		start, ok := w.parse(filepath.ToSlash(rel))
+		if ok && !nextPartition(start, w.unit).After(cutoff) {
+			expired = append(expired, path)
+		}
+		return nil
+	})
+	for _, path := range expired {
+		if rerr := os.Remove(path); rerr != nil && err == nil {
+			err = rerr
+		}
+		// Remove directories emptied by the removal, up to the root
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			if rel, rerr := filepath.Rel(w.root, dir); rerr != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				break
+			}
+			if os.Remove(dir) != nil {
+				break // Not empty
+			}
+		}
+	}
+	return err
+}
+
+// parse returns the partition start encoded in rel, a slash-separated path
+// relative to the root.
+func (w *PartitionedFileWriter) parse(rel string) (time.Time, bool) {
+	m := w.pattern.FindStringSubmatch(rel)
+	if m == nil {
+		return time.Time{}, false
+	}
+	year, month, day, hour, minute := 0, 1, 1, 0, 0
+	g := 1
+	for _, s := range w.segments {
+		if s.unit == partitionNone {
+			continue
+		}
+		v, _ := strconv.Atoi(m[g])
+		g++
+		switch s.unit {
+		case partitionYear:
+			year = v
+		case partitionMonth:
+			month = v
+		case partitionDay:
+			day = v
+		case partitionHour:
+			hour = v
+		case partitionMinute:
+			minute = v
+		}
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, 0, 0, w.loc), true
+}
@@ -0,0 +1,216 @@
+// sink_partition_test.go: Tests for time-partitioned file output
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func TestNewPartitionedFileWriter_InvalidLayouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		maxAge time.Duration
+		want   string
+	}{
+		{"no verb", "logs/app.log", 0, "no time verb"},
+		{"unknown verb", "logs/%Y/%q.log", 0, "unknown partition layout verb %q"},
+		{"trailing percent", "logs/app-%", 0, "ends with %"},
+		{"gap with cleanup", "logs/%Y/app-%H.log", time.Hour, "every verb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPartitionedFileWriter(PartitionConfig{Layout: tt.layout, MaxAge: tt.maxAge})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Without cleanup, gaps are allowed (e.g. hour-of-day files reused daily)
+	if _, err := NewPartitionedFileWriter(PartitionConfig{Layout: "logs/app-%H.log"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPartitionedFileWriter_SwitchesPartitions(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 13, 59, 58, 0, time.UTC)}
+	w, err := NewPartitionedFileWriter(PartitionConfig{
+		Layout: filepath.ToSlash(dir) + "/%Y/%m/%d/app-%H.log",
+		TimeFn: clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewPartitionedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	write := func(s string) {
+		t.Helper()
+		if _, err := w.Write([]byte(s + "\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	write("a")
+	clock.Set(time.Date(2025, 1, 15, 13, 59, 59, 0, time.UTC))
+	write("b")
+	clock.Set(time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC))
+	write("c")
+	clock.Set(time.Date(2025, 1, 16, 0, 30, 0, 0, time.UTC))
+	write("d")
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	want := map[string]string{
+		"2025/01/15/app-13.log": "a\nb\n",
+		"2025/01/15/app-14.log": "c\n",
+		"2025/01/16/app-00.log": "d\n",
+	}
+	for rel, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Errorf("missing partition %s: %v", rel, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", rel, data, content)
+		}
+	}
+	if got := w.Path(); got != filepath.Join(dir, "2025", "01", "16", "app-00.log") {
+		t.Errorf("Path() = %q", got)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
+
+func TestPartitionedFileWriter_Location(t *testing.T) {
+	dir := t.TempDir()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 23, 10, 0, 0, time.UTC)}
+	w, err := NewPartitionedFileWriter(PartitionConfig{
+		Layout:   filepath.ToSlash(dir) + "/app-%Y%m%d-%H%M.log",
+		Location: loc,
+		TimeFn:   clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewPartitionedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+	if _, err := w.Write([]byte("x\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got, want := filepath.Base(w.Path()), "app-20250302-0110.log"; got != want {
+		t.Errorf("partition = %q, want %q", got, want)
+	}
+}
+
+func TestPartitionedFileWriter_Cleanup(t *testing.T) {
+	dir := t.TempDir()
+	logs := filepath.Join(dir, "logs")
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 13, 5, 0, 0, time.UTC)}
+
+	// Partitions from earlier runs, plus files the cleanup must not touch
+	old := []string{"2025/01/13/app-09.log", "2025/01/14/app-11.log"}
+	kept := []string{"2025/01/14/app-13.log", "2025/01/14/notes.txt", "README"}
+	for _, rel := range append(append([]string{}, old...), kept...) {
+		path := filepath.Join(logs, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("old\n"), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	w, err := NewPartitionedFileWriter(PartitionConfig{
+		Layout: filepath.ToSlash(logs) + "/%Y/%m/%d/app-%H.log",
+		MaxAge: 24 * time.Hour,
+		TimeFn: clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewPartitionedFileWriter failed: %v", err)
+	}
+	if _, err := w.Write([]byte("new\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil { // Waits for the cleanup started by the first write
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for _, rel := range old {
+		if _, err := os.Stat(filepath.Join(logs, filepath.FromSlash(rel))); !os.IsNotExist(err) {
+			t.Errorf("expired partition %s was not removed", rel)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(logs, "2025", "01", "13")); !os.IsNotExist(err) {
+		t.Error("empty partition directory was not removed")
+	}
+	for _, rel := range append(kept, "2025/01/15/app-13.log") {
+		if _, err := os.Stat(filepath.Join(logs, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("%s should have been kept: %v", rel, err)
+		}
+	}
+}
+
+func TestPartitionedFileWriter_CustomOpen(t *testing.T) {
+	var opened []string
+	var mu sync.Mutex
+	clock := &fakeClock{now: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)}
+	w, err := NewPartitionedFileWriter(PartitionConfig{
+		Layout: "app-%Y-%m-%d.log",
+		TimeFn: clock.Now,
+		Open: func(path string) (WriteSyncer, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			opened = append(opened, path)
+			return &testSyncer{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPartitionedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	for _, day := range []int{1, 1, 2} {
+		clock.Set(time.Date(2025, 6, day, 10, 0, 0, 0, time.UTC))
+		if _, err := w.Write([]byte("x\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if len(opened) != 2 || opened[0] != "app-2025-06-01.log" || opened[1] != "app-2025-06-02.log" {
+		t.Errorf("opened = %v", opened)
+	}
+}
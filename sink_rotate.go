@@ -0,0 +1,435 @@
+// sink_rotate.go: Size-based rotating file output for Iris logging library
+//
+// RotatingFileSyncer writes to a single file and, when the next record
+// would take it past a size limit, renames it to a timestamped backup next
+// to it ("app-2025-01-15T13-59-58.000.log") and starts a new file at the
+// original path. Backups can be compressed with gzip and are removed once
+// there are too many of them or they are too old. Compression and removal
+// run in the background, so writes only pay for the rename.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/go-timecache"
+)
+
+// rotationTimeFormat is the timestamp of backup names, always UTC. It sorts
+// lexically and avoids ':' so that it is a valid file name everywhere.
+const rotationTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotationCompressSuffix is appended to compressed backups.
+const rotationCompressSuffix = ".gz"
+
+// RotationConfig configures a RotatingFileSyncer.
+type RotationConfig struct {
+	// Path of the log file (created with 0600 permissions if missing)
+	Path string
+
+	// MaxSize is the size in bytes that triggers a rotation before a
+	// record would exceed it (0 = never rotate on size). A record larger
+	// than MaxSize is written whole to a fresh file.
+	MaxSize int64
+
+	// MaxAge removes backups older than MaxAge, by the time in their name
+	// (0 = keep regardless of age)
+	MaxAge time.Duration
+
+	// MaxBackups is the number of backups kept, newest first
+	// (0 = keep every backup)
+	MaxBackups int
+
+	// Compress gzips backups after rotation
+	Compress bool
+
+	// TimeFn returns the current time (default: cached clock)
+	TimeFn func() time.Time
+}
+
+// RotatingFileSyncer is a WriteSyncer that writes to a file and rotates it
+// by size, keeping a bounded set of backups.
+type RotatingFileSyncer struct {
+	path       string
+	prefix     string // Backup name before the timestamp, "app-"
+	ext        string // Backup name after the timestamp, ".log"
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+
+	milling sync.WaitGroup
+	millMu  sync.Mutex // Serializes compression and cleanup
+}
+
+// NewRotatingFileSyncer opens (or creates) cfg.Path for appending. Backups
+// left by a previous run are compressed and cleaned up in the background.
+//
+// Returns:
+//   - *RotatingFileSyncer: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidConfig for an empty path or negative limits,
+//     ErrCodeFileOpen if the file cannot be opened
+//
+// Example:
+//
+//	out, err := iris.NewRotatingFileSyncer(iris.RotationConfig{
+//	    Path:       "/var/log/app/app.log",
+//	    MaxSize:    100 << 20,
+//	    MaxBackups: 10,
+//	    Compress:   true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewRotatingFileSyncer(cfg RotationConfig) (*RotatingFileSyncer, error) {
+	if cfg.Path == "" {
+		return nil, NewLoggerError(ErrCodeInvalidConfig, "rotating file path is empty")
+	}
+	if cfg.MaxSize < 0 || cfg.MaxAge < 0 || cfg.MaxBackups < 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidConfig, "rotation limits must not be negative", "path", cfg.Path)
+	}
+	path := filepath.Clean(cfg.Path)
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	w := &RotatingFileSyncer{
+		path:       path,
+		prefix:     strings.TrimSuffix(base, ext) + "-",
+		ext:        ext,
+		maxSize:    cfg.MaxSize,
+		maxAge:     cfg.MaxAge,
+		maxBackups: cfg.MaxBackups,
+		compress:   cfg.Compress,
+		now:        cfg.TimeFn,
+	}
+	if w.now == nil {
+		w.now = timecache.CachedTime
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	w.startMill()
+	return w, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// MaxSize.
+func (w *RotatingFileSyncer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "rotating file writer is closed")
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the file to stable storage.
+func (w *RotatingFileSyncer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || !fileSyncSupported {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close closes the file and waits for running compression and cleanup.
+// Subsequent writes fail.
+func (w *RotatingFileSyncer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.file = nil
+	w.mu.Unlock()
+	w.milling.Wait()
+	return err
+}
+
+// Rotate rotates the file now, regardless of its size. An empty file is
+// not rotated.
+//
+// Returns:
+//   - error: ErrCodeFileRotation if the file cannot be renamed,
+//     ErrCodeFileOpen if the new file cannot be opened
+func (w *RotatingFileSyncer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "rotating file writer is closed")
+	}
+	if w.size == 0 {
+		return nil
+	}
+	return w.rotate()
+}
+
+// Reopen closes the file and reopens it at the same path, creating it if
+// it was moved away. Writes are held while the file is swapped.
+func (w *RotatingFileSyncer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "rotating file writer is closed")
+	}
+	return w.open()
+}
+
+// Path returns the path of the log file.
+func (w *RotatingFileSyncer) Path() string {
+	return w.path
+}
+
+// open (re)opens the file at w.path, closing any previous handle. Must be
+// called with w.mu held (or before the writer is shared).
+func (w *RotatingFileSyncer) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path chosen by the application
+	if err != nil {
+		return NewLoggerErrorWithField(ErrCodeFileOpen, "failed to open rotating log file: "+err.Error(), "path", w.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return NewLoggerErrorWithField(ErrCodeFileOpen, "failed to stat rotating log file: "+err.Error(), "path", w.path)
+	}
+	if w.file != nil {
+		_ = w.file.Close()
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// rotate renames the file to a new backup and opens a fresh file. Must be
+// called with w.mu held.
+func (w *RotatingFileSyncer) rotate() error {
+	if fileSyncSupported {
+		_ = w.file.Sync()
+	}
+	if err := w.file.Close(); err != nil {
+		return NewLoggerErrorWithField(ErrCodeFileRotation, "failed to close log file: "+err.Error(), "path", w.path)
+	}
+	w.file = nil
+	backup := w.backupPath(w.now())
+	if err := os.Rename(w.path, backup); err != nil && !os.IsNotExist(err) {
+		// Keep writing to the current file rather than losing records
+		if oerr := w.open(); oerr != nil {
+			return oerr
+		}
+		return NewLoggerErrorWithField(ErrCodeFileRotation, "failed to rename log file: "+err.Error(), "path", backup)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.startMill()
+	return nil
+}
+
+// backupPath returns an unused backup path for a rotation at now.
+func (w *RotatingFileSyncer) backupPath(now time.Time) string {
+	dir := filepath.Dir(w.path)
+	stamp := w.prefix + now.UTC().Format(rotationTimeFormat)
+	name := stamp + w.ext
+	for i := 1; backupExists(filepath.Join(dir, name)); i++ {
+		name = stamp + "." + strconv.Itoa(i) + w.ext
+	}
+	return filepath.Join(dir, name)
+}
+
+// backupExists reports whether a backup, compressed or not, exists at path.
+func backupExists(path string) bool {
+	if _, err := os.Lstat(path); err == nil {
+		return true
+	}
+	_, err := os.Lstat(path + rotationCompressSuffix)
+	return err == nil
+}
+
+// startMill compresses and cleans up backups in the background, when
+// configured to.
+func (w *RotatingFileSyncer) startMill() {
+	if !w.compress && w.maxAge <= 0 && w.maxBackups <= 0 {
+		return
+	}
+	now := w.now()
+	w.milling.Add(1)
+	go func() {
+		defer w.milling.Done()
+		if err := w.mill(now); err != nil {
+			handleError(errors.Wrap(err, ErrCodeFileRotation, "failed to process rotated log files").
+				WithSeverity("warning").
+				WithContext("path", w.path))
+		}
+	}()
+}
+
+// rotatedFile is a backup found next to the log file.
+type rotatedFile struct {
+	path string
+	time time.Time
+}
+
+// Backups returns the paths of the backups of the log file, newest first.
+// Compressed backups end in ".gz".
+func (w *RotatingFileSyncer) Backups() ([]string, error) {
+	backups, err := w.backups()
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths, err
+}
+
+// backups lists the backups, newest first.
+func (w *RotatingFileSyncer) backups() ([]rotatedFile, error) {
+	dir := filepath.Dir(w.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []rotatedFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if t, ok := w.parseBackup(e.Name()); ok {
+			backups = append(backups, rotatedFile{path: filepath.Join(dir, e.Name()), time: t})
+		}
+	}
+	slices.SortStableFunc(backups, func(a, b rotatedFile) int {
+		if c := b.time.Compare(a.time); c != 0 {
+			return c
+		}
+		return strings.Compare(b.path, a.path)
+	})
+	return backups, nil
+}
+
+// parseBackup returns the rotation time encoded in a backup name.
+func (w *RotatingFileSyncer) parseBackup(name string) (time.Time, bool) {
+	name = strings.TrimSuffix(name, rotationCompressSuffix)
+	if !strings.HasPrefix(name, w.prefix) || !strings.HasSuffix(name, w.ext) {
+		return time.Time{}, false
+	}
+	stamp := name[len(w.prefix) : len(name)-len(w.ext)]
+	if len(stamp) < len(rotationTimeFormat) {
+		return time.Time{}, false
+	}
+	if suffix := stamp[len(rotationTimeFormat):]; suffix != "" {
+		// Collision counter: ".N"
+		if n, err := strconv.Atoi(strings.TrimPrefix(suffix, ".")); err != nil || n < 1 || suffix[0] != '.' {
+			return time.Time{}, false
+		}
+	}
+	t, err := time.Parse(rotationTimeFormat, stamp[:len(rotationTimeFormat)])
+	return t, err == nil
+}
+
+// mill removes the backups beyond MaxBackups or older than MaxAge, then
+// compresses the remaining uncompressed ones.
+func (w *RotatingFileSyncer) mill(now time.Time) error {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	keep := backups[:0]
+	for i, b := range backups {
+		expired := (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && b.time.Before(now.Add(-w.maxAge)))
+		if !expired {
+			keep = append(keep, b)
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if !w.compress {
+		return firstErr
+	}
+	for _, b := range keep {
+		if strings.HasSuffix(b.path, rotationCompressSuffix) {
+			continue
+		}
+		if err := compressBackup(b.path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// compressBackup gzips path to path.gz and removes path. The archive is
+// written to a temporary file first, so a crash never leaves a truncated
+// .gz behind.
+func compressBackup(path string) (err error) {
+	src, err := os.Open(path) // #nosec G304 -- backup of the application's log file
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmp := path + rotationCompressSuffix + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) // #nosec G304 -- derived from the backup path
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if fileSyncSupported {
+		if err = dst.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path+rotationCompressSuffix); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
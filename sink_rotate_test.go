@@ -0,0 +1,224 @@
+// sink_rotate_test.go: Tests for size-based rotating file output
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRotatingSyncer(t *testing.T, cfg RotationConfig) *RotatingFileSyncer {
+	t.Helper()
+	w, err := NewRotatingFileSyncer(cfg)
+	if err != nil {
+		t.Fatalf("NewRotatingFileSyncer failed: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func writeRecords(t *testing.T, w *RotatingFileSyncer, records ...string) {
+	t.Helper()
+	for _, r := range records {
+		if _, err := w.Write([]byte(r + "\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(strings.NewReader(string(data)))
+		if err != nil {
+			t.Fatalf("gzip.NewReader failed: %v", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			t.Fatalf("gzip read failed: %v", err)
+		}
+	}
+	return string(data)
+}
+
+func TestNewRotatingFileSyncer_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RotationConfig
+	}{
+		{"empty path", RotationConfig{}},
+		{"negative size", RotationConfig{Path: "app.log", MaxSize: -1}},
+		{"negative backups", RotationConfig{Path: "app.log", MaxBackups: -1}},
+		{"negative age", RotationConfig{Path: "app.log", MaxAge: -time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRotatingFileSyncer(tt.cfg); !IsLoggerError(err, ErrCodeInvalidConfig) {
+				t.Errorf("got %v, want ErrCodeInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestRotatingFileSyncer_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)}
+	w := newTestRotatingSyncer(t, RotationConfig{Path: path, MaxSize: 10, TimeFn: clock.Now})
+
+	writeRecords(t, w, "aaaa", "bbbb") // 10 bytes: fits exactly
+	writeRecords(t, w, "cccc")         // Rotates first
+	clock.Set(clock.Now().Add(time.Second))
+	writeRecords(t, w, "dddddddddddddddd") // Larger than MaxSize: rotates, written whole
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatalf("Backups failed: %v", err)
+	}
+	want := []string{"app-2025-01-15T13-00-01.000.log", "app-2025-01-15T13-00-00.000.log"}
+	if len(backups) != len(want) {
+		t.Fatalf("backups = %v, want %v", backups, want)
+	}
+	for i, name := range want {
+		if filepath.Base(backups[i]) != name {
+			t.Errorf("backup %d = %s, want %s", i, filepath.Base(backups[i]), name)
+		}
+	}
+	if got := readFile(t, backups[1]); got != "aaaa\nbbbb\n" {
+		t.Errorf("oldest backup = %q", got)
+	}
+	if got := readFile(t, backups[0]); got != "cccc\n" {
+		t.Errorf("newest backup = %q", got)
+	}
+	if got := readFile(t, path); got != "dddddddddddddddd\n" {
+		t.Errorf("current file = %q", got)
+	}
+
+	// Same timestamp: the backup name gets a counter
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app-2025-01-15T13-00-01.000.1.log")); err != nil {
+		t.Errorf("collision backup missing: %v", err)
+	}
+	if err := w.Rotate(); err != nil { // Empty file: nothing to do
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if backups, _ := w.Backups(); len(backups) != 3 {
+		t.Errorf("empty file rotated: %v", backups)
+	}
+}
+
+func TestRotatingFileSyncer_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("previous run\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w := newTestRotatingSyncer(t, RotationConfig{Path: path, MaxSize: 16})
+	writeRecords(t, w, "next") // 13 + 5 bytes: rotates the previous run away
+
+	backups, _ := w.Backups()
+	if len(backups) != 1 || readFile(t, backups[0]) != "previous run\n" || readFile(t, path) != "next\n" {
+		t.Errorf("backups %v, current %q", backups, readFile(t, path))
+	}
+}
+
+func TestRotatingFileSyncer_RetentionAndCompression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)}
+
+	// Leftovers: an ancient backup and an unrelated file
+	ancient := filepath.Join(dir, "app-2024-01-01T00-00-00.000.log")
+	unrelated := filepath.Join(dir, "app-notes.log")
+	for _, p := range []string{ancient, unrelated} {
+		if err := os.WriteFile(p, []byte("x\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := newTestRotatingSyncer(t, RotationConfig{
+		Path: path, MaxSize: 1, MaxBackups: 2, MaxAge: 30 * 24 * time.Hour, Compress: true, TimeFn: clock.Now,
+	})
+	for i := 0; i < 4; i++ {
+		writeRecords(t, w, "record-"+string(rune('0'+i)))
+		clock.Set(clock.Now().Add(time.Minute))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatalf("Backups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want the 2 newest", backups)
+	}
+	for i, want := range []string{"record-2\n", "record-1\n"} {
+		if !strings.HasSuffix(backups[i], ".log.gz") {
+			t.Errorf("backup %s not compressed", backups[i])
+		}
+		if got := readFile(t, backups[i]); got != want {
+			t.Errorf("backup %d = %q, want %q", i, got, want)
+		}
+	}
+	if _, err := os.Stat(ancient); !os.IsNotExist(err) {
+		t.Error("backup older than MaxAge kept")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("temporary files left: %v", matches)
+	}
+}
+
+func TestRotatingFileSyncer_Logger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w := newTestRotatingSyncer(t, RotationConfig{Path: path, MaxSize: 200})
+	logger, err := New(Config{Level: Info, Output: w, Encoder: NewJSONEncoder(), Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		logger.Info("rotating record", Int("i", i))
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	logger.Info("after reopen")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	backups, _ := w.Backups()
+	if len(backups) == 0 {
+		t.Fatal("no rotation after exceeding MaxSize")
+	}
+	total := readFile(t, path)
+	for _, b := range backups {
+		total += readFile(t, b)
+	}
+	if n := strings.Count(total, "\n"); n != 11 {
+		t.Errorf("found %d records across the files, want 11", n)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("Write after Close: got %v, want ErrCodeWriterNotAvailable", err)
+	}
+}
@@ -0,0 +1,217 @@
+// sink_shared.go: Multi-process safe file writer for Iris logging library
+//
+// Preforked servers often have several processes appending to the same log
+// file. With a plain buffered writer records from different processes
+// interleave mid-line. SharedFileWriter avoids this by opening the file with
+// O_APPEND and issuing exactly one write system call per record, so the
+// kernel positions each record atomically at the end of the file.
+//
+// Rotation is coordinated through an advisory lock on a sidecar file
+// ("<path>.lock"): only one process renames the file, and the others notice
+// the new file at the path and reopen it on their next write.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// sharedFileStaleCheck is how often a writer checks whether another process
+// rotated the file out from under it.
+const sharedFileStaleCheck = time.Second
+
+// SharedFileWriter is a WriteSyncer that can be shared by several processes
+// appending to the same file.
+//
+// Guarantees:
+//   - Each Write is a single O_APPEND write system call, so records from
+//     different processes never interleave (on local filesystems; NFS does
+//     not honor O_APPEND atomically)
+//   - A write that the kernel only partially accepts is reported as
+//     io.ErrShortWrite instead of being completed with a second call that
+//     could interleave with another process
+//   - Rotate is serialized across processes with an advisory lock
+//
+// The logger writes one encoded record per Write call, so records are never
+// split. Advisory locks are unavailable on some platforms (e.g. Windows,
+// js/wasm); there Rotate is only serialized within the process.
+type SharedFileWriter struct {
+	path     string
+	lockPath string
+
+	mu        sync.Mutex
+	file      *os.File
+	nextCheck int64 // Cached-clock nanoseconds of the next rotation check
+}
+
+// NewSharedFileWriter opens (or creates) path for multi-process appending.
+//
+// Parameters:
+//   - path: Log file path (created with 0600 permissions if missing)
+//
+// Returns:
+//   - *SharedFileWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeFileOpen if the file cannot be opened
+//
+// Example:
+//
+//	out, err := iris.NewSharedFileWriter("/var/log/app/app.log")
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewJSONEncoder()})
+func NewSharedFileWriter(path string) (*SharedFileWriter, error) {
+	cleanPath := filepath.Clean(path)
+	w := &SharedFileWriter{
+		path:     cleanPath,
+		lockPath: cleanPath + ".lock",
+	}
+	if err := w.reopen(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends p to the file with a single write system call.
+func (w *SharedFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "shared file writer is closed")
+	}
+
+	// Another process may have rotated the file; follow it to the new one
+	if now := timecache.CachedTimeNano(); now >= w.nextCheck {
+		w.nextCheck = now + int64(sharedFileStaleCheck)
+		if !w.ownsPath() {
+			if err := w.reopen(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := writeOnce(w.file, p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// Sync flushes the file to stable storage.
+func (w *SharedFileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil || !fileSyncSupported {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close closes the underlying file. Subsequent writes fail.
+func (w *SharedFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// Path returns the path of the log file.
+func (w *SharedFileWriter) Path() string {
+	return w.path
+}
+
+// Rotate renames the current file to archivePath and starts a new file at
+// the original path.
+//
+// The rename is performed under an exclusive advisory lock on the sidecar
+// lock file. If another process already rotated the file while this one was
+// waiting for the lock, Rotate only switches to the new file and leaves
+// archivePath untouched, so concurrent rotation attempts produce one archive.
+// Other processes pick up the new file within about a second; records they
+// write in the meantime land intact at the end of the archive.
+//
+// Windows does not allow renaming a file that other handles have open, so
+// Rotate fails there while other writers hold the file.
+//
+// Parameters:
+//   - archivePath: Destination for the current file contents
+//
+// Returns:
+//   - error: ErrCodeFileRotation if locking or renaming fails
+func (w *SharedFileWriter) Rotate(archivePath string) error {
+	unlock, err := lockFileExclusive(w.lockPath)
+	if err != nil {
+		return NewLoggerErrorWithField(ErrCodeFileRotation, "failed to acquire rotation lock: "+err.Error(), "path", w.lockPath)
+	}
+	defer unlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "shared file writer is closed")
+	}
+
+	if w.ownsPath() {
+		if err := os.Rename(w.path, filepath.Clean(archivePath)); err != nil {
+			return NewLoggerErrorWithField(ErrCodeFileRotation, "failed to rename log file: "+err.Error(), "path", archivePath)
+		}
+	}
+	return w.reopen()
+}
+
+// Reopen closes the file and reopens w.path, creating it if it was moved
+// away. Writes are held while the file is swapped. After copytruncate the
+// reopened file is written at its new end (O_APPEND).
+func (w *SharedFileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return NewLoggerError(ErrCodeWriterNotAvailable, "shared file writer is closed")
+	}
+	return w.reopen()
+}
+
+// ownsPath reports whether the open file is still the one at w.path.
+// Must be called with w.mu held.
+func (w *SharedFileWriter) ownsPath() bool {
+	current, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+	opened, err := w.file.Stat()
+	if err != nil {
+		return false
+	}
+	return os.SameFile(current, opened)
+}
+
+// reopen (re)opens the file at w.path, closing any previous handle.
+// Must be called with w.mu held (or before the writer is shared).
+func (w *SharedFileWriter) reopen() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304 -- path chosen by the application
+	if err != nil {
+		return NewLoggerErrorWithField(ErrCodeFileOpen, "failed to open shared log file: "+err.Error(), "path", w.path)
+	}
+	if w.file != nil {
+		_ = w.file.Close()
+	}
+	w.file = file
+	w.nextCheck = timecache.CachedTimeNano() + int64(sharedFileStaleCheck)
+	return nil
+}
@@ -0,0 +1,54 @@
+// sink_shared_flock.go: Advisory locking and single-syscall writes for SharedFileWriter
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package iris
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFileExclusive takes an exclusive flock on path (creating it if needed)
+// and returns the function that releases it.
+func lockFileExclusive(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600) // #nosec G304 -- sidecar of an application-chosen path
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G115 - file descriptors fit in an int
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		// #nosec G115 - file descriptors fit in an int
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
+
+// writeOnce writes p with exactly one write system call. Unlike
+// os.File.Write it never issues a second call for the remainder of a
+// partial write, which could interleave with another process.
+func writeOnce(f *os.File, p []byte) (int, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	var werr error
+	err = rc.Write(func(fd uintptr) bool {
+		// #nosec G115 - file descriptors fit in an int
+		n, werr = writeNoIntr(int(fd), p)
+		return true
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, werr
+}
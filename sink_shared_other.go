@@ -0,0 +1,30 @@
+// sink_shared_other.go: SharedFileWriter fallbacks for platforms without flock
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package iris
+
+import (
+	"os"
+	"sync"
+)
+
+// sharedRotateMu serializes rotations within the process where no
+// cross-process advisory lock is available.
+var sharedRotateMu sync.Mutex
+
+// lockFileExclusive serializes rotation within this process only.
+func lockFileExclusive(string) (func(), error) {
+	sharedRotateMu.Lock()
+	return sharedRotateMu.Unlock, nil
+}
+
+// writeOnce writes p through os.File. Without raw descriptor access a
+// partial write may be completed with a second call.
+func writeOnce(f *os.File, p []byte) (int, error) {
+	return f.Write(p)
+}
@@ -0,0 +1,207 @@
+// sink_shared_test.go: Tests for the multi-process safe file writer
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSharedFileWriter_Basic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if w.Path() != path {
+		t.Errorf("Path() = %q, want %q", w.Path(), path)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close should be a no-op, got %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("expected ErrCodeWriterNotAvailable after Close, got %v", err)
+	}
+
+	// Reopening appends instead of truncating
+	w2, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w2.Close() }()
+	_, _ = w2.Write([]byte("second\n"))
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("unexpected file contents %q", data)
+	}
+	if info, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("log file should not be group/world accessible, got %v", info.Mode().Perm())
+	}
+}
+
+func TestSharedFileWriter_ConcurrentWritersDoNotInterleave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.log")
+
+	// Independent writers have independent descriptors, like separate processes
+	const writers = 4
+	const lines = 500
+	payload := strings.Repeat("x", 2048)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		w, err := NewSharedFileWriter(path)
+		if err != nil {
+			t.Fatalf("NewSharedFileWriter failed: %v", err)
+		}
+		defer func() { _ = w.Close() }()
+
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				if _, err := fmt.Fprintf(w, "w%d-%d %s\n", id, j, payload); err != nil {
+					t.Errorf("Write failed: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(got) != writers*lines {
+		t.Fatalf("expected %d lines, got %d", writers*lines, len(got))
+	}
+	for i, line := range got {
+		if !strings.HasSuffix(line, " "+payload) || !strings.HasPrefix(line, "w") {
+			t.Fatalf("line %d is corrupted: %.60q", i, line)
+		}
+	}
+}
+
+func TestSharedFileWriter_RotateCoordination(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("open files cannot be renamed on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	a, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = a.Close() }()
+	b, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	_, _ = a.Write([]byte("before\n"))
+
+	archive := filepath.Join(dir, "app.log.1")
+	if err := a.Rotate(archive); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	// A second rotation from a process that has not noticed the first one
+	// must not rename the fresh file
+	if err := b.Rotate(filepath.Join(dir, "app.log.2")); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.log.2")); !os.IsNotExist(err) {
+		t.Error("concurrent rotation should produce a single archive")
+	}
+
+	_, _ = a.Write([]byte("after-a\n"))
+	_, _ = b.Write([]byte("after-b\n"))
+
+	archived, _ := os.ReadFile(archive)
+	if string(archived) != "before\n" {
+		t.Errorf("unexpected archive contents %q", archived)
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "after-a\nafter-b\n" {
+		t.Errorf("unexpected current contents %q", current)
+	}
+}
+
+func TestSharedFileWriter_FollowsExternalRotation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("open files cannot be renamed on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	// Simulate another process rotating the file
+	if err := os.Rename(path, path+".old"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	w.mu.Lock()
+	w.nextCheck = 0 // Force the periodic rotation check
+	w.mu.Unlock()
+
+	_, _ = w.Write([]byte("new file\n"))
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new file\n" {
+		t.Errorf("writer should follow rotation to the new file, got %q, %v", data, err)
+	}
+}
+
+func TestSharedFileWriter_WithLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logger.log")
+	w, err := NewSharedFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewSharedFileWriter failed: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	logger, err := New(Config{
+		Level:    Info,
+		Output:   w,
+		Encoder:  NewJSONEncoder(),
+		Capacity: 64,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	logger.Info("through shared writer", Int("worker", 1))
+	safeCloseWithOptionsLogger(t, logger)
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"msg":"through shared writer"`) {
+		t.Errorf("expected record in file, got %q", data)
+	}
+}
@@ -0,0 +1,261 @@
+// sink_syslog.go: Syslog transport for Iris logging library
+//
+// SyslogSyncer sends each record to a syslog server: the local daemon over
+// its unix socket, or a remote one over UDP (RFC 5426), TCP (RFC 6587) or
+// TLS (RFC 5425). It is a transport only; SyslogEncoder produces the RFC
+// 5424 messages it carries.
+//
+// Like the local sinks, the writer never stalls the consumer for long: a
+// message that cannot be delivered within the timeout is dropped and
+// counted, the connection is closed, and the writer reconnects at most once
+// per second.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// SyslogFraming selects how messages are delimited on a stream (TCP, TLS).
+type SyslogFraming int
+
+const (
+	// FramingOctetCounting prefixes each message with its length
+	// (RFC 6587, 3.4.1), as RFC 5425 requires over TLS. Messages may
+	// contain line breaks.
+	FramingOctetCounting SyslogFraming = iota
+
+	// FramingNonTransparent ends each message with a newline (RFC 6587,
+	// 3.4.2), the traditional framing many receivers expect on plain TCP.
+	FramingNonTransparent
+)
+
+// DefaultSyslogTimeout bounds the connection and each write when
+// SyslogConfig.Timeout is zero.
+const DefaultSyslogTimeout = 5 * time.Second
+
+// syslogLocalPaths are the sockets of the local syslog daemon, in the
+// order they are tried.
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig configures a SyslogSyncer.
+type SyslogConfig struct {
+	// Network is "udp", "tcp" or "tls" for a remote server, "unixgram" or
+	// "unix" for a socket at Address, or empty for the local daemon
+	// (/dev/log, /var/run/syslog or /var/run/log, datagram or stream).
+	Network string
+
+	// Address is host:port for a remote server (e.g. "logs.example:6514")
+	// or the path of a socket.
+	Address string
+
+	// TLSConfig is used with "tls"; nil verifies the server against the
+	// system roots.
+	TLSConfig *tls.Config
+
+	// Framing of the messages on TCP and TLS (default
+	// FramingOctetCounting). Datagrams carry one message each and local
+	// streams end messages with a newline.
+	Framing SyslogFraming
+
+	// Timeout bounds the connection and each write (default
+	// DefaultSyslogTimeout).
+	Timeout time.Duration
+}
+
+// SyslogSyncer writes each record to a syslog server as one message. Its
+// Write never fails for transient conditions: a message that cannot be
+// delivered is dropped and counted in Dropped(), and the connection is
+// re-established at most once per second.
+type SyslogSyncer struct {
+	network string
+	address string
+	tls     *tls.Config
+	framing SyslogFraming
+	timeout time.Duration
+
+	mu      sync.Mutex
+	conn    net.Conn
+	stream  bool  // Messages need framing
+	octets  bool  // Framed by octet counting rather than a newline
+	retryAt int64 // Cached-clock nanoseconds of the next dial attempt
+	closed  bool
+	frame   bytes.Buffer
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewSyslogSyncer connects to the syslog server described by cfg. Use it
+// with SyslogEncoder, which produces the RFC 5424 messages.
+//
+// Parameters:
+//   - cfg: Server to connect to
+//
+// Returns:
+//   - *SyslogSyncer: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidOutput for an unknown network,
+//     ErrCodeWriterNotAvailable if the server cannot be reached
+//
+// Example:
+//
+//	out, err := iris.NewSyslogSyncer(iris.SyslogConfig{Network: "tls", Address: "logs.example:6514"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger, err := iris.New(iris.Config{Output: out, Encoder: iris.NewSyslogEncoder()})
+func NewSyslogSyncer(cfg SyslogConfig) (*SyslogSyncer, error) {
+	switch cfg.Network {
+	case "", "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "tls", "unix", "unixgram":
+	default:
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "unknown syslog network (want udp, tcp, tls, unix, unixgram or empty for the local daemon)", "network", cfg.Network)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultSyslogTimeout
+	}
+	s := &SyslogSyncer{
+		network: cfg.Network,
+		address: cfg.Address,
+		tls:     cfg.TLSConfig,
+		framing: cfg.Framing,
+		timeout: cfg.Timeout,
+	}
+	if err := s.dial(); err != nil {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "failed to connect to syslog: "+err.Error(), "address", s.address)
+	}
+	return s, nil
+}
+
+// Write sends p, one encoded record, as one message. A trailing newline is
+// removed and replaced by the framing of the transport.
+func (s *SyslogSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "syslog writer is closed")
+	}
+	if s.conn == nil {
+		if timecache.CachedTimeNano() < s.retryAt || s.dial() != nil {
+			s.dropped.Add(1)
+			return len(p), nil
+		}
+	}
+
+	msg := bytes.TrimSuffix(p, []byte{'\n'})
+	if s.stream {
+		s.frame.Reset()
+		if s.octets {
+			s.frame.WriteString(strconv.Itoa(len(msg)))
+			s.frame.WriteByte(' ')
+			s.frame.Write(msg)
+		} else {
+			s.frame.Write(msg)
+			s.frame.WriteByte('\n')
+		}
+		msg = s.frame.Bytes()
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	if _, err := s.conn.Write(msg); err != nil {
+		s.disconnect()
+		s.dropped.Add(1)
+		return len(p), nil
+	}
+	s.sent.Add(1)
+	return len(p), nil
+}
+
+// Sync is a no-op: messages are sent as they are written.
+func (s *SyslogSyncer) Sync() error {
+	return nil
+}
+
+// Dropped returns the number of messages that could not be delivered.
+func (s *SyslogSyncer) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// DeliveryStats returns the messages written to the connection and those
+// dropped. The transport has no acknowledgements and messages are not
+// retried, so Acked and Retried stay 0.
+func (s *SyslogSyncer) DeliveryStats() DeliveryStats {
+	return DeliveryStats{Sent: s.sent.Load(), Dropped: s.dropped.Load()}
+}
+
+// Close closes the connection. Subsequent writes fail.
+func (s *SyslogSyncer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn != nil {
+		err := s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// dial connects to the server, scheduling a retry on failure.
+// Must be called with s.mu held (or before the writer is shared).
+func (s *SyslogSyncer) dial() error {
+	var err error
+	switch s.network {
+	case "":
+		err = s.dialLocal()
+	case "tls":
+		dialer := &net.Dialer{Timeout: s.timeout}
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", s.address, s.tls)
+		s.stream, s.octets = true, s.framing == FramingOctetCounting
+	default:
+		s.conn, err = net.DialTimeout(s.network, s.address, s.timeout)
+		tcp := strings.HasPrefix(s.network, "tcp")
+		s.stream, s.octets = tcp || s.network == "unix", tcp && s.framing == FramingOctetCounting
+	}
+	if err != nil {
+		s.conn = nil
+		s.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+	}
+	return err
+}
+
+// dialLocal connects to the first local syslog socket that accepts,
+// datagram first.
+func (s *SyslogSyncer) dialLocal() error {
+	var err error
+	for _, path := range syslogLocalPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			if conn, err = net.DialTimeout(network, path, s.timeout); err == nil {
+				s.conn, s.stream = conn, network == "unix"
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+// disconnect drops the current connection and schedules a reconnect.
+// Must be called with s.mu held.
+func (s *SyslogSyncer) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	s.retryAt = timecache.CachedTimeNano() + int64(localSinkRetryInterval)
+}
@@ -0,0 +1,253 @@
+// sink_syslog_test.go: Tests for the syslog transport
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSignedTLS returns a server configuration for 127.0.0.1 and a client
+// configuration trusting it.
+func selfSignedTLS(t *testing.T) (server, client *tls.Config) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "syslog test"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	server = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}, MinVersion: tls.VersionTLS12}
+	client = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return server, client
+}
+
+// acceptStream accepts one connection on lis and returns everything it
+// receives, once the connection is closed.
+func acceptStream(t *testing.T, lis net.Listener) <-chan string {
+	t.Helper()
+	got := make(chan string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			got <- ""
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		data, _ := io.ReadAll(bufio.NewReader(conn))
+		got <- string(data)
+	}()
+	return got
+}
+
+// syslogLogger returns an inline logger writing RFC 5424 messages to out.
+func syslogLogger(t *testing.T, out WriteSyncer) *Logger {
+	t.Helper()
+	enc := &SyslogEncoder{Facility: FacilityLocal0, Hostname: "h", AppName: "app"}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: enc, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return logger
+}
+
+func TestSyslogSyncer_Streams(t *testing.T) {
+	serverTLS, clientTLS := selfSignedTLS(t)
+	tests := []struct {
+		name    string
+		network string
+		framing SyslogFraming
+		listen  func() (net.Listener, error)
+		want    string
+	}{
+		{"tcp octet counting", "tcp", FramingOctetCounting,
+			func() (net.Listener, error) { return net.Listen("tcp", "127.0.0.1:0") },
+			"59 <134>1 - h app - - - first record" + "61 <132>1 - h app - - - second\\nrecord"},
+		{"tcp non-transparent", "tcp", FramingNonTransparent,
+			func() (net.Listener, error) { return net.Listen("tcp", "127.0.0.1:0") },
+			"<134>1 - h app - - - first record\n" + "<132>1 - h app - - - second\\nrecord\n"},
+		{"tls", "tls", FramingOctetCounting,
+			func() (net.Listener, error) { return tls.Listen("tcp", "127.0.0.1:0", serverTLS) },
+			"59 <134>1 - h app - - - first record" + "61 <132>1 - h app - - - second\\nrecord"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis, err := tt.listen()
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			defer func() { _ = lis.Close() }()
+			got := acceptStream(t, lis)
+
+			out, err := NewSyslogSyncer(SyslogConfig{Network: tt.network, Address: lis.Addr().String(), TLSConfig: clientTLS, Framing: tt.framing})
+			if err != nil {
+				t.Fatalf("NewSyslogSyncer failed: %v", err)
+			}
+			logger := syslogLogger(t, out)
+			logger.Info("first record")
+			logger.Warn("second\nrecord")
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			_ = out.Close()
+
+			// The timestamp varies: drop it, the lengths include it
+			data := <-got
+			for _, ts := range strings.Fields(data) {
+				if strings.HasSuffix(ts, "Z") && strings.Contains(ts, "T") {
+					data = strings.ReplaceAll(data, ts, "-")
+				}
+			}
+			if data != tt.want {
+				t.Errorf("received %q, want %q", data, tt.want)
+			}
+			if d := out.DeliveryStats(); d.Sent != 2 || d.Dropped != 0 {
+				t.Errorf("DeliveryStats() = %+v", d)
+			}
+		})
+	}
+}
+
+func TestSyslogSyncer_Datagrams(t *testing.T) {
+	networks := []struct {
+		network string
+		listen  func(t *testing.T) (net.PacketConn, string)
+	}{
+		{"udp", func(t *testing.T) (net.PacketConn, string) {
+			pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			return pc, pc.LocalAddr().String()
+		}},
+		{"unixgram", func(t *testing.T) (net.PacketConn, string) {
+			if runtime.GOOS == "windows" {
+				t.Skip("unix datagram sockets are not available")
+			}
+			path := filepath.Join(t.TempDir(), "log.sock")
+			pc, err := net.ListenPacket("unixgram", path)
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			return pc, path
+		}},
+	}
+	for _, tt := range networks {
+		t.Run(tt.network, func(t *testing.T) {
+			pc, addr := tt.listen(t)
+			defer func() { _ = pc.Close() }()
+			out, err := NewSyslogSyncer(SyslogConfig{Network: tt.network, Address: addr})
+			if err != nil {
+				t.Fatalf("NewSyslogSyncer failed: %v", err)
+			}
+			defer func() { _ = out.Close() }()
+			logger := syslogLogger(t, out)
+			defer func() { _ = logger.Close() }()
+
+			logger.Error("disk full", Str("mount", "/var"))
+			buf := make([]byte, 2048)
+			_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			msg := string(buf[:n])
+			if !strings.HasPrefix(msg, "<131>1 ") || !strings.HasSuffix(msg, ` - [iris@32473 mount="/var"] disk full`) {
+				t.Errorf("datagram = %q", msg)
+			}
+		})
+	}
+}
+
+func TestSyslogSyncer_Reconnect(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := lis.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	out, err := NewSyslogSyncer(SyslogConfig{Network: "tcp", Address: addr, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewSyslogSyncer failed: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	// The server goes away: writes are dropped, never failed
+	_ = (<-accepted).Close()
+	_ = lis.Close()
+	for i := 0; i < 50 && out.Dropped() == 0; i++ {
+		if n, err := out.Write([]byte("<14>1 - - - - - - lost\n")); err != nil || n == 0 {
+			t.Fatalf("Write = %d, %v", n, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if out.Dropped() == 0 {
+		t.Fatal("no message dropped after the server closed")
+	}
+	if err := out.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, err := out.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestNewSyslogSyncer_Errors(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closed := lis.Addr().String()
+	_ = lis.Close()
+
+	tests := []struct {
+		cfg  SyslogConfig
+		code string
+	}{
+		{SyslogConfig{Network: "sctp", Address: closed}, string(ErrCodeInvalidOutput)},
+		{SyslogConfig{Network: "tcp", Address: closed, Timeout: time.Second}, string(ErrCodeWriterNotAvailable)},
+	}
+	for _, tt := range tests {
+		if _, err := NewSyslogSyncer(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.code) {
+			t.Errorf("%s: err = %v, want %s", tt.cfg.Network, err, tt.code)
+		}
+	}
+}
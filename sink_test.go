@@ -734,3 +734,15 @@ func (e *errorWriter) Write(p []byte) (int, error) {
 func (e *errorWriter) Sync() error {
 	return e.error
 }
+
+// TestNewJSConsoleSyncer_NativeFallback tests that the console syncer falls back to stdout off WASM
+func TestNewJSConsoleSyncer_NativeFallback(t *testing.T) {
+	if !fileSyncSupported {
+		t.Skip("js/wasm build uses the real console syncer")
+	}
+	ws := NewJSConsoleSyncer()
+	fs, ok := ws.(fileSyncer)
+	if !ok || fs.File != os.Stdout {
+		t.Errorf("expected stdout fileSyncer on native builds, got %T", ws)
+	}
+}
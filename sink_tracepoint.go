@@ -0,0 +1,92 @@
+// sink_tracepoint.go: Linux user_events tracepoint sink for Iris logging library
+//
+// TracepointWriter publishes records as a Linux user_events tracepoint
+// (kernel 6.4+). Kernel-side tooling such as perf, bpftrace, ftrace or LTTng
+// can then capture application logs on the same timeline as scheduler,
+// block I/O or network events, without the application knowing or caring
+// whether anyone is listening.
+//
+// When no tracer is attached the kernel clears an enable bit in process
+// memory and Write returns after a single atomic load, so the sink can stay
+// installed in production (typically via MultiWriteSyncer next to the
+// primary output).
+//
+// The event is registered as "<name> __rel_loc char[] msg"; msg holds the
+// encoded record without its trailing newline.
+//
+// Capturing:
+//
+//	perf record -e user_events:iris_log -- ./app
+//	bpftrace -e 'tracepoint:user_events:iris_log { printf("%s\n", str(args.msg)); }'
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultTracepointName is the user_events event name used when none is given.
+const DefaultTracepointName = "iris_log"
+
+// tracepointMaxMsg caps the message payload; longer records are truncated.
+const tracepointMaxMsg = 4000
+
+// TracepointWriter is a WriteSyncer that emits each record as a user_events
+// tracepoint. See NewTracepointWriter.
+type TracepointWriter struct {
+	name string
+
+	mu         sync.RWMutex // Guards fd against Close during Write
+	fd         int
+	writeIndex uint32
+	enabled    *uint32 // Enable bit target, updated by the kernel
+	closed     bool
+
+	dropped atomic.Int64
+}
+
+// Name returns the tracepoint event name.
+func (w *TracepointWriter) Name() string {
+	return w.name
+}
+
+// Sync is a no-op: events are delivered to the trace buffers when written.
+func (w *TracepointWriter) Sync() error {
+	return nil
+}
+
+// Dropped returns the number of records the kernel rejected while a tracer
+// was attached.
+func (w *TracepointWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// validTracepointName reports whether name is a valid user_events event name.
+func validTracepointName(name string) bool {
+	if name == "" || len(name) > 255 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// trimTracepointMsg strips the record terminator and enforces the size cap.
+func trimTracepointMsg(p []byte) []byte {
+	if n := len(p); n > 0 && p[n-1] == '\n' {
+		p = p[:n-1]
+	}
+	if len(p) > tracepointMaxMsg {
+		p = p[:tracepointMaxMsg]
+	}
+	return p
+}
@@ -0,0 +1,185 @@
+// sink_tracepoint_linux.go: user_events registration and emission
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux && (amd64 || arm64 || riscv64 || loong64 || s390x)
+
+package iris
+
+import (
+	"encoding/binary"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// user_events ioctl requests for 64-bit architectures using the generic
+// ioctl encoding: _IOWR('*', 0, struct user_reg *) and _IOW('*', 2, struct user_unreg *).
+const (
+	userEventsIOCSReg   = 0xC0082A00
+	userEventsIOCSUnreg = 0x40082A02
+
+	userRegSize   = 28 // sizeof(struct user_reg), packed
+	userUnregSize = 16 // sizeof(struct user_unreg), packed
+)
+
+// userEventsDataPaths lists the user_events ABI file under the usual tracefs mounts.
+var userEventsDataPaths = []string{
+	"/sys/kernel/tracing/user_events_data",
+	"/sys/kernel/debug/tracing/user_events_data",
+}
+
+// NewTracepointWriter registers a user_events tracepoint and returns a writer
+// emitting one event per record.
+//
+// Requires Linux 6.4+ with CONFIG_USER_EVENTS and write access to
+// user_events_data in tracefs (root, or a tracing group configured by the
+// administrator).
+//
+// Parameters:
+//   - name: Event name ([A-Za-z0-9_], empty for DefaultTracepointName)
+//
+// Returns:
+//   - *TracepointWriter: Writer ready for use as Config.Output
+//   - error: ErrCodeInvalidOutput for bad names, ErrCodeWriterNotAvailable
+//     if user_events is unavailable or registration fails
+//
+// Example:
+//
+//	tp, err := iris.NewTracepointWriter("")
+//	if err == nil {
+//	    cfg.Output = iris.MultiWriteSyncer(cfg.Output, tp)
+//	}
+func NewTracepointWriter(name string) (*TracepointWriter, error) {
+	if name == "" {
+		name = DefaultTracepointName
+	}
+	if !validTracepointName(name) {
+		return nil, NewLoggerErrorWithField(ErrCodeInvalidOutput, "invalid tracepoint name", "name", name)
+	}
+
+	fd := -1
+	var openErr error
+	for _, path := range userEventsDataPaths {
+		fd, openErr = syscall.Open(path, syscall.O_RDWR|syscall.O_CLOEXEC, 0)
+		if openErr == nil {
+			break
+		}
+	}
+	if fd < 0 {
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "user_events is not available: "+openErr.Error(), "name", name)
+	}
+
+	w := &TracepointWriter{
+		name:    name,
+		fd:      fd,
+		enabled: new(uint32),
+	}
+	if err := w.register(); err != nil {
+		_ = syscall.Close(fd)
+		return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "user_events registration failed: "+err.Error(), "name", name)
+	}
+	return w, nil
+}
+
+// Enabled reports whether a tracer is currently attached to the event.
+func (w *TracepointWriter) Enabled() bool {
+	return atomic.LoadUint32(w.enabled)&1 != 0
+}
+
+// Write emits p as a tracepoint event when a tracer is attached.
+// It always reports len(p); rejected events are counted in Dropped().
+func (w *TracepointWriter) Write(p []byte) (int, error) {
+	if !w.Enabled() {
+		return len(p), nil // Nobody listening: one atomic load
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, NewLoggerError(ErrCodeWriterNotAvailable, "tracepoint writer is closed")
+	}
+
+	msg := trimTracepointMsg(p)
+
+	// Event layout: write_index, __rel_loc (len<<16 | offset 0), msg, NUL
+	var hdr [8]byte
+	binary.NativeEndian.PutUint32(hdr[0:4], w.writeIndex)
+	// #nosec G115 - msg is capped at tracepointMaxMsg
+	binary.NativeEndian.PutUint32(hdr[4:8], uint32(len(msg)+1)<<16)
+	nul := [1]byte{0}
+
+	iov := [3]syscall.Iovec{{Base: &hdr[0]}, {}, {Base: &nul[0]}}
+	iov[0].SetLen(len(hdr))
+	iov[2].SetLen(1)
+	iovcnt := 3
+	if len(msg) > 0 {
+		iov[1].Base = &msg[0]
+		iov[1].SetLen(len(msg))
+	} else {
+		iov[1] = iov[2]
+		iovcnt = 2
+	}
+
+	for {
+		// #nosec G103 - writev needs the iovec array address
+		_, _, errno := syscall.Syscall(syscall.SYS_WRITEV, uintptr(w.fd), uintptr(unsafe.Pointer(&iov[0])), uintptr(iovcnt))
+		if errno == syscall.EINTR {
+			continue
+		}
+		if errno != 0 {
+			w.dropped.Add(1)
+		}
+		break
+	}
+	runtime.KeepAlive(msg)
+	return len(p), nil
+}
+
+// Close unregisters the event and releases the user_events file.
+func (w *TracepointWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	var unreg [userUnregSize]byte
+	binary.NativeEndian.PutUint32(unreg[0:4], userUnregSize)
+	unreg[4] = 0 // disable_bit
+	// #nosec G103 - the kernel needs the address of the enable word
+	binary.NativeEndian.PutUint64(unreg[8:16], uint64(uintptr(unsafe.Pointer(w.enabled))))
+	// #nosec G103 - ioctl argument
+	_, _, _ = syscall.Syscall(syscall.SYS_IOCTL, uintptr(w.fd), userEventsIOCSUnreg, uintptr(unsafe.Pointer(&unreg[0])))
+
+	return syscall.Close(w.fd)
+}
+
+// register issues DIAG_IOCSREG for "<name> __rel_loc char[] msg".
+func (w *TracepointWriter) register() error {
+	nameArgs := append([]byte(w.name+" __rel_loc char[] msg"), 0)
+
+	var reg [userRegSize]byte
+	binary.NativeEndian.PutUint32(reg[0:4], userRegSize)
+	reg[4] = 0 // enable_bit
+	reg[5] = 4 // enable_size: 32-bit enable word
+	// #nosec G103 - the kernel updates the enable word at this address
+	binary.NativeEndian.PutUint64(reg[8:16], uint64(uintptr(unsafe.Pointer(w.enabled))))
+	// #nosec G103 - the kernel reads the event description at this address
+	binary.NativeEndian.PutUint64(reg[16:24], uint64(uintptr(unsafe.Pointer(&nameArgs[0]))))
+
+	// #nosec G103 - ioctl argument
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(w.fd), userEventsIOCSReg, uintptr(unsafe.Pointer(&reg[0])))
+	runtime.KeepAlive(nameArgs)
+	runtime.KeepAlive(w.enabled)
+	if errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	w.writeIndex = binary.NativeEndian.Uint32(reg[24:28])
+	return nil
+}
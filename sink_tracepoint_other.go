@@ -0,0 +1,29 @@
+// sink_tracepoint_other.go: Tracepoint sink on platforms without user_events
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !(linux && (amd64 || arm64 || riscv64 || loong64 || s390x))
+
+package iris
+
+// NewTracepointWriter is only supported on 64-bit Linux.
+func NewTracepointWriter(name string) (*TracepointWriter, error) {
+	return nil, NewLoggerErrorWithField(ErrCodeWriterNotAvailable, "user_events tracepoints are only supported on 64-bit Linux", "name", name)
+}
+
+// Enabled always reports false on this platform.
+func (w *TracepointWriter) Enabled() bool {
+	return false
+}
+
+// Write discards p on this platform.
+func (w *TracepointWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Close is a no-op on this platform.
+func (w *TracepointWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+// sink_tracepoint_test.go: Tests for the user_events tracepoint sink
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidTracepointName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"iris_log", true},
+		{"App2_Events", true},
+		{"", false},
+		{"has space", false},
+		{"semi;colon", false},
+		{"dash-name", false},
+		{strings.Repeat("a", 256), false},
+	}
+	for _, tt := range tests {
+		if got := validTracepointName(tt.name); got != tt.valid {
+			t.Errorf("validTracepointName(%.20q) = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestTrimTracepointMsg(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"\n", 0},
+		{"record\n", 6},
+		{"no newline", 10},
+		{strings.Repeat("x", tracepointMaxMsg+100) + "\n", tracepointMaxMsg},
+	}
+	for _, tt := range tests {
+		if got := trimTracepointMsg([]byte(tt.in)); len(got) != tt.want {
+			t.Errorf("trimTracepointMsg(%.20q) has length %d, want %d", tt.in, len(got), tt.want)
+		}
+	}
+}
+
+func TestTracepointWriter_InvalidName(t *testing.T) {
+	if _, err := NewTracepointWriter("bad name"); err == nil {
+		t.Error("expected error for an invalid event name")
+	}
+}
+
+func TestTracepointWriter_DisabledIsNoop(t *testing.T) {
+	// A writer nobody traces must accept records without touching the kernel
+	w := &TracepointWriter{name: DefaultTracepointName, fd: -1, enabled: new(uint32)}
+	if w.Enabled() {
+		t.Fatal("writer should start disabled")
+	}
+	if n, err := w.Write([]byte("record\n")); err != nil || n != 7 {
+		t.Errorf("Write = %d, %v", n, err)
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("disabled writes must not count as drops, got %d", w.Dropped())
+	}
+}
+
+func TestTracepointWriter_Register(t *testing.T) {
+	w, err := NewTracepointWriter("iris_test_event")
+	if err != nil {
+		t.Skipf("user_events not available: %v", err)
+	}
+	if w.Name() != "iris_test_event" {
+		t.Errorf("Name() = %q", w.Name())
+	}
+
+	logger, err := New(Config{Level: Info, Output: w, Encoder: NewJSONEncoder(), Capacity: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	logger.Info("tracepoint record")
+	safeCloseWithOptionsLogger(t, logger)
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close should be a no-op, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+// sink_vectored.go: Batch-coalescing sink submitting records with writev
+//
+// The consumer processes records in batches. A plain output receives one
+// write syscall per record; VectoredWriter instead keeps each encoded record
+// in its own pooled buffer until the end of the batch and submits them all
+// with a single vectored write (writev), without concatenating them into one
+// large buffer first.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/agilira/iris/internal/bufferpool"
+)
+
+// defaultVectoredBatch is the number of records after which a VectoredWriter
+// submits even if the batch has not ended. It matches the Linux IOV_MAX.
+const defaultVectoredBatch = 1024
+
+// BatchSink is implemented by outputs that coalesce records and need to
+// know when the consumer has finished a batch. The logger calls EndBatch
+// from its consumer goroutine after each batch of records it processed.
+type BatchSink interface {
+	EndBatch() error
+}
+
+// VectoredWriter coalesces the records of each consumer batch and writes
+// them with one vectored write.
+//
+// Files are written with the writev system call on Linux. Connections whose
+// net.Buffers support vectored writes (TCP and unix stream sockets) use
+// writev through the net package on every platform. Other writers, and
+// files elsewhere, receive one Write per record at the end of the batch,
+// still without concatenation.
+//
+// VectoredWriter implements StreamSink, so the built-in encoders encode each
+// record straight into the buffer that is later handed to writev, and
+// BatchSink, so a batch is submitted as soon as the consumer finishes it.
+// Records are also submitted once maxBatch of them are pending, on Sync and
+// on Close. Used outside a logger (without EndBatch calls), records are
+// only written on those occasions.
+type VectoredWriter struct {
+	w        io.Writer
+	file     *os.File // Non-nil: written with writev where supported
+	maxBatch int
+
+	mu      sync.Mutex
+	cur     *bytes.Buffer   // Record being encoded (consumer only)
+	bufs    []*bytes.Buffer // Pending records
+	pending [][]byte        // Bytes of bufs, reused across submissions
+	iov     iovecs          // writev scratch on platforms that use it
+	closed  bool
+
+	submits atomic.Int64
+	records atomic.Int64
+}
+
+// NewVectoredWriter returns a writer coalescing records for w.
+//
+// Parameters:
+//   - w: Destination; an *os.File or a net.Conn gets vectored writes
+//   - maxBatch: Maximum pending records before a submission (<= 0 for 1024)
+//
+// Returns:
+//   - *VectoredWriter: Writer to use as Config.Output
+//
+// Example:
+//
+//	f, _ := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+//	logger, _ := iris.New(iris.Config{Output: iris.NewVectoredWriter(f, 0)})
+func NewVectoredWriter(w io.Writer, maxBatch int) *VectoredWriter {
+	if maxBatch <= 0 {
+		maxBatch = defaultVectoredBatch
+	}
+	vw := &VectoredWriter{w: w, maxBatch: maxBatch}
+	if f, ok := w.(*os.File); ok {
+		vw.file = f
+	}
+	return vw
+}
+
+// Write queues a copy of p as one record.
+func (w *VectoredWriter) Write(p []byte) (int, error) {
+	buf := bufferpool.GetSized(len(p))
+	buf.Write(p)
+	return len(p), w.queue(buf)
+}
+
+// RecordWriter implements StreamSink: the next record is encoded into a
+// pooled buffer of its own.
+func (w *VectoredWriter) RecordWriter() io.Writer {
+	w.cur = bufferpool.Get()
+	return w.cur
+}
+
+// EndRecord implements StreamSink by queueing the record just encoded.
+func (w *VectoredWriter) EndRecord() error {
+	buf := w.cur
+	w.cur = nil
+	if buf == nil {
+		return nil
+	}
+	return w.queue(buf)
+}
+
+// EndBatch implements BatchSink by submitting the pending records.
+func (w *VectoredWriter) EndBatch() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.submitLocked()
+}
+
+// Sync submits the pending records and syncs the destination when it
+// supports it.
+func (w *VectoredWriter) Sync() error {
+	w.mu.Lock()
+	err := w.submitLocked()
+	w.mu.Unlock()
+	if s, ok := w.w.(interface{ Sync() error }); ok {
+		if serr := s.Sync(); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// Close submits the pending records and closes the destination when it is
+// an io.Closer. Records written afterwards are discarded with an error.
+func (w *VectoredWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	err := w.submitLocked()
+	w.closed = true
+	w.mu.Unlock()
+	if c, ok := w.w.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Submissions returns the number of vectored submissions made so far.
+func (w *VectoredWriter) Submissions() int64 {
+	return w.submits.Load()
+}
+
+// Records returns the number of records submitted so far.
+func (w *VectoredWriter) Records() int64 {
+	return w.records.Load()
+}
+
+// queue adds buf to the pending records, submitting them once maxBatch
+// are pending.
+func (w *VectoredWriter) queue(buf *bytes.Buffer) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		bufferpool.Put(buf)
+		return NewLoggerError(ErrCodeWriterNotAvailable, "vectored writer is closed")
+	}
+	w.bufs = append(w.bufs, buf)
+	if len(w.bufs) >= w.maxBatch {
+		return w.submitLocked()
+	}
+	return nil
+}
+
+// submitLocked writes the pending records and releases their buffers.
+// The caller holds w.mu.
+func (w *VectoredWriter) submitLocked() error {
+	if len(w.bufs) == 0 {
+		return nil
+	}
+	w.pending = w.pending[:0]
+	for _, buf := range w.bufs {
+		w.pending = append(w.pending, buf.Bytes())
+	}
+
+	var err error
+	if w.file != nil && writevSupported {
+		err = w.iov.writev(w.file, w.pending)
+	} else {
+		bufs := net.Buffers(w.pending) // writev for TCP and unix stream sockets
+		_, err = bufs.WriteTo(w.w)
+	}
+	w.submits.Add(1)
+	w.records.Add(int64(len(w.bufs)))
+
+	for i, buf := range w.bufs {
+		bufferpool.Put(buf)
+		w.bufs[i] = nil
+	}
+	w.bufs = w.bufs[:0]
+	clear(w.pending)
+	return err
+}
+
+// batchSinks returns the logger's outputs that implement BatchSink.
+func (l *Logger) batchSinks() []BatchSink {
+	var sinks []BatchSink
+	add := func(out WriteSyncer) {
+		if s, ok := out.(BatchSink); ok {
+			sinks = append(sinks, s)
+		}
+	}
+	add(l.out)
+	for _, o := range l.opts.outputs {
+		add(o.out)
+	}
+	if l.opts.classifier != nil && l.opts.classifier.restricted != nil {
+		add(l.opts.classifier.restricted)
+	}
+	return sinks
+}
+
+// endBatch notifies sinks that the consumer finished a batch. Errors are
+// dropped like write errors on the record path.
+func endBatch(sinks []BatchSink) {
+	for _, s := range sinks {
+		_ = s.EndBatch()
+	}
+}
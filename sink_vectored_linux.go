@@ -0,0 +1,87 @@
+// sink_vectored_linux.go: writev for VectoredWriter files on Linux
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package iris
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// writevSupported reports whether files are written with writev.
+const writevSupported = true
+
+// iovMax is the largest iovec count a single writev accepts (IOV_MAX).
+const iovMax = 1024
+
+// iovecs is the reusable iovec array for writev.
+type iovecs struct {
+	v []syscall.Iovec
+}
+
+// writev writes bufs to f in order with as few writev calls as possible,
+// resuming after short writes and EINTR.
+func (iv *iovecs) writev(f *os.File, bufs [][]byte) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	for len(bufs) > 0 {
+		iv.v = iv.v[:0]
+		for _, b := range bufs {
+			if len(iv.v) == iovMax {
+				break
+			}
+			if len(b) == 0 {
+				continue
+			}
+			iov := syscall.Iovec{Base: &b[0]}
+			iov.SetLen(len(b))
+			iv.v = append(iv.v, iov)
+		}
+		if len(iv.v) == 0 {
+			return nil
+		}
+
+		var n uintptr
+		var errno syscall.Errno
+		werr := rc.Write(func(fd uintptr) bool {
+			for {
+				// #nosec G103 - writev needs the iovec array address
+				n, _, errno = syscall.Syscall(syscall.SYS_WRITEV, fd, uintptr(unsafe.Pointer(&iv.v[0])), uintptr(len(iv.v)))
+				if errno != syscall.EINTR {
+					break
+				}
+			}
+			return errno != syscall.EAGAIN // Wait for the poller on non-blocking files
+		})
+		if werr != nil {
+			return werr
+		}
+		if errno != 0 {
+			return os.NewSyscallError("writev", errno)
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+
+		// Skip what was written, keeping the unwritten tail of a partial buffer
+		written := int(n)
+		for len(bufs) > 0 && written >= len(bufs[0]) {
+			written -= len(bufs[0])
+			bufs = bufs[1:]
+		}
+		if written > 0 {
+			bufs[0] = bufs[0][written:]
+		}
+	}
+	clear(iv.v)
+	return nil
+}
@@ -0,0 +1,27 @@
+// sink_vectored_other.go: VectoredWriter file fallback outside Linux
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package iris
+
+import "os"
+
+// writevSupported reports whether files are written with writev.
+const writevSupported = false
+
+// iovecs is empty where files are not written with writev.
+type iovecs struct{}
+
+// writev is never called where writevSupported is false.
+func (iovecs) writev(f *os.File, bufs [][]byte) error {
+	for _, b := range bufs {
+		if _, err := f.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// sink_vectored_test.go: Tests for the vectored batch writer
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkRecords verifies that data holds want JSON records numbered 0..want-1
+// in order.
+func checkRecords(t *testing.T, data []byte, want int) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != want {
+		t.Fatalf("got %d records, want %d", len(lines), want)
+	}
+	for i, line := range lines {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("record %d is not intact JSON: %q (%v)", i, line, err)
+		}
+		if n, _ := m["n"].(float64); int(n) != i {
+			t.Fatalf("record %d carries n=%v, records out of order", i, m["n"])
+		}
+	}
+}
+
+func TestVectoredWriter_Destinations(t *testing.T) {
+	const records = 3000 // More than IOV_MAX: several writev calls per batch
+
+	for _, tc := range []struct {
+		name string
+		open func(t *testing.T) (io.Writer, func() []byte)
+	}{
+		{"file", func(t *testing.T) (io.Writer, func() []byte) {
+			path := filepath.Join(t.TempDir(), "vectored.log")
+			f, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return f, func() []byte {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return data
+			}
+		}},
+		{"unix socket", func(t *testing.T) (io.Writer, func() []byte) {
+			return socketPair(t, "unix", filepath.Join(t.TempDir(), "v.sock"))
+		}},
+		{"tcp socket", func(t *testing.T) (io.Writer, func() []byte) {
+			return socketPair(t, "tcp", "127.0.0.1:0")
+		}},
+		{"plain writer", func(t *testing.T) (io.Writer, func() []byte) {
+			var buf bytes.Buffer
+			return &buf, buf.Bytes
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, read := tc.open(t)
+			vw := NewVectoredWriter(w, 4096)
+			logger, err := New(Config{Level: Info, Output: vw, Encoder: NewJSONEncoder(), Capacity: 4096, BatchSize: 4096})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			for i := 0; i < records; i++ {
+				logger.Info("vectored record with some padding to make it longer", Int("n", i))
+			}
+			logger.Start()
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			_ = vw.Close()
+
+			checkRecords(t, read(), records)
+			if vw.Records() != records {
+				t.Errorf("Records() = %d, want %d", vw.Records(), records)
+			}
+			if vw.Submissions() >= records/10 {
+				t.Errorf("%d submissions for %d records, want them coalesced", vw.Submissions(), records)
+			}
+		})
+	}
+}
+
+// socketPair returns the client end of a connection and a function reading
+// everything the server end received until the client closes.
+func socketPair(t *testing.T, network, addr string) (io.Writer, func() []byte) {
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s: %v", network, err)
+	}
+	received := make(chan []byte, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(bufio.NewReader(conn))
+		received <- data
+	}()
+	conn, err := net.Dial(network, ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, func() []byte { return <-received }
+}
+
+func TestVectoredWriter_SubmitsOnEndBatch(t *testing.T) {
+	var buf bytes.Buffer
+	vw := NewVectoredWriter(&buf, 0)
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(vw, "{\"n\":%d}\n", i)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("records written before the batch ended: %q", buf.String())
+	}
+	if err := vw.EndBatch(); err != nil {
+		t.Fatalf("EndBatch failed: %v", err)
+	}
+	checkRecords(t, buf.Bytes(), 3)
+	if vw.Submissions() != 1 {
+		t.Errorf("Submissions() = %d, want 1", vw.Submissions())
+	}
+
+	_ = vw.Close()
+	if _, err := vw.Write([]byte("late\n")); !IsLoggerError(err, ErrCodeWriterNotAvailable) {
+		t.Errorf("Write after Close = %v, want %s", err, ErrCodeWriterNotAvailable)
+	}
+}
+
+func TestVectoredWriter_MaxBatch(t *testing.T) {
+	var buf bytes.Buffer
+	vw := NewVectoredWriter(&buf, 2)
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(vw, "{\"n\":%d}\n", i)
+	}
+	if vw.Records() != 4 || vw.Submissions() != 2 {
+		t.Errorf("after 5 records with maxBatch 2: %d records in %d submissions, want 4 in 2", vw.Records(), vw.Submissions())
+	}
+	_ = vw.Sync()
+	checkRecords(t, buf.Bytes(), 5)
+}
+
+func TestVectoredWriter_InlineAndOutputs(t *testing.T) {
+	var main, extra bytes.Buffer
+	mainVW, extraVW := NewVectoredWriter(&main, 0), NewVectoredWriter(&extra, 0)
+	logger, err := New(Config{Level: Info, Output: mainVW, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true},
+		WithOutput(NewJSONEncoder(), extraVW))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		logger.Info("inline", Int("n", i))
+		if main.Len() == 0 || extra.Len() == 0 {
+			t.Fatalf("record %d not submitted at the end of its inline write", i)
+		}
+	}
+	_ = logger.Close()
+	checkRecords(t, main.Bytes(), 4)
+	checkRecords(t, extra.Bytes(), 4)
+}
+
+func BenchmarkVectoredWriter_File(b *testing.B) {
+	f, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	record := []byte(`{"level":"info","msg":"benchmark record","n":42}` + "\n")
+	for _, tc := range []struct {
+		name string
+		w    io.Writer
+	}{
+		{"direct", f},
+		{"vectored", NewVectoredWriter(f, 0)},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(record)))
+			for i := 0; i < b.N; i++ {
+				_, _ = tc.w.Write(record)
+				if i%256 == 255 {
+					if bs, ok := tc.w.(BatchSink); ok {
+						_ = bs.EndBatch()
+					}
+				}
+			}
+			if bs, ok := tc.w.(BatchSink); ok {
+				_ = bs.EndBatch()
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// slog_handler.go: log/slog Handler backed by an Iris logger
+//
+// Code written against the standard library's log/slog can keep its slog
+// calls and still log through Iris: SlogHandler turns every slog record into
+// an Iris record on the logger's ring buffer, so it gets the same level
+// filter, sampling, hooks, outputs and encoders as the rest of the program.
+// Attributes become Fields of the matching type; groups are flattened into
+// dotted keys ("request.method") since Iris fields are not nested.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that writes records through an Iris Logger.
+// Create it with NewSlogHandler.
+type SlogHandler struct {
+	logger *Logger
+	prefix string // Key prefix of the open groups, "a.b." (empty = none)
+}
+
+// NewSlogHandler returns a slog.Handler that logs through logger.
+//
+// slog levels map onto the closest Iris level at or below them: below
+// LevelDebug is Trace, LevelDebug is Debug, LevelInfo is Info, LevelWarn is
+// Warn, and LevelError and above are Error, so a slog record never panics
+// or exits the program. Attributes added with slog.Logger.With become base
+// fields of a derived logger (see Logger.With), and the record time is the
+// time at which Iris encodes the record, as for Iris's own logging calls.
+//
+// With WithCaller, the caller is the code that called the slog.Logger
+// method; records handed to the handler by other means may report a frame
+// inside the code that did.
+//
+// Parameters:
+//   - logger: Logger the records are written to
+//
+// Returns:
+//   - *SlogHandler: Handler for slog.New
+//
+// Example:
+//
+//	logger, _ := iris.New(iris.Config{Level: iris.Info, Output: os.Stdout})
+//	logger.Start()
+//	slog.SetDefault(slog.New(iris.NewSlogHandler(logger)))
+//
+//	slog.Info("request served", "method", "GET", slog.Group("user", "id", 42))
+//	// {"ts":"...","level":"info","msg":"request served","method":"GET","user.id":42}
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// Enabled reports whether a record at level might be logged. With
+// per-package levels or active debug sessions it reports true for every
+// level and leaves the decision to Handle, which knows the caller and the
+// record's fields.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	l := h.logger
+	return levelFromSlog(level).Enabled(l.level.Level()) || l.sources.active() || l.sessions.active()
+}
+
+// Handle writes r through the logger. ctx bounds the wait for a free slot
+// under BlockOnFull. Records the logger drops are counted in Logger.Stats,
+// not reported as errors, so Handle always returns nil.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var fields []Field
+	if r.NumAttrs() > 0 {
+		fields = make([]Field, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendSlogAttr(fields, h.prefix, a)
+			return true
+		})
+	}
+	level := levelFromSlog(r.Level)
+	l := h.logger
+	// Frames between emit and the slog.Logger method: Handle and slog's log
+	if !l.shouldLog(2, level, r.Message, fields) {
+		return nil
+	}
+	l.emit(ctx, 2, level, r.Message, l.withContextFields(ctx, fields)...)
+	return nil
+}
+
+// WithAttrs returns a handler whose records carry attrs, within the groups
+// opened so far.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, h.prefix, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return &SlogHandler{logger: h.logger.With(fields...), prefix: h.prefix}
+}
+
+// WithGroup returns a handler that prefixes the keys of the attributes
+// added later with name and a dot.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+// levelFromSlog maps a slog level onto the closest Iris level at or below
+// it, capped at Error.
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return Trace
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	default:
+		return Error
+	}
+}
+
+// appendSlogAttr appends the fields of a to fields, following the
+// slog.Handler rules: empty attributes and empty groups are left out, and
+// the attributes of a group without a key are inlined.
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	key := prefix + a.Key
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return append(fields, Str(key, v.String()))
+	case slog.KindInt64:
+		return append(fields, Int64(key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, Uint64(key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, Float64(key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, Bool(key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, Dur(key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, TimeField(key, v.Time()))
+	}
+	switch x := v.Any().(type) {
+	case error:
+		return append(fields, NamedError(key, x))
+	case []byte:
+		return append(fields, Bytes(key, x))
+	case interface{ String() string }:
+		return append(fields, Stringer(key, x))
+	default:
+		return append(fields, Object(key, x))
+	}
+}
@@ -0,0 +1,158 @@
+// slog_handler_test.go: Tests for the log/slog Handler adapter
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSlogTestLogger returns an inline JSON logger and its output.
+func newSlogTestLogger(t *testing.T, level Level, opts ...Option) (*Logger, *testSyncer) {
+	t.Helper()
+	out := &testSyncer{}
+	logger, err := New(Config{Level: level, Output: out, Encoder: NewJSONEncoder(), Inline: true}, opts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, out
+}
+
+// decodeLine decodes the output line of the record logged with msg.
+func decodeLine(t *testing.T, output, msg string) map[string]any {
+	t.Helper()
+	line := findLine(output, msg)
+	if line == "" {
+		t.Fatalf("no line for %q in:\n%s", msg, output)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	return m
+}
+
+func TestSlogHandler_Levels(t *testing.T) {
+	tests := []struct {
+		slog slog.Level
+		want Level
+	}{
+		{slog.LevelDebug - 4, Trace},
+		{slog.LevelDebug, Debug},
+		{slog.LevelDebug + 2, Debug},
+		{slog.LevelInfo, Info},
+		{slog.LevelWarn, Warn},
+		{slog.LevelError, Error},
+		{slog.LevelError + 8, Error},
+	}
+	for _, tt := range tests {
+		if got := levelFromSlog(tt.slog); got != tt.want {
+			t.Errorf("levelFromSlog(%v) = %v, want %v", tt.slog, got, tt.want)
+		}
+	}
+
+	logger, out := newSlogTestLogger(t, Info)
+	h := NewSlogHandler(logger)
+	if h.Enabled(context.Background(), slog.LevelDebug) || !h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Enabled does not follow the logger level")
+	}
+	sl := slog.New(h)
+	sl.Debug("hidden")
+	sl.Log(context.Background(), slog.LevelError+4, "critical")
+	if strings.Contains(out.String(), "hidden") {
+		t.Errorf("debug record logged at Info:\n%s", out.String())
+	}
+	if m := decodeLine(t, out.String(), "critical"); m["level"] != "error" {
+		t.Errorf("level = %v, want error", m["level"])
+	}
+}
+
+func TestSlogHandler_Attrs(t *testing.T) {
+	logger, out := newSlogTestLogger(t, Debug)
+	sl := slog.New(NewSlogHandler(logger)).With("service", "api").WithGroup("req").With("id", 7)
+
+	when := time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)
+	sl.Info("served",
+		"method", "GET",
+		slog.Duration("took", 1500*time.Millisecond),
+		slog.Uint64("bytes", 512),
+		slog.Float64("ratio", 0.5),
+		slog.Bool("cached", true),
+		slog.Time("at", when),
+		slog.Any("err", errors.New("partial")),
+		slog.Group("user", "name", "ada", slog.Group("empty")),
+		slog.Group("", "inlined", 1),
+		slog.Attr{},
+	)
+
+	m := decodeLine(t, out.String(), "served")
+	want := map[string]any{
+		"service":       "api",
+		"req.id":        float64(7),
+		"req.method":    "GET",
+		"req.took":      float64(1500 * time.Millisecond),
+		"req.bytes":     float64(512),
+		"req.ratio":     0.5,
+		"req.cached":    true,
+		"req.at":        "2025-09-02T12:00:00Z",
+		"req.err":       "partial",
+		"req.user.name": "ada",
+		"req.inlined":   float64(1),
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%s = %#v, want %#v", k, m[k], v)
+		}
+	}
+	for k := range m {
+		if strings.Contains(k, "empty") || k == "" {
+			t.Errorf("unexpected key %q in %v", k, m)
+		}
+	}
+}
+
+// slogValuer resolves to a fixed value.
+type slogValuer struct{}
+
+func (slogValuer) LogValue() slog.Value { return slog.StringValue("resolved") }
+
+func TestSlogHandler_LogValuerAndEmptyGroup(t *testing.T) {
+	logger, out := newSlogTestLogger(t, Info)
+	sl := slog.New(NewSlogHandler(logger))
+
+	sl.WithGroup("unused").Info("no attrs")
+	sl.Info("valuer", "v", slogValuer{})
+
+	if m := decodeLine(t, out.String(), "no attrs"); len(m) != 3 {
+		t.Errorf("empty group produced keys: %v", m)
+	}
+	if m := decodeLine(t, out.String(), "valuer"); m["v"] != "resolved" {
+		t.Errorf("v = %v, want the resolved value", m["v"])
+	}
+}
+
+func TestSlogHandler_Caller(t *testing.T) {
+	logger, out := newSlogTestLogger(t, Info, WithCaller())
+	sl := slog.New(NewSlogHandler(logger))
+
+	sl.Info("from method")
+	sl.InfoContext(context.Background(), "from context method")
+	sl.LogAttrs(context.Background(), slog.LevelInfo, "from attrs", slog.Int("n", 1))
+
+	for _, msg := range []string{"from method", "from context method", "from attrs"} {
+		caller, _ := decodeLine(t, out.String(), msg)["caller"].(string)
+		if !strings.Contains(caller, "/slog_handler_test.go:") {
+			t.Errorf("%s: caller = %q, want the test file", msg, caller)
+		}
+	}
+}
@@ -137,6 +137,28 @@ func TestSmartAPI_CapacityOptimization(t *testing.T) {
 	// Should complete without errors
 }
 
+// TestSmartAPI_EffectiveConfig tests that explicit values survive smart defaults
+func TestSmartAPI_EffectiveConfig(t *testing.T) {
+	eff := EffectiveConfig(Config{Capacity: 2048, BatchSize: 16, Name: "svc"})
+	if eff.Capacity != 2048 || eff.BatchSize != 16 || eff.Name != "svc" {
+		t.Errorf("explicit values not preserved: capacity=%d batch=%d name=%q", eff.Capacity, eff.BatchSize, eff.Name)
+	}
+
+	eff = EffectiveConfig(Config{})
+	if eff.Capacity <= 0 || eff.BatchSize != 32 || eff.IdleStrategy == nil || eff.Encoder == nil || eff.Output == nil {
+		t.Errorf("smart defaults not applied: %+v", eff)
+	}
+
+	logger, err := New(Config{Capacity: 1024, BatchSize: 8})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer safeCloseSmartAPILogger(t, logger)
+	if got := logger.Stats()["batch_size"]; got != 8 {
+		t.Errorf("batch_size = %d, want 8", got)
+	}
+}
+
 // TestSmartAPI_LevelDetection tests smart level detection from environment
 func TestSmartAPI_LevelDetection(t *testing.T) {
 	// Test with environment variable
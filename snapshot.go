@@ -0,0 +1,79 @@
+// snapshot.go: Pending record inspection for debugging Iris loggers
+//
+// When the ring buffer drops records or Sync times out, the interesting
+// question is what is sitting in the buffer. SnapshotPending copies the
+// pending records out of the ring without disturbing producers, so a debug
+// endpoint can show them.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+// RecordView is a read-only copy of a log record taken by SnapshotPending.
+//
+// Views share no memory with the ring buffer and stay valid after the
+// original record has been processed and its slot reused.
+type RecordView struct {
+	Level  Level   // Log level
+	Msg    string  // Log message
+	Logger string  // Logger name
+	Caller string  // Caller information (file:line)
+	Stack  string  // Stack trace
+	Fields []Field // Structured fields (byte values are copied)
+}
+
+// SnapshotPending returns copies of up to max records that have been
+// accepted by the ring buffer but not yet processed, oldest first.
+//
+// The consumer is paused only while the records are copied; producers are
+// never blocked. Records whose producer is still filling the slot are not
+// included. In inline mode records are processed before the logging call
+// returns, so the result is always empty.
+//
+// Parameters:
+//   - max: Maximum number of records to return (<= 0 returns nil)
+//
+// Returns:
+//   - []RecordView: Pending records, oldest first
+//
+// Example:
+//
+//	http.HandleFunc("/debug/iris/pending", func(w http.ResponseWriter, r *http.Request) {
+//	    for _, rec := range logger.SnapshotPending(100) {
+//	        fmt.Fprintf(w, "%s %s (%d fields)\n", rec.Level, rec.Msg, len(rec.Fields))
+//	    }
+//	})
+func (l *Logger) SnapshotPending(max int) []RecordView {
+	if max <= 0 {
+		return nil
+	}
+	var views []RecordView
+	l.r.Snapshot(max, func(rec *Record) {
+		views = append(views, newRecordView(rec))
+	})
+	return views
+}
+
+// newRecordView deep-copies the record contents that may be reused later.
+func newRecordView(rec *Record) RecordView {
+	view := RecordView{
+		Level:  rec.Level,
+		Msg:    rec.Msg,
+		Logger: rec.Logger,
+		Caller: rec.Caller,
+		Stack:  rec.Stack,
+	}
+	if n := rec.FieldCount(); n > 0 {
+		view.Fields = make([]Field, n)
+		for i := range view.Fields {
+			f := rec.fields[i]
+			if f.B != nil {
+				f.B = append([]byte(nil), f.B...)
+			}
+			view.Fields[i] = f
+		}
+	}
+	return view
+}
@@ -0,0 +1,125 @@
+// snapshot_test.go: Tests for pending record inspection
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSnapshotPending_ReturnsUnprocessedRecords(t *testing.T) {
+	buf := &testSyncer{}
+	logger, err := New(Config{
+		Level:     Debug,
+		Output:    buf,
+		Encoder:   NewJSONEncoder(),
+		Capacity:  64,
+		AutoStart: AutoStartOff,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	// Not started: records stay in the ring
+	raw := []byte("payload")
+	logger.Info("first", Str("k", "v"))
+	logger.Named("db").Warn("second", Bytes("raw", raw))
+	logger.Error("third")
+
+	tests := []struct {
+		max  int
+		want int
+	}{
+		{0, 0},
+		{-1, 0},
+		{2, 2},
+		{10, 3},
+	}
+	for _, tt := range tests {
+		if got := len(logger.SnapshotPending(tt.max)); got != tt.want {
+			t.Errorf("SnapshotPending(%d) returned %d records, want %d", tt.max, got, tt.want)
+		}
+	}
+
+	views := logger.SnapshotPending(10)
+	if views[0].Msg != "first" || views[0].Level != Info || len(views[0].Fields) != 1 {
+		t.Errorf("unexpected first view: %+v", views[0])
+	}
+	if views[1].Logger != "db" || views[1].Fields[0].Key() != "raw" {
+		t.Errorf("unexpected second view: %+v", views[1])
+	}
+
+	// Views are independent copies
+	raw[0] = 'X'
+	if string(views[1].Fields[0].BytesValue()) != "payload" {
+		t.Errorf("view shares byte storage with the record: %q", views[1].Fields[0].BytesValue())
+	}
+
+	logger.Start()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if pending := logger.SnapshotPending(10); len(pending) != 0 {
+		t.Errorf("expected no pending records after Sync, got %d", len(pending))
+	}
+	if views[2].Msg != "third" {
+		t.Errorf("views must survive processing, got %q", views[2].Msg)
+	}
+}
+
+func TestSnapshotPending_InlineModeIsEmpty(t *testing.T) {
+	logger, err := New(Config{
+		Level:   Debug,
+		Output:  &testSyncer{},
+		Encoder: NewJSONEncoder(),
+		Inline:  true,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("processed immediately")
+	if views := logger.SnapshotPending(10); views != nil {
+		t.Errorf("expected nil in inline mode, got %v", views)
+	}
+}
+
+func TestSnapshotPending_ConcurrentWithLogging(t *testing.T) {
+	logger, err := New(Config{
+		Level:    Debug,
+		Output:   &testSyncer{},
+		Encoder:  NewJSONEncoder(),
+		Capacity: 4096,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				logger.Info("busy", Int("i", i))
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		for _, v := range logger.SnapshotPending(32) {
+			if v.Msg != "busy" {
+				t.Fatalf("snapshot returned a record in an inconsistent state: %+v", v)
+			}
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,209 @@
+// source_level.go: Minimum levels keyed by the calling package
+//
+// Not every codebase threads Named() loggers through its packages, so the
+// logger name is often no help in turning up one subsystem's verbosity.
+// Source levels key the minimum level on the import path of the code that
+// calls the logging method instead: "github.com/acme/payments" at Debug
+// lifts that package and its subpackages while the rest of the service
+// stays at Info. Rules change at runtime and are shared by a logger and
+// its clones.
+//
+// Design:
+//   - Rules live in a copy-on-write slice behind an atomic pointer, sorted
+//     longest prefix first; with no rules the check is one atomic load
+//   - The calling package is resolved from the caller PC once per call site
+//     and cached, so an active rule set costs a runtime.Callers and a map
+//     lookup per record rather than a symbol table walk
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// sourceRule is the minimum level for packages under prefix.
+type sourceRule struct {
+	prefix string
+	level  Level
+}
+
+// sourceLevels holds the source level rules shared by a logger and its clones.
+type sourceLevels struct {
+	mu    sync.Mutex                   // Serializes SetSourceLevel and RemoveSourceLevel
+	rules atomic.Pointer[[]sourceRule] // nil or non-empty, never modified in place
+}
+
+// callerPackages caches the package of each call site PC. Call sites are
+// finite, so the cache is bounded by the size of the program.
+var callerPackages sync.Map // uintptr -> string
+
+// active reports whether any rule is set. One atomic load.
+func (s *sourceLevels) active() bool {
+	return s.rules.Load() != nil
+}
+
+// levelFor returns the minimum level for records logged from pkg: the level
+// of the longest matching rule, or def if no rule matches.
+func (s *sourceLevels) levelFor(pkg string, def Level) Level {
+	rules := s.rules.Load()
+	if rules == nil || pkg == "" {
+		return def
+	}
+	for _, rule := range *rules {
+		if matchesPackage(pkg, rule.prefix) {
+			return rule.level
+		}
+	}
+	return def
+}
+
+// matchesPackage reports whether pkg is prefix or one of its subpackages.
+func matchesPackage(pkg, prefix string) bool {
+	return strings.HasPrefix(pkg, prefix) &&
+		(len(pkg) == len(prefix) || pkg[len(prefix)] == '/')
+}
+
+// normalizeSourcePrefix strips the wildcard suffixes people write for "this
+// package and everything below it" ("pkg/*", "pkg/...", "pkg/").
+func normalizeSourcePrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	for _, suffix := range []string{"/*", "/...", "/"} {
+		prefix = strings.TrimSuffix(prefix, suffix)
+	}
+	return prefix
+}
+
+// callerPackage returns the import path of the package containing the
+// function skip frames above callerPackage's caller, with the same skip
+// convention as runtime.Caller. It returns "" if the frame is unavailable.
+func callerPackage(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return ""
+	}
+	if pkg, ok := callerPackages.Load(pcs[0]); ok {
+		return pkg.(string)
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	pkg := packageOf(frame.Function)
+	callerPackages.Store(pcs[0], pkg)
+	return pkg
+}
+
+// packageOf extracts the import path from a fully qualified function name
+// such as "github.com/acme/payments.(*Service).Charge.func1". Dots in the
+// last path element are escaped as %2e in symbol names (gopkg.in/yaml%2ev3).
+func packageOf(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	pkg := function
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		pkg = function[:slash+1+dot]
+	}
+	if strings.Contains(pkg, "%2e") {
+		pkg = strings.ReplaceAll(pkg, "%2e", ".")
+	}
+	return pkg
+}
+
+// SetSourceLevel sets the minimum level for records logged from code in
+// the package with import path prefix or any of its subpackages.
+//
+// The rule overrides the logger level in both directions: a package can be
+// lifted to Debug while the logger stays at Info, or a noisy dependency held
+// at Warn. When several rules match, the longest prefix wins. Setting a
+// prefix that already has a rule replaces it. Rules are shared by the
+// logger and every logger derived from it, and take effect immediately.
+//
+// The calling package is the one containing the function that calls the
+// logging method, after skipping WithCallerSkip frames. Records logged
+// through Logger.Write are not subject to source levels. While any rule is
+// set, every logging call resolves its caller (cached per call site).
+//
+// Parameters:
+//   - prefix: Import path; a trailing "/*" or "/..." is accepted and ignored
+//   - level: Minimum level for matching records
+//
+// Returns:
+//   - error: ErrCodeInvalidConfig for an empty prefix
+//
+// Example:
+//
+//	// Debug output from the payments packages only
+//	err := logger.SetSourceLevel("github.com/acme/payments/*", iris.Debug)
+func (l *Logger) SetSourceLevel(prefix string, level Level) error {
+	normalized := normalizeSourcePrefix(prefix)
+	if normalized == "" {
+		return NewLoggerErrorWithField(ErrCodeInvalidConfig,
+			"source level needs a package path prefix", "prefix", prefix)
+	}
+	s := l.sources
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rules []sourceRule
+	if cur := s.rules.Load(); cur != nil {
+		rules = make([]sourceRule, 0, len(*cur)+1)
+		for _, rule := range *cur {
+			if rule.prefix != normalized {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	rules = append(rules, sourceRule{prefix: normalized, level: level})
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+	s.rules.Store(&rules)
+	return nil
+}
+
+// RemoveSourceLevel removes the rule for prefix (normalized as in
+// SetSourceLevel).
+//
+// Returns:
+//   - bool: true if a rule was removed
+func (l *Logger) RemoveSourceLevel(prefix string) bool {
+	normalized := normalizeSourcePrefix(prefix)
+	s := l.sources
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur := s.rules.Load()
+	if cur == nil {
+		return false
+	}
+	rules := make([]sourceRule, 0, len(*cur))
+	for _, rule := range *cur {
+		if rule.prefix != normalized {
+			rules = append(rules, rule)
+		}
+	}
+	switch {
+	case len(rules) == len(*cur):
+		return false
+	case len(rules) == 0:
+		s.rules.Store(nil)
+	default:
+		s.rules.Store(&rules)
+	}
+	return true
+}
+
+// SourceLevels returns the current source level rules by package prefix.
+func (l *Logger) SourceLevels() map[string]Level {
+	rules := l.sources.rules.Load()
+	if rules == nil {
+		return nil
+	}
+	levels := make(map[string]Level, len(*rules))
+	for _, rule := range *rules {
+		levels[rule.prefix] = rule.level
+	}
+	return levels
+}
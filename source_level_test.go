@@ -0,0 +1,157 @@
+// source_level_test.go: Tests for per-package source levels
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPackageOf(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/acme/payments.(*Service).Charge", "github.com/acme/payments"},
+		{"github.com/acme/payments.Charge.func1", "github.com/acme/payments"},
+		{"github.com/acme/payments/v2.Charge[...]", "github.com/acme/payments/v2"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3"}, // Dots in the last element are escaped
+		{"main.main", "main"},
+		{"testing.tRunner", "testing"},
+	}
+	for _, tt := range tests {
+		if got := packageOf(tt.function); got != tt.want {
+			t.Errorf("packageOf(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestSourceLevels_Rules(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	for _, prefix := range []string{"github.com/acme/payments/*", "github.com/acme/payments/ledger/...", "github.com/acme"} {
+		if err := logger.SetSourceLevel(prefix, Debug); err != nil {
+			t.Fatalf("SetSourceLevel(%q) failed: %v", prefix, err)
+		}
+	}
+	if err := logger.Named("child").SetSourceLevel("github.com/acme", Warn); err != nil {
+		t.Fatalf("SetSourceLevel failed: %v", err)
+	}
+	if err := logger.SetSourceLevel(" /* ", Debug); !IsLoggerError(err, ErrCodeInvalidConfig) {
+		t.Errorf("SetSourceLevel with empty prefix = %v, want %s", err, ErrCodeInvalidConfig)
+	}
+
+	tests := []struct {
+		pkg  string
+		want Level
+	}{
+		{"github.com/acme/payments", Debug},
+		{"github.com/acme/payments/ledger", Debug},
+		{"github.com/acme/paymentsx", Warn}, // Prefix match stops at path boundaries
+		{"github.com/acme/orders", Warn},
+		{"github.com/other/payments", Info},
+	}
+	for _, tt := range tests {
+		if got := logger.sources.levelFor(tt.pkg, Info); got != tt.want {
+			t.Errorf("levelFor(%q) = %s, want %s", tt.pkg, got, tt.want)
+		}
+	}
+
+	if got := logger.SourceLevels(); len(got) != 3 || got["github.com/acme"] != Warn {
+		t.Errorf("SourceLevels() = %v, want 3 rules with github.com/acme at warn", got)
+	}
+	if !logger.RemoveSourceLevel("github.com/acme/payments/ledger") || logger.RemoveSourceLevel("github.com/acme/payments/ledger") {
+		t.Error("RemoveSourceLevel should report true once")
+	}
+	logger.RemoveSourceLevel("github.com/acme/payments")
+	logger.RemoveSourceLevel("github.com/acme")
+	if logger.sources.active() || logger.SourceLevels() != nil {
+		t.Errorf("rules left after removing all: %v", logger.SourceLevels())
+	}
+}
+
+type sourceLevelEvent struct {
+	ID string `iris:"id"`
+}
+
+// TestSourceLevels_CallingPackage checks that every logging method
+// attributes records to the package of its caller. WithCallerSkip(2) moves
+// the caller from this test to the testing package, which no other code in
+// the test binary logs from.
+func TestSourceLevels_CallingPackage(t *testing.T) {
+	ev, err := RegisterEvent[sourceLevelEvent]("source event", Debug)
+	if err != nil {
+		t.Fatalf("RegisterEvent failed: %v", err)
+	}
+	calls := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{"Debug", func(l *Logger) { l.Debug("record", Int("n", 1)) }},
+		{"Info", func(l *Logger) { l.Info("record") }},
+		{"Debugf", func(l *Logger) { l.Debugf("record %d", 1) }},
+		{"DebugCtx", func(l *Logger) { l.DebugCtx(context.Background(), "record") }},
+		{"EventType.Log", func(l *Logger) { ev.Log(l, sourceLevelEvent{ID: "a"}) }},
+		{"Logger.Event", func(l *Logger) { l.Event(sourceLevelEvent{ID: "a"}) }},
+		{"Logger.Event pointer", func(l *Logger) { l.Event(&sourceLevelEvent{ID: "a"}) }},
+	}
+
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			for _, tc := range []struct {
+				name    string
+				opts    []Option
+				min     Level
+				rule    Level
+				written bool
+			}{
+				{"caller package lifted", []Option{WithCallerSkip(2)}, Warn, Trace, true},
+				{"caller package muted", []Option{WithCallerSkip(2)}, Debug, Error, false},
+				{"other package unaffected", nil, Debug, Error, true},
+			} {
+				out := &testSyncer{}
+				logger, err := New(Config{Level: tc.min, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, tc.opts...)
+				if err != nil {
+					t.Fatalf("New failed: %v", err)
+				}
+				if err := logger.SetSourceLevel("testing", tc.rule); err != nil {
+					t.Fatalf("SetSourceLevel failed: %v", err)
+				}
+				call.log(logger)
+				_ = logger.Close()
+				if got := strings.Contains(out.String(), `"msg":`); got != tc.written {
+					t.Errorf("%s: written = %v, want %v: %s", tc.name, got, tc.written, out.String())
+				}
+			}
+		})
+	}
+}
+
+// TestLogger_Check uses WithCallerSkip(1) so that Check resolves the
+// testing package, as TestSourceLevels_CallingPackage does.
+func TestLogger_Check(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, WithCallerSkip(1))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	if logger.Check(Debug) || !logger.Check(Info) {
+		t.Error("Check should follow the logger level")
+	}
+	if err := logger.SetSourceLevel("testing", Debug); err != nil {
+		t.Fatalf("SetSourceLevel failed: %v", err)
+	}
+	if !logger.Check(Debug) {
+		t.Error("Check should follow the source level of the caller")
+	}
+}
@@ -11,7 +11,6 @@ package iris
 
 import (
 	"runtime"
-	"strconv"
 	"sync"
 
 	"github.com/agilira/iris/internal/bufferpool"
@@ -129,7 +128,7 @@ func (s *Stack) FormatStack() string {
 		buf.WriteByte('\t')
 		buf.WriteString(frame.File)
 		buf.WriteByte(':')
-		buf.WriteString(strconv.Itoa(frame.Line))
+		writeInt(buf, int64(frame.Line))
 	}
 
 	return buf.String()
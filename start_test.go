@@ -0,0 +1,139 @@
+// start_test.go: Tests for logger start-up and auto-start
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agilira/go-errors"
+)
+
+// captureErrors installs an error handler collecting reported errors for
+// the duration of the test.
+func captureErrors(t testing.TB) func() []*errors.Error {
+	t.Helper()
+	var mu sync.Mutex
+	var got []*errors.Error
+	SetErrorHandler(func(err *errors.Error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, err)
+	})
+	t.Cleanup(func() { SetErrorHandler(nil) })
+	return func() []*errors.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*errors.Error(nil), got...)
+	}
+}
+
+func TestAutoStart_Default(t *testing.T) {
+	reported := captureErrors(t)
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("without Start")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "without Start") {
+		t.Errorf("record not written by auto-started logger: %q", out.String())
+	}
+	logger.Start() // Harmless
+	if errs := reported(); len(errs) != 0 {
+		t.Errorf("unexpected diagnostics: %v", errs)
+	}
+}
+
+func TestAutoStart_OffReportsUnstartedLogging(t *testing.T) {
+	reported := captureErrors(t)
+	out := &testSyncer{}
+	logger, err := New(Config{
+		Level:     Info,
+		Output:    out,
+		Encoder:   NewJSONEncoder(),
+		Capacity:  1024,
+		Name:      "svc",
+		AutoStart: AutoStartOff,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("first")
+	logger.With(Str("k", "v")).Warn("second")
+	logger.Write(func(r *Record) { r.Level = Error; r.Msg = "third" })
+
+	errs := reported()
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one diagnostic, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Code != ErrLoggerNotStarted.Code || !strings.Contains(errs[0].Message, "Start()") {
+		t.Errorf("unexpected diagnostic: %v", errs[0])
+	}
+
+	logger.Start()
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	for _, msg := range []string{"first", "second", "third"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("buffered record %q not written after Start", msg)
+		}
+	}
+}
+
+func TestStart_SharedByDerivedLoggers(t *testing.T) {
+	reported := captureErrors(t)
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 1024, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	child := logger.Named("child").With(Str("k", "v"))
+	child.Start()
+	if logger.State() != StateStarted {
+		t.Fatal("starting a derived logger should start the shared consumer")
+	}
+	logger.Start() // Must not start a second consumer on the same ring
+
+	logger.Info("parent")
+	child.Info("child")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 2 {
+		t.Errorf("expected 2 records, got %d: %q", got, out.String())
+	}
+	if errs := reported(); len(errs) != 0 {
+		t.Errorf("unexpected diagnostics: %v", errs)
+	}
+}
+
+func TestAutoStart_InlineNeverReports(t *testing.T) {
+	reported := captureErrors(t)
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true, AutoStart: AutoStartOff})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	logger.Info("inline")
+	if !strings.Contains(out.String(), "inline") || len(reported()) != 0 {
+		t.Errorf("inline logger output %q, diagnostics %v", out.String(), reported())
+	}
+}
@@ -0,0 +1,132 @@
+// std_split.go: stdout/stderr split by level
+//
+// The twelve-factor convention is to log to the standard streams and let
+// the platform collect them; many platforms also colour, alert on or keep
+// stderr separately. The std-split preset writes Debug and Info records to
+// stdout and Warn and above to stderr from one logger, one ring and one
+// consumer. It is a pipeline like any other: Config.Output is stdout, and
+// an exclusive route sends Warn+ records to a stderr sink instead.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"strings"
+)
+
+// OutputStdSplit is the "output" of a JSON config (and the IRIS_OUTPUT
+// value) selecting the std-split preset: Debug and Info records to stdout,
+// Warn and above to stderr.
+const OutputStdSplit = "std-split"
+
+// StdSplitSink is the name of the pipeline sink the std-split preset
+// writes Warn and above to.
+const StdSplitSink = "std-split-stderr"
+
+// StdSplitPipeline returns the pipeline of the std-split preset: p (which
+// may be nil and is not modified) with a stderr sink in the given format
+// and an exclusive route of Warn and above to it. Used as Config.Pipeline
+// with Config.Output writing to stdout, it sends each record to exactly one
+// of the two streams.
+//
+// Parameters:
+//   - p: Pipeline to extend, or nil
+//   - format: Sink format, matching Config.Encoder (json, text, console
+//     or binary)
+//
+// Returns:
+//   - *PipelineConfig: A new pipeline with the preset's sink and route last
+//
+// Example:
+//
+//	logger, err := iris.New(iris.Config{
+//	    Output:   iris.WrapWriter(os.Stdout),
+//	    Encoder:  iris.NewJSONEncoder(),
+//	    Pipeline: iris.StdSplitPipeline(nil, "json"),
+//	})
+func StdSplitPipeline(p *PipelineConfig, format string) *PipelineConfig {
+	split := &PipelineConfig{}
+	if p != nil {
+		split.Processors = append(split.Processors, p.Processors...)
+		split.Sinks = append(split.Sinks, p.Sinks...)
+	}
+	split.Sinks = append(split.Sinks, SinkConfig{Name: StdSplitSink, Format: format, Output: "stderr"})
+	split.Processors = append(split.Processors, ProcessorConfig{
+		Type:      ProcessorRoute,
+		Level:     Warn.String(),
+		Sink:      StdSplitSink,
+		Exclusive: true,
+	})
+	return split
+}
+
+// NewStdSplit returns a started logger writing JSON records to stdout
+// below Warn and to stderr from Warn up. See NewStdoutJSON.
+//
+// Example:
+//
+//	logger := iris.NewStdSplit(iris.Info)
+//	defer logger.Close()
+func NewStdSplit(level Level, opts ...Option) *Logger {
+	return Must(New(Config{
+		Level:    level,
+		Output:   WrapWriter(os.Stdout),
+		Encoder:  NewJSONEncoder(),
+		Pipeline: StdSplitPipeline(nil, "json"),
+	}, opts...))
+}
+
+// hasStdSplit reports whether p contains the std-split preset.
+func hasStdSplit(p *PipelineConfig) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Sinks {
+		if s.Name == StdSplitSink {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutStdSplit returns p without the sink and route of the std-split
+// preset (p itself if it has none).
+func withoutStdSplit(p *PipelineConfig) *PipelineConfig {
+	if !hasStdSplit(p) {
+		return p
+	}
+	rest := &PipelineConfig{}
+	for _, s := range p.Sinks {
+		if s.Name != StdSplitSink {
+			rest.Sinks = append(rest.Sinks, s)
+		}
+	}
+	for _, pc := range p.Processors {
+		if !strings.EqualFold(pc.Type, ProcessorRoute) || pc.Sink != StdSplitSink {
+			rest.Processors = append(rest.Processors, pc)
+		}
+	}
+	return rest
+}
+
+// encoderFormat returns the sink format matching enc.
+func encoderFormat(enc Encoder) string {
+	switch enc.(type) {
+	case *TextEncoder:
+		return "text"
+	case *ConsoleEncoder:
+		return "console"
+	case *BinaryEncoder:
+		return "binary"
+	case *GELFEncoder:
+		return "gelf"
+	}
+	if name := registeredFormat(enc); name != "" {
+		return name
+	}
+	return "json"
+}
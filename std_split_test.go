@@ -0,0 +1,136 @@
+// std_split_test.go: Tests for the stdout/stderr split preset
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// redirectStd points os.Stdout and os.Stderr to files while fn runs and
+// returns their paths.
+func redirectStd(t *testing.T, fn func()) (stdoutPath, stderrPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	stdoutPath, stderrPath = filepath.Join(dir, "stdout"), filepath.Join(dir, "stderr")
+	outFile, err := os.Create(stdoutPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errFile, err := os.Create(stderrPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = outFile.Close()
+		_ = errFile.Close()
+	})
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outFile, errFile
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+	fn()
+	return stdoutPath, stderrPath
+}
+
+func TestNewStdSplit(t *testing.T) {
+	var logger *Logger
+	stdoutPath, stderrPath := redirectStd(t, func() { logger = NewStdSplit(Debug) })
+
+	logger.Debug("debug record")
+	logger.Info("info record")
+	logger.Warn("warn record")
+	logger.Error("error record")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	stdout, _ := os.ReadFile(stdoutPath)
+	stderr, _ := os.ReadFile(stderrPath)
+	tests := []struct {
+		msg    string
+		stream string
+		other  string
+	}{
+		{"debug record", string(stdout), string(stderr)},
+		{"info record", string(stdout), string(stderr)},
+		{"warn record", string(stderr), string(stdout)},
+		{"error record", string(stderr), string(stdout)},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.stream, tt.msg) || strings.Contains(tt.other, tt.msg) {
+			t.Errorf("%q not written to exactly its stream\nstdout: %s\nstderr: %s", tt.msg, stdout, stderr)
+		}
+	}
+}
+
+func TestStdSplitPipeline(t *testing.T) {
+	p := &PipelineConfig{Sinks: []SinkConfig{{Name: "audit", Output: "stderr"}}}
+	split := StdSplitPipeline(p, "text")
+	if len(p.Sinks) != 1 || len(p.Processors) != 0 {
+		t.Fatalf("StdSplitPipeline modified its argument: %+v", p)
+	}
+	if err := split.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	last := split.Processors[len(split.Processors)-1]
+	if !hasStdSplit(split) || split.Sinks[1].Format != "text" || last.Sink != StdSplitSink || !last.Exclusive {
+		t.Errorf("pipeline = %+v", split)
+	}
+	if rest := withoutStdSplit(split); hasStdSplit(rest) || len(rest.Sinks) != 1 || len(rest.Processors) != 0 {
+		t.Errorf("withoutStdSplit = %+v", rest)
+	}
+}
+
+func TestStdSplit_Config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iris.json")
+	data := `{"format": "text", "output": "std-split", "pipeline": {"processors": [{"type": "redact", "keys": ["token"]}]}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		split  bool
+		format string
+	}{
+		{"json file", nil, true, "text"},
+		{"env format", map[string]string{"IRIS_FORMAT": "json"}, true, "json"},
+		{"env output", map[string]string{"IRIS_OUTPUT": "stdout"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfigMultiSource(path)
+			if err != nil {
+				t.Fatalf("LoadConfigMultiSource failed: %v", err)
+			}
+			if hasStdSplit(cfg.Pipeline) != tt.split {
+				t.Fatalf("split = %v, want %v: %+v", !tt.split, tt.split, cfg.Pipeline)
+			}
+			if cfg.Pipeline.Processors[0].Type != ProcessorRedact {
+				t.Errorf("pipeline of the file lost: %+v", cfg.Pipeline)
+			}
+			if sinks := cfg.Pipeline.Sinks; tt.split && sinks[len(sinks)-1].Format != tt.format {
+				t.Errorf("sink format = %q, want %q", sinks[len(sinks)-1].Format, tt.format)
+			}
+		})
+	}
+
+	t.Setenv("IRIS_OUTPUT", OutputStdSplit)
+	cfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if !hasStdSplit(cfg.Pipeline) || cfg.Pipeline.Sinks[0].Format != "json" {
+		t.Errorf("IRIS_OUTPUT=std-split pipeline = %+v", cfg.Pipeline)
+	}
+}
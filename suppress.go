@@ -0,0 +1,428 @@
+// suppress.go: Suppression of identical records across replicas
+//
+// When N replicas of a service hit the same failing dependency, each of
+// them logs the same warning and the downstream system (alerting, a SIEM,
+// a paid ingestion pipeline) receives it N times. A Suppressor drops the
+// records whose fingerprint (level, logger, message and fields) it has
+// already seen within a window, locally or from a peer: each replica
+// publishes the fingerprints it logs first, through a SuppressionPeer
+// (gossip, a Redis channel), and feeds the ones it receives from the others
+// to Observe.
+//
+// Suppression is best effort. Fingerprints are kept in a Bloom filter, so a
+// distinct record may be taken for a duplicate (about 1% of the time at the
+// configured capacity), and the exchange is asynchronous, so replicas
+// logging the same record within the publication delay all write it. The
+// record is never delayed waiting for a peer.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agilira/go-timecache"
+)
+
+// Defaults of SuppressionConfig.
+const (
+	DefaultSuppressionWindow   = time.Minute
+	DefaultSuppressionCapacity = 10000
+	DefaultSuppressionInterval = 100 * time.Millisecond
+)
+
+const (
+	// bloomBitsPerKey and bloomHashes give a false positive rate of about
+	// 1% at capacity
+	bloomBitsPerKey = 9.6
+	bloomHashes     = 7
+
+	// suppressBatch bounds the fingerprints of one Publish call, and
+	// suppressQueue the fingerprints waiting for it
+	suppressBatch = 512
+	suppressQueue = 4096
+)
+
+// SuppressionPeer shares fingerprints between the replicas of a service.
+// Implementations send them to the other replicas, which pass them to their
+// own Suppressor's Observe.
+type SuppressionPeer interface {
+	// Publish sends fingerprints first seen by this replica. It is called
+	// from a background goroutine, one batch at a time, and should bound
+	// its own duration: batches queued meanwhile are dropped when the
+	// queue is full.
+	Publish(ctx context.Context, fingerprints []uint64) error
+}
+
+// SuppressionConfig configures a Suppressor.
+type SuppressionConfig struct {
+	// Window is the minimum time a record suppresses its duplicates; a
+	// fingerprint is forgotten between one and two windows after it was
+	// seen (default DefaultSuppressionWindow).
+	Window time.Duration
+
+	// MinLevel is the lowest level suppressed; records below it always
+	// pass (the zero value is Info).
+	MinLevel Level
+
+	// IgnoreKeys are fields left out of the fingerprint: request ids,
+	// timestamps, the host of the replica.
+	IgnoreKeys []string
+
+	// Capacity is the number of distinct records per window the filter is
+	// sized for (default DefaultSuppressionCapacity). Beyond it, distinct
+	// records are increasingly taken for duplicates.
+	Capacity int
+
+	// Peer shares the fingerprints with the other replicas; nil
+	// suppresses the duplicates of this process only.
+	Peer SuppressionPeer
+
+	// PublishInterval is the maximum time a fingerprint waits before it
+	// is published (default DefaultSuppressionInterval).
+	PublishInterval time.Duration
+}
+
+// Suppressor drops records whose fingerprint was seen within a window, by
+// this process or by a peer. It is safe for concurrent use and may be
+// shared by several loggers: it is closed when the last of them is.
+type Suppressor struct {
+	window  time.Duration
+	min     Level
+	ignore  []string
+	words   int
+	peer    SuppressionPeer
+	now     func() time.Time
+	gen     atomic.Pointer[bloomGeneration]
+	pending chan uint64
+
+	suppressed atomic.Int64
+	published  atomic.Int64
+	failed     atomic.Bool
+	users      atomic.Int32 // Loggers created with WithSuppression(s) and not closed
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// bloomGeneration is the filter of the current window and the one of the
+// previous window, which still suppresses.
+type bloomGeneration struct {
+	cur, prev []atomic.Uint64
+	until     time.Time
+}
+
+// NewSuppressor creates a Suppressor. With a Peer it starts a goroutine
+// publishing fingerprints, stopped by Close.
+//
+// Example:
+//
+//	s := iris.NewSuppressor(iris.SuppressionConfig{
+//	    Window:     5 * time.Minute,
+//	    MinLevel:   iris.Warn,
+//	    IgnoreKeys: []string{"request_id"},
+//	    Peer:       redisPeer, // Publish sends to a channel; its subscriber calls s.Observe
+//	})
+//	logger, err := iris.New(cfg, iris.WithSuppression(s))
+func NewSuppressor(cfg SuppressionConfig) *Suppressor {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultSuppressionWindow
+	}
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = DefaultSuppressionCapacity
+	}
+	if cfg.PublishInterval <= 0 {
+		cfg.PublishInterval = DefaultSuppressionInterval
+	}
+	s := &Suppressor{
+		window: cfg.Window,
+		min:    cfg.MinLevel,
+		ignore: append([]string(nil), cfg.IgnoreKeys...),
+		words:  int(math.Ceil(float64(cfg.Capacity)*bloomBitsPerKey/64)) + 1,
+		peer:   cfg.Peer,
+		now:    timecache.CachedTime,
+		done:   make(chan struct{}),
+	}
+	if s.peer != nil {
+		s.pending = make(chan uint64, suppressQueue)
+		s.stopped = make(chan struct{})
+		go s.publishLoop(cfg.PublishInterval)
+	}
+	return s
+}
+
+// WithSuppression drops the records s has already seen within its window,
+// after the stages added before it: add enrichment that differs between
+// replicas (the host) after it, or list those fields in IgnoreKeys.
+// Suppressed records are counted as DropFiltered. s may be passed to
+// several loggers; the Close of the last one closes s, publishing the
+// fingerprints still queued. Like the other consumer options it must be
+// passed to New.
+//
+// Parameters:
+//   - s: Suppressor to apply (nil is ignored)
+//
+// Returns:
+//   - Option: Configuration function to enable the suppression
+func WithSuppression(s *Suppressor) Option {
+	return func(o *loggerOptions) {
+		if s == nil {
+			return
+		}
+		o.stages = append(o.stages[:len(o.stages):len(o.stages)], s.process)
+		o.owned = append(o.owned[:len(o.owned):len(o.owned)], &suppressorUse{s: s})
+	}
+}
+
+// suppressorUse is the reference a logger holds on a shared Suppressor.
+type suppressorUse struct {
+	s    *Suppressor
+	once sync.Once
+}
+
+// acquireSuppressors takes the references of l on the Suppressors it was
+// created with. New applies the options more than once while it resolves
+// the configuration, so references are counted here and not by
+// WithSuppression.
+func (l *Logger) acquireSuppressors() {
+	for _, c := range l.opts.owned {
+		if u, ok := c.(*suppressorUse); ok {
+			u.s.users.Add(1)
+		}
+	}
+}
+
+// Close releases the reference, closing the Suppressor with the last one.
+func (u *suppressorUse) Close() error {
+	var err error
+	u.once.Do(func() {
+		if u.s.users.Add(-1) == 0 {
+			err = u.s.Close()
+		}
+	})
+	return err
+}
+
+// process is the PipelineStage of s.
+func (s *Suppressor) process(rec *Record) bool {
+	if !rec.Level.Enabled(s.min) {
+		return true
+	}
+	fp := s.Fingerprint(rec)
+	g := s.generation(s.now())
+	if bloomHas(g.cur, fp) || bloomHas(g.prev, fp) {
+		s.suppressed.Add(1)
+		return false
+	}
+	bloomAdd(g.cur, fp)
+	if s.pending != nil {
+		select {
+		case s.pending <- fp:
+		default: // Best effort: the peers see the record late or not at all
+		}
+	}
+	return true
+}
+
+// Observe records fingerprints seen by a peer, so that this process
+// suppresses their records too.
+func (s *Suppressor) Observe(fingerprints ...uint64) {
+	g := s.generation(s.now())
+	for _, fp := range fingerprints {
+		bloomAdd(g.cur, fp)
+	}
+}
+
+// Suppressed returns the number of records dropped as duplicates.
+func (s *Suppressor) Suppressed() int64 {
+	return s.suppressed.Load()
+}
+
+// Published returns the number of fingerprints sent to the peer.
+func (s *Suppressor) Published() int64 {
+	return s.published.Load()
+}
+
+// Close stops the publishing goroutine after publishing the fingerprints
+// still queued. It is idempotent; records processed after Close are no
+// longer published.
+func (s *Suppressor) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		if s.stopped != nil {
+			<-s.stopped
+		}
+	})
+	return nil
+}
+
+// generation returns the filters of the window containing now, starting
+// new ones when the current window has passed.
+func (s *Suppressor) generation(now time.Time) *bloomGeneration {
+	for {
+		g := s.gen.Load()
+		if g != nil && now.Before(g.until) {
+			return g
+		}
+		next := &bloomGeneration{cur: make([]atomic.Uint64, s.words), until: now.Add(s.window)}
+		// The previous window only suppresses if it just ended
+		if g != nil && now.Before(g.until.Add(s.window)) {
+			next.prev = g.cur
+			next.until = g.until.Add(s.window)
+		}
+		if s.gen.CompareAndSwap(g, next) {
+			return next
+		}
+	}
+}
+
+// publishLoop sends the queued fingerprints to the peer in batches.
+func (s *Suppressor) publishLoop(interval time.Duration) {
+	defer close(s.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	batch := make([]uint64, 0, suppressBatch)
+	for {
+		select {
+		case fp := <-s.pending:
+			if batch = append(batch, fp); len(batch) == suppressBatch {
+				batch = s.publish(batch)
+			}
+		case <-ticker.C:
+			batch = s.publish(batch)
+		case <-s.done:
+			for {
+				select {
+				case fp := <-s.pending:
+					if batch = append(batch, fp); len(batch) == suppressBatch {
+						batch = s.publish(batch)
+					}
+				default:
+					s.publish(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// publish sends batch to the peer and returns it emptied. A failure is
+// reported once, until a batch is published again.
+func (s *Suppressor) publish(batch []uint64) []uint64 {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := s.peer.Publish(context.Background(), batch); err != nil {
+		if !s.failed.Swap(true) {
+			handleError(NewLoggerError(ErrCodeWriteFailed, fmt.Sprintf("suppression peer: publish failed: %v", err)))
+		}
+	} else {
+		s.failed.Store(false)
+		s.published.Add(int64(len(batch)))
+	}
+	return batch[:0]
+}
+
+// FNV-1a, 64 bits
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Fingerprint returns the hash identifying the duplicates of rec: its
+// level, logger name, message and fields, in order, except the ignored
+// ones. Secret values are left out, so fingerprints can be shared. The
+// hash is the same in every process, whatever the build.
+func (s *Suppressor) Fingerprint(rec *Record) uint64 {
+	h := uint64(fnvOffset64)
+	h = fnvInt(h, uint64(rec.Level)) // #nosec G115 -- sign is irrelevant to the hash
+	h = fnvString(h, rec.Logger)
+	h = fnvString(h, rec.Msg)
+fields:
+	for i := int32(0); i < rec.n; i++ {
+		f := &rec.fields[i]
+		for _, k := range s.ignore {
+			if f.K == k {
+				continue fields
+			}
+		}
+		h = fnvString(h, f.K)
+		h = fnvInt(h, uint64(f.T))
+		switch f.T {
+		case kindSecret:
+		case kindString:
+			h = fnvString(h, f.Str)
+		case kindInt64, kindBool, kindDur, kindTime:
+			h = fnvInt(h, uint64(f.I64)) // #nosec G115 -- sign is irrelevant to the hash
+		case kindUint64:
+			h = fnvInt(h, f.U64)
+		case kindFloat64:
+			h = fnvInt(h, math.Float64bits(f.F64))
+		case kindBytes:
+			h = fnvString(h, string(f.B))
+		case kindError, kindStringer, kindObject:
+			var b strings.Builder
+			writeFieldText(&b, *f)
+			h = fnvString(h, b.String())
+		}
+	}
+	return h
+}
+
+// fnvString hashes v followed by a separator, so that ("ab", "c") and
+// ("a", "bc") differ.
+func fnvString(h uint64, v string) uint64 {
+	for i := 0; i < len(v); i++ {
+		h ^= uint64(v[i])
+		h *= fnvPrime64
+	}
+	h ^= 0xff
+	h *= fnvPrime64
+	return h
+}
+
+// fnvInt hashes the 8 bytes of v.
+func fnvInt(h, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= v & 0xff
+		h *= fnvPrime64
+		v >>= 8
+	}
+	return h
+}
+
+// bloomHas reports whether the bits of fp are all set in bits.
+func bloomHas(bits []atomic.Uint64, fp uint64) bool {
+	if bits == nil {
+		return false
+	}
+	m := uint64(len(bits)) * 64
+	h1, h2 := fp, fp>>32|1
+	for i := uint64(0); i < bloomHashes; i++ {
+		bit := (h1 + i*h2) % m
+		if bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomAdd sets the bits of fp in bits.
+func bloomAdd(bits []atomic.Uint64, fp uint64) {
+	m := uint64(len(bits)) * 64
+	h1, h2 := fp, fp>>32|1
+	for i := uint64(0); i < bloomHashes; i++ {
+		bit := (h1 + i*h2) % m
+		bits[bit/64].Or(1 << (bit % 64))
+	}
+}
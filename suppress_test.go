@@ -0,0 +1,185 @@
+// suppress_test.go: Tests for suppression across replicas
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loopbackPeer delivers the published fingerprints to other suppressors.
+type loopbackPeer struct {
+	mu    sync.Mutex
+	to    []*Suppressor
+	err   error
+	calls int
+}
+
+func (p *loopbackPeer) Publish(_ context.Context, fps []uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return p.err
+	}
+	for _, s := range p.to {
+		s.Observe(fps...)
+	}
+	return nil
+}
+
+func TestSuppressor_Fingerprint(t *testing.T) {
+	s := NewSuppressor(SuppressionConfig{IgnoreKeys: []string{"request_id"}})
+	record := func(level Level, msg string, fields ...Field) *Record {
+		rec := NewRecord(level, msg)
+		for _, f := range fields {
+			rec.AddField(f)
+		}
+		return rec
+	}
+	base := s.Fingerprint(record(Warn, "db slow", Str("table", "orders"), Int("ms", 900), Str("request_id", "a")))
+	tests := []struct {
+		name string
+		rec  *Record
+		same bool
+	}{
+		{"ignored key differs", record(Warn, "db slow", Str("table", "orders"), Int("ms", 900), Str("request_id", "b")), true},
+		{"field value", record(Warn, "db slow", Str("table", "orders"), Int("ms", 901)), false},
+		{"field kind", record(Warn, "db slow", Str("table", "orders"), Str("ms", "900")), false},
+		{"level", record(Error, "db slow", Str("table", "orders"), Int("ms", 900)), false},
+		{"message", record(Warn, "db slower", Str("table", "orders"), Int("ms", 900)), false},
+		{"boundaries", record(Warn, "db slow", Str("table", "order"), Int("sms", 900)), false},
+	}
+	for _, tt := range tests {
+		if got := s.Fingerprint(tt.rec) == base; got != tt.same {
+			t.Errorf("%s: same fingerprint = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+	if s.Fingerprint(record(Info, "login", Secret("token", "a"))) != s.Fingerprint(record(Info, "login", Secret("token", "b"))) {
+		t.Error("secret values change the fingerprint")
+	}
+}
+
+func TestWithSuppression(t *testing.T) {
+	s := NewSuppressor(SuppressionConfig{Window: time.Minute, MinLevel: Warn, IgnoreKeys: []string{"request_id"}})
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	s.now = clock.Now
+	out := &testSyncer{}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Inline: true}, WithSuppression(s))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Warn("upstream down", Str("service", "billing"), Str("request_id", "1"))
+	logger.Warn("upstream down", Str("service", "billing"), Str("request_id", "2"))
+	logger.Warn("upstream down", Str("service", "ledger"))
+	logger.Info("below min level")
+	logger.Info("below min level")
+	clock.Set(clock.Now().Add(90 * time.Second)) // Still in the previous window
+	logger.Warn("upstream down", Str("service", "billing"))
+	clock.Set(clock.Now().Add(2 * time.Minute))
+	logger.Warn("upstream down", Str("service", "billing"), Str("request_id", "3"))
+
+	if got := strings.Count(out.String(), "\n"); got != 5 {
+		t.Errorf("wrote %d records, want 5:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), `"request_id":"3"`) {
+		t.Errorf("record not written after two windows:\n%s", out.String())
+	}
+	if s.Suppressed() != 2 {
+		t.Errorf("Suppressed() = %d, want 2", s.Suppressed())
+	}
+	if got := logger.Stats()["dropped_filtered"]; got != 2 {
+		t.Errorf("dropped_filtered = %d, want 2", got)
+	}
+}
+
+func TestSuppressor_Peers(t *testing.T) {
+	peer := &loopbackPeer{}
+	a := NewSuppressor(SuppressionConfig{Peer: peer, PublishInterval: time.Hour})
+	b := NewSuppressor(SuppressionConfig{})
+	peer.to = []*Suppressor{b}
+	outA, outB := &testSyncer{}, &testSyncer{}
+	replicaA, err := New(Config{Level: Info, Output: outA, Encoder: NewJSONEncoder(), Inline: true}, WithSuppression(a))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	replicaB, err := New(Config{Level: Info, Output: outB, Encoder: NewJSONEncoder(), Inline: true}, WithSuppression(b))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = replicaB.Close() }()
+
+	replicaA.Warn("cache miss storm", Int("shard", 3))
+	replicaA.Warn("cache miss storm", Int("shard", 3))
+	// Closing A publishes its queued fingerprints
+	if err := replicaA.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	replicaB.Warn("cache miss storm", Int("shard", 3))
+	replicaB.Warn("cache miss storm", Int("shard", 4))
+
+	if strings.Count(outA.String(), "\n") != 1 || a.Published() != 1 || peer.calls != 1 {
+		t.Errorf("replica A wrote %q, published %d in %d calls", outA.String(), a.Published(), peer.calls)
+	}
+	if strings.Contains(outB.String(), `"shard":3`) || !strings.Contains(outB.String(), `"shard":4`) {
+		t.Errorf("replica B wrote %q, want only shard 4", outB.String())
+	}
+}
+
+func TestSuppressor_SharedByLoggers(t *testing.T) {
+	peer := &loopbackPeer{}
+	s := NewSuppressor(SuppressionConfig{Peer: peer, PublishInterval: time.Hour})
+	newLogger := func() *Logger {
+		logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Inline: true}, WithSuppression(s))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		return logger
+	}
+	first, second := newLogger(), newLogger()
+
+	first.Warn("disk almost full", Str("volume", "data"))
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// The other logger keeps s open: its fingerprints are still published
+	second.Warn("disk almost full", Str("volume", "logs"))
+	if got := s.Published(); got != 0 {
+		t.Errorf("published %d fingerprints before the last logger closed", got)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := s.Published(); got != 2 {
+		t.Errorf("published %d fingerprints, want 2", got)
+	}
+}
+
+func TestSuppressor_PublishFailure(t *testing.T) {
+	reported := captureErrors(t)
+	peer := &loopbackPeer{err: errors.New("redis unavailable")}
+	s := NewSuppressor(SuppressionConfig{Peer: peer, PublishInterval: time.Millisecond})
+	for i := 0; i < 3; i++ {
+		s.process(NewRecord(Warn, "distinct "+string(rune('a'+i))))
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = s.Close()
+	_ = s.Close()
+
+	if got := reported(); len(got) != 1 || got[0].ErrorCode() != ErrCodeWriteFailed || !strings.Contains(got[0].Error(), "redis unavailable") {
+		t.Errorf("reported %v, want one publish failure", got)
+	}
+	if peer.calls < 2 || s.Published() != 0 {
+		t.Errorf("peer called %d times, published %d", peer.calls, s.Published())
+	}
+}
@@ -0,0 +1,271 @@
+// templates.go: Message template extraction for Iris logging library
+//
+// Most log volume in production comes from a handful of call sites whose
+// messages differ only in embedded values ("user 42 logged in", "user 97
+// logged in"). The template analyzer groups messages into templates
+// ("user <*> logged in") with a Drain-style clustering and counts them, so
+// that the chattiest call sites can be found from a running process
+// without shipping and post-processing its logs.
+//
+// Algorithm (after He et al., "Drain: An Online Log Parsing Approach with
+// Fixed Depth Tree", ICWS 2017):
+//   - Messages are split on whitespace; tokens containing digits are
+//     replaced by the wildcard "<*>" up front
+//   - Candidates are looked up by token count and the first Depth tokens
+//   - The message joins the most similar candidate if the fraction of equal
+//     tokens (wildcards match anything) reaches Similarity; the positions
+//     that differ become wildcards. Otherwise a new template is created.
+//
+// The analyzer runs in the consumer thread on a sample of the records, so
+// the logging fast path is unaffected.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// templateWildcard replaces variable tokens in templates.
+const templateWildcard = "<*>"
+
+// TemplateConfig configures message template analysis.
+type TemplateConfig struct {
+	// SampleEvery analyzes one record in N (default 1: every record)
+	SampleEvery int
+
+	// MaxTemplates bounds memory; messages that would create a template
+	// beyond the limit are only counted in "templates_overflow" (default 1000)
+	MaxTemplates int
+
+	// Similarity is the fraction of matching tokens required to join a
+	// template, in (0, 1] (default 0.5)
+	Similarity float64
+
+	// Depth is the number of leading tokens used to select candidate
+	// templates (default 2)
+	Depth int
+}
+
+// Template is a message pattern with its estimated number of records.
+type Template struct {
+	Pattern string `json:"pattern"` // Message with variable tokens replaced by "<*>"
+	Count   int64  `json:"count"`   // Records matched, scaled by the sampling rate
+	Level   Level  `json:"level"`   // Level of the first record seen
+	Logger  string `json:"logger,omitempty"`
+	Example string `json:"example"` // First message seen
+}
+
+// templateCluster is one template being learned.
+type templateCluster struct {
+	tokens  []string
+	count   int64
+	level   Level
+	logger  string
+	example string
+}
+
+// templateAnalyzer clusters sampled messages into templates.
+type templateAnalyzer struct {
+	cfg  TemplateConfig
+	tick int // Consumer-only sampling counter
+
+	mu       sync.Mutex
+	groups   map[string][]*templateCluster // token count + leading tokens -> candidates
+	clusters int
+
+	sampled  atomic.Int64
+	overflow atomic.Int64
+}
+
+// WithTemplateAnalysis enables message template extraction.
+//
+// The consumer clusters a sample of the logged messages into templates;
+// Logger.Templates returns the most frequent ones, Logger.TemplatesHandler
+// serves them as JSON for a debug endpoint, and Stats reports "templates",
+// "templates_sampled" and "templates_overflow". The analyzer is shared by
+// the logger and all loggers derived from it.
+//
+// Example:
+//
+//	logger, _ := iris.New(cfg, iris.WithTemplateAnalysis(iris.TemplateConfig{SampleEvery: 16}))
+//	http.Handle("/debug/log-templates", logger.TemplatesHandler())
+func WithTemplateAnalysis(cfg TemplateConfig) Option {
+	if cfg.SampleEvery <= 0 {
+		cfg.SampleEvery = 1
+	}
+	if cfg.MaxTemplates <= 0 {
+		cfg.MaxTemplates = 1000
+	}
+	if cfg.Similarity <= 0 || cfg.Similarity > 1 {
+		cfg.Similarity = 0.5
+	}
+	if cfg.Depth <= 0 {
+		cfg.Depth = 2
+	}
+	return func(o *loggerOptions) { o.templates = &cfg }
+}
+
+// newTemplateAnalyzer creates an analyzer for a normalized config.
+func newTemplateAnalyzer(cfg TemplateConfig) *templateAnalyzer {
+	return &templateAnalyzer{cfg: cfg, groups: make(map[string][]*templateCluster)}
+}
+
+// observe feeds a record to the analyzer. Called by the consumer only.
+func (a *templateAnalyzer) observe(rec *Record) {
+	a.tick++
+	if a.tick < a.cfg.SampleEvery {
+		return
+	}
+	a.tick = 0
+	a.sampled.Add(1)
+	a.add(rec.Msg, rec.Level, rec.Logger)
+}
+
+// add clusters one message.
+func (a *templateAnalyzer) add(msg string, level Level, logger string) {
+	tokens := strings.Fields(msg)
+	for i, t := range tokens {
+		if strings.ContainsAny(t, "0123456789") {
+			tokens[i] = templateWildcard
+		}
+	}
+	key := templateGroupKey(tokens, a.cfg.Depth)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var best *templateCluster
+	bestSim := -1.0
+	for _, c := range a.groups[key] {
+		if sim := templateSimilarity(c.tokens, tokens); sim > bestSim {
+			best, bestSim = c, sim
+		}
+	}
+	if best != nil && bestSim >= a.cfg.Similarity {
+		for i, t := range tokens {
+			if best.tokens[i] != t {
+				best.tokens[i] = templateWildcard
+			}
+		}
+		best.count++
+		return
+	}
+	if a.clusters >= a.cfg.MaxTemplates {
+		a.overflow.Add(1)
+		return
+	}
+	a.groups[key] = append(a.groups[key], &templateCluster{
+		tokens: tokens, count: 1, level: level, logger: logger, example: msg,
+	})
+	a.clusters++
+}
+
+// templateGroupKey returns the candidate group of a tokenized message.
+func templateGroupKey(tokens []string, depth int) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(tokens)))
+	for i := 0; i < depth && i < len(tokens); i++ {
+		b.WriteByte(' ')
+		b.WriteString(tokens[i])
+	}
+	return b.String()
+}
+
+// templateSimilarity returns the fraction of positions where template and
+// tokens agree; wildcards in the template agree with anything. Both slices
+// have the same length.
+func templateSimilarity(template, tokens []string) float64 {
+	if len(tokens) == 0 {
+		return 1
+	}
+	same := 0
+	for i, t := range template {
+		if t == templateWildcard || t == tokens[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(tokens))
+}
+
+// top returns the n most frequent templates (all when n <= 0).
+func (a *templateAnalyzer) top(n int) []Template {
+	a.mu.Lock()
+	out := make([]Template, 0, a.clusters)
+	scale := int64(a.cfg.SampleEvery)
+	for _, group := range a.groups {
+		for _, c := range group {
+			out = append(out, Template{
+				Pattern: strings.Join(c.tokens, " "),
+				Count:   c.count * scale,
+				Level:   c.level,
+				Logger:  c.logger,
+				Example: c.example,
+			})
+		}
+	}
+	a.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Pattern < out[j].Pattern
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// addTo adds the analyzer counters to a Stats map.
+func (a *templateAnalyzer) addTo(stats map[string]int64) {
+	a.mu.Lock()
+	stats["templates"] = int64(a.clusters)
+	a.mu.Unlock()
+	stats["templates_sampled"] = a.sampled.Load()
+	stats["templates_overflow"] = a.overflow.Load()
+}
+
+// Templates returns the n most frequent message templates, most frequent
+// first (all templates when n <= 0). It returns nil unless the logger was
+// created with WithTemplateAnalysis.
+func (l *Logger) Templates(n int) []Template {
+	if l.templates == nil {
+		return nil
+	}
+	return l.templates.top(n)
+}
+
+// TemplatesHandler returns an HTTP handler serving the most frequent
+// message templates as a JSON array. The "n" query parameter limits the
+// number of templates (default 20, 0 for all). Without WithTemplateAnalysis
+// the handler responds 404.
+func (l *Logger) TemplatesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.templates == nil {
+			http.Error(w, "template analysis is not enabled", http.StatusNotFound)
+			return
+		}
+		n := 20
+		if s := r.URL.Query().Get("n"); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 0 {
+				http.Error(w, "invalid n", http.StatusBadRequest)
+				return
+			}
+			n = v
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(l.templates.top(n))
+	})
+}
@@ -0,0 +1,126 @@
+// templates_test.go: Tests for message template extraction
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTemplateAnalyzer_Clusters(t *testing.T) {
+	a := newTemplateAnalyzer(TemplateConfig{SampleEvery: 1, MaxTemplates: 100, Similarity: 0.5, Depth: 2})
+	for i := 0; i < 30; i++ {
+		a.add(fmt.Sprintf("user %d logged in from web", i), Info, "auth")
+	}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		a.add("session opened for "+name, Info, "")
+	}
+	a.add("cache warmed", Debug, "")
+	a.add("cache warmed", Debug, "")
+
+	top := a.top(0)
+	want := []struct {
+		pattern string
+		count   int64
+	}{
+		{"user <*> logged in from web", 30},
+		{"session opened for <*>", 3},
+		{"cache warmed", 2},
+	}
+	if len(top) != len(want) {
+		t.Fatalf("got %d templates, want %d: %+v", len(top), len(want), top)
+	}
+	for i, w := range want {
+		if top[i].Pattern != w.pattern || top[i].Count != w.count {
+			t.Errorf("template %d = %q x%d, want %q x%d", i, top[i].Pattern, top[i].Count, w.pattern, w.count)
+		}
+	}
+	if top[0].Logger != "auth" || top[0].Example != "user 0 logged in from web" {
+		t.Errorf("unexpected first template metadata: %+v", top[0])
+	}
+	if got := a.top(1); len(got) != 1 {
+		t.Errorf("top(1) returned %d templates", len(got))
+	}
+}
+
+func TestTemplateAnalyzer_DissimilarAndOverflow(t *testing.T) {
+	a := newTemplateAnalyzer(TemplateConfig{SampleEvery: 1, MaxTemplates: 2, Similarity: 0.5, Depth: 1})
+	a.add("disk almost full on volume data", Warn, "")
+	a.add("disk quota exceeded by tenant acme", Warn, "") // Same group, too different
+	a.add("queue drained", Info, "")                      // Over the limit
+
+	stats := map[string]int64{}
+	a.addTo(stats)
+	if stats["templates"] != 2 || stats["templates_overflow"] != 1 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+}
+
+func TestWithTemplateAnalysis(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: WrapWriter(discardWriter{}), Encoder: NewJSONEncoder(), Capacity: 1024},
+		WithTemplateAnalysis(TemplateConfig{SampleEvery: 2}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Start()
+
+	child := logger.With(Str("component", "worker")).Named("worker")
+	for i := 0; i < 100; i++ {
+		child.Info(fmt.Sprintf("job %d finished", i))
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	stats := logger.Stats()
+	if stats["templates_sampled"] != 50 || stats["templates"] != 1 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+	top := child.Templates(10)
+	if len(top) != 1 || top[0].Pattern != "job <*> finished" || top[0].Count != 100 || top[0].Logger != "worker" {
+		t.Errorf("unexpected templates: %+v", top)
+	}
+
+	rr := httptest.NewRecorder()
+	logger.TemplatesHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/templates?n=5", nil))
+	var served []map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &served); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rr.Body.String(), err)
+	}
+	if len(served) != 1 || served[0]["pattern"] != "job <*> finished" || served[0]["level"] != "info" {
+		t.Errorf("unexpected response: %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	logger.TemplatesHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/templates?n=x", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid n: status %d", rr.Code)
+	}
+}
+
+func TestTemplates_Disabled(t *testing.T) {
+	logger, err := New(Config{Level: Info, Output: WrapWriter(discardWriter{}), Encoder: NewJSONEncoder(), Capacity: 1024})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer safeCloseWithOptionsLogger(t, logger)
+
+	if logger.Templates(5) != nil {
+		t.Error("Templates should be nil when analysis is disabled")
+	}
+	if _, ok := logger.Stats()["templates"]; ok {
+		t.Error("Stats should not report templates when analysis is disabled")
+	}
+	rr := httptest.NewRecorder()
+	logger.TemplatesHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rr.Code)
+	}
+}
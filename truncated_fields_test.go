@@ -0,0 +1,142 @@
+// truncated_fields_test.go: Tests for the MarkTruncated encoder option
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// manyFields returns n distinct int fields.
+func manyFields(n int) []Field {
+	fields := make([]Field, n)
+	for i := range fields {
+		fields[i] = Int("f"+strconv.Itoa(i), i)
+	}
+	return fields
+}
+
+func TestTruncatedFields_Logger(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   int
+		fields int
+		caller bool
+		mark   bool
+		want   int // Expected marker value, 0 = no marker
+	}{
+		{"Fits", 2, 30, false, true, 0},
+		{"Provided", 0, 40, false, true, 8},
+		{"BaseAndProvided", 20, 20, false, true, 8},
+		{"CallerLeftOut", 32, 1, true, true, 2},
+		{"Unmarked", 0, 40, false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &testSyncer{}
+			enc := NewJSONEncoder()
+			enc.MarkTruncated = tt.mark
+			var opts []Option
+			if tt.caller {
+				opts = append(opts, WithCaller())
+			}
+			logger, err := New(Config{Level: Info, Output: out, Encoder: enc, Capacity: 64, Inline: true}, opts...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer func() { _ = logger.Close() }()
+
+			logger.With(manyFields(tt.base)...).Info("wide", manyFields(tt.fields)...)
+			_ = logger.Sync()
+
+			line := findLine(out.String(), "wide")
+			var got map[string]any
+			if err := json.Unmarshal([]byte(line), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", line, err)
+			}
+			n, ok := got[TruncatedFieldsKey]
+			if tt.want == 0 {
+				if ok {
+					t.Errorf("unexpected marker %v in %s", n, line)
+				}
+				return
+			}
+			if n != float64(tt.want) {
+				t.Errorf("%s = %v, want %d in %s", TruncatedFieldsKey, n, tt.want, line)
+			}
+		})
+	}
+}
+
+func TestTruncatedFields_AddField(t *testing.T) {
+	rec := NewRecord(Info, "wide")
+	for _, f := range manyFields(35) {
+		rec.AddField(f)
+	}
+	if rec.FieldCount() != 32 || rec.TruncatedFields() != 3 {
+		t.Fatalf("FieldCount/TruncatedFields = %d/%d, want 32/3", rec.FieldCount(), rec.TruncatedFields())
+	}
+	rec.Reset()
+	if rec.TruncatedFields() != 0 {
+		t.Errorf("TruncatedFields after Reset = %d, want 0", rec.TruncatedFields())
+	}
+}
+
+func TestTruncatedFields_Encoders(t *testing.T) {
+	text := NewTextEncoder()
+	text.MarkTruncated = true
+	console := NewConsoleEncoder()
+	console.MarkTruncated = true
+	binary := NewBinaryEncoder()
+	binary.MarkTruncated = true
+
+	encoders := []struct {
+		name string
+		enc  Encoder
+		want string
+	}{
+		{"Text", text, " _truncated_fields=3"},
+		{"Console", console, " _truncated_fields=3"},
+		{"Binary", binary, TruncatedFieldsKey},
+	}
+
+	for _, tt := range encoders {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecord(Info, "wide")
+			for _, f := range manyFields(35) {
+				rec.AddField(f)
+			}
+			var buf bytes.Buffer
+			tt.enc.Encode(rec, time.Unix(0, 0), &buf)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output %q does not contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("BinaryDecodes", func(t *testing.T) {
+		rec := NewRecord(Info, "wide")
+		for _, f := range manyFields(33) {
+			rec.AddField(f)
+		}
+		var buf bytes.Buffer
+		binary.Encode(rec, time.Unix(0, 0), &buf)
+		dec, _, err := (&BinaryDecoder{}).Decode(buf.Bytes())
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		last := dec.Fields[len(dec.Fields)-1]
+		if len(dec.Fields) != 33 || last.Key != TruncatedFieldsKey || last.Value != int64(1) {
+			t.Errorf("decoded %d fields ending with %+v, want 33 ending with the marker", len(dec.Fields), last)
+		}
+	})
+}
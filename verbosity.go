@@ -0,0 +1,293 @@
+// verbosity.go: V-style verbosity levels beneath Debug
+//
+// A single Debug level is too coarse for deeply instrumented code: turning
+// it on for one investigation floods the output with every trace point of
+// the service. Verbosity splits Debug into numbered tiers, as klog and glog
+// do: logger.V(1) for the decisions of a request, V(4) for its loops, V(8)
+// for the bytes on the wire. A tier is written when its number is at most
+// the verbosity of the code logging it: the logger's verbosity, or the one
+// set for its package with SetModuleVerbosity.
+//
+// V records are Debug records carrying their tier in the "v" field. The
+// logger's verbosity applies where Debug is enabled; a module verbosity
+// enables its tiers whatever the minimum level, like a source level of
+// Debug for that package, so one subsystem can be turned up on a service
+// running at Info.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// VerbosityKey is the field holding the tier of a V record.
+const VerbosityKey = "v"
+
+// verbosityRule is the verbosity of packages under prefix.
+type verbosityRule struct {
+	prefix string
+	v      int
+}
+
+// verbosityLevels holds the verbosity shared by a logger and its clones.
+// Rules are copy-on-write and sorted longest prefix first, as source
+// levels are.
+type verbosityLevels struct {
+	v     atomic.Int32
+	mu    sync.Mutex                      // Serializes rule updates
+	rules atomic.Pointer[[]verbosityRule] // nil or non-empty, never modified in place
+}
+
+// forPackage returns the verbosity of the longest rule matching pkg.
+func (vl *verbosityLevels) forPackage(pkg string) (int, bool) {
+	rules := vl.rules.Load()
+	if rules == nil || pkg == "" {
+		return 0, false
+	}
+	for _, rule := range *rules {
+		if matchesPackage(pkg, rule.prefix) {
+			return rule.v, true
+		}
+	}
+	return 0, false
+}
+
+// Verbose logs records of one verbosity tier; it is returned by Logger.V.
+// Its methods do nothing when the tier is disabled, so a disabled V call
+// costs the check only:
+//
+//	if v := logger.V(4); v.Enabled() {
+//	    v.Info("cache state", iris.Object("entries", expensiveDump()))
+//	}
+type Verbose struct {
+	l       *Logger
+	level   int
+	enabled bool
+}
+
+// V returns the verbosity tier level for the calling code: Debug records
+// written only when level is at most the verbosity of the caller's package
+// (SetModuleVerbosity), or else at most the logger's verbosity
+// (SetVerbosity) with Debug enabled for the caller. Negative levels are
+// treated as 0, which is plain Debug.
+//
+// Example:
+//
+//	logger.SetVerbosity(2)
+//	logger.V(1).Info("routing request", iris.Str("route", r.Pattern)) // written at Debug
+//	logger.V(5).Info("header dump")                                    // not written
+func (l *Logger) V(level int) Verbose {
+	if level < 0 {
+		level = 0
+	}
+	vl := l.verbosity
+	var pkg string
+	if vl.rules.Load() != nil || l.sources.active() {
+		pkg = callerPackage(2 + l.opts.callerSkip)
+	}
+	if v, ok := vl.forPackage(pkg); ok {
+		return Verbose{l: l, level: level, enabled: level <= v}
+	}
+	if level > int(vl.v.Load()) {
+		return Verbose{}
+	}
+	min := l.level.Level()
+	if pkg != "" {
+		min = l.sources.levelFor(pkg, min)
+	}
+	return Verbose{l: l, level: level, enabled: Debug.Enabled(min)}
+}
+
+// Enabled reports whether the records of this tier are written.
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+// Info logs msg at Debug level with the tier in VerbosityKey, if the tier
+// is enabled. The sampler applies as for any record.
+//
+// Returns:
+//   - bool: true if logged or disabled, false if dropped
+func (v Verbose) Info(msg string, fields ...Field) bool {
+	if !v.enabled || !v.l.allowSampled(Debug, msg, fields) {
+		return true
+	}
+	all := make([]Field, 0, len(fields)+1)
+	all = append(append(all, fields...), Int(VerbosityKey, v.level))
+	return v.l.emit(context.Background(), 0, Debug, msg, all...)
+}
+
+// Infof logs a formatted message at Debug level with the tier in
+// VerbosityKey, if the tier is enabled.
+func (v Verbose) Infof(format string, args ...any) bool {
+	if !v.enabled || !v.l.allowSampled(Debug, format, nil) {
+		return true
+	}
+	return v.l.emit(context.Background(), 0, Debug, fmt.Sprintf(format, args...), Int(VerbosityKey, v.level))
+}
+
+// SetVerbosity sets the verbosity of the logger and every logger derived
+// from it: the V tiers up to v are written where Debug is enabled.
+// Negative values are treated as 0.
+func (l *Logger) SetVerbosity(v int) {
+	if v < 0 {
+		v = 0
+	}
+	l.verbosity.v.Store(int32(min(v, 1<<30))) // #nosec G115 -- bounded above
+}
+
+// Verbosity returns the verbosity set with SetVerbosity.
+func (l *Logger) Verbosity() int {
+	return int(l.verbosity.v.Load())
+}
+
+// SetModuleVerbosity sets the verbosity of code in the package with import
+// path prefix or any of its subpackages. The V tiers up to v logged from
+// there are written whatever the minimum level of the logger; the longest
+// matching prefix wins. Rules are shared by the logger and every logger
+// derived from it, and take effect immediately. While any rule is set,
+// every V call resolves its caller (cached per call site).
+//
+// Parameters:
+//   - prefix: Import path; a trailing "/*" or "/..." is accepted and ignored
+//   - v: Verbosity of the matching code (0 writes V(0) only)
+//
+// Returns:
+//   - error: ErrCodeInvalidConfig for an empty prefix
+//
+// Example:
+//
+//	// V(3) output from the scheduler while the service logs at Info
+//	err := logger.SetModuleVerbosity("github.com/acme/scheduler/*", 3)
+func (l *Logger) SetModuleVerbosity(prefix string, v int) error {
+	normalized := normalizeSourcePrefix(prefix)
+	if normalized == "" {
+		return NewLoggerErrorWithField(ErrCodeInvalidConfig,
+			"module verbosity needs a package path prefix", "prefix", prefix)
+	}
+	vl := l.verbosity
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+
+	var rules []verbosityRule
+	if cur := vl.rules.Load(); cur != nil {
+		rules = make([]verbosityRule, 0, len(*cur)+1)
+		for _, rule := range *cur {
+			if rule.prefix != normalized {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	rules = append(rules, verbosityRule{prefix: normalized, v: max(v, 0)})
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+	vl.rules.Store(&rules)
+	return nil
+}
+
+// RemoveModuleVerbosity removes the rule for prefix (normalized as in
+// SetModuleVerbosity).
+//
+// Returns:
+//   - bool: true if a rule was removed
+func (l *Logger) RemoveModuleVerbosity(prefix string) bool {
+	normalized := normalizeSourcePrefix(prefix)
+	vl := l.verbosity
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+
+	cur := vl.rules.Load()
+	if cur == nil {
+		return false
+	}
+	rules := make([]verbosityRule, 0, len(*cur))
+	for _, rule := range *cur {
+		if rule.prefix != normalized {
+			rules = append(rules, rule)
+		}
+	}
+	switch {
+	case len(rules) == len(*cur):
+		return false
+	case len(rules) == 0:
+		vl.rules.Store(nil)
+	default:
+		vl.rules.Store(&rules)
+	}
+	return true
+}
+
+// ModuleVerbosity returns the current module verbosity rules by package
+// prefix.
+func (l *Logger) ModuleVerbosity() map[string]int {
+	rules := l.verbosity.rules.Load()
+	if rules == nil {
+		return nil
+	}
+	levels := make(map[string]int, len(*rules))
+	for _, rule := range *rules {
+		levels[rule.prefix] = rule.v
+	}
+	return levels
+}
+
+// SetVModule replaces every module verbosity rule with the ones of spec, a
+// comma-separated list of prefix=verbosity pairs in the style of klog's
+// -vmodule flag. An empty spec removes every rule.
+//
+// Parameters:
+//   - spec: Rules such as "github.com/acme/scheduler=3,github.com/acme/db/...=5"
+//
+// Returns:
+//   - error: ErrCodeInvalidConfig for a malformed pair; the rules are then
+//     left unchanged
+//
+// Example:
+//
+//	vmodule := flag.String("vmodule", "", "per-package verbosity, pkg=N,...")
+//	flag.Parse()
+//	if err := logger.SetVModule(*vmodule); err != nil {
+//	    log.Fatal(err)
+//	}
+func (l *Logger) SetVModule(spec string) error {
+	var rules []verbosityRule
+	seen := make(map[string]int)
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		prefix, value, ok := strings.Cut(pair, "=")
+		normalized := normalizeSourcePrefix(prefix)
+		v, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || normalized == "" || err != nil || v < 0 {
+			return NewLoggerErrorWithField(ErrCodeInvalidConfig,
+				"vmodule entries are package=verbosity with a non-negative verbosity", "vmodule", pair)
+		}
+		if i, dup := seen[normalized]; dup {
+			rules[i].v = v
+			continue
+		}
+		seen[normalized] = len(rules)
+		rules = append(rules, verbosityRule{prefix: normalized, v: v})
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+
+	vl := l.verbosity
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+	if len(rules) == 0 {
+		vl.rules.Store(nil)
+	} else {
+		vl.rules.Store(&rules)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// verbosity_test.go: Tests for V-style verbosity levels
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"strings"
+	"testing"
+)
+
+func newVerbosityLogger(t *testing.T, level Level, opts ...Option) (*Logger, *testSyncer) {
+	t.Helper()
+	out := &testSyncer{}
+	logger, err := New(Config{Level: level, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Inline: true}, opts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return logger, out
+}
+
+func TestVerbosity_Logger(t *testing.T) {
+	logger, out := newVerbosityLogger(t, Debug)
+	logger.SetVerbosity(2)
+	if got := logger.Named("child").Verbosity(); got != 2 {
+		t.Errorf("clone Verbosity() = %d, want 2", got)
+	}
+
+	logger.V(0).Info("tier zero")
+	logger.V(2).Infof("tier %d", 2)
+	logger.V(3).Info("tier three")
+	if logger.V(3).Enabled() || !logger.V(-1).Enabled() {
+		t.Error("Enabled should follow the logger verbosity")
+	}
+	_ = logger.Close()
+
+	got := out.String()
+	for _, want := range []string{`"msg":"tier zero"`, `"v":0`, `"msg":"tier 2"`, `"v":2`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %s: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "tier three") {
+		t.Errorf("V(3) written above verbosity 2: %s", got)
+	}
+	if !strings.Contains(got, `"level":"debug"`) {
+		t.Errorf("V records should be written at debug: %s", got)
+	}
+}
+
+func TestVerbosity_NeedsDebug(t *testing.T) {
+	logger, out := newVerbosityLogger(t, Info)
+	logger.SetVerbosity(5)
+	if logger.V(1).Enabled() {
+		t.Error("V(1) enabled with Debug disabled")
+	}
+	logger.V(1).Info("hidden")
+	_ = logger.Close()
+	if out.String() != "" {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+// TestVerbosity_Module uses WithCallerSkip(1) so that V resolves the
+// testing package, which no other code in the test binary logs from.
+func TestVerbosity_Module(t *testing.T) {
+	logger, out := newVerbosityLogger(t, Info, WithCallerSkip(1))
+	if err := logger.SetModuleVerbosity("testing", 3); err != nil {
+		t.Fatalf("SetModuleVerbosity failed: %v", err)
+	}
+	if err := logger.SetModuleVerbosity("/...", 3); !IsLoggerError(err, ErrCodeInvalidConfig) {
+		t.Errorf("SetModuleVerbosity with empty prefix = %v, want %s", err, ErrCodeInvalidConfig)
+	}
+
+	logger.V(3).Info("module tier")
+	logger.V(4).Info("too verbose")
+	if got := logger.ModuleVerbosity(); len(got) != 1 || got["testing"] != 3 {
+		t.Errorf("ModuleVerbosity() = %v, want testing=3", got)
+	}
+	if !logger.RemoveModuleVerbosity("testing/*") || logger.RemoveModuleVerbosity("testing") {
+		t.Error("RemoveModuleVerbosity should report true once")
+	}
+	logger.V(0).Info("after removal")
+	_ = logger.Close()
+
+	got := out.String()
+	if !strings.Contains(got, "module tier") {
+		t.Errorf("module verbosity should lift V(3) above Info: %s", got)
+	}
+	if strings.Contains(got, "too verbose") || strings.Contains(got, "after removal") {
+		t.Errorf("unexpected records: %s", got)
+	}
+}
+
+func TestVerbosity_SetVModule(t *testing.T) {
+	logger, _ := newVerbosityLogger(t, Info)
+	defer func() { _ = logger.Close() }()
+
+	if err := logger.SetVModule("github.com/acme/db/...=5, github.com/acme=2,github.com/acme=1"); err != nil {
+		t.Fatalf("SetVModule failed: %v", err)
+	}
+	tests := []struct {
+		pkg  string
+		want int
+		ok   bool
+	}{
+		{"github.com/acme/db/pool", 5, true},
+		{"github.com/acme/api", 1, true}, // The last duplicate wins
+		{"github.com/other", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := logger.verbosity.forPackage(tt.pkg); got != tt.want || ok != tt.ok {
+			t.Errorf("forPackage(%q) = %d, %v, want %d, %v", tt.pkg, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	for _, spec := range []string{"github.com/acme", "github.com/acme=x", "=3", "github.com/acme=-1"} {
+		if err := logger.SetVModule(spec); !IsLoggerError(err, ErrCodeInvalidConfig) {
+			t.Errorf("SetVModule(%q) = %v, want %s", spec, err, ErrCodeInvalidConfig)
+		}
+	}
+	if len(logger.ModuleVerbosity()) != 2 {
+		t.Errorf("rules changed by a failed SetVModule: %v", logger.ModuleVerbosity())
+	}
+	if err := logger.SetVModule(""); err != nil || logger.ModuleVerbosity() != nil {
+		t.Errorf("SetVModule(\"\") = %v, rules %v", err, logger.ModuleVerbosity())
+	}
+}
@@ -0,0 +1,186 @@
+// watchdog.go: Detection of a consumer that stopped making progress
+//
+// A consumer blocked in a write to a stuck sink (a full pipe, an NFS mount
+// that went away, a collector that stopped reading) takes the logs down
+// silently: producers keep logging into a ring that never drains, and the
+// first visible symptom is the ring-full drop rate, if anyone watches it.
+// With WithWatchdog a goroutine samples the ring and reports a stall as
+// soon as records are pending or dropped while none has been processed for
+// the configured interval.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultWatchdogInterval is the stall interval of WithWatchdog when
+// WatchdogConfig.Interval is zero.
+const DefaultWatchdogInterval = 5 * time.Second
+
+// WatchdogConfig configures WithWatchdog.
+type WatchdogConfig struct {
+	// Interval is how long the consumer may go without processing a
+	// record, while records are pending or being dropped, before it is
+	// reported stalled (default DefaultWatchdogInterval)
+	Interval time.Duration
+
+	// OnStall, if set, is called with each stall instead of reporting it
+	// to the error handler. It runs on the watchdog goroutine.
+	OnStall func(Stall)
+}
+
+// Stall describes a consumer that stopped making progress.
+type Stall struct {
+	Logger    string        // Name of the logger the watchdog belongs to
+	Since     time.Time     // When the consumer last processed a record
+	Duration  time.Duration // Time without progress when reported
+	Pending   int64         // Records waiting in the ring
+	Dropped   int64         // Records dropped by the ring since Since
+	Processed int64         // Records processed in total
+}
+
+// watchdogState is the watchdog of a logger, shared with its clones.
+type watchdogState struct {
+	cfg WatchdogConfig
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// WithWatchdog reports the consumer as stalled when no record has been
+// processed for cfg.Interval while producers are active: records are
+// waiting in the ring, or writes are being dropped because it is full.
+//
+// A stall is reported once, with ErrCodeConsumerStalled to the error handler
+// (which does not go through the stalled logger) or to cfg.OnStall; it is
+// reported again only after the consumer made progress in between. An idle
+// logger is never reported. The watchdog samples the ring four times per
+// interval from its own goroutine, started by Start and stopped by Close.
+// Inline loggers (Config.Inline) have no consumer and ignore it.
+//
+// Parameters:
+//   - cfg: Stall interval and optional callback
+//
+// Returns:
+//   - Option: Configuration function to enable the watchdog
+//
+// Example:
+//
+//	logger, err := iris.New(cfg, iris.WithWatchdog(iris.WatchdogConfig{
+//		Interval: 10 * time.Second,
+//		OnStall: func(s iris.Stall) {
+//			metrics.LogConsumerStalls.Inc()
+//		},
+//	}))
+func WithWatchdog(cfg WatchdogConfig) Option {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultWatchdogInterval
+	}
+	return func(o *loggerOptions) {
+		o.watchdog = &cfg
+	}
+}
+
+// newWatchdogState creates the watchdog for cfg (nil without WithWatchdog).
+func newWatchdogState(cfg *WatchdogConfig) *watchdogState {
+	if cfg == nil {
+		return nil
+	}
+	return &watchdogState{
+		cfg:  *cfg,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// startWatchdog launches the watchdog goroutine once.
+func (l *Logger) startWatchdog() {
+	w := l.watchdog
+	if w == nil || l.r.inline != nil {
+		return
+	}
+	w.startOnce.Do(func() {
+		go func() {
+			defer close(w.done)
+			ticker := time.NewTicker(w.cfg.Interval / 4)
+			defer ticker.Stop()
+			last := l.watchdogSample()
+			reported := false
+			for {
+				select {
+				case <-ticker.C:
+				case <-w.quit:
+					return
+				}
+				cur := l.watchdogSample()
+				switch {
+				case cur.processed != last.processed || (cur.pending == 0 && cur.dropped == last.dropped):
+					// Progress, or nothing to do: restart the interval
+					last, reported = cur, false
+				case !reported && cur.at.Sub(last.at) >= w.cfg.Interval:
+					l.reportStall(Stall{
+						Logger:    l.name,
+						Since:     last.at,
+						Duration:  cur.at.Sub(last.at),
+						Pending:   cur.pending,
+						Dropped:   cur.dropped - last.dropped,
+						Processed: cur.processed,
+					})
+					reported = true
+				}
+			}
+		}()
+	})
+}
+
+// stopWatchdog stops the watchdog goroutine.
+func (l *Logger) stopWatchdog() {
+	w := l.watchdog
+	if w == nil {
+		return
+	}
+	w.startOnce.Do(func() { close(w.done) }) // Never started
+	w.stopOnce.Do(func() { close(w.quit) })
+	<-w.done
+}
+
+// watchdogSample is the ring progress at one point in time.
+type watchdogSample struct {
+	at        time.Time
+	processed int64
+	pending   int64
+	dropped   int64
+}
+
+// watchdogSample reads the ring progress.
+func (l *Logger) watchdogSample() watchdogSample {
+	stats := l.r.Stats()
+	return watchdogSample{
+		at:        time.Now(),
+		processed: stats["items_processed"],
+		pending:   stats["items_buffered"],
+		dropped:   stats["items_dropped"],
+	}
+}
+
+// reportStall hands s to the OnStall callback or the error handler.
+func (l *Logger) reportStall(s Stall) {
+	if l.watchdog.cfg.OnStall != nil {
+		l.watchdog.cfg.OnStall(s)
+		return
+	}
+	l.reportError(NewLoggerError(ErrCodeConsumerStalled, "log consumer stalled: records pending but none processed").
+		WithSeverity("warning").
+		WithContext("logger", s.Logger).
+		WithContext("stalled_for", s.Duration.String()).
+		WithContext("pending", s.Pending).
+		WithContext("dropped", s.Dropped))
+}
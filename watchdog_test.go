@@ -0,0 +1,111 @@
+// watchdog_test.go: Tests for stalled consumer detection
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"testing"
+	"time"
+)
+
+// stuckSyncer blocks every write until release is closed.
+type stuckSyncer struct {
+	release chan struct{}
+}
+
+func (s *stuckSyncer) Write(p []byte) (int, error) {
+	<-s.release
+	return len(p), nil
+}
+
+func (s *stuckSyncer) Sync() error { return nil }
+
+func TestWatchdog_ReportsStuckSink(t *testing.T) {
+	out := &stuckSyncer{release: make(chan struct{})}
+	stalls := make(chan Stall, 4)
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64, Name: "api"},
+		WithWatchdog(WatchdogConfig{Interval: 100 * time.Millisecond, OnStall: func(s Stall) { stalls <- s }}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		logger.Info("stuck")
+	}
+	var s Stall
+	select {
+	case s = <-stalls:
+	case <-time.After(5 * time.Second):
+		close(out.release)
+		t.Fatal("stall not reported")
+	}
+	if s.Logger != "api" || s.Pending == 0 || s.Duration < 100*time.Millisecond {
+		t.Errorf("unexpected stall report: %+v", s)
+	}
+
+	// Reported once per stall
+	time.Sleep(300 * time.Millisecond)
+	close(out.release)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := len(stalls); n != 0 {
+		t.Errorf("stall reported %d more times", n)
+	}
+}
+
+func TestWatchdog_IdleAndHealthy(t *testing.T) {
+	tests := []struct {
+		name string
+		log  bool
+	}{
+		{"Idle", false},
+		{"Healthy", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stalls := make(chan Stall, 4)
+			logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64},
+				WithWatchdog(WatchdogConfig{Interval: 40 * time.Millisecond, OnStall: func(s Stall) { stalls <- s }}))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			deadline := time.Now().Add(250 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				if tt.log {
+					logger.Info("healthy")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			if n := len(stalls); n != 0 {
+				t.Errorf("%d stalls reported, want none", n)
+			}
+		})
+	}
+}
+
+func TestWatchdog_ErrorHandler(t *testing.T) {
+	reported := captureErrors(t)
+
+	out := &stuckSyncer{release: make(chan struct{})}
+	logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64},
+		WithWatchdog(WatchdogConfig{Interval: 60 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	logger.Info("stuck")
+	time.Sleep(400 * time.Millisecond)
+	close(out.release)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := reported(); len(got) != 1 || got[0].Code != ErrCodeConsumerStalled {
+		t.Fatalf("reported %v, want one ErrCodeConsumerStalled", got)
+	}
+}
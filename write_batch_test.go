@@ -0,0 +1,121 @@
+// write_batch_test.go: Tests for Logger.WriteBatch
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// batchFills returns n fill functions logging "record <i>" with
+// consecutive historical timestamps.
+func batchFills(n int) []func(*Record) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fills := make([]func(*Record), n)
+	for i := range fills {
+		fills[i] = func(r *Record) {
+			r.Level = Info
+			r.Msg = fmt.Sprintf("record %d", i)
+			r.SetTime(base.Add(time.Duration(i) * time.Second))
+		}
+	}
+	return fills
+}
+
+func TestLogger_WriteBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		n      int
+		queued int
+	}{
+		{"ring", Config{Capacity: 64}, 200, 200}, // Larger than the ring: waits for room
+		{"inline", Config{Inline: true}, 10, 10},
+		{"drop on full", Config{Capacity: 64, AutoStart: AutoStartOff, BackpressurePolicy: zephyroslite.DropOnFull}, 100, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureErrors(t) // Silence the not-started diagnostic
+			out := &testSyncer{}
+			cfg := tt.cfg
+			cfg.Level, cfg.Output, cfg.Encoder = Debug, out, NewJSONEncoder()
+			if cfg.AutoStart != AutoStartOff {
+				cfg.BackpressurePolicy = zephyroslite.BlockOnFull
+			}
+			logger, err := New(cfg, WithIngestionTime("ingested_at"))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			if got := logger.WriteBatch(batchFills(tt.n)); got != tt.queued {
+				t.Errorf("WriteBatch queued %d records, want %d", got, tt.queued)
+			}
+			logger.Start()
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != tt.queued {
+				t.Fatalf("wrote %d records, want %d", len(lines), tt.queued)
+			}
+			for i, line := range lines {
+				ts := time.Date(2020, 1, 1, 0, 0, i, 0, time.UTC).Format(time.RFC3339Nano)
+				if !strings.Contains(line, fmt.Sprintf(`"msg":"record %d"`, i)) || !strings.Contains(line, `"ts":"`+ts+`"`) {
+					t.Fatalf("record %d out of order or without its timestamp: %s", i, line)
+				}
+				if !strings.Contains(line, `"ingested_at":`) {
+					t.Fatalf("record %d lacks the ingestion field: %s", i, line)
+				}
+			}
+			if got := logger.WriteBatch(batchFills(3)); got != 0 {
+				t.Errorf("WriteBatch after Close queued %d records", got)
+			}
+		})
+	}
+}
+
+func TestLogger_WriteBatchDropStats(t *testing.T) {
+	captureErrors(t) // Silence the not-started diagnostic
+	logger, err := New(Config{
+		Capacity:           8,
+		BatchSize:          8,
+		AutoStart:          AutoStartOff,
+		BackpressurePolicy: zephyroslite.DropOnFull,
+		Output:             &testSyncer{},
+		Encoder:            NewJSONEncoder(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if got := logger.WriteBatch(batchFills(20)); got != 8 {
+		t.Fatalf("WriteBatch queued %d records, want 8", got)
+	}
+	if stats := logger.Stats(); stats["dropped"] != 12 {
+		t.Errorf("dropped = %d, want 12", stats["dropped"])
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 12 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 12", got)
+	}
+
+	logger.Start()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	logger.WriteBatch(batchFills(3))
+	if got := logger.DroppedBy(DropClosed); got != 3 {
+		t.Errorf("DroppedBy(DropClosed) = %d, want 3", got)
+	}
+	if stats := logger.Stats(); stats["dropped"] != 15 {
+		t.Errorf("dropped = %d, want 15", stats["dropped"])
+	}
+}
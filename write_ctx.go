@@ -0,0 +1,129 @@
+// write_ctx.go: Context-aware logging for the BlockOnFull policy
+//
+// With BlockOnFull a logging call waits until the ring has a free slot, so a
+// stalled output can block request goroutines forever. The *Ctx variants
+// take the caller's context and stop waiting once it is done: the record is
+// dropped, counted as DropCanceled, and the call returns false (or
+// ErrWriteCanceled for WriteCtx). With DropOnFull they behave exactly like
+// the plain methods, since those never wait.
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+
+	"github.com/agilira/go-errors"
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+var (
+	// ErrRingFull is returned by WriteCtx when the ring has no free slot
+	// under the DropOnFull policy
+	ErrRingFull = errors.New(ErrCodeRingFull, "ring buffer is full")
+
+	// ErrWriteCanceled is returned by WriteCtx when the context was done
+	// before a free slot became available. The returned error wraps
+	// ctx.Err(), so errors.Is also matches context.Canceled or
+	// context.DeadlineExceeded.
+	ErrWriteCanceled = errors.New(ErrCodeWriteCanceled, "write canceled while waiting for ring space")
+)
+
+// WriteCtx is Write bounded by ctx: under BlockOnFull it stops waiting for
+// a free slot once ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding the wait
+//   - fill: Function that populates the record slot
+//
+// Returns:
+//   - error: nil if the record was accepted; ErrWriteCanceled (wrapping
+//     ctx.Err()), ErrRingFull or ErrLoggerClosed if it was dropped. Drops
+//     are counted (DropCanceled, DropRingFull or DropClosed, at Info level)
+//     in Stats and DroppedBy.
+//
+// Example:
+//
+//	err := logger.WriteCtx(r.Context(), func(rec *iris.Record) {
+//	    rec.Level = iris.Info
+//	    rec.Msg = "request served"
+//	})
+//	if errors.Is(err, iris.ErrWriteCanceled) {
+//	    // Client went away while the logger was saturated
+//	}
+func (l *Logger) WriteCtx(ctx context.Context, fill func(*Record)) error {
+	if l.r.state.Load() == int32(StateNew) {
+		l.reportNotStarted()
+	}
+	err := l.r.WriteContext(ctx, func(slot *Record) { l.fillSlot(slot, fill) })
+	switch {
+	case err == nil:
+		return nil
+	case err == ctx.Err():
+		// fill never ran, so the level is unknown: counted at Info like
+		// the drops of Write and WriteBatch
+		l.countDropped()
+		l.recordDrop(DropCanceled, Info)
+		return errors.Wrap(err, ErrCodeWriteCanceled, ErrWriteCanceled.Message)
+	case err == zephyroslite.ErrRingFull:
+		l.countDropped()
+		l.recordDrop(DropRingFull, Info)
+		return ErrRingFull
+	default:
+		l.recordRingDrop(Info) // Closed: counted as DropClosed
+		return ErrLoggerClosed
+	}
+}
+
+// TraceCtx is Trace bounded by ctx under BlockOnFull (see InfoCtx).
+func (l *Logger) TraceCtx(ctx context.Context, msg string, fields ...Field) bool {
+	return l.logCtx(ctx, Trace, msg, fields...)
+}
+
+// DebugCtx is Debug bounded by ctx under BlockOnFull (see InfoCtx).
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...Field) bool {
+	return l.logCtx(ctx, Debug, msg, fields...)
+}
+
+// InfoCtx logs at Info level like Info, but under BlockOnFull it stops
+// waiting for a free ring slot once ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding the wait (its values are logged only through
+//     WithContextFields; see also WithContext)
+//   - msg: Primary log message
+//   - fields: Structured key-value pairs
+//
+// Returns:
+//   - bool: true if logged or filtered, false if dropped (a canceled wait
+//     is counted as DropCanceled)
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    logger.InfoCtx(r.Context(), "request served", iris.Str("path", r.URL.Path))
+//	}
+func (l *Logger) InfoCtx(ctx context.Context, msg string, fields ...Field) bool {
+	return l.logCtx(ctx, Info, msg, fields...)
+}
+
+// WarnCtx is Warn bounded by ctx under BlockOnFull (see InfoCtx).
+func (l *Logger) WarnCtx(ctx context.Context, msg string, fields ...Field) bool {
+	return l.logCtx(ctx, Warn, msg, fields...)
+}
+
+// ErrorCtx is Error bounded by ctx under BlockOnFull (see InfoCtx).
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields ...Field) bool {
+	return l.logCtx(ctx, Error, msg, fields...)
+}
+
+// logCtx is log with a context bounding the ring write.
+func (l *Logger) logCtx(ctx context.Context, level Level, msg string, fields ...Field) bool {
+	if !l.shouldLog(1, level, msg, fields) {
+		return true
+	}
+	return l.emit(ctx, 1, level, msg, l.withContextFields(ctx, fields)...)
+}
@@ -0,0 +1,178 @@
+// write_ctx_test.go: Tests for context-aware logging
+//
+// Copyright (c) 2025 AGILira
+// Series: an AGILira fragment
+// SPDX-License-Identifier: MPL-2.0
+
+package iris
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agilira/iris/internal/zephyroslite"
+)
+
+// newFullCtxLogger returns an unstarted logger whose ring has no free slot.
+func newFullCtxLogger(t *testing.T, policy zephyroslite.BackpressurePolicy) *Logger {
+	t.Helper()
+	captureErrors(t) // Silence the not-started diagnostic
+	logger, err := New(Config{
+		Level:              Info,
+		Output:             &testSyncer{},
+		Encoder:            NewJSONEncoder(),
+		Capacity:           16,
+		BatchSize:          8,
+		BackpressurePolicy: policy,
+		AutoStart:          AutoStartOff,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+
+	for i := 0; ; i++ {
+		if i > 1024 {
+			t.Fatal("ring never filled up")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		ok := logger.InfoCtx(ctx, "fill")
+		cancel()
+		if !ok {
+			break
+		}
+	}
+	return logger
+}
+
+func TestInfoCtx_CanceledWhileBlocked(t *testing.T) {
+	logger := newFullCtxLogger(t, zephyroslite.BlockOnFull)
+	before := logger.DroppedBy(DropCanceled)
+	if before != 1 {
+		t.Fatalf("DroppedBy(DropCanceled) after fill = %d, want 1", before)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if logger.WarnCtx(ctx, "canceled") {
+		t.Error("WarnCtx with a canceled context reported success")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WarnCtx blocked for %v", elapsed)
+	}
+	if got := logger.DroppedBy(DropCanceled); got != before+1 {
+		t.Errorf("DroppedBy(DropCanceled) = %d, want %d", got, before+1)
+	}
+	if got := logger.DroppedBy(DropRingFull); got != 0 {
+		t.Errorf("DroppedBy(DropRingFull) = %d, want 0", got)
+	}
+}
+
+func TestInfoCtx_OnDropReason(t *testing.T) {
+	var got []DropReason
+	captureErrors(t)
+	logger, err := New(Config{
+		Level:              Info,
+		Output:             &testSyncer{},
+		Encoder:            NewJSONEncoder(),
+		Capacity:           16,
+		BatchSize:          8,
+		BackpressurePolicy: zephyroslite.BlockOnFull,
+		AutoStart:          AutoStartOff,
+	}, WithOnDrop(func(reason DropReason, _ Level) {
+		got = append(got, reason)
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	for i := 0; i < 1024; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		ok := logger.ErrorCtx(ctx, "fill")
+		cancel()
+		if !ok {
+			break
+		}
+	}
+	if len(got) != 1 || got[0] != DropCanceled {
+		t.Errorf("OnDrop reasons = %v, want [canceled]", got)
+	}
+}
+
+func TestWriteCtx_Errors(t *testing.T) {
+	fill := func(rec *Record) {
+		rec.Level = Info
+		rec.Msg = "write"
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		logger := newFullCtxLogger(t, zephyroslite.BlockOnFull)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		err := logger.WriteCtx(ctx, fill)
+		if !errors.Is(err, ErrWriteCanceled) {
+			t.Errorf("WriteCtx error = %v, want ErrWriteCanceled", err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("WriteCtx error = %v, want it to wrap context.DeadlineExceeded", err)
+		}
+		if got := logger.DroppedBy(DropCanceled); got != 2 {
+			t.Errorf("DroppedBy(DropCanceled) = %d, want 2", got)
+		}
+	})
+
+	t.Run("ring full", func(t *testing.T) {
+		logger := newFullCtxLogger(t, zephyroslite.DropOnFull)
+		if err := logger.WriteCtx(context.Background(), fill); err != ErrRingFull {
+			t.Errorf("WriteCtx error = %v, want ErrRingFull", err)
+		}
+		if got := logger.DroppedBy(DropCanceled); got != 0 {
+			t.Errorf("DroppedBy(DropCanceled) = %d, want 0 under DropOnFull", got)
+		}
+		// The fill loop's last InfoCtx and the WriteCtx
+		if stats := logger.Stats(); stats["dropped_ring_full"] != 2 || stats["dropped"] != 2 {
+			t.Errorf("unexpected drop stats: %v", stats)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		logger, err := New(Config{Level: Info, Output: &testSyncer{}, Encoder: NewJSONEncoder(), Capacity: 64})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		_ = logger.Close()
+		if err := logger.WriteCtx(context.Background(), fill); err != ErrLoggerClosed {
+			t.Errorf("WriteCtx error = %v, want ErrLoggerClosed", err)
+		}
+		if got := logger.DroppedBy(DropClosed); got != 1 {
+			t.Errorf("DroppedBy(DropClosed) = %d, want 1", got)
+		}
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		out := &testSyncer{}
+		logger, err := New(Config{Level: Info, Output: out, Encoder: NewJSONEncoder(), Capacity: 64,
+			BackpressurePolicy: zephyroslite.BlockOnFull})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := logger.WriteCtx(ctx, fill); err != nil {
+			t.Errorf("WriteCtx error = %v, want nil", err)
+		}
+		if !logger.InfoCtx(ctx, "info") {
+			t.Error("InfoCtx on a started logger reported a drop")
+		}
+		_ = logger.Close()
+		got := out.String()
+		if !strings.Contains(got, `"msg":"write"`) || !strings.Contains(got, `"msg":"info"`) {
+			t.Errorf("output = %q, want both records", got)
+		}
+	})
+}